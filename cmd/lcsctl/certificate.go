@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"life-certificates/internal/signing"
+)
+
+func runCertificateRecompute(ctx context.Context, env *environment, args []string) error {
+	fs := newFlagSet("certificate recompute", "")
+	participantID := fs.String("participant", "", "recompute this participant only; every participant by default")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	participants, _, err := env.participantService(ctx)
+	if err != nil {
+		return err
+	}
+	built, err := participants.RecomputeVerificationStates(ctx, *participantID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("recomputed the verification status of %d participants\n", built)
+	return nil
+}
+
+// runCertificateVerify checks a certificate without the server or its
+// configuration: a signed JSON certificate on its own, or a PDF with the
+// detached signature from ?format=signature.
+func runCertificateVerify(_ context.Context, _ *environment, args []string) error {
+	fs := newFlagSet("certificate verify", "<certificate.pdf|certificate.json>")
+	keysFile := fs.String("keys", "", "JWK set saved from /.well-known/jwks.json (required)")
+	signatureFile := fs.String("signature", "", "detached signature of a PDF")
+	rootsFile := fs.String("roots", "", "PEM roots the key's x5c chain must lead to; the chain is not checked without it")
+	operands, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if *keysFile == "" {
+		fs.Usage()
+		return errUsage
+	}
+
+	raw, err := os.ReadFile(*keysFile)
+	if err != nil {
+		return err
+	}
+	var keys signing.JWKSet
+	if err := json.Unmarshal(raw, &keys); err != nil {
+		return fmt.Errorf("parse %s: %w", *keysFile, err)
+	}
+	content, err := os.ReadFile(operands[0])
+	if err != nil {
+		return err
+	}
+
+	var key *signing.JWK
+	var payload []byte
+	if *signatureFile != "" {
+		signature, err := os.ReadFile(*signatureFile)
+		if err != nil {
+			return err
+		}
+		if key, err = signing.VerifyDetached(keys, string(signature), content); err != nil {
+			return err
+		}
+	} else {
+		var jws signing.JWS
+		if err := json.Unmarshal(content, &jws); err != nil || jws.Signature == "" {
+			return errors.New("not a signed JSON certificate; pass -signature for a PDF")
+		}
+		if payload, key, err = signing.VerifyJSON(keys, jws); err != nil {
+			return err
+		}
+	}
+
+	if *rootsFile != "" {
+		if err := verifyKeyChain(key, *rootsFile); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("signature valid, signed with %s key %s\n", key.Algorithm, key.KeyID)
+	if payload != nil {
+		fmt.Println(string(payload))
+	}
+	return nil
+}
+
+// verifyKeyChain checks that the key's x5c chain leads to one of the roots.
+func verifyKeyChain(key *signing.JWK, rootsFile string) error {
+	chain, err := key.Certificates()
+	if err != nil {
+		return err
+	}
+	if len(chain) == 0 {
+		return errors.New("the signing key has no certificate chain")
+	}
+	pemRoots, err := os.ReadFile(rootsFile)
+	if err != nil {
+		return err
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(pemRoots) {
+		return fmt.Errorf("%s holds no certificates", rootsFile)
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	if _, err := chain[0].Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}}); err != nil {
+		return fmt.Errorf("signing certificate: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"life-certificates/internal/cache"
+	"life-certificates/internal/config"
+	"life-certificates/internal/database"
+	"life-certificates/internal/domain"
+	"life-certificates/internal/frcore"
+	"life-certificates/internal/imaging"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/service"
+	"life-certificates/internal/storage"
+)
+
+// environment loads the configuration and opens connections the first time
+// a command asks for them.
+type environment struct {
+	configFile string
+	// tenantID is the tenant the commands act for; empty is the default tenant.
+	tenantID string
+	cfg      *config.Config
+	db       *gorm.DB
+	closers  []io.Closer
+}
+
+// repositories are the stores the commands work on, wrapped as in the server
+// so verification states and cached lookups stay in step.
+type repositories struct {
+	participants         repository.ParticipantRepository
+	members              repository.MemberRepository
+	certificates         repository.LifeCertificateRepository
+	states               repository.VerificationStateRepository
+	locks                repository.VerificationLockRepository
+	conflicts            repository.FacialConflictRepository
+	verificationDevices  repository.VerificationDeviceRepository
+	homeVisits           repository.HomeVisitRepository
+	holidays             repository.HolidayRepository
+	frIdentities         repository.FRIdentityRepository
+	certificateDocuments repository.CertificateDocumentRepository
+	documents            repository.DocumentRepository
+	ktpChecks            repository.KTPCheckRepository
+	campaigns            repository.CampaignRepository
+	profiles             repository.VerificationProfileRepository
+	outbox               repository.OutboxRepository
+	devices              repository.DeviceRepository
+	notifications        repository.NotificationRepository
+	consents             repository.ConsentRepository
+	audit                repository.AuditLogRepository
+	accessLogs           repository.AccessLogRepository
+	reconciliations      repository.FRReconciliationRepository
+	jobs                 repository.JobRepository
+	tenants              repository.TenantRepository
+	tenantData           repository.TenantDataRepository
+	tx                   repository.Transactor
+}
+
+func (e *environment) config() (*config.Config, error) {
+	if e.cfg == nil {
+		cfg, err := config.Load(e.configFile)
+		if err != nil {
+			return nil, fmt.Errorf("load config: %w", err)
+		}
+		e.cfg = cfg
+	}
+	return e.cfg, nil
+}
+
+// database connects to the configured database. Unless migrating, the
+// schema must be complete.
+func (e *environment) database(ctx context.Context, migrating bool) (*gorm.DB, error) {
+	if e.db != nil {
+		return e.db, nil
+	}
+	cfg, err := e.config()
+	if err != nil {
+		return nil, err
+	}
+	db, err := database.New(cfg.Database.DSN)
+	if err != nil {
+		return nil, err
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		e.closers = append(e.closers, sqlDB)
+	}
+	if err := database.Ping(ctx, db); err != nil {
+		return nil, fmt.Errorf("ping database: %w", err)
+	}
+	if !migrating {
+		if err := database.CheckSchema(ctx, db); err != nil {
+			return nil, fmt.Errorf("%w; run lcsctl migrate first", err)
+		}
+		if e.tenantID != "" {
+			t, err := repository.NewTenantRepository(db).Get(ctx, e.tenantID)
+			if err != nil {
+				return nil, err
+			}
+			if t == nil {
+				return nil, fmt.Errorf("tenant %s not found", e.tenantID)
+			}
+		}
+	}
+	e.db = db
+	return db, nil
+}
+
+func (e *environment) repositories(ctx context.Context) (*repositories, error) {
+	db, err := e.database(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	cfg := e.cfg
+
+	repos := &repositories{
+		participants:         repository.NewParticipantRepository(db),
+		members:              repository.NewMemberRepository(db),
+		certificates:         repository.NewLifeCertificateRepository(db),
+		states:               repository.NewVerificationStateRepository(db),
+		locks:                repository.NewVerificationLockRepository(db),
+		conflicts:            repository.NewFacialConflictRepository(db),
+		verificationDevices:  repository.NewVerificationDeviceRepository(db),
+		homeVisits:           repository.NewHomeVisitRepository(db),
+		holidays:             repository.NewHolidayRepository(db),
+		frIdentities:         repository.NewFRIdentityRepository(db),
+		certificateDocuments: repository.NewCertificateDocumentRepository(db),
+		documents:            repository.NewDocumentRepository(db),
+		ktpChecks:            repository.NewKTPCheckRepository(db),
+		campaigns:            repository.NewCampaignRepository(db),
+		profiles:             repository.NewVerificationProfileRepository(db),
+		outbox:               repository.NewOutboxRepository(db),
+		devices:              repository.NewDeviceRepository(db),
+		notifications:        repository.NewNotificationRepository(db),
+		consents:             repository.NewConsentRepository(db),
+		audit:                repository.NewAuditLogRepository(db),
+		accessLogs:           repository.NewAccessLogRepository(db),
+		reconciliations:      repository.NewFRReconciliationRepository(db),
+		jobs:                 repository.NewJobRepository(db),
+		tenants:              repository.NewTenantRepository(db),
+		tx:                   repository.NewTransactor(db),
+	}
+	repos.certificates = repository.NewStateTrackingLifeCertificateRepository(repos.certificates, repos.states, repos.tx, cfg.Verification.ValidityMonths)
+	if repos.tenantData, err = repository.NewTenantDataRepository(db, database.Models()...); err != nil {
+		return nil, err
+	}
+
+	// Only a shared Redis cache outlives this process; invalidating it keeps
+	// the servers from answering with what a command just deleted.
+	if cfg.Cache.Backend == "redis" {
+		lookupCache, err := cache.NewRedis(cfg.Cache.RedisURL, cfg.Cache.RedisPrefix, cfg.Cache.TTL)
+		if err != nil {
+			return nil, fmt.Errorf("init cache: %w", err)
+		}
+		e.closers = append(e.closers, lookupCache)
+		repos.participants = repository.NewCachedParticipantRepository(repos.participants, lookupCache)
+		repos.members = repository.NewCachedMemberRepository(repos.members, lookupCache)
+		repos.frIdentities = repository.NewCachedFRIdentityRepository(repos.frIdentities, lookupCache)
+		repos.tenants = repository.NewCachedTenantRepository(repos.tenants, lookupCache)
+	}
+	return repos, nil
+}
+
+// participantService builds the participant service as the server does. Its
+// events are written to the outbox, for the servers to dispatch.
+func (e *environment) participantService(ctx context.Context) (*service.ParticipantService, *repositories, error) {
+	repos, err := e.repositories(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	frClient, err := e.frClient(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg := e.cfg
+	images := imaging.NewProcessor(imaging.Options{
+		MaxDimension:  cfg.Image.MaxDimension,
+		Quality:       cfg.Image.JPEGQuality,
+		HEICConverter: cfg.Image.HEICConverter,
+	})
+	consents := service.NewConsentService(repos.consents, repos.participants, repos.audit, cfg.Consent.TermsVersion)
+	calendar := service.NewCalendarService(repos.holidays, repos.audit, repos.tx, cfg.Calendar.WeekendDays)
+	ktp := service.NewKTPCheckService(repos.ktpChecks, nil, nil, "", nil, nil, service.KTPFaceMatch{}, repos.audit, repos.tx)
+	participants := service.NewParticipantService(repos.participants, repos.frIdentities, repos.certificates, repos.states, repos.locks, repos.conflicts, repos.verificationDevices, repos.homeVisits, repos.members, repos.campaigns, repos.profiles,
+		frClient, images, repos.tx, service.NewOutboxService(repos.outbox), service.ScheduleSettings{
+			Policy:         domain.SchedulePolicy(strings.ToUpper(cfg.Verification.SchedulePolicy)),
+			Date:           cfg.Verification.ScheduleDate,
+			ValidityMonths: cfg.Verification.ValidityMonths,
+		}, calendar, service.FaceQualityThresholds{
+			Enabled:        cfg.Quality.Enabled,
+			MinFaceRatio:   cfg.Quality.MinFaceRatio,
+			MaxPoseDegrees: cfg.Quality.MaxPoseDegrees,
+			MinBrightness:  cfg.Quality.MinBrightness,
+			MaxBrightness:  cfg.Quality.MaxBrightness,
+		}, consents, ktp)
+	return participants, repos, nil
+}
+
+// tenantTransferService builds the service behind tenant export and import.
+func (e *environment) tenantTransferService(ctx context.Context) (*service.TenantTransferService, error) {
+	repos, err := e.repositories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	blobs, err := e.blobs()
+	if err != nil {
+		return nil, err
+	}
+	return service.NewTenantTransferService(repos.tenants, repos.tenantData, blobs, repos.audit, repos.tx), nil
+}
+
+// frClient builds the FR Core client as the server does, so a tenant with
+// FR Core settings of its own uses them.
+func (e *environment) frClient(ctx context.Context) (frcore.Client, error) {
+	cfg, err := e.config()
+	if err != nil {
+		return nil, err
+	}
+	db, err := e.database(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	options := func(t *domain.Tenant) frcore.Options {
+		opts := frcore.Options{
+			BaseURL:         cfg.FRC.BaseURL,
+			UploadAPIKey:    cfg.FRC.UploadAPIKey,
+			RecognizeAPIKey: cfg.FRC.RecognizeAPIKey,
+			TenantID:        cfg.FRC.TenantID,
+			Timeout:         cfg.FRC.RequestTimeout,
+			HTTPClient:      &http.Client{Timeout: cfg.FRC.RequestTimeout},
+			LogLevel:        frcore.LogLevel(cfg.FRC.LogLevel),
+		}
+		if t != nil {
+			if t.FRCoreBaseURL != "" {
+				opts.BaseURL = t.FRCoreBaseURL
+			}
+			if t.FRCoreUploadAPIKey != "" {
+				opts.UploadAPIKey = t.FRCoreUploadAPIKey
+			}
+			if t.FRCoreRecognizeAPIKey != "" {
+				opts.RecognizeAPIKey = t.FRCoreRecognizeAPIKey
+			}
+			if t.FRCoreTenantID != "" {
+				opts.TenantID = t.FRCoreTenantID
+			}
+		}
+		return opts
+	}
+	client, err := frcore.NewHTTPClient(options(nil))
+	if err != nil {
+		return nil, fmt.Errorf("init fr client: %w", err)
+	}
+	return service.NewTenantFRClient(client, repository.NewTenantRepository(db), func(t *domain.Tenant) (frcore.Client, error) {
+		return frcore.NewHTTPClient(options(t))
+	}), nil
+}
+
+func (e *environment) blobs() (storage.BlobStore, error) {
+	cfg, err := e.config()
+	if err != nil {
+		return nil, err
+	}
+	store, err := storage.NewLocalStore(cfg.Storage.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("init blob storage: %w", err)
+	}
+	return store, nil
+}
+
+func (e *environment) close() {
+	for i := len(e.closers) - 1; i >= 0; i-- {
+		if err := e.closers[i].Close(); err != nil {
+			log.Printf("close: %v", err)
+		}
+	}
+}
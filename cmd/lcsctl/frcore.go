@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/service"
+)
+
+func runFRCoreReconcile(ctx context.Context, env *environment, args []string) error {
+	fs := newFlagSet("frcore reconcile", "")
+	deleteOrphans := fs.Bool("delete-orphans", false, "delete the orphaned enrollments from FR Core instead of only listing them")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	repos, err := env.repositories(ctx)
+	if err != nil {
+		return err
+	}
+	frClient, err := env.frClient(ctx)
+	if err != nil {
+		return err
+	}
+	// The run is synchronous; the job service is only needed by the constructor.
+	jobs := service.NewJobService(repos.jobs, repos.audit, service.JobOptions{})
+	reconciliation := service.NewReconciliationService(repos.reconciliations, repos.frIdentities, frClient, jobs, repos.tx)
+
+	run, err := reconciliation.Run(ctx, *deleteOrphans)
+	if err != nil {
+		return err
+	}
+	var orphans []service.OrphanFace
+	_ = json.Unmarshal([]byte(run.Orphans), &orphans)
+	for _, orphan := range orphans {
+		switch {
+		case orphan.Error != "":
+			fmt.Printf("orphan %s (%s): delete failed: %s\n", orphan.Label, orphan.ExternalRef, orphan.Error)
+		case orphan.Deleted:
+			fmt.Printf("orphan %s (%s): deleted\n", orphan.Label, orphan.ExternalRef)
+		default:
+			fmt.Printf("orphan %s (%s)\n", orphan.Label, orphan.ExternalRef)
+		}
+	}
+	fmt.Printf("run %s %s: %d enrollments in FR Core, %d orphaned, %d deleted\n",
+		run.ID, run.Status, run.RemoteCount, run.OrphanCount, run.DeletedCount)
+	if run.Status == domain.FRReconciliationFailed && run.Error != nil {
+		return errors.New(*run.Error)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+// Command lcsctl runs routine maintenance against the service's database and
+// FR Core, reading the same configuration as the server: CONFIG_FILE or
+// -config, overridden by environment variables. Commands act for the default
+// tenant unless -tenant names another.
+//
+//	lcsctl [-config file] [-tenant id] <command> [flags] [args]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"life-certificates/internal/tenant"
+)
+
+// errUsage reports command line mistakes; the usage has already been printed.
+var errUsage = errors.New("usage")
+
+// command is one lcsctl subcommand; args excludes the command's name.
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, env *environment, args []string) error
+}
+
+var commands = []command{
+	{"migrate", "apply schema migrations, or rehearse them with -dry-run", runMigrate},
+	{"seed", "load demo members, participants and certificate histories (needs SEED_ENABLED)", runSeed},
+	{"participant delete", "delete a participant; -purge also removes faces, selfies, documents and devices", runParticipantDelete},
+	{"frcore reconcile", "find FR Core enrollments without a participant, -delete-orphans removes them", runFRCoreReconcile},
+	{"certificate recompute", "rebuild the latest verification status of one or every participant", runCertificateRecompute},
+	{"certificate verify", "check a signed PDF or JSON certificate offline against the published keys", runCertificateVerify},
+	{"tenant export", "write a tenant's rows and files to a ZIP archive for another deployment", runTenantExport},
+	{"tenant import", "load a tenant archive, checking its checksums; -as imports it under another tenant ID", runTenantImport},
+	{"user create", "print the BASIC_AUTH_USERS value that adds an account", runUserCreate},
+}
+
+func main() {
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML or TOML config file; environment variables override its settings")
+	tenantID := flag.String("tenant", "", "tenant the command acts for; the default tenant when empty")
+	flag.Usage = usage
+	flag.Parse()
+
+	cmd, args, ok := lookup(flag.Args())
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *tenantID != "" {
+		ctx = tenant.WithID(ctx, *tenantID)
+	}
+
+	env := &environment{configFile: *configFile, tenantID: *tenantID}
+	defer env.close()
+	switch err := cmd.run(ctx, env, args); {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "lcsctl %s: %v\n", cmd.name, err)
+		os.Exit(1)
+	}
+}
+
+// lookup finds the command named by the leading one or two arguments.
+func lookup(args []string) (command, []string, bool) {
+	for _, cmd := range commands {
+		words := len(strings.Fields(cmd.name))
+		if len(args) >= words && strings.Join(args[:words], " ") == cmd.name {
+			return cmd, args[words:], true
+		}
+	}
+	return command{}, nil, false
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintln(out, "Usage: lcsctl [-config file] [-tenant id] <command> [flags] [args]")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(out, "  %-22s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Run lcsctl <command> -h for the flags of a command.")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Flags:")
+	flag.PrintDefaults()
+}
+
+// newFlagSet returns the flag set of a command, printing usage as
+// "lcsctl <name> [flags] <operands>".
+func newFlagSet(name, operands string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: lcsctl %s [flags] %s\n", name, operands)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses args and returns the operands, which must number want.
+func parseFlags(fs *flag.FlagSet, args []string, want int) ([]string, error) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, err
+		}
+		return nil, errUsage
+	}
+	if fs.NArg() != want {
+		fs.Usage()
+		return nil, errUsage
+	}
+	return fs.Args(), nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"life-certificates/internal/database"
+)
+
+func runMigrate(ctx context.Context, env *environment, args []string) error {
+	fs := newFlagSet("migrate", "")
+	dryRun := fs.Bool("dry-run", false, "apply the migrations in a transaction that is rolled back, to check they would succeed")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	db, err := env.database(ctx, true)
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		if err := database.RehearseMigrate(ctx, db); err != nil {
+			return fmt.Errorf("rehearse migrations: %w", err)
+		}
+		fmt.Println("migrations would apply cleanly; nothing was changed")
+		return nil
+	}
+	if err := database.Migrate(db.WithContext(ctx)); err != nil {
+		return err
+	}
+	fmt.Println("schema is up to date")
+	return nil
+}
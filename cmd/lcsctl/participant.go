@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/user"
+
+	"life-certificates/internal/service"
+)
+
+func runParticipantDelete(ctx context.Context, env *environment, args []string) error {
+	fs := newFlagSet("participant delete", "<participant_id>")
+	purge := fs.Bool("purge", false, "also delete the participant's faces from FR Core, their selfies and documents from storage, and their devices")
+	actor := fs.String("actor", defaultActor(), "operator recorded in the audit log of a purge")
+	operands, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	participantID := operands[0]
+
+	participants, repos, err := env.participantService(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !*purge {
+		if err := participants.Delete(ctx, participantID); err != nil {
+			return err
+		}
+		fmt.Printf("deleted participant %s; faces in FR Core and stored files were kept, use -purge to remove them\n", participantID)
+		return nil
+	}
+
+	frClient, err := env.frClient(ctx)
+	if err != nil {
+		return err
+	}
+	blobs, err := env.blobs()
+	if err != nil {
+		return err
+	}
+	dataSubject := service.NewDataSubjectService(repos.members, repos.participants, repos.certificates, repos.certificateDocuments, repos.documents, repos.frIdentities, repos.campaigns, repos.locks, repos.conflicts, repos.verificationDevices,
+		repos.devices, repos.homeVisits, repos.ktpChecks, repos.notifications, repos.consents, repos.audit, repos.accessLogs, blobs, frClient, repos.tx)
+	out, err := dataSubject.PurgeParticipant(ctx, *actor, participantID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("purged participant %s: %d certificates, %d faces, %d selfies, %d documents, %d devices\n",
+		out.ParticipantID, out.Certificates, out.FacesDeleted, out.SelfiesDeleted, out.DocumentsDeleted, out.DevicesDeleted)
+	return nil
+}
+
+// defaultActor names the operator running lcsctl in audit entries.
+func defaultActor() string {
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		return "lcsctl:" + current.Username
+	}
+	return "lcsctl"
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"life-certificates/internal/config"
+	"life-certificates/internal/service"
+)
+
+func runSeed(ctx context.Context, env *environment, args []string) error {
+	fs := newFlagSet("seed", "")
+	members := fs.Int("members", 50, "demo members to generate")
+	participants := fs.Int("participants", 30, "demo participants to generate, linked to the first members")
+	seed := fs.Int64("seed", 1, "seed for the generated data; the same seed generates the same people, so a rerun skips them")
+	file := fs.String("file", "", "load these JSON fixtures instead of generating data")
+	actor := fs.String("actor", defaultActor(), "operator recorded in the audit log")
+	if _, err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	cfg, err := env.config()
+	if err != nil {
+		return err
+	}
+	if !cfg.Seed.Enabled {
+		return fmt.Errorf("seeding writes fake data and is disabled; set %sSEED_ENABLED=true on demo and staging environments only", os.Getenv(config.PrefixVariable))
+	}
+
+	input := service.SeedInput{Members: *members, Participants: *participants, Seed: *seed}
+	if *file != "" {
+		raw, err := os.ReadFile(*file)
+		if err != nil {
+			return err
+		}
+		var fixtures service.Fixtures
+		if err := json.Unmarshal(raw, &fixtures); err != nil {
+			return fmt.Errorf("parse %s: %w", *file, err)
+		}
+		input.Fixtures = &fixtures
+	}
+
+	repos, err := env.repositories(ctx)
+	if err != nil {
+		return err
+	}
+	seeder := service.NewSeedService(repos.members, repos.participants, repos.frIdentities, repos.certificates, repos.audit, repos.tx, cfg.Review.SLA)
+	result, err := seeder.Seed(ctx, *actor, input)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("members: %d created, %d skipped; participants: %d created with %d certificates, %d skipped\n",
+		result.MembersCreated, result.MembersSkipped, result.ParticipantsCreated, result.Certificates, result.ParticipantsSkipped)
+	return nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"life-certificates/internal/service"
+)
+
+func runTenantExport(ctx context.Context, env *environment, args []string) error {
+	fs := newFlagSet("tenant export", "<archive.zip>")
+	actor := fs.String("actor", defaultActor(), "operator recorded in the audit log")
+	operands, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	transfer, err := env.tenantTransferService(ctx)
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(operands[0])
+	if err != nil {
+		return err
+	}
+	archive, err := transfer.Export(ctx, *actor, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(operands[0])
+		return err
+	}
+
+	fmt.Printf("exported tenant %s to %s: %d rows, %d files\n", archive.Tenant.ID, operands[0], archiveRows(archive), len(archive.Blobs))
+	for _, key := range archive.MissingBlobs {
+		fmt.Fprintf(os.Stderr, "missing from storage, not exported: %s\n", key)
+	}
+	return nil
+}
+
+func runTenantImport(ctx context.Context, env *environment, args []string) error {
+	fs := newFlagSet("tenant import", "<archive.zip>")
+	as := fs.String("as", "", "tenant to import into; the archived tenant's ID when empty")
+	actor := fs.String("actor", defaultActor(), "operator recorded in the audit log")
+	operands, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if env.tenantID != "" {
+		return fmt.Errorf("the tenant is named by the archive or -as, not -tenant")
+	}
+
+	transfer, err := env.tenantTransferService(ctx)
+	if err != nil {
+		return err
+	}
+	reader, err := zip.OpenReader(operands[0])
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	target := strings.ToLower(strings.TrimSpace(*as))
+	archive, err := transfer.Import(ctx, *actor, &reader.Reader, target)
+	if err != nil {
+		return err
+	}
+
+	if target == "" {
+		target = archive.Tenant.ID
+	}
+	fmt.Printf("imported tenant %s as %s: %d rows, %d files; set its FR Core API keys again if it has its own\n", archive.Tenant.ID, target, archiveRows(archive), len(archive.Blobs))
+	return nil
+}
+
+func archiveRows(archive *service.TenantArchive) int {
+	rows := 0
+	for _, table := range archive.Tables {
+		rows += table.Rows
+	}
+	return rows
+}
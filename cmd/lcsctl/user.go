@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"life-certificates/internal/config"
+)
+
+func runUserCreate(ctx context.Context, env *environment, args []string) error {
+	fs := newFlagSet("user create", "<username>")
+	role := fs.String("role", "operator", "admin or operator")
+	password := fs.String("password", "", "password of the account; a random one is generated when empty")
+	tenantID := fs.String("tenant", "", "tenant the account is bound to; empty lets it act for any tenant")
+	operands, err := parseFlags(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	username := strings.TrimSpace(operands[0])
+
+	cfg, err := env.config()
+	if err != nil {
+		return err
+	}
+	if strings.ContainsAny(username, ":,") || username == "" {
+		return fmt.Errorf("username must not be empty or contain ':' or ','")
+	}
+	if username == cfg.Auth.Username {
+		return fmt.Errorf("%s is the primary account", username)
+	}
+	for _, existing := range cfg.Auth.Users {
+		if existing.Username == username {
+			return fmt.Errorf("user %s already exists", username)
+		}
+	}
+
+	secret := *password
+	generated := secret == ""
+	if generated {
+		raw := make([]byte, 18)
+		if _, err := rand.Read(raw); err != nil {
+			return fmt.Errorf("generate password: %w", err)
+		}
+		secret = base64.RawURLEncoding.EncodeToString(raw)
+	}
+	if strings.ContainsAny(secret, ":,") {
+		return fmt.Errorf("password must not contain ':' or ','")
+	}
+
+	// Decoding the new list applies the same checks as loading the config.
+	entries := make([]string, 0, len(cfg.Auth.Users)+1)
+	for _, existing := range cfg.Auth.Users {
+		entries = append(entries, userEntry(existing.Username, existing.Password, existing.Role, existing.Tenant))
+	}
+	entries = append(entries, userEntry(username, secret, *role, strings.TrimSpace(*tenantID)))
+	value := strings.Join(entries, ",")
+	var users config.AuthUsers
+	if err := users.Decode(value); err != nil {
+		return err
+	}
+
+	// Accounts live in the configuration, not the database, so the new list
+	// has to be deployed and the servers restarted.
+	fmt.Fprintf(os.Stderr, "Set this on every server and restart them to add %s as %s:\n", username, *role)
+	fmt.Printf("%sBASIC_AUTH_USERS=%s\n", os.Getenv(config.PrefixVariable), value)
+	if generated {
+		fmt.Fprintf(os.Stderr, "Generated password for %s: %s\n", username, secret)
+	}
+	return nil
+}
+
+// userEntry formats an account as a BASIC_AUTH_USERS entry.
+func userEntry(username, password, role, tenantID string) string {
+	entry := username + ":" + password + ":" + role
+	if tenantID != "" {
+		entry += ":" + tenantID
+	}
+	return entry
+}
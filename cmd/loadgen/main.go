@@ -0,0 +1,120 @@
+// Command loadgen drives POST /life-certificate/verify of a running service
+// and reports latency percentiles per image size. With -mock-frcore it also
+// serves a stand-in FR Core for the service to point FRCORE_BASE_URL at.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"life-certificates/internal/frcore/frcoretest"
+	"life-certificates/internal/loadtest"
+)
+
+func main() {
+	baseURL := flag.String("url", "http://localhost:9800", "base URL of the service under test")
+	username := flag.String("username", os.Getenv("BASIC_AUTH_USERNAME"), "Basic Auth username")
+	password := flag.String("password", os.Getenv("BASIC_AUTH_PASSWORD"), "Basic Auth password")
+	concurrency := flag.Int("concurrency", 8, "verifications in flight at once")
+	requests := flag.Int("requests", 200, "verifications per image size")
+	sizes := flag.String("sizes", "640x480,1280x960,3024x4032", "comma-separated image sizes, WIDTHxHEIGHT")
+	participants := flag.Int("participants", 20, "participants to register and spread the load over")
+	consent := flag.Bool("consent", true, "record consent to the active terms before registering each participant")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "seed for the generated photos")
+	timeout := flag.Duration("timeout", 60*time.Second, "timeout of each request")
+	mockAddr := flag.String("mock-frcore", "", "also serve a mock FR Core on this address, e.g. :8000, for the service's FRCORE_BASE_URL")
+	mockLatency := flag.Duration("mock-latency", 150*time.Millisecond, "latency the mock FR Core adds to each call")
+	mockJitter := flag.Duration("mock-jitter", 50*time.Millisecond, "random extra latency of up to this much per mock call")
+	flag.Parse()
+
+	imageSizes, err := loadtest.ParseSizes(*sizes)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *username == "" {
+		log.Fatal("-username or BASIC_AUTH_USERNAME is required")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *mockAddr != "" {
+		mock := frcoretest.NewServer(frcoretest.Options{Latency: *mockLatency, Jitter: *mockJitter})
+		listener, err := net.Listen("tcp", *mockAddr)
+		if err != nil {
+			log.Fatalf("listen for mock FR Core: %v", err)
+		}
+		srv := &http.Server{Handler: mock, ReadHeaderTimeout: 10 * time.Second}
+		go func() {
+			if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatalf("mock FR Core: %v", err)
+			}
+		}()
+		defer srv.Close()
+		log.Printf("mock FR Core listening on %s", listener.Addr())
+	}
+
+	client := &loadtest.Client{
+		BaseURL:  *baseURL,
+		Username: *username,
+		Password: *password,
+		HTTP: &http.Client{
+			Timeout:   *timeout,
+			Transport: &http.Transport{MaxIdleConnsPerHost: *concurrency},
+		},
+	}
+
+	log.Printf("registering %d participants", *participants)
+	registered, err := loadtest.Setup(ctx, client, *participants, *consent, *seed)
+	if err != nil {
+		log.Fatalf("set up participants: %v", err)
+	}
+
+	log.Printf("sending %d verifications per size, %d at a time", *requests, *concurrency)
+	results, err := loadtest.Run(ctx, client, registered, loadtest.Options{
+		Concurrency: *concurrency,
+		Requests:    *requests,
+		Sizes:       imageSizes,
+		Seed:        *seed,
+	})
+	report(results)
+	if err != nil {
+		log.Fatalf("load run: %v", err)
+	}
+}
+
+func report(results []loadtest.Result) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "size\tok\tfailed\treq/s\tmean\tp50\tp90\tp95\tp99\tmax\t")
+	for _, r := range results {
+		l := r.Latencies
+		fmt.Fprintf(w, "%s\t%d\t%d\t%.1f\t%s\t%s\t%s\t%s\t%s\t%s\t\n", r.Size, l.Count(), r.Failures, r.Throughput(),
+			ms(l.Mean()), ms(l.Percentile(50)), ms(l.Percentile(90)), ms(l.Percentile(95)), ms(l.Percentile(99)), ms(l.Percentile(100)))
+	}
+	w.Flush()
+
+	for _, r := range results {
+		outcomes := make([]string, 0, len(r.Outcomes))
+		for outcome, count := range r.Outcomes {
+			outcomes = append(outcomes, fmt.Sprintf("%s=%d", outcome, count))
+		}
+		sort.Strings(outcomes)
+		fmt.Printf("%s: %s\n", r.Size, strings.Join(outcomes, " "))
+	}
+}
+
+func ms(d time.Duration) string {
+	return fmt.Sprintf("%.0fms", float64(d)/float64(time.Millisecond))
+}
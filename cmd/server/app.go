@@ -0,0 +1,603 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"life-certificates/internal/alerting"
+	"life-certificates/internal/antivirus"
+	"life-certificates/internal/buildinfo"
+	"life-certificates/internal/civilregistry"
+	"life-certificates/internal/config"
+	"life-certificates/internal/database"
+	"life-certificates/internal/decision"
+	"life-certificates/internal/domain"
+	"life-certificates/internal/events"
+	"life-certificates/internal/frcore"
+	"life-certificates/internal/graphql"
+	"life-certificates/internal/grpcapi"
+	httpserver "life-certificates/internal/http"
+	"life-certificates/internal/http/handler"
+	"life-certificates/internal/i18n"
+	"life-certificates/internal/imaging"
+	"life-certificates/internal/liveness"
+	"life-certificates/internal/metrics"
+	"life-certificates/internal/payroll"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/service"
+	"life-certificates/internal/storage"
+)
+
+// signingKeyTimeout bounds fetching the signing key from KMS at startup.
+const signingKeyTimeout = 30 * time.Second
+
+// application is the service wired from its configuration: the HTTP and gRPC
+// servers and the background workers behind them.
+type application struct {
+	server       *httpserver.Server
+	grpc         *grpcapi.Server
+	health       *service.HealthService
+	settings     *service.VerificationSettingsService
+	bulk         *service.BulkRegistrationService
+	reviews      *service.ReviewService
+	participants *service.ParticipantService
+	webhooks     *service.WebhookService
+	outbox       *service.OutboxService
+	alerts       *service.AlertService
+	jobs         *service.JobService
+	scheduler    *service.SchedulerService
+	// paymentPush and holdRelease are nil unless their endpoints are configured.
+	paymentPush *service.PaymentPushService
+	holdRelease *service.HoldReleaseService
+	closers     []io.Closer
+}
+
+// newApplication wires the repositories, services and handlers on top of a
+// migrated database. Nothing runs until start is called.
+func newApplication(cfg *config.Config, db *gorm.DB) (_ *application, err error) {
+	app := &application{}
+	defer func() {
+		if err != nil {
+			app.close()
+		}
+	}()
+
+	blobStore, err := storage.NewLocalStore(cfg.Storage.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("init blob storage: %w", err)
+	}
+
+	var scanner antivirus.Scanner
+	if cfg.Antivirus.ClamdAddr != "" {
+		if scanner, err = antivirus.NewClamdScanner(cfg.Antivirus.ClamdAddr, cfg.Antivirus.Timeout); err != nil {
+			return nil, fmt.Errorf("init antivirus scanner: %w", err)
+		}
+	}
+
+	participantRepo := repository.NewParticipantRepository(db)
+	memberRepo := repository.NewMemberRepository(db)
+	certificateRepo := repository.NewLifeCertificateRepository(db)
+	verificationStateRepo := repository.NewVerificationStateRepository(db)
+	verificationLockRepo := repository.NewVerificationLockRepository(db)
+	facialConflictRepo := repository.NewFacialConflictRepository(db)
+	verificationDeviceRepo := repository.NewVerificationDeviceRepository(db)
+	homeVisitRepo := repository.NewHomeVisitRepository(db)
+	officerRepo := repository.NewOfficerRepository(db)
+	branchRepo := repository.NewBranchRepository(db)
+	holidayRepo := repository.NewHolidayRepository(db)
+	statsRepo := repository.NewStatsRepository(db)
+	monthlyReportRepo := repository.NewMonthlyReportRepository(db)
+	frIdentityRepo := repository.NewFRIdentityRepository(db)
+	bulkRepo := repository.NewBulkRegistrationRepository(db)
+	reconciliationRepo := repository.NewFRReconciliationRepository(db)
+	auditRepo := repository.NewAuditLogRepository(db)
+	certificateDocumentRepo := repository.NewCertificateDocumentRepository(db)
+	documentRepo := repository.NewDocumentRepository(db)
+	ktpCheckRepo := repository.NewKTPCheckRepository(db)
+	overrideRepo := repository.NewStatusOverrideRepository(db)
+	deathReportRepo := repository.NewDeathReportRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	paymentPushRepo := repository.NewPaymentPushRepository(db)
+	holdReleaseRepo := repository.NewHoldReleaseRepository(db)
+	payrollFileRepo := repository.NewPayrollFileRepository(db)
+	partnerRepo := repository.NewPartnerRepository(db)
+	selfServiceRepo := repository.NewSelfServiceRepository(db)
+	sessionRepo := repository.NewVerificationSessionRepository(db)
+	kioskRepo := repository.NewKioskRepository(db)
+	accessLogRepo := repository.NewAccessLogRepository(db)
+	uploadScanRepo := repository.NewUploadScanRepository(db)
+	consentRepo := repository.NewConsentRepository(db)
+	verificationRequestRepo := repository.NewVerificationRequestRepository(db)
+	profileRepo := repository.NewVerificationProfileRepository(db)
+	campaignRepo := repository.NewCampaignRepository(db)
+	reminderRepo := repository.NewReminderRepository(db)
+	jobRepo := repository.NewJobRepository(db)
+	scheduledTaskRepo := repository.NewScheduledTaskRepository(db)
+	tenantRepo := repository.NewTenantRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	deviceRepo := repository.NewDeviceRepository(db)
+	transactor := repository.NewTransactor(db)
+	certificateRepo = repository.NewStateTrackingLifeCertificateRepository(certificateRepo, verificationStateRepo, transactor, cfg.Verification.ValidityMonths)
+
+	lookupCache, err := newCache(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("init cache: %w", err)
+	}
+	if lookupCache != nil {
+		if closer, ok := lookupCache.(io.Closer); ok {
+			app.closers = append(app.closers, closer)
+		}
+		participantRepo = repository.NewCachedParticipantRepository(participantRepo, lookupCache)
+		memberRepo = repository.NewCachedMemberRepository(memberRepo, lookupCache)
+		frIdentityRepo = repository.NewCachedFRIdentityRepository(frIdentityRepo, lookupCache)
+		tenantRepo = repository.NewCachedTenantRepository(tenantRepo, lookupCache)
+	}
+
+	sharedFRClient, err := newFRClient(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("init fr client: %w", err)
+	}
+	var frClient frcore.Client = service.NewTenantFRClient(sharedFRClient, tenantRepo, func(t *domain.Tenant) (frcore.Client, error) {
+		return newTenantFRClient(cfg, t)
+	})
+	// The limiter sits outside the instrumentation so latency excludes the queue wait.
+	limitedFRClient := frcore.NewLimitedClient(metrics.InstrumentFRCore(frClient), frcore.Limits{
+		MaxConcurrent: cfg.FRC.MaxConcurrent,
+		PerOperation:  cfg.FRC.ConcurrencyLimits,
+		MaxQueued:     cfg.FRC.MaxQueued,
+		QueueTimeout:  cfg.FRC.QueueTimeout,
+		OnWait:        metrics.ObserveFRCoreQueueWait,
+	})
+	metrics.RegisterFRCoreQueue(limitedFRClient.Stats)
+	frClient = limitedFRClient
+
+	jobService := service.NewJobService(jobRepo, auditRepo, service.JobOptions{
+		Workers:      cfg.Jobs.Workers,
+		PollInterval: cfg.Jobs.PollInterval,
+		MaxAttempts:  cfg.Jobs.MaxAttempts,
+		Timeout:      cfg.Jobs.Timeout,
+	})
+	webhookService := service.NewWebhookService(webhookRepo, auditRepo, cfg.Webhook.Timeout, cfg.Webhook.MaxAttempts)
+	sinks := []events.Publisher{webhookService}
+	broker, err := newEventBroker(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("init event broker: %w", err)
+	}
+	if broker != nil {
+		app.closers = append(app.closers, broker)
+		sinks = append(sinks, broker)
+	}
+	var paymentClient payroll.Client
+	if cfg.PaymentPush.URL != "" {
+		paymentClient, err = payroll.NewHTTPClient(payroll.Options{
+			URL:      cfg.PaymentPush.URL,
+			AuthType: cfg.PaymentPush.AuthType,
+			Username: cfg.PaymentPush.Username,
+			Password: cfg.PaymentPush.Password,
+			Token:    cfg.PaymentPush.Token,
+			FieldMap: cfg.PaymentPush.FieldMap,
+			Timeout:  cfg.PaymentPush.Timeout,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("init payment system client: %w", err)
+		}
+	}
+	paymentPushService := service.NewPaymentPushService(paymentPushRepo, participantRepo, certificateRepo, auditRepo, paymentClient, cfg.Verification.ValidityMonths, cfg.PaymentPush.MaxAttempts)
+	if paymentClient != nil {
+		sinks = append(sinks, paymentPushService)
+	}
+	var (
+		holdClient   payroll.HoldReleaser
+		holdTemplate *payroll.HoldReleaseTemplate
+	)
+	if cfg.HoldRelease.URL != "" {
+		holdClient, holdTemplate, err = newHoldRelease(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("init hold release client: %w", err)
+		}
+	}
+	holdReleaseService := service.NewHoldReleaseService(holdReleaseRepo, participantRepo, certificateRepo, auditRepo, holdClient, holdTemplate, cfg.Verification.ValidityMonths, cfg.HoldRelease.MaxAttempts)
+	if holdClient != nil {
+		sinks = append(sinks, holdReleaseService)
+	}
+	var payrollUploader payroll.Uploader
+	if cfg.PayrollFile.SFTPHost != "" {
+		payrollUploader, err = newPayrollUploader(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("init payroll file uploader: %w", err)
+		}
+	}
+	payrollFileService := service.NewPayrollFileService(payrollFileRepo, payrollUploader, service.PayrollFileOptions{
+		Format: cfg.PayrollFile.Format,
+		Prefix: cfg.PayrollFile.Prefix,
+		Fund:   cfg.PayrollFile.Fund,
+	})
+	notifiers, err := newAlertNotifiers(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("init alert notifiers: %w", err)
+	}
+	if len(notifiers) > 0 {
+		sinks = append(sinks, alerting.NewDispatcher(notifiers...))
+	}
+	notificationChannels, err := newNotificationChannels(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("init notification channels: %w", err)
+	}
+	// The zone was validated when the config was loaded.
+	notificationLocation, _ := time.LoadLocation(cfg.Notification.Timezone)
+	notificationService := service.NewNotificationService(notificationRepo, participantRepo, memberRepo, deviceRepo, tenantRepo, auditRepo, jobService, transactor, notificationChannels, service.NotificationOptions{
+		TemplateDir:       cfg.Notification.TemplateDir,
+		PhoneCountryCode:  cfg.Notification.PhoneCountryCode,
+		Location:          notificationLocation,
+		PublicURL:         cfg.Notification.PublicURL,
+		UnsubscribeSecret: cfg.Notification.UnsubscribeSecret,
+		Language:          cfg.I18n.DefaultLanguage,
+	})
+	if len(notificationChannels) > 0 {
+		sinks = append(sinks, notificationService)
+	}
+	receiptService := service.NewReceiptService(certificateRepo, participantRepo, service.ReceiptOptions{
+		Secret:         cfg.Receipt.SigningSecret,
+		PublicURL:      cfg.Receipt.PublicURL,
+		ValidityMonths: cfg.Verification.ValidityMonths,
+	})
+	signingCtx, cancelSigning := context.WithTimeout(context.Background(), signingKeyTimeout)
+	signingKey, err := newSigningKey(signingCtx, cfg)
+	cancelSigning()
+	if err != nil {
+		return nil, fmt.Errorf("init certificate signing key: %w", err)
+	}
+	var certificatePDFService *service.CertificatePDFService
+	if cfg.CertificatePDF.Enabled {
+		certificatePDFService, err = newCertificatePDFService(cfg, certificateRepo, participantRepo, memberRepo, tenantRepo, blobStore, receiptService, signingKey, jobService)
+		if err != nil {
+			return nil, fmt.Errorf("init certificate PDF: %w", err)
+		}
+		sinks = append(sinks, certificatePDFService)
+	}
+	catalog, err := i18n.Load(cfg.I18n.CatalogDir, cfg.I18n.DefaultLanguage)
+	if err != nil {
+		return nil, fmt.Errorf("load message catalogs: %w", err)
+	}
+	// The hub comes last so live streams only see events the durable sinks accepted.
+	hub := events.NewHub()
+	sinks = append(sinks, hub)
+	outboxService := service.NewOutboxService(outboxRepo, sinks...)
+	if fields := strings.Fields(cfg.Image.HEICConverter); len(fields) > 0 {
+		if _, err := exec.LookPath(fields[0]); err != nil {
+			log.Printf("HEIC converter %q not found, HEIC uploads will fail: %v", fields[0], err)
+		}
+	}
+	imageProcessor := imaging.NewProcessor(imaging.Options{
+		MaxDimension:  cfg.Image.MaxDimension,
+		Quality:       cfg.Image.JPEGQuality,
+		HEICConverter: cfg.Image.HEICConverter,
+	})
+	consentService := service.NewConsentService(consentRepo, participantRepo, auditRepo, cfg.Consent.TermsVersion)
+	calendarService := service.NewCalendarService(holidayRepo, auditRepo, transactor, cfg.Calendar.WeekendDays)
+	uploadScanService := service.NewUploadScanService(scanner, uploadScanRepo, auditRepo, blobStore, cfg.Antivirus.Quarantine)
+	documentService := service.NewDocumentService(documentRepo, participantRepo, certificateRepo, homeVisitRepo, blobStore, uploadScanService, auditRepo, transactor)
+	ocrReader, err := newOCRReader(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("init OCR reader: %w", err)
+	}
+	ktpCheckService := service.NewKTPCheckService(ktpCheckRepo, documentService, ocrReader, cfg.OCR.Provider, frClient, imageProcessor, service.KTPFaceMatch{
+		Mode:          cfg.KTPFace.Mode,
+		MinSimilarity: cfg.KTPFace.MinSimilarity,
+	}, auditRepo, transactor)
+	participantService := service.NewParticipantService(participantRepo, frIdentityRepo, certificateRepo, verificationStateRepo, verificationLockRepo, facialConflictRepo, verificationDeviceRepo, homeVisitRepo, memberRepo, campaignRepo, profileRepo, frClient, imageProcessor, transactor, outboxService, service.ScheduleSettings{
+		Policy:         domain.SchedulePolicy(strings.ToUpper(cfg.Verification.SchedulePolicy)),
+		Date:           cfg.Verification.ScheduleDate,
+		ValidityMonths: cfg.Verification.ValidityMonths,
+	}, calendarService, service.FaceQualityThresholds{
+		Enabled:        cfg.Quality.Enabled,
+		MinFaceRatio:   cfg.Quality.MinFaceRatio,
+		MaxPoseDegrees: cfg.Quality.MaxPoseDegrees,
+		MinBrightness:  cfg.Quality.MinBrightness,
+		MaxBrightness:  cfg.Quality.MaxBrightness,
+	}, consentService, ktpCheckService)
+	bulkService := service.NewBulkRegistrationService(bulkRepo, participantService, cfg.BulkRegistration.Workers)
+	reconciliationService := service.NewReconciliationService(reconciliationRepo, frIdentityRepo, frClient, jobService, transactor)
+	reviewService := service.NewReviewService(certificateRepo, auditRepo, cfg.Review.SLA, seniorReviewers(cfg), transactor, outboxService)
+	metrics.RegisterReviewQueue(reviewService.QueueDepth)
+	alertService := service.NewAlertService(certificateRepo, reviewService, outboxService, service.AlertThresholds{
+		FRCoreErrorRate:  cfg.Alert.FRCoreErrorRate,
+		InvalidRatio:     cfg.Alert.InvalidRatio,
+		InvalidMin:       cfg.Alert.InvalidMin,
+		ReviewBacklog:    cfg.Alert.ReviewBacklog,
+		RepeatedFailures: cfg.Alert.RepeatedFailures,
+		Cooldown:         cfg.Alert.Cooldown,
+	})
+	overrideService := service.NewStatusOverrideService(overrideRepo, certificateRepo, auditRepo, transactor)
+	var registryClient civilregistry.Client
+	if cfg.CivilRegistry.URL != "" {
+		registryClient, err = civilregistry.NewHTTPClient(civilregistry.Options{
+			BaseURL:  cfg.CivilRegistry.URL,
+			Username: cfg.CivilRegistry.Username,
+			Password: cfg.CivilRegistry.Password,
+			Timeout:  cfg.CivilRegistry.Timeout,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("init civil registry client: %w", err)
+		}
+	}
+	deathReportService := service.NewDeathReportService(deathReportRepo, memberRepo, participantRepo, auditRepo, registryClient, transactor, outboxService)
+	retentionService := service.NewRetentionService(certificateRepo, auditRepo, blobStore, transactor, service.RetentionPolicy{
+		SelfieMonths:    cfg.Retention.SelfieMonths,
+		LatestValidOnly: cfg.Retention.LatestValidOnly,
+	})
+	manualService := service.NewManualVerificationService(participantRepo, certificateRepo, certificateDocumentRepo, auditRepo, blobStore, uploadScanService, homeVisitRepo, campaignRepo, officerRepo, branchRepo, transactor, outboxService, cfg.Review.SLA, calendarService)
+	homeVisitService := service.NewHomeVisitService(homeVisitRepo, participantRepo, memberRepo, officerRepo, auditRepo, transactor)
+	memberService := service.NewMemberService(memberRepo)
+	dataSubjectService := service.NewDataSubjectService(memberRepo, participantRepo, certificateRepo, certificateDocumentRepo, documentRepo, frIdentityRepo, campaignRepo, verificationLockRepo, facialConflictRepo, verificationDeviceRepo, deviceRepo, homeVisitRepo, ktpCheckRepo, notificationRepo, consentRepo, auditRepo, accessLogRepo, blobStore, frClient, transactor)
+	accessLogService := service.NewAccessLogService(accessLogRepo, auditRepo)
+	partnerService := service.NewPartnerService(partnerRepo, auditRepo)
+	officerService := service.NewOfficerService(officerRepo, branchRepo, auditRepo, transactor, service.OfficerThresholds{
+		MaxDailyVerifications: cfg.Officers.MaxDailyVerifications,
+		MinFieldInterval:      cfg.Officers.MinFieldInterval,
+	})
+	branchService := service.NewBranchService(branchRepo, auditRepo, transactor)
+	exportService := service.NewExportService(memberRepo, certificateRepo, campaignRepo)
+	tenantService := service.NewTenantService(tenantRepo, auditRepo, blobStore)
+	seedService := service.NewSeedService(memberRepo, participantRepo, frIdentityRepo, certificateRepo, auditRepo, transactor, cfg.Review.SLA)
+	// LIVENESS_ENABLED is applied through the settings service so it can be toggled at runtime.
+	checker := liveness.NoopChecker{Enabled: true}
+	settingsService := service.NewVerificationSettingsService(verificationSettings(cfg), auditRepo, tenantRepo)
+	statsService := service.NewStatsService(statsRepo, settingsService)
+	monthlyReportService, err := newMonthlyReportService(cfg, statsRepo, monthlyReportRepo, blobStore, signingKey)
+	if err != nil {
+		return nil, fmt.Errorf("init monthly report: %w", err)
+	}
+	profileService := service.NewVerificationProfileService(profileRepo, participantRepo, auditRepo, transactor)
+	var selfieStore storage.BlobStore
+	if cfg.Storage.Selfies {
+		selfieStore = blobStore
+	}
+	verificationLockService := service.NewVerificationLockService(verificationLockRepo, participantRepo, auditRepo, transactor, service.VerificationLockPolicy{
+		MaxFailures: cfg.VerificationLock.MaxFailures,
+		Cooldown:    cfg.VerificationLock.Cooldown,
+	})
+	facialConflictService := service.NewFacialConflictService(facialConflictRepo, participantRepo, auditRepo, transactor, outboxService)
+	verificationDeviceService := service.NewVerificationDeviceService(verificationDeviceRepo, participantRepo, auditRepo, transactor, cfg.VerificationDevice.TrustOnValid)
+	decisionRules, err := decision.Load(cfg.Verification.RulesFile)
+	if err != nil {
+		return nil, fmt.Errorf("load decision rules: %w", err)
+	}
+	// The zone was validated when the config was loaded.
+	captureLocation, _ := time.LoadLocation(cfg.Capture.Timezone)
+	verificationService := service.NewVerificationService(participantRepo, certificateRepo, verificationStateRepo, frIdentityRepo, profileRepo, campaignRepo, frClient, imageProcessor, selfieStore, checker, settingsService, cfg.Review.SLA, calendarService, service.CaptureCheck{
+		Enabled:           cfg.Capture.Enabled,
+		MaxAge:            cfg.Capture.MaxAge,
+		MaxDistanceMeters: cfg.Capture.MaxDistanceMeters,
+		Location:          captureLocation,
+	}, service.FraudCheck{
+		Enabled:                  cfg.Fraud.Enabled,
+		Window:                   cfg.Fraud.Window,
+		MaxParticipantsPerSource: cfg.Fraud.MaxParticipantsPerSource,
+		MaxTravelSpeedKMH:        cfg.Fraud.MaxTravelSpeedKMH,
+		ReviewScore:              cfg.Fraud.ReviewScore,
+	}, service.StepUpCheck{
+		Enabled:          cfg.StepUp.Enabled,
+		RiskScore:        cfg.StepUp.RiskScore,
+		SimilarityMargin: cfg.StepUp.SimilarityMargin,
+		DistanceMargin:   cfg.StepUp.DistanceMargin,
+		Challenge:        strings.ToLower(cfg.StepUp.Challenge),
+	}, decisionRules, verificationLockService, facialConflictService, verificationDeviceService, consentService, transactor, outboxService)
+	sessionService := service.NewVerificationSessionService(sessionRepo, participantRepo, imageProcessor, service.VerificationSessionOptions{
+		Required:      cfg.Session.Required,
+		TTL:           cfg.Session.TTL,
+		MaxImageBytes: cfg.Session.MaxImageBytes,
+	})
+	selfService := service.NewSelfServiceService(selfServiceRepo, participantRepo, memberRepo, auditRepo, notificationService, verificationService, service.SelfServiceOptions{
+		Secret:         cfg.SelfService.TokenSecret,
+		CodeLength:     cfg.SelfService.CodeLength,
+		CodeTTL:        cfg.SelfService.CodeTTL,
+		MaxAttempts:    cfg.SelfService.MaxAttempts,
+		ResendInterval: cfg.SelfService.ResendInterval,
+		TokenTTL:       cfg.SelfService.TokenTTL,
+	})
+	kioskService := service.NewKioskService(kioskRepo, participantRepo, officerRepo, branchRepo, auditRepo, consentService, verificationService)
+	asyncVerificationService := service.NewAsyncVerificationService(verificationRequestRepo, verificationService, sessionService, blobStore, uploadScanService, jobService, transactor, outboxService)
+
+	participantHandler := handler.NewParticipantHandler(participantService)
+	memberHandler := handler.NewMemberHandler(memberService)
+	dataSubjectHandler := handler.NewDataSubjectHandler(dataSubjectService)
+	consentHandler := handler.NewConsentHandler(consentService)
+	lifeHandler := handler.NewLifeCertificateHandler(verificationService, sessionService)
+	verificationRequestHandler := handler.NewVerificationRequestHandler(asyncVerificationService)
+	bulkHandler := handler.NewBulkRegistrationHandler(bulkService)
+	reconciliationHandler := handler.NewReconciliationHandler(reconciliationService)
+	reviewHandler := handler.NewReviewHandler(reviewService)
+	manualHandler := handler.NewManualVerificationHandler(manualService)
+	documentHandler := handler.NewDocumentHandler(documentService)
+	ktpCheckHandler := handler.NewKTPCheckHandler(ktpCheckService)
+	overrideHandler := handler.NewStatusOverrideHandler(overrideService)
+	deathReportHandler := handler.NewDeathReportHandler(deathReportService)
+	homeVisitHandler := handler.NewHomeVisitHandler(homeVisitService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	paymentPushHandler := handler.NewPaymentPushHandler(paymentPushService)
+	holdReleaseHandler := handler.NewHoldReleaseHandler(holdReleaseService)
+	payrollFileHandler := handler.NewPayrollFileHandler(payrollFileService)
+	accessLogHandler := handler.NewAccessLogHandler(accessLogService)
+	partnerHandler := handler.NewPartnerHandler(partnerService, accessLogService)
+	selfServiceHandler := handler.NewSelfServiceHandler(selfService, sessionService)
+	kioskHandler := handler.NewKioskHandler(kioskService, sessionService, accessLogService)
+	officerHandler := handler.NewOfficerHandler(officerService)
+	branchHandler := handler.NewBranchHandler(branchService)
+	holidayHandler := handler.NewHolidayHandler(calendarService)
+	receiptHandler := handler.NewReceiptHandler(receiptService, cfg.Receipt.QRSize)
+	certificatePDFHandler := handler.NewCertificatePDFHandler(certificatePDFService)
+	signingHandler := handler.NewSigningHandler(signingKey)
+	exportHandler := handler.NewExportHandler(exportService)
+	seedHandler := handler.NewSeedHandler(seedService)
+	uploadScanHandler := handler.NewUploadScanHandler(uploadScanService)
+	settingsHandler := handler.NewVerificationSettingsHandler(settingsService)
+	profileHandler := handler.NewVerificationProfileHandler(profileService)
+	verificationLockHandler := handler.NewVerificationLockHandler(verificationLockService)
+	facialConflictHandler := handler.NewFacialConflictHandler(facialConflictService)
+	verificationDeviceHandler := handler.NewVerificationDeviceHandler(verificationDeviceService)
+	statsHandler := handler.NewStatsHandler(statsService)
+	monthlyReportHandler := handler.NewMonthlyReportHandler(monthlyReportService)
+	campaignService := service.NewCampaignService(campaignRepo, participantRepo, reminderRepo, auditRepo, notificationService, jobService, outboxService, transactor, calendarService, service.CampaignOptions{
+		NotifyPerMinute: cfg.Campaign.NotifyPerMinute,
+	})
+	campaignHandler := handler.NewCampaignHandler(campaignService)
+	reminderService := service.NewReminderService(reminderRepo, transactor, outboxService, calendarService, service.ReminderOptions{
+		LeadDays:     cfg.Reminder.LeadDays,
+		RepeatDays:   cfg.Reminder.RepeatDays,
+		MaxPerTarget: cfg.Reminder.MaxPerTarget,
+	}, cfg.Verification.ValidityMonths)
+	jobHandler := handler.NewJobHandler(jobService)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+	schedulerService := service.NewSchedulerService(scheduledTaskRepo, tenantRepo)
+	if err := registerScheduledTasks(cfg, schedulerService, reminderService, campaignService, reconciliationService, paymentPushService, retentionService, deathReportService, payrollFileService, monthlyReportService, paymentClient != nil); err != nil {
+		return nil, fmt.Errorf("register scheduled tasks: %w", err)
+	}
+	schedulerHandler := handler.NewSchedulerHandler(schedulerService)
+	tenantHandler := handler.NewTenantHandler(tenantService)
+	streamHandler := handler.NewVerificationStreamHandler(service.NewVerificationStreamService(hub))
+
+	healthService := service.NewHealthService(
+		service.HealthCheck{Name: "database", Check: func(ctx context.Context) error { return database.Ping(ctx, db) }, Startup: true},
+		service.HealthCheck{Name: "migrations", Check: func(ctx context.Context) error { return database.CheckSchema(ctx, db) }, Startup: true},
+		service.HealthCheck{Name: "frcore", Check: frClient.Ping},
+	)
+
+	app.server = httpserver.NewServer(cfg, httpserver.Handlers{
+		Health:             handler.NewHealthHandler(healthService),
+		Version:            handler.NewVersionHandler(buildinfo.Get(), cfg.Summary()),
+		Participant:        participantHandler,
+		Member:             memberHandler,
+		DataSubject:        dataSubjectHandler,
+		Consent:            consentHandler,
+		LifeCertificate:    lifeHandler,
+		Verification:       verificationRequestHandler,
+		BulkRegistration:   bulkHandler,
+		Reconciliation:     reconciliationHandler,
+		Review:             reviewHandler,
+		Manual:             manualHandler,
+		Document:           documentHandler,
+		KTPCheck:           ktpCheckHandler,
+		StatusOverride:     overrideHandler,
+		DeathReport:        deathReportHandler,
+		HomeVisit:          homeVisitHandler,
+		Webhook:            webhookHandler,
+		Stream:             streamHandler,
+		PaymentPush:        paymentPushHandler,
+		HoldRelease:        holdReleaseHandler,
+		PayrollFile:        payrollFileHandler,
+		Partner:            partnerHandler,
+		SelfService:        selfServiceHandler,
+		Kiosk:              kioskHandler,
+		Officer:            officerHandler,
+		Branch:             branchHandler,
+		Holiday:            holidayHandler,
+		Receipt:            receiptHandler,
+		CertificatePDF:     certificatePDFHandler,
+		Signing:            signingHandler,
+		AccessLog:          accessLogHandler,
+		UploadScan:         uploadScanHandler,
+		Settings:           settingsHandler,
+		Profile:            profileHandler,
+		VerificationLock:   verificationLockHandler,
+		FacialConflict:     facialConflictHandler,
+		VerificationDevice: verificationDeviceHandler,
+		Stats:              statsHandler,
+		MonthlyReport:      monthlyReportHandler,
+		Campaign:           campaignHandler,
+		Job:                jobHandler,
+		Scheduler:          schedulerHandler,
+		Notification:       notificationHandler,
+		Tenant:             tenantHandler,
+		Export:             exportHandler,
+		Seed:               seedHandler,
+		Access:             accessLogService,
+		Unmask:             accessLogService,
+		Partners:           partnerAuthenticator(partnerService),
+		SelfTokens:         selfService.Authenticate,
+		Kiosks:             kioskAuthenticator(kioskService),
+		Tenants:            tenantLookup(tenantService),
+		Catalog:            catalog,
+		GraphQL: graphql.NewHandler(&graphql.Resolver{
+			ParticipantService:  participantService,
+			MemberService:       memberService,
+			VerificationService: verificationService,
+		}, cfg.GraphQL.MaxDepth),
+	})
+
+	if cfg.GRPC.Port > 0 {
+		app.grpc = grpcapi.NewServer(cfg, participantService, verificationService, sessionService, accessLogService, tenantLookup(tenantService))
+	}
+
+	app.health = healthService
+	app.settings = settingsService
+	app.bulk = bulkService
+	app.reviews = reviewService
+	app.participants = participantService
+	app.webhooks = webhookService
+	app.outbox = outboxService
+	app.alerts = alertService
+	app.jobs = jobService
+	app.scheduler = schedulerService
+	if paymentClient != nil {
+		app.paymentPush = paymentPushService
+	}
+	if holdClient != nil {
+		app.holdRelease = holdReleaseService
+	}
+	return app, nil
+}
+
+// start runs the startup backfills, then the background workers until ctx is
+// done, and marks the service started.
+func (a *application) start(ctx context.Context) error {
+	if err := a.bulk.Start(ctx); err != nil {
+		return fmt.Errorf("start bulk registration workers: %w", err)
+	}
+	if n, err := a.reviews.BackfillDueDates(ctx); err != nil {
+		return fmt.Errorf("backfill review due dates: %w", err)
+	} else if n > 0 {
+		log.Printf("assigned SLA due dates to %d pending reviews", n)
+	}
+	if n, err := a.participants.BackfillVerificationStates(ctx); err != nil {
+		return fmt.Errorf("backfill verification states: %w", err)
+	} else if n > 0 {
+		log.Printf("built latest verification states for %d participants", n)
+	}
+	a.webhooks.Start(ctx)
+	a.outbox.Start(ctx)
+	if a.paymentPush != nil {
+		a.paymentPush.Start(ctx)
+	}
+	if a.holdRelease != nil {
+		a.holdRelease.Start(ctx)
+	}
+	a.alerts.Start(ctx)
+	a.jobs.Start(ctx)
+	if err := a.scheduler.Start(ctx); err != nil {
+		return fmt.Errorf("start scheduler: %w", err)
+	}
+	a.health.MarkStarted()
+	return nil
+}
+
+// close waits for in-flight bulk registrations, once start's context is done,
+// and releases the cache and broker connections.
+func (a *application) close() {
+	if a.bulk != nil {
+		a.bulk.Wait()
+	}
+	for _, closer := range a.closers {
+		if err := closer.Close(); err != nil {
+			log.Printf("close: %v", err)
+		}
+	}
+}
@@ -0,0 +1,295 @@
+//go:build integration
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+	"gorm.io/gorm"
+
+	"life-certificates/internal/config"
+	"life-certificates/internal/database"
+	"life-certificates/internal/frcore/frcoretest"
+	"life-certificates/internal/loadtest"
+)
+
+const (
+	e2eUsername = "e2e"
+	e2ePassword = "e2e-secret"
+)
+
+// harness is the service under test: the real router and services on a
+// throwaway Postgres, with FR Core mocked. It is nil when Docker is not
+// available.
+var harness *e2eHarness
+
+// skipReason explains why harness is nil.
+var skipReason string
+
+type e2eHarness struct {
+	url    string
+	frcore *frcoretest.Server
+	client *loadtest.Client
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(runE2E(m))
+}
+
+// runE2E starts Postgres in Docker, boots the service against it and runs the
+// tests, removing the container afterwards.
+func runE2E(m *testing.M) int {
+	pool, err := dockertest.NewPool("")
+	if err == nil {
+		err = pool.Client.Ping()
+	}
+	if err != nil {
+		skipReason = fmt.Sprintf("docker is not available: %v", err)
+		return m.Run()
+	}
+	pool.MaxWait = 2 * time.Minute
+
+	postgres, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: "postgres",
+		Tag:        "16-alpine",
+		Env:        []string{"POSTGRES_USER=postgres", "POSTGRES_PASSWORD=postgres", "POSTGRES_DB=life_certificates"},
+	}, func(host *docker.HostConfig) {
+		host.AutoRemove = true
+		host.RestartPolicy = docker.RestartPolicy{Name: "no"}
+	})
+	if err != nil {
+		log.Printf("start postgres: %v", err)
+		return 1
+	}
+	defer func() {
+		if err := pool.Purge(postgres); err != nil {
+			log.Printf("remove postgres: %v", err)
+		}
+	}()
+	// Docker removes the container on its own if the run is killed.
+	if err := postgres.Expire(600); err != nil {
+		log.Printf("expire postgres: %v", err)
+		return 1
+	}
+
+	mock := frcoretest.NewServer(frcoretest.Options{})
+	frcoreServer := httptest.NewServer(mock)
+	defer frcoreServer.Close()
+
+	storageDir, err := os.MkdirTemp("", "life-certificates-e2e")
+	if err != nil {
+		log.Printf("create storage dir: %v", err)
+		return 1
+	}
+	defer os.RemoveAll(storageDir)
+
+	env := map[string]string{
+		"DATABASE_DSN":             fmt.Sprintf("postgres://postgres:postgres@%s/life_certificates?sslmode=disable", postgres.GetHostPort("5432/tcp")),
+		"BASIC_AUTH_USERNAME":      e2eUsername,
+		"BASIC_AUTH_PASSWORD":      e2ePassword,
+		"FRCORE_BASE_URL":          frcoreServer.URL,
+		"FRCORE_UPLOAD_API_KEY":    "e2e",
+		"FRCORE_RECOGNIZE_API_KEY": "e2e",
+		"FRCORE_LOG_LEVEL":         "none",
+		"STORAGE_DIR":              storageDir,
+		"GRPC_PORT":                "0",
+	}
+	for key, value := range env {
+		if err := os.Setenv(key, value); err != nil {
+			log.Printf("set %s: %v", key, err)
+			return 1
+		}
+	}
+	cfg, err := config.Load("")
+	if err != nil {
+		log.Printf("load config: %v", err)
+		return 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err = pool.Retry(func() error {
+		db, err := database.New(cfg.Database.DSN)
+		if err != nil {
+			return err
+		}
+		defer closeDB(db)
+		return database.Ping(ctx, db)
+	})
+	if err != nil {
+		log.Printf("wait for postgres: %v", err)
+		return 1
+	}
+	db, err := database.New(cfg.Database.DSN)
+	if err != nil {
+		log.Printf("init database: %v", err)
+		return 1
+	}
+	defer closeDB(db)
+	if err := database.Migrate(db); err != nil {
+		log.Printf("migrate database: %v", err)
+		return 1
+	}
+
+	app, err := newApplication(cfg, db)
+	if err != nil {
+		log.Printf("init application: %v", err)
+		return 1
+	}
+	// Cancelling ctx stops the workers before app.close waits for them.
+	defer app.close()
+	defer cancel()
+	if err := app.start(ctx); err != nil {
+		log.Printf("start: %v", err)
+		return 1
+	}
+
+	server := httptest.NewServer(app.server.Handler())
+	defer server.Close()
+
+	harness = &e2eHarness{
+		url:    server.URL,
+		frcore: mock,
+		client: &loadtest.Client{
+			BaseURL:  server.URL,
+			Username: e2eUsername,
+			Password: e2ePassword,
+			HTTP:     &http.Client{Timeout: 30 * time.Second},
+		},
+	}
+	return m.Run()
+}
+
+func requireHarness(t *testing.T) *e2eHarness {
+	t.Helper()
+	if harness == nil {
+		t.Skip(skipReason)
+	}
+	return harness
+}
+
+func TestReady(t *testing.T) {
+	h := requireHarness(t)
+
+	if code, _ := h.call(t, http.MethodGet, "/ready"); code != http.StatusOK {
+		t.Fatalf("GET /ready: status=%d, want %d", code, http.StatusOK)
+	}
+}
+
+func TestParticipantLifecycle(t *testing.T) {
+	h := requireHarness(t)
+	ctx := context.Background()
+	rng := rand.New(rand.NewSource(1))
+	faces := h.frcore.Faces()
+
+	photo, err := loadtest.Selfie(rng, loadtest.Size{Width: 640, Height: 480})
+	if err != nil {
+		t.Fatal(err)
+	}
+	participant, err := h.client.Register(ctx, "9900000000000001", "E2E Participant", photo)
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if got := h.frcore.Faces(); got != faces+1 {
+		t.Fatalf("FR Core faces after register = %d, want %d", got, faces+1)
+	}
+	if _, err := h.client.Register(ctx, "9900000000000001", "E2E Duplicate", photo); err == nil {
+		t.Fatal("registering the same NIK twice succeeded")
+	}
+
+	if status := h.lastStatus(t, participant.ID); status != nil {
+		t.Fatalf("last_status before any verification = %v, want null", status)
+	}
+
+	verify := func(label, want string) {
+		t.Helper()
+		selfie, err := loadtest.Selfie(rng, loadtest.Size{Width: 640, Height: 480})
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := h.client.Verify(ctx, loadtest.Participant{ID: participant.ID, Label: label}, selfie)
+		if err != nil {
+			t.Fatalf("verify: %v", err)
+		}
+		if result.StatusCode != http.StatusOK || result.Status != want {
+			t.Fatalf("verify as %q: status=%d verification_status=%q code=%q, want %d %q", label, result.StatusCode, result.Status, result.Code, http.StatusOK, want)
+		}
+		if status := h.lastStatus(t, participant.ID); status != want {
+			t.Fatalf("last_status after verifying as %q = %v, want %q", label, status, want)
+		}
+	}
+	// The mock recognises a selfie by its file name, <label>.jpg.
+	verify(participant.Label, "VALID")
+	verify("someone-else", "INVALID")
+
+	if code, _ := h.call(t, http.MethodDelete, "/participants/"+participant.ID); code != http.StatusNoContent {
+		t.Fatalf("delete: status=%d, want %d", code, http.StatusNoContent)
+	}
+	if code, _ := h.call(t, http.MethodGet, "/life-certificate/status/"+participant.ID); code != http.StatusNotFound {
+		t.Fatalf("status after delete: status=%d, want %d", code, http.StatusNotFound)
+	}
+	result, err := h.client.Verify(ctx, participant, photo)
+	if err != nil {
+		t.Fatalf("verify after delete: %v", err)
+	}
+	if result.StatusCode != http.StatusNotFound {
+		t.Fatalf("verify after delete: status=%d, want %d", result.StatusCode, http.StatusNotFound)
+	}
+}
+
+// lastStatus returns the participant's last_status, nil before any verification.
+func (h *e2eHarness) lastStatus(t *testing.T, participantID string) interface{} {
+	t.Helper()
+	code, body := h.call(t, http.MethodGet, "/life-certificate/status/"+participantID)
+	if code != http.StatusOK {
+		t.Fatalf("status: status=%d body=%s", code, body)
+	}
+	var resp struct {
+		Data struct {
+			LastStatus interface{} `json:"last_status"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		t.Fatalf("decode status: %v", err)
+	}
+	return resp.Data.LastStatus
+}
+
+// call sends an authenticated request without a body.
+func (h *e2eHarness) call(t *testing.T, method, path string) (int, []byte) {
+	t.Helper()
+	req, err := http.NewRequest(method, h.url+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.SetBasicAuth(e2eUsername, e2ePassword)
+	resp, err := h.client.HTTP.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read %s %s: %v", method, path, err)
+	}
+	return resp.StatusCode, body
+}
+
+func closeDB(db *gorm.DB) {
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.Close()
+	}
+}
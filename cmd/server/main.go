@@ -3,89 +3,547 @@
 // @description API for managing participants and life certificate verifications
 // @BasePath /
 // @securityDefinitions.basic BasicAuth
+// @securityDefinitions.apikey PartnerKey
+// @in header
+// @name X-API-Key
+// @securityDefinitions.apikey SelfServiceToken
+// @in header
+// @name Authorization
+// @securityDefinitions.apikey KioskKey
+// @in header
+// @name X-Kiosk-Key
 package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"flag"
 	"log"
 	"net/http"
+	"os"
 	"os/signal"
 	"syscall"
 	"time"
+	// Embedded zone data keeps NOTIFICATION_TIMEZONE working in images without tzdata.
+	_ "time/tzdata"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 
 	_ "life-certificates/docs"
+	"life-certificates/internal/alerting"
+	"life-certificates/internal/buildinfo"
+	"life-certificates/internal/cache"
 	"life-certificates/internal/config"
 	"life-certificates/internal/database"
+	"life-certificates/internal/document"
+	"life-certificates/internal/domain"
+	"life-certificates/internal/events"
 	"life-certificates/internal/frcore"
-	httpserver "life-certificates/internal/http"
-	"life-certificates/internal/http/handler"
-	"life-certificates/internal/liveness"
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/notification"
+	"life-certificates/internal/ocr"
+	"life-certificates/internal/payroll"
 	"life-certificates/internal/repository"
 	"life-certificates/internal/service"
+	"life-certificates/internal/signing"
+	"life-certificates/internal/storage"
+	"life-certificates/internal/tracing"
 )
 
 func main() {
-	cfg, err := config.Load()
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML or TOML config file; environment variables override its settings")
+	validateConfig := flag.Bool("validate-config", false, "check the configuration and dependencies, print a report and exit")
+	dryRun := flag.Bool("dry-run", false, "like -validate-config, and also rehearse migrations in a rolled-back transaction")
+	flag.Parse()
+
+	if *validateConfig || *dryRun {
+		if !runPreflight(*configFile, *dryRun, os.Stdout) {
+			os.Exit(1)
+		}
+		return
+	}
+
+	cfg, err := config.Load(*configFile)
 	if err != nil {
 		log.Fatalf("load config: %v", err)
 	}
+	logEffectiveConfig(cfg)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		Endpoint:    cfg.Tracing.Endpoint,
+		ServiceName: cfg.Tracing.ServiceName,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		log.Fatalf("init tracing: %v", err)
+	}
 
 	db, err := database.New(cfg.Database.DSN)
 	if err != nil {
 		log.Fatalf("init database: %v", err)
 	}
+	if err := tracing.InstrumentGORM(db); err != nil {
+		log.Fatalf("instrument database: %v", err)
+	}
 
 	if err := database.Migrate(db); err != nil {
 		log.Fatalf("migrate database: %v", err)
 	}
 
-	frClient, err := frcore.NewHTTPClient(frcore.Options{
-		BaseURL:         cfg.FRC.BaseURL,
-		UploadAPIKey:    cfg.FRC.UploadAPIKey,
-		RecognizeAPIKey: cfg.FRC.RecognizeAPIKey,
-		TenantID:        cfg.FRC.TenantID,
-		Timeout:         cfg.FRC.RequestTimeout,
-	})
+	app, err := newApplication(cfg, db)
 	if err != nil {
-		log.Fatalf("init fr client: %v", err)
+		log.Fatalf("init application: %v", err)
 	}
 
-	participantRepo := repository.NewParticipantRepository(db)
-	memberRepo := repository.NewMemberRepository(db)
-	certificateRepo := repository.NewLifeCertificateRepository(db)
-	frIdentityRepo := repository.NewFRIdentityRepository(db)
-
-	participantService := service.NewParticipantService(participantRepo, frIdentityRepo, certificateRepo, frClient)
-	memberService := service.NewMemberService(memberRepo)
-	checker := liveness.NoopChecker{Enabled: cfg.Liveness.Enabled}
-	verificationService := service.NewVerificationService(participantRepo, certificateRepo, frIdentityRepo, frClient, checker, cfg.Verification.DistanceThreshold, cfg.Verification.SimilarityThreshold)
-
-	participantHandler := handler.NewParticipantHandler(participantService)
-	memberHandler := handler.NewMemberHandler(memberService)
-	lifeHandler := handler.NewLifeCertificateHandler(verificationService)
-
-	srv := httpserver.NewServer(cfg, participantHandler, memberHandler, lifeHandler)
-
 	sigCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
+	// Serve probes while startup tasks run; /startup and /ready report starting until they finish.
 	go func() {
-		log.Printf("HTTP server listening on %s:%d", cfg.HTTP.Host, cfg.HTTP.Port)
-		if err := srv.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Printf("HTTP server listening on %s:%d (commit %s)", cfg.HTTP.Host, cfg.HTTP.Port, buildinfo.Get().Commit)
+		if err := app.server.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Fatalf("http server: %v", err)
 		}
 	}()
 
+	if err := app.start(sigCtx); err != nil {
+		log.Fatalf("start: %v", err)
+	}
+	reloadOnSIGHUP(sigCtx, *configFile, app.settings)
+
+	if app.grpc != nil {
+		go func() {
+			log.Printf("gRPC server listening on %s:%d", cfg.GRPC.Host, cfg.GRPC.Port)
+			if err := app.grpc.Start(); err != nil {
+				log.Fatalf("grpc server: %v", err)
+			}
+		}()
+	}
+
 	<-sigCtx.Done()
 	log.Println("shutdown signal received")
 
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	if err := srv.Shutdown(shutdownCtx); err != nil {
+	if err := app.server.Shutdown(shutdownCtx); err != nil {
 		log.Fatalf("server shutdown: %v", err)
 	}
+	if app.grpc != nil {
+		app.grpc.Shutdown(shutdownCtx)
+	}
+	app.close()
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		log.Printf("flush traces: %v", err)
+	}
 
 	log.Println("server stopped cleanly")
 }
+
+// registerScheduledTasks declares the tasks that must run once per schedule
+// across every replica.
+func registerScheduledTasks(cfg *config.Config, scheduler *service.SchedulerService, reminders *service.ReminderService, campaigns *service.CampaignService, reconciliation *service.ReconciliationService, paymentPush *service.PaymentPushService, retention *service.RetentionService, deathReports *service.DeathReportService, payrollFiles *service.PayrollFileService, monthlyReports *service.MonthlyReportService, paymentPushEnabled bool) error {
+	if err := scheduler.Register("reminders.dispatch", string(cfg.Reminder.Schedule), reminders.Dispatch); err != nil {
+		return err
+	}
+	if err := scheduler.Register("campaigns.refresh", string(cfg.Campaign.RefreshSchedule), campaigns.RefreshAll); err != nil {
+		return err
+	}
+	if err := scheduler.Register("frcore.reconcile", string(cfg.FRC.ReconcileSchedule), func(ctx context.Context) error {
+		run, err := reconciliation.Run(ctx, cfg.FRC.ReconcileDelete)
+		if err != nil {
+			return err
+		}
+		if run.Error != nil {
+			return errors.New(*run.Error)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := scheduler.Register("retention.purge_selfies", string(cfg.Retention.PurgeSchedule), retention.PurgeSelfies); err != nil {
+		return err
+	}
+	if paymentPushEnabled {
+		if err := scheduler.Register("payment_push.expire", string(cfg.PaymentPush.ExpirySchedule), paymentPush.SweepExpired); err != nil {
+			return err
+		}
+	}
+	if err := scheduler.Register("monthly_report.generate", string(cfg.MonthlyReport.Schedule), monthlyReports.GeneratePrevious); err != nil {
+		return err
+	}
+	if cfg.CivilRegistry.URL != "" {
+		if err := scheduler.Register("civil_registry.check_deaths", string(cfg.CivilRegistry.Schedule), deathReports.Check); err != nil {
+			return err
+		}
+	}
+	if cfg.PayrollFile.SFTPHost != "" {
+		if err := scheduler.Register("payroll_file.export", string(cfg.PayrollFile.Schedule), payrollFiles.Export); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verificationSettings extracts the settings that can change at runtime.
+func verificationSettings(cfg *config.Config) service.VerificationSettings {
+	return service.VerificationSettings{
+		DistanceThreshold:   cfg.Verification.DistanceThreshold,
+		SimilarityThreshold: cfg.Verification.SimilarityThreshold,
+		LivenessEnabled:     cfg.Liveness.Enabled,
+	}
+}
+
+// seniorReviewers are REVIEW_SENIOR_REVIEWERS or, without them, the admin accounts.
+func seniorReviewers(cfg *config.Config) []string {
+	if len(cfg.Review.SeniorReviewers) > 0 {
+		return cfg.Review.SeniorReviewers
+	}
+	var admins []string
+	for _, credential := range middleware.CredentialsFromConfig(cfg) {
+		if credential.Role == middleware.RoleAdmin {
+			admins = append(admins, credential.Username)
+		}
+	}
+	return admins
+}
+
+// reloadOnSIGHUP re-reads the config file and environment on SIGHUP and
+// applies the verification settings; other settings still need a restart.
+func reloadOnSIGHUP(ctx context.Context, configFile string, settings *service.VerificationSettingsService) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(hup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				cfg, err := config.Load(configFile)
+				if err != nil {
+					log.Printf("reload config: %v", err)
+					continue
+				}
+				applied, err := settings.Replace(ctx, "", verificationSettings(cfg))
+				if err != nil {
+					log.Printf("apply reloaded verification settings: %v", err)
+					continue
+				}
+				log.Printf("reloaded verification settings: %+v", *applied)
+			}
+		}
+	}()
+}
+
+// logEffectiveConfig prints the merged configuration, secrets redacted, so operators can see what is in force.
+func logEffectiveConfig(cfg *config.Config) {
+	summary, err := json.Marshal(cfg.Summary())
+	if err != nil {
+		log.Printf("encode effective config: %v", err)
+		return
+	}
+	source := "environment"
+	if cfg.File != "" {
+		source = cfg.File + " and environment"
+	}
+	log.Printf("effective config (from %s): %s", source, summary)
+}
+
+// newNotificationChannels builds the enabled participant notification
+// channels, wrapped to only log messages in dry-run mode.
+func newNotificationChannels(cfg *config.Config) ([]notification.Channel, error) {
+	var channels []notification.Channel
+	if cfg.Notification.EmailEnabled {
+		mailer, err := notification.NewSMTPMailer(notification.SMTPOptions{
+			Addr:     cfg.SMTP.Addr,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.SMTP.From,
+		})
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, mailer)
+	}
+	if cfg.SMS.Provider != "none" {
+		sms, err := notification.NewSMSSender(notification.SMSOptions{
+			Provider:  cfg.SMS.Provider,
+			URL:       cfg.SMS.URL,
+			AccountID: cfg.SMS.AccountID,
+			Token:     cfg.SMS.Token,
+			From:      cfg.SMS.From,
+			Timeout:   cfg.Notification.Timeout,
+		})
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, sms)
+	}
+	if cfg.WhatsApp.PhoneNumberID != "" {
+		whatsApp, err := notification.NewWhatsAppSender(notification.WhatsAppOptions{
+			URL:              cfg.WhatsApp.URL,
+			PhoneNumberID:    cfg.WhatsApp.PhoneNumberID,
+			Token:            cfg.WhatsApp.Token,
+			Template:         cfg.WhatsApp.Template,
+			TemplateLanguage: cfg.WhatsApp.TemplateLanguage,
+			Timeout:          cfg.Notification.Timeout,
+		})
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, whatsApp)
+	}
+	if cfg.FCM.CredentialsFile != "" {
+		push, err := notification.NewFCMSender(notification.FCMOptions{
+			CredentialsFile: cfg.FCM.CredentialsFile,
+			ProjectID:       cfg.FCM.ProjectID,
+			URL:             cfg.FCM.URL,
+			Timeout:         cfg.Notification.Timeout,
+		})
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, push)
+	}
+	if cfg.Notification.DryRun {
+		for i, channel := range channels {
+			channels[i] = notification.DryRun(channel)
+		}
+	}
+	return channels, nil
+}
+
+// newAlertNotifiers builds the chat and email channels alerts are sent to.
+func newAlertNotifiers(cfg *config.Config) ([]alerting.Notifier, error) {
+	var notifiers []alerting.Notifier
+	if cfg.Alert.SlackWebhookURL != "" {
+		slack, err := alerting.NewSlackNotifier(cfg.Alert.SlackWebhookURL, cfg.Webhook.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, slack)
+	}
+	if len(cfg.Alert.EmailTo) > 0 {
+		email, err := alerting.NewEmailNotifier(alerting.EmailOptions{
+			Addr:     cfg.SMTP.Addr,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.SMTP.From,
+			To:       cfg.Alert.EmailTo,
+		})
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, email)
+	}
+	return notifiers, nil
+}
+
+// newFRClient builds the FR Core client from the configuration.
+func newFRClient(cfg *config.Config) (frcore.Client, error) {
+	return frcore.NewHTTPClient(frClientOptions(cfg))
+}
+
+// newTenantFRClient builds the FR Core client of a tenant with settings of its
+// own; the ones it leaves empty keep their configured value.
+func newTenantFRClient(cfg *config.Config, t *domain.Tenant) (frcore.Client, error) {
+	opts := frClientOptions(cfg)
+	if t.FRCoreBaseURL != "" {
+		opts.BaseURL = t.FRCoreBaseURL
+	}
+	if t.FRCoreUploadAPIKey != "" {
+		opts.UploadAPIKey = t.FRCoreUploadAPIKey
+	}
+	if t.FRCoreRecognizeAPIKey != "" {
+		opts.RecognizeAPIKey = t.FRCoreRecognizeAPIKey
+	}
+	if t.FRCoreTenantID != "" {
+		opts.TenantID = t.FRCoreTenantID
+	}
+	return frcore.NewHTTPClient(opts)
+}
+
+func frClientOptions(cfg *config.Config) frcore.Options {
+	return frcore.Options{
+		BaseURL:         cfg.FRC.BaseURL,
+		UploadAPIKey:    cfg.FRC.UploadAPIKey,
+		RecognizeAPIKey: cfg.FRC.RecognizeAPIKey,
+		TenantID:        cfg.FRC.TenantID,
+		Timeout:         cfg.FRC.RequestTimeout,
+		HTTPClient: &http.Client{
+			Timeout:   cfg.FRC.RequestTimeout,
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+		},
+		LogLevel: frcore.LogLevel(cfg.FRC.LogLevel),
+	}
+}
+
+// newCache builds the configured lookup cache, or returns nil when caching is off.
+func newCache(cfg *config.Config) (cache.Cache, error) {
+	switch cfg.Cache.Backend {
+	case "memory":
+		return cache.NewMemory(cfg.Cache.Size, cfg.Cache.TTL), nil
+	case "redis":
+		return cache.NewRedis(cfg.Cache.RedisURL, cfg.Cache.RedisPrefix, cfg.Cache.TTL)
+	default:
+		return nil, nil
+	}
+}
+
+// newOCRReader builds the configured KTP reader, or returns nil when KTP
+// photos are filed unread.
+func newOCRReader(cfg *config.Config) (ocr.Reader, error) {
+	switch cfg.OCR.Provider {
+	case "tesseract":
+		reader, err := ocr.NewTesseractReader(cfg.OCR.TesseractPath, cfg.OCR.TesseractLanguage, cfg.OCR.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		return reader, nil
+	case "http":
+		reader, err := ocr.NewHTTPReader(ocr.Options{URL: cfg.OCR.URL, APIKey: cfg.OCR.APIKey, Timeout: cfg.OCR.Timeout})
+		if err != nil {
+			return nil, err
+		}
+		return reader, nil
+	default:
+		return nil, nil
+	}
+}
+
+// eventBroker is a publisher holding a connection that must be closed on shutdown.
+type eventBroker interface {
+	events.Publisher
+	Close() error
+}
+
+// newEventBroker connects to the configured broker, or returns nil when events stay in-process.
+func newEventBroker(cfg *config.Config) (eventBroker, error) {
+	switch cfg.Events.Broker {
+	case "kafka":
+		return events.NewKafkaPublisher(cfg.Events.KafkaBrokers, cfg.Events.KafkaTopic, cfg.Events.PublishTimeout)
+	case "nats":
+		return events.NewNATSPublisher(cfg.Events.NATSURL, cfg.Events.NATSSubjectPrefix, cfg.Events.PublishTimeout)
+	default:
+		return nil, nil
+	}
+}
+
+// newHoldRelease builds the hold release client and its payload template.
+func newHoldRelease(cfg *config.Config) (payroll.HoldReleaser, *payroll.HoldReleaseTemplate, error) {
+	template, err := payroll.LoadHoldReleaseTemplate(cfg.HoldRelease.TemplateFile, cfg.HoldRelease.ContentType)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := payroll.NewHoldReleaseClient(payroll.HoldReleaseOptions{
+		URL:         cfg.HoldRelease.URL,
+		Method:      cfg.HoldRelease.Method,
+		AuthType:    cfg.HoldRelease.AuthType,
+		Username:    cfg.HoldRelease.Username,
+		Password:    cfg.HoldRelease.Password,
+		Token:       cfg.HoldRelease.Token,
+		ContentType: cfg.HoldRelease.ContentType,
+		AckField:    cfg.HoldRelease.AckField,
+		Timeout:     cfg.HoldRelease.Timeout,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, template, nil
+}
+
+// newCertificatePDFService loads the certificate layout; the QR code is left
+// out while receipts are disabled.
+func newCertificatePDFService(cfg *config.Config, certificates repository.LifeCertificateRepository, participants repository.ParticipantRepository, members repository.MemberRepository, tenants repository.TenantRepository, blobs storage.BlobStore, receipts *service.ReceiptService, signer *signing.Key, jobs *service.JobService) (*service.CertificatePDFService, error) {
+	// The zone was validated when the config was loaded.
+	location, _ := time.LoadLocation(cfg.CertificatePDF.Timezone)
+	template, err := document.LoadCertificateTemplate(cfg.CertificatePDF.Template, location)
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.Receipt.Enabled {
+		receipts = nil
+	}
+	return service.NewCertificatePDFService(certificates, participants, members, tenants, blobs, template, receipts, signer, jobs, cfg.CertificatePDF.Issuer, cfg.Verification.ValidityMonths), nil
+}
+
+func newMonthlyReportService(cfg *config.Config, stats repository.StatsRepository, reports repository.MonthlyReportRepository, blobs storage.BlobStore, signer *signing.Key) (*service.MonthlyReportService, error) {
+	// The zone was validated when the config was loaded.
+	location, _ := time.LoadLocation(cfg.MonthlyReport.Timezone)
+	template, err := document.LoadReportTemplate(cfg.MonthlyReport.Template, location)
+	if err != nil {
+		return nil, err
+	}
+	return service.NewMonthlyReportService(stats, reports, blobs, template, signer, cfg.CertificatePDF.Issuer, cfg.MonthlyReport.Format, location), nil
+}
+
+// newSigningKey loads the certificate signing key; nil when signing is disabled.
+func newSigningKey(ctx context.Context, cfg *config.Config) (*signing.Key, error) {
+	switch cfg.Signing.Backend {
+	case "pkcs12":
+		return signing.LoadPKCS12(cfg.Signing.PKCS12File, cfg.Signing.PKCS12Password)
+	case "kms":
+		return signing.NewKMSKey(ctx, signing.KMSOptions{
+			KeyID:     cfg.Signing.KMSKeyID,
+			Region:    cfg.Signing.KMSRegion,
+			ChainFile: cfg.Signing.KMSChainFile,
+		})
+	default:
+		return nil, nil
+	}
+}
+
+// newPayrollUploader builds the SFTP uploader for payroll files.
+func newPayrollUploader(cfg *config.Config) (payroll.Uploader, error) {
+	return payroll.NewSFTPUploader(payroll.SFTPOptions{
+		Addr:          cfg.PayrollFile.SFTPHost,
+		User:          cfg.PayrollFile.SFTPUser,
+		KeyFile:       cfg.PayrollFile.SFTPKeyFile,
+		KeyPassphrase: cfg.PayrollFile.SFTPKeyPassphrase,
+		HostKey:       cfg.PayrollFile.SFTPHostKey,
+		Dir:           cfg.PayrollFile.SFTPPath,
+		Timeout:       cfg.PayrollFile.Timeout,
+	})
+}
+
+// partnerAuthenticator resolves partner API keys for the partner route group.
+func partnerAuthenticator(partners *service.PartnerService) middleware.PartnerAuthenticator {
+	return func(ctx context.Context, secret string) (*middleware.Partner, error) {
+		key, err := partners.Authenticate(ctx, secret)
+		if err != nil || key == nil {
+			return nil, err
+		}
+		partner := &middleware.Partner{KeyID: key.ID, Name: key.Name, Scopes: service.PartnerScopes(key), Tenant: key.TenantID}
+		if key.Fund != nil {
+			partner.Fund = *key.Fund
+		}
+		return partner, nil
+	}
+}
+
+// tenantLookup resolves the tenants requests name.
+func tenantLookup(tenants *service.TenantService) middleware.TenantLookup {
+	return func(ctx context.Context, id string) (*middleware.Tenant, error) {
+		t, err := tenants.Get(ctx, id)
+		if errors.Is(err, service.ErrTenantNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &middleware.Tenant{ID: t.ID, Disabled: t.DisabledAt != nil}, nil
+	}
+}
+
+// kioskAuthenticator resolves kiosk device keys for the kiosk route group.
+func kioskAuthenticator(kiosks *service.KioskService) middleware.KioskAuthenticator {
+	return func(ctx context.Context, secret string) (*middleware.Kiosk, error) {
+		kiosk, err := kiosks.Authenticate(ctx, secret)
+		if err != nil || kiosk == nil {
+			return nil, err
+		}
+		return &middleware.Kiosk{ID: kiosk.ID, Name: kiosk.Name, BranchID: kiosk.BranchID}, nil
+	}
+}
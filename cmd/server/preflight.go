@@ -0,0 +1,341 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gorm.io/gorm"
+
+	"life-certificates/internal/antivirus"
+	"life-certificates/internal/cache"
+	"life-certificates/internal/civilregistry"
+	"life-certificates/internal/config"
+	"life-certificates/internal/database"
+	"life-certificates/internal/decision"
+	"life-certificates/internal/i18n"
+	"life-certificates/internal/payroll"
+	"life-certificates/internal/storage"
+)
+
+const preflightTimeout = 10 * time.Second
+
+// Outcomes of a preflight check.
+const (
+	preflightOK      = "ok"
+	preflightWarn    = "warn"
+	preflightFail    = "FAIL"
+	preflightSkipped = "skipped"
+)
+
+// preflightResult is one line of the -validate-config report.
+type preflightResult struct {
+	name    string
+	outcome string
+	detail  string
+	elapsed time.Duration
+}
+
+// preflightWarning reports a problem the server copes with at startup.
+type preflightWarning string
+
+func (w preflightWarning) Error() string { return string(w) }
+
+// runPreflight loads the configuration and checks every dependency the server
+// needs, writing a report to out. It returns false when any check failed, so
+// a broken deployment fails in CI/CD instead of at the first request.
+func runPreflight(configFile string, dryRun bool, out io.Writer) bool {
+	var results []preflightResult
+	record := func(name string, check func(ctx context.Context) (string, error)) bool {
+		ctx, cancel := context.WithTimeout(context.Background(), preflightTimeout)
+		defer cancel()
+
+		start := time.Now()
+		detail, err := check(ctx)
+		result := preflightResult{name: name, outcome: preflightOK, detail: detail, elapsed: time.Since(start)}
+		var warning preflightWarning
+		switch {
+		case errors.As(err, &warning):
+			result.outcome, result.detail = preflightWarn, warning.Error()
+		case err != nil:
+			result.outcome, result.detail = preflightFail, err.Error()
+		}
+		results = append(results, result)
+		return result.outcome != preflightFail
+	}
+	skip := func(name, reason string) {
+		results = append(results, preflightResult{name: name, outcome: preflightSkipped, detail: reason})
+	}
+
+	var cfg *config.Config
+	configured := record("config", func(context.Context) (string, error) {
+		var err error
+		if cfg, err = config.Load(configFile); err != nil {
+			return "", err
+		}
+		if cfg.File != "" {
+			return "loaded from " + cfg.File + " and environment", nil
+		}
+		return "loaded from environment", nil
+	})
+	if !configured {
+		for _, name := range []string{"database", "migrations", "frcore", "storage", "cache", "antivirus", "ocr", "event broker", "payment push", "hold release", "payroll file", "civil registry", "signing key", "decision rules", "message catalogs", "alerts"} {
+			skip(name, "configuration did not load")
+		}
+		return writePreflightReport(out, results, dryRun)
+	}
+
+	var db *gorm.DB
+	connected := record("database", func(ctx context.Context) (string, error) {
+		var err error
+		if db, err = database.New(cfg.Database.DSN); err != nil {
+			return "", err
+		}
+		if err := database.Ping(ctx, db); err != nil {
+			return "", fmt.Errorf("ping: %w", err)
+		}
+		return "connected", nil
+	})
+	if connected {
+		defer func() {
+			if sqlDB, err := db.DB(); err == nil {
+				_ = sqlDB.Close()
+			}
+		}()
+		record("migrations", func(ctx context.Context) (string, error) {
+			if dryRun {
+				if err := database.RehearseMigrate(ctx, db); err != nil {
+					return "", err
+				}
+				return "applied cleanly in a rolled-back transaction", nil
+			}
+			if err := database.CheckSchema(ctx, db); err != nil {
+				return "", preflightWarning(err.Error() + " (created on startup)")
+			}
+			return "schema up to date", nil
+		})
+	} else {
+		skip("migrations", "database unreachable")
+	}
+
+	record("frcore", func(ctx context.Context) (string, error) {
+		client, err := newFRClient(cfg)
+		if err != nil {
+			return "", err
+		}
+		if err := client.Ping(ctx); err != nil {
+			return "", err
+		}
+		return "reachable at " + cfg.FRC.BaseURL, nil
+	})
+
+	record("storage", func(ctx context.Context) (string, error) {
+		store, err := storage.NewLocalStore(cfg.Storage.Dir)
+		if err != nil {
+			return "", err
+		}
+		const probe = ".preflight"
+		if err := store.Put(ctx, probe, []byte("ok")); err != nil {
+			return "", fmt.Errorf("write probe: %w", err)
+		}
+		if err := store.Delete(ctx, probe); err != nil {
+			return "", fmt.Errorf("delete probe: %w", err)
+		}
+		return "writable at " + cfg.Storage.Dir, nil
+	})
+
+	if cfg.Cache.Backend != "redis" {
+		skip("cache", "CACHE_BACKEND is "+cfg.Cache.Backend)
+	} else {
+		record("cache", func(ctx context.Context) (string, error) {
+			redis, err := cache.NewRedis(cfg.Cache.RedisURL, cfg.Cache.RedisPrefix, cfg.Cache.TTL)
+			if err != nil {
+				return "", err
+			}
+			defer redis.Close()
+			if err := redis.Ping(ctx); err != nil {
+				return "", err
+			}
+			return "redis reachable", nil
+		})
+	}
+
+	if cfg.Antivirus.ClamdAddr == "" {
+		skip("antivirus", "ANTIVIRUS_CLAMD_ADDR not set")
+	} else {
+		record("antivirus", func(ctx context.Context) (string, error) {
+			scanner, err := antivirus.NewClamdScanner(cfg.Antivirus.ClamdAddr, cfg.Antivirus.Timeout)
+			if err != nil {
+				return "", err
+			}
+			if err := scanner.Ping(ctx); err != nil {
+				return "", err
+			}
+			return "clamd reachable at " + cfg.Antivirus.ClamdAddr, nil
+		})
+	}
+
+	if cfg.OCR.Provider == "none" {
+		skip("ocr", "KTP photos are filed unread")
+	} else {
+		record("ocr", func(context.Context) (string, error) {
+			if _, err := newOCRReader(cfg); err != nil {
+				return "", err
+			}
+			if cfg.OCR.Provider == "tesseract" {
+				return "tesseract found at " + cfg.OCR.TesseractPath, nil
+			}
+			return "client configured for " + cfg.OCR.URL, nil
+		})
+	}
+
+	if cfg.Events.Broker == "none" {
+		skip("event broker", "events stay in-process")
+	} else {
+		record("event broker", func(context.Context) (string, error) {
+			broker, err := newEventBroker(cfg)
+			if err != nil {
+				return "", err
+			}
+			if broker != nil {
+				_ = broker.Close()
+			}
+			return "connected to " + cfg.Events.Broker, nil
+		})
+	}
+
+	if cfg.PaymentPush.URL == "" {
+		skip("payment push", "PAYMENT_PUSH_URL not set")
+	} else {
+		record("payment push", func(context.Context) (string, error) {
+			if _, err := payroll.NewHTTPClient(payroll.Options{
+				URL:      cfg.PaymentPush.URL,
+				AuthType: cfg.PaymentPush.AuthType,
+				Username: cfg.PaymentPush.Username,
+				Password: cfg.PaymentPush.Password,
+				Token:    cfg.PaymentPush.Token,
+				FieldMap: cfg.PaymentPush.FieldMap,
+				Timeout:  cfg.PaymentPush.Timeout,
+			}); err != nil {
+				return "", err
+			}
+			return "client configured for " + cfg.PaymentPush.URL, nil
+		})
+	}
+
+	if cfg.HoldRelease.URL == "" {
+		skip("hold release", "HOLD_RELEASE_URL not set")
+	} else {
+		record("hold release", func(context.Context) (string, error) {
+			if _, _, err := newHoldRelease(cfg); err != nil {
+				return "", err
+			}
+			return "client configured for " + cfg.HoldRelease.URL, nil
+		})
+	}
+
+	if cfg.PayrollFile.SFTPHost == "" {
+		skip("payroll file", "PAYROLL_FILE_SFTP_HOST not set")
+	} else {
+		record("payroll file", func(context.Context) (string, error) {
+			if _, err := newPayrollUploader(cfg); err != nil {
+				return "", err
+			}
+			return "SFTP uploads to " + cfg.PayrollFile.SFTPHost, nil
+		})
+	}
+
+	if cfg.CivilRegistry.URL == "" {
+		skip("civil registry", "CIVIL_REGISTRY_URL not set")
+	} else {
+		record("civil registry", func(context.Context) (string, error) {
+			if _, err := civilregistry.NewHTTPClient(civilregistry.Options{
+				BaseURL:  cfg.CivilRegistry.URL,
+				Username: cfg.CivilRegistry.Username,
+				Password: cfg.CivilRegistry.Password,
+				Timeout:  cfg.CivilRegistry.Timeout,
+			}); err != nil {
+				return "", err
+			}
+			return "client configured for " + cfg.CivilRegistry.URL, nil
+		})
+	}
+
+	if cfg.Signing.Backend == "none" {
+		skip("signing key", "SIGNING_BACKEND is none")
+	} else {
+		record("signing key", func(ctx context.Context) (string, error) {
+			key, err := newSigningKey(ctx, cfg)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%s key %s from %s", key.Algorithm(), key.ID(), cfg.Signing.Backend), nil
+		})
+	}
+
+	record("decision rules", func(context.Context) (string, error) {
+		if cfg.Verification.RulesFile == "" {
+			return "built-in default rules", nil
+		}
+		rules, err := decision.Load(cfg.Verification.RulesFile)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("default and %d profile rule set(s)", len(rules.Profiles)), nil
+	})
+
+	record("message catalogs", func(context.Context) (string, error) {
+		catalog, err := i18n.Load(cfg.I18n.CatalogDir, cfg.I18n.DefaultLanguage)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("languages %s, default %s", strings.Join(catalog.Languages(), ", "), catalog.DefaultLanguage()), nil
+	})
+
+	record("alerts", func(context.Context) (string, error) {
+		notifiers, err := newAlertNotifiers(cfg)
+		if err != nil {
+			return "", err
+		}
+		if len(notifiers) == 0 {
+			return "", preflightWarning("no Slack webhook or email recipients configured")
+		}
+		return fmt.Sprintf("%d notifier(s) configured", len(notifiers)), nil
+	})
+
+	return writePreflightReport(out, results, dryRun)
+}
+
+// writePreflightReport prints one aligned line per check and a verdict, returning whether every check passed.
+func writePreflightReport(out io.Writer, results []preflightResult, dryRun bool) bool {
+	mode := "validate-config"
+	if dryRun {
+		mode = "dry-run"
+	}
+	fmt.Fprintf(out, "life-certificates %s\n\n", mode)
+
+	passed := true
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, result := range results {
+		elapsed := ""
+		if result.outcome != preflightSkipped {
+			elapsed = result.elapsed.Round(time.Millisecond).String()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", result.name, result.outcome, elapsed, result.detail)
+		if result.outcome == preflightFail {
+			passed = false
+		}
+	}
+	_ = tw.Flush()
+
+	if passed {
+		fmt.Fprintln(out, "\nall checks passed")
+	} else {
+		fmt.Fprintln(out, "\none or more checks failed")
+	}
+	return passed
+}
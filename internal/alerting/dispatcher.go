@@ -0,0 +1,39 @@
+package alerting
+
+import (
+	"context"
+	"log"
+
+	"life-certificates/internal/events"
+)
+
+// Dispatcher is an outbox sink forwarding alert.triggered events to the notifiers.
+type Dispatcher struct {
+	notifiers []Notifier
+}
+
+// NewDispatcher wires the channels alerts are sent to.
+func NewDispatcher(notifiers ...Notifier) *Dispatcher {
+	return &Dispatcher{notifiers: notifiers}
+}
+
+// Publish sends alert events to every notifier. Delivery is best effort: a
+// failing channel is logged rather than retried, so the other outbox sinks do
+// not receive the event twice.
+func (d *Dispatcher) Publish(ctx context.Context, event events.Event) error {
+	if event.Type != events.TypeAlertTriggered {
+		return nil
+	}
+	alert := Alert{FiredAt: event.OccurredAt}
+	alert.Kind, _ = event.Data["kind"].(string)
+	alert.Severity, _ = event.Data["severity"].(string)
+	alert.Message, _ = event.Data["message"].(string)
+	alert.Details, _ = event.Data["details"].(map[string]interface{})
+
+	for _, notifier := range d.notifiers {
+		if err := notifier.Notify(ctx, alert); err != nil {
+			log.Printf("[alert] notify %s: %v", alert.Kind, err)
+		}
+	}
+	return nil
+}
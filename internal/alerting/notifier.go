@@ -0,0 +1,143 @@
+// Package alerting delivers operational alerts to chat and email channels.
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const responseBodyLimit = 1024
+
+// Alert is a tripped operational threshold.
+type Alert struct {
+	// Kind identifies the condition, e.g. frcore_error_rate.
+	Kind     string
+	Severity string
+	Message  string
+	Details  map[string]interface{}
+	FiredAt  time.Time
+}
+
+// Notifier sends an alert to one channel.
+type Notifier interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// SlackNotifier posts alerts to a Slack incoming webhook.
+type SlackNotifier struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewSlackNotifier validates the incoming webhook URL.
+func NewSlackNotifier(webhookURL string, timeout time.Duration) (*SlackNotifier, error) {
+	parsed, err := url.Parse(webhookURL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, fmt.Errorf("slack webhook URL must be an absolute http or https URL")
+	}
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	return &SlackNotifier{url: webhookURL, httpClient: &http.Client{Timeout: timeout}}, nil
+}
+
+// Notify posts the alert as a Slack message.
+func (n *SlackNotifier) Notify(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(map[string]string{"text": fmt.Sprintf("*[%s] %s*\n%s", strings.ToUpper(alert.Severity), alert.Kind, Text(alert))})
+	if err != nil {
+		return fmt.Errorf("encode slack message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, responseBodyLimit))
+		return fmt.Errorf("slack returned %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// EmailOptions configures the SMTP relay used for alert emails.
+type EmailOptions struct {
+	// Addr is the SMTP relay as host:port.
+	Addr     string
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// EmailNotifier mails alerts through an SMTP relay.
+type EmailNotifier struct {
+	opts EmailOptions
+}
+
+// NewEmailNotifier validates the SMTP settings.
+func NewEmailNotifier(opts EmailOptions) (*EmailNotifier, error) {
+	if _, _, err := net.SplitHostPort(opts.Addr); err != nil {
+		return nil, fmt.Errorf("SMTP address must be host:port: %w", err)
+	}
+	if opts.From == "" || len(opts.To) == 0 {
+		return nil, fmt.Errorf("email sender and recipients are required")
+	}
+	return &EmailNotifier{opts: opts}, nil
+}
+
+// Notify sends the alert as a plain text email.
+func (n *EmailNotifier) Notify(_ context.Context, alert Alert) error {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", n.opts.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.opts.To, ", "))
+	fmt.Fprintf(&msg, "Subject: [%s] Life Certificates alert: %s\r\n", strings.ToUpper(alert.Severity), alert.Kind)
+	fmt.Fprintf(&msg, "Date: %s\r\n", alert.FiredAt.Format(time.RFC1123Z))
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(Text(alert), "\n", "\r\n"))
+	msg.WriteString("\r\n")
+
+	var auth smtp.Auth
+	if n.opts.Username != "" {
+		host, _, _ := net.SplitHostPort(n.opts.Addr)
+		auth = smtp.PlainAuth("", n.opts.Username, n.opts.Password, host)
+	}
+	if err := smtp.SendMail(n.opts.Addr, auth, n.opts.From, n.opts.To, msg.Bytes()); err != nil {
+		return fmt.Errorf("send alert email: %w", err)
+	}
+	return nil
+}
+
+// Text renders the alert message followed by its details, one per line.
+func Text(alert Alert) string {
+	keys := make([]string, 0, len(alert.Details))
+	for key := range alert.Details {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(alert.Message)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "\n%s: %v", key, alert.Details[key])
+	}
+	return b.String()
+}
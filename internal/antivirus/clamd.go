@@ -0,0 +1,130 @@
+// Package antivirus scans uploads for malware before they are stored.
+package antivirus
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// chunkSize is the largest INSTREAM chunk sent to clamd.
+const chunkSize = 64 << 10
+
+// Result is the verdict on one upload.
+type Result struct {
+	Infected bool
+	// Signature names the malware found, empty when the upload is clean.
+	Signature string
+}
+
+// Scanner inspects uploads for malware.
+type Scanner interface {
+	Scan(ctx context.Context, data []byte) (Result, error)
+	Ping(ctx context.Context) error
+}
+
+// ClamdScanner talks to a clamd daemon over TCP or a unix socket.
+type ClamdScanner struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+// NewClamdScanner connects to addr, either "host:port" or "unix:/path/to/clamd.sock".
+func NewClamdScanner(addr string, timeout time.Duration) (*ClamdScanner, error) {
+	addr = strings.TrimSpace(addr)
+	if addr == "" {
+		return nil, fmt.Errorf("clamd address is required")
+	}
+	scanner := &ClamdScanner{network: "tcp", address: addr, timeout: timeout}
+	if path, ok := strings.CutPrefix(addr, "unix:"); ok {
+		scanner.network, scanner.address = "unix", path
+	}
+	return scanner, nil
+}
+
+// Scan streams data to clamd with the INSTREAM command.
+func (c *ClamdScanner) Scan(ctx context.Context, data []byte) (Result, error) {
+	reply, err := c.command(ctx, "INSTREAM", func(conn net.Conn) error {
+		size := make([]byte, 4)
+		for offset := 0; offset < len(data); offset += chunkSize {
+			chunk := data[offset:min(offset+chunkSize, len(data))]
+			binary.BigEndian.PutUint32(size, uint32(len(chunk)))
+			if _, err := conn.Write(size); err != nil {
+				return err
+			}
+			if _, err := conn.Write(chunk); err != nil {
+				return err
+			}
+		}
+		// A zero-length chunk ends the stream.
+		binary.BigEndian.PutUint32(size, 0)
+		_, err := conn.Write(size)
+		return err
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	// Replies are "stream: OK", "stream: <signature> FOUND" or "<reason> ERROR".
+	reply = strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case reply == "OK":
+		return Result{}, nil
+	case strings.HasSuffix(reply, " FOUND"):
+		return Result{Infected: true, Signature: strings.TrimSuffix(reply, " FOUND")}, nil
+	}
+	return Result{}, fmt.Errorf("clamd scan: %s", reply)
+}
+
+// Ping checks clamd is answering.
+func (c *ClamdScanner) Ping(ctx context.Context) error {
+	reply, err := c.command(ctx, "PING", nil)
+	if err != nil {
+		return err
+	}
+	if reply != "PONG" {
+		return fmt.Errorf("clamd ping: unexpected reply %q", reply)
+	}
+	return nil
+}
+
+// command sends a NUL-terminated clamd command, lets send write any payload
+// and reads the single NUL-terminated reply.
+func (c *ClamdScanner) command(ctx context.Context, name string, send func(net.Conn) error) (string, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, c.network, c.address)
+	if err != nil {
+		return "", fmt.Errorf("connect to clamd: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if _, err := conn.Write([]byte("z" + name + "\x00")); err != nil {
+		return "", fmt.Errorf("clamd %s: %w", name, err)
+	}
+	if send != nil {
+		if err := send(conn); err != nil {
+			return "", fmt.Errorf("clamd %s: %w", name, err)
+		}
+	}
+	reply, err := bufio.NewReader(conn).ReadBytes(0)
+	if err != nil {
+		return "", fmt.Errorf("clamd %s: read reply: %w", name, err)
+	}
+	return string(bytes.TrimRight(reply, "\x00\n")), nil
+}
+
+var _ Scanner = (*ClamdScanner)(nil)
@@ -0,0 +1,58 @@
+// Package cache keeps serialized lookups close to the service, either in
+// process or in Redis, so hot reads skip the database.
+package cache
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+)
+
+// Cache stores values under string keys for a fixed time to live.
+type Cache interface {
+	// Get reports whether key was found.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte) error
+	Delete(ctx context.Context, keys ...string) error
+	// DeletePrefix drops every key starting with prefix.
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// Memory is an in-process LRU cache. Each replica holds its own copy, so
+// writes on one replica do not invalidate the others.
+type Memory struct {
+	entries *expirable.LRU[string, []byte]
+}
+
+// NewMemory holds at most size entries, each for ttl.
+func NewMemory(size int, ttl time.Duration) *Memory {
+	return &Memory{entries: expirable.NewLRU[string, []byte](size, nil, ttl)}
+}
+
+func (m *Memory) Get(_ context.Context, key string) ([]byte, bool, error) {
+	value, ok := m.entries.Get(key)
+	return value, ok, nil
+}
+
+func (m *Memory) Set(_ context.Context, key string, value []byte) error {
+	m.entries.Add(key, value)
+	return nil
+}
+
+func (m *Memory) Delete(_ context.Context, keys ...string) error {
+	for _, key := range keys {
+		m.entries.Remove(key)
+	}
+	return nil
+}
+
+func (m *Memory) DeletePrefix(_ context.Context, prefix string) error {
+	for _, key := range m.entries.Keys() {
+		if strings.HasPrefix(key, prefix) {
+			m.entries.Remove(key)
+		}
+	}
+	return nil
+}
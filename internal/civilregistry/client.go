@@ -0,0 +1,116 @@
+// Package civilregistry looks up death records in the civil registry (Dukcapil).
+package civilregistry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const responseBodyLimit = 1024
+
+// Record is the registry's answer for one NIK.
+type Record struct {
+	NIK      string
+	Deceased bool
+	// DateOfDeath and Reference, the death certificate number, are set when
+	// the registry reports them.
+	DateOfDeath *time.Time
+	Reference   string
+}
+
+// Client queries the civil registry.
+type Client interface {
+	// DeathStatus reports whether the registry records a death for nik. An
+	// NIK the registry does not know is reported as not deceased.
+	DeathStatus(ctx context.Context, nik string) (*Record, error)
+}
+
+// Options configures the civil registry HTTP client.
+type Options struct {
+	BaseURL  string
+	Username string
+	Password string
+	Timeout  time.Duration
+}
+
+type apiClient struct {
+	endpoint   string
+	opts       Options
+	httpClient *http.Client
+}
+
+// NewHTTPClient constructs a REST client for the civil registry.
+func NewHTTPClient(opts Options) (Client, error) {
+	parsed, err := url.Parse(opts.BaseURL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, fmt.Errorf("civil registry URL must be an absolute http or https URL")
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	return &apiClient{
+		endpoint:   strings.TrimRight(opts.BaseURL, "/") + "/death-status",
+		opts:       opts,
+		httpClient: &http.Client{Timeout: opts.Timeout},
+	}, nil
+}
+
+// deathStatusResponse is the registry's JSON answer; date_of_death is YYYY-MM-DD.
+type deathStatusResponse struct {
+	NIK         string `json:"nik"`
+	Deceased    bool   `json:"deceased"`
+	DateOfDeath string `json:"date_of_death"`
+	Reference   string `json:"reference"`
+}
+
+func (c *apiClient) DeathStatus(ctx context.Context, nik string) (*Record, error) {
+	body, err := json.Marshal(map[string]string{"nik": nik})
+	if err != nil {
+		return nil, fmt.Errorf("encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if c.opts.Username != "" || c.opts.Password != "" {
+		req.SetBasicAuth(c.opts.Username, c.opts.Password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return &Record{NIK: nik}, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, responseBodyLimit))
+		return nil, fmt.Errorf("civil registry returned %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+
+	var payload deathStatusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	record := &Record{NIK: nik, Deceased: payload.Deceased, Reference: strings.TrimSpace(payload.Reference)}
+	if raw := strings.TrimSpace(payload.DateOfDeath); raw != "" {
+		date, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			return nil, fmt.Errorf("decode date_of_death %q: %w", raw, err)
+		}
+		record.DateOfDeath = &date
+	}
+	return record, nil
+}
@@ -0,0 +1,225 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PrefixVariable names the environment variable holding the prefix applied to
+// every other variable; it is always read unprefixed.
+const PrefixVariable = "ENV_PREFIX"
+
+// Decoder is implemented by setting types that parse their own raw value.
+type Decoder interface {
+	Decode(raw string) error
+}
+
+var (
+	decoderType  = reflect.TypeOf((*Decoder)(nil)).Elem()
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// durationUnits are the values accepted by the unit tag.
+var durationUnits = map[string]time.Duration{
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+}
+
+// setting describes one tagged Config field.
+type setting struct {
+	key      string
+	noPrefix bool
+	fallback string
+	required bool
+	oneOf    []string
+	min, max string
+	unit     time.Duration
+}
+
+func parseSetting(field reflect.StructField) (setting, error) {
+	tag := strings.Split(field.Tag.Get("env"), ",")
+	s := setting{
+		key:      tag[0],
+		fallback: field.Tag.Get("default"),
+		required: field.Tag.Get("required") == "true",
+		min:      field.Tag.Get("min"),
+		max:      field.Tag.Get("max"),
+	}
+	for _, option := range tag[1:] {
+		switch option {
+		case "noprefix":
+			s.noPrefix = true
+		default:
+			return s, fmt.Errorf("config field %s: unknown env option %q", field.Name, option)
+		}
+	}
+	if oneOf := field.Tag.Get("oneof"); oneOf != "" {
+		s.oneOf = strings.Split(oneOf, ",")
+	}
+	if field.Type == durationType {
+		unit, ok := durationUnits[field.Tag.Get("unit")]
+		if !ok {
+			return s, fmt.Errorf("config field %s: unit must be one of ms, s, m, h", field.Name)
+		}
+		s.unit = unit
+	}
+	return s, nil
+}
+
+// decode fills every env-tagged field of target, a pointer to a struct,
+// descending into nested structs.
+func (s *source) decode(target interface{}) error {
+	return s.decodeStruct(reflect.ValueOf(target).Elem())
+}
+
+func (s *source) decodeStruct(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, value := t.Field(i), v.Field(i)
+		if _, tagged := field.Tag.Lookup("env"); !tagged {
+			if value.Kind() == reflect.Struct && field.Type != durationType {
+				if err := s.decodeStruct(value); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		spec, err := parseSetting(field)
+		if err != nil {
+			return err
+		}
+		name := s.variable(spec.key, spec.noPrefix)
+		raw := strings.TrimSpace(s.lookup(spec.key, name, spec.fallback))
+		if raw == "" && spec.required {
+			return fmt.Errorf("%s is required", name)
+		}
+		if len(spec.oneOf) > 0 {
+			raw = strings.ToLower(raw)
+			if !contains(spec.oneOf, raw) {
+				return fmt.Errorf("%s must be one of %s", name, strings.Join(spec.oneOf, ", "))
+			}
+		}
+		number, err := setValue(value, raw, spec.unit)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+		if err := checkBounds(number, spec.min, spec.max); err != nil {
+			return fmt.Errorf("%s %w", name, err)
+		}
+	}
+	return nil
+}
+
+// setValue parses raw into value, returning the number it holds, in the
+// field's unit for durations, for bounds checking.
+func setValue(value reflect.Value, raw string, unit time.Duration) (float64, error) {
+	if value.Addr().Type().Implements(decoderType) {
+		return 0, value.Addr().Interface().(Decoder).Decode(raw)
+	}
+
+	if value.Type() == durationType {
+		if raw == "" {
+			return 0, nil
+		}
+		if whole, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			value.SetInt(whole * int64(unit))
+			return float64(whole), nil
+		}
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return 0, err
+		}
+		value.SetInt(int64(d))
+		return float64(d) / float64(unit), nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		if raw == "" {
+			return 0, nil
+		}
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return 0, err
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		if raw == "" {
+			return 0, nil
+		}
+		n, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+		if err != nil {
+			return 0, err
+		}
+		value.SetInt(n)
+		return float64(n), nil
+	case reflect.Float64:
+		if raw == "" {
+			return 0, nil
+		}
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return 0, err
+		}
+		value.SetFloat(f)
+		return f, nil
+	case reflect.Slice:
+		if value.Type().Elem().Kind() != reflect.String {
+			return 0, fmt.Errorf("unsupported setting type %s", value.Type())
+		}
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		value.Set(reflect.ValueOf(items))
+	default:
+		return 0, fmt.Errorf("unsupported setting type %s", value.Type())
+	}
+	return 0, nil
+}
+
+// checkBounds enforces the min and max tags.
+func checkBounds(number float64, min, max string) error {
+	switch {
+	case min != "" && max != "":
+		lo, _ := strconv.ParseFloat(min, 64)
+		hi, _ := strconv.ParseFloat(max, 64)
+		if number < lo || number > hi {
+			return fmt.Errorf("must be between %s and %s", min, max)
+		}
+	case min == "1":
+		if number < 1 {
+			return fmt.Errorf("must be positive")
+		}
+	case min != "":
+		lo, _ := strconv.ParseFloat(min, 64)
+		if number < lo {
+			return fmt.Errorf("must be at least %s", min)
+		}
+	case max != "":
+		hi, _ := strconv.ParseFloat(max, 64)
+		if number > hi {
+			return fmt.Errorf("must be at most %s", max)
+		}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
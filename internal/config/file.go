@@ -0,0 +1,128 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile is read when present and no other config file is named.
+const DefaultFile = "config.yaml"
+
+// source resolves settings from the environment, then the config file, then defaults.
+type source struct {
+	path string
+	// prefix is prepended to environment variable names, never to file keys.
+	prefix string
+	file   map[string]string
+	used   map[string]bool
+}
+
+// newSource reads the optional config file. An empty path falls back to
+// DefaultFile, which may be missing; an explicitly named file must exist.
+func newSource(path string) (*source, error) {
+	src := &source{prefix: os.Getenv(PrefixVariable), file: map[string]string{}, used: map[string]bool{}}
+	explicit := path != ""
+	if !explicit {
+		path = DefaultFile
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && os.IsNotExist(err) {
+			return src, nil
+		}
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+
+	var tree map[string]interface{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(raw, &tree)
+	case ".toml":
+		err = toml.Unmarshal(raw, &tree)
+	default:
+		return nil, fmt.Errorf("config file %s: unsupported format %q, use .yaml, .yml or .toml", path, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	if err := flatten(tree, "", src.file); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	src.path = path
+	return src, nil
+}
+
+// variable is the environment variable read for key.
+func (s *source) variable(key string, noPrefix bool) string {
+	if noPrefix {
+		return key
+	}
+	return s.prefix + key
+}
+
+// name is the prefixed variable name for key, as shown in error messages.
+func (s *source) name(key string) string {
+	return s.variable(key, false)
+}
+
+// lookup returns the environment variable name, else the file setting key, else fallback.
+func (s *source) lookup(key, name, fallback string) string {
+	s.used[key] = true
+	if value, ok := os.LookupEnv(name); ok {
+		return value
+	}
+	if value, ok := s.file[key]; ok {
+		return value
+	}
+	return fallback
+}
+
+// unknown lists file settings that no configuration option reads, usually typos.
+func (s *source) unknown() []string {
+	var keys []string
+	for key := range s.file {
+		if !s.used[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// flatten maps nested keys to environment variable names: frcore.base_url
+// becomes FRCORE_BASE_URL. Lists become comma separated values.
+func flatten(tree map[string]interface{}, prefix string, out map[string]string) error {
+	for key, value := range tree {
+		name := strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if err := flatten(v, name, out); err != nil {
+				return err
+			}
+		case []interface{}:
+			items := make([]string, len(v))
+			for i, item := range v {
+				if _, nested := item.(map[string]interface{}); nested {
+					return fmt.Errorf("%s: lists may only hold plain values", name)
+				}
+				items[i] = fmt.Sprint(item)
+			}
+			out[name] = strings.Join(items, ",")
+		case nil:
+			out[name] = ""
+		default:
+			out[name] = fmt.Sprint(v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,354 @@
+package config
+
+import (
+	"net/url"
+	"sort"
+)
+
+const redacted = "[REDACTED]"
+
+// Summary describes the active configuration for diagnostics, with secrets redacted.
+func (c *Config) Summary() map[string]interface{} {
+	users := make([]string, 0, len(c.Auth.Users)+1)
+	users = append(users, c.Auth.Username+":admin")
+	for _, user := range c.Auth.Users {
+		entry := user.Username + ":" + user.Role
+		if user.Tenant != "" {
+			entry += ":" + user.Tenant
+		}
+		users = append(users, entry)
+	}
+
+	fieldMap := make([]string, 0, len(c.PaymentPush.FieldMap))
+	for source, target := range c.PaymentPush.FieldMap {
+		fieldMap = append(fieldMap, source+"="+target)
+	}
+	sort.Strings(fieldMap)
+
+	return map[string]interface{}{
+		"file": c.File,
+		"http": map[string]interface{}{
+			"host": c.HTTP.Host,
+			"port": c.HTTP.Port,
+		},
+		"grpc": map[string]interface{}{
+			"host": c.GRPC.Host,
+			"port": c.GRPC.Port,
+		},
+		"database": map[string]interface{}{
+			"dsn": redactDSN(c.Database.DSN),
+		},
+		"auth": map[string]interface{}{
+			"users": users,
+		},
+		"tenant": map[string]interface{}{
+			"base_domain": c.Tenant.BaseDomain,
+		},
+		"frcore": map[string]interface{}{
+			"base_url":                 c.FRC.BaseURL,
+			"upload_api_key":           redactSecret(c.FRC.UploadAPIKey),
+			"recognize_api_key":        redactSecret(c.FRC.RecognizeAPIKey),
+			"tenant_id":                c.FRC.TenantID,
+			"timeout":                  c.FRC.RequestTimeout.String(),
+			"reconcile_schedule":       c.FRC.ReconcileSchedule,
+			"reconcile_delete_orphans": c.FRC.ReconcileDelete,
+			"log_level":                c.FRC.LogLevel,
+			"max_concurrent":           c.FRC.MaxConcurrent,
+			"concurrency_limits":       c.FRC.ConcurrencyLimits,
+			"max_queued":               c.FRC.MaxQueued,
+			"queue_timeout":            c.FRC.QueueTimeout.String(),
+		},
+		"image": map[string]interface{}{
+			"max_dimension":  c.Image.MaxDimension,
+			"jpeg_quality":   c.Image.JPEGQuality,
+			"heic_converter": c.Image.HEICConverter,
+		},
+		"quality": map[string]interface{}{
+			"enabled":          c.Quality.Enabled,
+			"min_face_ratio":   c.Quality.MinFaceRatio,
+			"max_pose_degrees": c.Quality.MaxPoseDegrees,
+			"min_brightness":   c.Quality.MinBrightness,
+			"max_brightness":   c.Quality.MaxBrightness,
+		},
+		"capture": map[string]interface{}{
+			"enabled":             c.Capture.Enabled,
+			"max_age":             c.Capture.MaxAge.String(),
+			"max_distance_meters": c.Capture.MaxDistanceMeters,
+			"timezone":            c.Capture.Timezone,
+		},
+		"fraud": map[string]interface{}{
+			"enabled":                     c.Fraud.Enabled,
+			"window":                      c.Fraud.Window.String(),
+			"max_participants_per_source": c.Fraud.MaxParticipantsPerSource,
+			"max_travel_speed_kmh":        c.Fraud.MaxTravelSpeedKMH,
+			"review_score":                c.Fraud.ReviewScore,
+		},
+		"verification": map[string]interface{}{
+			"distance_threshold":   c.Verification.DistanceThreshold,
+			"similarity_threshold": c.Verification.SimilarityThreshold,
+			"validity_months":      c.Verification.ValidityMonths,
+			"schedule_policy":      c.Verification.SchedulePolicy,
+			"schedule_date":        c.Verification.ScheduleDate,
+			"rules_file":           c.Verification.RulesFile,
+		},
+		"step_up": map[string]interface{}{
+			"enabled":           c.StepUp.Enabled,
+			"risk_score":        c.StepUp.RiskScore,
+			"similarity_margin": c.StepUp.SimilarityMargin,
+			"distance_margin":   c.StepUp.DistanceMargin,
+			"challenge":         c.StepUp.Challenge,
+		},
+		"verification_lock": map[string]interface{}{
+			"max_failures": c.VerificationLock.MaxFailures,
+			"cooldown":     c.VerificationLock.Cooldown.String(),
+		},
+		"verification_device": map[string]interface{}{
+			"trust_on_valid": c.VerificationDevice.TrustOnValid,
+		},
+		"verification_session": map[string]interface{}{
+			"required":        c.Session.Required,
+			"ttl":             c.Session.TTL.String(),
+			"max_image_bytes": c.Session.MaxImageBytes,
+		},
+		"liveness": map[string]interface{}{
+			"enabled": c.Liveness.Enabled,
+		},
+		"review": map[string]interface{}{
+			"sla":              c.Review.SLA.String(),
+			"senior_reviewers": c.Review.SeniorReviewers,
+		},
+		"officers": map[string]interface{}{
+			"max_daily_verifications": c.Officers.MaxDailyVerifications,
+			"min_field_interval":      c.Officers.MinFieldInterval.String(),
+		},
+		"calendar": map[string]interface{}{
+			"weekend_days": c.Calendar.WeekendDays.String(),
+		},
+		"bulk_registration": map[string]interface{}{
+			"workers": c.BulkRegistration.Workers,
+		},
+		"storage": map[string]interface{}{
+			"dir":     c.Storage.Dir,
+			"selfies": c.Storage.Selfies,
+		},
+		"retention": map[string]interface{}{
+			"selfie_months":     c.Retention.SelfieMonths,
+			"latest_valid_only": c.Retention.LatestValidOnly,
+			"purge_schedule":    c.Retention.PurgeSchedule,
+		},
+		"consent": map[string]interface{}{
+			"terms_version": c.Consent.TermsVersion,
+		},
+		"self_service": map[string]interface{}{
+			"enabled":         c.SelfService.Enabled,
+			"token_secret":    redactSecret(c.SelfService.TokenSecret),
+			"code_length":     c.SelfService.CodeLength,
+			"code_ttl":        c.SelfService.CodeTTL.String(),
+			"max_attempts":    c.SelfService.MaxAttempts,
+			"resend_interval": c.SelfService.ResendInterval.String(),
+			"token_ttl":       c.SelfService.TokenTTL.String(),
+		},
+		"receipt": map[string]interface{}{
+			"enabled":        c.Receipt.Enabled,
+			"signing_secret": redactSecret(c.Receipt.SigningSecret),
+			"public_url":     c.Receipt.PublicURL,
+			"qr_size":        c.Receipt.QRSize,
+		},
+		"certificate_pdf": map[string]interface{}{
+			"enabled":  c.CertificatePDF.Enabled,
+			"template": c.CertificatePDF.Template,
+			"issuer":   c.CertificatePDF.Issuer,
+			"timezone": c.CertificatePDF.Timezone,
+		},
+		"signing": map[string]interface{}{
+			"backend":         c.Signing.Backend,
+			"pkcs12_file":     c.Signing.PKCS12File,
+			"pkcs12_password": redactSecret(c.Signing.PKCS12Password),
+			"kms_key_id":      c.Signing.KMSKeyID,
+			"kms_region":      c.Signing.KMSRegion,
+			"kms_chain_file":  c.Signing.KMSChainFile,
+		},
+		"monthly_report": map[string]interface{}{
+			"schedule": c.MonthlyReport.Schedule,
+			"format":   c.MonthlyReport.Format,
+			"template": c.MonthlyReport.Template,
+			"timezone": c.MonthlyReport.Timezone,
+		},
+		"seed": map[string]interface{}{
+			"enabled": c.Seed.Enabled,
+		},
+		"cache": map[string]interface{}{
+			"backend":      c.Cache.Backend,
+			"ttl":          c.Cache.TTL.String(),
+			"size":         c.Cache.Size,
+			"redis_url":    redactDSN(c.Cache.RedisURL),
+			"redis_prefix": c.Cache.RedisPrefix,
+		},
+		"antivirus": map[string]interface{}{
+			"clamd_addr": c.Antivirus.ClamdAddr,
+			"timeout":    c.Antivirus.Timeout.String(),
+			"quarantine": c.Antivirus.Quarantine,
+		},
+		"ocr": map[string]interface{}{
+			"provider":           c.OCR.Provider,
+			"tesseract_path":     c.OCR.TesseractPath,
+			"tesseract_language": c.OCR.TesseractLanguage,
+			"url":                c.OCR.URL,
+			"api_key":            redactSecret(c.OCR.APIKey),
+			"timeout":            c.OCR.Timeout.String(),
+		},
+		"ktp_face": map[string]interface{}{
+			"mode":           c.KTPFace.Mode,
+			"min_similarity": c.KTPFace.MinSimilarity,
+		},
+		"webhook": map[string]interface{}{
+			"timeout":      c.Webhook.Timeout.String(),
+			"max_attempts": c.Webhook.MaxAttempts,
+		},
+		"payment_push": map[string]interface{}{
+			"url":             c.PaymentPush.URL,
+			"auth":            c.PaymentPush.AuthType,
+			"username":        c.PaymentPush.Username,
+			"password":        redactSecret(c.PaymentPush.Password),
+			"token":           redactSecret(c.PaymentPush.Token),
+			"field_map":       fieldMap,
+			"timeout":         c.PaymentPush.Timeout.String(),
+			"max_attempts":    c.PaymentPush.MaxAttempts,
+			"expiry_schedule": c.PaymentPush.ExpirySchedule,
+		},
+		"hold_release": map[string]interface{}{
+			"url":           c.HoldRelease.URL,
+			"method":        c.HoldRelease.Method,
+			"auth":          c.HoldRelease.AuthType,
+			"username":      c.HoldRelease.Username,
+			"password":      redactSecret(c.HoldRelease.Password),
+			"token":         redactSecret(c.HoldRelease.Token),
+			"template_file": c.HoldRelease.TemplateFile,
+			"content_type":  c.HoldRelease.ContentType,
+			"ack_field":     c.HoldRelease.AckField,
+			"timeout":       c.HoldRelease.Timeout.String(),
+			"max_attempts":  c.HoldRelease.MaxAttempts,
+		},
+		"payroll_file": map[string]interface{}{
+			"sftp_host":           c.PayrollFile.SFTPHost,
+			"sftp_user":           c.PayrollFile.SFTPUser,
+			"sftp_key_file":       c.PayrollFile.SFTPKeyFile,
+			"sftp_key_passphrase": redactSecret(c.PayrollFile.SFTPKeyPassphrase),
+			"sftp_host_key":       c.PayrollFile.SFTPHostKey,
+			"sftp_path":           c.PayrollFile.SFTPPath,
+			"timeout":             c.PayrollFile.Timeout.String(),
+			"format":              c.PayrollFile.Format,
+			"prefix":              c.PayrollFile.Prefix,
+			"fund":                c.PayrollFile.Fund,
+			"schedule":            c.PayrollFile.Schedule,
+		},
+		"civil_registry": map[string]interface{}{
+			"url":      c.CivilRegistry.URL,
+			"username": c.CivilRegistry.Username,
+			"password": redactSecret(c.CivilRegistry.Password),
+			"timeout":  c.CivilRegistry.Timeout.String(),
+			"schedule": c.CivilRegistry.Schedule,
+		},
+		"alert": map[string]interface{}{
+			"frcore_error_rate": c.Alert.FRCoreErrorRate,
+			"invalid_ratio":     c.Alert.InvalidRatio,
+			"invalid_min":       c.Alert.InvalidMin,
+			"review_backlog":    c.Alert.ReviewBacklog,
+			"repeated_failures": c.Alert.RepeatedFailures,
+			"cooldown":          c.Alert.Cooldown.String(),
+			// Slack incoming webhook URLs embed their credential.
+			"slack_webhook_url": redactSecret(c.Alert.SlackWebhookURL),
+			"email_to":          c.Alert.EmailTo,
+		},
+		"smtp": map[string]interface{}{
+			"addr":     c.SMTP.Addr,
+			"username": c.SMTP.Username,
+			"password": redactSecret(c.SMTP.Password),
+			"from":     c.SMTP.From,
+		},
+		"i18n": map[string]interface{}{
+			"default_language": c.I18n.DefaultLanguage,
+			"catalog_dir":      c.I18n.CatalogDir,
+		},
+		"notification": map[string]interface{}{
+			"email_enabled":      c.Notification.EmailEnabled,
+			"template_dir":       c.Notification.TemplateDir,
+			"dry_run":            c.Notification.DryRun,
+			"phone_country_code": c.Notification.PhoneCountryCode,
+			"timeout":            c.Notification.Timeout.String(),
+			"timezone":           c.Notification.Timezone,
+			"public_url":         c.Notification.PublicURL,
+			"unsubscribe_secret": redactSecret(c.Notification.UnsubscribeSecret),
+		},
+		"sms": map[string]interface{}{
+			"provider":   c.SMS.Provider,
+			"url":        c.SMS.URL,
+			"account_id": c.SMS.AccountID,
+			"token":      redactSecret(c.SMS.Token),
+			"from":       c.SMS.From,
+		},
+		"whatsapp": map[string]interface{}{
+			"phone_number_id":   c.WhatsApp.PhoneNumberID,
+			"token":             redactSecret(c.WhatsApp.Token),
+			"url":               c.WhatsApp.URL,
+			"template":          c.WhatsApp.Template,
+			"template_language": c.WhatsApp.TemplateLanguage,
+		},
+		"fcm": map[string]interface{}{
+			"credentials_file": c.FCM.CredentialsFile,
+			"project_id":       c.FCM.ProjectID,
+			"url":              c.FCM.URL,
+		},
+		"reminder": map[string]interface{}{
+			"schedule":       c.Reminder.Schedule,
+			"lead_days":      c.Reminder.LeadDays,
+			"repeat_days":    c.Reminder.RepeatDays,
+			"max_per_target": c.Reminder.MaxPerTarget,
+		},
+		"campaign": map[string]interface{}{
+			"refresh_schedule":  c.Campaign.RefreshSchedule,
+			"notify_per_minute": c.Campaign.NotifyPerMinute,
+		},
+		"jobs": map[string]interface{}{
+			"workers":       c.Jobs.Workers,
+			"poll_interval": c.Jobs.PollInterval.String(),
+			"max_attempts":  c.Jobs.MaxAttempts,
+			"timeout":       c.Jobs.Timeout.String(),
+		},
+		"tracing": map[string]interface{}{
+			"endpoint":     c.Tracing.Endpoint,
+			"service_name": c.Tracing.ServiceName,
+			"sample_ratio": c.Tracing.SampleRatio,
+			"slow_request": c.Tracing.SlowRequest.String(),
+		},
+		"graphql": map[string]interface{}{
+			"max_depth": c.GraphQL.MaxDepth,
+		},
+		"events": map[string]interface{}{
+			"broker":              c.Events.Broker,
+			"publish_timeout":     c.Events.PublishTimeout.String(),
+			"kafka_brokers":       c.Events.KafkaBrokers,
+			"kafka_topic":         c.Events.KafkaTopic,
+			"nats_url":            redactDSN(c.Events.NATSURL),
+			"nats_subject_prefix": c.Events.NATSSubjectPrefix,
+		},
+	}
+}
+
+// redactSecret hides a secret while still showing whether it is set.
+func redactSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redacted
+}
+
+// redactDSN masks the password of a URL style connection string.
+func redactDSN(dsn string) string {
+	parsed, err := url.Parse(dsn)
+	if err != nil || parsed.Scheme == "" {
+		// Key/value DSNs may carry password=...; hide the whole string rather than parse it.
+		return redactSecret(dsn)
+	}
+	return parsed.Redacted()
+}
@@ -1,28 +1,236 @@
 package database
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/tenant"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 // New initialises a gorm DB connection using PostgreSQL with the provided DSN.
+// Statements on tenant tables act for the tenant of their context.
 func New(dsn string) (*gorm.DB, error) {
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	if err != nil {
 		return nil, fmt.Errorf("open database: %w", err)
 	}
+	if err := repository.RegisterTenantScope(db, Models()...); err != nil {
+		return nil, err
+	}
 
 	return db, nil
 }
 
+// Models lists every table managed by Migrate.
+func Models() []interface{} {
+	return []interface{}{&domain.Participant{}, &domain.LifeCertificate{}, &domain.FRIdentity{}, &domain.Member{}, &domain.MemberMerge{}, &domain.BulkRegistrationJob{}, &domain.BulkRegistrationRow{}, &domain.FRReconciliationRun{}, &domain.AuditLog{}, &domain.CertificateDocument{}, &domain.StatusOverride{}, &domain.WebhookSubscription{}, &domain.WebhookDelivery{}, &domain.OutboxEvent{}, &domain.PaymentPush{}, &domain.AccessLog{}, &domain.VerificationProfile{}, &domain.Campaign{}, &domain.CampaignParticipant{}, &domain.CampaignExclusion{}, &domain.CampaignNotification{}, &domain.Reminder{}, &domain.Job{}, &domain.ScheduledTask{}, &domain.NotificationDelivery{}, &domain.NotificationTemplate{}, &domain.ParticipantDevice{}, &domain.NotificationPreference{}, &domain.UploadScan{}, &domain.Consent{}, &domain.VerificationRequest{}, &domain.ParticipantVerificationState{}, &domain.DeathReport{}, &domain.HoldRelease{}, &domain.PayrollFileDelivery{}, &domain.PartnerAPIKey{}, &domain.SelfServiceOTP{}, &domain.VerificationSession{}, &domain.Kiosk{}, &domain.VerificationLock{}, &domain.FacialConflict{}, &domain.VerificationDevice{}, &domain.MonthlyReport{}, &domain.Tenant{}, &domain.HomeVisit{}, &domain.Officer{}, &domain.Branch{}, &domain.Holiday{}, &domain.Document{}, &domain.KTPCheck{}}
+}
+
+// Ping checks the database connection is alive.
+func Ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("get sql db: %w", err)
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// CheckSchema reports an error naming the tables Migrate would create that are missing.
+func CheckSchema(ctx context.Context, db *gorm.DB) error {
+	migrator := db.WithContext(ctx).Migrator()
+	var missing []string
+	for _, model := range Models() {
+		if !migrator.HasTable(model) {
+			stmt := &gorm.Statement{DB: db}
+			if err := stmt.Parse(model); err != nil {
+				return fmt.Errorf("parse model: %w", err)
+			}
+			missing = append(missing, stmt.Table)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing tables: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
 // Migrate applies the schema required for the service.
 func Migrate(db *gorm.DB) error {
-	if err := db.AutoMigrate(&domain.Participant{}, &domain.LifeCertificate{}, &domain.FRIdentity{}, &domain.Member{}); err != nil {
+	// The schema is shared by every tenant, and the scope would query
+	// tenant_id before it exists.
+	db = db.WithContext(tenant.System(db.Statement.Context))
+	if err := db.AutoMigrate(Models()...); err != nil {
 		return fmt.Errorf("auto migrate: %w", err)
 	}
+	if err := migrateParticipantFRLabels(db); err != nil {
+		return err
+	}
+	if err := migrateMemberPreferredChannel(db); err != nil {
+		return err
+	}
+	if err := migrateTenants(db); err != nil {
+		return err
+	}
+	if err := migrateCertificateChannels(db); err != nil {
+		return err
+	}
+	// One event now notifies a participant on several channels; the old
+	// per-event index would reject the second delivery.
+	if db.Migrator().HasIndex(&domain.NotificationDelivery{}, "idx_notification_event") {
+		if err := db.Migrator().DropIndex(&domain.NotificationDelivery{}, "idx_notification_event"); err != nil {
+			return fmt.Errorf("drop idx_notification_event: %w", err)
+		}
+	}
+	return nil
+}
+
+// errRehearsal rolls back the transaction opened by RehearseMigrate.
+var errRehearsal = errors.New("migration rehearsal")
+
+// RehearseMigrate runs Migrate inside a transaction that is always rolled back,
+// reporting whether the schema changes would apply cleanly.
+func RehearseMigrate(ctx context.Context, db *gorm.DB) error {
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := Migrate(tx); err != nil {
+			return err
+		}
+		return errRehearsal
+	})
+	if errors.Is(err, errRehearsal) {
+		return nil
+	}
+	return err
+}
+
+// migrateParticipantFRLabels moves the legacy single participants.fr_label column
+// into fr_identities, which now owns every enrolled face, then drops the column.
+func migrateParticipantFRLabels(db *gorm.DB) error {
+	if !db.Migrator().HasColumn("participants", "fr_label") {
+		return nil
+	}
+
+	if err := db.Exec(`
+		INSERT INTO fr_identities (label, participant_id, external_ref, source, created_at)
+		SELECT p.fr_label, p.id, p.fr_external_ref, ?, p.created_at
+		FROM participants p
+		WHERE p.fr_label IS NOT NULL AND p.fr_label <> ''
+		ON CONFLICT (label) DO NOTHING`, domain.FRIdentitySourceRegistration).Error; err != nil {
+		return fmt.Errorf("backfill fr identities: %w", err)
+	}
+
+	if err := db.Migrator().DropColumn("participants", "fr_label"); err != nil {
+		return fmt.Errorf("drop participants.fr_label: %w", err)
+	}
+	return nil
+}
+
+// migrateMemberPreferredChannel moves the legacy members.preferred_channel
+// column into notification_preferences, which now owns a member's channel
+// choices, keeping the other channels as fallbacks, then drops the column.
+func migrateMemberPreferredChannel(db *gorm.DB) error {
+	if !db.Migrator().HasColumn("members", "preferred_channel") {
+		return nil
+	}
+
+	if err := db.Exec(`
+		INSERT INTO notification_preferences (member_id, channels, updated_by, updated_at)
+		SELECT m.id, CASE m.preferred_channel
+			WHEN 'sms' THEN 'sms,email,whatsapp,push'
+			WHEN 'whatsapp' THEN 'whatsapp,email,sms,push'
+			ELSE 'email,sms,whatsapp,push' END, 'migration', NOW()
+		FROM members m
+		WHERE m.preferred_channel IS NOT NULL AND m.preferred_channel <> ''
+		ON CONFLICT (member_id) DO NOTHING`).Error; err != nil {
+		return fmt.Errorf("backfill notification preferences: %w", err)
+	}
+
+	if err := db.Migrator().DropColumn("members", "preferred_channel"); err != nil {
+		return fmt.Errorf("drop members.preferred_channel: %w", err)
+	}
+	return nil
+}
+
+// migrateCertificateChannels sets the channel of attempts recorded before
+// certificates carried one, from their method, kiosk and home visit, and
+// their session from the verification session that consumed it.
+func migrateCertificateChannels(db *gorm.DB) error {
+	if err := db.Exec(`
+		UPDATE life_certificate lc SET channel = CASE
+			WHEN lc.method = ? THEN ?
+			WHEN lc.method = ? AND EXISTS (SELECT 1 FROM home_visits hv WHERE hv.certificate_id = lc.id) THEN ?
+			WHEN lc.method = ? THEN ?
+			WHEN lc.kiosk_id IS NOT NULL THEN ?
+			ELSE ? END,
+			session_id = (SELECT vs.id FROM verification_sessions vs WHERE vs.certificate_id = lc.id LIMIT 1)
+		WHERE lc.channel IS NULL OR lc.channel = ''`,
+		domain.VerificationMethodProxy, domain.VerificationChannelProxy,
+		domain.VerificationMethodManual, domain.VerificationChannelField,
+		domain.VerificationMethodManual, domain.VerificationChannelManual,
+		domain.VerificationChannelKiosk, domain.VerificationChannelMobile).Error; err != nil {
+		return fmt.Errorf("backfill certificate channels: %w", err)
+	}
+	return nil
+}
+
+// tenantUniqueIndexes are the single-tenant unique indexes replaced by ones
+// that include tenant_id, so two funds can enrol the same NIK.
+var tenantUniqueIndexes = map[string][]string{
+	"members":               {"idx_members_nik", "idx_members_nomor_peserta"},
+	"participants":          {"idx_participants_nik"},
+	"verification_profiles": {"idx_verification_profiles_name", "idx_verification_profiles_fund"},
+}
+
+// tenantPrimaryKeys are the tables keyed by name whose primary key now leads
+// with tenant_id.
+var tenantPrimaryKeys = map[string]string{
+	"monthly_reports":        "tenant_id, period",
+	"notification_templates": "tenant_id, name",
+}
+
+// migrateTenants moves a single-tenant schema to multi-tenancy: AutoMigrate
+// has already added tenant_id everywhere with existing rows under the
+// default tenant; this drops the unique indexes and primary keys that
+// ignore the tenant and makes sure the default tenant exists.
+func migrateTenants(db *gorm.DB) error {
+	for table, indexes := range tenantUniqueIndexes {
+		for _, index := range indexes {
+			if !db.Migrator().HasIndex(table, index) {
+				continue
+			}
+			if err := db.Migrator().DropIndex(table, index); err != nil {
+				return fmt.Errorf("drop %s: %w", index, err)
+			}
+		}
+	}
+
+	for table, columns := range tenantPrimaryKeys {
+		var keyed int64
+		if err := db.Raw(`
+			SELECT COUNT(*) FROM information_schema.table_constraints c
+			JOIN information_schema.key_column_usage k ON k.constraint_name = c.constraint_name AND k.table_name = c.table_name
+			WHERE c.table_name = ? AND c.constraint_type = 'PRIMARY KEY' AND k.column_name = 'tenant_id'`, table).Scan(&keyed).Error; err != nil {
+			return fmt.Errorf("check %s primary key: %w", table, err)
+		}
+		if keyed > 0 {
+			continue
+		}
+		if err := db.Exec(fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s_pkey, ADD PRIMARY KEY (%s)", table, table, columns)).Error; err != nil {
+			return fmt.Errorf("rekey %s: %w", table, err)
+		}
+	}
+
+	if err := db.Exec(`
+		INSERT INTO tenants (id, name, created_at, updated_at)
+		VALUES (?, 'Default', NOW(), NOW())
+		ON CONFLICT (id) DO NOTHING`, tenant.DefaultID).Error; err != nil {
+		return fmt.Errorf("create default tenant: %w", err)
+	}
 	return nil
 }
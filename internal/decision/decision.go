@@ -0,0 +1,435 @@
+// Package decision decides whether an automatic verification is VALID from
+// declared rules instead of hard-coded conditions.
+//
+// A rule set lists rules over the signals an attempt produced: whether the
+// face matched one of the participant's labels, the match distance and
+// similarity, the liveness check and the fraud risk score. Each rule passes,
+// fails, or is skipped when its signal was not measured. Required rules must
+// pass; the others are combined by the set's policy: all of them, any of
+// them, or at least a number of them. A rule may apply only to attempts from
+// devices at some trust levels, so submissions from new or unknown devices
+// can be held to stricter rules. Rule sets are declared per verification
+// profile in a YAML file, with a default set for everyone else.
+package decision
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Signal names something an attempt measured.
+type Signal string
+
+const (
+	// SignalLabelMatch is whether FR Core matched one of the participant's labels.
+	SignalLabelMatch Signal = "label_match"
+	// SignalDistance is the match distance; lower is closer.
+	SignalDistance Signal = "distance"
+	// SignalSimilarity is the match similarity; higher is closer.
+	SignalSimilarity Signal = "similarity"
+	// SignalLiveness is whether the liveness check passed, unmeasured when the profile skips it.
+	SignalLiveness Signal = "liveness"
+	// SignalRiskScore is the fraud risk score, unmeasured when the fraud check is off.
+	SignalRiskScore Signal = "risk_score"
+)
+
+// Combine is how the rules that are not required decide together.
+type Combine string
+
+const (
+	// CombineAll needs every evaluated rule to pass.
+	CombineAll Combine = "all"
+	// CombineAny needs one evaluated rule to pass.
+	CombineAny Combine = "any"
+	// CombineAtLeast needs MinPassed rules to pass.
+	CombineAtLeast Combine = "at_least"
+)
+
+// Outcome is what a rule concluded.
+type Outcome string
+
+const (
+	// OutcomePass and OutcomeFail are the verdicts of an evaluated rule;
+	// OutcomeSkipped rules did not count.
+	OutcomePass    Outcome = "pass"
+	OutcomeFail    Outcome = "fail"
+	OutcomeSkipped Outcome = "skipped"
+)
+
+// Device is how well the participant's capturing device is known.
+type Device string
+
+const (
+	// DeviceUnknown attempts sent no device ID, or came from a kiosk.
+	DeviceUnknown Device = "unknown"
+	// DeviceNew is the first attempt from the device for the participant.
+	DeviceNew Device = "new"
+	// DeviceKnown devices were seen before but are not trusted.
+	DeviceKnown Device = "known"
+	// DeviceTrusted devices passed a verification or were trusted by an admin.
+	DeviceTrusted Device = "trusted"
+)
+
+// DefaultRuleSet names the rule set of participants without a profile of their own.
+const DefaultRuleSet = "default"
+
+// Rule checks one signal.
+type Rule struct {
+	Signal Signal `yaml:"signal" json:"signal"`
+	// Threshold is the largest distance or risk score, or the smallest
+	// similarity, that passes. Distance and similarity rules without one use
+	// the profile's thresholds; risk score rules need one.
+	Threshold *float64 `yaml:"threshold" json:"threshold,omitempty"`
+	// Required rules must pass whatever the combination policy, and fail
+	// when their signal was not measured.
+	Required bool `yaml:"required" json:"required,omitempty"`
+	// InsteadOf skips the rule when the named signal was measured, e.g. a
+	// similarity rule standing in for distance when FR Core returns none.
+	InsteadOf Signal `yaml:"instead_of" json:"instead_of,omitempty"`
+	// Devices limits the rule to attempts from devices at these trust
+	// levels, e.g. requiring liveness from new and unknown devices only.
+	Devices []Device `yaml:"devices" json:"devices,omitempty"`
+}
+
+// applies reports whether the rule covers attempts from the device.
+func (r Rule) applies(device Device) bool {
+	if len(r.Devices) == 0 {
+		return true
+	}
+	for _, level := range r.Devices {
+		if level == device {
+			return true
+		}
+	}
+	return false
+}
+
+// RuleSet is the rules deciding one profile's attempts.
+type RuleSet struct {
+	Name    string  `yaml:"-" json:"name"`
+	Combine Combine `yaml:"combine" json:"combine"`
+	// MinPassed is how many rules that are not required must pass for at_least.
+	MinPassed int    `yaml:"min_passed" json:"min_passed,omitempty"`
+	Rules     []Rule `yaml:"rules" json:"rules"`
+}
+
+// Rules holds the default rule set and those of named verification profiles.
+type Rules struct {
+	Default  RuleSet            `yaml:"default"`
+	Profiles map[string]RuleSet `yaml:"profiles"`
+}
+
+// Default is the rule set used when no rules file declares one: the face
+// must match one of the participant's labels within the distance threshold,
+// or within the similarity threshold when FR Core returns no distance.
+func Default() RuleSet {
+	return RuleSet{
+		Name:    DefaultRuleSet,
+		Combine: CombineAll,
+		Rules: []Rule{
+			{Signal: SignalLabelMatch, Required: true},
+			{Signal: SignalDistance},
+			{Signal: SignalSimilarity, InsteadOf: SignalDistance},
+		},
+	}
+}
+
+// Load reads a rules file. An empty path uses the default rule set for every
+// profile, as does a file without a default section.
+func Load(path string) (*Rules, error) {
+	rules := &Rules{Default: Default()}
+	if path == "" {
+		return rules, nil
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read rules file: %w", err)
+	}
+	var file Rules
+	if err := yaml.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("parse rules file %s: %w", path, err)
+	}
+	if len(file.Default.Rules) > 0 || file.Default.Combine != "" {
+		rules.Default = file.Default
+		rules.Default.Name = DefaultRuleSet
+	}
+	if err := rules.Default.normalize(); err != nil {
+		return nil, fmt.Errorf("rules file %s: default: %w", path, err)
+	}
+	rules.Profiles = make(map[string]RuleSet, len(file.Profiles))
+	for name, set := range file.Profiles {
+		set.Name = name
+		if err := set.normalize(); err != nil {
+			return nil, fmt.Errorf("rules file %s: profile %q: %w", path, name, err)
+		}
+		rules.Profiles[name] = set
+	}
+	return rules, nil
+}
+
+// For returns the rule set of a verification profile, or the default set
+// when the profile has none or the participant has no profile.
+func (r *Rules) For(profile string) RuleSet {
+	if set, ok := r.Profiles[profile]; ok && profile != "" {
+		return set
+	}
+	return r.Default
+}
+
+// Requires reports whether a required rule of the set checks the signal for
+// attempts from the device, so the signal has to be measured.
+func (s RuleSet) Requires(signal Signal, device Device) bool {
+	for _, rule := range s.Rules {
+		if rule.Signal == signal && rule.Required && rule.applies(device) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize lower-cases the names in the set and checks it can be evaluated.
+func (s *RuleSet) normalize() error {
+	s.Combine = Combine(strings.ToLower(strings.TrimSpace(string(s.Combine))))
+	if s.Combine == "" {
+		s.Combine = CombineAll
+	}
+	if len(s.Rules) == 0 {
+		return fmt.Errorf("no rules")
+	}
+	seen := make(map[Signal]bool, len(s.Rules))
+	optional := 0
+	for i := range s.Rules {
+		rule := &s.Rules[i]
+		rule.Signal = Signal(strings.ToLower(strings.TrimSpace(string(rule.Signal))))
+		rule.InsteadOf = Signal(strings.ToLower(strings.TrimSpace(string(rule.InsteadOf))))
+		if !known(rule.Signal) {
+			return fmt.Errorf("rule %d: unknown signal %q", i+1, rule.Signal)
+		}
+		// Rules limited to some devices may check a signal again.
+		if len(rule.Devices) == 0 {
+			if seen[rule.Signal] {
+				return fmt.Errorf("rule %d: signal %s is already checked", i+1, rule.Signal)
+			}
+			seen[rule.Signal] = true
+		}
+		for j, level := range rule.Devices {
+			level = Device(strings.ToLower(strings.TrimSpace(string(level))))
+			switch level {
+			case DeviceUnknown, DeviceNew, DeviceKnown, DeviceTrusted:
+			default:
+				return fmt.Errorf("rule %d: devices must be among unknown, new, known, trusted", i+1)
+			}
+			rule.Devices[j] = level
+		}
+		if rule.InsteadOf != "" && (!known(rule.InsteadOf) || rule.InsteadOf == rule.Signal) {
+			return fmt.Errorf("rule %d: instead_of must name another signal", i+1)
+		}
+		switch rule.Signal {
+		case SignalLabelMatch, SignalLiveness:
+			if rule.Threshold != nil {
+				return fmt.Errorf("rule %d: %s takes no threshold", i+1, rule.Signal)
+			}
+		case SignalRiskScore:
+			if rule.Threshold == nil || *rule.Threshold < 0 || *rule.Threshold > 100 {
+				return fmt.Errorf("rule %d: risk_score needs a threshold between 0 and 100", i+1)
+			}
+		}
+		if !rule.Required {
+			optional++
+		}
+	}
+	switch s.Combine {
+	case CombineAll, CombineAny:
+	case CombineAtLeast:
+		if s.MinPassed < 1 || s.MinPassed > optional {
+			return fmt.Errorf("min_passed must be between 1 and the %d rules that are not required", optional)
+		}
+	default:
+		return fmt.Errorf("combine must be one of all, any, at_least")
+	}
+	return nil
+}
+
+func known(signal Signal) bool {
+	switch signal {
+	case SignalLabelMatch, SignalDistance, SignalSimilarity, SignalLiveness, SignalRiskScore:
+		return true
+	}
+	return false
+}
+
+// Observation is what an attempt measured; nil signals were not measured.
+type Observation struct {
+	LabelMatch bool
+	Distance   *float64
+	Similarity float64
+	Liveness   *bool
+	RiskScore  *int
+	Device     Device
+}
+
+// Thresholds are the profile's thresholds for rules that declare none.
+type Thresholds struct {
+	Distance   float64
+	Similarity float64
+}
+
+// Step is the evaluation of one rule.
+type Step struct {
+	Signal    Signal   `json:"signal"`
+	Devices   []Device `json:"devices,omitempty"`
+	Value     *float64 `json:"value,omitempty"`
+	Threshold *float64 `json:"threshold,omitempty"`
+	Required  bool     `json:"required,omitempty"`
+	Outcome   Outcome  `json:"outcome"`
+}
+
+// Result is a rule set's verdict and the trace of how it was reached.
+type Result struct {
+	RuleSet   string  `json:"rule_set"`
+	Combine   Combine `json:"combine"`
+	MinPassed int     `json:"min_passed,omitempty"`
+	Device    Device  `json:"device,omitempty"`
+	Passed    bool    `json:"passed"`
+	Steps     []Step  `json:"rules"`
+}
+
+// Trace encodes the result for storing on the certificate.
+func (r Result) Trace() string {
+	encoded, _ := json.Marshal(r)
+	return string(encoded)
+}
+
+// Evaluate applies the rule set to an attempt. Nothing passes unless at
+// least one rule was evaluated and passed.
+func (s RuleSet) Evaluate(observation Observation, thresholds Thresholds) Result {
+	result := Result{RuleSet: s.Name, Combine: s.Combine, MinPassed: s.MinPassed, Device: observation.Device}
+	for _, rule := range s.Rules {
+		result.Steps = append(result.Steps, evaluate(rule, observation, thresholds))
+	}
+	result.Passed = result.decide()
+	return result
+}
+
+// Replay decides a traced attempt again as if its distance and similarity
+// rules, including those declaring their own threshold, were held to
+// thresholds. The other rules keep their outcome.
+func (r Result) Replay(thresholds Thresholds) bool {
+	replayed := r
+	replayed.Steps = make([]Step, len(r.Steps))
+	for i, step := range r.Steps {
+		if step.Outcome != OutcomeSkipped && step.Value != nil {
+			switch step.Signal {
+			case SignalDistance:
+				step.Outcome = outcome(*step.Value <= thresholds.Distance)
+			case SignalSimilarity:
+				step.Outcome = outcome(*step.Value >= thresholds.Similarity)
+			}
+		}
+		replayed.Steps[i] = step
+	}
+	return replayed.decide()
+}
+
+// decide combines the outcomes of the steps. Nothing passes unless at least
+// one rule was evaluated and passed.
+func (r Result) decide() bool {
+	requiredOK, anyPassed := true, false
+	passed, failed := 0, 0
+	for _, step := range r.Steps {
+		if step.Outcome == OutcomePass {
+			anyPassed = true
+		}
+		switch {
+		case step.Outcome == OutcomeSkipped && !(Rule{Devices: step.Devices}).applies(r.Device):
+			// A rule for other devices neither passes nor fails, even when required.
+		case step.Required:
+			if step.Outcome != OutcomePass {
+				requiredOK = false
+			}
+		case step.Outcome == OutcomePass:
+			passed++
+		case step.Outcome == OutcomeFail:
+			failed++
+		}
+	}
+
+	combined := false
+	switch r.Combine {
+	case CombineAll:
+		combined = failed == 0
+	case CombineAny:
+		combined = passed > 0 || passed+failed == 0
+	case CombineAtLeast:
+		combined = passed >= r.MinPassed
+	}
+	return requiredOK && combined && anyPassed
+}
+
+func evaluate(rule Rule, observation Observation, thresholds Thresholds) Step {
+	step := Step{Signal: rule.Signal, Devices: rule.Devices, Required: rule.Required, Outcome: OutcomeSkipped}
+	if !rule.applies(observation.Device) {
+		return step
+	}
+	if rule.InsteadOf != "" && measured(rule.InsteadOf, observation) {
+		return step
+	}
+	if !measured(rule.Signal, observation) {
+		if rule.Required {
+			step.Outcome = OutcomeFail
+		}
+		return step
+	}
+
+	ok := false
+	switch rule.Signal {
+	case SignalLabelMatch:
+		ok = observation.LabelMatch
+	case SignalLiveness:
+		ok = *observation.Liveness
+	case SignalDistance:
+		step.Value, step.Threshold = observation.Distance, threshold(rule, thresholds.Distance)
+		ok = *step.Value <= *step.Threshold
+	case SignalSimilarity:
+		value := observation.Similarity
+		step.Value, step.Threshold = &value, threshold(rule, thresholds.Similarity)
+		ok = value >= *step.Threshold
+	case SignalRiskScore:
+		value := float64(*observation.RiskScore)
+		step.Value, step.Threshold = &value, rule.Threshold
+		ok = value <= *step.Threshold
+	}
+	step.Outcome = outcome(ok)
+	return step
+}
+
+func outcome(ok bool) Outcome {
+	if ok {
+		return OutcomePass
+	}
+	return OutcomeFail
+}
+
+func threshold(rule Rule, fallback float64) *float64 {
+	if rule.Threshold != nil {
+		return rule.Threshold
+	}
+	return &fallback
+}
+
+// measured reports whether the attempt produced the signal.
+func measured(signal Signal, observation Observation) bool {
+	switch signal {
+	case SignalDistance:
+		return observation.Distance != nil
+	case SignalLiveness:
+		return observation.Liveness != nil
+	case SignalRiskScore:
+		return observation.RiskScore != nil
+	}
+	return true
+}
@@ -0,0 +1,280 @@
+// Package document renders the official PDF life certificate and the monthly
+// regulator report.
+package document
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+//go:embed templates/*.tmpl
+var builtin embed.FS
+
+// Page layout in millimetres on A4 portrait.
+const (
+	pageWidth   = 210.0
+	pageMargin  = 20.0
+	labelWidth  = 50.0
+	photoWidth  = 40.0
+	logoWidth   = 30.0
+	qrWidth     = 35.0
+	lineHeight  = 6.0
+	fieldHeight = 7.0
+)
+
+// CertificateData is the certificate a layout renders.
+type CertificateData struct {
+	Issuer        string
+	CertificateID string
+	ParticipantID string
+	Name          string
+	NIK           string
+	MemberNumber  string
+	Fund          string
+	Method        string
+	Location      string
+	Similarity    *float64
+	Distance      *float64
+	VerifiedAt    time.Time
+	ValidUntil    time.Time
+	IssuedAt      time.Time
+	// ReceiptURL is the public receipt check; the qr directive is skipped without it.
+	ReceiptURL string
+	// Footer is the issuer's closing text; it may be empty.
+	Footer string
+	// Photo is a JPEG of the selfie; the photo directive is skipped without it.
+	Photo []byte
+	// Logo is a PNG or JPEG of the issuer's logo; the logo directive is skipped without it.
+	Logo []byte
+}
+
+// CertificateTemplate lays out the PDF certificate. A layout is a Go
+// text/template rendering one directive per line:
+//
+//	title <text>            large centred heading
+//	heading <text>          section heading
+//	text <text>             paragraph
+//	field <label> | <value> labelled value
+//	photo                   the selfie thumbnail
+//	logo                    the issuer's logo
+//	qr                      QR code of the receipt URL
+//	rule                    horizontal line
+//	space                   blank gap
+type CertificateTemplate struct {
+	tmpl     *template.Template
+	location *time.Location
+}
+
+// LoadCertificateTemplate parses the layout file at path, or the built-in
+// layout when path is empty. Dates are printed in location.
+func LoadCertificateTemplate(path string, location *time.Location) (*CertificateTemplate, error) {
+	var raw []byte
+	var err error
+	if path != "" {
+		raw, err = os.ReadFile(path)
+	} else {
+		raw, err = builtin.ReadFile("templates/life_certificate.tmpl")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read certificate template: %w", err)
+	}
+	return ParseCertificateTemplate(string(raw), location)
+}
+
+// ParseCertificateTemplate parses a layout. It is rendered once against
+// sample data so mistakes surface at startup.
+func ParseCertificateTemplate(text string, location *time.Location) (*CertificateTemplate, error) {
+	if location == nil {
+		location = time.UTC
+	}
+	tmpl, err := template.New("life_certificate").Funcs(funcs(location)).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse certificate template: %w", err)
+	}
+	t := &CertificateTemplate{tmpl: tmpl, location: location}
+
+	score, now := 0.9, time.Now().UTC()
+	if _, err := t.Render(CertificateData{
+		Issuer:        "Sample Issuer",
+		Footer:        "Sample footer",
+		CertificateID: "00000000-0000-0000-0000-000000000000",
+		ParticipantID: "00000000-0000-0000-0000-000000000000",
+		Name:          "Sample Participant",
+		NIK:           "0000000000000000",
+		MemberNumber:  "0000000000",
+		Fund:          "SAMPLE",
+		Method:        "AUTOMATIC",
+		Location:      "Sample Office",
+		Similarity:    &score,
+		Distance:      &score,
+		VerifiedAt:    now,
+		ValidUntil:    now.AddDate(1, 0, 0),
+		IssuedAt:      now,
+		ReceiptURL:    "https://example.com/verify-receipt/sample",
+	}); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Render returns the PDF of one certificate.
+func (t *CertificateTemplate) Render(data CertificateData) ([]byte, error) {
+	for _, field := range []*string{&data.Issuer, &data.Footer, &data.Name, &data.MemberNumber, &data.Fund, &data.Location} {
+		// A value spanning lines would start a directive of its own.
+		*field = strings.Join(strings.Fields(*field), " ")
+	}
+	var layout bytes.Buffer
+	if err := t.tmpl.Execute(&layout, data); err != nil {
+		return nil, fmt.Errorf("render certificate template: %w", err)
+	}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.SetTitle("Life Certificate "+data.CertificateID, true)
+	pdf.SetCreator(data.Issuer, true)
+	pdf.AddPage()
+	// The core fonts are cp1252; names are translated from UTF-8.
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	for n, line := range strings.Split(layout.String(), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		directive, rest, _ := strings.Cut(line, " ")
+		rest = strings.TrimSpace(rest)
+		switch directive {
+		case "photo":
+			if len(data.Photo) > 0 {
+				placeImage(pdf, "photo", gofpdf.ImageOptions{ImageType: "JPG"}, data.Photo, photoWidth)
+			}
+		case "logo":
+			if len(data.Logo) > 0 {
+				placeImage(pdf, "logo", gofpdf.ImageOptions{ImageType: imageType(data.Logo)}, data.Logo, logoWidth)
+			}
+		case "qr":
+			if data.ReceiptURL != "" {
+				png, err := qrcode.Encode(data.ReceiptURL, qrcode.Medium, 512)
+				if err != nil {
+					return nil, fmt.Errorf("encode receipt QR code: %w", err)
+				}
+				placeImage(pdf, "qr", gofpdf.ImageOptions{ImageType: "PNG"}, png, qrWidth)
+			}
+		default:
+			if err := drawText(pdf, tr, directive, rest); err != nil {
+				return nil, fmt.Errorf("certificate template line %d: %w", n+1, err)
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	if err := pdf.Output(&out); err != nil {
+		return nil, fmt.Errorf("write certificate PDF: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// drawText draws one of the text directives shared by every layout.
+func drawText(pdf *gofpdf.Fpdf, tr func(string) string, directive, rest string) error {
+	switch directive {
+	case "title":
+		pdf.SetFont("Helvetica", "B", 20)
+		pdf.CellFormat(0, 12, tr(rest), "", 1, "C", false, 0, "")
+		pdf.Ln(4)
+	case "heading":
+		pdf.Ln(2)
+		pdf.SetFont("Helvetica", "B", 13)
+		pdf.CellFormat(0, 9, tr(rest), "", 1, "L", false, 0, "")
+	case "text":
+		pdf.SetFont("Helvetica", "", 11)
+		pdf.MultiCell(0, lineHeight, tr(rest), "", "L", false)
+		pdf.Ln(2)
+	case "field":
+		label, value, ok := strings.Cut(rest, "|")
+		if !ok {
+			return fmt.Errorf("field needs <label> | <value>")
+		}
+		pdf.SetFont("Helvetica", "B", 11)
+		pdf.CellFormat(labelWidth, fieldHeight, tr(strings.TrimSpace(label)), "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 11)
+		pdf.MultiCell(0, fieldHeight, tr(strings.TrimSpace(value)), "", "L", false)
+	case "rule":
+		y := pdf.GetY() + 2
+		pdf.Line(pageMargin, y, pageWidth-pageMargin, y)
+		pdf.SetY(y + 4)
+	case "space":
+		pdf.Ln(lineHeight)
+	default:
+		return fmt.Errorf("unknown directive %q", directive)
+	}
+	return nil
+}
+
+// placeImage centres an image of the given width at the current position.
+func placeImage(pdf *gofpdf.Fpdf, name string, options gofpdf.ImageOptions, image []byte, width float64) {
+	options.ReadDpi = false
+	pdf.RegisterImageOptionsReader(name, options, bytes.NewReader(image))
+	pdf.ImageOptions(name, (pageWidth-width)/2, pdf.GetY(), width, 0, true, options, 0, "")
+	pdf.Ln(4)
+}
+
+// CheckLogo reports whether logo is a PNG or JPEG the logo directive can draw.
+func CheckLogo(logo []byte) error {
+	imageType := imageType(logo)
+	if imageType == "" {
+		return fmt.Errorf("logo must be a PNG or JPEG image")
+	}
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.RegisterImageOptionsReader("logo", gofpdf.ImageOptions{ImageType: imageType}, bytes.NewReader(logo))
+	if err := pdf.Error(); err != nil {
+		return fmt.Errorf("logo cannot be drawn: %w", err)
+	}
+	return nil
+}
+
+// imageType returns the gofpdf image type of a PNG or JPEG, else "".
+func imageType(image []byte) string {
+	switch http.DetectContentType(image) {
+	case "image/png":
+		return "PNG"
+	case "image/jpeg":
+		return "JPG"
+	}
+	return ""
+}
+
+// funcs returns the layout functions, printing dates in location.
+func funcs(location *time.Location) template.FuncMap {
+	return template.FuncMap{
+		"date": func(t time.Time) string {
+			return t.In(location).Format("2 January 2006")
+		},
+		"datetime": func(t time.Time) string {
+			return t.In(location).Format("2 January 2006 15:04 MST")
+		},
+		"month": func(t time.Time) string {
+			return t.In(location).Format("January 2006")
+		},
+		"score": func(v *float64) string {
+			if v == nil {
+				return "-"
+			}
+			return fmt.Sprintf("%.2f", *v)
+		},
+		"percent": func(v *float64) string {
+			if v == nil {
+				return "-"
+			}
+			return fmt.Sprintf("%.2f%%", *v)
+		},
+	}
+}
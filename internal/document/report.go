@@ -0,0 +1,204 @@
+package document
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"life-certificates/internal/export"
+)
+
+// MonthlyReportData is the monthly report a layout renders.
+type MonthlyReportData struct {
+	Issuer string
+	// Period is the month reported, as YYYY-MM; From and To are its first and last day.
+	Period        string
+	From          time.Time
+	To            time.Time
+	GeneratedAt   time.Time
+	Verifications ReportVerifications
+	Exceptions    ReportExceptions
+	Overrides     ReportOverrides
+	Deaths        ReportDeaths
+}
+
+// ReportVerifications counts the month's attempts. PassRate is the
+// percentage of decided attempts that were VALID, nil without any.
+type ReportVerifications struct {
+	Attempts             int64
+	Valid                int64
+	Invalid              int64
+	Review               int64
+	Automatic            int64
+	Manual               int64
+	ParticipantsVerified int64
+	PassRate             *float64
+}
+
+// ReportExceptions counts the month's attempts that needed attention.
+// ReviewsPending are its attempts still waiting for a reviewer.
+type ReportExceptions struct {
+	FacialConflicts int64
+	CaptureFindings int64
+	ReviewsPending  int64
+}
+
+// ReportOverrides counts the status overrides proposed in the month and
+// those decided in it.
+type ReportOverrides struct {
+	Proposed int64
+	Approved int64
+	Rejected int64
+}
+
+// ReportDeaths counts the deaths the civil registry reported in the month and
+// the reports decided in it.
+type ReportDeaths struct {
+	Reported  int64
+	Confirmed int64
+	Rejected  int64
+}
+
+// ReportTemplate lays out the monthly report with the certificate's text
+// directives: title, heading, text, field, rule and space. The same layout
+// renders to PDF or, one directive per row, to XLSX.
+type ReportTemplate struct {
+	tmpl     *template.Template
+	location *time.Location
+}
+
+// LoadReportTemplate parses the layout file at path, or the built-in layout
+// when path is empty. Dates are printed in location.
+func LoadReportTemplate(path string, location *time.Location) (*ReportTemplate, error) {
+	var raw []byte
+	var err error
+	if path != "" {
+		raw, err = os.ReadFile(path)
+	} else {
+		raw, err = builtin.ReadFile("templates/monthly_report.tmpl")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read report template: %w", err)
+	}
+	return ParseReportTemplate(string(raw), location)
+}
+
+// ParseReportTemplate parses a layout. It is rendered once against sample
+// data so mistakes surface at startup.
+func ParseReportTemplate(text string, location *time.Location) (*ReportTemplate, error) {
+	if location == nil {
+		location = time.UTC
+	}
+	tmpl, err := template.New("monthly_report").Funcs(funcs(location)).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse report template: %w", err)
+	}
+	t := &ReportTemplate{tmpl: tmpl, location: location}
+
+	rate, now := 95.5, time.Now().UTC()
+	if _, err := t.PDF(MonthlyReportData{
+		Issuer:        "Sample Issuer",
+		Period:        now.Format("2006-01"),
+		From:          now.AddDate(0, 0, 1-now.Day()),
+		To:            now,
+		GeneratedAt:   now,
+		Verifications: ReportVerifications{PassRate: &rate},
+	}); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// PDF returns the report as a PDF.
+func (t *ReportTemplate) PDF(data MonthlyReportData) ([]byte, error) {
+	lines, err := t.lines(data)
+	if err != nil {
+		return nil, err
+	}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.SetTitle("Monthly Report "+data.Period, true)
+	pdf.SetCreator(data.Issuer, true)
+	pdf.AddPage()
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	for _, line := range lines {
+		if err := drawText(pdf, tr, line.directive, line.rest); err != nil {
+			return nil, fmt.Errorf("report template line %d: %w", line.n, err)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := pdf.Output(&out); err != nil {
+		return nil, fmt.Errorf("write report PDF: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// XLSX returns the report as a worksheet: fields as label and value
+// columns, other text in the first column and rules and spaces as empty rows.
+func (t *ReportTemplate) XLSX(data MonthlyReportData) ([]byte, error) {
+	lines, err := t.lines(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	sheet, err := export.New(export.FormatXLSX, &out)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range lines {
+		var row []string
+		switch line.directive {
+		case "title", "heading", "text":
+			row = []string{line.rest}
+		case "field":
+			label, value, ok := strings.Cut(line.rest, "|")
+			if !ok {
+				return nil, fmt.Errorf("report template line %d: field needs <label> | <value>", line.n)
+			}
+			row = []string{strings.TrimSpace(label), strings.TrimSpace(value)}
+		case "rule", "space":
+		default:
+			return nil, fmt.Errorf("report template line %d: unknown directive %q", line.n, line.directive)
+		}
+		if err := sheet.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	if err := sheet.Close(); err != nil {
+		return nil, fmt.Errorf("write report XLSX: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+type reportLine struct {
+	n         int
+	directive string
+	rest      string
+}
+
+// lines executes the layout and splits it into directives.
+func (t *ReportTemplate) lines(data MonthlyReportData) ([]reportLine, error) {
+	data.Issuer = strings.Join(strings.Fields(data.Issuer), " ")
+	var layout bytes.Buffer
+	if err := t.tmpl.Execute(&layout, data); err != nil {
+		return nil, fmt.Errorf("render report template: %w", err)
+	}
+	var lines []reportLine
+	for n, line := range strings.Split(layout.String(), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		directive, rest, _ := strings.Cut(line, " ")
+		lines = append(lines, reportLine{n: n + 1, directive: directive, rest: strings.TrimSpace(rest)})
+	}
+	return lines, nil
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// Resource types recorded in the access log.
+const (
+	AccessResourceParticipant       = "participant"
+	AccessResourceMember            = "member"
+	AccessResourceCertificateStatus = "certificate_status"
+	AccessResourceLifeCertificate   = "life_certificate"
+	// AccessResourceVerificationRequest is an asynchronous verification, keyed by its verification ID.
+	AccessResourceVerificationRequest = "verification_request"
+	// AccessResourceStatusFeed is a page of the partner change feed, keyed by the partner key ID.
+	AccessResourceStatusFeed = "status_feed"
+	// AccessResourceHomeVisit is a home visit, which holds the participant's address.
+	AccessResourceHomeVisit = "home_visit"
+	// AccessResourceDocument is a supporting document filed under another record.
+	AccessResourceDocument = "document"
+)
+
+// AccessLog records that an operator read personal data, kept apart from the mutation audit log.
+type AccessLog struct {
+	ID           string `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID     string `gorm:"size:36;not null;default:'default';index" json:"-"`
+	Actor        string `gorm:"size:100;index" json:"actor"`
+	ResourceType string `gorm:"size:64;index:idx_access_logs_resource" json:"resource_type"`
+	ResourceID   string `gorm:"size:64;index:idx_access_logs_resource" json:"resource_id"`
+	// Path is the request path that exposed the data.
+	Path      string    `gorm:"size:255" json:"path"`
+	RemoteIP  string    `gorm:"size:64" json:"remote_ip"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (AccessLog) TableName() string {
+	return "access_logs"
+}
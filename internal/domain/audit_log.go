@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// AuditLog records a state-changing action performed by an operator or the system.
+type AuditLog struct {
+	ID         string `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID   string `gorm:"size:36;not null;default:'default';index" json:"-"`
+	Actor      string `gorm:"size:100;index" json:"actor"`
+	Action     string `gorm:"size:64;index" json:"action"`
+	EntityType string `gorm:"size:64;index:idx_audit_logs_entity" json:"entity_type"`
+	EntityID   string `gorm:"size:64;index:idx_audit_logs_entity" json:"entity_id"`
+	// Details is a JSON object describing the change.
+	Details   string    `gorm:"type:text" json:"details"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
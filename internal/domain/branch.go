@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// Branch is a branch office and the geofence kiosk and officer submissions
+// attributed to it must be made in: RadiusMeters around its position.
+type Branch struct {
+	ID       string `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID string `gorm:"size:36;not null;default:'default';index;uniqueIndex:idx_branches_tenant_code,priority:1" json:"-"`
+	// Code is the branch_id kiosks, officers, home visits and certificates carry.
+	Code         string    `gorm:"size:64;uniqueIndex:idx_branches_tenant_code,priority:2" json:"code"`
+	Name         string    `gorm:"size:150" json:"name"`
+	Address      string    `gorm:"size:500" json:"address"`
+	Latitude     float64   `json:"latitude"`
+	Longitude    float64   `json:"longitude"`
+	RadiusMeters float64   `json:"radius_meters"`
+	CreatedBy    string    `gorm:"size:100" json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (Branch) TableName() string {
+	return "branches"
+}
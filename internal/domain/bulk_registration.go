@@ -0,0 +1,66 @@
+package domain
+
+import "time"
+
+// BulkRegistrationJobStatus tracks the lifecycle of a bulk registration upload.
+type BulkRegistrationJobStatus string
+
+const (
+	BulkRegistrationJobPending   BulkRegistrationJobStatus = "PENDING"
+	BulkRegistrationJobRunning   BulkRegistrationJobStatus = "RUNNING"
+	BulkRegistrationJobCompleted BulkRegistrationJobStatus = "COMPLETED"
+)
+
+// BulkRegistrationRowStatus tracks the outcome of a single manifest row.
+type BulkRegistrationRowStatus string
+
+const (
+	BulkRegistrationRowPending   BulkRegistrationRowStatus = "PENDING"
+	BulkRegistrationRowSucceeded BulkRegistrationRowStatus = "SUCCEEDED"
+	BulkRegistrationRowFailed    BulkRegistrationRowStatus = "FAILED"
+)
+
+// BulkRegistrationJob aggregates the progress of a bulk registration upload.
+type BulkRegistrationJob struct {
+	ID            string                    `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID      string                    `gorm:"size:36;not null;default:'default';index" json:"-"`
+	Status        BulkRegistrationJobStatus `gorm:"type:varchar(16);index" json:"status"`
+	SourceName    string                    `gorm:"size:255" json:"source_name"`
+	TotalRows     int                       `json:"total_rows"`
+	ProcessedRows int                       `json:"processed_rows"`
+	SucceededRows int                       `json:"succeeded_rows"`
+	FailedRows    int                       `json:"failed_rows"`
+	CreatedAt     time.Time                 `json:"created_at"`
+	StartedAt     *time.Time                `json:"started_at"`
+	FinishedAt    *time.Time                `json:"finished_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (BulkRegistrationJob) TableName() string {
+	return "bulk_registration_jobs"
+}
+
+// BulkRegistrationRow is one participant to register from a bulk manifest. The
+// image bytes are held only until the row has been processed.
+type BulkRegistrationRow struct {
+	ID            string                    `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID      string                    `gorm:"size:36;not null;default:'default';index" json:"-"`
+	JobID         string                    `gorm:"type:char(36);index" json:"job_id"`
+	RowNumber     int                       `json:"row_number"`
+	NIK           string                    `gorm:"size:20" json:"nik"`
+	Name          string                    `gorm:"size:100" json:"name"`
+	ImageName     string                    `gorm:"size:255" json:"image_name"`
+	ImageData     []byte                    `gorm:"type:bytea" json:"-"`
+	Status        BulkRegistrationRowStatus `gorm:"type:varchar(16);index" json:"status"`
+	ParticipantID *string                   `gorm:"type:char(36)" json:"participant_id"`
+	Error         *string                   `gorm:"type:text" json:"error"`
+	ProcessedAt   *time.Time                `json:"processed_at"`
+	// ClaimedAt is when a worker took the pending row; other replicas leave
+	// it alone until the claim is released or goes stale.
+	ClaimedAt *time.Time `json:"-"`
+}
+
+// TableName keeps the table naming explicit.
+func (BulkRegistrationRow) TableName() string {
+	return "bulk_registration_rows"
+}
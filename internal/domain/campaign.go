@@ -0,0 +1,185 @@
+package domain
+
+import "time"
+
+// CampaignKind decides how each participant's due date is set within a campaign.
+type CampaignKind string
+
+const (
+	// CampaignKindAnnual gives every participant the campaign's due date.
+	CampaignKindAnnual CampaignKind = "ANNUAL"
+	// CampaignKindBirthdayMonth makes each participant due at the end of their birthday month.
+	CampaignKindBirthdayMonth CampaignKind = "BIRTHDAY_MONTH"
+)
+
+// CampaignParticipantStatus tracks a participant's progress in a campaign.
+type CampaignParticipantStatus string
+
+const (
+	CampaignParticipantPending   CampaignParticipantStatus = "PENDING"
+	CampaignParticipantCompleted CampaignParticipantStatus = "COMPLETED"
+	CampaignParticipantOverdue   CampaignParticipantStatus = "OVERDUE"
+)
+
+// CampaignEscalationStage is how far a participant still pending after their
+// campaign due date has been escalated.
+type CampaignEscalationStage string
+
+const (
+	CampaignStageReminder CampaignEscalationStage = "REMINDER"
+	CampaignStageWarning  CampaignEscalationStage = "WARNING"
+	// CampaignStageHoldRecommended recommends holding the participant's pension payment.
+	CampaignStageHoldRecommended CampaignEscalationStage = "HOLD_RECOMMENDED"
+)
+
+// CampaignEscalationStages lists the stages in the order participants reach them.
+var CampaignEscalationStages = []CampaignEscalationStage{CampaignStageReminder, CampaignStageWarning, CampaignStageHoldRecommended}
+
+// CampaignEscalation is a campaign's grace-period policy: how many days after
+// their due date a participant still pending reaches each stage. Nil skips
+// the stage; a campaign without stages does not escalate.
+type CampaignEscalation struct {
+	ReminderDays *int `json:"reminder_days"`
+	WarningDays  *int `json:"warning_days"`
+	HoldDays     *int `json:"hold_days"`
+}
+
+// Days returns how many days after the due date the stage starts, false when the policy skips it.
+func (e CampaignEscalation) Days(stage CampaignEscalationStage) (int, bool) {
+	var days *int
+	switch stage {
+	case CampaignStageReminder:
+		days = e.ReminderDays
+	case CampaignStageWarning:
+		days = e.WarningDays
+	case CampaignStageHoldRecommended:
+		days = e.HoldDays
+	}
+	if days == nil {
+		return 0, false
+	}
+	return *days, true
+}
+
+// Enabled reports whether the policy has any stage.
+func (e CampaignEscalation) Enabled() bool {
+	return e.ReminderDays != nil || e.WarningDays != nil || e.HoldDays != nil
+}
+
+// Campaign is a life certificate cycle with a due window and a target population.
+type Campaign struct {
+	ID       string       `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID string       `gorm:"size:36;not null;default:'default';index" json:"-"`
+	Name     string       `gorm:"size:150" json:"name"`
+	Kind     CampaignKind `gorm:"type:varchar(20)" json:"kind"`
+	// StartsAt and DueAt are dates; a VALID certificate on or after StartsAt completes the campaign.
+	StartsAt time.Time `gorm:"type:date" json:"starts_at"`
+	DueAt    time.Time `gorm:"type:date" json:"due_at"`
+	// Fund, Province, Provinces, MinAge and LastVerifiedBefore are the
+	// targeting rules the population was drawn with; unset rules match everyone.
+	Fund     *string `gorm:"size:64" json:"fund"`
+	Province *string `gorm:"size:100" json:"province"`
+	// Provinces is a comma separated list of the provinces to target.
+	Provinces string `gorm:"type:text" json:"provinces"`
+	// MinAge keeps participants at least this old on StartsAt.
+	MinAge *int `json:"min_age"`
+	// LastVerifiedBefore keeps participants without a VALID certificate verified on or after it.
+	LastVerifiedBefore *time.Time `gorm:"type:date" json:"last_verified_before"`
+	Participants       int        `json:"participants"`
+	// Excluded counts the participants the rules matched but an exclusion left out.
+	Excluded   int                `json:"excluded"`
+	Escalation CampaignEscalation `gorm:"embedded;embeddedPrefix:escalation_" json:"escalation"`
+	CreatedBy  string             `gorm:"size:100" json:"created_by"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (Campaign) TableName() string {
+	return "campaigns"
+}
+
+// CampaignNotificationStatus tracks a campaign notification kickoff.
+type CampaignNotificationStatus string
+
+const (
+	CampaignNotificationQueued  CampaignNotificationStatus = "QUEUED"
+	CampaignNotificationRunning CampaignNotificationStatus = "RUNNING"
+	// CampaignNotificationCompleted has queued a reminder for every participant;
+	// the notifications themselves may still be waiting to be sent.
+	CampaignNotificationCompleted CampaignNotificationStatus = "COMPLETED"
+	CampaignNotificationFailed    CampaignNotificationStatus = "FAILED"
+)
+
+// CampaignNotification is a kickoff reminding every participant yet to
+// complete a campaign. Its deliveries carry its ID as their batch.
+type CampaignNotification struct {
+	ID         string                     `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID   string                     `gorm:"size:36;not null;default:'default';index" json:"-"`
+	CampaignID string                     `gorm:"type:char(36);index" json:"campaign_id"`
+	JobID      string                     `gorm:"type:char(36)" json:"job_id"`
+	Status     CampaignNotificationStatus `gorm:"type:varchar(16)" json:"status"`
+	// Targets counts the participants yet to complete when the kickoff started,
+	// Processed those reminded so far and Queued the deliveries queued for them.
+	Targets   int `json:"targets"`
+	Processed int `json:"processed"`
+	Queued    int `json:"queued"`
+	// Cursor is the last participant reminded, where a retried kickoff resumes.
+	Cursor     string     `gorm:"type:char(36)" json:"-"`
+	Error      *string    `gorm:"type:text" json:"error"`
+	CreatedBy  string     `gorm:"size:100" json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (CampaignNotification) TableName() string {
+	return "campaign_notifications"
+}
+
+// CampaignExclusionList names why participants are kept out of campaigns.
+type CampaignExclusionList string
+
+const (
+	CampaignExclusionHospitalized CampaignExclusionList = "HOSPITALIZED"
+	CampaignExclusionAbroad       CampaignExclusionList = "ABROAD"
+)
+
+// CampaignExclusion keeps a participant out of the campaigns starting while
+// it is in force, whatever the targeting rules.
+type CampaignExclusion struct {
+	ID            string                `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID      string                `gorm:"size:36;not null;default:'default';index" json:"-"`
+	ParticipantID string                `gorm:"type:char(36);uniqueIndex:idx_campaign_exclusions_participant_list,priority:1" json:"participant_id"`
+	List          CampaignExclusionList `gorm:"type:varchar(20);uniqueIndex:idx_campaign_exclusions_participant_list,priority:2" json:"list"`
+	Reason        string                `gorm:"size:500" json:"reason"`
+	// Until is the last date the exclusion applies; nil keeps it until removed.
+	Until     *time.Time `gorm:"type:date" json:"until"`
+	CreatedBy string     `gorm:"size:100" json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (CampaignExclusion) TableName() string {
+	return "campaign_exclusions"
+}
+
+// CampaignParticipant is a participant targeted by a campaign.
+type CampaignParticipant struct {
+	CampaignID    string                    `gorm:"type:char(36);primaryKey" json:"campaign_id"`
+	ParticipantID string                    `gorm:"type:char(36);primaryKey;index" json:"participant_id"`
+	TenantID      string                    `gorm:"size:36;not null;default:'default';index" json:"-"`
+	Status        CampaignParticipantStatus `gorm:"type:varchar(16);default:PENDING;index" json:"status"`
+	DueAt         time.Time                 `gorm:"type:date;index" json:"due_at"`
+	// CertificateID is the first VALID certificate recorded since the campaign started.
+	CertificateID *string    `gorm:"type:char(36)" json:"certificate_id"`
+	CompletedAt   *time.Time `json:"completed_at"`
+	// Stage is the escalation stage reached while overdue, empty before the first.
+	Stage       CampaignEscalationStage `gorm:"type:varchar(20);not null;default:'';index" json:"stage"`
+	EscalatedAt *time.Time              `json:"escalated_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (CampaignParticipant) TableName() string {
+	return "campaign_participants"
+}
@@ -0,0 +1,34 @@
+package domain
+
+import "time"
+
+// ConsentChannel records how a person accepted the terms.
+type ConsentChannel string
+
+const (
+	ConsentChannelMobile ConsentChannel = "MOBILE"
+	ConsentChannelWeb    ConsentChannel = "WEB"
+	ConsentChannelKiosk  ConsentChannel = "KIOSK"
+	ConsentChannelPaper  ConsentChannel = "PAPER"
+)
+
+// Consent records a person's acceptance of a version of the biometric
+// processing terms. It is keyed by NIK so it can be given before registration.
+type Consent struct {
+	ID           string         `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID     string         `gorm:"size:36;not null;default:'default';index" json:"-"`
+	NIK          string         `gorm:"size:20;index:idx_consent_nik_version" json:"nik"`
+	TermsVersion string         `gorm:"size:32;index:idx_consent_nik_version" json:"terms_version"`
+	Channel      ConsentChannel `gorm:"type:varchar(16)" json:"channel"`
+	// Evidence describes how the acceptance can be proven, such as the app
+	// session and device, or the reference of a signed paper form.
+	Evidence   string    `gorm:"type:text" json:"evidence"`
+	AcceptedAt time.Time `json:"accepted_at"`
+	RecordedBy string    `gorm:"size:100" json:"recorded_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (Consent) TableName() string {
+	return "consents"
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// DeathReportState tracks the confirmation of a death reported by the civil registry.
+type DeathReportState string
+
+const (
+	DeathReportPending   DeathReportState = "PENDING"
+	DeathReportConfirmed DeathReportState = "CONFIRMED"
+	DeathReportRejected  DeathReportState = "REJECTED"
+)
+
+// DeathReport is a death the civil registry reported for a member. The member
+// is marked DECEASED and their participant blocked as soon as it is recorded;
+// staff then confirm the report or reject it, which reverses both.
+type DeathReport struct {
+	ID       string `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID string `gorm:"size:36;not null;default:'default';index" json:"-"`
+	MemberID string `gorm:"type:char(36);index" json:"member_id"`
+	NIK      string `gorm:"size:20" json:"nik"`
+	// ParticipantID is the participant blocked because of the report.
+	ParticipantID *string          `gorm:"type:char(36)" json:"participant_id"`
+	DateOfDeath   *time.Time       `gorm:"type:date" json:"date_of_death"`
+	Reference     *string          `gorm:"size:100" json:"reference"`
+	State         DeathReportState `gorm:"type:varchar(16);index" json:"state"`
+	ReportedAt    time.Time        `json:"reported_at"`
+	DecidedBy     *string          `gorm:"size:100" json:"decided_by"`
+	DecidedAt     *time.Time       `json:"decided_at"`
+	DecisionNotes *string          `gorm:"type:text" json:"decision_notes"`
+}
+
+// TableName keeps the table naming explicit.
+func (DeathReport) TableName() string {
+	return "death_reports"
+}
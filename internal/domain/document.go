@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// DocumentOwnerType is the kind of record a document is filed under.
+type DocumentOwnerType string
+
+const (
+	DocumentOwnerParticipant     DocumentOwnerType = "participant"
+	DocumentOwnerLifeCertificate DocumentOwnerType = "life_certificate"
+	DocumentOwnerHomeVisit       DocumentOwnerType = "home_visit"
+)
+
+// DocumentCategory is what a document is evidence of.
+type DocumentCategory string
+
+const (
+	DocumentCategoryIdentityCard     DocumentCategory = "IDENTITY_CARD"
+	DocumentCategoryFamilyCard       DocumentCategory = "FAMILY_CARD"
+	DocumentCategoryDoctorLetter     DocumentCategory = "DOCTOR_LETTER"
+	DocumentCategoryHomeVisitPhoto   DocumentCategory = "HOME_VISIT_PHOTO"
+	DocumentCategoryPowerOfAttorney  DocumentCategory = "POWER_OF_ATTORNEY"
+	DocumentCategoryDeathCertificate DocumentCategory = "DEATH_CERTIFICATE"
+	DocumentCategoryOther            DocumentCategory = "OTHER"
+)
+
+// Document is a supporting document filed under a participant, a life
+// certificate or a home visit, its content kept in the blob store.
+type Document struct {
+	ID        string            `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID  string            `gorm:"size:36;not null;default:'default';index" json:"-"`
+	OwnerType DocumentOwnerType `gorm:"type:varchar(32);index:idx_documents_owner,priority:1" json:"owner_type"`
+	OwnerID   string            `gorm:"size:64;index:idx_documents_owner,priority:2" json:"owner_id"`
+	Category  DocumentCategory  `gorm:"type:varchar(32);index" json:"category"`
+	// Description says what the document shows, if the category does not.
+	Description *string   `gorm:"size:500" json:"description"`
+	FileName    string    `gorm:"size:255" json:"file_name"`
+	ContentType string    `gorm:"size:100" json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	SHA256      string    `gorm:"size:64" json:"sha256"`
+	StorageKey  string    `gorm:"size:255" json:"-"`
+	UploadedBy  string    `gorm:"size:100;index" json:"uploaded_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (Document) TableName() string {
+	return "documents"
+}
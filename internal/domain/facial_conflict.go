@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// FacialConflictState tracks a reviewer's decision on a facial conflict.
+type FacialConflictState string
+
+const (
+	FacialConflictPending   FacialConflictState = "PENDING"
+	FacialConflictConfirmed FacialConflictState = "CONFIRMED"
+	FacialConflictDismissed FacialConflictState = "DISMISSED"
+)
+
+// FacialConflict is an automatic attempt whose selfie FR Core matched, with
+// high confidence, to a face enrolled for another participant: someone may
+// be verifying on behalf of a pensioner who is not there.
+type FacialConflict struct {
+	ID       string `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID string `gorm:"size:36;not null;default:'default';index" json:"-"`
+	// CertificateID is the attempt, recorded INVALID, and ParticipantID the
+	// participant it was submitted for.
+	CertificateID string `gorm:"type:char(36);index" json:"certificate_id"`
+	ParticipantID string `gorm:"type:char(36);index" json:"participant_id"`
+	// MatchedParticipantID owns the FR Core label the selfie matched.
+	MatchedParticipantID string              `gorm:"type:char(36);index" json:"matched_participant_id"`
+	Label                string              `gorm:"size:128" json:"label"`
+	Distance             *float64            `json:"distance"`
+	Similarity           float64             `json:"similarity"`
+	State                FacialConflictState `gorm:"type:varchar(16);index" json:"state"`
+	DetectedAt           time.Time           `json:"detected_at"`
+	DecidedBy            *string             `gorm:"size:100" json:"decided_by"`
+	DecidedAt            *time.Time          `json:"decided_at"`
+	DecisionNotes        *string             `gorm:"type:text" json:"decision_notes"`
+}
+
+// TableName keeps the table naming explicit.
+func (FacialConflict) TableName() string {
+	return "facial_conflicts"
+}
@@ -2,10 +2,25 @@ package domain
 
 import "time"
 
-// FRIdentity maps FR Core labels to participants for verification.
+// FRIdentitySource describes how a face enrollment came to exist.
+type FRIdentitySource string
+
+const (
+	// FRIdentitySourceRegistration marks the face uploaded during participant registration.
+	FRIdentitySourceRegistration FRIdentitySource = "REGISTRATION"
+	// FRIdentitySourceEnrollment marks additional faces enrolled explicitly by an operator.
+	FRIdentitySourceEnrollment FRIdentitySource = "ENROLLMENT"
+	// FRIdentitySourceVerification marks aliases learned from high-confidence verifications.
+	FRIdentitySourceVerification FRIdentitySource = "VERIFICATION"
+)
+
+// FRIdentity maps FR Core labels to participants for verification. A participant
+// may own several labels, one per enrolled face.
 type FRIdentity struct {
-	Label         string    `gorm:"primaryKey;size:128" json:"label"`
-	ParticipantID string    `gorm:"type:char(36);index" json:"participant_id"`
-	ExternalRef   string    `gorm:"size:128" json:"external_ref"`
-	CreatedAt     time.Time `json:"created_at"`
+	Label         string           `gorm:"primaryKey;size:128" json:"label"`
+	TenantID      string           `gorm:"size:36;not null;default:'default';index" json:"-"`
+	ParticipantID string           `gorm:"type:char(36);index" json:"participant_id"`
+	ExternalRef   string           `gorm:"size:128" json:"external_ref"`
+	Source        FRIdentitySource `gorm:"type:varchar(16);default:REGISTRATION" json:"source"`
+	CreatedAt     time.Time        `json:"created_at"`
 }
@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// FRReconciliationStatus tracks the lifecycle of a reconciliation run.
+type FRReconciliationStatus string
+
+const (
+	FRReconciliationRunning   FRReconciliationStatus = "RUNNING"
+	FRReconciliationCompleted FRReconciliationStatus = "COMPLETED"
+	FRReconciliationFailed    FRReconciliationStatus = "FAILED"
+)
+
+// FRReconciliationRun records a comparison of FR Core enrollments against fr_identities.
+type FRReconciliationRun struct {
+	ID            string                 `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID      string                 `gorm:"size:36;not null;default:'default';index" json:"-"`
+	Status        FRReconciliationStatus `gorm:"type:varchar(16)" json:"status"`
+	DeleteOrphans bool                   `json:"delete_orphans"`
+	RemoteCount   int                    `json:"remote_count"`
+	OrphanCount   int                    `json:"orphan_count"`
+	DeletedCount  int                    `json:"deleted_count"`
+	// Orphans is a JSON array of the orphaned FR Core labels and their outcome.
+	Orphans    string     `gorm:"type:text" json:"orphans"`
+	Error      *string    `gorm:"type:text" json:"error"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (FRReconciliationRun) TableName() string {
+	return "fr_reconciliation_runs"
+}
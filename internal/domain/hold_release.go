@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// HoldReleaseStatus tracks whether the payment system acknowledged a hold release.
+type HoldReleaseStatus string
+
+const (
+	HoldReleasePending      HoldReleaseStatus = "PENDING"
+	HoldReleaseAcknowledged HoldReleaseStatus = "ACKNOWLEDGED"
+	HoldReleaseFailed       HoldReleaseStatus = "FAILED"
+)
+
+// HoldRelease is the callback asking the payment system to release a pension
+// payment hold once a certificate turns VALID, with its attempt history.
+// Payload is the rendered request body, so retries and re-sends repeat exactly
+// what was first sent. A certificate releases the hold at most once.
+type HoldRelease struct {
+	ID            string            `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID      string            `gorm:"size:36;not null;default:'default';index" json:"-"`
+	ParticipantID string            `gorm:"type:char(36);index" json:"participant_id"`
+	CertificateID string            `gorm:"type:char(36);uniqueIndex" json:"certificate_id"`
+	Payload       string            `gorm:"type:text" json:"payload"`
+	Status        HoldReleaseStatus `gorm:"type:varchar(16);index" json:"status"`
+	Attempts      int               `json:"attempts"`
+	NextAttemptAt time.Time         `gorm:"index" json:"next_attempt_at"`
+	ResponseCode  *int              `json:"response_code"`
+	// ResponseBody is the start of the last response; AckReference is the
+	// acknowledgment the payment system returned, when configured.
+	ResponseBody   *string    `gorm:"type:text" json:"response_body"`
+	AckReference   *string    `gorm:"size:200" json:"ack_reference"`
+	LastError      *string    `gorm:"type:text" json:"last_error"`
+	CreatedAt      time.Time  `gorm:"index" json:"created_at"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (HoldRelease) TableName() string {
+	return "hold_releases"
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// Holiday is a day off, nationwide or in one region, that due dates, review
+// SLAs and reminders skip along with the configured weekend.
+type Holiday struct {
+	ID       string `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID string `gorm:"size:36;not null;default:'default';index;uniqueIndex:idx_holidays_tenant_region_date,priority:1" json:"-"`
+	// Region is the province the holiday is observed in, matched against the
+	// member's province; empty for a national holiday.
+	Region    string    `gorm:"size:100;uniqueIndex:idx_holidays_tenant_region_date,priority:2" json:"region"`
+	Date      time.Time `gorm:"type:date;index;uniqueIndex:idx_holidays_tenant_region_date,priority:3" json:"date"`
+	Name      string    `gorm:"size:150" json:"name"`
+	CreatedBy string    `gorm:"size:100" json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (Holiday) TableName() string {
+	return "holidays"
+}
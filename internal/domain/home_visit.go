@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+// HomeVisitStatus tracks a home visit from request to outcome.
+type HomeVisitStatus string
+
+const (
+	HomeVisitRequested HomeVisitStatus = "REQUESTED"
+	HomeVisitScheduled HomeVisitStatus = "SCHEDULED"
+	// HomeVisitCompleted visits are linked to the verification made on them.
+	HomeVisitCompleted HomeVisitStatus = "COMPLETED"
+	HomeVisitMissed    HomeVisitStatus = "MISSED"
+	HomeVisitCancelled HomeVisitStatus = "CANCELLED"
+)
+
+// HomeVisit is an officer's visit to a participant who cannot verify
+// remotely, ending in a manual or proxy verification.
+type HomeVisit struct {
+	ID            string          `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID      string          `gorm:"size:36;not null;default:'default';index" json:"-"`
+	ParticipantID string          `gorm:"type:char(36);index" json:"participant_id"`
+	Status        HomeVisitStatus `gorm:"type:varchar(16);index" json:"status"`
+	Address       string          `gorm:"size:500" json:"address"`
+	// Reason is why the participant cannot verify remotely.
+	Reason string `gorm:"size:500" json:"reason"`
+	// RequestedStart and RequestedEnd are the slot the visit was requested for.
+	RequestedStart time.Time `json:"requested_start"`
+	RequestedEnd   time.Time `json:"requested_end"`
+	// ScheduledAt, the officer and the branch are set when the visit is scheduled.
+	ScheduledAt *time.Time `gorm:"index" json:"scheduled_at"`
+	OfficerID   *string    `gorm:"size:64;index" json:"officer_id"`
+	OfficerName *string    `gorm:"size:150" json:"officer_name"`
+	BranchID    *string    `gorm:"size:64;index" json:"branch_id"`
+	// CertificateID is the manual or proxy verification made on the visit.
+	CertificateID *string    `gorm:"type:char(36);index" json:"certificate_id"`
+	CompletedAt   *time.Time `json:"completed_at"`
+	// Notes explain why a visit was missed or cancelled.
+	Notes       *string   `gorm:"type:text" json:"notes"`
+	RequestedBy string    `gorm:"size:100" json:"requested_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (HomeVisit) TableName() string {
+	return "home_visits"
+}
+
+// Open reports whether the visit may still take place.
+func (v *HomeVisit) Open() bool {
+	return v.Status == HomeVisitRequested || v.Status == HomeVisitScheduled
+}
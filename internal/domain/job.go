@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// JobStatus tracks a background job through its lifecycle.
+type JobStatus string
+
+const (
+	// JobStatusQueued waits for RunAt and a free worker.
+	JobStatusQueued JobStatus = "QUEUED"
+	// JobStatusRunning is held by a worker until LeaseUntil; an expired lease is picked up again.
+	JobStatusRunning   JobStatus = "RUNNING"
+	JobStatusSucceeded JobStatus = "SUCCEEDED"
+	// JobStatusFailed has used up its attempts.
+	JobStatusFailed    JobStatus = "FAILED"
+	JobStatusCancelled JobStatus = "CANCELLED"
+)
+
+// Job is a unit of asynchronous work run by the worker pool.
+type Job struct {
+	ID       string    `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID string    `gorm:"size:36;not null;default:'default';index" json:"-"`
+	Type     string    `gorm:"size:64;index" json:"type"`
+	Status   JobStatus `gorm:"type:varchar(16);index:idx_jobs_due" json:"status"`
+	// Payload is the JSON input handed to the job's handler.
+	Payload     string `gorm:"type:text" json:"payload"`
+	Attempts    int    `json:"attempts"`
+	MaxAttempts int    `json:"max_attempts"`
+	// RunAt is when a queued job becomes due, pushed back after each failed attempt.
+	RunAt      time.Time  `gorm:"index:idx_jobs_due" json:"run_at"`
+	LeaseUntil *time.Time `json:"lease_until"`
+	LastError  *string    `gorm:"type:text" json:"last_error"`
+	// Result is the JSON output of the last successful attempt.
+	Result     *string    `gorm:"type:text" json:"result"`
+	CreatedBy  string     `gorm:"size:100" json:"created_by"`
+	CreatedAt  time.Time  `gorm:"index" json:"created_at"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (Job) TableName() string {
+	return "jobs"
+}
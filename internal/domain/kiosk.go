@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// Kiosk is a shared device in a branch office. Operators sign in with their
+// own credentials and the device proves itself with its key; only the
+// SHA-256 of the key is stored and KeyPrefix identifies it in listings.
+type Kiosk struct {
+	ID         string     `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID   string     `gorm:"size:36;not null;default:'default';index" json:"-"`
+	Name       string     `gorm:"size:100" json:"name"`
+	BranchID   string     `gorm:"size:64;index" json:"branch_id"`
+	KeyHash    string     `gorm:"size:64;uniqueIndex" json:"-"`
+	KeyPrefix  string     `gorm:"size:16" json:"key_prefix"`
+	CreatedBy  string     `gorm:"size:100" json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (Kiosk) TableName() string {
+	return "kiosks"
+}
@@ -0,0 +1,54 @@
+package domain
+
+import "time"
+
+// KTPCheckStatus tracks the comparison of a registration with its KTP photo.
+type KTPCheckStatus string
+
+const (
+	// KTPCheckMatched cards agree with every submitted value.
+	KTPCheckMatched KTPCheckStatus = "MATCHED"
+	// KTPCheckPending checks have warnings for an operator to confirm.
+	KTPCheckPending KTPCheckStatus = "PENDING"
+	// KTPCheckUnreadable cards could not be read; an operator compares them by eye.
+	KTPCheckUnreadable KTPCheckStatus = "UNREADABLE"
+	KTPCheckConfirmed  KTPCheckStatus = "CONFIRMED"
+)
+
+// KTPCheck is what OCR read from the KTP (ID card) photo submitted at
+// registration, how its portrait compares with the registration selfie, and
+// where the card disagrees with the registration.
+type KTPCheck struct {
+	ID            string `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID      string `gorm:"size:36;not null;default:'default';index" json:"-"`
+	ParticipantID string `gorm:"type:char(36);index" json:"participant_id"`
+	// DocumentID is the KTP photo, filed under the participant.
+	DocumentID string         `gorm:"type:char(36)" json:"document_id"`
+	Status     KTPCheckStatus `gorm:"type:varchar(16);index" json:"status"`
+	// Provider is the OCR provider that read the card.
+	Provider string `gorm:"size:20" json:"provider"`
+	// NIK, Name and BirthDate are the values read from the card, nil when unreadable.
+	NIK       *string    `gorm:"size:20" json:"nik"`
+	Name      *string    `gorm:"size:150" json:"name"`
+	BirthDate *time.Time `gorm:"type:date" json:"birth_date"`
+	// Warnings is a JSON array of the fields that disagree with the form,
+	// each with the submitted and the extracted value.
+	Warnings string `gorm:"type:text" json:"warnings"`
+	// Error explains why the card could not be read.
+	Error *string `gorm:"type:text" json:"error"`
+	// FaceSimilarity scores the card's portrait against the registration
+	// selfie, nil when the faces were not compared.
+	FaceSimilarity *float64 `json:"face_similarity"`
+	// FaceError explains why the faces could not be compared.
+	FaceError   *string    `gorm:"type:text" json:"face_error"`
+	CheckedBy   string     `gorm:"size:100" json:"checked_by"`
+	CheckedAt   time.Time  `gorm:"index" json:"checked_at"`
+	ConfirmedBy *string    `gorm:"size:100" json:"confirmed_by"`
+	ConfirmedAt *time.Time `json:"confirmed_at"`
+	Notes       *string    `gorm:"type:text" json:"notes"`
+}
+
+// TableName keeps the table naming explicit.
+func (KTPCheck) TableName() string {
+	return "ktp_checks"
+}
@@ -2,23 +2,52 @@ package domain
 
 import "time"
 
+// MemberStatus captures the life status of a member.
+type MemberStatus string
+
+const (
+	MemberStatusActive   MemberStatus = "ACTIVE"
+	MemberStatusDeceased MemberStatus = "DECEASED"
+)
+
 // Member represents an individual enrolled in the programme.
 type Member struct {
-	ID           string    `gorm:"type:char(36);primaryKey" json:"id"`
-	NIK          string    `gorm:"size:20;uniqueIndex" json:"nik"`
-	NomorPeserta string    `gorm:"size:50;uniqueIndex" json:"nomor_peserta"`
-	BirthDate    time.Time `gorm:"type:date" json:"birth_date"`
-	FullName     string    `gorm:"size:150;column:fullname" json:"fullname"`
-	Address      string    `gorm:"size:255" json:"address"`
-	City         string    `gorm:"size:100" json:"city"`
-	Province     string    `gorm:"size:100" json:"province"`
-	PhoneNumber  string    `gorm:"size:30;column:phone_number" json:"phone_number"`
-	Email        string    `gorm:"size:120" json:"email"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           string       `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID     string       `gorm:"size:36;not null;default:'default';index;uniqueIndex:idx_members_tenant_nik,priority:1;uniqueIndex:idx_members_tenant_nomor_peserta,priority:1" json:"-"`
+	NIK          string       `gorm:"size:20;uniqueIndex:idx_members_tenant_nik,priority:2" json:"nik"`
+	NomorPeserta string       `gorm:"size:50;uniqueIndex:idx_members_tenant_nomor_peserta,priority:2" json:"nomor_peserta"`
+	BirthDate    time.Time    `gorm:"type:date" json:"birth_date"`
+	FullName     string       `gorm:"size:150;column:fullname" json:"fullname"`
+	Address      string       `gorm:"size:255" json:"address"`
+	City         string       `gorm:"size:100" json:"city"`
+	Province     string       `gorm:"size:100" json:"province"`
+	PhoneNumber  string       `gorm:"size:30;column:phone_number" json:"phone_number"`
+	Email        string       `gorm:"size:120" json:"email"`
+	Status       MemberStatus `gorm:"type:varchar(16);default:ACTIVE" json:"status"`
+	// ErasedAt is set once the member's personal data has been anonymized on request.
+	ErasedAt  *time.Time `json:"erased_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
 }
 
 // TableName keeps the table naming explicit.
 func (Member) TableName() string {
 	return "members"
 }
+
+// MemberMerge records a merge of a duplicate member into a surviving record.
+type MemberMerge struct {
+	ID                string    `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID          string    `gorm:"size:36;not null;default:'default';index" json:"-"`
+	SourceMemberID    string    `gorm:"type:char(36);index" json:"source_member_id"`
+	TargetMemberID    string    `gorm:"type:char(36);index" json:"target_member_id"`
+	SourceSnapshot    string    `gorm:"type:text" json:"source_snapshot"`
+	ParticipantsMoved int       `json:"participants_moved"`
+	Notes             string    `gorm:"type:text" json:"notes"`
+	MergedAt          time.Time `json:"merged_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (MemberMerge) TableName() string {
+	return "member_merges"
+}
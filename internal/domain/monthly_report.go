@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// MonthlyReport is the stored regulator report of one month.
+type MonthlyReport struct {
+	TenantID string `gorm:"size:36;primaryKey;default:'default'" json:"-"`
+	// Period is the month reported, as YYYY-MM.
+	Period string `gorm:"size:7;primaryKey" json:"period"`
+	// Format is pdf or xlsx.
+	Format string `gorm:"size:8" json:"format"`
+	Path   string `gorm:"type:text" json:"-"`
+	// Signature is the report's detached JWS, empty when signing is disabled.
+	Signature   string    `gorm:"type:text" json:"signature,omitempty"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (MonthlyReport) TableName() string {
+	return "monthly_reports"
+}
@@ -0,0 +1,114 @@
+package domain
+
+import "time"
+
+// NotificationStatus tracks a notification delivery.
+type NotificationStatus string
+
+const (
+	// NotificationPending waits for its first send attempt.
+	NotificationPending NotificationStatus = "PENDING"
+	NotificationSent    NotificationStatus = "SENT"
+	// NotificationFailed means the latest attempt failed; the delivery job may still retry it.
+	NotificationFailed NotificationStatus = "FAILED"
+	// NotificationSkipped was not sent, e.g. because the member has no email address.
+	NotificationSkipped NotificationStatus = "SKIPPED"
+)
+
+// NotificationDelivery logs one notification to a participant, rendered when the triggering event was received.
+type NotificationDelivery struct {
+	ID       string `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID string `gorm:"size:36;not null;default:'default';index" json:"-"`
+	// EventID, Template, Channel and Recipient identify the notification, so redelivered events are not sent twice.
+	EventID       string `gorm:"type:char(36);uniqueIndex:idx_notification_recipient" json:"event_id"`
+	Template      string `gorm:"size:64;uniqueIndex:idx_notification_recipient" json:"template"`
+	Channel       string `gorm:"size:16;uniqueIndex:idx_notification_recipient" json:"channel"`
+	ParticipantID string `gorm:"type:char(36);index" json:"participant_id"`
+	// Recipient is the email address or phone number, or the device ID for push notifications.
+	Recipient string             `gorm:"size:120;uniqueIndex:idx_notification_recipient" json:"recipient"`
+	Subject   string             `gorm:"size:255" json:"subject"`
+	Body      string             `gorm:"type:text" json:"body"`
+	Status    NotificationStatus `gorm:"type:varchar(16);index" json:"status"`
+	Attempts  int                `json:"attempts"`
+	// ProviderMessageID is the SMTP Message-ID or the SMS, WhatsApp or FCM message ID of the last successful send.
+	ProviderMessageID *string `gorm:"size:255" json:"provider_message_id"`
+	LastError         *string `gorm:"type:text" json:"last_error"`
+	// BatchID is the bulk send, such as a campaign kickoff, the delivery was queued by.
+	BatchID   *string    `gorm:"type:char(36);index" json:"batch_id"`
+	CreatedAt time.Time  `gorm:"index" json:"created_at"`
+	SentAt    *time.Time `json:"sent_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (NotificationDelivery) TableName() string {
+	return "notification_deliveries"
+}
+
+// NotificationTemplate overrides a file or built-in notification template.
+type NotificationTemplate struct {
+	TenantID  string    `gorm:"size:36;primaryKey;default:'default'" json:"-"`
+	Name      string    `gorm:"size:64;primaryKey" json:"name"`
+	Subject   string    `gorm:"type:text" json:"subject"`
+	Body      string    `gorm:"type:text" json:"body"`
+	UpdatedBy string    `gorm:"size:100" json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (NotificationTemplate) TableName() string {
+	return "notification_templates"
+}
+
+// DevicePlatform is the operating system of a registered device.
+type DevicePlatform string
+
+const (
+	DevicePlatformAndroid DevicePlatform = "ANDROID"
+	DevicePlatformIOS     DevicePlatform = "IOS"
+	DevicePlatformWeb     DevicePlatform = "WEB"
+)
+
+// ParticipantDevice is a mobile app installation receiving push notifications for a participant.
+type ParticipantDevice struct {
+	ID            string         `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID      string         `gorm:"size:36;not null;default:'default';index" json:"-"`
+	ParticipantID string         `gorm:"type:char(36);index" json:"participant_id"`
+	Token         string         `gorm:"size:512;uniqueIndex" json:"-"`
+	Platform      DevicePlatform `gorm:"type:varchar(16)" json:"platform"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	// InvalidatedAt is set when FCM reports the token as no longer registered.
+	InvalidatedAt *time.Time `json:"invalidated_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (ParticipantDevice) TableName() string {
+	return "participant_devices"
+}
+
+// NotificationPreference holds a member's choices about notifications. A
+// member without a row gets the defaults: every channel, the base language,
+// no quiet hours and no opt-outs.
+type NotificationPreference struct {
+	MemberID string `gorm:"type:char(36);primaryKey" json:"member_id"`
+	TenantID string `gorm:"size:36;not null;default:'default';index" json:"-"`
+	// Channels is a comma separated list of the channels to use, in order of
+	// preference for email, sms and whatsapp; push is used when listed. Empty allows all.
+	Channels string `gorm:"size:64" json:"channels"`
+	Language string `gorm:"size:8" json:"language"`
+	// QuietStart and QuietEnd bound a daily HH:MM window, in the notification
+	// time zone, during which notifications are held until it ends.
+	QuietStart      *string `gorm:"size:5" json:"quiet_start"`
+	QuietEnd        *string `gorm:"size:5" json:"quiet_end"`
+	OptOutReminders bool    `json:"opt_out_reminders"`
+	OptOutResults   bool    `json:"opt_out_results"`
+	// EmailOptOut is set by the unsubscribe link in notification emails.
+	EmailOptOut bool      `json:"email_opt_out"`
+	UpdatedBy   string    `gorm:"size:100" json:"updated_by"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// OfficerStatus tells whether an officer may still perform verifications.
+type OfficerStatus string
+
+const (
+	OfficerActive   OfficerStatus = "ACTIVE"
+	OfficerInactive OfficerStatus = "INACTIVE"
+)
+
+// Officer is a branch or field agent who performs manual, proxy and kiosk
+// verifications. Certificates reference the officer by ID.
+type Officer struct {
+	ID       string `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID string `gorm:"size:36;not null;default:'default';index;uniqueIndex:idx_officers_tenant_code,priority:1;uniqueIndex:idx_officers_tenant_username,priority:1" json:"-"`
+	// Code is the officer's employee number.
+	Code     string `gorm:"size:64;uniqueIndex:idx_officers_tenant_code,priority:2" json:"code"`
+	Name     string `gorm:"size:150" json:"name"`
+	BranchID string `gorm:"size:64;index" json:"branch_id"`
+	Region   string `gorm:"size:100;index" json:"region"`
+	// Username is the account the officer signs in with, which ties kiosk
+	// operators to their officer record.
+	Username *string `gorm:"size:100;uniqueIndex:idx_officers_tenant_username,priority:2" json:"username"`
+	// CredentialNumber is the officer's field credential (badge or assignment
+	// letter); it is valid through the day CredentialExpiresAt, when set.
+	CredentialNumber    string        `gorm:"size:64" json:"credential_number"`
+	CredentialExpiresAt *time.Time    `json:"credential_expires_at"`
+	Status              OfficerStatus `gorm:"type:varchar(16);default:ACTIVE;index" json:"status"`
+	CreatedBy           string        `gorm:"size:100" json:"created_by"`
+	CreatedAt           time.Time     `json:"created_at"`
+	UpdatedAt           time.Time     `json:"updated_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (Officer) TableName() string {
+	return "officers"
+}
@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+// OutboxEvent is a domain event stored in the same transaction as the change
+// that produced it, waiting to be published.
+type OutboxEvent struct {
+	ID            string     `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID      string     `gorm:"size:36;not null;default:'default';index" json:"-"`
+	Type          string     `gorm:"size:64;index" json:"type"`
+	Payload       string     `gorm:"type:text" json:"payload"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"index" json:"next_attempt_at"`
+	LastError     *string    `gorm:"type:text" json:"last_error"`
+	CreatedAt     time.Time  `gorm:"index" json:"created_at"`
+	PublishedAt   *time.Time `gorm:"index" json:"published_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (OutboxEvent) TableName() string {
+	return "event_outbox"
+}
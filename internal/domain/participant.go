@@ -11,30 +11,189 @@ const (
 	LifeCertificateStatusReview  LifeCertificateStatus = "REVIEW"
 )
 
+// ParticipantStatus controls whether a participant may submit verifications.
+type ParticipantStatus string
+
+const (
+	ParticipantStatusActive    ParticipantStatus = "ACTIVE"
+	ParticipantStatusSuspended ParticipantStatus = "SUSPENDED"
+	ParticipantStatusBlocked   ParticipantStatus = "BLOCKED"
+)
+
+// VerificationMethod records how a life certificate was produced.
+type VerificationMethod string
+
+const (
+	// VerificationMethodAutomatic is a selfie checked by liveness and FR Core.
+	VerificationMethodAutomatic VerificationMethod = "AUTOMATIC"
+	// VerificationMethodManual is an in-person verification recorded by an officer.
+	VerificationMethodManual VerificationMethod = "MANUAL"
+	// VerificationMethodProxy is a verification submitted on behalf of a
+	// bedridden participant, decided in senior review.
+	VerificationMethodProxy VerificationMethod = "PROXY"
+)
+
+// VerificationChannel is where an attempt was captured, for reporting.
+type VerificationChannel string
+
+const (
+	// VerificationChannelMobile attempts were submitted from the participant's
+	// or an operator's device: the app, self-service or an integration.
+	VerificationChannelMobile VerificationChannel = "MOBILE"
+	// VerificationChannelKiosk attempts were captured on a branch kiosk.
+	VerificationChannelKiosk VerificationChannel = "KIOSK"
+	// VerificationChannelField verifications were made by an officer on a home visit.
+	VerificationChannelField VerificationChannel = "FIELD"
+	// VerificationChannelProxy verifications were submitted on a participant's behalf.
+	VerificationChannelProxy VerificationChannel = "PROXY"
+	// VerificationChannelManual verifications were made by an officer in person.
+	VerificationChannelManual VerificationChannel = "MANUAL"
+)
+
+// ProxyKind is who submitted a proxy verification.
+type ProxyKind string
+
+const (
+	ProxyKindFamily  ProxyKind = "FAMILY"
+	ProxyKindOfficer ProxyKind = "OFFICER"
+)
+
+// DocumentKind is the evidence a certificate document stands for.
+type DocumentKind string
+
+const (
+	DocumentKindDoctorLetter   DocumentKind = "DOCTOR_LETTER"
+	DocumentKindHomeVisitPhoto DocumentKind = "HOME_VISIT_PHOTO"
+)
+
 // Participant represents a pension participant tracked by the service.
 type Participant struct {
-	ID            string    `gorm:"type:char(36);primaryKey" json:"participant_id"`
-	NIK           string    `gorm:"size:20;uniqueIndex" json:"nik"`
-	Name          string    `gorm:"size:100" json:"name"`
-	FRLabel       string    `gorm:"column:fr_label;size:64;uniqueIndex" json:"fr_label"`
-	FRExternalRef string    `gorm:"column:fr_external_ref;size:64;uniqueIndex" json:"fr_external_ref"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID       string  `gorm:"type:char(36);primaryKey" json:"participant_id"`
+	TenantID string  `gorm:"size:36;not null;default:'default';index;uniqueIndex:idx_participants_tenant_nik,priority:1" json:"-"`
+	NIK      string  `gorm:"size:20;uniqueIndex:idx_participants_tenant_nik,priority:2" json:"nik"`
+	MemberID *string `gorm:"type:char(36);index" json:"member_id"`
+	Name     string  `gorm:"size:100" json:"name"`
+	// PhoneNumber, Email and Address reach participants without a member
+	// record; a linked member's own contact details take precedence.
+	PhoneNumber     string            `gorm:"size:30;column:phone_number" json:"phone_number"`
+	Email           string            `gorm:"size:120" json:"email"`
+	Address         string            `gorm:"size:255" json:"address"`
+	FRExternalRef   string            `gorm:"column:fr_external_ref;size:64;uniqueIndex" json:"fr_external_ref"`
+	Status          ParticipantStatus `gorm:"type:varchar(16);default:ACTIVE;index" json:"status"`
+	StatusReason    *string           `gorm:"type:text" json:"status_reason"`
+	StatusChangedAt *time.Time        `json:"status_changed_at"`
+	// Fund is the pension fund the participant belongs to.
+	Fund *string `gorm:"size:64;index" json:"fund"`
+	// VerificationProfileID overrides the fund's verification profile for this participant.
+	VerificationProfileID *string   `gorm:"type:char(36);index" json:"verification_profile_id"`
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
 }
 
 // LifeCertificate represents a single verification attempt.
 type LifeCertificate struct {
 	ID            string                `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID      string                `gorm:"size:36;not null;default:'default';index" json:"-"`
 	ParticipantID string                `gorm:"type:char(36);index" json:"participant_id"`
 	SelfiePath    string                `gorm:"type:text" json:"selfie_path"`
 	Status        LifeCertificateStatus `gorm:"type:varchar(16)" json:"status"`
-	Distance      *float64              `json:"distance"`
-	Similarity    *float64              `json:"similarity"`
-	VerifiedAt    time.Time             `json:"verified_at"`
-	Notes         *string               `json:"notes"`
+	Method        VerificationMethod    `gorm:"type:varchar(16);default:AUTOMATIC" json:"method"`
+	// Channel is where the attempt was captured, CampaignID the unfinished
+	// campaign it counted towards, if any, and SessionID the verification
+	// session it presented. None of them is a foreign key.
+	Channel    VerificationChannel `gorm:"type:varchar(16);index" json:"channel"`
+	CampaignID *string             `gorm:"type:char(36);index" json:"campaign_id"`
+	SessionID  *string             `gorm:"type:char(36);index" json:"session_id"`
+	Distance   *float64            `json:"distance"`
+	Similarity *float64            `json:"similarity"`
+	VerifiedAt time.Time           `json:"verified_at"`
+	Notes      *string             `json:"notes"`
+	// Location names the kiosk or office where the attempt was captured.
+	Location *string `gorm:"size:100;index" json:"location"`
+	// SelfieHash is the perceptual hash of the submitted selfie, used to spot replayed photos.
+	SelfieHash *string `gorm:"size:16;index" json:"selfie_hash"`
+	// Capture time and GPS position from the selfie's EXIF, and why they did not fit the submission.
+	CapturedAt       *time.Time `json:"captured_at"`
+	CaptureLatitude  *float64   `json:"capture_latitude"`
+	CaptureLongitude *float64   `json:"capture_longitude"`
+	CaptureFindings  *string    `gorm:"type:text" json:"capture_findings"`
+	// Latitude and Longitude are where the participant declared they were.
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+	// ClientIP and DeviceID identify where an automatic attempt was submitted from.
+	ClientIP *string `gorm:"size:45;index" json:"client_ip"`
+	DeviceID *string `gorm:"size:100;index" json:"device_id"`
+	// DeviceModel and DeviceOS fingerprint the device and DeviceTrust is how
+	// well it was known when the attempt was submitted.
+	DeviceModel *string      `gorm:"size:100" json:"device_model"`
+	DeviceOS    *string      `gorm:"size:50" json:"device_os"`
+	DeviceTrust *DeviceTrust `gorm:"type:varchar(16)" json:"device_trust"`
+	// RiskScore rates an automatic attempt from 0 to 100 for fraud patterns
+	// and RiskSignals lists the patterns that raised it.
+	RiskScore   *int    `json:"risk_score"`
+	RiskSignals *string `gorm:"type:text" json:"risk_signals"`
+	// DecisionTrace is the JSON trace of the decision rules that made an
+	// automatic attempt VALID or INVALID.
+	DecisionTrace *string `gorm:"type:text" json:"decision_trace"`
+	// StepUp is the JSON of the first step, and why it fell in the gray zone,
+	// when the attempt completed a step-up challenge.
+	StepUp *string `gorm:"type:text" json:"step_up"`
+	// Officer and operator accountability for non-automatic methods and kiosk captures.
+	OfficerID   *string `gorm:"size:64" json:"officer_id"`
+	OfficerName *string `gorm:"size:150" json:"officer_name"`
+	RecordedBy  *string `gorm:"size:100" json:"recorded_by"`
+	// Proxy verifications name who submitted them and, for family members,
+	// their relationship to the participant.
+	ProxyKind         *ProxyKind `gorm:"type:varchar(16)" json:"proxy_kind"`
+	ProxyName         *string    `gorm:"size:150" json:"proxy_name"`
+	ProxyRelationship *string    `gorm:"size:50" json:"proxy_relationship"`
+	// KioskID names the branch kiosk the selfie was captured on. BranchID is
+	// the kiosk's branch, else the home visit's, else the performing officer's.
+	KioskID  *string `gorm:"type:char(36);index" json:"kiosk_id"`
+	BranchID *string `gorm:"size:64;index" json:"branch_id"`
+	// GeofenceDistance is how far from its branch's position a kiosk or officer
+	// submission was made. OutsideGeofence flags submissions beyond the branch
+	// radius, without a position or for an unregistered branch, and
+	// GeofenceFinding says which.
+	GeofenceDistance *float64 `json:"geofence_distance"`
+	OutsideGeofence  bool     `gorm:"not null;default:false;index" json:"outside_geofence"`
+	GeofenceFinding  *string  `gorm:"size:200" json:"geofence_finding"`
+	// DocumentPath is the blob key of the official PDF certificate of a VALID
+	// attempt and DocumentSignature its detached JWS when signing is enabled.
+	DocumentPath      string `gorm:"type:text" json:"document_path"`
+	DocumentSignature string `gorm:"type:text" json:"document_signature"`
+	// Manual review bookkeeping for REVIEW attempts.
+	AssignedTo  *string    `gorm:"size:100;index" json:"assigned_to"`
+	AssignedAt  *time.Time `json:"assigned_at"`
+	ReviewDueAt *time.Time `gorm:"index" json:"review_due_at"`
+	ReviewedBy  *string    `gorm:"size:100" json:"reviewed_by"`
+	ReviewedAt  *time.Time `json:"reviewed_at"`
+	ReviewNotes *string    `gorm:"type:text" json:"review_notes"`
+	// SeniorReview restricts the review to senior reviewers.
+	SeniorReview bool `gorm:"not null;default:false;index" json:"senior_review"`
 }
 
 // TableName overrides gorm pluralisation for consistency.
 func (LifeCertificate) TableName() string {
 	return "life_certificate"
 }
+
+// CertificateDocument is a supporting document attached to a life certificate.
+type CertificateDocument struct {
+	ID            string    `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID      string    `gorm:"size:36;not null;default:'default';index" json:"-"`
+	CertificateID string    `gorm:"type:char(36);index" json:"certificate_id"`
+	FileName      string    `gorm:"size:255" json:"file_name"`
+	ContentType   string    `gorm:"size:100" json:"content_type"`
+	SizeBytes     int64     `json:"size_bytes"`
+	StorageKey    string    `gorm:"size:255" json:"-"`
+	UploadedBy    string    `gorm:"size:100" json:"uploaded_by"`
+	CreatedAt     time.Time `json:"created_at"`
+	// Kind is empty for supporting documents of no particular kind.
+	Kind DocumentKind `gorm:"type:varchar(20)" json:"kind"`
+}
+
+// TableName keeps the table naming explicit.
+func (CertificateDocument) TableName() string {
+	return "certificate_documents"
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// Partner API scopes.
+const (
+	// PartnerScopeStatus allows looking up a participant's status by NIK.
+	PartnerScopeStatus = "status:read"
+	// PartnerScopeChanges allows reading the feed of status changes.
+	PartnerScopeChanges = "changes:read"
+)
+
+// PartnerAPIKey lets an external institution read certificate statuses
+// through the partner API. Only the SHA-256 of the key is stored; KeyPrefix
+// identifies the key in listings.
+type PartnerAPIKey struct {
+	ID        string `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID  string `gorm:"size:36;not null;default:'default';index" json:"-"`
+	Name      string `gorm:"size:100" json:"name"`
+	KeyHash   string `gorm:"size:64;uniqueIndex" json:"-"`
+	KeyPrefix string `gorm:"size:16" json:"key_prefix"`
+	// Scopes is a comma separated list of granted scopes.
+	Scopes string `gorm:"type:text" json:"scopes"`
+	// Fund restricts the key to participants of one fund; nil allows every fund.
+	Fund       *string    `gorm:"size:64" json:"fund"`
+	CreatedBy  string     `gorm:"size:100" json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (PartnerAPIKey) TableName() string {
+	return "partner_api_keys"
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// PaymentPushTransition is the certificate status change reported to the payment system.
+type PaymentPushTransition string
+
+const (
+	PaymentPushValid   PaymentPushTransition = "VALID"
+	PaymentPushExpired PaymentPushTransition = "EXPIRED"
+)
+
+// PaymentPushStatus tracks whether the payment system acknowledged a push.
+type PaymentPushStatus string
+
+const (
+	PaymentPushPending      PaymentPushStatus = "PENDING"
+	PaymentPushAcknowledged PaymentPushStatus = "ACKNOWLEDGED"
+	PaymentPushFailed       PaymentPushStatus = "FAILED"
+)
+
+// PaymentPush is one status transition queued for the payment system, with its attempt history.
+// A certificate is pushed at most once per transition.
+type PaymentPush struct {
+	ID             string                `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID       string                `gorm:"size:36;not null;default:'default';index" json:"-"`
+	ParticipantID  string                `gorm:"type:char(36);index" json:"participant_id"`
+	CertificateID  string                `gorm:"type:char(36);uniqueIndex:idx_payment_push_certificate_transition" json:"certificate_id"`
+	Transition     PaymentPushTransition `gorm:"type:varchar(16);uniqueIndex:idx_payment_push_certificate_transition" json:"transition"`
+	Payload        string                `gorm:"type:text" json:"payload"`
+	Status         PaymentPushStatus     `gorm:"type:varchar(16);index" json:"status"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  time.Time             `gorm:"index" json:"next_attempt_at"`
+	ResponseCode   *int                  `json:"response_code"`
+	LastError      *string               `gorm:"type:text" json:"last_error"`
+	CreatedAt      time.Time             `gorm:"index" json:"created_at"`
+	AcknowledgedAt *time.Time            `json:"acknowledged_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (PaymentPush) TableName() string {
+	return "payment_pushes"
+}
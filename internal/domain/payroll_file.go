@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// PayrollFileStatus is the outcome of one payroll file delivery.
+type PayrollFileStatus string
+
+const (
+	PayrollFileDelivered PayrollFileStatus = "DELIVERED"
+	PayrollFileFailed    PayrollFileStatus = "FAILED"
+)
+
+// PayrollFileDelivery logs one verification status file generated for a
+// legacy payroll system and uploaded over SFTP. Checksum is the SHA-256 of the
+// file as uploaded, so the receiving side can confirm what it was sent.
+type PayrollFileDelivery struct {
+	ID         string            `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID   string            `gorm:"size:36;not null;default:'default';index" json:"-"`
+	FileName   string            `gorm:"size:200" json:"file_name"`
+	RemotePath string            `gorm:"type:text" json:"remote_path"`
+	Format     string            `gorm:"size:16" json:"format"`
+	Fund       *string           `gorm:"size:64" json:"fund"`
+	Records    int64             `json:"records"`
+	SizeBytes  int64             `json:"size_bytes"`
+	Checksum   string            `gorm:"size:64" json:"checksum_sha256"`
+	Status     PayrollFileStatus `gorm:"type:varchar(16);index" json:"status"`
+	Error      *string           `gorm:"type:text" json:"error"`
+	StartedAt  time.Time         `gorm:"index" json:"started_at"`
+	FinishedAt *time.Time        `json:"finished_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (PayrollFileDelivery) TableName() string {
+	return "payroll_file_deliveries"
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// ReminderKind is why a participant was reminded.
+type ReminderKind string
+
+const (
+	// ReminderKindExpiring is sent when the latest VALID certificate is about to lapse or has lapsed.
+	ReminderKindExpiring ReminderKind = "EXPIRING"
+	// ReminderKindCampaign is sent while the participant has not completed a campaign.
+	ReminderKindCampaign ReminderKind = "CAMPAIGN"
+)
+
+// Reminder records a reminder dispatched to a participant; the history drives deduplication.
+type Reminder struct {
+	ID            string       `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID      string       `gorm:"size:36;not null;default:'default';index" json:"-"`
+	ParticipantID string       `gorm:"type:char(36);index:idx_reminders_target" json:"participant_id"`
+	Kind          ReminderKind `gorm:"type:varchar(16);index:idx_reminders_target" json:"kind"`
+	// Reference is the expiring certificate or the campaign the reminder is about.
+	Reference string    `gorm:"type:char(36);index:idx_reminders_target" json:"reference"`
+	DueAt     time.Time `json:"due_at"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (Reminder) TableName() string {
+	return "reminders"
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// ScheduledTask records the state of a cron task shared by every replica.
+type ScheduledTask struct {
+	Name     string `gorm:"size:64;primaryKey" json:"name"`
+	Schedule string `gorm:"size:100" json:"schedule"`
+	// LastSlot is the latest scheduled time claimed by a replica; each slot runs once.
+	LastSlot *time.Time `json:"last_slot"`
+	// Owner identifies the replica running or that last ran the task.
+	Owner *string `gorm:"size:200" json:"owner"`
+	// LeaseUntil is set while a run is in progress and keeps other replicas out.
+	LeaseUntil     *time.Time `json:"lease_until"`
+	LastStartedAt  *time.Time `json:"last_started_at"`
+	LastFinishedAt *time.Time `json:"last_finished_at"`
+	LastError      *string    `gorm:"type:text" json:"last_error"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (ScheduledTask) TableName() string {
+	return "scheduled_tasks"
+}
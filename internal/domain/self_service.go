@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// SelfServiceOTP is a one-time code sent to a member so their participant can
+// verify without an operator. Only an HMAC of the code is stored.
+type SelfServiceOTP struct {
+	ID            string `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID      string `gorm:"size:36;not null;default:'default';index" json:"-"`
+	ParticipantID string `gorm:"type:char(36);index" json:"participant_id"`
+	CodeHash      string `gorm:"size:64" json:"-"`
+	// Channel and Recipient are where the code was sent.
+	Channel    string     `gorm:"size:20" json:"channel"`
+	Recipient  string     `gorm:"size:255" json:"recipient"`
+	Attempts   int        `json:"attempts"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	ConsumedAt *time.Time `json:"consumed_at"`
+	CreatedAt  time.Time  `gorm:"index" json:"created_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (SelfServiceOTP) TableName() string {
+	return "self_service_otps"
+}
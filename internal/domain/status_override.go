@@ -0,0 +1,33 @@
+package domain
+
+import "time"
+
+// StatusOverrideState tracks a proposed certificate status correction.
+type StatusOverrideState string
+
+const (
+	StatusOverridePending  StatusOverrideState = "PENDING"
+	StatusOverrideApproved StatusOverrideState = "APPROVED"
+	StatusOverrideRejected StatusOverrideState = "REJECTED"
+)
+
+// StatusOverride is a status change proposed by one admin that only takes effect once a second admin approves it.
+type StatusOverride struct {
+	ID            string                `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID      string                `gorm:"size:36;not null;default:'default';index" json:"-"`
+	CertificateID string                `gorm:"type:char(36);index" json:"certificate_id"`
+	FromStatus    LifeCertificateStatus `gorm:"type:varchar(16)" json:"from_status"`
+	ToStatus      LifeCertificateStatus `gorm:"type:varchar(16)" json:"to_status"`
+	Justification string                `gorm:"type:text" json:"justification"`
+	State         StatusOverrideState   `gorm:"type:varchar(16);index" json:"state"`
+	ProposedBy    string                `gorm:"size:100" json:"proposed_by"`
+	ProposedAt    time.Time             `json:"proposed_at"`
+	DecidedBy     *string               `gorm:"size:100" json:"decided_by"`
+	DecidedAt     *time.Time            `json:"decided_at"`
+	DecisionNotes *string               `gorm:"type:text" json:"decision_notes"`
+}
+
+// TableName keeps the table naming explicit.
+func (StatusOverride) TableName() string {
+	return "status_overrides"
+}
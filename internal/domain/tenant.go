@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// Tenant is a pension fund served by the deployment. Its ID names it in the
+// X-Tenant-ID header and as the subdomain of TENANT_BASE_DOMAIN. Empty FR
+// Core settings and nil thresholds fall back to the configured ones.
+type Tenant struct {
+	ID   string `gorm:"size:36;primaryKey" json:"id"`
+	Name string `gorm:"size:150" json:"name"`
+	// FRCoreBaseURL points the tenant at its own FR Core instance.
+	FRCoreBaseURL         string `gorm:"column:frcore_base_url;size:255" json:"frcore_base_url"`
+	FRCoreUploadAPIKey    string `gorm:"column:frcore_upload_api_key;size:255" json:"-"`
+	FRCoreRecognizeAPIKey string `gorm:"column:frcore_recognize_api_key;size:255" json:"-"`
+	// FRCoreTenantID is the tenant the tenant's faces are kept under in FR Core.
+	FRCoreTenantID      string         `gorm:"column:frcore_tenant_id;size:100" json:"frcore_tenant_id"`
+	DistanceThreshold   *float64       `json:"distance_threshold"`
+	SimilarityThreshold *float64       `json:"similarity_threshold"`
+	Branding            TenantBranding `gorm:"embedded;embeddedPrefix:branding_" json:"branding"`
+	// DisabledAt is set while the tenant is refused; its data is kept.
+	DisabledAt *time.Time `json:"disabled_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TenantBranding is how a tenant presents itself in notifications and PDF
+// certificates. Empty senders fall back to the configured ones.
+type TenantBranding struct {
+	DisplayName string `gorm:"size:150" json:"display_name"`
+	FooterText  string `gorm:"size:500" json:"footer_text"`
+	EmailFrom   string `gorm:"size:255" json:"email_from"`
+	// SMSFrom is the sender number or alphanumeric sender ID.
+	SMSFrom               string `gorm:"column:sms_from;size:50" json:"sms_from"`
+	WhatsAppPhoneNumberID string `gorm:"column:whatsapp_phone_number_id;size:50" json:"whatsapp_phone_number_id"`
+	// LogoKey is the blob of the PNG or JPEG logo, empty without one.
+	LogoKey       string     `gorm:"size:255" json:"-"`
+	LogoUpdatedAt *time.Time `json:"logo_updated_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (Tenant) TableName() string {
+	return "tenants"
+}
+
+// HasFRCoreOverrides reports whether the tenant uses FR Core settings of its own.
+func (t *Tenant) HasFRCoreOverrides() bool {
+	return t.FRCoreBaseURL != "" || t.FRCoreUploadAPIKey != "" || t.FRCoreRecognizeAPIKey != "" || t.FRCoreTenantID != ""
+}
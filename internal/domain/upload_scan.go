@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// Upload scan outcomes.
+const (
+	UploadScanClean    = "CLEAN"
+	UploadScanInfected = "INFECTED"
+)
+
+// UploadScan records the malware scan of an uploaded file before it was stored.
+type UploadScan struct {
+	ID        string `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID  string `gorm:"size:36;not null;default:'default';index" json:"-"`
+	FileName  string `gorm:"size:255" json:"file_name"`
+	SizeBytes int64  `json:"size_bytes"`
+	SHA256    string `gorm:"size:64;index" json:"sha256"`
+	Result    string `gorm:"size:16;index" json:"result"`
+	// Signature names the malware found in an infected upload.
+	Signature *string `gorm:"size:255" json:"signature"`
+	// DocumentID is the supporting document stored from a clean upload.
+	DocumentID *string `gorm:"type:char(36);index" json:"document_id"`
+	// QuarantineKey is the blob an infected upload was kept in, nil when it was discarded.
+	QuarantineKey *string   `gorm:"size:255" json:"quarantine_key"`
+	ScannedBy     string    `gorm:"size:100" json:"scanned_by"`
+	ScannedAt     time.Time `gorm:"index" json:"scanned_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (UploadScan) TableName() string {
+	return "upload_scans"
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+// DeviceTrust is how well a participant's capturing device is known when an attempt is submitted.
+type DeviceTrust string
+
+const (
+	// DeviceTrustUnknown attempts sent no device ID, or came from a kiosk.
+	DeviceTrustUnknown DeviceTrust = "UNKNOWN"
+	// DeviceTrustNew is the first attempt from the device for the participant.
+	DeviceTrustNew DeviceTrust = "NEW"
+	// DeviceTrustKnown devices were seen before but are not trusted.
+	DeviceTrustKnown DeviceTrust = "KNOWN"
+	// DeviceTrustTrusted devices passed a verification or were trusted by an admin.
+	DeviceTrustTrusted DeviceTrust = "TRUSTED"
+)
+
+// VerificationDevice is a device a participant submitted automatic attempts
+// from, identified by the app instance ID the client sends.
+type VerificationDevice struct {
+	ParticipantID string `gorm:"type:char(36);primaryKey" json:"participant_id"`
+	DeviceID      string `gorm:"size:100;primaryKey" json:"device_id"`
+	TenantID      string `gorm:"size:36;not null;default:'default';index" json:"-"`
+	// Model and OS are the fingerprint reported with the latest attempt.
+	Model       *string   `gorm:"size:100" json:"model"`
+	OS          *string   `gorm:"size:50" json:"os"`
+	Attempts    int       `json:"attempts"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+	// TrustedAt is set while the device is trusted; TrustedBy is system when a VALID attempt trusted it.
+	TrustedAt *time.Time `json:"trusted_at"`
+	TrustedBy *string    `gorm:"size:100" json:"trusted_by"`
+}
+
+// TableName keeps the table naming explicit.
+func (VerificationDevice) TableName() string {
+	return "verification_devices"
+}
+
+// Trust is the device's trust level; a nil device was never seen.
+func (d *VerificationDevice) Trust() DeviceTrust {
+	switch {
+	case d == nil:
+		return DeviceTrustNew
+	case d.TrustedAt != nil:
+		return DeviceTrustTrusted
+	}
+	return DeviceTrustKnown
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// VerificationLock counts a participant's consecutive INVALID automatic
+// attempts and holds the lock they trigger.
+type VerificationLock struct {
+	ParticipantID string `gorm:"type:char(36);primaryKey" json:"participant_id"`
+	TenantID      string `gorm:"size:36;not null;default:'default';index" json:"-"`
+	// FailedAttempts is the number of INVALID attempts since the last VALID
+	// one, lock or unlock.
+	FailedAttempts int        `json:"failed_attempts"`
+	LockedAt       *time.Time `json:"locked_at"`
+	// LockedUntil ends the cooldown; a lock without one lasts until an admin unlocks it.
+	LockedUntil *time.Time `gorm:"index" json:"locked_until"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (VerificationLock) TableName() string {
+	return "participant_verification_lock"
+}
+
+// Locked reports whether automatic verification is locked at now.
+func (l *VerificationLock) Locked(now time.Time) bool {
+	return l != nil && l.LockedAt != nil && (l.LockedUntil == nil || now.Before(*l.LockedUntil))
+}
@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+// LivenessPolicy decides how liveness is handled for automatic verifications.
+type LivenessPolicy string
+
+const (
+	// LivenessPolicyRequired runs the liveness check; failures go to manual review.
+	LivenessPolicyRequired LivenessPolicy = "REQUIRED"
+	// LivenessPolicyReview sends every attempt to manual review without a liveness check.
+	LivenessPolicyReview LivenessPolicy = "REVIEW"
+	// LivenessPolicySkip goes straight to face matching without a liveness check.
+	LivenessPolicySkip LivenessPolicy = "SKIP"
+)
+
+// SchedulePolicy decides when a participant's next verification is due.
+type SchedulePolicy string
+
+const (
+	// SchedulePolicyRolling makes the next verification due a number of months after the last VALID one.
+	SchedulePolicyRolling SchedulePolicy = "ROLLING"
+	// SchedulePolicyFixedDate makes everyone due on the same date every year.
+	SchedulePolicyFixedDate SchedulePolicy = "FIXED_DATE"
+	// SchedulePolicyBirthdayMonth makes each participant due at the end of their birthday month every year.
+	SchedulePolicyBirthdayMonth SchedulePolicy = "BIRTHDAY_MONTH"
+)
+
+// VerificationProfile is a named set of verification rules applied to a fund
+// or to individual participants instead of the global settings.
+type VerificationProfile struct {
+	ID       string `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID string `gorm:"size:36;not null;default:'default';index;uniqueIndex:idx_verification_profiles_tenant_name,priority:1;uniqueIndex:idx_verification_profiles_tenant_fund,priority:1" json:"-"`
+	Name     string `gorm:"size:100;uniqueIndex:idx_verification_profiles_tenant_name,priority:2" json:"name"`
+	// Fund makes the profile the default for participants of that fund.
+	Fund                *string        `gorm:"size:64;uniqueIndex:idx_verification_profiles_tenant_fund,priority:2" json:"fund"`
+	DistanceThreshold   float64        `json:"distance_threshold"`
+	SimilarityThreshold float64        `json:"similarity_threshold"`
+	LivenessPolicy      LivenessPolicy `gorm:"type:varchar(16);default:REQUIRED" json:"liveness_policy"`
+	// MaxAttemptsPerDay limits automatic attempts per participant in a rolling 24 hours; zero means unlimited.
+	MaxAttemptsPerDay int `json:"max_attempts_per_day"`
+	// SchedulePolicy overrides the global due date policy; nil keeps it.
+	SchedulePolicy *SchedulePolicy `gorm:"type:varchar(20)" json:"schedule_policy"`
+	// ScheduleDate is the yearly MM-DD due date for FIXED_DATE.
+	ScheduleDate *string `gorm:"size:5" json:"schedule_date"`
+	// ScheduleMonths is the ROLLING interval; zero uses the configured validity.
+	ScheduleMonths int       `json:"schedule_months"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (VerificationProfile) TableName() string {
+	return "verification_profiles"
+}
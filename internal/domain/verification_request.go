@@ -0,0 +1,54 @@
+package domain
+
+import "time"
+
+// VerificationRequestStatus tracks an asynchronous verification through the job system.
+type VerificationRequestStatus string
+
+const (
+	VerificationRequestQueued     VerificationRequestStatus = "QUEUED"
+	VerificationRequestProcessing VerificationRequestStatus = "PROCESSING"
+	// VerificationRequestCompleted recorded a certificate, whatever its status.
+	VerificationRequestCompleted VerificationRequestStatus = "COMPLETED"
+	// VerificationRequestFailed was refused or ran out of attempts without a certificate.
+	VerificationRequestFailed VerificationRequestStatus = "FAILED"
+)
+
+// VerificationRequest is a selfie submitted for verification in the background.
+type VerificationRequest struct {
+	ID            string                    `gorm:"type:char(36);primaryKey" json:"verification_id"`
+	TenantID      string                    `gorm:"size:36;not null;default:'default';index" json:"-"`
+	ParticipantID string                    `gorm:"type:char(36);index" json:"participant_id"`
+	Status        VerificationRequestStatus `gorm:"type:varchar(16);index" json:"status"`
+	// ImageKey is the blob holding the upload until the request finishes.
+	ImageKey         string   `gorm:"size:255" json:"-"`
+	OriginalFilename string   `gorm:"size:255" json:"-"`
+	Location         *string  `gorm:"size:100" json:"location"`
+	Latitude         *float64 `json:"latitude"`
+	Longitude        *float64 `json:"longitude"`
+	// ClientIP and the device fields identify where the request was submitted from.
+	ClientIP    *string `gorm:"size:45" json:"client_ip"`
+	DeviceID    *string `gorm:"size:100" json:"device_id"`
+	DeviceModel *string `gorm:"size:100" json:"device_model"`
+	DeviceOS    *string `gorm:"size:50" json:"device_os"`
+	// SessionID is the verification session presented at submission, used
+	// up when the queued attempt records its certificate.
+	SessionID *string `gorm:"type:char(36)" json:"session_id"`
+	// Outcome of a COMPLETED request.
+	CertificateID      *string                `gorm:"type:char(36)" json:"certificate_id"`
+	VerificationStatus *LifeCertificateStatus `gorm:"type:varchar(16)" json:"verification_status"`
+	Similarity         *float64               `json:"similarity"`
+	Distance           *float64               `json:"distance"`
+	// Why a FAILED request was refused; the code matches the synchronous endpoint's.
+	ErrorCode   *string    `gorm:"size:64" json:"error_code"`
+	Error       *string    `gorm:"type:text" json:"error"`
+	SubmittedBy string     `gorm:"size:100" json:"submitted_by"`
+	CreatedAt   time.Time  `gorm:"index" json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (VerificationRequest) TableName() string {
+	return "verification_requests"
+}
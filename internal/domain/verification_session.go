@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// VerificationSession is issued to the mobile SDK before a capture: it binds
+// one verification attempt to a participant, a liveness challenge and an
+// upload policy, and can be used once. Only the SHA-256 of the token is stored.
+type VerificationSession struct {
+	ID            string `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID      string `gorm:"size:36;not null;default:'default';index" json:"-"`
+	ParticipantID string `gorm:"type:char(36);index" json:"participant_id"`
+	TokenHash     string `gorm:"size:64;uniqueIndex" json:"-"`
+	Nonce         string `gorm:"size:64" json:"nonce"`
+	// ChallengeAction is the liveness action the participant is asked to perform.
+	ChallengeAction string    `gorm:"size:32" json:"challenge_action"`
+	MaxImageBytes   int64     `json:"max_image_bytes"`
+	ExpiresAt       time.Time `json:"expires_at"`
+	// ConsumedAt and CertificateID are set when a verification used the session.
+	ConsumedAt    *time.Time `json:"consumed_at"`
+	CertificateID *string    `gorm:"type:char(36)" json:"certificate_id"`
+	// ParentID is the session of the first step when this session was opened
+	// for a step-up, and StepUp that first step as JSON.
+	ParentID  *string   `gorm:"type:char(36);index" json:"parent_id"`
+	StepUp    *string   `gorm:"type:text" json:"step_up"`
+	CreatedBy string    `gorm:"size:100" json:"created_by"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (VerificationSession) TableName() string {
+	return "verification_sessions"
+}
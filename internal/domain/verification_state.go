@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// ParticipantVerificationState is a participant's latest verification outcome,
+// kept up to date with every attempt so status reads need not search
+// life_certificate.
+type ParticipantVerificationState struct {
+	ParticipantID string `gorm:"type:char(36);primaryKey" json:"participant_id"`
+	TenantID      string `gorm:"size:36;not null;default:'default';index" json:"-"`
+	// CertificateID is the latest attempt, whose outcome the fields below copy.
+	CertificateID string                `gorm:"type:char(36);index" json:"certificate_id"`
+	Status        LifeCertificateStatus `gorm:"type:varchar(16);index" json:"status"`
+	Method        VerificationMethod    `gorm:"type:varchar(16)" json:"method"`
+	Distance      *float64              `json:"distance"`
+	Similarity    *float64              `json:"similarity"`
+	VerifiedAt    time.Time             `json:"verified_at"`
+	SelfiePath    string                `gorm:"type:text" json:"selfie_path"`
+	// LastValidAt is the latest VALID attempt, which need not be the latest attempt.
+	LastValidAt *time.Time `json:"last_valid_at"`
+	// ValidUntil is LastValidAt plus the validity period in force when it was recorded.
+	ValidUntil *time.Time `gorm:"index" json:"valid_until"`
+	Attempts   int64      `json:"attempts"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (ParticipantVerificationState) TableName() string {
+	return "participant_verification_state"
+}
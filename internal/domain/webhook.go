@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+// WebhookDeliveryStatus tracks an individual webhook delivery.
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "SUCCEEDED"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "FAILED"
+)
+
+// WebhookSubscription is a downstream endpoint receiving signed event notifications.
+type WebhookSubscription struct {
+	ID       string `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID string `gorm:"size:36;not null;default:'default';index" json:"-"`
+	URL      string `gorm:"type:text" json:"url"`
+	Secret   string `gorm:"size:128" json:"-"`
+	// EventTypes is a comma separated list of subscribed event types.
+	EventTypes string    `gorm:"type:text" json:"event_types"`
+	Active     bool      `gorm:"default:true" json:"active"`
+	CreatedBy  string    `gorm:"size:100" json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// WebhookDelivery is one event queued for one subscription, with its attempt history.
+type WebhookDelivery struct {
+	ID             string                `gorm:"type:char(36);primaryKey" json:"id"`
+	TenantID       string                `gorm:"size:36;not null;default:'default';index" json:"-"`
+	SubscriptionID string                `gorm:"type:char(36);index" json:"subscription_id"`
+	EventID        string                `gorm:"type:char(36);index" json:"event_id"`
+	EventType      string                `gorm:"size:64" json:"event_type"`
+	Payload        string                `gorm:"type:text" json:"payload"`
+	Status         WebhookDeliveryStatus `gorm:"type:varchar(16);index" json:"status"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  time.Time             `gorm:"index" json:"next_attempt_at"`
+	ResponseCode   *int                  `json:"response_code"`
+	LastError      *string               `gorm:"type:text" json:"last_error"`
+	CreatedAt      time.Time             `json:"created_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at"`
+}
+
+// TableName keeps the table naming explicit.
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
@@ -0,0 +1,68 @@
+// Package events defines the domain events emitted to downstream systems.
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event types emitted by the service.
+const (
+	TypeVerificationCompleted      = "verification.completed"
+	TypeVerificationReviewRequired = "verification.review_required"
+	// TypeVerificationRequestCompleted reports the end of an asynchronous verification, successful or not.
+	TypeVerificationRequestCompleted = "verification.request_completed"
+	TypeParticipantRegistered        = "participant.registered"
+	TypeAlertTriggered               = "alert.triggered"
+	TypeReminderDue                  = "participant.reminder_due"
+	// TypeParticipantEscalated reports a participant overdue in a campaign reaching an escalation stage.
+	TypeParticipantEscalated = "participant.escalated"
+)
+
+// Types lists every event type subscribers may select.
+var Types = []string{
+	TypeVerificationCompleted,
+	TypeVerificationReviewRequired,
+	TypeVerificationRequestCompleted,
+	TypeParticipantRegistered,
+	TypeAlertTriggered,
+	TypeReminderDue,
+	TypeParticipantEscalated,
+}
+
+// Event is the envelope delivered to subscribers.
+type Event struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	// Tenant is the pension fund the event happened in.
+	Tenant     string                 `json:"tenant,omitempty"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Data       map[string]interface{} `json:"data"`
+}
+
+// New builds an event with a fresh ID stamped with the current time.
+func New(eventType string, data map[string]interface{}) Event {
+	return Event{
+		ID:         uuid.NewString(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+}
+
+// Publisher hands events to a delivery mechanism.
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// IsKnownType reports whether t is an event type emitted by the service.
+func IsKnownType(t string) bool {
+	for _, known := range Types {
+		if known == t {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+// hubBuffer is how many events a slow subscriber may lag behind before events are dropped for it.
+const hubBuffer = 64
+
+// Hub fans events out to in-process subscribers such as live streams.
+// Publishing never blocks: a subscriber whose buffer is full misses the event.
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[*subscription]struct{}
+}
+
+type subscription struct {
+	ch    chan Event
+	match func(Event) bool
+}
+
+// NewHub creates an empty hub.
+func NewHub() *Hub {
+	return &Hub{subscribers: make(map[*subscription]struct{})}
+}
+
+// Publish delivers the event to every subscriber whose filter matches it.
+func (h *Hub) Publish(_ context.Context, event Event) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for sub := range h.subscribers {
+		if sub.match != nil && !sub.match(event) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+		}
+	}
+	return nil
+}
+
+// Subscribe registers a subscriber until ctx is cancelled, after which the returned channel is closed.
+// A nil match receives every event.
+func (h *Hub) Subscribe(ctx context.Context, match func(Event) bool) <-chan Event {
+	sub := &subscription{ch: make(chan Event, hubBuffer), match: match}
+
+	h.mu.Lock()
+	h.subscribers[sub] = struct{}{}
+	h.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		h.mu.Lock()
+		delete(h.subscribers, sub)
+		h.mu.Unlock()
+		close(sub.ch)
+	}()
+	return sub.ch
+}
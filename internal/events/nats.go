@@ -0,0 +1,60 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATSPublisher publishes events on "<prefix>.<event type>" subjects.
+type NATSPublisher struct {
+	conn    *nats.Conn
+	prefix  string
+	timeout time.Duration
+}
+
+// NewNATSPublisher connects to the NATS server at url.
+func NewNATSPublisher(url, prefix string, timeout time.Duration) (*NATSPublisher, error) {
+	if url == "" {
+		return nil, fmt.Errorf("nats url is required")
+	}
+	conn, err := nats.Connect(url, nats.Name("life-certificates"), nats.Timeout(timeout))
+	if err != nil {
+		return nil, fmt.Errorf("connect nats: %w", err)
+	}
+	return &NATSPublisher{conn: conn, prefix: prefix, timeout: timeout}, nil
+}
+
+// Publish sends the event and flushes so a nil error means the server received it.
+func (p *NATSPublisher) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+	subject := event.Type
+	if p.prefix != "" {
+		subject = p.prefix + "." + event.Type
+	}
+
+	msg := nats.NewMsg(subject)
+	msg.Data = payload
+	msg.Header.Set(nats.MsgIdHdr, event.ID)
+	if err := p.conn.PublishMsg(msg); err != nil {
+		return fmt.Errorf("nats publish: %w", err)
+	}
+
+	flushCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	if err := p.conn.FlushWithContext(flushCtx); err != nil {
+		return fmt.Errorf("nats flush: %w", err)
+	}
+	return nil
+}
+
+// Close drains pending messages and closes the connection.
+func (p *NATSPublisher) Close() error {
+	return p.conn.Drain()
+}
@@ -0,0 +1,59 @@
+// Package export writes tabular exports row by row, so a large export is sent
+// while it is read from the database instead of being built in memory first.
+package export
+
+import (
+	"errors"
+	"io"
+	"strings"
+)
+
+// Supported export formats.
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// ErrUnsupportedFormat is returned for formats other than csv and xlsx.
+var ErrUnsupportedFormat = errors.New("unsupported export format, use csv or xlsx")
+
+// Writer writes the rows of one table. Close must be called once all rows are
+// written; until then the output is incomplete.
+type Writer interface {
+	Write(row []string) error
+	// Flush pushes buffered rows to the underlying writer.
+	Flush() error
+	Close() error
+}
+
+// ParseFormat normalises a requested format, defaulting to csv.
+func ParseFormat(raw string) (string, error) {
+	switch format := strings.ToLower(strings.TrimSpace(raw)); format {
+	case "":
+		return FormatCSV, nil
+	case FormatCSV, FormatXLSX:
+		return format, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+// New returns a Writer for the format that writes to w.
+func New(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// ContentType is the media type of the format.
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
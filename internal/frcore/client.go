@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"mime"
 	"mime/multipart"
 	"net/http"
@@ -16,12 +15,27 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Client exposes the FR Core operations required by LCS.
 type Client interface {
 	UploadFace(ctx context.Context, req UploadRequest) (*UploadResponse, error)
 	Recognize(ctx context.Context, req RecognizeRequest) (*RecognizeResponse, error)
+	AssessQuality(ctx context.Context, req QualityRequest) (*QualityResponse, error)
+	Compare(ctx context.Context, req CompareRequest) (*CompareResponse, error)
+	ListFaces(ctx context.Context) ([]Face, error)
+	DeleteFace(ctx context.Context, label string) error
+	Ping(ctx context.Context) error
+}
+
+// Face describes an enrollment stored in FR Core.
+type Face struct {
+	ID          string     `json:"id"`
+	Label       string     `json:"label"`
+	ExternalRef string     `json:"external_ref"`
+	CreatedAt   *time.Time `json:"created_at"`
 }
 
 // UploadRequest carries the data for registering a face encoding.
@@ -53,6 +67,39 @@ type RecognizeResponse struct {
 	Distance   *float64 `json:"distance"`
 }
 
+// QualityRequest asks FR Core to assess a photo before it is enrolled.
+type QualityRequest struct {
+	ImageName string
+	Image     []byte
+}
+
+// QualityResponse describes the faces FR Core found in a photo.
+type QualityResponse struct {
+	FaceCount int `json:"face_count"`
+	// FaceRatio is the area of the largest face box relative to the whole photo, 0 to 1.
+	FaceRatio float64 `json:"face_ratio"`
+	// Yaw and Pitch are the head rotation in degrees; 0 is frontal.
+	Yaw   float64 `json:"yaw"`
+	Pitch float64 `json:"pitch"`
+	// Brightness is the mean luminance of the face region, 0 to 255.
+	Brightness float64 `json:"brightness"`
+}
+
+// CompareRequest asks FR Core how alike the faces on two photos are, without
+// enrolling either.
+type CompareRequest struct {
+	ImageName     string
+	Image         []byte
+	ReferenceName string
+	Reference     []byte
+}
+
+// CompareResponse scores the two faces on the same scale as recognition.
+type CompareResponse struct {
+	Similarity float64  `json:"similarity"`
+	Distance   *float64 `json:"distance"`
+}
+
 // Options configures the FR Core HTTP client.
 type Options struct {
 	BaseURL         string
@@ -61,6 +108,8 @@ type Options struct {
 	TenantID        string
 	Timeout         time.Duration
 	HTTPClient      *http.Client
+	// LogLevel defaults to LogBody.
+	LogLevel LogLevel
 }
 
 type apiClient struct {
@@ -69,6 +118,7 @@ type apiClient struct {
 	recognizeAPIKey string
 	tenantID        string
 	httpClient      *http.Client
+	logLevel        LogLevel
 }
 
 // NewHTTPClient constructs a HTTP-backed FR Core client.
@@ -90,12 +140,17 @@ func NewHTTPClient(opts Options) (Client, error) {
 		client = &http.Client{Timeout: opts.Timeout}
 	}
 
+	if opts.LogLevel == "" {
+		opts.LogLevel = LogBody
+	}
+
 	return &apiClient{
 		baseURL:         parsed,
 		uploadAPIKey:    opts.UploadAPIKey,
 		recognizeAPIKey: opts.RecognizeAPIKey,
 		tenantID:        opts.TenantID,
 		httpClient:      client,
+		logLevel:        opts.LogLevel,
 	}, nil
 }
 
@@ -142,7 +197,7 @@ func (c *apiClient) UploadFace(ctx context.Context, req UploadRequest) (*UploadR
 
 	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
 	c.applyAuthHeader(httpReq, c.uploadAPIKey)
-	logRequest(httpReq, len(req.Image))
+	c.logRequest(httpReq, len(req.Image))
 
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
@@ -152,15 +207,15 @@ func (c *apiClient) UploadFace(ctx context.Context, req UploadRequest) (*UploadR
 
 	if resp.StatusCode >= 400 {
 		payload, _ := io.ReadAll(resp.Body)
-		logResponse(resp, payload)
-		return nil, fmt.Errorf("frcore upload error: status=%d body=%s", resp.StatusCode, string(payload))
+		c.logResponse(resp, payload)
+		return nil, fmt.Errorf("frcore upload error: status=%d body=%s", resp.StatusCode, redactBody(payload))
 	}
 
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("read response body: %w", err)
 	}
-	logResponse(resp, bodyBytes)
+	c.logResponse(resp, bodyBytes)
 
 	var apiResp struct {
 		Status  string `json:"status"`
@@ -223,7 +278,7 @@ func (c *apiClient) Recognize(ctx context.Context, req RecognizeRequest) (*Recog
 
 	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
 	c.applyAuthHeader(httpReq, c.recognizeAPIKey)
-	logRequest(httpReq, len(req.Image))
+	c.logRequest(httpReq, len(req.Image))
 
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
@@ -233,15 +288,15 @@ func (c *apiClient) Recognize(ctx context.Context, req RecognizeRequest) (*Recog
 
 	if resp.StatusCode >= 400 {
 		payload, _ := io.ReadAll(resp.Body)
-		logResponse(resp, payload)
-		return nil, fmt.Errorf("frcore recognize error: status=%d body=%s", resp.StatusCode, string(payload))
+		c.logResponse(resp, payload)
+		return nil, fmt.Errorf("frcore recognize error: status=%d body=%s", resp.StatusCode, redactBody(payload))
 	}
 
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("read response body: %w", err)
 	}
-	logResponse(resp, bodyBytes)
+	c.logResponse(resp, bodyBytes)
 
 	var apiResp struct {
 		Status  string `json:"status"`
@@ -268,6 +323,249 @@ func (c *apiClient) Recognize(ctx context.Context, req RecognizeRequest) (*Recog
 	}, nil
 }
 
+// AssessQuality reports face count, size, pose and brightness via POST /quality.
+func (c *apiClient) AssessQuality(ctx context.Context, req QualityRequest) (*QualityResponse, error) {
+	if len(req.Image) == 0 {
+		return nil, fmt.Errorf("image payload is empty")
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	filename := req.ImageName
+	if strings.TrimSpace(filename) == "" {
+		filename = "selfie.jpg"
+	}
+
+	contentType := determineContentType(req.Image, filename)
+	part, err := createFormFileWithContentType(writer, "image", filename, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("create form file: %w", err)
+	}
+	if _, err := io.Copy(part, bytes.NewReader(req.Image)); err != nil {
+		return nil, fmt.Errorf("write image: %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("close multipart writer: %w", err)
+	}
+
+	endpoint := c.resolvePath("quality")
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
+	c.applyAuthHeader(httpReq, c.uploadAPIKey)
+	c.logRequest(httpReq, len(req.Image))
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		payload, _ := io.ReadAll(resp.Body)
+		c.logResponse(resp, payload)
+		return nil, fmt.Errorf("frcore quality error: status=%d body=%s", resp.StatusCode, redactBody(payload))
+	}
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+	c.logResponse(resp, bodyBytes)
+
+	var apiResp struct {
+		Status  string          `json:"status"`
+		Message string          `json:"message"`
+		Data    QualityResponse `json:"data"`
+	}
+
+	if err := json.Unmarshal(bodyBytes, &apiResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	if strings.ToLower(apiResp.Status) != "success" {
+		return nil, fmt.Errorf("frcore quality failed: %s", apiResp.Message)
+	}
+
+	return &apiResp.Data, nil
+}
+
+// Compare scores the faces of two photos via POST /compare.
+func (c *apiClient) Compare(ctx context.Context, req CompareRequest) (*CompareResponse, error) {
+	if len(req.Image) == 0 || len(req.Reference) == 0 {
+		return nil, fmt.Errorf("image payload is empty")
+	}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	parts := []struct {
+		field, name, fallback string
+		data                  []byte
+	}{
+		{"image", req.ImageName, "selfie.jpg", req.Image},
+		{"reference", req.ReferenceName, "reference.jpg", req.Reference},
+	}
+	for _, p := range parts {
+		filename := p.name
+		if strings.TrimSpace(filename) == "" {
+			filename = p.fallback
+		}
+		part, err := createFormFileWithContentType(writer, p.field, filename, determineContentType(p.data, filename))
+		if err != nil {
+			return nil, fmt.Errorf("create form file: %w", err)
+		}
+		if _, err := io.Copy(part, bytes.NewReader(p.data)); err != nil {
+			return nil, fmt.Errorf("write %s: %w", p.field, err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("close multipart writer: %w", err)
+	}
+
+	endpoint := c.resolvePath("compare")
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
+	c.applyAuthHeader(httpReq, c.recognizeAPIKey)
+	c.logRequest(httpReq, len(req.Image)+len(req.Reference))
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		payload, _ := io.ReadAll(resp.Body)
+		c.logResponse(resp, payload)
+		return nil, fmt.Errorf("frcore compare error: status=%d body=%s", resp.StatusCode, redactBody(payload))
+	}
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+	c.logResponse(resp, bodyBytes)
+
+	var apiResp struct {
+		Status  string          `json:"status"`
+		Message string          `json:"message"`
+		Data    CompareResponse `json:"data"`
+	}
+
+	if err := json.Unmarshal(bodyBytes, &apiResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	if strings.ToLower(apiResp.Status) != "success" {
+		return nil, fmt.Errorf("frcore compare failed: %s", apiResp.Message)
+	}
+
+	return &apiResp.Data, nil
+}
+
+// ListFaces returns every enrollment FR Core holds for the tenant via GET /faces.
+func (c *apiClient) ListFaces(ctx context.Context) ([]Face, error) {
+	endpoint := c.resolvePath("faces")
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+
+	c.applyAuthHeader(httpReq, c.uploadAPIKey)
+	c.logRequest(httpReq, 0)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+	c.logResponse(resp, bodyBytes)
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("frcore list faces error: status=%d body=%s", resp.StatusCode, redactBody(bodyBytes))
+	}
+
+	var apiResp struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+		Data    []Face `json:"data"`
+	}
+	if err := json.Unmarshal(bodyBytes, &apiResp); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	if strings.ToLower(apiResp.Status) != "success" {
+		return nil, fmt.Errorf("frcore list faces failed: %s", apiResp.Message)
+	}
+
+	return apiResp.Data, nil
+}
+
+// DeleteFace removes an enrollment from FR Core via DELETE /faces/{label}.
+func (c *apiClient) DeleteFace(ctx context.Context, label string) error {
+	if strings.TrimSpace(label) == "" {
+		return fmt.Errorf("label is required")
+	}
+
+	endpoint := c.resolvePath("faces/" + label)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+
+	c.applyAuthHeader(httpReq, c.uploadAPIKey)
+	c.logRequest(httpReq, 0)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	payload, _ := io.ReadAll(resp.Body)
+	c.logResponse(resp, payload)
+
+	if resp.StatusCode >= 400 && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("frcore delete face error: status=%d body=%s", resp.StatusCode, redactBody(payload))
+	}
+	return nil
+}
+
+// Ping checks FR Core is reachable; any response below 500 from the base URL counts.
+func (c *apiClient) Ping(ctx context.Context) error {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL.String(), nil)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 500 {
+		return fmt.Errorf("frcore unavailable: status=%d", resp.StatusCode)
+	}
+	return nil
+}
+
 func (c *apiClient) resolvePath(p string) string {
 	u := *c.baseURL
 	u.Path = path.Join(c.baseURL.Path, p)
@@ -285,23 +583,16 @@ func (c *apiClient) applyAuthHeader(req *http.Request, apiKey string) {
 
 var _ Client = (*apiClient)(nil)
 
-func logRequest(req *http.Request, payloadSize int) {
-	headers := make(map[string]string)
-	for k, v := range req.Header {
-		if len(v) > 0 {
-			headers[k] = v[0]
-		}
+// traceID links log lines to the trace of the request that triggered the call.
+func traceID(req *http.Request) string {
+	if req == nil {
+		return ""
 	}
-	log.Printf("[frcore] request method=%s url=%s headers=%v payload_bytes=%d", req.Method, req.URL.String(), headers, payloadSize)
-}
-
-func logResponse(resp *http.Response, body []byte) {
-	preview := string(body)
-	const maxPreview = 1024
-	if len(preview) > maxPreview {
-		preview = preview[:maxPreview] + "..."
+	spanContext := trace.SpanContextFromContext(req.Context())
+	if !spanContext.IsValid() {
+		return ""
 	}
-	log.Printf("[frcore] response status=%d headers=%v body=%s", resp.StatusCode, resp.Header, preview)
+	return spanContext.TraceID().String()
 }
 
 func determineContentType(data []byte, filename string) string {
@@ -0,0 +1,206 @@
+// Package frcoretest serves a stand-in for FR Core, so the verify path can be
+// exercised and measured without the real service.
+package frcoretest
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/frcore"
+)
+
+// maxUpload bounds the multipart bodies the mock accepts, as FR Core does.
+const maxUpload = 20 << 20
+
+// Options shapes the mock's answers.
+type Options struct {
+	// Latency is added to every call except the health check, plus up to Jitter at random.
+	Latency time.Duration
+	Jitter  time.Duration
+	// Similarity and Distance are reported for a recognised face.
+	Similarity float64
+	Distance   float64
+}
+
+// Server mimics the FR Core HTTP API: POST /upload, /recognize, /quality and
+// /compare, GET /faces and DELETE /faces/{label}. A recognize request is
+// matched to the enrolled label named by its image's file name, e.g.
+// <label>.jpg, and otherwise reports no match; every compared pair matches.
+type Server struct {
+	opts  Options
+	mu    sync.RWMutex
+	faces map[string]frcore.Face
+}
+
+// NewServer returns an empty mock. Zero Similarity and Distance default to a
+// confident match, 95 and 0.3.
+func NewServer(opts Options) *Server {
+	if opts.Similarity == 0 {
+		opts.Similarity = 95
+	}
+	if opts.Distance == 0 {
+		opts.Distance = 0.3
+	}
+	return &Server{opts: opts, faces: make(map[string]frcore.Face)}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	s.delay()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/upload":
+		s.upload(w, r)
+	case r.Method == http.MethodPost && r.URL.Path == "/recognize":
+		s.recognize(w, r)
+	case r.Method == http.MethodPost && r.URL.Path == "/quality":
+		s.quality(w, r)
+	case r.Method == http.MethodPost && r.URL.Path == "/compare":
+		s.compare(w, r)
+	case r.Method == http.MethodGet && r.URL.Path == "/faces":
+		s.list(w)
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/faces/"):
+		s.delete(w, strings.TrimPrefix(r.URL.Path, "/faces/"))
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// Faces returns how many faces are enrolled.
+func (s *Server) Faces() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.faces)
+}
+
+func (s *Server) upload(w http.ResponseWriter, r *http.Request) {
+	if _, ok := readImage(w, r); !ok {
+		return
+	}
+	label := strings.TrimSpace(r.FormValue("label"))
+	if label == "" {
+		writeError(w, http.StatusBadRequest, "label is required")
+		return
+	}
+	now := time.Now().UTC()
+	face := frcore.Face{ID: uuid.NewString(), Label: label, ExternalRef: r.FormValue("external_ref"), CreatedAt: &now}
+	s.mu.Lock()
+	s.faces[label] = face
+	s.mu.Unlock()
+	writeSuccess(w, map[string]string{
+		"id":           face.ID,
+		"label":        face.Label,
+		"image_path":   "faces/" + face.ID + ".jpg",
+		"external_ref": face.ExternalRef,
+	})
+}
+
+func (s *Server) recognize(w http.ResponseWriter, r *http.Request) {
+	name, ok := readImage(w, r)
+	if !ok {
+		return
+	}
+	label := strings.TrimSuffix(name, filepath.Ext(name))
+	s.mu.RLock()
+	_, enrolled := s.faces[label]
+	s.mu.RUnlock()
+	if !enrolled {
+		writeSuccess(w, frcore.RecognizeResponse{})
+		return
+	}
+	distance := s.opts.Distance
+	writeSuccess(w, frcore.RecognizeResponse{Label: label, Similarity: s.opts.Similarity, Distance: &distance})
+}
+
+// quality describes every photo as one frontal, well-lit face.
+func (s *Server) quality(w http.ResponseWriter, r *http.Request) {
+	if _, ok := readImage(w, r); !ok {
+		return
+	}
+	writeSuccess(w, frcore.QualityResponse{FaceCount: 1, FaceRatio: 0.3, Brightness: 128})
+}
+
+func (s *Server) compare(w http.ResponseWriter, r *http.Request) {
+	if _, ok := readImage(w, r); !ok {
+		return
+	}
+	reference, _, err := r.FormFile("reference")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "reference file is required")
+		return
+	}
+	reference.Close()
+	distance := s.opts.Distance
+	writeSuccess(w, frcore.CompareResponse{Similarity: s.opts.Similarity, Distance: &distance})
+}
+
+func (s *Server) list(w http.ResponseWriter) {
+	s.mu.RLock()
+	faces := make([]frcore.Face, 0, len(s.faces))
+	for _, face := range s.faces {
+		faces = append(faces, face)
+	}
+	s.mu.RUnlock()
+	writeSuccess(w, faces)
+}
+
+func (s *Server) delete(w http.ResponseWriter, label string) {
+	s.mu.Lock()
+	_, found := s.faces[label]
+	delete(s.faces, label)
+	s.mu.Unlock()
+	if !found {
+		writeError(w, http.StatusNotFound, "face not found")
+		return
+	}
+	writeSuccess(w, nil)
+}
+
+func (s *Server) delay() {
+	wait := s.opts.Latency
+	if s.opts.Jitter > 0 {
+		wait += time.Duration(rand.Int63n(int64(s.opts.Jitter)))
+	}
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
+// readImage reads the image part and returns its file name.
+func readImage(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if err := r.ParseMultipartForm(maxUpload); err != nil {
+		writeError(w, http.StatusBadRequest, "failed to parse multipart form")
+		return "", false
+	}
+	file, header, err := r.FormFile("image")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "image file is required")
+		return "", false
+	}
+	file.Close()
+	return header.Filename, true
+}
+
+func writeSuccess(w http.ResponseWriter, data interface{}) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "success", "data": data})
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, map[string]interface{}{"status": "error", "message": message})
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(payload)
+}
@@ -0,0 +1,222 @@
+package frcore
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// Operations limited by LimitedClient; Ping is never queued so health checks
+// report FR Core itself rather than the queue.
+const (
+	OperationUpload     = "upload"
+	OperationRecognize  = "recognize"
+	OperationQuality    = "quality"
+	OperationCompare    = "compare"
+	OperationListFaces  = "list_faces"
+	OperationDeleteFace = "delete_face"
+)
+
+// Operations lists the operation names Limits.PerOperation accepts.
+var Operations = []string{OperationUpload, OperationRecognize, OperationQuality, OperationCompare, OperationListFaces, OperationDeleteFace}
+
+// ErrOverloaded is returned instead of calling FR Core when no slot freed up
+// in time or too many calls are already waiting.
+var ErrOverloaded = errors.New("fr core is busy, try again later")
+
+// Limits caps the calls in flight to FR Core. Zero leaves a cap off.
+type Limits struct {
+	// MaxConcurrent caps calls across all operations.
+	MaxConcurrent int
+	// PerOperation caps calls of one operation, keyed by the Operation names.
+	PerOperation map[string]int
+	// MaxQueued caps the calls waiting for a slot; further calls fail at once.
+	MaxQueued int
+	// QueueTimeout is how long a call may wait for a slot.
+	QueueTimeout time.Duration
+	// OnWait, when set, is told how long each call queued and whether it got a slot.
+	OnWait func(operation string, waited time.Duration, err error)
+}
+
+// QueueStats is a snapshot of one operation's traffic.
+type QueueStats struct {
+	Operation string
+	InFlight  int64
+	Queued    int64
+}
+
+// LimitedClient queues calls beyond the configured caps so a spike of
+// verifications cannot overload FR Core.
+type LimitedClient struct {
+	next   Client
+	limits Limits
+	total  semaphore
+	ops    map[string]*operationLimit
+	queued atomic.Int64
+}
+
+type semaphore chan struct{}
+
+type operationLimit struct {
+	slots    semaphore
+	inFlight atomic.Int64
+	queued   atomic.Int64
+}
+
+// NewLimitedClient wraps next with the given limits.
+func NewLimitedClient(next Client, limits Limits) *LimitedClient {
+	c := &LimitedClient{next: next, limits: limits, ops: make(map[string]*operationLimit, len(Operations))}
+	if limits.MaxConcurrent > 0 {
+		c.total = make(semaphore, limits.MaxConcurrent)
+	}
+	for _, operation := range Operations {
+		op := &operationLimit{}
+		if limit := limits.PerOperation[operation]; limit > 0 {
+			op.slots = make(semaphore, limit)
+		}
+		c.ops[operation] = op
+	}
+	return c
+}
+
+func (c *LimitedClient) UploadFace(ctx context.Context, req UploadRequest) (*UploadResponse, error) {
+	release, err := c.acquire(ctx, OperationUpload)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return c.next.UploadFace(ctx, req)
+}
+
+func (c *LimitedClient) Recognize(ctx context.Context, req RecognizeRequest) (*RecognizeResponse, error) {
+	release, err := c.acquire(ctx, OperationRecognize)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return c.next.Recognize(ctx, req)
+}
+
+func (c *LimitedClient) AssessQuality(ctx context.Context, req QualityRequest) (*QualityResponse, error) {
+	release, err := c.acquire(ctx, OperationQuality)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return c.next.AssessQuality(ctx, req)
+}
+
+func (c *LimitedClient) Compare(ctx context.Context, req CompareRequest) (*CompareResponse, error) {
+	release, err := c.acquire(ctx, OperationCompare)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return c.next.Compare(ctx, req)
+}
+
+func (c *LimitedClient) ListFaces(ctx context.Context) ([]Face, error) {
+	release, err := c.acquire(ctx, OperationListFaces)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return c.next.ListFaces(ctx)
+}
+
+func (c *LimitedClient) DeleteFace(ctx context.Context, label string) error {
+	release, err := c.acquire(ctx, OperationDeleteFace)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return c.next.DeleteFace(ctx, label)
+}
+
+func (c *LimitedClient) Ping(ctx context.Context) error {
+	return c.next.Ping(ctx)
+}
+
+// Stats reports the calls in flight and queued per operation.
+func (c *LimitedClient) Stats() []QueueStats {
+	stats := make([]QueueStats, 0, len(c.ops))
+	for operation, op := range c.ops {
+		stats = append(stats, QueueStats{Operation: operation, InFlight: op.inFlight.Load(), Queued: op.queued.Load()})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Operation < stats[j].Operation })
+	return stats
+}
+
+// acquire takes the operation's slot, then a shared one, so a call never holds
+// a shared slot while waiting for its operation's.
+func (c *LimitedClient) acquire(ctx context.Context, operation string) (func(), error) {
+	op := c.ops[operation]
+	start := time.Now()
+	var deadline <-chan time.Time
+	if c.limits.QueueTimeout > 0 {
+		timer := time.NewTimer(c.limits.QueueTimeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	var held []semaphore
+	release := func() {
+		for _, slots := range held {
+			<-slots
+		}
+	}
+	for _, slots := range []semaphore{op.slots, c.total} {
+		if slots == nil {
+			continue
+		}
+		if err := c.wait(ctx, op, slots, deadline); err != nil {
+			release()
+			c.observe(operation, time.Since(start), err)
+			return nil, err
+		}
+		held = append(held, slots)
+	}
+	c.observe(operation, time.Since(start), nil)
+
+	op.inFlight.Add(1)
+	return func() {
+		op.inFlight.Add(-1)
+		release()
+	}, nil
+}
+
+// wait takes a slot, queueing when none is free.
+func (c *LimitedClient) wait(ctx context.Context, op *operationLimit, slots semaphore, deadline <-chan time.Time) error {
+	select {
+	case slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if queued := c.queued.Add(1); c.limits.MaxQueued > 0 && queued > int64(c.limits.MaxQueued) {
+		c.queued.Add(-1)
+		return ErrOverloaded
+	}
+	op.queued.Add(1)
+	defer func() {
+		c.queued.Add(-1)
+		op.queued.Add(-1)
+	}()
+
+	select {
+	case slots <- struct{}{}:
+		return nil
+	case <-deadline:
+		return ErrOverloaded
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *LimitedClient) observe(operation string, waited time.Duration, err error) {
+	if c.limits.OnWait != nil {
+		c.limits.OnWait(operation, waited, err)
+	}
+}
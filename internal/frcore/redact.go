@@ -0,0 +1,137 @@
+package frcore
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// LogLevel controls how much of each FR Core exchange is logged.
+type LogLevel string
+
+const (
+	// LogNone disables request and response logging.
+	LogNone LogLevel = "none"
+	// LogMetadata logs method, URL, status and redacted headers.
+	LogMetadata LogLevel = "metadata"
+	// LogBody additionally logs a redacted, truncated response body.
+	LogBody LogLevel = "body"
+)
+
+const (
+	redacted       = "[REDACTED]"
+	maxBodyPreview = 1024
+)
+
+// sensitiveHeaders never appear in logs.
+var sensitiveHeaders = map[string]bool{
+	"X-Api-Key":           true,
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// sensitiveFields are JSON keys whose values identify a person or carry biometrics.
+var sensitiveFields = map[string]bool{
+	"external_ref": true,
+	"image":        true,
+	"image_path":   true,
+	"embedding":    true,
+	"encoding":     true,
+	"name":         true,
+	"nik":          true,
+}
+
+// base64Pattern matches long base64 runs such as inline images.
+var base64Pattern = regexp.MustCompile(`^[A-Za-z0-9+/=_-]{256,}$`)
+
+func (c *apiClient) logRequest(req *http.Request, payloadSize int) {
+	if c.logLevel == LogNone {
+		return
+	}
+	log.Printf("[frcore] request method=%s url=%s headers=%v payload_bytes=%d trace_id=%s", req.Method, req.URL.String(), redactHeaders(req.Header), payloadSize, traceID(req))
+}
+
+func (c *apiClient) logResponse(resp *http.Response, body []byte) {
+	switch c.logLevel {
+	case LogNone:
+		return
+	case LogMetadata:
+		log.Printf("[frcore] response status=%d headers=%v body_bytes=%d trace_id=%s", resp.StatusCode, redactHeaders(resp.Header), len(body), traceID(resp.Request))
+	default:
+		log.Printf("[frcore] response status=%d headers=%v body=%s trace_id=%s", resp.StatusCode, redactHeaders(resp.Header), redactBody(body), traceID(resp.Request))
+	}
+}
+
+// redactHeaders flattens headers for logging with credentials masked.
+func redactHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for k, v := range header {
+		if len(v) == 0 {
+			continue
+		}
+		if sensitiveHeaders[http.CanonicalHeaderKey(k)] {
+			headers[k] = redacted
+			continue
+		}
+		headers[k] = v[0]
+	}
+	return headers
+}
+
+// redactBody renders a body for logs and error messages: binary data is
+// summarized, personal fields of JSON bodies are masked and the result is truncated.
+func redactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	if !utf8.Valid(body) || strings.IndexByte(string(body), 0) >= 0 {
+		return fmt.Sprintf("<binary %d bytes>", len(body))
+	}
+
+	preview := string(body)
+	var decoded interface{}
+	if err := json.Unmarshal(body, &decoded); err == nil {
+		if masked, err := json.Marshal(redactValue(decoded)); err == nil {
+			preview = string(masked)
+		}
+	} else if base64Pattern.MatchString(strings.TrimSpace(preview)) {
+		return fmt.Sprintf("<base64 %d bytes>", len(body))
+	}
+
+	if len(preview) > maxBodyPreview {
+		preview = preview[:maxBodyPreview] + "..."
+	}
+	return preview
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if sensitiveFields[strings.ToLower(key)] {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactValue(field)
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i])
+		}
+		return v
+	case string:
+		if base64Pattern.MatchString(v) {
+			return fmt.Sprintf("<base64 %d bytes>", len(v))
+		}
+		return v
+	default:
+		return v
+	}
+}
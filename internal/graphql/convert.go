@@ -0,0 +1,52 @@
+package graphql
+
+import (
+	"context"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/graphql/model"
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/pii"
+)
+
+// maskNIK hides NIKs, as a query can list many records, unless an admin
+// asked for them in full with ?unmasked=true.
+func maskNIK(ctx context.Context, nik string) string {
+	if middleware.Unmasked(ctx) {
+		return nik
+	}
+	return pii.NIK(nik)
+}
+
+func toParticipant(ctx context.Context, p *domain.Participant) *model.Participant {
+	return &model.Participant{
+		ID:           p.ID,
+		Nik:          maskNIK(ctx, p.NIK),
+		Name:         p.Name,
+		Status:       model.ParticipantStatus(p.Status),
+		StatusReason: p.StatusReason,
+		CreatedAt:    p.CreatedAt,
+		UpdatedAt:    p.UpdatedAt,
+		MemberID:     p.MemberID,
+	}
+}
+
+func toMember(ctx context.Context, m *domain.Member) *model.Member {
+	return &model.Member{
+		ID:           m.ID,
+		Nik:          maskNIK(ctx, m.NIK),
+		NomorPeserta: m.NomorPeserta,
+		FullName:     m.FullName,
+		BirthDate:    m.BirthDate,
+		City:         m.City,
+		Province:     m.Province,
+		Status:       string(m.Status),
+	}
+}
+
+func stringValue(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
@@ -0,0 +1,81 @@
+package graphql
+
+import (
+	"context"
+	"net/http"
+
+	gql "github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// NewHandler serves the schema over HTTP, rejecting operations nested deeper than maxDepth.
+func NewHandler(resolver *Resolver, maxDepth int) http.Handler {
+	srv := handler.New(NewExecutableSchema(Config{Resolvers: resolver}))
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.Use(extension.Introspection{})
+	srv.Use(DepthLimit{Max: maxDepth})
+	return srv
+}
+
+// DepthLimit rejects operations whose selection sets nest deeper than Max.
+type DepthLimit struct {
+	Max int
+}
+
+var _ interface {
+	gql.HandlerExtension
+	gql.OperationContextMutator
+} = DepthLimit{}
+
+// ExtensionName implements graphql.HandlerExtension.
+func (DepthLimit) ExtensionName() string {
+	return "DepthLimit"
+}
+
+// Validate implements graphql.HandlerExtension.
+func (DepthLimit) Validate(gql.ExecutableSchema) error {
+	return nil
+}
+
+// MutateOperationContext measures the operation before it executes.
+func (d DepthLimit) MutateOperationContext(_ context.Context, rc *gql.OperationContext) *gqlerror.Error {
+	if d.Max <= 0 || rc.Operation == nil {
+		return nil
+	}
+	if depth := selectionDepth(rc.Operation.SelectionSet, map[string]bool{}); depth > d.Max {
+		err := gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", depth, d.Max)
+		err.Extensions = map[string]interface{}{"code": "DEPTH_LIMIT_EXCEEDED"}
+		return err
+	}
+	return nil
+}
+
+// selectionDepth returns how many levels of fields the selection set nests, following fragments once.
+func selectionDepth(set ast.SelectionSet, visiting map[string]bool) int {
+	deepest := 0
+	for _, selection := range set {
+		var depth int
+		switch s := selection.(type) {
+		case *ast.Field:
+			depth = 1 + selectionDepth(s.SelectionSet, visiting)
+		case *ast.InlineFragment:
+			depth = selectionDepth(s.SelectionSet, visiting)
+		case *ast.FragmentSpread:
+			if s.Definition == nil || visiting[s.Name] {
+				continue
+			}
+			visiting[s.Name] = true
+			depth = selectionDepth(s.Definition.SelectionSet, visiting)
+			delete(visiting, s.Name)
+		}
+		if depth > deepest {
+			deepest = depth
+		}
+	}
+	return deepest
+}
@@ -0,0 +1,175 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type LifeCertificate struct {
+	Status     CertificateStatus `json:"status"`
+	Similarity *float64          `json:"similarity,omitempty"`
+	Distance   *float64          `json:"distance,omitempty"`
+	VerifiedAt *time.Time        `json:"verifiedAt,omitempty"`
+}
+
+type Member struct {
+	ID           string    `json:"id"`
+	Nik          string    `json:"nik"`
+	NomorPeserta string    `json:"nomorPeserta"`
+	FullName     string    `json:"fullName"`
+	BirthDate    time.Time `json:"birthDate"`
+	City         string    `json:"city"`
+	Province     string    `json:"province"`
+	Status       string    `json:"status"`
+}
+
+type Participant struct {
+	ID                string            `json:"id"`
+	Nik               string            `json:"nik"`
+	Name              string            `json:"name"`
+	Status            ParticipantStatus `json:"status"`
+	StatusReason      *string           `json:"statusReason,omitempty"`
+	CreatedAt         time.Time         `json:"createdAt"`
+	UpdatedAt         time.Time         `json:"updatedAt"`
+	MemberID          *string           `json:"memberId,omitempty"`
+	Member            *Member           `json:"member,omitempty"`
+	LatestCertificate *LifeCertificate  `json:"latestCertificate,omitempty"`
+	AttemptCount      int               `json:"attemptCount"`
+	NextDueAt         time.Time         `json:"nextDueAt"`
+}
+
+type ParticipantFilter struct {
+	Nik          *string            `json:"nik,omitempty"`
+	Name         *string            `json:"name,omitempty"`
+	FrLabel      *string            `json:"frLabel,omitempty"`
+	LatestStatus *CertificateStatus `json:"latestStatus,omitempty"`
+}
+
+type ParticipantPage struct {
+	Items    []*Participant `json:"items"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"pageSize"`
+	Total    int            `json:"total"`
+}
+
+type Query struct {
+}
+
+type CertificateStatus string
+
+const (
+	CertificateStatusValid   CertificateStatus = "VALID"
+	CertificateStatusInvalid CertificateStatus = "INVALID"
+	CertificateStatusReview  CertificateStatus = "REVIEW"
+)
+
+var AllCertificateStatus = []CertificateStatus{
+	CertificateStatusValid,
+	CertificateStatusInvalid,
+	CertificateStatusReview,
+}
+
+func (e CertificateStatus) IsValid() bool {
+	switch e {
+	case CertificateStatusValid, CertificateStatusInvalid, CertificateStatusReview:
+		return true
+	}
+	return false
+}
+
+func (e CertificateStatus) String() string {
+	return string(e)
+}
+
+func (e *CertificateStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CertificateStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CertificateStatus", str)
+	}
+	return nil
+}
+
+func (e CertificateStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *CertificateStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e CertificateStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ParticipantStatus string
+
+const (
+	ParticipantStatusActive    ParticipantStatus = "ACTIVE"
+	ParticipantStatusSuspended ParticipantStatus = "SUSPENDED"
+	ParticipantStatusBlocked   ParticipantStatus = "BLOCKED"
+)
+
+var AllParticipantStatus = []ParticipantStatus{
+	ParticipantStatusActive,
+	ParticipantStatusSuspended,
+	ParticipantStatusBlocked,
+}
+
+func (e ParticipantStatus) IsValid() bool {
+	switch e {
+	case ParticipantStatusActive, ParticipantStatusSuspended, ParticipantStatusBlocked:
+		return true
+	}
+	return false
+}
+
+func (e ParticipantStatus) String() string {
+	return string(e)
+}
+
+func (e *ParticipantStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ParticipantStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ParticipantStatus", str)
+	}
+	return nil
+}
+
+func (e ParticipantStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ParticipantStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ParticipantStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
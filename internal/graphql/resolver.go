@@ -0,0 +1,10 @@
+package graphql
+
+import "life-certificates/internal/service"
+
+// Resolver exposes the existing services to the GraphQL schema.
+type Resolver struct {
+	ParticipantService  *service.ParticipantService
+	MemberService       *service.MemberService
+	VerificationService *service.VerificationService
+}
@@ -0,0 +1,125 @@
+package graphql
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.78
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"life-certificates/internal/graphql/model"
+	"life-certificates/internal/service"
+)
+
+// Member is the resolver for the member field.
+func (r *participantResolver) Member(ctx context.Context, obj *model.Participant) (*model.Member, error) {
+	if obj.MemberID == nil {
+		return nil, nil
+	}
+	return r.Query().Member(ctx, *obj.MemberID)
+}
+
+// LatestCertificate is the resolver for the latestCertificate field.
+func (r *participantResolver) LatestCertificate(ctx context.Context, obj *model.Participant) (*model.LifeCertificate, error) {
+	latest, err := r.VerificationService.LatestStatus(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	if latest.Status == "" {
+		return nil, nil
+	}
+	return &model.LifeCertificate{
+		Status:     model.CertificateStatus(latest.Status),
+		Similarity: latest.Similarity,
+		Distance:   latest.Distance,
+		VerifiedAt: latest.VerifiedAt,
+	}, nil
+}
+
+// AttemptCount is the resolver for the attemptCount field.
+func (r *participantResolver) AttemptCount(ctx context.Context, obj *model.Participant) (int, error) {
+	detail, err := r.ParticipantService.Get(ctx, obj.ID)
+	if err != nil {
+		return 0, err
+	}
+	return int(detail.Summary.TotalAttempts), nil
+}
+
+// NextDueAt is the resolver for the nextDueAt field.
+func (r *participantResolver) NextDueAt(ctx context.Context, obj *model.Participant) (*time.Time, error) {
+	detail, err := r.ParticipantService.Get(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &detail.Summary.NextDueAt, nil
+}
+
+// Participants is the resolver for the participants field.
+func (r *queryResolver) Participants(ctx context.Context, filter *model.ParticipantFilter, page *int, pageSize *int) (*model.ParticipantPage, error) {
+	input := service.SearchParticipantsInput{}
+	if filter != nil {
+		input.NIK = stringValue(filter.Nik)
+		input.Name = stringValue(filter.Name)
+		input.FRLabel = stringValue(filter.FrLabel)
+		if filter.LatestStatus != nil {
+			input.Status = filter.LatestStatus.String()
+		}
+	}
+	if page != nil {
+		input.Page = *page
+	}
+	if pageSize != nil {
+		input.PageSize = *pageSize
+	}
+
+	result, err := r.ParticipantService.Search(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*model.Participant, 0, len(result.Participants))
+	for i := range result.Participants {
+		items = append(items, toParticipant(ctx, &result.Participants[i]))
+	}
+	return &model.ParticipantPage{
+		Items:    items,
+		Page:     result.Page,
+		PageSize: result.PageSize,
+		Total:    int(result.Total),
+	}, nil
+}
+
+// Participant is the resolver for the participant field.
+func (r *queryResolver) Participant(ctx context.Context, id string) (*model.Participant, error) {
+	detail, err := r.ParticipantService.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, service.ErrParticipantNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toParticipant(ctx, &detail.Participant), nil
+}
+
+// Member is the resolver for the member field.
+func (r *queryResolver) Member(ctx context.Context, id string) (*model.Member, error) {
+	member, err := r.MemberService.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, service.ErrMemberNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toMember(ctx, member), nil
+}
+
+// Participant returns ParticipantResolver implementation.
+func (r *Resolver) Participant() ParticipantResolver { return &participantResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+type participantResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
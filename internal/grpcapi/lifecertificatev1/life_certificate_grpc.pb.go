@@ -0,0 +1,252 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: lifecertificate/v1/life_certificate.proto
+
+package lifecertificatev1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	LifeCertificateService_RegisterParticipant_FullMethodName = "/lifecertificate.v1.LifeCertificateService/RegisterParticipant"
+	LifeCertificateService_Verify_FullMethodName              = "/lifecertificate.v1.LifeCertificateService/Verify"
+	LifeCertificateService_GetStatus_FullMethodName           = "/lifecertificate.v1.LifeCertificateService/GetStatus"
+	LifeCertificateService_ListParticipants_FullMethodName    = "/lifecertificate.v1.LifeCertificateService/ListParticipants"
+)
+
+// LifeCertificateServiceClient is the client API for LifeCertificateService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// LifeCertificateService exposes participant registration and life certificate
+// verification to internal services. Calls require Basic credentials in the
+// "authorization" metadata, the same accounts as the HTTP API.
+type LifeCertificateServiceClient interface {
+	// RegisterParticipant registers a participant and enrolls their face with FR Core.
+	RegisterParticipant(ctx context.Context, in *RegisterParticipantRequest, opts ...grpc.CallOption) (*RegisterParticipantResponse, error)
+	// Verify submits a selfie for a life certificate verification.
+	Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error)
+	// GetStatus returns the participant's latest verification.
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error)
+	// ListParticipants pages through participants with optional filters.
+	ListParticipants(ctx context.Context, in *ListParticipantsRequest, opts ...grpc.CallOption) (*ListParticipantsResponse, error)
+}
+
+type lifeCertificateServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLifeCertificateServiceClient(cc grpc.ClientConnInterface) LifeCertificateServiceClient {
+	return &lifeCertificateServiceClient{cc}
+}
+
+func (c *lifeCertificateServiceClient) RegisterParticipant(ctx context.Context, in *RegisterParticipantRequest, opts ...grpc.CallOption) (*RegisterParticipantResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterParticipantResponse)
+	err := c.cc.Invoke(ctx, LifeCertificateService_RegisterParticipant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lifeCertificateServiceClient) Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyResponse)
+	err := c.cc.Invoke(ctx, LifeCertificateService_Verify_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lifeCertificateServiceClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStatusResponse)
+	err := c.cc.Invoke(ctx, LifeCertificateService_GetStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lifeCertificateServiceClient) ListParticipants(ctx context.Context, in *ListParticipantsRequest, opts ...grpc.CallOption) (*ListParticipantsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListParticipantsResponse)
+	err := c.cc.Invoke(ctx, LifeCertificateService_ListParticipants_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LifeCertificateServiceServer is the server API for LifeCertificateService service.
+// All implementations must embed UnimplementedLifeCertificateServiceServer
+// for forward compatibility.
+//
+// LifeCertificateService exposes participant registration and life certificate
+// verification to internal services. Calls require Basic credentials in the
+// "authorization" metadata, the same accounts as the HTTP API.
+type LifeCertificateServiceServer interface {
+	// RegisterParticipant registers a participant and enrolls their face with FR Core.
+	RegisterParticipant(context.Context, *RegisterParticipantRequest) (*RegisterParticipantResponse, error)
+	// Verify submits a selfie for a life certificate verification.
+	Verify(context.Context, *VerifyRequest) (*VerifyResponse, error)
+	// GetStatus returns the participant's latest verification.
+	GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error)
+	// ListParticipants pages through participants with optional filters.
+	ListParticipants(context.Context, *ListParticipantsRequest) (*ListParticipantsResponse, error)
+	mustEmbedUnimplementedLifeCertificateServiceServer()
+}
+
+// UnimplementedLifeCertificateServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedLifeCertificateServiceServer struct{}
+
+func (UnimplementedLifeCertificateServiceServer) RegisterParticipant(context.Context, *RegisterParticipantRequest) (*RegisterParticipantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterParticipant not implemented")
+}
+func (UnimplementedLifeCertificateServiceServer) Verify(context.Context, *VerifyRequest) (*VerifyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Verify not implemented")
+}
+func (UnimplementedLifeCertificateServiceServer) GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedLifeCertificateServiceServer) ListParticipants(context.Context, *ListParticipantsRequest) (*ListParticipantsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListParticipants not implemented")
+}
+func (UnimplementedLifeCertificateServiceServer) mustEmbedUnimplementedLifeCertificateServiceServer() {
+}
+func (UnimplementedLifeCertificateServiceServer) testEmbeddedByValue() {}
+
+// UnsafeLifeCertificateServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LifeCertificateServiceServer will
+// result in compilation errors.
+type UnsafeLifeCertificateServiceServer interface {
+	mustEmbedUnimplementedLifeCertificateServiceServer()
+}
+
+func RegisterLifeCertificateServiceServer(s grpc.ServiceRegistrar, srv LifeCertificateServiceServer) {
+	// If the following call pancis, it indicates UnimplementedLifeCertificateServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&LifeCertificateService_ServiceDesc, srv)
+}
+
+func _LifeCertificateService_RegisterParticipant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterParticipantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LifeCertificateServiceServer).RegisterParticipant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LifeCertificateService_RegisterParticipant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LifeCertificateServiceServer).RegisterParticipant(ctx, req.(*RegisterParticipantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LifeCertificateService_Verify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LifeCertificateServiceServer).Verify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LifeCertificateService_Verify_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LifeCertificateServiceServer).Verify(ctx, req.(*VerifyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LifeCertificateService_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LifeCertificateServiceServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LifeCertificateService_GetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LifeCertificateServiceServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LifeCertificateService_ListParticipants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListParticipantsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LifeCertificateServiceServer).ListParticipants(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LifeCertificateService_ListParticipants_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LifeCertificateServiceServer).ListParticipants(ctx, req.(*ListParticipantsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// LifeCertificateService_ServiceDesc is the grpc.ServiceDesc for LifeCertificateService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LifeCertificateService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "lifecertificate.v1.LifeCertificateService",
+	HandlerType: (*LifeCertificateServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterParticipant",
+			Handler:    _LifeCertificateService_RegisterParticipant_Handler,
+		},
+		{
+			MethodName: "Verify",
+			Handler:    _LifeCertificateService_Verify_Handler,
+		},
+		{
+			MethodName: "GetStatus",
+			Handler:    _LifeCertificateService_GetStatus_Handler,
+		},
+		{
+			MethodName: "ListParticipants",
+			Handler:    _LifeCertificateService_ListParticipants_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "lifecertificate/v1/life_certificate.proto",
+}
@@ -0,0 +1,302 @@
+// Package grpcapi serves the participant and verification operations over gRPC
+// on top of the same service layer as the HTTP API.
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"life-certificates/internal/config"
+	"life-certificates/internal/domain"
+	pb "life-certificates/internal/grpcapi/lifecertificatev1"
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/pii"
+	"life-certificates/internal/service"
+)
+
+// maxMessageBytes matches the multipart limit of the HTTP API so selfies fit.
+const maxMessageBytes = 20 << 20
+
+// Server wraps the gRPC server lifecycle.
+type Server struct {
+	grpcServer *grpc.Server
+	addr       string
+}
+
+// NewServer registers the LifeCertificateService behind Basic Auth.
+func NewServer(cfg *config.Config, participants *service.ParticipantService, verification *service.VerificationService, sessions *service.VerificationSessionService, unmask middleware.UnmaskRecorder, tenants middleware.TenantLookup) *Server {
+	grpcServer := grpc.NewServer(
+		grpc.MaxRecvMsgSize(maxMessageBytes),
+		grpc.ChainUnaryInterceptor(basicAuthInterceptor(middleware.CredentialsFromConfig(cfg), tenants)),
+	)
+	pb.RegisterLifeCertificateServiceServer(grpcServer, &lifeCertificateServer{
+		participants: participants,
+		verification: verification,
+		sessions:     sessions,
+		unmask:       unmask,
+	})
+
+	return &Server{grpcServer: grpcServer, addr: fmt.Sprintf("%s:%d", cfg.GRPC.Host, cfg.GRPC.Port)}
+}
+
+// Start listens on the configured address and serves until Shutdown.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	return s.grpcServer.Serve(listener)
+}
+
+// Shutdown stops accepting calls and waits for in-flight calls until ctx expires.
+func (s *Server) Shutdown(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+	}
+}
+
+// basicAuthInterceptor authenticates calls with the "authorization" metadata
+// and stores the actor and role on the context like the HTTP middleware, along
+// with the tenant named by the "x-tenant-id" metadata.
+func basicAuthInterceptor(credentials []middleware.Credential, tenants middleware.TenantLookup) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "missing authorization metadata")
+		}
+		credential, ok := middleware.Authenticate(values[0], credentials)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "invalid credentials")
+		}
+		ctx = middleware.WithRole(middleware.WithActor(ctx, credential.Username), credential.Role)
+		if credential.Tenant != "" {
+			ctx = middleware.WithBoundTenant(ctx, credential.Tenant)
+		}
+
+		var requested string
+		if values := md.Get("x-tenant-id"); len(values) > 0 {
+			requested = values[0]
+		}
+		ctx, err := middleware.SelectTenant(ctx, tenants, requested)
+		switch {
+		case errors.Is(err, middleware.ErrTenantUnknown):
+			return nil, status.Error(codes.NotFound, err.Error())
+		case errors.Is(err, middleware.ErrTenantNotBound), errors.Is(err, middleware.ErrTenantDisabled):
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		case err != nil:
+			return nil, status.Error(codes.Internal, "could not check tenant")
+		}
+		return handler(ctx, req)
+	}
+}
+
+type lifeCertificateServer struct {
+	pb.UnimplementedLifeCertificateServiceServer
+	participants *service.ParticipantService
+	verification *service.VerificationService
+	sessions     *service.VerificationSessionService
+	unmask       middleware.UnmaskRecorder
+}
+
+func (s *lifeCertificateServer) RegisterParticipant(ctx context.Context, req *pb.RegisterParticipantRequest) (*pb.RegisterParticipantResponse, error) {
+	out, err := s.participants.Register(ctx, service.RegisterInput{
+		NIK:       req.GetNik(),
+		Name:      req.GetName(),
+		Image:     req.GetImage(),
+		ImageName: req.GetImageName(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.RegisterParticipantResponse{
+		ParticipantId: out.ParticipantID,
+		MemberId:      out.MemberID,
+		FrRef:         out.FRRef,
+		FrExternalRef: out.FRExternalRef,
+	}, nil
+}
+
+func (s *lifeCertificateServer) Verify(ctx context.Context, req *pb.VerifyRequest) (*pb.VerifyResponse, error) {
+	input := service.VerifyInput{
+		ParticipantID:    req.GetParticipantId(),
+		ImageBytes:       req.GetImage(),
+		OriginalFilename: req.GetImageName(),
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			input.ClientIP = host
+		}
+	}
+	// The device fingerprint travels in metadata, like the X-Device-* headers over HTTP.
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get("x-device-id"); len(values) > 0 {
+		input.DeviceID = values[0]
+	}
+	if values := md.Get("x-device-model"); len(values) > 0 {
+		input.DeviceModel = values[0]
+	}
+	if values := md.Get("x-device-os"); len(values) > 0 {
+		input.DeviceOS = values[0]
+	}
+	var sessionToken string
+	if values := md.Get("x-verification-session"); len(values) > 0 {
+		sessionToken = values[0]
+	}
+	input, err := s.sessions.Attach(ctx, sessionToken, input)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	out, err := s.verification.Verify(ctx, input)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.VerifyResponse{
+		ParticipantId: out.ParticipantID,
+		Status:        string(out.Status),
+		Similarity:    out.Similarity,
+		Distance:      out.Distance,
+		VerifiedAt:    timestamppb.New(out.VerifiedAt),
+	}, nil
+}
+
+func (s *lifeCertificateServer) GetStatus(ctx context.Context, req *pb.GetStatusRequest) (*pb.GetStatusResponse, error) {
+	out, err := s.verification.LatestStatus(ctx, req.GetParticipantId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.GetStatusResponse{
+		ParticipantId: out.ParticipantID,
+		Status:        string(out.Status),
+		Similarity:    out.Similarity,
+		Distance:      out.Distance,
+		VerifiedAt:    optionalTimestamp(out.VerifiedAt),
+	}, nil
+}
+
+// ListParticipants masks NIKs like the HTTP lists. Admins get them in full
+// with "x-unmasked: true" metadata, and each such call is recorded.
+func (s *lifeCertificateServer) ListParticipants(ctx context.Context, req *pb.ListParticipantsRequest) (*pb.ListParticipantsResponse, error) {
+	unmasked, err := unmaskRequested(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out, err := s.participants.Search(ctx, service.SearchParticipantsInput{
+		NIK:      req.GetNik(),
+		Name:     req.GetName(),
+		Status:   req.GetStatus(),
+		Page:     int(req.GetPage()),
+		PageSize: int(req.GetPageSize()),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	participants := make([]*pb.Participant, 0, len(out.Participants))
+	for _, p := range out.Participants {
+		if !unmasked {
+			p.NIK = pii.NIK(p.NIK)
+		}
+		participants = append(participants, toParticipant(p))
+	}
+	if unmasked {
+		var remoteAddr string
+		if p, ok := peer.FromContext(ctx); ok {
+			remoteAddr = p.Addr.String()
+		}
+		query := fmt.Sprintf("nik=%s&name=%s&status=%s&page=%d&page_size=%d", req.GetNik(), req.GetName(), req.GetStatus(), req.GetPage(), req.GetPageSize())
+		if err := s.unmask.RecordUnmask(context.WithoutCancel(ctx), middleware.Actor(ctx), pb.LifeCertificateService_ListParticipants_FullMethodName, query, remoteAddr); err != nil {
+			log.Printf("record unmasked read of %s: %v", pb.LifeCertificateService_ListParticipants_FullMethodName, err)
+		}
+	}
+	return &pb.ListParticipantsResponse{
+		Participants: participants,
+		Page:         int32(out.Page),
+		PageSize:     int32(out.PageSize),
+		Total:        out.Total,
+	}, nil
+}
+
+// unmaskRequested reads the "x-unmasked" metadata, which only admins may set.
+func unmaskRequested(ctx context.Context) (bool, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("x-unmasked")
+	if len(values) == 0 || values[0] == "" {
+		return false, nil
+	}
+	unmasked, err := strconv.ParseBool(values[0])
+	if err != nil {
+		return false, status.Error(codes.InvalidArgument, "x-unmasked must be true or false")
+	}
+	if unmasked && middleware.Role(ctx) != middleware.RoleAdmin {
+		return false, status.Error(codes.PermissionDenied, "unmasked data requires the admin role")
+	}
+	return unmasked, nil
+}
+
+func toParticipant(p domain.Participant) *pb.Participant {
+	return &pb.Participant{
+		ParticipantId: p.ID,
+		Nik:           p.NIK,
+		Name:          p.Name,
+		MemberId:      p.MemberID,
+		Status:        string(p.Status),
+		CreatedAt:     timestamppb.New(p.CreatedAt),
+		UpdatedAt:     timestamppb.New(p.UpdatedAt),
+	}
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// toStatus maps service errors to gRPC codes, mirroring the HTTP status codes.
+func toStatus(err error) error {
+	var verr *service.ValidationError
+	switch {
+	case errors.As(err, &verr):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrParticipantNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrParticipantExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, service.ErrParticipantSuspended), errors.Is(err, service.ErrParticipantBlocked):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, service.ErrVerificationSessionRequired):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, service.ErrInvalidVerificationSession), errors.Is(err, service.ErrVerificationSessionMismatch):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, service.ErrConsentRequired):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, service.ErrAttemptLimitReached):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, service.ErrVerificationLocked):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, service.ErrFRCoreBusy):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// AccessLogHandler exposes the personal data access log.
+type AccessLogHandler struct {
+	service *service.AccessLogService
+}
+
+// NewAccessLogHandler wires dependencies for access log endpoints.
+func NewAccessLogHandler(service *service.AccessLogService) *AccessLogHandler {
+	return &AccessLogHandler{service: service}
+}
+
+// List godoc
+// @Summary Query the access log
+// @Description Who read which participant, member or certificate and when, newest first (admin only)
+// @Tags Admin
+// @Security BasicAuth
+// @Produce json
+// @Param actor query string false "Operator who read the data"
+// @Param resource_type query string false "participant, member, certificate_status or life_certificate"
+// @Param resource_id query string false "Resource ID"
+// @Param from query string false "Reads on or after date (YYYY-MM-DD)"
+// @Param to query string false "Reads on or before date (YYYY-MM-DD)"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/access-logs [get]
+func (h *AccessLogHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, pageSize, err := pageParams(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	out, err := h.service.List(r.Context(), service.AccessLogQueryInput{
+		Actor:        query.Get("actor"),
+		ResourceType: query.Get("resource_type"),
+		ResourceID:   query.Get("resource_id"),
+		From:         query.Get("from"),
+		To:           query.Get("to"),
+		Page:         page,
+		PageSize:     pageSize,
+	})
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, out)
+}
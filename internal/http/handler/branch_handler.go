@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// BranchHandler manages the branch registry and its geofences.
+type BranchHandler struct {
+	service *service.BranchService
+}
+
+// NewBranchHandler wires dependencies for branch endpoints.
+func NewBranchHandler(service *service.BranchService) *BranchHandler {
+	return &BranchHandler{service: service}
+}
+
+// Create godoc
+// @Summary Register a branch
+// @Description Code is the branch_id kiosks and officers are assigned to; kiosk, manual and proxy verifications attributed to the branch must be submitted within radius_meters of its latitude and longitude or they are flagged (admin only)
+// @Tags Admin
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param payload body service.BranchInput true "Branch"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/branches [post]
+func (h *BranchHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req service.BranchInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	branch, err := h.service.Create(r.Context(), middleware.Actor(r.Context()), req)
+	if err != nil {
+		writeBranchError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, branch)
+}
+
+// List godoc
+// @Summary List branches
+// @Tags Admin
+// @Security BasicAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/branches [get]
+func (h *BranchHandler) List(w http.ResponseWriter, r *http.Request) {
+	branches, err := h.service.List(r.Context())
+	if err != nil {
+		writeBranchError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, branches)
+}
+
+// Get godoc
+// @Summary Get a branch
+// @Tags Admin
+// @Security BasicAuth
+// @Produce json
+// @Param branch_id path string true "Branch ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/branches/{branch_id} [get]
+func (h *BranchHandler) Get(w http.ResponseWriter, r *http.Request) {
+	branch, err := h.service.Get(r.Context(), chi.URLParam(r, "branch_id"))
+	if err != nil {
+		writeBranchError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, branch)
+}
+
+// Update godoc
+// @Summary Update a branch
+// @Description Only the provided fields change; the code is fixed once registered (admin only)
+// @Tags Admin
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param branch_id path string true "Branch ID"
+// @Param payload body service.UpdateBranchInput true "Fields to change"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/branches/{branch_id} [patch]
+func (h *BranchHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var req service.UpdateBranchInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	branch, err := h.service.Update(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "branch_id"), req)
+	if err != nil {
+		writeBranchError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, branch)
+}
+
+// Delete godoc
+// @Summary Delete a branch
+// @Description Refused while an active kiosk or officer belongs to the branch (admin only)
+// @Tags Admin
+// @Security BasicAuth
+// @Param branch_id path string true "Branch ID"
+// @Success 204
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/branches/{branch_id} [delete]
+func (h *BranchHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.Delete(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "branch_id")); err != nil {
+		writeBranchError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeBranchError(w http.ResponseWriter, err error) {
+	var verr *service.ValidationError
+	if errors.As(err, &verr) {
+		response.ValidationError(w, "validation failed", verr.Fields)
+		return
+	}
+	switch err {
+	case service.ErrBranchNotFound:
+		response.Error(w, http.StatusNotFound, err.Error())
+	case service.ErrBranchConflict, service.ErrBranchInUse:
+		response.Error(w, http.StatusConflict, err.Error())
+	default:
+		response.Error(w, http.StatusInternalServerError, err.Error())
+	}
+}
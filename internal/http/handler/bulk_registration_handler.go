@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// maxBulkArchiveBytes caps the size of an uploaded bulk registration archive.
+const maxBulkArchiveBytes = 512 << 20
+
+// BulkRegistrationHandler exposes bulk participant registration endpoints.
+type BulkRegistrationHandler struct {
+	service *service.BulkRegistrationService
+}
+
+// NewBulkRegistrationHandler wires dependencies for bulk registration endpoints.
+func NewBulkRegistrationHandler(service *service.BulkRegistrationService) *BulkRegistrationHandler {
+	return &BulkRegistrationHandler{service: service}
+}
+
+// Submit godoc
+// @Summary Bulk register participants
+// @Description Upload a ZIP archive containing manifest.csv (nik,name,image) and the referenced selfies. Rows are registered asynchronously.
+// @Tags Participants
+// @Security BasicAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param archive formData file true "ZIP archive with manifest.csv and images"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /participants/bulk-register [post]
+func (h *BulkRegistrationHandler) Submit(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBulkArchiveBytes)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		response.Error(w, http.StatusBadRequest, "failed to parse multipart form")
+		return
+	}
+
+	file, header, err := r.FormFile("archive")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "archive file is required")
+		return
+	}
+	defer file.Close()
+
+	archive, err := io.ReadAll(file)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "failed to read archive")
+		return
+	}
+
+	job, err := h.service.Submit(r.Context(), service.BulkRegisterInput{
+		SourceName: header.Filename,
+		Archive:    archive,
+	})
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusAccepted, job)
+}
+
+// Status godoc
+// @Summary Get bulk registration progress
+// @Tags Participants
+// @Security BasicAuth
+// @Produce json
+// @Param job_id path string true "Bulk registration job ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /participants/bulk-register/{job_id} [get]
+func (h *BulkRegistrationHandler) Status(w http.ResponseWriter, r *http.Request) {
+	job, err := h.service.GetJob(r.Context(), chi.URLParam(r, "job_id"))
+	if err != nil {
+		switch err {
+		case service.ErrBulkJobNotFound:
+			response.Error(w, http.StatusNotFound, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, job)
+}
+
+// Failures godoc
+// @Summary Get bulk registration failure report
+// @Description Lists every manifest row that failed, with the reason
+// @Tags Participants
+// @Security BasicAuth
+// @Produce json
+// @Param job_id path string true "Bulk registration job ID"
+// @Param unmasked query bool false "Return personal identifiers in full (admin only, audit-logged)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /participants/bulk-register/{job_id}/failures [get]
+func (h *BulkRegistrationHandler) Failures(w http.ResponseWriter, r *http.Request) {
+	report, err := h.service.FailureReport(r.Context(), chi.URLParam(r, "job_id"))
+	if err != nil {
+		switch err {
+		case service.ErrBulkJobNotFound:
+			response.Error(w, http.StatusNotFound, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	maskBulkRows(r, report.Failures)
+	response.Success(w, http.StatusOK, report)
+}
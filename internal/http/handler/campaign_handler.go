@@ -0,0 +1,365 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// CampaignHandler exposes life certificate campaign endpoints.
+type CampaignHandler struct {
+	service *service.CampaignService
+}
+
+// NewCampaignHandler wires dependencies for campaign endpoints.
+func NewCampaignHandler(service *service.CampaignService) *CampaignHandler {
+	return &CampaignHandler{service: service}
+}
+
+// Create godoc
+// @Summary Create a verification campaign
+// @Description Enrols every active participant matching the optional targeting rules (fund, province or provinces, min_age, last_verified_before) and not on an exclusion list in force on starts_at; ANNUAL campaigns share the due date, BIRTHDAY_MONTH campaigns make each participant due at the end of their birthday month within the window (admin only)
+// @Tags Campaign
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param payload body service.CreateCampaignInput true "Campaign"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /campaigns [post]
+func (h *CampaignHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req service.CreateCampaignInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	campaign, err := h.service.Create(r.Context(), middleware.Actor(r.Context()), req)
+	if err != nil {
+		writeCampaignError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, campaign)
+}
+
+// Preview godoc
+// @Summary Preview a campaign's target population
+// @Description Counts the active participants the targeting rules match, those of them on an exclusion list in force on starts_at and the rest a campaign would enrol
+// @Tags Campaign
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param payload body service.CampaignPreviewInput true "Targeting rules"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /campaigns/preview [post]
+func (h *CampaignHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	var req service.CampaignPreviewInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	preview, err := h.service.Preview(r.Context(), req)
+	if err != nil {
+		writeCampaignError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, preview)
+}
+
+// AddExclusion godoc
+// @Summary Put a participant on an exclusion list
+// @Description Keeps the participant out of campaigns starting while the exclusion is in force, up to the optional until date (admin only)
+// @Tags Campaign
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param payload body service.CampaignExclusionInput true "Exclusion"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /campaigns/exclusions [post]
+func (h *CampaignHandler) AddExclusion(w http.ResponseWriter, r *http.Request) {
+	var req service.CampaignExclusionInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	exclusion, err := h.service.AddExclusion(r.Context(), middleware.Actor(r.Context()), req)
+	if err != nil {
+		writeCampaignError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, exclusion)
+}
+
+// ListExclusions godoc
+// @Summary List campaign exclusions
+// @Tags Campaign
+// @Security BasicAuth
+// @Produce json
+// @Param list query string false "HOSPITALIZED or ABROAD"
+// @Param participant_id query string false "Participant ID"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /campaigns/exclusions [get]
+func (h *CampaignHandler) ListExclusions(w http.ResponseWriter, r *http.Request) {
+	page, pageSize, err := pageParams(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	out, err := h.service.ListExclusions(r.Context(), service.CampaignExclusionsInput{
+		List:          r.URL.Query().Get("list"),
+		ParticipantID: r.URL.Query().Get("participant_id"),
+		Page:          page,
+		PageSize:      pageSize,
+	})
+	if err != nil {
+		writeCampaignError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, out)
+}
+
+// RemoveExclusion godoc
+// @Summary Take a participant off an exclusion list
+// @Description Campaigns already created keep their population (admin only)
+// @Tags Campaign
+// @Security BasicAuth
+// @Produce json
+// @Param exclusion_id path string true "Exclusion ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /campaigns/exclusions/{exclusion_id} [delete]
+func (h *CampaignHandler) RemoveExclusion(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.RemoveExclusion(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "exclusion_id")); err != nil {
+		writeCampaignError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"message": "campaign exclusion removed"})
+}
+
+// List godoc
+// @Summary List verification campaigns
+// @Tags Campaign
+// @Security BasicAuth
+// @Produce json
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /campaigns [get]
+func (h *CampaignHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, pageSize, err := pageParams(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	out, err := h.service.List(r.Context(), page, pageSize)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, out)
+}
+
+// Progress godoc
+// @Summary Campaign progress
+// @Description Participants pending, completed and overdue, with the completion rate
+// @Tags Campaign
+// @Security BasicAuth
+// @Produce json
+// @Param campaign_id path string true "Campaign ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /campaigns/{campaign_id} [get]
+func (h *CampaignHandler) Progress(w http.ResponseWriter, r *http.Request) {
+	progress, err := h.service.Progress(r.Context(), chi.URLParam(r, "campaign_id"))
+	if err != nil {
+		writeCampaignError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, progress)
+}
+
+// Participants godoc
+// @Summary Campaign participants
+// @Description Earliest due first, with each participant's campaign status
+// @Tags Campaign
+// @Security BasicAuth
+// @Produce json
+// @Param campaign_id path string true "Campaign ID"
+// @Param status query string false "PENDING, COMPLETED or OVERDUE"
+// @Param stage query string false "REMINDER, WARNING or HOLD_RECOMMENDED"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /campaigns/{campaign_id}/participants [get]
+func (h *CampaignHandler) Participants(w http.ResponseWriter, r *http.Request) {
+	page, pageSize, err := pageParams(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	out, err := h.service.Participants(r.Context(), chi.URLParam(r, "campaign_id"), service.CampaignParticipantsInput{
+		Status:   r.URL.Query().Get("status"),
+		Stage:    r.URL.Query().Get("stage"),
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		writeCampaignError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, out)
+}
+
+// UpdateEscalation godoc
+// @Summary Set a campaign's escalation policy
+// @Description Replaces the days after their due date at which overdue participants are escalated to REMINDER, WARNING and HOLD_RECOMMENDED; a null stage is skipped. Participants keep the stages they already reached (admin only)
+// @Tags Campaign
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param campaign_id path string true "Campaign ID"
+// @Param payload body domain.CampaignEscalation true "Escalation policy"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /campaigns/{campaign_id}/escalation [put]
+func (h *CampaignHandler) UpdateEscalation(w http.ResponseWriter, r *http.Request) {
+	var req domain.CampaignEscalation
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	campaign, err := h.service.UpdateEscalation(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "campaign_id"), req)
+	if err != nil {
+		writeCampaignError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, campaign)
+}
+
+// Notify godoc
+// @Summary Remind a campaign's participants
+// @Description Starts a kickoff that queues a reminder for every participant yet to complete the campaign on their preferred channels, spread out at CAMPAIGN_NOTIFY_PER_MINUTE; follow it with the notification progress endpoint (admin only)
+// @Tags Campaign
+// @Security BasicAuth
+// @Produce json
+// @Param campaign_id path string true "Campaign ID"
+// @Success 202 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /campaigns/{campaign_id}/notify [post]
+func (h *CampaignHandler) Notify(w http.ResponseWriter, r *http.Request) {
+	kickoff, err := h.service.Notify(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "campaign_id"))
+	if err != nil {
+		writeCampaignError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusAccepted, kickoff)
+}
+
+// Notifications godoc
+// @Summary List a campaign's notification kickoffs
+// @Description Latest first, with each kickoff's status and how many participants it has reminded
+// @Tags Campaign
+// @Security BasicAuth
+// @Produce json
+// @Param campaign_id path string true "Campaign ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /campaigns/{campaign_id}/notifications [get]
+func (h *CampaignHandler) Notifications(w http.ResponseWriter, r *http.Request) {
+	kickoffs, err := h.service.Notifications(r.Context(), chi.URLParam(r, "campaign_id"))
+	if err != nil {
+		writeCampaignError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{"items": kickoffs})
+}
+
+// NotificationProgress godoc
+// @Summary Campaign notification kickoff progress
+// @Description The kickoff's progress with its deliveries pending, sent, failed and skipped
+// @Tags Campaign
+// @Security BasicAuth
+// @Produce json
+// @Param campaign_id path string true "Campaign ID"
+// @Param notification_id path string true "Kickoff ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /campaigns/{campaign_id}/notifications/{notification_id} [get]
+func (h *CampaignHandler) NotificationProgress(w http.ResponseWriter, r *http.Request) {
+	progress, err := h.service.NotificationProgress(r.Context(), chi.URLParam(r, "campaign_id"), chi.URLParam(r, "notification_id"))
+	if err != nil {
+		writeCampaignError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, progress)
+}
+
+func writeCampaignError(w http.ResponseWriter, err error) {
+	var verr *service.ValidationError
+	if errors.As(err, &verr) {
+		response.ValidationError(w, "validation failed", verr.Fields)
+		return
+	}
+	switch err {
+	case service.ErrCampaignNotFound, service.ErrCampaignExclusionNotFound, service.ErrParticipantNotFound, service.ErrCampaignNotificationNotFound:
+		response.Error(w, http.StatusNotFound, err.Error())
+	case service.ErrCampaignExclusionExists, service.ErrCampaignNotificationInProgress, service.ErrNotificationsDisabled:
+		response.Error(w, http.StatusConflict, err.Error())
+	default:
+		response.Error(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// CertificatePDFHandler serves the official PDF certificates.
+type CertificatePDFHandler struct {
+	service *service.CertificatePDFService
+}
+
+// NewCertificatePDFHandler wires dependencies for PDF certificate downloads.
+func NewCertificatePDFHandler(service *service.CertificatePDFService) *CertificatePDFHandler {
+	return &CertificatePDFHandler{service: service}
+}
+
+// CertificateSignatureHeader carries the PDF's detached JWS when signing is enabled.
+const CertificateSignatureHeader = "X-Certificate-Signature"
+
+// Download godoc
+// @Summary Download the PDF certificate of a VALID verification
+// @Description The official certificate with the participant's details, selfie thumbnail, scores and receipt QR code. It is generated after the verification and on first download when missing. With a signing key the PDF's detached JWS is sent in X-Certificate-Signature; format=signature returns only that JWS and format=json the certificate as a signed JSON JWS, both checked against /.well-known/jwks.json.
+// @Tags LifeCertificate
+// @Security BasicAuth
+// @Produce application/pdf,application/jose,application/jose+json
+// @Param certificate_id path string true "Life certificate ID"
+// @Param format query string false "pdf (default), signature or json"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /life-certificate/{certificate_id}/document [get]
+func (h *CertificatePDFHandler) Download(w http.ResponseWriter, r *http.Request) {
+	certificateID := chi.URLParam(r, "certificate_id")
+	format := r.URL.Query().Get("format")
+	switch format {
+	case "", "pdf", "signature", "json":
+	default:
+		response.Error(w, http.StatusBadRequest, "format must be pdf, signature or json")
+		return
+	}
+
+	if format == "json" {
+		jws, err := h.service.SignedJSON(r.Context(), certificateID)
+		if err != nil {
+			writeCertificateDocumentError(w, err)
+			return
+		}
+		writeJOSE(w, "application/jose+json", jws)
+		return
+	}
+
+	doc, err := h.service.Open(r.Context(), certificateID)
+	if err != nil {
+		writeCertificateDocumentError(w, err)
+		return
+	}
+	if format == "signature" {
+		if doc.Signature == "" {
+			writeCertificateDocumentError(w, service.ErrCertificateSigningDisabled)
+			return
+		}
+		w.Header().Set("Content-Type", "application/jose")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "life-certificate-"+certificateID+".pdf.jws"))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(doc.Signature))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "life-certificate-"+certificateID+".pdf"))
+	if doc.Signature != "" {
+		w.Header().Set(CertificateSignatureHeader, doc.Signature)
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(doc.Content)
+}
+
+func writeCertificateDocumentError(w http.ResponseWriter, err error) {
+	switch err {
+	case service.ErrCertificateNotFound:
+		response.Error(w, http.StatusNotFound, err.Error())
+	case service.ErrCertificateDocumentNotIssuable:
+		response.Error(w, http.StatusConflict, err.Error())
+	case service.ErrCertificateSigningDisabled:
+		response.Error(w, http.StatusBadRequest, err.Error())
+	default:
+		response.Error(w, http.StatusInternalServerError, err.Error())
+	}
+}
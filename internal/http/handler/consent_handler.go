@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// ConsentHandler exposes consent to the biometric processing terms.
+type ConsentHandler struct {
+	service *service.ConsentService
+}
+
+// NewConsentHandler wires dependencies for consent endpoints.
+func NewConsentHandler(service *service.ConsentService) *ConsentHandler {
+	return &ConsentHandler{service: service}
+}
+
+// Record godoc
+// @Summary Record consent to the biometric processing terms
+// @Description Record a person's acceptance of the terms by NIK, before or after registration; terms_version defaults to CONSENT_TERMS_VERSION
+// @Tags Consents
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param payload body service.RecordConsentInput true "Consent payload"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /consents [post]
+func (h *ConsentHandler) Record(w http.ResponseWriter, r *http.Request) {
+	var req service.RecordConsentInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	consent, err := h.service.Record(r.Context(), middleware.Actor(r.Context()), req)
+	if err != nil {
+		var verr *service.ValidationError
+		if errors.As(err, &verr) {
+			response.ValidationError(w, "validation failed", verr.Fields)
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusCreated, consent)
+}
+
+// List godoc
+// @Summary List consents
+// @Description Consents given under a NIK or a participant's NIK, newest first, and whether the active terms version was accepted
+// @Tags Consents
+// @Security BasicAuth
+// @Produce json
+// @Param nik query string false "NIK"
+// @Param participant_id query string false "Participant ID"
+// @Param unmasked query bool false "Return personal identifiers in full (admin only, audit-logged)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /consents [get]
+func (h *ConsentHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	out, err := h.service.List(r.Context(), service.ConsentQueryInput{
+		NIK:           query.Get("nik"),
+		ParticipantID: query.Get("participant_id"),
+	})
+	if err != nil {
+		var verr *service.ValidationError
+		switch {
+		case errors.As(err, &verr):
+			response.ValidationError(w, "validation failed", verr.Fields)
+		case errors.Is(err, service.ErrParticipantNotFound):
+			response.Error(w, http.StatusNotFound, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	maskConsents(r, out)
+	response.Success(w, http.StatusOK, out)
+}
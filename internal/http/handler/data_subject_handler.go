@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// DataSubjectHandler exposes data export and erasure of members.
+type DataSubjectHandler struct {
+	service *service.DataSubjectService
+}
+
+// NewDataSubjectHandler wires dependencies for data subject endpoints.
+func NewDataSubjectHandler(service *service.DataSubjectService) *DataSubjectHandler {
+	return &DataSubjectHandler{service: service}
+}
+
+// Export godoc
+// @Summary Export a member's data
+// @Description Everything held about the member: member, participant, FR identities, certificates with document metadata, devices, notifications, audit entries and access logs. The zip format adds the stored selfies and documents (admin only)
+// @Tags Members
+// @Security BasicAuth
+// @Produce json
+// @Produce application/zip
+// @Param member_id path string true "Member ID"
+// @Param format query string false "json (default) or zip"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /members/{member_id}/data-export [get]
+func (h *DataSubjectHandler) Export(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	export, err := h.service.Export(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "member_id"), format)
+	if err != nil {
+		switch err {
+		case service.ErrMemberNotFound:
+			response.Error(w, http.StatusNotFound, err.Error())
+		default:
+			response.Error(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	if !strings.EqualFold(strings.TrimSpace(format), service.DataExportFormatZIP) {
+		response.Success(w, http.StatusOK, export)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "member-"+export.Member.ID+".zip"))
+	w.WriteHeader(http.StatusOK)
+	if err := h.service.WriteArchive(r.Context(), export, w); err != nil {
+		// The status is already sent; the truncated archive will not open.
+		log.Printf("write data export of member %s: %v", export.Member.ID, err)
+	}
+}
+
+// Erase godoc
+// @Summary Erase a member's personal data
+// @Description Anonymizes the member and their participant, removes faces from FR Core, deletes selfies, documents and devices, and keeps certificate outcomes for statistics. The participant is blocked (admin only)
+// @Tags Members
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param member_id path string true "Member ID"
+// @Param payload body service.EraseMemberInput true "Erasure reason"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /members/{member_id}/erase [post]
+func (h *DataSubjectHandler) Erase(w http.ResponseWriter, r *http.Request) {
+	var req service.EraseMemberInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	out, err := h.service.Erase(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "member_id"), req)
+	if err != nil {
+		switch err {
+		case service.ErrStatusReasonRequired:
+			response.Error(w, http.StatusBadRequest, err.Error())
+		case service.ErrMemberNotFound:
+			response.Error(w, http.StatusNotFound, err.Error())
+		case service.ErrMemberErased:
+			response.Error(w, http.StatusConflict, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, out)
+}
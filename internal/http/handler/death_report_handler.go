@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// DeathReportHandler exposes the confirmation of deaths reported by the civil registry.
+type DeathReportHandler struct {
+	service *service.DeathReportService
+}
+
+// NewDeathReportHandler wires dependencies for death report endpoints.
+func NewDeathReportHandler(service *service.DeathReportService) *DeathReportHandler {
+	return &DeathReportHandler{service: service}
+}
+
+// List godoc
+// @Summary List civil registry death reports
+// @Description Oldest first; use state=PENDING for the reports awaiting confirmation
+// @Tags DeathReport
+// @Security BasicAuth
+// @Produce json
+// @Param state query string false "PENDING, CONFIRMED or REJECTED"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/death-reports [get]
+func (h *DeathReportHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, pageSize, err := pageParams(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	out, err := h.service.List(r.Context(), r.URL.Query().Get("state"), page, pageSize)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, out)
+}
+
+// Confirm godoc
+// @Summary Confirm a death report
+// @Description The member stays DECEASED and their participant blocked
+// @Tags DeathReport
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param report_id path string true "Death report ID"
+// @Param payload body service.DecideDeathReportInput false "Confirmation notes"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/death-reports/{report_id}/confirm [post]
+func (h *DeathReportHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	var req service.DecideDeathReportInput
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+			return
+		}
+	}
+
+	report, err := h.service.Confirm(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "report_id"), req)
+	if err != nil {
+		writeDeathReportError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, report)
+}
+
+// Reject godoc
+// @Summary Reject a death report
+// @Description Reactivates the member and the participant the report blocked; notes are required
+// @Tags DeathReport
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param report_id path string true "Death report ID"
+// @Param payload body service.DecideDeathReportInput true "Rejection notes"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/death-reports/{report_id}/reject [post]
+func (h *DeathReportHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	var req service.DecideDeathReportInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	report, err := h.service.Reject(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "report_id"), req)
+	if err != nil {
+		writeDeathReportError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, report)
+}
+
+func writeDeathReportError(w http.ResponseWriter, err error) {
+	switch err {
+	case service.ErrDeathReportNotFound:
+		response.Error(w, http.StatusNotFound, err.Error())
+	case service.ErrDeathReportNotPending:
+		response.Error(w, http.StatusConflict, err.Error())
+	default:
+		response.Error(w, http.StatusBadRequest, err.Error())
+	}
+}
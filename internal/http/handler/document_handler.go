@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// DocumentHandler files supporting documents under participants, life
+// certificates and home visits.
+type DocumentHandler struct {
+	service *service.DocumentService
+}
+
+// NewDocumentHandler wires dependencies for document endpoints.
+func NewDocumentHandler(service *service.DocumentService) *DocumentHandler {
+	return &DocumentHandler{service: service}
+}
+
+// Upload godoc
+// @Summary Upload a supporting document
+// @Description Files a PDF, JPEG or PNG of at most 10 MB under a participant, life certificate or home visit after a malware scan. DOCTOR_LETTER documents can only be downloaded by their uploader and admins
+// @Tags Documents
+// @Security BasicAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param owner_type formData string true "participant, life_certificate or home_visit"
+// @Param owner_id formData string true "ID of the record the document belongs to"
+// @Param category formData string true "IDENTITY_CARD, FAMILY_CARD, DOCTOR_LETTER, HOME_VISIT_PHOTO, POWER_OF_ATTORNEY, DEATH_CERTIFICATE or OTHER"
+// @Param description formData string false "What the document shows"
+// @Param file formData file true "Document"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /documents [post]
+func (h *DocumentHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(50 << 20); err != nil {
+		response.Error(w, http.StatusBadRequest, "failed to parse multipart form")
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "file is required")
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "failed to read document")
+		return
+	}
+
+	document, err := h.service.Upload(r.Context(), middleware.Actor(r.Context()), service.UploadDocumentInput{
+		OwnerType:   r.FormValue("owner_type"),
+		OwnerID:     r.FormValue("owner_id"),
+		Category:    r.FormValue("category"),
+		Description: r.FormValue("description"),
+		FileName:    header.Filename,
+		Data:        data,
+	})
+	if err != nil {
+		writeDocumentError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, document)
+}
+
+// List godoc
+// @Summary List the documents of a record
+// @Tags Documents
+// @Security BasicAuth
+// @Produce json
+// @Param owner_type query string true "participant, life_certificate or home_visit"
+// @Param owner_id query string true "ID of the record"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /documents [get]
+func (h *DocumentHandler) List(w http.ResponseWriter, r *http.Request) {
+	documents, err := h.service.List(r.Context(), r.URL.Query().Get("owner_type"), r.URL.Query().Get("owner_id"))
+	if err != nil {
+		writeDocumentError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, documents)
+}
+
+// Get godoc
+// @Summary Get a document's metadata
+// @Tags Documents
+// @Security BasicAuth
+// @Produce json
+// @Param document_id path string true "Document ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /documents/{document_id} [get]
+func (h *DocumentHandler) Get(w http.ResponseWriter, r *http.Request) {
+	document, err := h.service.Get(r.Context(), chi.URLParam(r, "document_id"))
+	if err != nil {
+		writeDocumentError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, document)
+}
+
+// Download godoc
+// @Summary Download a document
+// @Description Every download is recorded in the access log; DOCTOR_LETTER documents are restricted to their uploader and admins
+// @Tags Documents
+// @Security BasicAuth
+// @Produce octet-stream
+// @Param document_id path string true "Document ID"
+// @Success 200 {file} file
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /documents/{document_id}/content [get]
+func (h *DocumentHandler) Download(w http.ResponseWriter, r *http.Request) {
+	document, content, err := h.service.Open(r.Context(), documentCaller(r), chi.URLParam(r, "document_id"))
+	if err != nil {
+		writeDocumentError(w, err)
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", document.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(document.SizeBytes, 10))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", document.FileName))
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, content)
+}
+
+// Delete godoc
+// @Summary Delete a document
+// @Description Only the uploader and admins may delete a document
+// @Tags Documents
+// @Security BasicAuth
+// @Param document_id path string true "Document ID"
+// @Success 204
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /documents/{document_id} [delete]
+func (h *DocumentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.Delete(r.Context(), documentCaller(r), chi.URLParam(r, "document_id")); err != nil {
+		writeDocumentError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func documentCaller(r *http.Request) service.DocumentCaller {
+	return service.DocumentCaller{
+		Username: middleware.Actor(r.Context()),
+		Admin:    middleware.Role(r.Context()) == middleware.RoleAdmin,
+	}
+}
+
+func writeDocumentError(w http.ResponseWriter, err error) {
+	var verr *service.ValidationError
+	if errors.As(err, &verr) {
+		response.ValidationError(w, "validation failed", verr.Fields)
+		return
+	}
+	var infected *service.InfectedUploadError
+	if errors.As(err, &infected) {
+		response.ErrorWithCode(w, http.StatusUnprocessableEntity, "INFECTED_UPLOAD", err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrUploadScanFailed) {
+		response.Error(w, http.StatusServiceUnavailable, service.ErrUploadScanFailed.Error())
+		return
+	}
+	switch err {
+	case service.ErrDocumentNotFound, service.ErrParticipantNotFound, service.ErrCertificateNotFound, service.ErrHomeVisitNotFound:
+		response.Error(w, http.StatusNotFound, err.Error())
+	case service.ErrDocumentRestricted:
+		response.Error(w, http.StatusForbidden, err.Error())
+	default:
+		response.Error(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,319 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/export"
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/pii"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/service"
+)
+
+// exportFlushRows is how many rows are written between flushes to the client.
+const exportFlushRows = 500
+
+var memberExportHeader = []string{
+	"id", "nik", "nomor_peserta", "birth_date", "fullname", "address", "city", "province",
+	"phone_number", "email", "status", "erased_at", "created_at", "updated_at",
+}
+
+// certificateExportColumn is a column of the certificate export.
+type certificateExportColumn struct {
+	name  string
+	value func(row *repository.CertificateExportRow) string
+}
+
+// certificateExportColumns are the certificate export's columns in their default order.
+var certificateExportColumns = []certificateExportColumn{
+	{"id", func(row *repository.CertificateExportRow) string { return row.ID }},
+	{"participant_id", func(row *repository.CertificateExportRow) string { return row.ParticipantID }},
+	{"nik", func(row *repository.CertificateExportRow) string { return row.ParticipantNIK }},
+	{"name", func(row *repository.CertificateExportRow) string { return row.ParticipantName }},
+	{"status", func(row *repository.CertificateExportRow) string { return string(row.Status) }},
+	{"method", func(row *repository.CertificateExportRow) string { return string(row.Method) }},
+	{"channel", func(row *repository.CertificateExportRow) string { return string(row.Channel) }},
+	{"campaign_id", func(row *repository.CertificateExportRow) string { return exportString(row.CampaignID) }},
+	{"session_id", func(row *repository.CertificateExportRow) string { return exportString(row.SessionID) }},
+	{"similarity", func(row *repository.CertificateExportRow) string { return exportFloat(row.Similarity) }},
+	{"distance", func(row *repository.CertificateExportRow) string { return exportFloat(row.Distance) }},
+	{"verified_at", func(row *repository.CertificateExportRow) string { return exportTime(&row.VerifiedAt) }},
+	{"location", func(row *repository.CertificateExportRow) string { return exportString(row.Location) }},
+	{"officer_id", func(row *repository.CertificateExportRow) string { return exportString(row.OfficerID) }},
+	{"officer_name", func(row *repository.CertificateExportRow) string { return exportString(row.OfficerName) }},
+	{"recorded_by", func(row *repository.CertificateExportRow) string { return exportString(row.RecordedBy) }},
+	{"kiosk_id", func(row *repository.CertificateExportRow) string { return exportString(row.KioskID) }},
+	{"branch_id", func(row *repository.CertificateExportRow) string { return exportString(row.BranchID) }},
+	{"outside_geofence", func(row *repository.CertificateExportRow) string { return strconv.FormatBool(row.OutsideGeofence) }},
+	{"geofence_finding", func(row *repository.CertificateExportRow) string { return exportString(row.GeofenceFinding) }},
+	{"risk_score", func(row *repository.CertificateExportRow) string { return exportInt(row.RiskScore) }},
+	{"reviewed_by", func(row *repository.CertificateExportRow) string { return exportString(row.ReviewedBy) }},
+	{"reviewed_at", func(row *repository.CertificateExportRow) string { return exportTime(row.ReviewedAt) }},
+}
+
+// ExportHandler streams members and verification attempts as CSV or XLSX.
+type ExportHandler struct {
+	service *service.ExportService
+}
+
+// NewExportHandler wires dependencies for export endpoints.
+func NewExportHandler(service *service.ExportService) *ExportHandler {
+	return &ExportHandler{service: service}
+}
+
+// Members godoc
+// @Summary Export members
+// @Description Streams every member, newest first, as CSV or XLSX. NIKs, phone numbers and emails are masked unless unmasked
+// @Tags Members
+// @Security BasicAuth
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "csv (default) or xlsx"
+// @Param unmasked query bool false "Export personal identifiers in full (admin only, audit-logged)"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /members/export [get]
+func (h *ExportHandler) Members(w http.ResponseWriter, r *http.Request) {
+	stream, ok := newExportStream(w, r, "members", memberExportHeader)
+	if !ok {
+		return
+	}
+
+	unmasked := middleware.Unmasked(r.Context())
+	err := h.service.Members(r.Context(), func(member *domain.Member) error {
+		if !unmasked {
+			member.NIK = pii.NIK(member.NIK)
+			member.PhoneNumber = pii.Phone(member.PhoneNumber)
+			member.Email = pii.Email(member.Email)
+		}
+		return stream.write([]string{
+			member.ID, member.NIK, member.NomorPeserta, member.BirthDate.Format("2006-01-02"), member.FullName,
+			member.Address, member.City, member.Province, member.PhoneNumber, member.Email, string(member.Status),
+			exportTime(member.ErasedAt), exportTime(&member.CreatedAt), exportTime(&member.UpdatedAt),
+		})
+	})
+	stream.finish(err)
+}
+
+// Certificates godoc
+// @Summary Export verification attempts
+// @Description Streams the verification attempts matching the filters, oldest first, as CSV or XLSX. NIKs are masked unless unmasked
+// @Tags Life Certificate
+// @Security BasicAuth
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "csv (default) or xlsx"
+// @Param status query string false "VALID, INVALID or REVIEW"
+// @Param method query string false "AUTOMATIC, MANUAL or PROXY"
+// @Param channel query string false "MOBILE, KIOSK, FIELD, PROXY or MANUAL"
+// @Param location query string false "Kiosk or office"
+// @Param campaign_id query string false "Attempts of the campaign's participants since it started"
+// @Param from query string false "Verified on or after date (YYYY-MM-DD)"
+// @Param to query string false "Verified on or before date (YYYY-MM-DD)"
+// @Param columns query string false "Comma-separated columns to export, in order; default all"
+// @Param unmasked query bool false "Export NIKs in full (admin only, audit-logged)"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /life-certificate/export [get]
+func (h *ExportHandler) Certificates(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	columns, err := certificateColumns(query.Get("columns"))
+	if err != nil {
+		response.ValidationError(w, "validation failed", map[string]string{"columns": err.Error()})
+		return
+	}
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.name
+	}
+	stream, ok := newExportStream(w, r, "life-certificates", header)
+	if !ok {
+		return
+	}
+
+	unmasked := middleware.Unmasked(r.Context())
+	err = h.service.Certificates(r.Context(), service.CertificateExportInput{
+		Status:     query.Get("status"),
+		Method:     query.Get("method"),
+		Channel:    query.Get("channel"),
+		Location:   query.Get("location"),
+		CampaignID: query.Get("campaign_id"),
+		From:       query.Get("from"),
+		To:         query.Get("to"),
+	}, func(row *repository.CertificateExportRow) error {
+		if !unmasked {
+			row.ParticipantNIK = pii.NIK(row.ParticipantNIK)
+		}
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			cells[i] = column.value(row)
+		}
+		return stream.write(cells)
+	})
+	stream.finish(err)
+}
+
+// certificateColumns resolves a comma-separated list of column names, all
+// columns when it is empty. A column named twice is exported once.
+func certificateColumns(raw string) ([]certificateExportColumn, error) {
+	if strings.TrimSpace(raw) == "" {
+		return certificateExportColumns, nil
+	}
+	var columns []certificateExportColumn
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		found := false
+		for _, column := range certificateExportColumns {
+			if column.name == name {
+				columns, found = append(columns, column), true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		seen[name] = true
+	}
+	if len(columns) == 0 {
+		return certificateExportColumns, nil
+	}
+	return columns, nil
+}
+
+// exportStream sends an export as its rows are read. The response starts
+// with the first row, so an error raised before then, such as an invalid
+// filter, still gets a JSON error response.
+type exportStream struct {
+	w        http.ResponseWriter
+	format   string
+	filename string
+	header   []string
+	started  bool
+	out      export.Writer
+	rows     int
+}
+
+// newExportStream reads the requested format, answering 400 when it is not
+// supported.
+func newExportStream(w http.ResponseWriter, r *http.Request, name string, header []string) (*exportStream, bool) {
+	format, err := export.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	return &exportStream{
+		w:        w,
+		format:   format,
+		filename: fmt.Sprintf("%s-%s.%s", name, time.Now().UTC().Format("20060102"), format),
+		header:   header,
+	}, true
+}
+
+func (s *exportStream) start() error {
+	s.w.Header().Set("Content-Type", export.ContentType(s.format))
+	s.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", s.filename))
+	// A large export outlasts the server's write timeout.
+	_ = http.NewResponseController(s.w).SetWriteDeadline(time.Time{})
+	s.w.WriteHeader(http.StatusOK)
+	s.started = true
+
+	out, err := export.New(s.format, s.w)
+	if err != nil {
+		return err
+	}
+	s.out = out
+	return s.out.Write(s.header)
+}
+
+func (s *exportStream) write(row []string) error {
+	if s.out == nil {
+		if err := s.start(); err != nil {
+			return err
+		}
+	}
+	if err := s.out.Write(row); err != nil {
+		return err
+	}
+	s.rows++
+	if s.rows%exportFlushRows == 0 {
+		if err := s.out.Flush(); err != nil {
+			return err
+		}
+		_ = http.NewResponseController(s.w).Flush()
+	}
+	return nil
+}
+
+// finish completes the export once its rows are read. After the response has
+// started an error can no longer be reported, so the connection is aborted
+// and the client sees a broken download rather than a short file that looks
+// complete.
+func (s *exportStream) finish(err error) {
+	if err == nil && !s.started {
+		// Nothing matched; send the header row alone.
+		err = s.start()
+	}
+	if err == nil {
+		err = s.out.Close()
+	}
+	if err == nil {
+		return
+	}
+
+	var verr *service.ValidationError
+	switch {
+	case !s.started && errors.As(err, &verr):
+		response.ValidationError(s.w, "validation failed", verr.Fields)
+	case !s.started:
+		response.Error(s.w, http.StatusInternalServerError, err.Error())
+	default:
+		log.Printf("stream %s after %d rows: %v", s.filename, s.rows, err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+func exportTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func exportFloat(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'f', -1, 64)
+}
+
+func exportInt(n *int) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.Itoa(*n)
+}
+
+func exportString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
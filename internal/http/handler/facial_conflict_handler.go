@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// FacialConflictHandler exposes the queue of attempts matched to another participant's face.
+type FacialConflictHandler struct {
+	service *service.FacialConflictService
+}
+
+// NewFacialConflictHandler wires dependencies for facial conflict endpoints.
+func NewFacialConflictHandler(service *service.FacialConflictService) *FacialConflictHandler {
+	return &FacialConflictHandler{service: service}
+}
+
+// List godoc
+// @Summary List facial conflicts
+// @Description Attempts whose selfie matched another participant's enrolled face, oldest first; use state=PENDING for the conflicts awaiting a decision
+// @Tags Review
+// @Security BasicAuth
+// @Produce json
+// @Param state query string false "PENDING, CONFIRMED or DISMISSED"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /review/conflicts [get]
+func (h *FacialConflictHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, pageSize, err := pageParams(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	out, err := h.service.List(r.Context(), r.URL.Query().Get("state"), page, pageSize)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, out)
+}
+
+// Confirm godoc
+// @Summary Confirm a facial conflict
+// @Description Upholds the conflict as impersonation and blocks the participant the attempt was submitted for
+// @Tags Review
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param conflict_id path string true "Facial conflict ID"
+// @Param payload body service.DecideFacialConflictInput false "Confirmation notes"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /review/conflicts/{conflict_id}/confirm [post]
+func (h *FacialConflictHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	var req service.DecideFacialConflictInput
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+			return
+		}
+	}
+
+	conflict, err := h.service.Confirm(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "conflict_id"), req)
+	if err != nil {
+		writeFacialConflictError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, conflict)
+}
+
+// Dismiss godoc
+// @Summary Dismiss a facial conflict
+// @Description Closes the conflict as a false match, e.g. twins; notes are required
+// @Tags Review
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param conflict_id path string true "Facial conflict ID"
+// @Param payload body service.DecideFacialConflictInput true "Dismissal notes"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /review/conflicts/{conflict_id}/dismiss [post]
+func (h *FacialConflictHandler) Dismiss(w http.ResponseWriter, r *http.Request) {
+	var req service.DecideFacialConflictInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	conflict, err := h.service.Dismiss(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "conflict_id"), req)
+	if err != nil {
+		writeFacialConflictError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, conflict)
+}
+
+func writeFacialConflictError(w http.ResponseWriter, err error) {
+	switch err {
+	case service.ErrFacialConflictNotFound:
+		response.Error(w, http.StatusNotFound, err.Error())
+	case service.ErrFacialConflictNotPending:
+		response.Error(w, http.StatusConflict, err.Error())
+	default:
+		response.Error(w, http.StatusBadRequest, err.Error())
+	}
+}
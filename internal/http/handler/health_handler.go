@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// HealthHandler exposes Kubernetes style liveness, readiness and startup probes.
+type HealthHandler struct {
+	service *service.HealthService
+}
+
+// NewHealthHandler wires dependencies for probe endpoints.
+func NewHealthHandler(service *service.HealthService) *HealthHandler {
+	return &HealthHandler{service: service}
+}
+
+// Live godoc
+// @Summary Liveness probe
+// @Description Reports the process is up without checking dependencies
+// @Tags Health
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /live [get]
+func (h *HealthHandler) Live(w http.ResponseWriter, _ *http.Request) {
+	response.Success(w, http.StatusOK, h.service.Live())
+}
+
+// Ready godoc
+// @Summary Readiness probe
+// @Description Checks the database connection, applied migrations and FR Core reachability, with per-dependency status and latency
+// @Tags Health
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /ready [get]
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	writeHealthReport(w, h.service.Ready(r.Context()))
+}
+
+// Startup godoc
+// @Summary Startup probe
+// @Description Reports up once migrations and startup backfills finished and the database checks pass
+// @Tags Health
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /startup [get]
+func (h *HealthHandler) Startup(w http.ResponseWriter, r *http.Request) {
+	writeHealthReport(w, h.service.Startup(r.Context()))
+}
+
+func writeHealthReport(w http.ResponseWriter, report *service.HealthReport) {
+	if !report.Up() {
+		response.ErrorWithData(w, http.StatusServiceUnavailable, "service is "+report.Status, report)
+		return
+	}
+	response.Success(w, http.StatusOK, report)
+}
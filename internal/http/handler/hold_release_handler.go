@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// HoldReleaseHandler exposes hold release callbacks to the payment system.
+type HoldReleaseHandler struct {
+	service *service.HoldReleaseService
+}
+
+// NewHoldReleaseHandler wires dependencies for hold release endpoints.
+func NewHoldReleaseHandler(service *service.HoldReleaseService) *HoldReleaseHandler {
+	return &HoldReleaseHandler{service: service}
+}
+
+// List godoc
+// @Summary List hold release callbacks
+// @Description Callbacks asking the payment system to release pension payment holds for VALID certificates, oldest first, with their acknowledgment
+// @Tags HoldRelease
+// @Security BasicAuth
+// @Produce json
+// @Param status query string false "PENDING, ACKNOWLEDGED or FAILED"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/hold-releases [get]
+func (h *HoldReleaseHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, pageSize, err := pageParams(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	out, err := h.service.List(r.Context(), r.URL.Query().Get("status"), page, pageSize)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, out)
+}
+
+// Retry godoc
+// @Summary Retry a failed hold release
+// @Description Moves a FAILED hold release back to PENDING with a fresh attempt budget
+// @Tags HoldRelease
+// @Security BasicAuth
+// @Produce json
+// @Param release_id path string true "Hold release ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/hold-releases/{release_id}/retry [post]
+func (h *HoldReleaseHandler) Retry(w http.ResponseWriter, r *http.Request) {
+	release, err := h.service.Retry(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "release_id"))
+	if err != nil {
+		switch err {
+		case service.ErrHoldReleaseNotFound:
+			response.Error(w, http.StatusNotFound, err.Error())
+		case service.ErrHoldReleaseNotFailed:
+			response.Error(w, http.StatusConflict, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, release)
+}
+
+// Resend godoc
+// @Summary Re-send a failed hold release
+// @Description Sends a FAILED hold release once, right away, and returns the outcome; an unacknowledged re-send stays FAILED
+// @Tags HoldRelease
+// @Security BasicAuth
+// @Produce json
+// @Param release_id path string true "Hold release ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/hold-releases/{release_id}/resend [post]
+func (h *HoldReleaseHandler) Resend(w http.ResponseWriter, r *http.Request) {
+	release, err := h.service.Resend(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "release_id"))
+	if err != nil {
+		switch err {
+		case service.ErrHoldReleaseNotFound:
+			response.Error(w, http.StatusNotFound, err.Error())
+		case service.ErrHoldReleaseNotFailed:
+			response.Error(w, http.StatusConflict, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, release)
+}
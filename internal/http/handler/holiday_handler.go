@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// HolidayHandler manages the national and regional holidays of the working calendar.
+type HolidayHandler struct {
+	service *service.CalendarService
+}
+
+// NewHolidayHandler wires dependencies for holiday endpoints.
+func NewHolidayHandler(service *service.CalendarService) *HolidayHandler {
+	return &HolidayHandler{service: service}
+}
+
+// Create godoc
+// @Summary Add holidays
+// @Description Adds a list of YYYY-MM-DD holidays for a region (a member province), or national holidays without a region. Due dates, review SLAs and reminders computed afterwards skip them; a date the region already has fails the whole list (admin only)
+// @Tags Admin
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param payload body service.HolidayInput true "Holidays"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/holidays [post]
+func (h *HolidayHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req service.HolidayInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	holidays, err := h.service.Create(r.Context(), middleware.Actor(r.Context()), req)
+	if err != nil {
+		writeHolidayError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, holidays)
+}
+
+// List godoc
+// @Summary List holidays
+// @Tags Admin
+// @Security BasicAuth
+// @Produce json
+// @Param region query string false "A region's holidays together with the national ones"
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date (YYYY-MM-DD)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/holidays [get]
+func (h *HolidayHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	holidays, err := h.service.List(r.Context(), service.HolidayListInput{
+		Region: query.Get("region"),
+		From:   query.Get("from"),
+		To:     query.Get("to"),
+	})
+	if err != nil {
+		writeHolidayError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, holidays)
+}
+
+// Delete godoc
+// @Summary Delete a holiday
+// @Description Due dates already computed with the holiday keep their value (admin only)
+// @Tags Admin
+// @Security BasicAuth
+// @Param holiday_id path string true "Holiday ID"
+// @Success 204
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/holidays/{holiday_id} [delete]
+func (h *HolidayHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.Delete(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "holiday_id")); err != nil {
+		writeHolidayError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeHolidayError(w http.ResponseWriter, err error) {
+	var verr *service.ValidationError
+	if errors.As(err, &verr) {
+		response.ValidationError(w, "validation failed", verr.Fields)
+		return
+	}
+	switch err {
+	case service.ErrHolidayNotFound:
+		response.Error(w, http.StatusNotFound, err.Error())
+	case service.ErrHolidayConflict:
+		response.Error(w, http.StatusConflict, err.Error())
+	default:
+		response.Error(w, http.StatusInternalServerError, err.Error())
+	}
+}
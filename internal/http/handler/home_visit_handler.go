@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// HomeVisitHandler exposes home visit scheduling.
+type HomeVisitHandler struct {
+	service *service.HomeVisitService
+}
+
+// NewHomeVisitHandler wires dependencies for home visit endpoints.
+func NewHomeVisitHandler(service *service.HomeVisitService) *HomeVisitHandler {
+	return &HomeVisitHandler{service: service}
+}
+
+// Request godoc
+// @Summary Request a home visit
+// @Description For a participant who cannot verify remotely, within the requested slot (RFC 3339 times); the address defaults to the linked member's
+// @Tags HomeVisit
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param payload body service.RequestHomeVisitInput true "Visit request"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /home-visits [post]
+func (h *HomeVisitHandler) Request(w http.ResponseWriter, r *http.Request) {
+	var req service.RequestHomeVisitInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	visit, err := h.service.Request(r.Context(), middleware.Actor(r.Context()), req)
+	if err != nil {
+		writeHomeVisitError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, visit)
+}
+
+// List godoc
+// @Summary List home visits
+// @Description By scheduled time, visits not scheduled yet last
+// @Tags HomeVisit
+// @Security BasicAuth
+// @Produce json
+// @Param participant_id query string false "Participant ID"
+// @Param status query string false "REQUESTED, SCHEDULED, COMPLETED, MISSED or CANCELLED"
+// @Param officer_id query string false "Assigned officer"
+// @Param branch_id query string false "Branch"
+// @Param from query string false "Scheduled on or after date (YYYY-MM-DD)"
+// @Param to query string false "Scheduled on or before date (YYYY-MM-DD)"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /home-visits [get]
+func (h *HomeVisitHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, pageSize, err := pageParams(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	out, err := h.service.List(r.Context(), service.HomeVisitListInput{
+		ParticipantID: query.Get("participant_id"),
+		Status:        query.Get("status"),
+		OfficerID:     query.Get("officer_id"),
+		BranchID:      query.Get("branch_id"),
+		From:          query.Get("from"),
+		To:            query.Get("to"),
+		Page:          page,
+		PageSize:      pageSize,
+	})
+	if err != nil {
+		writeHomeVisitError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, out)
+}
+
+// Get godoc
+// @Summary Get a home visit
+// @Tags HomeVisit
+// @Security BasicAuth
+// @Produce json
+// @Param visit_id path string true "Home visit ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /home-visits/{visit_id} [get]
+func (h *HomeVisitHandler) Get(w http.ResponseWriter, r *http.Request) {
+	visit, err := h.service.Get(r.Context(), chi.URLParam(r, "visit_id"))
+	if err != nil {
+		writeHomeVisitError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, visit)
+}
+
+// Schedule godoc
+// @Summary Schedule a home visit
+// @Description Assigns a requested visit to an active officer at scheduled_at (RFC 3339), or reschedules a scheduled one; the branch defaults to the officer's
+// @Tags HomeVisit
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param visit_id path string true "Home visit ID"
+// @Param payload body service.ScheduleHomeVisitInput true "Schedule"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /home-visits/{visit_id}/schedule [post]
+func (h *HomeVisitHandler) Schedule(w http.ResponseWriter, r *http.Request) {
+	var req service.ScheduleHomeVisitInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	visit, err := h.service.Schedule(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "visit_id"), req)
+	if err != nil {
+		writeHomeVisitError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, visit)
+}
+
+// Missed godoc
+// @Summary Record a missed home visit
+// @Description The officer could not carry out the scheduled visit; request another one if needed
+// @Tags HomeVisit
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param visit_id path string true "Home visit ID"
+// @Param payload body service.CloseHomeVisitInput true "Why the visit was missed"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /home-visits/{visit_id}/missed [post]
+func (h *HomeVisitHandler) Missed(w http.ResponseWriter, r *http.Request) {
+	h.close(w, r, h.service.Missed)
+}
+
+// Cancel godoc
+// @Summary Cancel a home visit
+// @Tags HomeVisit
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param visit_id path string true "Home visit ID"
+// @Param payload body service.CloseHomeVisitInput true "Why the visit was cancelled"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /home-visits/{visit_id}/cancel [post]
+func (h *HomeVisitHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	h.close(w, r, h.service.Cancel)
+}
+
+func (h *HomeVisitHandler) close(w http.ResponseWriter, r *http.Request, close func(ctx context.Context, actor, id string, input service.CloseHomeVisitInput) (*domain.HomeVisit, error)) {
+	var req service.CloseHomeVisitInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	visit, err := close(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "visit_id"), req)
+	if err != nil {
+		writeHomeVisitError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, visit)
+}
+
+func writeHomeVisitError(w http.ResponseWriter, err error) {
+	var verr *service.ValidationError
+	if errors.As(err, &verr) {
+		response.ValidationError(w, "validation failed", verr.Fields)
+		return
+	}
+	if writeOfficerEligibilityError(w, err) {
+		return
+	}
+	switch err {
+	case service.ErrHomeVisitNotFound, service.ErrParticipantNotFound:
+		response.Error(w, http.StatusNotFound, err.Error())
+	case service.ErrHomeVisitOpen, service.ErrHomeVisitNotScheduled, service.ErrHomeVisitClosed:
+		response.Error(w, http.StatusConflict, err.Error())
+	case service.ErrParticipantSuspended:
+		response.ErrorWithCode(w, http.StatusForbidden, "PARTICIPANT_SUSPENDED", err.Error())
+	case service.ErrParticipantBlocked:
+		response.ErrorWithCode(w, http.StatusForbidden, "PARTICIPANT_BLOCKED", err.Error())
+	default:
+		response.Error(w, http.StatusInternalServerError, err.Error())
+	}
+}
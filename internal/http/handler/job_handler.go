@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// JobHandler exposes background job administration.
+type JobHandler struct {
+	service *service.JobService
+}
+
+// NewJobHandler wires dependencies for job endpoints.
+func NewJobHandler(service *service.JobService) *JobHandler {
+	return &JobHandler{service: service}
+}
+
+// List godoc
+// @Summary List background jobs
+// @Description Latest first (admin only)
+// @Tags Jobs
+// @Security BasicAuth
+// @Produce json
+// @Param type query string false "Job type, e.g. frcore.reconcile"
+// @Param status query string false "QUEUED, RUNNING, SUCCEEDED, FAILED or CANCELLED"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/jobs [get]
+func (h *JobHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, pageSize, err := pageParams(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	out, err := h.service.List(r.Context(), service.JobListInput{
+		Type:     r.URL.Query().Get("type"),
+		Status:   r.URL.Query().Get("status"),
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		writeJobError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, out)
+}
+
+// Get godoc
+// @Summary Get a background job
+// @Description Includes the payload, attempts, last error and result (admin only)
+// @Tags Jobs
+// @Security BasicAuth
+// @Produce json
+// @Param job_id path string true "Job ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/jobs/{job_id} [get]
+func (h *JobHandler) Get(w http.ResponseWriter, r *http.Request) {
+	job, err := h.service.Get(r.Context(), chi.URLParam(r, "job_id"))
+	if err != nil {
+		writeJobError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, job)
+}
+
+// Retry godoc
+// @Summary Retry a background job
+// @Description Moves a FAILED or CANCELLED job back to QUEUED with a fresh attempt budget (admin only)
+// @Tags Jobs
+// @Security BasicAuth
+// @Produce json
+// @Param job_id path string true "Job ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/jobs/{job_id}/retry [post]
+func (h *JobHandler) Retry(w http.ResponseWriter, r *http.Request) {
+	job, err := h.service.Retry(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "job_id"))
+	if err != nil {
+		writeJobError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, job)
+}
+
+// Cancel godoc
+// @Summary Cancel a background job
+// @Description Stops a QUEUED or RUNNING job (admin only)
+// @Tags Jobs
+// @Security BasicAuth
+// @Produce json
+// @Param job_id path string true "Job ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/jobs/{job_id}/cancel [post]
+func (h *JobHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	job, err := h.service.Cancel(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "job_id"))
+	if err != nil {
+		writeJobError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, job)
+}
+
+func writeJobError(w http.ResponseWriter, err error) {
+	var verr *service.ValidationError
+	if errors.As(err, &verr) {
+		response.ValidationError(w, "validation failed", verr.Fields)
+		return
+	}
+	switch err {
+	case service.ErrJobNotFound:
+		response.Error(w, http.StatusNotFound, err.Error())
+	case service.ErrJobNotRetryable, service.ErrJobNotCancellable:
+		response.Error(w, http.StatusConflict, err.Error())
+	default:
+		response.Error(w, http.StatusInternalServerError, err.Error())
+	}
+}
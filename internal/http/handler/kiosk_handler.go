@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// KioskHandler exposes the operator-assisted kiosk flow and kiosk management.
+type KioskHandler struct {
+	service  *service.KioskService
+	sessions *service.VerificationSessionService
+	access   middleware.AccessRecorder
+}
+
+// NewKioskHandler wires dependencies for kiosk endpoints. Every participant
+// an operator looks up is recorded with access.
+func NewKioskHandler(service *service.KioskService, sessions *service.VerificationSessionService, access middleware.AccessRecorder) *KioskHandler {
+	return &KioskHandler{service: service, sessions: sessions, access: access}
+}
+
+// KioskLookupRequest looks up a participant; the NIK travels in the body to keep it out of URLs and logs.
+type KioskLookupRequest struct {
+	NIK string `json:"nik"`
+}
+
+// Lookup godoc
+// @Summary Look up the participant at a kiosk
+// @Description Requires the kiosk's device key on top of the operator's credentials
+// @Tags Kiosk
+// @Security BasicAuth
+// @Security KioskKey
+// @Accept json
+// @Produce json
+// @Param payload body KioskLookupRequest true "NIK"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /kiosk/lookup [post]
+func (h *KioskHandler) Lookup(w http.ResponseWriter, r *http.Request) {
+	var req KioskLookupRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	participant, err := h.service.Lookup(r.Context(), req.NIK)
+	if err != nil {
+		var verr *service.ValidationError
+		if errors.As(err, &verr) {
+			response.ValidationError(w, "validation failed", verr.Fields)
+			return
+		}
+		switch err {
+		case service.ErrParticipantNotFound:
+			response.Error(w, http.StatusNotFound, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	recordAccess(h.access, r, domain.AccessResourceParticipant, participant.ParticipantID)
+	response.Success(w, http.StatusOK, participant)
+}
+
+// Verify godoc
+// @Summary Submit a selfie captured at a kiosk
+// @Description Like POST /life-certificate/verify; the certificate records the kiosk, its branch, the operator and the operator's officer record, and the location defaults to the kiosk's name. A submission made outside the branch's geofence goes to review with reason outside_geofence. The operator must sign in with the username of an active officer
+// @Tags Kiosk
+// @Security BasicAuth
+// @Security KioskKey
+// @Accept multipart/form-data
+// @Produce json
+// @Param participant_id formData string false "Participant ID from the lookup; optional with a session token"
+// @Param session_token formData string false "Token of a verification session, also accepted in the X-Verification-Session header; required when VERIFICATION_SESSION_REQUIRED is on"
+// @Param image formData file true "Selfie image"
+// @Param location formData string false "Where the selfie was captured (default the kiosk's name)"
+// @Param latitude formData number false "Kiosk position, checked against its branch's geofence; submissions without one go to review"
+// @Param longitude formData number false "Kiosk position"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 415 {object} map[string]interface{}
+// @Failure 423 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /kiosk/verify [post]
+func (h *KioskHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	input, ok := readVerifyForm(w, r)
+	if !ok {
+		return
+	}
+	input.AllowStepUp = true
+	input, err := h.sessions.Attach(r.Context(), sessionToken(r), input)
+	if err != nil {
+		writeVerifyError(w, err)
+		return
+	}
+
+	kiosk := middleware.KioskFromContext(r.Context())
+	out, err := h.service.Verify(r.Context(), &domain.Kiosk{ID: kiosk.ID, Name: kiosk.Name, BranchID: kiosk.BranchID}, middleware.Actor(r.Context()), input)
+	if err != nil {
+		if writeOfficerEligibilityError(w, err) {
+			return
+		}
+		writeVerifyError(w, err)
+		return
+	}
+	if writeStepUp(w, r, h.sessions, input, out) {
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"certificate_id":      out.CertificateID,
+		"participant_id":      out.ParticipantID,
+		"verification_status": string(out.Status),
+		"similarity":          out.Similarity,
+		"distance":            out.Distance,
+		"verified_at":         out.VerifiedAt,
+		"kiosk_id":            kiosk.ID,
+		"branch_id":           kiosk.BranchID,
+	})
+}
+
+// CreateKiosk godoc
+// @Summary Register a branch kiosk
+// @Description branch_id must be a registered branch; the device key is returned only in this response
+// @Tags Kiosk
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param payload body service.CreateKioskInput true "Kiosk"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/kiosks [post]
+func (h *KioskHandler) CreateKiosk(w http.ResponseWriter, r *http.Request) {
+	var req service.CreateKioskInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	kiosk, err := h.service.Create(r.Context(), middleware.Actor(r.Context()), req)
+	if err != nil {
+		var verr *service.ValidationError
+		if errors.As(err, &verr) {
+			response.ValidationError(w, "validation failed", verr.Fields)
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusCreated, kiosk)
+}
+
+// ListKiosks godoc
+// @Summary List branch kiosks
+// @Description Kiosks are identified by their key prefix; revoked kiosks are included
+// @Tags Kiosk
+// @Security BasicAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/kiosks [get]
+func (h *KioskHandler) ListKiosks(w http.ResponseWriter, r *http.Request) {
+	kiosks, err := h.service.List(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, kiosks)
+}
+
+// RevokeKiosk godoc
+// @Summary Revoke a branch kiosk
+// @Tags Kiosk
+// @Security BasicAuth
+// @Produce json
+// @Param kiosk_id path string true "Kiosk ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/kiosks/{kiosk_id} [delete]
+func (h *KioskHandler) RevokeKiosk(w http.ResponseWriter, r *http.Request) {
+	kiosk, err := h.service.Revoke(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "kiosk_id"))
+	if err != nil {
+		switch err {
+		case service.ErrKioskNotFound:
+			response.Error(w, http.StatusNotFound, err.Error())
+		case service.ErrKioskRevoked:
+			response.Error(w, http.StatusConflict, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, kiosk)
+}
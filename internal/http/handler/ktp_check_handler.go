@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// KTPCheckHandler lists the OCR comparisons of registration KTP photos and
+// lets operators confirm the ones with warnings.
+type KTPCheckHandler struct {
+	service *service.KTPCheckService
+}
+
+// NewKTPCheckHandler wires dependencies for KTP check endpoints.
+func NewKTPCheckHandler(service *service.KTPCheckService) *KTPCheckHandler {
+	return &KTPCheckHandler{service: service}
+}
+
+// List godoc
+// @Summary List KTP checks
+// @Description Lists the comparisons of registration KTP photos with the submitted NIK, name and birth date, newest first. Use status=PENDING,UNREADABLE for the checks awaiting confirmation
+// @Tags Participants
+// @Security BasicAuth
+// @Produce json
+// @Param participant_id query string false "Participant ID"
+// @Param status query string false "Comma separated MATCHED, PENDING, UNREADABLE or CONFIRMED"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Page size"
+// @Param unmasked query bool false "Return NIKs in full (admin only, audit-logged)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /participants/ktp-checks [get]
+func (h *KTPCheckHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, pageSize, err := pageParams(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	out, err := h.service.List(r.Context(), service.KTPCheckQueryInput{
+		ParticipantID: query.Get("participant_id"),
+		Status:        query.Get("status"),
+		Page:          page,
+		PageSize:      pageSize,
+	})
+	if err != nil {
+		writeKTPCheckError(w, err)
+		return
+	}
+
+	maskKTPChecks(r, out.Items)
+	response.Success(w, http.StatusOK, out)
+}
+
+// Get godoc
+// @Summary Get a KTP check
+// @Tags Participants
+// @Security BasicAuth
+// @Produce json
+// @Param check_id path string true "KTP check ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /participants/ktp-checks/{check_id} [get]
+func (h *KTPCheckHandler) Get(w http.ResponseWriter, r *http.Request) {
+	check, err := h.service.Get(r.Context(), chi.URLParam(r, "check_id"))
+	if err != nil {
+		writeKTPCheckError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, check)
+}
+
+// Confirm godoc
+// @Summary Confirm a KTP check
+// @Description Records that an operator compared the KTP with the registration and accepts it despite the warnings. Only PENDING and UNREADABLE checks can be confirmed
+// @Tags Participants
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param check_id path string true "KTP check ID"
+// @Param payload body service.ConfirmKTPCheckInput true "Confirmation"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /participants/ktp-checks/{check_id}/confirm [post]
+func (h *KTPCheckHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	var req service.ConfirmKTPCheckInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	check, err := h.service.Confirm(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "check_id"), req)
+	if err != nil {
+		writeKTPCheckError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, check)
+}
+
+func writeKTPCheckError(w http.ResponseWriter, err error) {
+	var verr *service.ValidationError
+	if errors.As(err, &verr) {
+		response.ValidationError(w, "validation failed", verr.Fields)
+		return
+	}
+	switch err {
+	case service.ErrKTPCheckNotFound:
+		response.Error(w, http.StatusNotFound, err.Error())
+	case service.ErrKTPCheckNotOpen:
+		response.Error(w, http.StatusConflict, err.Error())
+	default:
+		response.Error(w, http.StatusBadRequest, err.Error())
+	}
+}
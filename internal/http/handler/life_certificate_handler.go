@@ -1,70 +1,142 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
 	"io"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 
+	"life-certificates/internal/http/middleware"
 	"life-certificates/internal/http/response"
 	"life-certificates/internal/service"
 )
 
+// verificationSessionHeader carries the session token when it is not sent as a form field.
+const verificationSessionHeader = "X-Verification-Session"
+
+// deviceIDHeader carries a stable identifier of the capturing device, such as the mobile SDK's installation ID.
+const deviceIDHeader = "X-Device-ID"
+
+// deviceModelHeader and deviceOSHeader fingerprint the capturing device, e.g. "Pixel 7" and "Android 14".
+const (
+	deviceModelHeader = "X-Device-Model"
+	deviceOSHeader    = "X-Device-OS"
+)
+
 // LifeCertificateHandler exposes endpoints for verification and status queries.
 type LifeCertificateHandler struct {
-	service *service.VerificationService
+	service  *service.VerificationService
+	sessions *service.VerificationSessionService
 }
 
 // NewLifeCertificateHandler wires dependencies for life certificate endpoints.
-func NewLifeCertificateHandler(service *service.VerificationService) *LifeCertificateHandler {
-	return &LifeCertificateHandler{service: service}
+func NewLifeCertificateHandler(service *service.VerificationService, sessions *service.VerificationSessionService) *LifeCertificateHandler {
+	return &LifeCertificateHandler{service: service, sessions: sessions}
+}
+
+// CreateSession godoc
+// @Summary Open a verification session for the mobile SDK
+// @Description Returns a single-use session token with the liveness challenge the participant must perform and the upload policy of the verify call. The token is returned only in this response.
+// @Tags LifeCertificate
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param payload body service.CreateVerificationSessionInput true "Participant"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /life-certificate/sessions [post]
+func (h *LifeCertificateHandler) CreateSession(w http.ResponseWriter, r *http.Request) {
+	var req service.CreateVerificationSessionInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	session, err := h.sessions.Create(r.Context(), middleware.Actor(r.Context()), req)
+	if err != nil {
+		var verr *service.ValidationError
+		if errors.As(err, &verr) {
+			response.ValidationError(w, "validation failed", verr.Fields)
+			return
+		}
+		switch err {
+		case service.ErrParticipantNotFound:
+			response.Error(w, http.StatusNotFound, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(w, http.StatusCreated, session)
 }
 
 // Verify godoc
 // @Summary Submit life certificate verification
+// @Description With STEP_UP_ENABLED, an attempt in a gray zone records nothing and answers verification_status STEP_UP_REQUIRED with a step_up session; the participant performs its challenge in another selfie submitted with the session token. With dry_run=true the attempt runs liveness and recognition and answers its would-be outcome and scores with dry_run true, recording nothing
 // @Tags LifeCertificate
 // @Security BasicAuth
 // @Accept multipart/form-data
 // @Produce json
-// @Param participant_id formData string true "Participant ID"
+// @Param dry_run query bool false "Practice attempt: answer the would-be outcome without recording a certificate"
+// @Param participant_id formData string false "Participant ID; optional with a session token"
+// @Param session_token formData string false "Token of a verification session, also accepted in the X-Verification-Session header; required when VERIFICATION_SESSION_REQUIRED is on"
 // @Param image formData file true "Selfie image"
+// @Param location formData string false "Kiosk or office where the selfie was captured"
+// @Param latitude formData number false "Declared latitude, compared with the selfie's GPS position"
+// @Param longitude formData number false "Declared longitude, compared with the selfie's GPS position"
+// @Param X-Device-ID header string false "Stable identifier of the capturing device, used for fraud scoring"
+// @Param X-Device-Model header string false "Model of the capturing device, recorded in its fingerprint"
+// @Param X-Device-OS header string false "Operating system of the capturing device, recorded in its fingerprint"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 415 {object} map[string]interface{}
+// @Failure 423 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
 // @Router /life-certificate/verify [post]
 func (h *LifeCertificateHandler) Verify(w http.ResponseWriter, r *http.Request) {
-	if err := r.ParseMultipartForm(20 << 20); err != nil {
-		response.Error(w, http.StatusBadRequest, "failed to parse multipart form")
+	var dryRun bool
+	if raw := r.URL.Query().Get("dry_run"); raw != "" {
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "dry_run must be true or false")
+			return
+		}
+		dryRun = value
+	}
+	input, ok := readVerifyForm(w, r)
+	if !ok {
 		return
 	}
-
-	participantID := r.FormValue("participant_id")
-	file, header, err := r.FormFile("image")
+	input.AllowStepUp = true
+	input.DryRun = dryRun
+	input, err := h.sessions.Attach(r.Context(), sessionToken(r), input)
 	if err != nil {
-		response.Error(w, http.StatusBadRequest, "image file is required")
+		writeVerifyError(w, err)
 		return
 	}
-	defer file.Close()
 
-	imageBytes, err := io.ReadAll(file)
+	out, err := h.service.Verify(r.Context(), input)
 	if err != nil {
-		response.Error(w, http.StatusBadRequest, "failed to read image")
+		writeVerifyError(w, err)
 		return
 	}
-
-	out, err := h.service.Verify(r.Context(), service.VerifyInput{
-		ParticipantID:    participantID,
-		ImageBytes:       imageBytes,
-		OriginalFilename: header.Filename,
-	})
-	if err != nil {
-		switch err {
-		case service.ErrParticipantNotFound:
-			response.Error(w, http.StatusNotFound, err.Error())
-		default:
-			response.Error(w, http.StatusBadRequest, err.Error())
-		}
+	if dryRun {
+		writeDryRun(w, out)
+		return
+	}
+	if writeStepUp(w, r, h.sessions, input, out) {
 		return
 	}
 
@@ -112,6 +184,7 @@ func (h *LifeCertificateHandler) LatestStatus(w http.ResponseWriter, r *http.Req
 		"last_status":    lastStatus,
 		"similarity":     out.Similarity,
 		"distance":       out.Distance,
+		"lock":           out.Lock,
 	}
 	if out.VerifiedAt != nil {
 		data["verified_at"] = out.VerifiedAt
@@ -119,3 +192,204 @@ func (h *LifeCertificateHandler) LatestStatus(w http.ResponseWriter, r *http.Req
 
 	response.Success(w, http.StatusOK, data)
 }
+
+// Selfie godoc
+// @Summary Download the selfie of an automatic attempt
+// @Description Available when STORAGE_SELFIES is on, until the retention purge removes it
+// @Tags LifeCertificate
+// @Security BasicAuth
+// @Produce jpeg
+// @Param certificate_id path string true "Life certificate ID"
+// @Success 200 {file} file
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /life-certificate/{certificate_id}/selfie [get]
+func (h *LifeCertificateHandler) Selfie(w http.ResponseWriter, r *http.Request) {
+	content, err := h.service.OpenSelfie(r.Context(), chi.URLParam(r, "certificate_id"))
+	if err != nil {
+		switch err {
+		case service.ErrCertificateNotFound, service.ErrSelfieNotFound:
+			response.Error(w, http.StatusNotFound, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, content)
+}
+
+// readVerifyForm reads a verification submission, answering 400 when it is malformed.
+func readVerifyForm(w http.ResponseWriter, r *http.Request) (service.VerifyInput, bool) {
+	if err := r.ParseMultipartForm(20 << 20); err != nil {
+		response.Error(w, http.StatusBadRequest, "failed to parse multipart form")
+		return service.VerifyInput{}, false
+	}
+
+	file, header, err := r.FormFile("image")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "image file is required")
+		return service.VerifyInput{}, false
+	}
+	defer file.Close()
+
+	imageBytes, err := io.ReadAll(file)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "failed to read image")
+		return service.VerifyInput{}, false
+	}
+
+	latitude, longitude, ok := readPosition(w, r)
+	if !ok {
+		return service.VerifyInput{}, false
+	}
+
+	return service.VerifyInput{
+		ParticipantID:    r.FormValue("participant_id"),
+		ImageBytes:       imageBytes,
+		OriginalFilename: header.Filename,
+		Location:         r.FormValue("location"),
+		Latitude:         latitude,
+		Longitude:        longitude,
+		ClientIP:         clientIP(r),
+		DeviceID:         strings.TrimSpace(r.Header.Get(deviceIDHeader)),
+		DeviceModel:      strings.TrimSpace(r.Header.Get(deviceModelHeader)),
+		DeviceOS:         strings.TrimSpace(r.Header.Get(deviceOSHeader)),
+	}, true
+}
+
+// writeStepUp opens the session of the step-up challenge an attempt needs
+// and answers with it, reporting false when the attempt was decided instead.
+func writeStepUp(w http.ResponseWriter, r *http.Request, sessions *service.VerificationSessionService, input service.VerifyInput, out *service.VerifyOutput) bool {
+	if out.StepUp == nil {
+		return false
+	}
+	input.ParticipantID = out.ParticipantID
+	session, err := sessions.StepUp(r.Context(), middleware.Actor(r.Context()), input, out.StepUp)
+	if err != nil {
+		writeVerifyError(w, err)
+		return true
+	}
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"participant_id":      out.ParticipantID,
+		"verification_status": service.VerificationStatusStepUpRequired,
+		"step_up": map[string]interface{}{
+			"reasons":   out.StepUp.Reasons,
+			"challenge": out.StepUp.Challenge,
+			"session":   session,
+		},
+	})
+	return true
+}
+
+// writeDryRun answers a practice attempt's would-be outcome. A step-up is
+// reported without opening its session.
+func writeDryRun(w http.ResponseWriter, out *service.VerifyOutput) {
+	data := map[string]interface{}{
+		"participant_id": out.ParticipantID,
+		"dry_run":        true,
+	}
+	if out.StepUp != nil {
+		data["verification_status"] = service.VerificationStatusStepUpRequired
+		data["step_up"] = map[string]interface{}{
+			"reasons":   out.StepUp.Reasons,
+			"challenge": out.StepUp.Challenge,
+		}
+		data["similarity"] = out.StepUp.Similarity
+		data["distance"] = out.StepUp.Distance
+		data["risk_score"] = out.StepUp.RiskScore
+	} else {
+		data["verification_status"] = string(out.Status)
+		data["similarity"] = out.Similarity
+		data["distance"] = out.Distance
+		data["risk_score"] = out.RiskScore
+		data["reason"] = out.Reason
+	}
+	response.Success(w, http.StatusOK, data)
+}
+
+// clientIP is the address a request came from, as resolved by the RealIP middleware.
+func clientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// writeVerifyError maps a refused verification to its status and code.
+func writeVerifyError(w http.ResponseWriter, err error) {
+	if writeImageFormatError(w, err) || writeFRCoreBusyError(w, err) {
+		return
+	}
+	var verr *service.ValidationError
+	if errors.As(err, &verr) {
+		response.ValidationError(w, "validation failed", verr.Fields)
+		return
+	}
+	var infected *service.InfectedUploadError
+	if errors.As(err, &infected) {
+		response.ErrorWithCode(w, http.StatusUnprocessableEntity, "INFECTED_UPLOAD", err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrUploadScanFailed) {
+		response.Error(w, http.StatusServiceUnavailable, service.ErrUploadScanFailed.Error())
+		return
+	}
+	switch err {
+	case service.ErrParticipantNotFound:
+		response.Error(w, http.StatusNotFound, err.Error())
+	case service.ErrParticipantSuspended:
+		response.ErrorWithCode(w, http.StatusForbidden, "PARTICIPANT_SUSPENDED", err.Error())
+	case service.ErrParticipantBlocked:
+		response.ErrorWithCode(w, http.StatusForbidden, "PARTICIPANT_BLOCKED", err.Error())
+	case service.ErrConsentRequired:
+		response.ErrorWithCode(w, http.StatusForbidden, "CONSENT_REQUIRED", err.Error())
+	case service.ErrAttemptLimitReached:
+		response.ErrorWithCode(w, http.StatusTooManyRequests, "ATTEMPT_LIMIT_REACHED", err.Error())
+	case service.ErrVerificationLocked:
+		response.ErrorWithCode(w, http.StatusLocked, "VERIFICATION_LOCKED", err.Error())
+	case service.ErrVerificationSessionRequired:
+		response.ErrorWithCode(w, http.StatusBadRequest, "SESSION_REQUIRED", err.Error())
+	case service.ErrInvalidVerificationSession:
+		response.ErrorWithCode(w, http.StatusForbidden, "SESSION_INVALID", err.Error())
+	case service.ErrVerificationSessionMismatch:
+		response.ErrorWithCode(w, http.StatusForbidden, "SESSION_MISMATCH", err.Error())
+	default:
+		response.Error(w, http.StatusBadRequest, err.Error())
+	}
+}
+
+// sessionToken reads the verification session token from the form or the header.
+func sessionToken(r *http.Request) string {
+	if token := strings.TrimSpace(r.FormValue("session_token")); token != "" {
+		return token
+	}
+	return strings.TrimSpace(r.Header.Get(verificationSessionHeader))
+}
+
+// readPosition parses the optional latitude and longitude form fields.
+func readPosition(w http.ResponseWriter, r *http.Request) (*float64, *float64, bool) {
+	latitude, errLatitude := formFloat(r, "latitude")
+	longitude, errLongitude := formFloat(r, "longitude")
+	if errLatitude != nil || errLongitude != nil {
+		response.Error(w, http.StatusBadRequest, "latitude and longitude must be decimal degrees")
+		return nil, nil, false
+	}
+	return latitude, longitude, true
+}
+
+// formFloat parses an optional decimal form field, nil when it is absent.
+func formFloat(r *http.Request, name string) (*float64, error) {
+	raw := strings.TrimSpace(r.FormValue(name))
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
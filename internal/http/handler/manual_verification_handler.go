@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// ManualVerificationHandler exposes officer-recorded verifications and their documents.
+type ManualVerificationHandler struct {
+	service *service.ManualVerificationService
+}
+
+// NewManualVerificationHandler wires dependencies for manual verification endpoints.
+func NewManualVerificationHandler(service *service.ManualVerificationService) *ManualVerificationHandler {
+	return &ManualVerificationHandler{service: service}
+}
+
+// Verify godoc
+// @Summary Record a manual life certificate verification
+// @Description Creates a VALID certificate with method MANUAL backed by supporting documents (PDF, JPEG or PNG, 10 MB each). A verification made outside the branch's geofence is recorded with outside_geofence set and raises an alert
+// @Tags LifeCertificate
+// @Security BasicAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param participant_id formData string true "Participant ID"
+// @Param officer_id formData string true "Registered officer who verified the participant"
+// @Param notes formData string true "Justification for the manual verification"
+// @Param location formData string false "Office where the verification took place"
+// @Param latitude formData number false "Where the verification took place, checked against the branch's geofence"
+// @Param longitude formData number false "Where the verification took place"
+// @Param visit_id formData string false "Scheduled home visit the verification was made on"
+// @Param documents formData file true "Supporting document (repeatable)"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /life-certificate/manual [post]
+func (h *ManualVerificationHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(50 << 20); err != nil {
+		response.Error(w, http.StatusBadRequest, "failed to parse multipart form")
+		return
+	}
+
+	documents, err := readDocuments(r, "documents", "")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	latitude, longitude, ok := readPosition(w, r)
+	if !ok {
+		return
+	}
+
+	out, err := h.service.Verify(r.Context(), middleware.Actor(r.Context()), service.ManualVerifyInput{
+		ParticipantID: r.FormValue("participant_id"),
+		OfficerID:     r.FormValue("officer_id"),
+		Notes:         r.FormValue("notes"),
+		Location:      r.FormValue("location"),
+		Latitude:      latitude,
+		Longitude:     longitude,
+		VisitID:       r.FormValue("visit_id"),
+		Documents:     documents,
+	})
+	if err != nil {
+		writeManualVerificationError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, out)
+}
+
+// Proxy godoc
+// @Summary Submit a proxy life certificate verification
+// @Description For a bedridden participant: a family member or field officer submits the evidence, creating a REVIEW certificate with method PROXY that only senior reviewers can decide. Documents are PDF, JPEG or PNG, 10 MB each; home-visit photos are JPEG or PNG. A submission made outside the branch's geofence is recorded with outside_geofence set and raises an alert
+// @Tags LifeCertificate
+// @Security BasicAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param participant_id formData string true "Participant ID"
+// @Param proxy_kind formData string true "FAMILY or OFFICER"
+// @Param proxy_name formData string false "Name of the family member (required for FAMILY)"
+// @Param relationship formData string false "Family member's relationship to the participant (required for FAMILY)"
+// @Param officer_id formData string true "Registered field officer who took the submission"
+// @Param notes formData string true "Why the participant cannot verify in person"
+// @Param location formData string false "Where the participant was visited"
+// @Param latitude formData number false "Where the participant was visited, checked against the branch's geofence"
+// @Param longitude formData number false "Where the participant was visited"
+// @Param visit_id formData string false "Scheduled home visit the verification was made on"
+// @Param doctor_letter formData file true "Doctor letter (repeatable)"
+// @Param home_visit_photo formData file true "Home-visit photo (repeatable)"
+// @Param documents formData file false "Other supporting document (repeatable)"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /life-certificate/proxy [post]
+func (h *ManualVerificationHandler) Proxy(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(50 << 20); err != nil {
+		response.Error(w, http.StatusBadRequest, "failed to parse multipart form")
+		return
+	}
+
+	var documents []service.DocumentUpload
+	for _, field := range []struct {
+		name string
+		kind domain.DocumentKind
+	}{
+		{"doctor_letter", domain.DocumentKindDoctorLetter},
+		{"home_visit_photo", domain.DocumentKindHomeVisitPhoto},
+		{"documents", ""},
+	} {
+		uploads, err := readDocuments(r, field.name, field.kind)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		documents = append(documents, uploads...)
+	}
+
+	latitude, longitude, ok := readPosition(w, r)
+	if !ok {
+		return
+	}
+
+	out, err := h.service.Proxy(r.Context(), middleware.Actor(r.Context()), service.ProxyVerifyInput{
+		ParticipantID: r.FormValue("participant_id"),
+		Kind:          r.FormValue("proxy_kind"),
+		ProxyName:     r.FormValue("proxy_name"),
+		Relationship:  r.FormValue("relationship"),
+		OfficerID:     r.FormValue("officer_id"),
+		Notes:         r.FormValue("notes"),
+		Location:      r.FormValue("location"),
+		Latitude:      latitude,
+		Longitude:     longitude,
+		VisitID:       r.FormValue("visit_id"),
+		Documents:     documents,
+	})
+	if err != nil {
+		writeManualVerificationError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, out)
+}
+
+// Documents godoc
+// @Summary List supporting documents of a certificate
+// @Tags LifeCertificate
+// @Security BasicAuth
+// @Produce json
+// @Param certificate_id path string true "Life certificate ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /life-certificate/{certificate_id}/documents [get]
+func (h *ManualVerificationHandler) Documents(w http.ResponseWriter, r *http.Request) {
+	documents, err := h.service.Documents(r.Context(), chi.URLParam(r, "certificate_id"))
+	if err != nil {
+		switch err {
+		case service.ErrCertificateNotFound:
+			response.Error(w, http.StatusNotFound, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, documents)
+}
+
+// Download godoc
+// @Summary Download a supporting document
+// @Tags LifeCertificate
+// @Security BasicAuth
+// @Produce octet-stream
+// @Param certificate_id path string true "Life certificate ID"
+// @Param document_id path string true "Document ID"
+// @Success 200 {file} file
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /life-certificate/{certificate_id}/documents/{document_id} [get]
+func (h *ManualVerificationHandler) Download(w http.ResponseWriter, r *http.Request) {
+	document, content, err := h.service.OpenDocument(r.Context(), chi.URLParam(r, "certificate_id"), chi.URLParam(r, "document_id"))
+	if err != nil {
+		switch err {
+		case service.ErrDocumentNotFound:
+			response.Error(w, http.StatusNotFound, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", document.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(document.SizeBytes, 10))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", document.FileName))
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.Copy(w, content)
+}
+
+// readDocuments reads the files uploaded under a multipart form field.
+func readDocuments(r *http.Request, field string, kind domain.DocumentKind) ([]service.DocumentUpload, error) {
+	var documents []service.DocumentUpload
+	for _, header := range r.MultipartForm.File[field] {
+		file, err := header.Open()
+		if err != nil {
+			return nil, errors.New("failed to read document")
+		}
+		data, err := io.ReadAll(file)
+		file.Close()
+		if err != nil {
+			return nil, errors.New("failed to read document")
+		}
+		documents = append(documents, service.DocumentUpload{FileName: header.Filename, Data: data, Kind: kind})
+	}
+	return documents, nil
+}
+
+func writeManualVerificationError(w http.ResponseWriter, err error) {
+	var verr *service.ValidationError
+	if errors.As(err, &verr) {
+		response.ValidationError(w, "validation failed", verr.Fields)
+		return
+	}
+	var infected *service.InfectedUploadError
+	if errors.As(err, &infected) {
+		response.ErrorWithCode(w, http.StatusUnprocessableEntity, "INFECTED_UPLOAD", err.Error())
+		return
+	}
+	if errors.Is(err, service.ErrUploadScanFailed) {
+		response.Error(w, http.StatusServiceUnavailable, service.ErrUploadScanFailed.Error())
+		return
+	}
+	if writeOfficerEligibilityError(w, err) {
+		return
+	}
+	switch err {
+	case service.ErrParticipantNotFound, service.ErrHomeVisitNotFound:
+		response.Error(w, http.StatusNotFound, err.Error())
+	case service.ErrHomeVisitNotScheduled:
+		response.Error(w, http.StatusConflict, err.Error())
+	case service.ErrParticipantSuspended:
+		response.ErrorWithCode(w, http.StatusForbidden, "PARTICIPANT_SUSPENDED", err.Error())
+	case service.ErrParticipantBlocked:
+		response.ErrorWithCode(w, http.StatusForbidden, "PARTICIPANT_BLOCKED", err.Error())
+	default:
+		response.Error(w, http.StatusInternalServerError, err.Error())
+	}
+}
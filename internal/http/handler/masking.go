@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/notification"
+	"life-certificates/internal/pii"
+	"life-certificates/internal/service"
+)
+
+// List responses mask NIKs, phone numbers and email addresses unless an admin
+// asked for them in full with ?unmasked=true (see middleware.Unmask). Detail
+// endpoints return them in full and are access-logged instead.
+
+func maskParticipants(r *http.Request, participants []domain.Participant) {
+	if middleware.Unmasked(r.Context()) {
+		return
+	}
+	for i := range participants {
+		participants[i].NIK = pii.NIK(participants[i].NIK)
+		participants[i].PhoneNumber = pii.Phone(participants[i].PhoneNumber)
+		participants[i].Email = pii.Email(participants[i].Email)
+	}
+}
+
+func maskMembers(r *http.Request, members []domain.Member) {
+	if middleware.Unmasked(r.Context()) {
+		return
+	}
+	for i := range members {
+		members[i].NIK = pii.NIK(members[i].NIK)
+		members[i].PhoneNumber = pii.Phone(members[i].PhoneNumber)
+		members[i].Email = pii.Email(members[i].Email)
+	}
+}
+
+func maskDuplicateGroups(r *http.Request, groups []service.DuplicateGroup) {
+	if middleware.Unmasked(r.Context()) {
+		return
+	}
+	for i := range groups {
+		maskMembers(r, groups[i].Members)
+		if groups[i].Reason == service.DuplicateReasonNIKFragment {
+			groups[i].Key = pii.NIK(groups[i].Key)
+		}
+	}
+}
+
+func maskDeliveries(r *http.Request, deliveries []domain.NotificationDelivery) {
+	if middleware.Unmasked(r.Context()) {
+		return
+	}
+	for i := range deliveries {
+		// Push recipients are device IDs, not contact details.
+		if deliveries[i].Channel != notification.ChannelPush {
+			deliveries[i].Recipient = pii.Contact(deliveries[i].Recipient)
+		}
+	}
+}
+
+func maskConsents(r *http.Request, out *service.ConsentListOutput) {
+	if middleware.Unmasked(r.Context()) {
+		return
+	}
+	out.NIK = pii.NIK(out.NIK)
+	for i := range out.Items {
+		out.Items[i].NIK = pii.NIK(out.Items[i].NIK)
+	}
+}
+
+func maskBulkRows(r *http.Request, rows []domain.BulkRegistrationRow) {
+	if middleware.Unmasked(r.Context()) {
+		return
+	}
+	for i := range rows {
+		rows[i].NIK = pii.NIK(rows[i].NIK)
+	}
+}
+
+func maskKTPChecks(r *http.Request, checks []domain.KTPCheck) {
+	if middleware.Unmasked(r.Context()) {
+		return
+	}
+	for i := range checks {
+		if checks[i].NIK != nil {
+			masked := pii.NIK(*checks[i].NIK)
+			checks[i].NIK = &masked
+		}
+		checks[i].Warnings = maskKTPWarnings(checks[i].Warnings)
+	}
+}
+
+// maskKTPWarnings masks both sides of a nik warning. Warnings that do not
+// decode are dropped rather than returned with the NIKs in them.
+func maskKTPWarnings(raw string) string {
+	if raw == "" {
+		return raw
+	}
+	var warnings []service.KTPWarning
+	if err := json.Unmarshal([]byte(raw), &warnings); err != nil {
+		return ""
+	}
+	for i := range warnings {
+		if warnings[i].Field == "nik" {
+			warnings[i].Submitted = pii.NIK(warnings[i].Submitted)
+			warnings[i].Extracted = pii.NIK(warnings[i].Extracted)
+		}
+	}
+	encoded, err := json.Marshal(warnings)
+	if err != nil {
+		return ""
+	}
+	return string(encoded)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 
+	"life-certificates/internal/domain"
 	"life-certificates/internal/http/response"
 	"life-certificates/internal/service"
 )
@@ -59,18 +60,29 @@ func (h *MemberHandler) Create(w http.ResponseWriter, r *http.Request) {
 // @Tags Members
 // @Security BasicAuth
 // @Produce json
+// @Param unmasked query bool false "Return personal identifiers in full (admin only, audit-logged)"
+// @Param fields query string false "Comma-separated fields to return, e.g. id,nik,name; default all"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /members [get]
 func (h *MemberHandler) List(w http.ResponseWriter, r *http.Request) {
+	fields, err := response.ParseFields(r, domain.Member{})
+	if err != nil {
+		response.ValidationError(w, "validation failed", map[string]string{"fields": err.Error()})
+		return
+	}
+
 	members, err := h.service.List(r.Context())
 	if err != nil {
 		response.Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	response.Success(w, http.StatusOK, map[string]interface{}{"members": members})
+	maskMembers(r, members)
+	response.Success(w, http.StatusOK, map[string]interface{}{"members": fields.Apply(members)})
 }
 
 // Get godoc
@@ -79,12 +91,20 @@ func (h *MemberHandler) List(w http.ResponseWriter, r *http.Request) {
 // @Security BasicAuth
 // @Produce json
 // @Param member_id path string true "Member ID"
+// @Param fields query string false "Comma-separated fields to return, e.g. id,nik,name; default all"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /members/{member_id} [get]
 func (h *MemberHandler) Get(w http.ResponseWriter, r *http.Request) {
+	fields, err := response.ParseFields(r, domain.Member{})
+	if err != nil {
+		response.ValidationError(w, "validation failed", map[string]string{"fields": err.Error()})
+		return
+	}
+
 	id := chi.URLParam(r, "member_id")
 	member, err := h.service.Get(r.Context(), id)
 	if err != nil {
@@ -97,7 +117,7 @@ func (h *MemberHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response.Success(w, http.StatusOK, member)
+	response.Success(w, http.StatusOK, fields.Apply(member))
 }
 
 // Update godoc
@@ -129,7 +149,7 @@ func (h *MemberHandler) Update(w http.ResponseWriter, r *http.Request) {
 		switch err {
 		case service.ErrMemberNotFound:
 			response.Error(w, http.StatusNotFound, err.Error())
-		case service.ErrMemberNIKExists, service.ErrMemberNomorPesertaExists:
+		case service.ErrMemberNIKExists, service.ErrMemberNomorPesertaExists, service.ErrMemberErased:
 			response.Error(w, http.StatusConflict, err.Error())
 		default:
 			response.Error(w, http.StatusBadRequest, err.Error())
@@ -164,3 +184,90 @@ func (h *MemberHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// Duplicates godoc
+// @Summary List probable duplicate members
+// @Description Groups members sharing a NIK fragment or the same name and birth date
+// @Tags Members
+// @Security BasicAuth
+// @Produce json
+// @Param unmasked query bool false "Return personal identifiers in full (admin only, audit-logged)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /members/duplicates [get]
+func (h *MemberHandler) Duplicates(w http.ResponseWriter, r *http.Request) {
+	groups, err := h.service.FindDuplicates(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	maskDuplicateGroups(r, groups)
+	response.Success(w, http.StatusOK, map[string]interface{}{"groups": groups})
+}
+
+// Merge godoc
+// @Summary Merge duplicate members
+// @Description Merge the source member into the target, re-pointing linked participants
+// @Tags Members
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param payload body service.MergeMembersInput true "Merge payload"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /members/merge [post]
+func (h *MemberHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	var req service.MergeMembersInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	out, err := h.service.Merge(r.Context(), req)
+	if err != nil {
+		switch err {
+		case service.ErrMemberNotFound:
+			response.Error(w, http.StatusNotFound, err.Error())
+		case service.ErrMemberErased:
+			response.Error(w, http.StatusConflict, err.Error())
+		default:
+			response.Error(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, out)
+}
+
+// Merges godoc
+// @Summary List member merge history
+// @Tags Members
+// @Security BasicAuth
+// @Produce json
+// @Param fields query string false "Comma-separated fields to return; default all"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /members/merges [get]
+func (h *MemberHandler) Merges(w http.ResponseWriter, r *http.Request) {
+	fields, err := response.ParseFields(r, domain.MemberMerge{})
+	if err != nil {
+		response.ValidationError(w, "validation failed", map[string]string{"fields": err.Error()})
+		return
+	}
+
+	merges, err := h.service.ListMerges(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{"merges": fields.Apply(merges)})
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// ReportSignatureHeader carries a monthly report's detached JWS when signing is enabled.
+const ReportSignatureHeader = "X-Report-Signature"
+
+// MonthlyReportHandler serves the monthly regulator reports.
+type MonthlyReportHandler struct {
+	service *service.MonthlyReportService
+}
+
+// NewMonthlyReportHandler wires dependencies for monthly report downloads.
+func NewMonthlyReportHandler(service *service.MonthlyReportService) *MonthlyReportHandler {
+	return &MonthlyReportHandler{service: service}
+}
+
+// Download godoc
+// @Summary Download a monthly regulator report
+// @Description The month's verifications, exceptions, status overrides and deceased detections as PDF or XLSX (MONTHLY_REPORT_FORMAT). It is compiled by a scheduled task after the month ends, and on first download when missing. With a signing key the report's detached JWS is sent in X-Report-Signature; format=signature returns only that JWS, checked against /.well-known/jwks.json.
+// @Tags Stats
+// @Security BasicAuth
+// @Produce application/pdf,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/jose
+// @Param period path string true "Month (YYYY-MM)"
+// @Param format query string false "report (default) or signature"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /reports/monthly/{period} [get]
+func (h *MonthlyReportHandler) Download(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "report" && format != "signature" {
+		response.Error(w, http.StatusBadRequest, "format must be report or signature")
+		return
+	}
+
+	doc, err := h.service.Open(r.Context(), chi.URLParam(r, "period"))
+	if err != nil {
+		switch err {
+		case service.ErrInvalidReportPeriod:
+			response.Error(w, http.StatusBadRequest, err.Error())
+		case service.ErrMonthlyReportNotReady:
+			response.Error(w, http.StatusNotFound, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	filename := fmt.Sprintf("monthly-report-%s.%s", doc.Period, doc.Format)
+	if format == "signature" {
+		if doc.Signature == "" {
+			response.Error(w, http.StatusBadRequest, service.ErrCertificateSigningDisabled.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/jose")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".jws"))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(doc.Signature))
+		return
+	}
+
+	w.Header().Set("Content-Type", doc.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if doc.Signature != "" {
+		w.Header().Set(ReportSignatureHeader, doc.Signature)
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(doc.Content)
+}
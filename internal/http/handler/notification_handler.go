@@ -0,0 +1,352 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// NotificationHandler exposes the notification delivery log, template
+// administration, device registration and member notification preferences.
+type NotificationHandler struct {
+	service *service.NotificationService
+}
+
+// NewNotificationHandler wires dependencies for notification endpoints.
+func NewNotificationHandler(service *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{service: service}
+}
+
+// List godoc
+// @Summary List notification deliveries
+// @Description Every notification logged for sending, newest first, with its channel, recipient, template, status, provider message ID and last error
+// @Tags Notifications
+// @Security BasicAuth
+// @Produce json
+// @Param participant_id query string false "Participant ID"
+// @Param channel query string false "email, sms, whatsapp or push"
+// @Param recipient query string false "Email address, E.164 phone number or device ID"
+// @Param template query string false "verification_success, verification_failure, verification_review or reminder"
+// @Param status query string false "PENDING, SENT, FAILED or SKIPPED"
+// @Param batch_id query string false "Bulk send, e.g. a campaign notification kickoff ID"
+// @Param from query string false "Created on or after date (YYYY-MM-DD)"
+// @Param to query string false "Created on or before date (YYYY-MM-DD)"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Param unmasked query bool false "Return personal identifiers in full (admin only, audit-logged)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /notifications [get]
+func (h *NotificationHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, pageSize, err := pageParams(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	out, err := h.service.Deliveries(r.Context(), service.NotificationListInput{
+		ParticipantID: query.Get("participant_id"),
+		Channel:       query.Get("channel"),
+		Recipient:     query.Get("recipient"),
+		Template:      query.Get("template"),
+		Status:        query.Get("status"),
+		BatchID:       query.Get("batch_id"),
+		From:          query.Get("from"),
+		To:            query.Get("to"),
+		Page:          page,
+		PageSize:      pageSize,
+	})
+	if err != nil {
+		writeNotificationError(w, err)
+		return
+	}
+
+	maskDeliveries(r, out.Items)
+	response.Success(w, http.StatusOK, out)
+}
+
+// Retry godoc
+// @Summary Retry a failed notification
+// @Description Queues another send of a FAILED delivery; preferences are checked again before sending. The retry is audit-logged.
+// @Tags Notifications
+// @Security BasicAuth
+// @Produce json
+// @Param notification_id path string true "Delivery ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /notifications/{notification_id}/retry [post]
+func (h *NotificationHandler) Retry(w http.ResponseWriter, r *http.Request) {
+	delivery, err := h.service.RetryDelivery(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "notification_id"))
+	if err != nil {
+		writeNotificationError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, delivery)
+}
+
+// ListTemplates godoc
+// @Summary List notification templates
+// @Description Subject and body of every template in force, with its source: database, file or builtin (admin only)
+// @Tags Notifications
+// @Security BasicAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/notification-templates [get]
+func (h *NotificationHandler) ListTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.service.Templates(r.Context())
+	if err != nil {
+		writeNotificationError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{"templates": templates})
+}
+
+// SaveTemplate godoc
+// @Summary Override a notification template
+// @Description Stores a Go text/template subject and body in the database; they take precedence over template files and the built-in text (admin only)
+// @Tags Notifications
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param name path string true "verification_success, verification_failure, verification_review or reminder"
+// @Param payload body service.NotificationTemplateInput true "Template"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/notification-templates/{name} [put]
+func (h *NotificationHandler) SaveTemplate(w http.ResponseWriter, r *http.Request) {
+	var req service.NotificationTemplateInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	tmpl, err := h.service.SaveTemplate(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "name"), req)
+	if err != nil {
+		writeNotificationError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, tmpl)
+}
+
+// ResetTemplate godoc
+// @Summary Reset a notification template
+// @Description Removes the database override and returns the file or built-in template now in force (admin only)
+// @Tags Notifications
+// @Security BasicAuth
+// @Produce json
+// @Param name path string true "Template name"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/notification-templates/{name} [delete]
+func (h *NotificationHandler) ResetTemplate(w http.ResponseWriter, r *http.Request) {
+	tmpl, err := h.service.ResetTemplate(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "name"))
+	if err != nil {
+		writeNotificationError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, tmpl)
+}
+
+// RegisterDevice godoc
+// @Summary Register a device for push notifications
+// @Description Stores the mobile app's FCM registration token for the participant. Registering a known token again moves it to this participant and makes it valid again.
+// @Tags Notifications
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param participant_id path string true "Participant ID"
+// @Param payload body service.RegisterDeviceInput true "Device"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /participants/{participant_id}/devices [post]
+func (h *NotificationHandler) RegisterDevice(w http.ResponseWriter, r *http.Request) {
+	var req service.RegisterDeviceInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	device, err := h.service.RegisterDevice(r.Context(), chi.URLParam(r, "participant_id"), req)
+	if err != nil {
+		writeNotificationError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, device)
+}
+
+// Devices godoc
+// @Summary List a participant's devices
+// @Description Registered devices with their platform; invalidated_at is set once FCM rejected the token
+// @Tags Notifications
+// @Security BasicAuth
+// @Produce json
+// @Param participant_id path string true "Participant ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /participants/{participant_id}/devices [get]
+func (h *NotificationHandler) Devices(w http.ResponseWriter, r *http.Request) {
+	devices, err := h.service.Devices(r.Context(), chi.URLParam(r, "participant_id"))
+	if err != nil {
+		writeNotificationError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{"devices": devices})
+}
+
+// RemoveDevice godoc
+// @Summary Unregister a device
+// @Description Stops push notifications to the device, e.g. when the participant signs out of the app
+// @Tags Notifications
+// @Security BasicAuth
+// @Produce json
+// @Param participant_id path string true "Participant ID"
+// @Param device_id path string true "Device ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /participants/{participant_id}/devices/{device_id} [delete]
+func (h *NotificationHandler) RemoveDevice(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.RemoveDevice(r.Context(), chi.URLParam(r, "participant_id"), chi.URLParam(r, "device_id")); err != nil {
+		writeNotificationError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"message": "device removed"})
+}
+
+// Preferences godoc
+// @Summary Get a member's notification preferences
+// @Description Channels in order of preference, language, quiet hours and opt-outs; defaults when none were saved
+// @Tags Notifications
+// @Security BasicAuth
+// @Produce json
+// @Param member_id path string true "Member ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /members/{member_id}/notification-preferences [get]
+func (h *NotificationHandler) Preferences(w http.ResponseWriter, r *http.Request) {
+	preference, err := h.service.Preferences(r.Context(), chi.URLParam(r, "member_id"))
+	if err != nil {
+		writeNotificationError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, preference)
+}
+
+// SavePreferences godoc
+// @Summary Set a member's notification preferences
+// @Description Replaces the member's preferences. An empty channel list allows every channel; notifications due in quiet hours are sent when they end.
+// @Tags Notifications
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param member_id path string true "Member ID"
+// @Param payload body service.NotificationPreferenceInput true "Preferences"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /members/{member_id}/notification-preferences [put]
+func (h *NotificationHandler) SavePreferences(w http.ResponseWriter, r *http.Request) {
+	var req service.NotificationPreferenceInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	preference, err := h.service.SavePreferences(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "member_id"), req)
+	if err != nil {
+		writeNotificationError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, preference)
+}
+
+// ResetPreferences godoc
+// @Summary Reset a member's notification preferences
+// @Description Drops the saved preferences, including an email unsubscribe, and returns the defaults now in force
+// @Tags Notifications
+// @Security BasicAuth
+// @Produce json
+// @Param member_id path string true "Member ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /members/{member_id}/notification-preferences [delete]
+func (h *NotificationHandler) ResetPreferences(w http.ResponseWriter, r *http.Request) {
+	preference, err := h.service.ResetPreferences(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "member_id"))
+	if err != nil {
+		writeNotificationError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, preference)
+}
+
+// Unsubscribe godoc
+// @Summary Unsubscribe from notification emails
+// @Description Target of the unsubscribe link and List-Unsubscribe header in notification emails; needs no credentials, the token identifies the member
+// @Tags Notifications
+// @Produce json
+// @Param token query string true "Unsubscribe token from the email"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /notifications/unsubscribe [get]
+// @Router /notifications/unsubscribe [post]
+func (h *NotificationHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.Unsubscribe(r.Context(), r.URL.Query().Get("token")); err != nil {
+		writeNotificationError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"message": "you will no longer receive notification emails"})
+}
+
+func writeNotificationError(w http.ResponseWriter, err error) {
+	var verr *service.ValidationError
+	if errors.As(err, &verr) {
+		response.ValidationError(w, "validation failed", verr.Fields)
+		return
+	}
+	switch err {
+	case service.ErrNotificationTemplateNotFound, service.ErrParticipantNotFound, service.ErrDeviceNotFound, service.ErrMemberNotFound, service.ErrNotificationNotFound:
+		response.Error(w, http.StatusNotFound, err.Error())
+	case service.ErrNotificationNotFailed:
+		response.Error(w, http.StatusConflict, err.Error())
+	case service.ErrInvalidUnsubscribeToken:
+		response.Error(w, http.StatusBadRequest, err.Error())
+	default:
+		response.Error(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// OfficerHandler manages the officers who perform field, manual and kiosk verifications.
+type OfficerHandler struct {
+	service *service.OfficerService
+}
+
+// NewOfficerHandler wires dependencies for officer endpoints.
+func NewOfficerHandler(service *service.OfficerService) *OfficerHandler {
+	return &OfficerHandler{service: service}
+}
+
+// Create godoc
+// @Summary Register an officer
+// @Description Code is the employee number, username the account the officer signs in with (required to operate kiosks), and credential_expires_at (YYYY-MM-DD) the last day the field credential is valid (admin only)
+// @Tags Admin
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param payload body service.CreateOfficerInput true "Officer"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/officers [post]
+func (h *OfficerHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req service.CreateOfficerInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	officer, err := h.service.Create(r.Context(), middleware.Actor(r.Context()), req)
+	if err != nil {
+		writeOfficerError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, officer)
+}
+
+// List godoc
+// @Summary List officers
+// @Tags Admin
+// @Security BasicAuth
+// @Produce json
+// @Param branch_id query string false "Branch"
+// @Param region query string false "Region"
+// @Param status query string false "ACTIVE or INACTIVE"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/officers [get]
+func (h *OfficerHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, pageSize, err := pageParams(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	out, err := h.service.List(r.Context(), service.OfficerListInput{
+		BranchID: query.Get("branch_id"),
+		Region:   query.Get("region"),
+		Status:   query.Get("status"),
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		writeOfficerError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, out)
+}
+
+// Get godoc
+// @Summary Get an officer
+// @Tags Admin
+// @Security BasicAuth
+// @Produce json
+// @Param officer_id path string true "Officer ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/officers/{officer_id} [get]
+func (h *OfficerHandler) Get(w http.ResponseWriter, r *http.Request) {
+	officer, err := h.service.Get(r.Context(), chi.URLParam(r, "officer_id"))
+	if err != nil {
+		writeOfficerError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, officer)
+}
+
+// Update godoc
+// @Summary Update an officer
+// @Description Only the provided fields change; status INACTIVE stops the officer from performing verifications (admin only)
+// @Tags Admin
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param officer_id path string true "Officer ID"
+// @Param payload body service.UpdateOfficerInput true "Fields to change"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/officers/{officer_id} [patch]
+func (h *OfficerHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var req service.UpdateOfficerInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	officer, err := h.service.Update(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "officer_id"), req)
+	if err != nil {
+		writeOfficerError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, officer)
+}
+
+// Report godoc
+// @Summary Verifications per officer
+// @Description Manual, proxy and kiosk verifications each officer performed in a year, quarter or month, busiest first, with their outcomes, participants, busiest day and rapid field verifications. Officers are flagged DAILY_PEAK above OFFICERS_MAX_DAILY_VERIFICATIONS on a day, RAPID_FIELD_VERIFICATIONS for manual or proxy verifications closer together than OFFICERS_MIN_FIELD_INTERVAL_MINUTES, and HIGH_VOLUME at more than three times the typical officer's count (admin only)
+// @Tags Admin
+// @Security BasicAuth
+// @Produce json
+// @Param period query string true "Year (2024), quarter (2024-Q3) or month (2024-07)"
+// @Param branch_id query string false "Only officers of a branch"
+// @Param region query string false "Only officers of a region"
+// @Success 200 {object} service.OfficerReport
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/officers/report [get]
+func (h *OfficerHandler) Report(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	report, err := h.service.Report(r.Context(), service.OfficerReportInput{
+		Period:   query.Get("period"),
+		BranchID: query.Get("branch_id"),
+		Region:   query.Get("region"),
+	})
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, report)
+}
+
+func writeOfficerError(w http.ResponseWriter, err error) {
+	var verr *service.ValidationError
+	if errors.As(err, &verr) {
+		response.ValidationError(w, "validation failed", verr.Fields)
+		return
+	}
+	switch err {
+	case service.ErrOfficerNotFound:
+		response.Error(w, http.StatusNotFound, err.Error())
+	case service.ErrOfficerConflict:
+		response.Error(w, http.StatusConflict, err.Error())
+	default:
+		response.Error(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// writeOfficerEligibilityError answers for a verification whose officer is
+// unknown or may not perform it.
+func writeOfficerEligibilityError(w http.ResponseWriter, err error) bool {
+	switch err {
+	case service.ErrOfficerNotFound:
+		response.Error(w, http.StatusNotFound, err.Error())
+	case service.ErrOfficerInactive:
+		response.ErrorWithCode(w, http.StatusForbidden, "OFFICER_INACTIVE", err.Error())
+	case service.ErrOfficerCredentialExpired:
+		response.ErrorWithCode(w, http.StatusForbidden, "OFFICER_CREDENTIAL_EXPIRED", err.Error())
+	case service.ErrOperatorNotOfficer:
+		response.ErrorWithCode(w, http.StatusForbidden, "OPERATOR_NOT_OFFICER", err.Error())
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// pageParams reads the optional page and page_size query parameters.
+// Zero values let the service layer apply its defaults.
+func pageParams(r *http.Request) (int, int, error) {
+	page, err := intQuery(r, "page")
+	if err != nil {
+		return 0, 0, err
+	}
+	pageSize, err := intQuery(r, "page_size")
+	if err != nil {
+		return 0, 0, err
+	}
+	return page, pageSize, nil
+}
+
+func intQuery(r *http.Request, key string) (int, error) {
+	raw := r.URL.Query().Get(key)
+	if raw == "" {
+		return 0, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid %s", key)
+	}
+	return value, nil
+}
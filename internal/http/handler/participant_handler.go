@@ -1,12 +1,16 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 
+	"life-certificates/internal/domain"
+	"life-certificates/internal/http/middleware"
 	"life-certificates/internal/http/response"
 	"life-certificates/internal/service"
 )
@@ -23,18 +27,27 @@ func NewParticipantHandler(service *service.ParticipantService) *ParticipantHand
 
 // Register godoc
 // @Summary Register participant
-// @Description Register participant and store reference with FR Core
+// @Description Register participant and store reference with FR Core. An optional KTP photo is read with OCR and compared with the NIK, name and birth date, and its portrait with the selfie; disagreements are returned as a ktp_check awaiting operator confirmation, and a portrait unlike the selfie is rejected with 422 KTP_FACE_MISMATCH when KTP_FACE_MATCH is reject
 // @Tags Participants
 // @Security BasicAuth
 // @Accept multipart/form-data
 // @Produce json
 // @Param nik formData string true "Participant NIK"
 // @Param name formData string true "Participant name"
+// @Param birth_date formData string false "Birth date (YYYY-MM-DD), compared with the KTP"
+// @Param phone_number formData string false "Phone number for notifications and one-time codes"
+// @Param email formData string false "Email address for notifications and one-time codes"
+// @Param address formData string false "Postal address"
 // @Param image formData file true "Initial selfie image"
+// @Param ktp_image formData file false "KTP photo (JPEG or PNG)"
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 409 {object} map[string]interface{}
+// @Failure 415 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
 // @Router /participants/register [post]
 func (h *ParticipantHandler) Register(w http.ResponseWriter, r *http.Request) {
 	if err := r.ParseMultipartForm(20 << 20); err != nil {
@@ -54,17 +67,109 @@ func (h *ParticipantHandler) Register(w http.ResponseWriter, r *http.Request) {
 		response.Error(w, http.StatusBadRequest, "failed to read image")
 		return
 	}
+	ktp, ok := readKTPPhoto(w, r)
+	if !ok {
+		return
+	}
 
 	out, err := h.service.Register(r.Context(), service.RegisterInput{
-		NIK:       r.FormValue("nik"),
-		Name:      r.FormValue("name"),
+		NIK:         r.FormValue("nik"),
+		Name:        r.FormValue("name"),
+		Image:       imageBytes,
+		ImageName:   header.Filename,
+		BirthDate:   r.FormValue("birth_date"),
+		PhoneNumber: r.FormValue("phone_number"),
+		Email:       r.FormValue("email"),
+		Address:     r.FormValue("address"),
+		KTP:         ktp,
+		Actor:       middleware.Actor(r.Context()),
+	})
+	if err != nil {
+		if writeImageFormatError(w, err) || writeFRCoreBusyError(w, err) || writeFaceQualityError(w, err) || writeKTPPhotoError(w, err) {
+			return
+		}
+		switch err {
+		case service.ErrParticipantExists:
+			response.Error(w, http.StatusConflict, err.Error())
+		case service.ErrConsentRequired:
+			response.ErrorWithCode(w, http.StatusForbidden, "CONSENT_REQUIRED", err.Error())
+		default:
+			response.Error(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	response.Success(w, http.StatusCreated, map[string]interface{}{
+		"participant_id":  out.ParticipantID,
+		"fr_ref":          out.FRRef,
+		"fr_external_ref": out.FRExternalRef,
+		"ktp_check":       out.KTPCheck,
+	})
+}
+
+// RegisterFromMember godoc
+// @Summary Register participant from member
+// @Description Register a participant using an existing member's NIK and name and link both records
+// @Tags Participants
+// @Security BasicAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param member_id formData string true "Member ID"
+// @Param image formData file true "Initial selfie image"
+// @Param ktp_image formData file false "KTP photo (JPEG or PNG), compared with the member's NIK, name and birth date and with the selfie"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Failure 415 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /participants/register-from-member [post]
+func (h *ParticipantHandler) RegisterFromMember(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(20 << 20); err != nil {
+		response.Error(w, http.StatusBadRequest, "failed to parse multipart form")
+		return
+	}
+
+	file, header, err := r.FormFile("image")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "image file is required")
+		return
+	}
+	defer file.Close()
+
+	imageBytes, err := io.ReadAll(file)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "failed to read image")
+		return
+	}
+	ktp, ok := readKTPPhoto(w, r)
+	if !ok {
+		return
+	}
+
+	out, err := h.service.RegisterFromMember(r.Context(), service.RegisterFromMemberInput{
+		MemberID:  r.FormValue("member_id"),
 		Image:     imageBytes,
 		ImageName: header.Filename,
+		KTP:       ktp,
+		Actor:     middleware.Actor(r.Context()),
 	})
 	if err != nil {
+		if writeImageFormatError(w, err) || writeFRCoreBusyError(w, err) || writeFaceQualityError(w, err) || writeKTPPhotoError(w, err) {
+			return
+		}
 		switch err {
-		case service.ErrParticipantExists:
+		case service.ErrMemberNotFound:
+			response.Error(w, http.StatusNotFound, err.Error())
+		case service.ErrParticipantExists, service.ErrMemberAlreadyRegistered:
 			response.Error(w, http.StatusConflict, err.Error())
+		case service.ErrMemberDeceased:
+			response.Error(w, http.StatusUnprocessableEntity, err.Error())
+		case service.ErrConsentRequired:
+			response.ErrorWithCode(w, http.StatusForbidden, "CONSENT_REQUIRED", err.Error())
 		default:
 			response.Error(w, http.StatusBadRequest, err.Error())
 		}
@@ -73,28 +178,152 @@ func (h *ParticipantHandler) Register(w http.ResponseWriter, r *http.Request) {
 
 	response.Success(w, http.StatusCreated, map[string]interface{}{
 		"participant_id":  out.ParticipantID,
+		"member_id":       out.MemberID,
 		"fr_ref":          out.FRRef,
 		"fr_external_ref": out.FRExternalRef,
+		"ktp_check":       out.KTPCheck,
 	})
 }
 
+// EnrollFace godoc
+// @Summary Enroll an additional face
+// @Description Upload another face image for an existing participant to FR Core
+// @Tags Participants
+// @Security BasicAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param participant_id path string true "Participant ID"
+// @Param image formData file true "Face image"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 415 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /participants/{participant_id}/faces [post]
+func (h *ParticipantHandler) EnrollFace(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(20 << 20); err != nil {
+		response.Error(w, http.StatusBadRequest, "failed to parse multipart form")
+		return
+	}
+
+	file, header, err := r.FormFile("image")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "image file is required")
+		return
+	}
+	defer file.Close()
+
+	imageBytes, err := io.ReadAll(file)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "failed to read image")
+		return
+	}
+
+	face, err := h.service.EnrollFace(r.Context(), service.EnrollFaceInput{
+		ParticipantID: chi.URLParam(r, "participant_id"),
+		Image:         imageBytes,
+		ImageName:     header.Filename,
+	})
+	if err != nil {
+		if writeImageFormatError(w, err) || writeFRCoreBusyError(w, err) || writeFaceQualityError(w, err) {
+			return
+		}
+		switch err {
+		case service.ErrParticipantNotFound:
+			response.Error(w, http.StatusNotFound, err.Error())
+		default:
+			response.Error(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	response.Success(w, http.StatusCreated, face)
+}
+
 // List godoc
 // @Summary List participants
 // @Tags Participants
 // @Security BasicAuth
 // @Produce json
+// @Param unmasked query bool false "Return personal identifiers in full (admin only, audit-logged)"
+// @Param fields query string false "Comma-separated fields to return, e.g. participant_id,nik,name; default all"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /participants [get]
 func (h *ParticipantHandler) List(w http.ResponseWriter, r *http.Request) {
+	fields, err := response.ParseFields(r, domain.Participant{})
+	if err != nil {
+		response.ValidationError(w, "validation failed", map[string]string{"fields": err.Error()})
+		return
+	}
+
 	participants, err := h.service.List(r.Context())
 	if err != nil {
 		response.Error(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	response.Success(w, http.StatusOK, map[string]interface{}{"participants": participants})
+	maskParticipants(r, participants)
+	response.Success(w, http.StatusOK, map[string]interface{}{"participants": fields.Apply(participants)})
+}
+
+// Search godoc
+// @Summary Search participants
+// @Description Search participants by exact NIK, partial name, FR label and latest verification status
+// @Tags Participants
+// @Security BasicAuth
+// @Produce json
+// @Param nik query string false "Exact NIK"
+// @Param name query string false "Partial name (case-insensitive)"
+// @Param fr_label query string false "FR Core label"
+// @Param status query string false "Latest verification status (VALID, INVALID, REVIEW)"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Param unmasked query bool false "Return personal identifiers in full (admin only, audit-logged)"
+// @Param fields query string false "Comma-separated fields to return, e.g. participant_id,nik,name; default all"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /participants/search [get]
+func (h *ParticipantHandler) Search(w http.ResponseWriter, r *http.Request) {
+	page, pageSize, err := pageParams(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	fields, err := response.ParseFields(r, domain.Participant{})
+	if err != nil {
+		response.ValidationError(w, "validation failed", map[string]string{"fields": err.Error()})
+		return
+	}
+
+	query := r.URL.Query()
+	out, err := h.service.Search(r.Context(), service.SearchParticipantsInput{
+		NIK:      query.Get("nik"),
+		Name:     query.Get("name"),
+		FRLabel:  query.Get("fr_label"),
+		Status:   query.Get("status"),
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	maskParticipants(r, out.Participants)
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"participants": fields.Apply(out.Participants),
+		"page":         out.Page,
+		"page_size":    out.PageSize,
+		"total":        out.Total,
+	})
 }
 
 // Get godoc
@@ -103,12 +332,20 @@ func (h *ParticipantHandler) List(w http.ResponseWriter, r *http.Request) {
 // @Security BasicAuth
 // @Produce json
 // @Param participant_id path string true "Participant ID"
+// @Param fields query string false "Comma-separated fields to return, e.g. participant_id,nik,name; default all"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /participants/{participant_id} [get]
 func (h *ParticipantHandler) Get(w http.ResponseWriter, r *http.Request) {
+	fields, err := response.ParseFields(r, service.ParticipantDetail{})
+	if err != nil {
+		response.ValidationError(w, "validation failed", map[string]string{"fields": err.Error()})
+		return
+	}
+
 	id := chi.URLParam(r, "participant_id")
 	participant, err := h.service.Get(r.Context(), id)
 	if err != nil {
@@ -121,11 +358,39 @@ func (h *ParticipantHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response.Success(w, http.StatusOK, participant)
+	response.Success(w, http.StatusOK, fields.Apply(participant))
+}
+
+// Schedule godoc
+// @Summary Get participant verification schedule
+// @Description Next verification due date with days remaining. An unfinished campaign sets it first, then the participant's or fund's verification profile, then VERIFICATION_SCHEDULE_POLICY.
+// @Tags Participants
+// @Security BasicAuth
+// @Produce json
+// @Param participant_id path string true "Participant ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /participants/{participant_id}/schedule [get]
+func (h *ParticipantHandler) Schedule(w http.ResponseWriter, r *http.Request) {
+	schedule, err := h.service.Schedule(r.Context(), chi.URLParam(r, "participant_id"))
+	if err != nil {
+		switch err {
+		case service.ErrParticipantNotFound:
+			response.Error(w, http.StatusNotFound, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, schedule)
 }
 
 // Update godoc
 // @Summary Update participant metadata
+// @Description Partially update a participant; omitted fields are left unchanged
 // @Tags Participants
 // @Security BasicAuth
 // @Accept json
@@ -139,6 +404,7 @@ func (h *ParticipantHandler) Get(w http.ResponseWriter, r *http.Request) {
 // @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /participants/{participant_id} [put]
+// @Router /participants/{participant_id} [patch]
 func (h *ParticipantHandler) Update(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "participant_id")
 	var req service.UpdateParticipantInput
@@ -150,6 +416,11 @@ func (h *ParticipantHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	participant, err := h.service.Update(r.Context(), id, req)
 	if err != nil {
+		var verr *service.ValidationError
+		if errors.As(err, &verr) {
+			response.ValidationError(w, "validation failed", verr.Fields)
+			return
+		}
 		switch err {
 		case service.ErrParticipantNotFound:
 			response.Error(w, http.StatusNotFound, err.Error())
@@ -188,3 +459,160 @@ func (h *ParticipantHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// Suspend godoc
+// @Summary Suspend participant
+// @Tags Participants
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param participant_id path string true "Participant ID"
+// @Param payload body service.ChangeStatusInput true "Reason for the change"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /participants/{participant_id}/suspend [post]
+func (h *ParticipantHandler) Suspend(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.service.Suspend)
+}
+
+// Unsuspend godoc
+// @Summary Reactivate suspended or blocked participant
+// @Tags Participants
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param participant_id path string true "Participant ID"
+// @Param payload body service.ChangeStatusInput false "Reason for the change"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /participants/{participant_id}/unsuspend [post]
+func (h *ParticipantHandler) Unsuspend(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.service.Unsuspend)
+}
+
+// Block godoc
+// @Summary Block participant
+// @Tags Participants
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param participant_id path string true "Participant ID"
+// @Param payload body service.ChangeStatusInput true "Reason for the change"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /participants/{participant_id}/block [post]
+func (h *ParticipantHandler) Block(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, h.service.Block)
+}
+
+func (h *ParticipantHandler) changeStatus(w http.ResponseWriter, r *http.Request, apply func(context.Context, string, service.ChangeStatusInput) (*domain.Participant, error)) {
+	var req service.ChangeStatusInput
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+			return
+		}
+	}
+
+	participant, err := apply(r.Context(), chi.URLParam(r, "participant_id"), req)
+	if err != nil {
+		switch err {
+		case service.ErrParticipantNotFound:
+			response.Error(w, http.StatusNotFound, err.Error())
+		case service.ErrStatusReasonRequired:
+			response.Error(w, http.StatusBadRequest, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, participant)
+}
+
+// writeImageFormatError answers 415 for photos that are not JPEG, PNG, HEIC or WebP.
+func writeImageFormatError(w http.ResponseWriter, err error) bool {
+	if !errors.Is(err, service.ErrUnsupportedImageFormat) {
+		return false
+	}
+	response.ErrorWithCode(w, http.StatusUnsupportedMediaType, "UNSUPPORTED_IMAGE_FORMAT", err.Error())
+	return true
+}
+
+// writeFRCoreBusyError answers 503 when FR Core calls are queued beyond their limits.
+func writeFRCoreBusyError(w http.ResponseWriter, err error) bool {
+	if !errors.Is(err, service.ErrFRCoreBusy) {
+		return false
+	}
+	response.ErrorWithCode(w, http.StatusServiceUnavailable, "FRCORE_BUSY", err.Error())
+	return true
+}
+
+// writeFaceQualityError answers 422 with the reasons a photo failed the face quality check.
+func writeFaceQualityError(w http.ResponseWriter, err error) bool {
+	var qerr *service.FaceQualityError
+	if !errors.As(err, &qerr) {
+		return false
+	}
+	response.ErrorWithData(w, http.StatusUnprocessableEntity, qerr.Error(), map[string]interface{}{
+		"code":    "FACE_QUALITY_REJECTED",
+		"reasons": qerr.Reasons,
+	})
+	return true
+}
+
+// readKTPPhoto reads the optional KTP photo of a registration form.
+func readKTPPhoto(w http.ResponseWriter, r *http.Request) (*service.KTPPhoto, bool) {
+	file, header, err := r.FormFile("ktp_image")
+	if errors.Is(err, http.ErrMissingFile) {
+		return nil, true
+	}
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "failed to read ktp_image")
+		return nil, false
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "failed to read ktp_image")
+		return nil, false
+	}
+	return &service.KTPPhoto{Image: data, FileName: header.Filename}, true
+}
+
+// writeKTPPhotoError answers for KTP photos that are invalid, infected, could
+// not be scanned or show someone other than the selfie.
+func writeKTPPhotoError(w http.ResponseWriter, err error) bool {
+	if errors.Is(err, service.ErrKTPFaceMismatch) {
+		response.ErrorWithCode(w, http.StatusUnprocessableEntity, "KTP_FACE_MISMATCH", err.Error())
+		return true
+	}
+	var verr *service.ValidationError
+	if errors.As(err, &verr) {
+		response.ValidationError(w, "validation failed", verr.Fields)
+		return true
+	}
+	var infected *service.InfectedUploadError
+	if errors.As(err, &infected) {
+		response.ErrorWithCode(w, http.StatusUnprocessableEntity, "INFECTED_UPLOAD", err.Error())
+		return true
+	}
+	if errors.Is(err, service.ErrUploadScanFailed) {
+		response.Error(w, http.StatusServiceUnavailable, service.ErrUploadScanFailed.Error())
+		return true
+	}
+	return false
+}
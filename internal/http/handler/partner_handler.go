@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// PartnerHandler exposes the read-only partner API and its key management.
+type PartnerHandler struct {
+	service *service.PartnerService
+	access  middleware.AccessRecorder
+}
+
+// NewPartnerHandler wires dependencies for partner endpoints. Every status a
+// partner reads is recorded with access.
+func NewPartnerHandler(service *service.PartnerService, access middleware.AccessRecorder) *PartnerHandler {
+	return &PartnerHandler{service: service, access: access}
+}
+
+// PartnerStatusRequest looks up a participant; the NIK travels in the body to keep it out of URLs and logs.
+type PartnerStatusRequest struct {
+	NIK string `json:"nik"`
+}
+
+// Status godoc
+// @Summary Look up a participant's certificate status
+// @Description Requires the status:read scope. Keys restricted to a fund only find that fund's participants.
+// @Tags Partner
+// @Security PartnerKey
+// @Accept json
+// @Produce json
+// @Param payload body PartnerStatusRequest true "NIK"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /partner/v1/status [post]
+func (h *PartnerHandler) Status(w http.ResponseWriter, r *http.Request) {
+	var req PartnerStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	partner := middleware.PartnerFromContext(r.Context())
+	status, err := h.service.Status(r.Context(), partner.Fund, req.NIK)
+	if err != nil {
+		var verr *service.ValidationError
+		if errors.As(err, &verr) {
+			response.ValidationError(w, "validation failed", verr.Fields)
+			return
+		}
+		switch err {
+		case service.ErrPartnerStatusNotFound:
+			response.Error(w, http.StatusNotFound, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	recordAccess(h.access, r, domain.AccessResourceCertificateStatus, status.ParticipantID)
+	response.Success(w, http.StatusOK, status)
+}
+
+// Changes godoc
+// @Summary Feed of certificate status changes
+// @Description Requires the changes:read scope. Participants whose status changed after since, oldest change first. Pass next_cursor back as cursor to read the following page; an empty page keeps the cursor, so it can be polled.
+// @Tags Partner
+// @Security PartnerKey
+// @Produce json
+// @Param since query string false "RFC 3339 timestamp; required without cursor"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query int false "Page size (default 100, max 1000)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /partner/v1/changes [get]
+func (h *PartnerHandler) Changes(w http.ResponseWriter, r *http.Request) {
+	limit, err := intQuery(r, "limit")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	partner := middleware.PartnerFromContext(r.Context())
+	out, err := h.service.Changes(r.Context(), partner.Fund, service.PartnerChangesInput{
+		Since:  r.URL.Query().Get("since"),
+		Cursor: r.URL.Query().Get("cursor"),
+		Limit:  limit,
+	})
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(out.Items) > 0 {
+		recordAccess(h.access, r, domain.AccessResourceStatusFeed, partner.KeyID)
+	}
+	response.Success(w, http.StatusOK, out)
+}
+
+// CreateKey godoc
+// @Summary Issue a partner API key
+// @Description Scopes: status:read, changes:read. The key is returned only in this response.
+// @Tags Partner
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param payload body service.CreatePartnerKeyInput true "Key"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/partner-keys [post]
+func (h *PartnerHandler) CreateKey(w http.ResponseWriter, r *http.Request) {
+	var req service.CreatePartnerKeyInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	key, err := h.service.CreateKey(r.Context(), middleware.Actor(r.Context()), req)
+	if err != nil {
+		var verr *service.ValidationError
+		if errors.As(err, &verr) {
+			response.ValidationError(w, "validation failed", verr.Fields)
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusCreated, key)
+}
+
+// ListKeys godoc
+// @Summary List partner API keys
+// @Description Keys are identified by their prefix; revoked keys are included
+// @Tags Partner
+// @Security BasicAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/partner-keys [get]
+func (h *PartnerHandler) ListKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.service.ListKeys(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, keys)
+}
+
+// RevokeKey godoc
+// @Summary Revoke a partner API key
+// @Tags Partner
+// @Security BasicAuth
+// @Produce json
+// @Param key_id path string true "Partner key ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/partner-keys/{key_id} [delete]
+func (h *PartnerHandler) RevokeKey(w http.ResponseWriter, r *http.Request) {
+	key, err := h.service.RevokeKey(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "key_id"))
+	if err != nil {
+		switch err {
+		case service.ErrPartnerKeyNotFound:
+			response.Error(w, http.StatusNotFound, err.Error())
+		case service.ErrPartnerKeyRevoked:
+			response.Error(w, http.StatusConflict, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, key)
+}
+
+// recordAccess writes a read of personal data that the route's URL does not identify to the access log.
+func recordAccess(access middleware.AccessRecorder, r *http.Request, resourceType, resourceID string) {
+	// The response is about to be sent, so record even if the client went away.
+	ctx := context.WithoutCancel(r.Context())
+	if err := access.RecordAccess(ctx, middleware.Actor(r.Context()), resourceType, resourceID, r.URL.Path, r.RemoteAddr); err != nil {
+		log.Printf("record access to %s %s: %v", resourceType, resourceID, err)
+	}
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// PaymentPushHandler exposes reconciliation of status pushes to the payment system.
+type PaymentPushHandler struct {
+	service *service.PaymentPushService
+}
+
+// NewPaymentPushHandler wires dependencies for payment push endpoints.
+func NewPaymentPushHandler(service *service.PaymentPushService) *PaymentPushHandler {
+	return &PaymentPushHandler{service: service}
+}
+
+// Unacknowledged godoc
+// @Summary Unacknowledged payment system pushes
+// @Description Reconciliation report of VALID/EXPIRED pushes the payment system has not acknowledged, oldest first, with totals by status and transition
+// @Tags PaymentPush
+// @Security BasicAuth
+// @Produce json
+// @Param status query string false "PENDING or FAILED"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/payment-pushes/unacknowledged [get]
+func (h *PaymentPushHandler) Unacknowledged(w http.ResponseWriter, r *http.Request) {
+	page, pageSize, err := pageParams(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	out, err := h.service.Unacknowledged(r.Context(), r.URL.Query().Get("status"), page, pageSize)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, out)
+}
+
+// Retry godoc
+// @Summary Retry a failed payment system push
+// @Description Moves a FAILED push back to PENDING with a fresh attempt budget
+// @Tags PaymentPush
+// @Security BasicAuth
+// @Produce json
+// @Param push_id path string true "Payment push ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/payment-pushes/{push_id}/retry [post]
+func (h *PaymentPushHandler) Retry(w http.ResponseWriter, r *http.Request) {
+	push, err := h.service.Retry(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "push_id"))
+	if err != nil {
+		switch err {
+		case service.ErrPaymentPushNotFound:
+			response.Error(w, http.StatusNotFound, err.Error())
+		case service.ErrPaymentPushNotFailed:
+			response.Error(w, http.StatusConflict, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, push)
+}
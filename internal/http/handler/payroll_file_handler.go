@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// PayrollFileHandler exposes the delivery log of payroll files.
+type PayrollFileHandler struct {
+	service *service.PayrollFileService
+}
+
+// NewPayrollFileHandler wires dependencies for payroll file endpoints.
+func NewPayrollFileHandler(service *service.PayrollFileService) *PayrollFileHandler {
+	return &PayrollFileHandler{service: service}
+}
+
+// List godoc
+// @Summary List payroll file deliveries
+// @Description Verification status files uploaded to legacy payroll systems over SFTP, latest first, with record counts and SHA-256 checksums
+// @Tags PayrollFile
+// @Security BasicAuth
+// @Produce json
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/payroll-files [get]
+func (h *PayrollFileHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, pageSize, err := pageParams(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	out, err := h.service.List(r.Context(), page, pageSize)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, out)
+}
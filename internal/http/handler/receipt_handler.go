@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	qrcode "github.com/skip2/go-qrcode"
+
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// ReceiptHandler serves certificate receipts and their public check.
+type ReceiptHandler struct {
+	service *service.ReceiptService
+	qrSize  int
+}
+
+// NewReceiptHandler wires dependencies for receipt endpoints; QR codes are qrSize pixels square.
+func NewReceiptHandler(service *service.ReceiptService, qrSize int) *ReceiptHandler {
+	return &ReceiptHandler{service: service, qrSize: qrSize}
+}
+
+// Receipt godoc
+// @Summary Get the receipt of a VALID certificate
+// @Description A QR code pointing at the public receipt check, or with format=json the signed token and its URL
+// @Tags LifeCertificate
+// @Security BasicAuth
+// @Produce png,json
+// @Param certificate_id path string true "Life certificate ID"
+// @Param format query string false "png (default) or json"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /life-certificate/{certificate_id}/receipt [get]
+func (h *ReceiptHandler) Receipt(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "png" && format != "json" {
+		response.Error(w, http.StatusBadRequest, "format must be png or json")
+		return
+	}
+
+	receipt, err := h.service.Issue(r.Context(), chi.URLParam(r, "certificate_id"))
+	if err != nil {
+		switch err {
+		case service.ErrCertificateNotFound:
+			response.Error(w, http.StatusNotFound, err.Error())
+		case service.ErrReceiptNotIssuable:
+			response.Error(w, http.StatusConflict, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	if format == "json" {
+		response.Success(w, http.StatusOK, receipt)
+		return
+	}
+
+	png, err := qrcode.Encode(receipt.URL, qrcode.Medium, h.qrSize)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(png)
+}
+
+// Check godoc
+// @Summary Check a certificate receipt
+// @Description Public. Confirms the receipt was issued by this service and whether the certificate is VALID, EXPIRED or REVOKED, without revealing who it belongs to.
+// @Tags LifeCertificate
+// @Produce json
+// @Param token path string true "Receipt token from the QR code"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /verify-receipt/{token} [get]
+func (h *ReceiptHandler) Check(w http.ResponseWriter, r *http.Request) {
+	check, err := h.service.Check(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		switch err {
+		case service.ErrReceiptNotFound:
+			response.Error(w, http.StatusNotFound, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	// The answer changes when the certificate expires or is overridden.
+	w.Header().Set("Cache-Control", "no-store")
+	response.Success(w, http.StatusOK, check)
+}
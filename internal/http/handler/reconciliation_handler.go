@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// ReconciliationHandler exposes FR Core reconciliation admin endpoints.
+type ReconciliationHandler struct {
+	service *service.ReconciliationService
+}
+
+// NewReconciliationHandler wires dependencies for reconciliation endpoints.
+func NewReconciliationHandler(service *service.ReconciliationService) *ReconciliationHandler {
+	return &ReconciliationHandler{service: service}
+}
+
+// Trigger godoc
+// @Summary Trigger FR Core reconciliation
+// @Description Queue a background job comparing FR Core enrollments against local FR identities. Orphans are only reported unless delete=true.
+// @Tags Admin
+// @Security BasicAuth
+// @Produce json
+// @Param delete query bool false "Delete orphaned FR Core enrollments"
+// @Success 202 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/frcore/reconciliations [post]
+func (h *ReconciliationHandler) Trigger(w http.ResponseWriter, r *http.Request) {
+	deleteOrphans := r.URL.Query().Get("delete") == "true"
+
+	run, err := h.service.Trigger(r.Context(), middleware.Actor(r.Context()), deleteOrphans)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusAccepted, run)
+}
+
+// List godoc
+// @Summary List FR Core reconciliation runs
+// @Tags Admin
+// @Security BasicAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/frcore/reconciliations [get]
+func (h *ReconciliationHandler) List(w http.ResponseWriter, r *http.Request) {
+	runs, err := h.service.List(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{"runs": runs})
+}
+
+// Get godoc
+// @Summary Get FR Core reconciliation run
+// @Tags Admin
+// @Security BasicAuth
+// @Produce json
+// @Param run_id path string true "Reconciliation run ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/frcore/reconciliations/{run_id} [get]
+func (h *ReconciliationHandler) Get(w http.ResponseWriter, r *http.Request) {
+	run, err := h.service.Get(r.Context(), chi.URLParam(r, "run_id"))
+	if err != nil {
+		switch err {
+		case service.ErrReconciliationNotFound:
+			response.Error(w, http.StatusNotFound, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, run)
+}
@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// ReviewHandler exposes the manual review queue endpoints.
+type ReviewHandler struct {
+	service *service.ReviewService
+}
+
+// NewReviewHandler wires dependencies for review endpoints.
+func NewReviewHandler(service *service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{service: service}
+}
+
+// Queue godoc
+// @Summary List the manual review queue
+// @Description Unresolved REVIEW attempts, oldest first
+// @Tags Review
+// @Security BasicAuth
+// @Produce json
+// @Param participant_id query string false "Participant ID"
+// @Param assigned_to query string false "Reviewer holding the item"
+// @Param unassigned query bool false "Only unassigned items"
+// @Param senior query bool false "Only senior reviews (true) or only the others (false)"
+// @Param from query string false "Attempts on or after date (YYYY-MM-DD)"
+// @Param to query string false "Attempts on or before date (YYYY-MM-DD)"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /review/queue [get]
+func (h *ReviewHandler) Queue(w http.ResponseWriter, r *http.Request) {
+	page, pageSize, err := pageParams(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	var senior *bool
+	if raw := query.Get("senior"); raw != "" {
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "senior must be true or false")
+			return
+		}
+		senior = &value
+	}
+	out, err := h.service.Queue(r.Context(), service.ReviewQueueInput{
+		ParticipantID: query.Get("participant_id"),
+		AssignedTo:    query.Get("assigned_to"),
+		Unassigned:    query.Get("unassigned") == "true",
+		Senior:        senior,
+		From:          query.Get("from"),
+		To:            query.Get("to"),
+		Page:          page,
+		PageSize:      pageSize,
+	})
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, out)
+}
+
+// Claim godoc
+// @Summary Claim a review item
+// @Description Assign the review to the authenticated reviewer
+// @Tags Review
+// @Security BasicAuth
+// @Produce json
+// @Param certificate_id path string true "Life certificate ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /review/{certificate_id}/claim [post]
+func (h *ReviewHandler) Claim(w http.ResponseWriter, r *http.Request) {
+	record, err := h.service.Claim(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "certificate_id"))
+	if err != nil {
+		writeReviewError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, record)
+}
+
+// Assign godoc
+// @Summary Assign a review item
+// @Tags Review
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param certificate_id path string true "Life certificate ID"
+// @Param payload body service.AssignReviewInput true "Reviewer"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /review/{certificate_id}/assign [post]
+func (h *ReviewHandler) Assign(w http.ResponseWriter, r *http.Request) {
+	var req service.AssignReviewInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	record, err := h.service.Assign(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "certificate_id"), req)
+	if err != nil {
+		writeReviewError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, record)
+}
+
+// Resolve godoc
+// @Summary Resolve a review item
+// @Description Approve (VALID) or reject (INVALID) a REVIEW attempt; notes are mandatory
+// @Tags Review
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param certificate_id path string true "Life certificate ID"
+// @Param payload body service.ResolveReviewInput true "Decision"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /review/{certificate_id}/resolve [post]
+func (h *ReviewHandler) Resolve(w http.ResponseWriter, r *http.Request) {
+	var req service.ResolveReviewInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	record, err := h.service.Resolve(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "certificate_id"), req)
+	if err != nil {
+		writeReviewError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, record)
+}
+
+// History godoc
+// @Summary Review audit trail
+// @Description Who claimed, assigned and decided the review, and when
+// @Tags Review
+// @Security BasicAuth
+// @Produce json
+// @Param certificate_id path string true "Life certificate ID"
+// @Param fields query string false "Comma-separated fields to return; default all"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /review/{certificate_id}/history [get]
+func (h *ReviewHandler) History(w http.ResponseWriter, r *http.Request) {
+	fields, err := response.ParseFields(r, domain.AuditLog{})
+	if err != nil {
+		response.ValidationError(w, "validation failed", map[string]string{"fields": err.Error()})
+		return
+	}
+
+	entries, err := h.service.History(r.Context(), chi.URLParam(r, "certificate_id"))
+	if err != nil {
+		writeReviewError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{"history": fields.Apply(entries)})
+}
+
+// Overdue godoc
+// @Summary List overdue reviews
+// @Description Pending reviews past their SLA due date, grouped by age bucket (0-24h, 1-3d, 3-7d, 7d+ overdue)
+// @Tags Review
+// @Security BasicAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /review/overdue [get]
+func (h *ReviewHandler) Overdue(w http.ResponseWriter, r *http.Request) {
+	out, err := h.service.Overdue(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, out)
+}
+
+// Metrics godoc
+// @Summary Review SLA metrics
+// @Description Backlog size, overdue count and average time-to-decision for reviews decided in the window
+// @Tags Review
+// @Security BasicAuth
+// @Produce json
+// @Param from query string false "Decisions on or after date (YYYY-MM-DD)"
+// @Param to query string false "Decisions on or before date (YYYY-MM-DD)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /review/metrics [get]
+func (h *ReviewHandler) Metrics(w http.ResponseWriter, r *http.Request) {
+	out, err := h.service.Metrics(r.Context(), r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, out)
+}
+
+func writeReviewError(w http.ResponseWriter, err error) {
+	switch err {
+	case service.ErrCertificateNotFound:
+		response.Error(w, http.StatusNotFound, err.Error())
+	case service.ErrReviewNotPending, service.ErrReviewClaimedByOther:
+		response.Error(w, http.StatusConflict, err.Error())
+	case service.ErrSeniorReviewRequired:
+		response.Error(w, http.StatusForbidden, err.Error())
+	default:
+		response.Error(w, http.StatusBadRequest, err.Error())
+	}
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// SchedulerHandler exposes the state of scheduled tasks.
+type SchedulerHandler struct {
+	service *service.SchedulerService
+}
+
+// NewSchedulerHandler wires dependencies for scheduler endpoints.
+func NewSchedulerHandler(service *service.SchedulerService) *SchedulerHandler {
+	return &SchedulerHandler{service: service}
+}
+
+// List godoc
+// @Summary List scheduled tasks
+// @Description Schedule, last claimed slot, owning replica and outcome of the last run of every task (admin only)
+// @Tags Jobs
+// @Security BasicAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/scheduled-tasks [get]
+func (h *SchedulerHandler) List(w http.ResponseWriter, r *http.Request) {
+	tasks, err := h.service.Tasks(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{"tasks": tasks})
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// SeedHandler loads demo data; it is only routed when SEED_ENABLED is set.
+type SeedHandler struct {
+	service *service.SeedService
+}
+
+// NewSeedHandler wires dependencies for the seed endpoint.
+func NewSeedHandler(service *service.SeedService) *SeedHandler {
+	return &SeedHandler{service: service}
+}
+
+// Seed godoc
+// @Summary Load demo data
+// @Description Creates fake members, participants with FR labels (nothing is enrolled in FR Core) and certificate histories, generated from a seed or given as fixtures. Existing NIKs are skipped. Only available when SEED_ENABLED is true (admin only)
+// @Tags Admin
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param payload body service.SeedInput false "Sizes and seed of the generated data, or fixtures; an empty body generates 50 members and 30 participants"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/seed [post]
+func (h *SeedHandler) Seed(w http.ResponseWriter, r *http.Request) {
+	var req service.SeedInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	result, err := h.service.Seed(r.Context(), middleware.Actor(r.Context()), req)
+	if err != nil {
+		var verr *service.ValidationError
+		switch {
+		case errors.As(err, &verr):
+			response.ValidationError(w, "validation failed", verr.Fields)
+		case errors.Is(err, service.ErrMemberNotFound):
+			response.Error(w, http.StatusBadRequest, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(w, http.StatusCreated, result)
+}
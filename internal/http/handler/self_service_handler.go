@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// SelfServiceHandler exposes self-service sign-in and verification to pensioners.
+type SelfServiceHandler struct {
+	service  *service.SelfServiceService
+	sessions *service.VerificationSessionService
+}
+
+// NewSelfServiceHandler wires dependencies for self-service endpoints.
+func NewSelfServiceHandler(service *service.SelfServiceService, sessions *service.VerificationSessionService) *SelfServiceHandler {
+	return &SelfServiceHandler{service: service, sessions: sessions}
+}
+
+// SelfServiceCodeRequest asks for a one-time code.
+type SelfServiceCodeRequest struct {
+	NIK string `json:"nik"`
+}
+
+// SelfServiceTokenRequest exchanges a one-time code for a token.
+type SelfServiceTokenRequest struct {
+	NIK  string `json:"nik"`
+	Code string `json:"code"`
+}
+
+// RequestCode godoc
+// @Summary Request a self-service sign-in code
+// @Description Sends a one-time code to the registered email or phone of the member linked to the participant. The answer is the same whether or not the NIK is registered.
+// @Tags SelfService
+// @Accept json
+// @Produce json
+// @Param payload body SelfServiceCodeRequest true "NIK"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /self/otp [post]
+func (h *SelfServiceHandler) RequestCode(w http.ResponseWriter, r *http.Request) {
+	var req SelfServiceCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	out, err := h.service.RequestCode(r.Context(), req.NIK)
+	if err != nil {
+		var verr *service.ValidationError
+		if errors.As(err, &verr) {
+			response.ValidationError(w, "validation failed", verr.Fields)
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusAccepted, out)
+}
+
+// ExchangeCode godoc
+// @Summary Exchange a sign-in code for a token
+// @Description Returns a short-lived bearer token that can only submit a verification for the participant with the NIK
+// @Tags SelfService
+// @Accept json
+// @Produce json
+// @Param payload body SelfServiceTokenRequest true "NIK and code"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Router /self/token [post]
+func (h *SelfServiceHandler) ExchangeCode(w http.ResponseWriter, r *http.Request) {
+	var req SelfServiceTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	token, err := h.service.ExchangeCode(r.Context(), req.NIK, req.Code)
+	if err != nil {
+		var verr *service.ValidationError
+		if errors.As(err, &verr) {
+			response.ValidationError(w, "validation failed", verr.Fields)
+			return
+		}
+		switch err {
+		case service.ErrInvalidSelfServiceCode:
+			response.Error(w, http.StatusUnauthorized, err.Error())
+		case service.ErrSelfServiceCodeLocked:
+			response.Error(w, http.StatusTooManyRequests, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, token)
+}
+
+// CreateSession godoc
+// @Summary Open a verification session for the signed-in participant
+// @Description Like POST /life-certificate/sessions for the participant the bearer token was issued to
+// @Tags SelfService
+// @Security SelfServiceToken
+// @Produce json
+// @Success 201 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /self/sessions [post]
+func (h *SelfServiceHandler) CreateSession(w http.ResponseWriter, r *http.Request) {
+	session, err := h.sessions.Create(r.Context(), middleware.Actor(r.Context()), service.CreateVerificationSessionInput{
+		ParticipantID: middleware.SelfParticipant(r.Context()),
+	})
+	if err != nil {
+		switch err {
+		case service.ErrParticipantNotFound:
+			response.Error(w, http.StatusNotFound, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(w, http.StatusCreated, session)
+}
+
+// Verify godoc
+// @Summary Submit a self-service verification
+// @Description Like POST /life-certificate/verify for the participant the bearer token was issued to; a participant_id form field is ignored
+// @Tags SelfService
+// @Security SelfServiceToken
+// @Accept multipart/form-data
+// @Produce json
+// @Param session_token formData string false "Token of a session from POST /self/sessions, also accepted in the X-Verification-Session header; required when VERIFICATION_SESSION_REQUIRED is on"
+// @Param image formData file true "Selfie image"
+// @Param location formData string false "Where the selfie was captured (default self-service)"
+// @Param latitude formData number false "Declared latitude, compared with the selfie's GPS position"
+// @Param longitude formData number false "Declared longitude, compared with the selfie's GPS position"
+// @Param X-Device-ID header string false "Stable identifier of the capturing device, used for fraud scoring"
+// @Param X-Device-Model header string false "Model of the capturing device, recorded in its fingerprint"
+// @Param X-Device-OS header string false "Operating system of the capturing device, recorded in its fingerprint"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 415 {object} map[string]interface{}
+// @Failure 423 {object} map[string]interface{}
+// @Failure 429 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /self/verify [post]
+func (h *SelfServiceHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	input, ok := readVerifyForm(w, r)
+	if !ok {
+		return
+	}
+	participantID := middleware.SelfParticipant(r.Context())
+	// A session opened for another participant is refused.
+	input.ParticipantID = participantID
+	input.AllowStepUp = true
+	input, err := h.sessions.Attach(r.Context(), sessionToken(r), input)
+	if err != nil {
+		writeVerifyError(w, err)
+		return
+	}
+
+	out, err := h.service.Verify(r.Context(), participantID, input)
+	if err != nil {
+		writeVerifyError(w, err)
+		return
+	}
+	if writeStepUp(w, r, h.sessions, input, out) {
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"participant_id":      out.ParticipantID,
+		"verification_status": string(out.Status),
+		"verified_at":         out.VerifiedAt,
+	})
+}
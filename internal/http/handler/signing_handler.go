@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/signing"
+)
+
+// SigningHandler publishes the key certificates are signed with.
+type SigningHandler struct {
+	key *signing.Key
+}
+
+// NewSigningHandler wires the certificate signing key.
+func NewSigningHandler(key *signing.Key) *SigningHandler {
+	return &SigningHandler{key: key}
+}
+
+// Keys godoc
+// @Summary Certificate signing keys
+// @Description Public. The JWK set that signed PDF and JSON certificates are verified against; x5c holds the key's certificate chain when one is configured.
+// @Tags LifeCertificate
+// @Produce json
+// @Success 200 {object} signing.JWKSet
+// @Router /.well-known/jwks.json [get]
+func (h *SigningHandler) Keys(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	writeJOSE(w, "application/jwk-set+json", h.key.KeySet())
+}
+
+// Chain godoc
+// @Summary Certificate signing chain
+// @Description Public. The signing key's X.509 certificate chain as PEM, leaf first.
+// @Tags LifeCertificate
+// @Produce application/x-pem-file
+// @Success 200 {file} file
+// @Failure 404 {object} map[string]interface{}
+// @Router /.well-known/certificate-chain.pem [get]
+func (h *SigningHandler) Chain(w http.ResponseWriter, r *http.Request) {
+	chain := h.key.ChainPEM()
+	if len(chain) == 0 {
+		response.Error(w, http.StatusNotFound, "the signing key has no certificate chain")
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-pem-file")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(chain)
+}
+
+// writeJOSE writes a JOSE object as is, outside the response envelope, so
+// standard JOSE libraries can read it.
+func writeJOSE(w http.ResponseWriter, contentType string, v interface{}) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"life-certificates/internal/export"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// StatsHandler serves the statistics behind the ops dashboard.
+type StatsHandler struct {
+	service *service.StatsService
+}
+
+// NewStatsHandler wires dependencies for statistics endpoints.
+func NewStatsHandler(service *service.StatsService) *StatsHandler {
+	return &StatsHandler{service: service}
+}
+
+// Overview godoc
+// @Summary Verification statistics overview
+// @Description Attempts verified in the range by status, their pass rate and average similarity, the current review backlog and the completion of campaigns overlapping the range. The range defaults to the last 30 days.
+// @Tags Stats
+// @Security BasicAuth
+// @Produce json
+// @Param from query string false "Verified on or after date (YYYY-MM-DD)"
+// @Param to query string false "Verified on or before date (YYYY-MM-DD), default today"
+// @Param province query string false "Province of the participants' members"
+// @Param fund query string false "Participants' pension fund"
+// @Success 200 {object} service.StatsOverview
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /stats/overview [get]
+func (h *StatsHandler) Overview(w http.ResponseWriter, r *http.Request) {
+	out, err := h.service.Overview(r.Context(), statsInput(r))
+	if err != nil {
+		writeStatsError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, out)
+}
+
+// Timeseries godoc
+// @Summary Verification statistics over time
+// @Description Attempts verified per day, week or month of the range by status, with their pass rate and average similarity; intervals without attempts are included. The range defaults to the last 30 days.
+// @Tags Stats
+// @Security BasicAuth
+// @Produce json
+// @Param from query string false "Verified on or after date (YYYY-MM-DD)"
+// @Param to query string false "Verified on or before date (YYYY-MM-DD), default today"
+// @Param province query string false "Province of the participants' members"
+// @Param fund query string false "Participants' pension fund"
+// @Param interval query string false "day (default), week or month"
+// @Success 200 {object} service.StatsTimeseries
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /stats/timeseries [get]
+func (h *StatsHandler) Timeseries(w http.ResponseWriter, r *http.Request) {
+	out, err := h.service.Timeseries(r.Context(), statsInput(r))
+	if err != nil {
+		writeStatsError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, out)
+}
+
+// ScoreDistribution godoc
+// @Summary Similarity or distance distribution
+// @Description Histogram of the similarity or distance of automatic attempts verified in the range, by status, with the score's threshold in the verification settings. Buckets between the lowest and highest score are included even when empty. The range defaults to the last 30 days.
+// @Tags Stats
+// @Security BasicAuth
+// @Produce json
+// @Param from query string false "Verified on or after date (YYYY-MM-DD)"
+// @Param to query string false "Verified on or before date (YYYY-MM-DD), default today"
+// @Param province query string false "Province of the participants' members"
+// @Param fund query string false "Participants' pension fund"
+// @Param score query string false "similarity (default) or distance"
+// @Param width query number false "Bucket width, default 5 for similarity and 0.05 for distance"
+// @Success 200 {object} service.ScoreDistribution
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /stats/score-distribution [get]
+func (h *StatsHandler) ScoreDistribution(w http.ResponseWriter, r *http.Request) {
+	out, err := h.service.ScoreDistribution(r.Context(), service.ScoreDistributionInput{
+		StatsInput: statsInput(r),
+		Score:      r.URL.Query().Get("score"),
+		Width:      r.URL.Query().Get("width"),
+	})
+	if err != nil {
+		writeStatsError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, out)
+}
+
+// SimulateThresholds godoc
+// @Summary Simulate verification thresholds
+// @Description Replays the automatic attempts of the range decided by the decision rules with their distance and similarity rules held to other thresholds, and compares the recorded outcomes with the simulated ones. Omitted thresholds are those in the verification settings. Nothing is changed.
+// @Tags Stats
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param payload body service.ThresholdSimulationInput true "Thresholds to simulate and attempts to replay"
+// @Success 200 {object} service.ThresholdSimulation
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /stats/threshold-simulation [post]
+func (h *StatsHandler) SimulateThresholds(w http.ResponseWriter, r *http.Request) {
+	var req service.ThresholdSimulationInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	out, err := h.service.SimulateThresholds(r.Context(), req)
+	if err != nil {
+		writeStatsError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, out)
+}
+
+// VerificationReport godoc
+// @Summary Verification report by region or fund
+// @Description Participants verified, failed and not attempted in a year, quarter or month, with completion and failure rates and attempt counts, grouped by the province or city of the participants' members or by their fund. Participants registered after the period and those whose member is deceased are left out. Returned as JSON or as a CSV download for regulator submissions.
+// @Tags Stats
+// @Security BasicAuth
+// @Produce json
+// @Produce text/csv
+// @Param group_by query string true "province, city or fund"
+// @Param period query string true "Year (2024), quarter (2024-Q3) or month (2024-07)"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {object} service.VerificationReport
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /reports/verification [get]
+func (h *StatsHandler) VerificationReport(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	format := strings.ToLower(strings.TrimSpace(query.Get("format")))
+	if format != "" && format != "json" && format != export.FormatCSV {
+		response.Error(w, http.StatusBadRequest, "unsupported report format, use json or csv")
+		return
+	}
+
+	report, err := h.service.VerificationReport(r.Context(), service.VerificationReportInput{
+		GroupBy: query.Get("group_by"),
+		Period:  query.Get("period"),
+	})
+	if err != nil {
+		writeStatsError(w, err)
+		return
+	}
+	if format != export.FormatCSV {
+		response.Success(w, http.StatusOK, report)
+		return
+	}
+
+	w.Header().Set("Content-Type", export.ContentType(export.FormatCSV))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q",
+		fmt.Sprintf("verification-report-%s-%s.csv", report.GroupBy, report.Period)))
+	w.WriteHeader(http.StatusOK)
+	out, _ := export.New(export.FormatCSV, w)
+	_ = out.Write([]string{
+		report.GroupBy, "participants", "verified", "failed", "not_attempted", "completion_rate", "failure_rate",
+		"attempts", "valid", "invalid", "review",
+	})
+	for _, row := range append(report.Rows, report.Total) {
+		_ = out.Write([]string{
+			row.Group, strconv.FormatInt(row.Participants, 10), strconv.FormatInt(row.Verified, 10),
+			strconv.FormatInt(row.Failed, 10), strconv.FormatInt(row.NotAttempted, 10),
+			strconv.FormatFloat(row.CompletionRate, 'f', 2, 64), strconv.FormatFloat(row.FailureRate, 'f', 2, 64),
+			strconv.FormatInt(row.Attempts, 10), strconv.FormatInt(row.Valid, 10),
+			strconv.FormatInt(row.Invalid, 10), strconv.FormatInt(row.Review, 10),
+		})
+	}
+	_ = out.Close()
+}
+
+func statsInput(r *http.Request) service.StatsInput {
+	query := r.URL.Query()
+	return service.StatsInput{
+		From:     query.Get("from"),
+		To:       query.Get("to"),
+		Province: query.Get("province"),
+		Fund:     query.Get("fund"),
+		Interval: query.Get("interval"),
+	}
+}
+
+func writeStatsError(w http.ResponseWriter, err error) {
+	var verr *service.ValidationError
+	if errors.As(err, &verr) {
+		response.ValidationError(w, "validation failed", verr.Fields)
+		return
+	}
+	response.Error(w, http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// StatusOverrideHandler exposes dual-control certificate status overrides.
+type StatusOverrideHandler struct {
+	service *service.StatusOverrideService
+}
+
+// NewStatusOverrideHandler wires dependencies for status override endpoints.
+func NewStatusOverrideHandler(service *service.StatusOverrideService) *StatusOverrideHandler {
+	return &StatusOverrideHandler{service: service}
+}
+
+// Propose godoc
+// @Summary Propose a certificate status override
+// @Description Records a pending status change that only takes effect once a different admin approves it
+// @Tags StatusOverride
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param certificate_id path string true "Life certificate ID"
+// @Param payload body service.ProposeOverrideInput true "Target status and justification"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /life-certificate/{certificate_id}/override [post]
+func (h *StatusOverrideHandler) Propose(w http.ResponseWriter, r *http.Request) {
+	var req service.ProposeOverrideInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	override, err := h.service.Propose(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "certificate_id"), req)
+	if err != nil {
+		writeOverrideError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusAccepted, override)
+}
+
+// ListByCertificate godoc
+// @Summary List status overrides of a certificate
+// @Tags StatusOverride
+// @Security BasicAuth
+// @Produce json
+// @Param certificate_id path string true "Life certificate ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /life-certificate/{certificate_id}/overrides [get]
+func (h *StatusOverrideHandler) ListByCertificate(w http.ResponseWriter, r *http.Request) {
+	overrides, err := h.service.ListByCertificate(r.Context(), chi.URLParam(r, "certificate_id"))
+	if err != nil {
+		writeOverrideError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, overrides)
+}
+
+// List godoc
+// @Summary List status overrides
+// @Description Oldest first; use state=PENDING for the approval inbox
+// @Tags StatusOverride
+// @Security BasicAuth
+// @Produce json
+// @Param state query string false "PENDING, APPROVED or REJECTED"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/overrides [get]
+func (h *StatusOverrideHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, pageSize, err := pageParams(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	out, err := h.service.List(r.Context(), r.URL.Query().Get("state"), page, pageSize)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, out)
+}
+
+// Approve godoc
+// @Summary Approve a status override
+// @Description Applies the proposed status; the approver must differ from the proposer
+// @Tags StatusOverride
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param override_id path string true "Override ID"
+// @Param payload body service.DecideOverrideInput false "Approval notes"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/overrides/{override_id}/approve [post]
+func (h *StatusOverrideHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	var req service.DecideOverrideInput
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+			return
+		}
+	}
+
+	override, err := h.service.Approve(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "override_id"), req)
+	if err != nil {
+		writeOverrideError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, override)
+}
+
+// Reject godoc
+// @Summary Reject a status override
+// @Description Closes the proposal without changing the certificate; notes are required
+// @Tags StatusOverride
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param override_id path string true "Override ID"
+// @Param payload body service.DecideOverrideInput true "Rejection notes"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/overrides/{override_id}/reject [post]
+func (h *StatusOverrideHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	var req service.DecideOverrideInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	override, err := h.service.Reject(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "override_id"), req)
+	if err != nil {
+		writeOverrideError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, override)
+}
+
+func writeOverrideError(w http.ResponseWriter, err error) {
+	switch err {
+	case service.ErrCertificateNotFound, service.ErrOverrideNotFound:
+		response.Error(w, http.StatusNotFound, err.Error())
+	case service.ErrOverrideSelfApproval:
+		response.Error(w, http.StatusForbidden, err.Error())
+	case service.ErrOverridePending, service.ErrOverrideNotPending, service.ErrOverrideStale:
+		response.Error(w, http.StatusConflict, err.Error())
+	default:
+		response.Error(w, http.StatusBadRequest, err.Error())
+	}
+}
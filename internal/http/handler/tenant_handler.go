@@ -0,0 +1,261 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// TenantHandler manages the pension funds served by the deployment.
+type TenantHandler struct {
+	service *service.TenantService
+}
+
+// NewTenantHandler wires dependencies for tenant endpoints.
+func NewTenantHandler(service *service.TenantService) *TenantHandler {
+	return &TenantHandler{service: service}
+}
+
+// List godoc
+// @Summary List tenants
+// @Description Disabled tenants included; FR Core API keys are never returned (admin accounts not bound to a tenant only)
+// @Tags Admin
+// @Security BasicAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/tenants [get]
+func (h *TenantHandler) List(w http.ResponseWriter, r *http.Request) {
+	tenants, err := h.service.List(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, tenants)
+}
+
+// Create godoc
+// @Summary Create a tenant
+// @Description The ID is used in the X-Tenant-ID header and as subdomain; empty FR Core settings and thresholds fall back to the configured ones (admin accounts not bound to a tenant only)
+// @Tags Admin
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param payload body service.CreateTenantInput true "Tenant"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/tenants [post]
+func (h *TenantHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req service.CreateTenantInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	tenant, err := h.service.Create(r.Context(), middleware.Actor(r.Context()), req)
+	if err != nil {
+		writeTenantError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, tenant)
+}
+
+// Get godoc
+// @Summary Get a tenant
+// @Tags Admin
+// @Security BasicAuth
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/tenants/{tenant_id} [get]
+func (h *TenantHandler) Get(w http.ResponseWriter, r *http.Request) {
+	tenant, err := h.service.Get(r.Context(), chi.URLParam(r, "tenant_id"))
+	if err != nil {
+		writeTenantError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, tenant)
+}
+
+// Update godoc
+// @Summary Update a tenant
+// @Description A zero threshold falls back to the verification settings; disabling refuses the tenant's requests and keeps its data. The default tenant cannot be disabled (admin accounts not bound to a tenant only)
+// @Tags Admin
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param payload body service.UpdateTenantInput true "Fields to change"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/tenants/{tenant_id} [patch]
+func (h *TenantHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var req service.UpdateTenantInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	tenant, err := h.service.Update(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "tenant_id"), req)
+	if err != nil {
+		writeTenantError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, tenant)
+}
+
+// UpdateBranding godoc
+// @Summary Update a tenant's branding
+// @Description The display name signs notifications and issues PDF certificates, the footer text closes both; empty senders fall back to the configured email, SMS and WhatsApp senders. PDF certificates already generated keep their branding (admin accounts not bound to a tenant only)
+// @Tags Admin
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param payload body service.TenantBrandingInput true "Fields to change"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/tenants/{tenant_id}/branding [put]
+func (h *TenantHandler) UpdateBranding(w http.ResponseWriter, r *http.Request) {
+	var req service.TenantBrandingInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	tenant, err := h.service.UpdateBranding(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "tenant_id"), req)
+	if err != nil {
+		writeTenantError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, tenant)
+}
+
+// UploadLogo godoc
+// @Summary Upload a tenant's logo
+// @Description A PNG or JPEG of at most 512 KiB, drawn at the top of the tenant's PDF certificates; replaces any earlier logo (admin accounts not bound to a tenant only)
+// @Tags Admin
+// @Security BasicAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Param logo formData file true "PNG or JPEG logo"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/tenants/{tenant_id}/branding/logo [put]
+func (h *TenantHandler) UploadLogo(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseMultipartForm(1 << 20); err != nil {
+		response.Error(w, http.StatusBadRequest, "failed to parse multipart form")
+		return
+	}
+
+	file, _, err := r.FormFile("logo")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "logo file is required")
+		return
+	}
+	defer file.Close()
+
+	logo, err := io.ReadAll(file)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "failed to read logo")
+		return
+	}
+
+	tenant, err := h.service.SetLogo(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "tenant_id"), logo)
+	if err != nil {
+		writeTenantError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, tenant)
+}
+
+// Logo godoc
+// @Summary Download a tenant's logo
+// @Tags Admin
+// @Security BasicAuth
+// @Produce png,jpeg
+// @Param tenant_id path string true "Tenant ID"
+// @Success 200 {file} file
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/tenants/{tenant_id}/branding/logo [get]
+func (h *TenantHandler) Logo(w http.ResponseWriter, r *http.Request) {
+	logo, contentType, err := h.service.Logo(r.Context(), chi.URLParam(r, "tenant_id"))
+	if err != nil {
+		writeTenantError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(logo)
+}
+
+// DeleteLogo godoc
+// @Summary Remove a tenant's logo
+// @Description PDF certificates generated afterwards have no logo (admin accounts not bound to a tenant only)
+// @Tags Admin
+// @Security BasicAuth
+// @Produce json
+// @Param tenant_id path string true "Tenant ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/tenants/{tenant_id}/branding/logo [delete]
+func (h *TenantHandler) DeleteLogo(w http.ResponseWriter, r *http.Request) {
+	tenant, err := h.service.DeleteLogo(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "tenant_id"))
+	if err != nil {
+		writeTenantError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, tenant)
+}
+
+func writeTenantError(w http.ResponseWriter, err error) {
+	var verr *service.ValidationError
+	if errors.As(err, &verr) {
+		response.ValidationError(w, "validation failed", verr.Fields)
+		return
+	}
+	switch err {
+	case service.ErrTenantNotFound, service.ErrTenantLogoNotFound:
+		response.Error(w, http.StatusNotFound, err.Error())
+	case service.ErrTenantExists:
+		response.Error(w, http.StatusConflict, err.Error())
+	default:
+		response.Error(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// UploadScanHandler exposes the malware scan results of uploads.
+type UploadScanHandler struct {
+	service *service.UploadScanService
+}
+
+// NewUploadScanHandler wires dependencies for upload scan endpoints.
+func NewUploadScanHandler(service *service.UploadScanService) *UploadScanHandler {
+	return &UploadScanHandler{service: service}
+}
+
+// List godoc
+// @Summary List upload malware scans
+// @Description Scan results of uploaded documents, newest first; infected ones name the signature and quarantine blob (admin only)
+// @Tags Admin
+// @Security BasicAuth
+// @Produce json
+// @Param result query string false "CLEAN or INFECTED"
+// @Param from query string false "Scanned on or after date (YYYY-MM-DD)"
+// @Param to query string false "Scanned on or before date (YYYY-MM-DD)"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/upload-scans [get]
+func (h *UploadScanHandler) List(w http.ResponseWriter, r *http.Request) {
+	page, pageSize, err := pageParams(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	query := r.URL.Query()
+	out, err := h.service.List(r.Context(), service.UploadScanQueryInput{
+		Result:   query.Get("result"),
+		From:     query.Get("from"),
+		To:       query.Get("to"),
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, out)
+}
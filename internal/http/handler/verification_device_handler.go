@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// VerificationDeviceHandler exposes the devices participants verify from and their trust.
+type VerificationDeviceHandler struct {
+	service *service.VerificationDeviceService
+}
+
+// NewVerificationDeviceHandler wires dependencies for verification device endpoints.
+func NewVerificationDeviceHandler(service *service.VerificationDeviceService) *VerificationDeviceHandler {
+	return &VerificationDeviceHandler{service: service}
+}
+
+// List godoc
+// @Summary List a participant's verification devices
+// @Description Returns the devices the participant submitted automatic attempts from, with their fingerprint and trust, most recently used first
+// @Tags Participant
+// @Security BasicAuth
+// @Produce json
+// @Param participant_id path string true "Participant ID"
+// @Success 200 {array} domain.VerificationDevice
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /participants/{participant_id}/verification-devices [get]
+func (h *VerificationDeviceHandler) List(w http.ResponseWriter, r *http.Request) {
+	devices, err := h.service.List(r.Context(), chi.URLParam(r, "participant_id"))
+	if err != nil {
+		writeVerificationDeviceError(w, err)
+		return
+	}
+	if devices == nil {
+		devices = []domain.VerificationDevice{}
+	}
+	response.Success(w, http.StatusOK, devices)
+}
+
+// Trust godoc
+// @Summary Trust a participant's verification device
+// @Description Adds the device to the participant's trusted devices (admin only)
+// @Tags Participant
+// @Security BasicAuth
+// @Produce json
+// @Param participant_id path string true "Participant ID"
+// @Param device_id path string true "Device ID"
+// @Success 200 {object} domain.VerificationDevice
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /participants/{participant_id}/verification-devices/{device_id}/trust [put]
+func (h *VerificationDeviceHandler) Trust(w http.ResponseWriter, r *http.Request) {
+	device, err := h.service.Trust(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "participant_id"), chi.URLParam(r, "device_id"))
+	if err != nil {
+		writeVerificationDeviceError(w, err)
+		return
+	}
+	response.Success(w, http.StatusOK, device)
+}
+
+// Untrust godoc
+// @Summary Withdraw trust from a participant's verification device
+// @Description Removes the device from the participant's trusted devices, so its attempts are held to the rules for known devices (admin only)
+// @Tags Participant
+// @Security BasicAuth
+// @Produce json
+// @Param participant_id path string true "Participant ID"
+// @Param device_id path string true "Device ID"
+// @Success 200 {object} domain.VerificationDevice
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /participants/{participant_id}/verification-devices/{device_id}/trust [delete]
+func (h *VerificationDeviceHandler) Untrust(w http.ResponseWriter, r *http.Request) {
+	device, err := h.service.Untrust(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "participant_id"), chi.URLParam(r, "device_id"))
+	if err != nil {
+		writeVerificationDeviceError(w, err)
+		return
+	}
+	response.Success(w, http.StatusOK, device)
+}
+
+func writeVerificationDeviceError(w http.ResponseWriter, err error) {
+	switch err {
+	case service.ErrParticipantNotFound, service.ErrVerificationDeviceNotFound:
+		response.Error(w, http.StatusNotFound, err.Error())
+	default:
+		response.Error(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// VerificationLockHandler lifts locks placed after repeated failed attempts.
+type VerificationLockHandler struct {
+	service *service.VerificationLockService
+}
+
+// NewVerificationLockHandler wires dependencies for verification lock endpoints.
+func NewVerificationLockHandler(service *service.VerificationLockService) *VerificationLockHandler {
+	return &VerificationLockHandler{service: service}
+}
+
+// Unlock godoc
+// @Summary Unlock a participant's automatic verification
+// @Description Lifts the lock placed after repeated INVALID attempts and starts the failure count over (admin only)
+// @Tags Participant
+// @Security BasicAuth
+// @Produce json
+// @Param participant_id path string true "Participant ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /participants/{participant_id}/verification-lock [delete]
+func (h *VerificationLockHandler) Unlock(w http.ResponseWriter, r *http.Request) {
+	status, err := h.service.Unlock(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "participant_id"))
+	if err != nil {
+		switch err {
+		case service.ErrParticipantNotFound:
+			response.Error(w, http.StatusNotFound, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, status)
+}
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// VerificationProfileHandler manages per-fund and per-participant verification profiles.
+type VerificationProfileHandler struct {
+	service *service.VerificationProfileService
+}
+
+// NewVerificationProfileHandler wires dependencies for verification profile endpoints.
+func NewVerificationProfileHandler(service *service.VerificationProfileService) *VerificationProfileHandler {
+	return &VerificationProfileHandler{service: service}
+}
+
+type assignProfileRequest struct {
+	ProfileID *string `json:"profile_id"`
+}
+
+// Create godoc
+// @Summary Create a verification profile
+// @Description Thresholds, liveness policy (REQUIRED, REVIEW or SKIP) and daily attempt limit; a fund makes it the default for that fund (admin only)
+// @Tags Admin
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param payload body service.VerificationProfileInput true "Profile"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/verification-profiles [post]
+func (h *VerificationProfileHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req service.VerificationProfileInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	profile, err := h.service.Create(r.Context(), middleware.Actor(r.Context()), req)
+	if err != nil {
+		writeVerificationProfileError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, profile)
+}
+
+// List godoc
+// @Summary List verification profiles
+// @Tags Admin
+// @Security BasicAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/verification-profiles [get]
+func (h *VerificationProfileHandler) List(w http.ResponseWriter, r *http.Request) {
+	profiles, err := h.service.List(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, profiles)
+}
+
+// Get godoc
+// @Summary Get a verification profile
+// @Tags Admin
+// @Security BasicAuth
+// @Produce json
+// @Param profile_id path string true "Profile ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/verification-profiles/{profile_id} [get]
+func (h *VerificationProfileHandler) Get(w http.ResponseWriter, r *http.Request) {
+	profile, err := h.service.Get(r.Context(), chi.URLParam(r, "profile_id"))
+	if err != nil {
+		writeVerificationProfileError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, profile)
+}
+
+// Update godoc
+// @Summary Update a verification profile
+// @Description Applies to attempts started after the change; an empty fund detaches the profile from its fund (admin only)
+// @Tags Admin
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param profile_id path string true "Profile ID"
+// @Param payload body service.UpdateVerificationProfileInput true "Fields to change"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/verification-profiles/{profile_id} [patch]
+func (h *VerificationProfileHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var req service.UpdateVerificationProfileInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	profile, err := h.service.Update(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "profile_id"), req)
+	if err != nil {
+		writeVerificationProfileError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, profile)
+}
+
+// Delete godoc
+// @Summary Delete a verification profile
+// @Description Participants assigned to it fall back to their fund's profile or the global settings (admin only)
+// @Tags Admin
+// @Security BasicAuth
+// @Produce json
+// @Param profile_id path string true "Profile ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/verification-profiles/{profile_id} [delete]
+func (h *VerificationProfileHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.Delete(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "profile_id")); err != nil {
+		writeVerificationProfileError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"message": "verification profile deleted"})
+}
+
+// Assign godoc
+// @Summary Assign a verification profile to a participant
+// @Description A null profile_id clears the assignment so the fund's profile or the global settings apply (admin only)
+// @Tags Participant
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param participant_id path string true "Participant ID"
+// @Param payload body assignProfileRequest true "Profile to assign"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /participants/{participant_id}/verification-profile [put]
+func (h *VerificationProfileHandler) Assign(w http.ResponseWriter, r *http.Request) {
+	var req assignProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	participant, err := h.service.AssignParticipant(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "participant_id"), req.ProfileID)
+	if err != nil {
+		writeVerificationProfileError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, participant)
+}
+
+func writeVerificationProfileError(w http.ResponseWriter, err error) {
+	var verr *service.ValidationError
+	if errors.As(err, &verr) {
+		response.ValidationError(w, "validation failed", verr.Fields)
+		return
+	}
+	switch err {
+	case service.ErrVerificationProfileNotFound, service.ErrParticipantNotFound:
+		response.Error(w, http.StatusNotFound, err.Error())
+	case service.ErrVerificationProfileConflict:
+		response.Error(w, http.StatusConflict, err.Error())
+	default:
+		response.Error(w, http.StatusInternalServerError, err.Error())
+	}
+}
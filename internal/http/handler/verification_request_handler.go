@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// VerificationRequestHandler exposes asynchronous verification.
+type VerificationRequestHandler struct {
+	service *service.AsyncVerificationService
+}
+
+// NewVerificationRequestHandler wires dependencies for asynchronous verification endpoints.
+func NewVerificationRequestHandler(service *service.AsyncVerificationService) *VerificationRequestHandler {
+	return &VerificationRequestHandler{service: service}
+}
+
+// Submit godoc
+// @Summary Submit life certificate verification asynchronously
+// @Description Stores the selfie and verifies it in the background. Poll the returned verification or subscribe a webhook to verification.request_completed. Participants who cannot verify are refused straight away, as by the synchronous endpoint
+// @Tags LifeCertificate
+// @Security BasicAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param participant_id formData string false "Participant ID; optional with a session token"
+// @Param image formData file true "Selfie image"
+// @Param location formData string false "Kiosk or office where the selfie was captured"
+// @Param latitude formData number false "Declared latitude, compared with the selfie's GPS position"
+// @Param longitude formData number false "Declared longitude, compared with the selfie's GPS position"
+// @Param X-Device-ID header string false "Stable identifier of the capturing device, used for fraud scoring"
+// @Param X-Device-Model header string false "Model of the capturing device, recorded in its fingerprint"
+// @Param X-Device-OS header string false "Operating system of the capturing device, recorded in its fingerprint"
+// @Param session_token formData string false "Token of a verification session, also accepted in the X-Verification-Session header; required when VERIFICATION_SESSION_REQUIRED is on"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Failure 423 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /life-certificate/verify-async [post]
+func (h *VerificationRequestHandler) Submit(w http.ResponseWriter, r *http.Request) {
+	input, ok := readVerifyForm(w, r)
+	if !ok {
+		return
+	}
+
+	request, err := h.service.Submit(r.Context(), middleware.Actor(r.Context()), sessionToken(r), input)
+	if err != nil {
+		writeVerifyError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/life-certificate/verifications/"+request.ID)
+	response.Success(w, http.StatusAccepted, request)
+}
+
+// Get godoc
+// @Summary Get an asynchronous verification
+// @Description QUEUED and PROCESSING requests are still running; COMPLETED ones carry the certificate and its verification_status, FAILED ones the error_code and error
+// @Tags LifeCertificate
+// @Security BasicAuth
+// @Produce json
+// @Param verification_id path string true "Verification ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /life-certificate/verifications/{verification_id} [get]
+func (h *VerificationRequestHandler) Get(w http.ResponseWriter, r *http.Request) {
+	request, err := h.service.Get(r.Context(), chi.URLParam(r, "verification_id"))
+	if err != nil {
+		switch err {
+		case service.ErrVerificationRequestNotFound:
+			response.Error(w, http.StatusNotFound, err.Error())
+		default:
+			response.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(w, http.StatusOK, request)
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// VerificationSettingsHandler exposes the live verification settings.
+type VerificationSettingsHandler struct {
+	service *service.VerificationSettingsService
+}
+
+// NewVerificationSettingsHandler wires dependencies for verification settings endpoints.
+func NewVerificationSettingsHandler(service *service.VerificationSettingsService) *VerificationSettingsHandler {
+	return &VerificationSettingsHandler{service: service}
+}
+
+// Get godoc
+// @Summary Current verification settings
+// @Description Thresholds and liveness toggle used by new verification attempts (admin only)
+// @Tags Admin
+// @Security BasicAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/config/verification [get]
+func (h *VerificationSettingsHandler) Get(w http.ResponseWriter, _ *http.Request) {
+	response.Success(w, http.StatusOK, h.service.Current())
+}
+
+// Update godoc
+// @Summary Change verification settings without a restart
+// @Description Applies to attempts started after the change; the before and after values are audit-logged (admin only)
+// @Tags Admin
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param payload body service.UpdateVerificationSettingsInput true "Fields to change"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/config/verification [put]
+func (h *VerificationSettingsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var req service.UpdateVerificationSettingsInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	settings, err := h.service.Update(r.Context(), middleware.Actor(r.Context()), req)
+	if err != nil {
+		var verr *service.ValidationError
+		if errors.As(err, &verr) {
+			response.ValidationError(w, "validation failed", verr.Fields)
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, settings)
+}
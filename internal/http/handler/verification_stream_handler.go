@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// streamHeartbeat keeps idle connections open through proxies.
+const streamHeartbeat = 15 * time.Second
+
+// VerificationStreamHandler pushes live verification outcomes over server-sent events.
+type VerificationStreamHandler struct {
+	service *service.VerificationStreamService
+}
+
+// NewVerificationStreamHandler wires dependencies for the verification stream.
+func NewVerificationStreamHandler(service *service.VerificationStreamService) *VerificationStreamHandler {
+	return &VerificationStreamHandler{service: service}
+}
+
+// Stream godoc
+// @Summary Stream live verification results
+// @Description Server-sent events for new verification outcomes (verification.completed and verification.review_required), sent as they are published. Each message carries the event ID, type and JSON data.
+// @Tags LifeCertificate
+// @Security BasicAuth
+// @Produce text/event-stream
+// @Param status query string false "Only VALID, INVALID or REVIEW outcomes"
+// @Param location query string false "Only outcomes captured at this location"
+// @Success 200 {string} string "event stream"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /life-certificate/stream [get]
+func (h *VerificationStreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		response.Error(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+
+	query := r.URL.Query()
+	stream, err := h.service.Subscribe(r.Context(), service.StreamFilter{
+		Status:   query.Get("status"),
+		Location: query.Get("location"),
+	})
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// The stream outlives the server's write timeout; it ends when the client disconnects.
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case event, ok := <-stream:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event.Data)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"net/http"
+	"runtime"
+
+	"life-certificates/internal/buildinfo"
+	"life-certificates/internal/http/response"
+)
+
+// VersionHandler exposes build information and runtime diagnostics.
+type VersionHandler struct {
+	info     buildinfo.Info
+	settings map[string]interface{}
+}
+
+// NewVersionHandler wires the build information and redacted configuration summary.
+func NewVersionHandler(info buildinfo.Info, settings map[string]interface{}) *VersionHandler {
+	return &VersionHandler{info: info, settings: settings}
+}
+
+// Version godoc
+// @Summary Build and runtime information
+// @Description Returns the git commit, build time, Go version, runtime statistics and the active configuration with secrets redacted
+// @Tags Diagnostics
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Security BasicAuth
+// @Router /version [get]
+func (h *VersionHandler) Version(w http.ResponseWriter, _ *http.Request) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	response.Success(w, http.StatusOK, map[string]interface{}{
+		"build": h.info,
+		"runtime": map[string]interface{}{
+			"goroutines":       runtime.NumGoroutine(),
+			"gomaxprocs":       runtime.GOMAXPROCS(0),
+			"num_cpu":          runtime.NumCPU(),
+			"heap_alloc_bytes": mem.HeapAlloc,
+			"sys_bytes":        mem.Sys,
+			"num_gc":           mem.NumGC,
+		},
+		"config": h.settings,
+	})
+}
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"life-certificates/internal/http/middleware"
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/service"
+)
+
+// WebhookHandler exposes webhook subscription management and the delivery log.
+type WebhookHandler struct {
+	service *service.WebhookService
+}
+
+// NewWebhookHandler wires dependencies for webhook endpoints.
+func NewWebhookHandler(service *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+// Create godoc
+// @Summary Register a webhook subscription
+// @Description Event types: verification.completed, verification.review_required, participant.registered. The signing secret is returned only in this response; it is generated when omitted.
+// @Tags Webhook
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param payload body service.CreateWebhookInput true "Subscription"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /webhooks [post]
+func (h *WebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req service.CreateWebhookInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	sub, err := h.service.Create(r.Context(), middleware.Actor(r.Context()), req)
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusCreated, sub)
+}
+
+// List godoc
+// @Summary List webhook subscriptions
+// @Tags Webhook
+// @Security BasicAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /webhooks [get]
+func (h *WebhookHandler) List(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.service.List(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, subs)
+}
+
+// Get godoc
+// @Summary Get a webhook subscription
+// @Tags Webhook
+// @Security BasicAuth
+// @Produce json
+// @Param webhook_id path string true "Webhook ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /webhooks/{webhook_id} [get]
+func (h *WebhookHandler) Get(w http.ResponseWriter, r *http.Request) {
+	sub, err := h.service.Get(r.Context(), chi.URLParam(r, "webhook_id"))
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, sub)
+}
+
+// Update godoc
+// @Summary Update a webhook subscription
+// @Description Change the URL, event types or pause the subscription with active=false
+// @Tags Webhook
+// @Security BasicAuth
+// @Accept json
+// @Produce json
+// @Param webhook_id path string true "Webhook ID"
+// @Param payload body service.UpdateWebhookInput true "Fields to change"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /webhooks/{webhook_id} [patch]
+func (h *WebhookHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var req service.UpdateWebhookInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid JSON payload")
+		return
+	}
+
+	sub, err := h.service.Update(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "webhook_id"), req)
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, sub)
+}
+
+// Delete godoc
+// @Summary Delete a webhook subscription
+// @Tags Webhook
+// @Security BasicAuth
+// @Produce json
+// @Param webhook_id path string true "Webhook ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /webhooks/{webhook_id} [delete]
+func (h *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.Delete(r.Context(), middleware.Actor(r.Context()), chi.URLParam(r, "webhook_id")); err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, map[string]string{"message": "webhook deleted"})
+}
+
+// Deliveries godoc
+// @Summary Webhook delivery log
+// @Description Newest first, with attempt count, last response code and error
+// @Tags Webhook
+// @Security BasicAuth
+// @Produce json
+// @Param webhook_id path string true "Webhook ID"
+// @Param status query string false "PENDING, SUCCEEDED or FAILED"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size (default 20, max 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /webhooks/{webhook_id}/deliveries [get]
+func (h *WebhookHandler) Deliveries(w http.ResponseWriter, r *http.Request) {
+	page, pageSize, err := pageParams(r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	out, err := h.service.Deliveries(r.Context(), chi.URLParam(r, "webhook_id"), r.URL.Query().Get("status"), page, pageSize)
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	response.Success(w, http.StatusOK, out)
+}
+
+func writeWebhookError(w http.ResponseWriter, err error) {
+	var verr *service.ValidationError
+	if errors.As(err, &verr) {
+		response.ValidationError(w, "validation failed", verr.Fields)
+		return
+	}
+	switch err {
+	case service.ErrWebhookNotFound:
+		response.Error(w, http.StatusNotFound, err.Error())
+	default:
+		response.Error(w, http.StatusBadRequest, err.Error())
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// AccessRecorder stores reads of personal data.
+type AccessRecorder interface {
+	RecordAccess(ctx context.Context, actor, resourceType, resourceID, path, remoteIP string) error
+}
+
+// AccessLog records the authenticated actor reading the resource named by the
+// URL parameter. Only successful responses are recorded, since failed reads
+// exposed no data.
+func AccessLog(recorder AccessRecorder, resourceType, param string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if status >= http.StatusMultipleChoices {
+				return
+			}
+			// The response is already sent, so record even if the client went away.
+			ctx := context.WithoutCancel(r.Context())
+			if err := recorder.RecordAccess(ctx, Actor(r.Context()), resourceType, chi.URLParam(r, param), r.URL.Path, r.RemoteAddr); err != nil {
+				log.Printf("record access to %s %s: %v", resourceType, chi.URLParam(r, param), err)
+			}
+		})
+	}
+}
@@ -1,42 +1,130 @@
 package middleware
 
 import (
+	"context"
+	"crypto/subtle"
 	"encoding/base64"
 	"fmt"
 	"net/http"
 	"strings"
+
+	"life-certificates/internal/config"
+	"life-certificates/internal/http/response"
+)
+
+// Roles recognised by RequireRole.
+const (
+	RoleAdmin    = "admin"
+	RoleOperator = "operator"
 )
 
-// BasicAuth protects endpoints using HTTP Basic authentication.
-func BasicAuth(username, password string) func(http.Handler) http.Handler {
+// Credential is an account accepted by BasicAuth.
+type Credential struct {
+	Username string
+	Password string
+	Role     string
+	// Tenant binds the account to one tenant; empty lets it pick any.
+	Tenant string
+}
+
+type actorContextKey struct{}
+
+type roleContextKey struct{}
+
+// BasicAuth protects endpoints using HTTP Basic authentication. The
+// authenticated username and role, and the tenant an account is bound to,
+// are stored on the request context.
+func BasicAuth(credentials []Credential) func(http.Handler) http.Handler {
 	realm := "Restricted"
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			auth := r.Header.Get("Authorization")
-			if !validateBasicAuth(auth, username, password) {
+			credential, ok := Authenticate(r.Header.Get("Authorization"), credentials)
+			if !ok {
 				w.Header().Set("WWW-Authenticate", fmt.Sprintf("Basic realm=\"%s\"", realm))
 				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 				return
 			}
-			next.ServeHTTP(w, r)
+			ctx := WithRole(WithActor(r.Context(), credential.Username), credential.Role)
+			if credential.Tenant != "" {
+				ctx = WithBoundTenant(ctx, credential.Tenant)
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
-func validateBasicAuth(header, username, password string) bool {
+// RequireRole rejects authenticated callers whose role is not in the allowed list.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role := Role(r.Context())
+			for _, allowed := range roles {
+				if role == allowed {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			response.Error(w, http.StatusForbidden, "insufficient role")
+		})
+	}
+}
+
+// WithActor returns a context carrying the authenticated actor name.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// Actor returns the authenticated actor name, or an empty string when unauthenticated.
+func Actor(ctx context.Context) string {
+	actor, _ := ctx.Value(actorContextKey{}).(string)
+	return actor
+}
+
+// WithRole returns a context carrying the authenticated caller's role.
+func WithRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, roleContextKey{}, role)
+}
+
+// Role returns the authenticated caller's role, or an empty string when unauthenticated.
+func Role(ctx context.Context) string {
+	role, _ := ctx.Value(roleContextKey{}).(string)
+	return role
+}
+
+// CredentialsFromConfig lists the configured accounts; the primary account is always an admin.
+func CredentialsFromConfig(cfg *config.Config) []Credential {
+	creds := []Credential{{
+		Username: cfg.Auth.Username,
+		Password: cfg.Auth.Password,
+		Role:     RoleAdmin,
+	}}
+	for _, user := range cfg.Auth.Users {
+		creds = append(creds, Credential{Username: user.Username, Password: user.Password, Role: user.Role, Tenant: user.Tenant})
+	}
+	return creds
+}
+
+// Authenticate checks a "Basic ..." authorization value against the accounts.
+func Authenticate(header string, credentials []Credential) (Credential, bool) {
 	if !strings.HasPrefix(header, "Basic ") {
-		return false
+		return Credential{}, false
 	}
 
 	payload, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(header, "Basic "))
 	if err != nil {
-		return false
+		return Credential{}, false
 	}
 
 	parts := strings.SplitN(string(payload), ":", 2)
 	if len(parts) != 2 {
-		return false
+		return Credential{}, false
 	}
 
-	return parts[0] == username && parts[1] == password
+	for _, credential := range credentials {
+		if parts[0] == credential.Username &&
+			subtle.ConstantTimeCompare([]byte(parts[1]), []byte(credential.Password)) == 1 {
+			return credential, true
+		}
+	}
+	return Credential{}, false
 }
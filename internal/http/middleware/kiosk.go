@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"life-certificates/internal/http/response"
+)
+
+// KioskKeyHeader carries a kiosk's device key.
+const KioskKeyHeader = "X-Kiosk-Key"
+
+// Kiosk is the branch device authenticated by a kiosk key.
+type Kiosk struct {
+	ID       string
+	Name     string
+	BranchID string
+}
+
+// KioskAuthenticator resolves a device key to its kiosk, returning nil for
+// unknown or revoked keys.
+type KioskAuthenticator func(ctx context.Context, key string) (*Kiosk, error)
+
+type kioskContextKey struct{}
+
+// KioskAuth requires the device key in the X-Kiosk-Key header on top of the
+// operator's own credentials, so kiosk endpoints only work on registered
+// devices and the operator stays the request's actor.
+func KioskAuth(authenticate KioskAuthenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(KioskKeyHeader)
+			if key == "" {
+				response.Error(w, http.StatusUnauthorized, "missing kiosk key")
+				return
+			}
+			kiosk, err := authenticate(r.Context(), key)
+			if err != nil {
+				log.Printf("authenticate kiosk key: %v", err)
+				response.Error(w, http.StatusInternalServerError, "could not check kiosk key")
+				return
+			}
+			if kiosk == nil {
+				response.Error(w, http.StatusUnauthorized, "invalid kiosk key")
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), kioskContextKey{}, kiosk)))
+		})
+	}
+}
+
+// KioskFromContext returns the authenticated kiosk, or nil outside kiosk endpoints.
+func KioskFromContext(ctx context.Context) *Kiosk {
+	kiosk, _ := ctx.Value(kioskContextKey{}).(*Kiosk)
+	return kiosk
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+
+	"life-certificates/internal/i18n"
+)
+
+// Language negotiates the response language from Accept-Language. The
+// language is recorded in the request context and sent as Content-Language,
+// which the response helpers translate error messages into.
+func Language(catalog *i18n.Catalog) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			language := catalog.Negotiate(r.Header.Get("Accept-Language"))
+			w.Header().Set("Content-Language", language)
+			w.Header().Add("Vary", "Accept-Language")
+			next.ServeHTTP(w, r.WithContext(i18n.WithLanguage(r.Context(), language)))
+		})
+	}
+}
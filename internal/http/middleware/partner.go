@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"life-certificates/internal/http/response"
+)
+
+// PartnerKeyHeader carries a partner API key.
+const PartnerKeyHeader = "X-API-Key"
+
+// Partner is the external institution authenticated by a partner API key.
+type Partner struct {
+	KeyID  string
+	Name   string
+	Scopes []string
+	// Fund restricts the partner to one fund's participants; empty allows every fund.
+	Fund string
+	// Tenant is the tenant that issued the key, the only one it can act for.
+	Tenant string
+}
+
+// HasScope reports whether the partner was granted scope.
+func (p *Partner) HasScope(scope string) bool {
+	for _, granted := range p.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// PartnerAuthenticator resolves an API key to its partner, returning nil for
+// unknown or revoked keys.
+type PartnerAuthenticator func(ctx context.Context, key string) (*Partner, error)
+
+type partnerContextKey struct{}
+
+// PartnerAuth protects the partner API with the key in the X-API-Key header.
+// Partners act as "partner:<name>" so the access log names them.
+func PartnerAuth(authenticate PartnerAuthenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(PartnerKeyHeader)
+			if key == "" {
+				response.Error(w, http.StatusUnauthorized, "missing API key")
+				return
+			}
+			partner, err := authenticate(r.Context(), key)
+			if err != nil {
+				log.Printf("authenticate partner key: %v", err)
+				response.Error(w, http.StatusInternalServerError, "could not check API key")
+				return
+			}
+			if partner == nil {
+				response.Error(w, http.StatusUnauthorized, "invalid API key")
+				return
+			}
+			ctx := context.WithValue(WithActor(r.Context(), "partner:"+partner.Name), partnerContextKey{}, partner)
+			ctx = WithBoundTenant(ctx, partner.Tenant)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireScope rejects partners whose key was not granted scope.
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			partner := PartnerFromContext(r.Context())
+			if partner == nil || !partner.HasScope(scope) {
+				response.Error(w, http.StatusForbidden, "API key lacks the "+scope+" scope")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// PartnerFromContext returns the authenticated partner, or nil outside the partner API.
+func PartnerFromContext(ctx context.Context) *Partner {
+	partner, _ := ctx.Value(partnerContextKey{}).(*Partner)
+	return partner
+}
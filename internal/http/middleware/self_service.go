@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"life-certificates/internal/http/response"
+)
+
+// SelfServiceAuthenticator returns the participant a self-service token was issued for.
+type SelfServiceAuthenticator func(token string) (participantID string, ok bool)
+
+type selfParticipantContextKey struct{}
+
+// SelfServiceAuth protects self-service endpoints with the bearer token a
+// pensioner got for a one-time code. The request acts as "self:<participant_id>".
+func SelfServiceAuth(authenticate SelfServiceAuthenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				response.Error(w, http.StatusUnauthorized, "missing bearer token")
+				return
+			}
+			participantID, ok := authenticate(strings.TrimSpace(token))
+			if !ok {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				response.Error(w, http.StatusUnauthorized, "invalid or expired token")
+				return
+			}
+			ctx := context.WithValue(WithActor(r.Context(), "self:"+participantID), selfParticipantContextKey{}, participantID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// SelfParticipant returns the participant authenticated by SelfServiceAuth, or "".
+func SelfParticipant(ctx context.Context) string {
+	participantID, _ := ctx.Value(selfParticipantContextKey{}).(string)
+	return participantID
+}
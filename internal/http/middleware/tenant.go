@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+
+	"life-certificates/internal/http/response"
+	"life-certificates/internal/tenant"
+)
+
+// TenantHeader names the tenant a request acts for.
+const TenantHeader = "X-Tenant-ID"
+
+// Tenant is a pension fund served by the deployment.
+type Tenant struct {
+	ID       string
+	Disabled bool
+}
+
+// TenantLookup resolves a tenant ID, returning nil for unknown tenants.
+type TenantLookup func(ctx context.Context, id string) (*Tenant, error)
+
+type boundTenantContextKey struct{}
+
+// Errors of SelectTenant for callers that cannot act for the tenant they name.
+var (
+	ErrTenantNotBound = errors.New("credentials are bound to another tenant")
+	ErrTenantUnknown  = errors.New("unknown tenant")
+	ErrTenantDisabled = errors.New("tenant is disabled")
+)
+
+// ResolveTenant picks the tenant a request acts for from the X-Tenant-ID
+// header, else the subdomain of baseDomain in the Host, and stores it on the
+// request context as SelectTenant does. Unknown tenants are refused with 404,
+// disabled ones and those a bound caller may not use with 403.
+func ResolveTenant(lookup TenantLookup, baseDomain string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested := r.Header.Get(TenantHeader)
+			if strings.TrimSpace(requested) == "" {
+				requested = tenantSubdomain(r.Host, baseDomain)
+			}
+			ctx, err := SelectTenant(r.Context(), lookup, requested)
+			switch {
+			case err == nil:
+				next.ServeHTTP(w, r.WithContext(ctx))
+			case errors.Is(err, ErrTenantUnknown):
+				response.Error(w, http.StatusNotFound, err.Error())
+			case errors.Is(err, ErrTenantNotBound), errors.Is(err, ErrTenantDisabled):
+				response.Error(w, http.StatusForbidden, err.Error())
+			default:
+				log.Printf("resolve tenant: %v", err)
+				response.Error(w, http.StatusInternalServerError, "could not check tenant")
+			}
+		})
+	}
+}
+
+// SelectTenant returns ctx acting for the requested tenant. Callers naming
+// none act for the tenant they are bound to, else the default tenant; bound
+// callers cannot name another one.
+func SelectTenant(ctx context.Context, lookup TenantLookup, requested string) (context.Context, error) {
+	requested = strings.ToLower(strings.TrimSpace(requested))
+	bound := BoundTenant(ctx)
+	switch {
+	case requested == "" && bound != "":
+		requested = bound
+	case requested == "":
+		requested = tenant.DefaultID
+	case bound != "" && requested != bound:
+		return nil, ErrTenantNotBound
+	}
+
+	t, err := lookup(ctx, requested)
+	if err != nil {
+		return nil, fmt.Errorf("look up tenant %s: %w", requested, err)
+	}
+	if t == nil {
+		return nil, ErrTenantUnknown
+	}
+	if t.Disabled {
+		return nil, ErrTenantDisabled
+	}
+	return tenant.WithID(ctx, t.ID), nil
+}
+
+// RequireUnboundTenant rejects callers bound to a single tenant, for
+// endpoints that manage the tenants themselves.
+func RequireUnboundTenant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if BoundTenant(r.Context()) != "" {
+			response.Error(w, http.StatusForbidden, "credentials are bound to a tenant")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// WithBoundTenant returns a context whose caller may only act for tenantID.
+func WithBoundTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, boundTenantContextKey{}, tenantID)
+}
+
+// BoundTenant returns the tenant the caller is bound to, or "" when it may pick any.
+func BoundTenant(ctx context.Context) string {
+	tenantID, _ := ctx.Value(boundTenantContextKey{}).(string)
+	return tenantID
+}
+
+// tenantSubdomain returns the label host has directly under baseDomain, or "".
+func tenantSubdomain(host, baseDomain string) string {
+	if baseDomain == "" {
+		return ""
+	}
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	label, ok := strings.CutSuffix(strings.ToLower(host), "."+strings.ToLower(baseDomain))
+	if !ok || label == "" || strings.Contains(label, ".") {
+		return ""
+	}
+	return label
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"life-certificates/internal/http/response"
+)
+
+// UnmaskRecorder stores requests that revealed unmasked personal data.
+type UnmaskRecorder interface {
+	RecordUnmask(ctx context.Context, actor, path, query, remoteIP string) error
+}
+
+type unmaskedContextKey struct{}
+
+// Unmask lets admins ask for personal data in full with ?unmasked=true on
+// endpoints that mask it otherwise. Other roles asking for it get 403, and
+// every successful unmasked response is recorded.
+func Unmask(recorder UnmaskRecorder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			raw := r.URL.Query().Get("unmasked")
+			if raw == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			unmasked, err := strconv.ParseBool(raw)
+			if err != nil {
+				response.Error(w, http.StatusBadRequest, "unmasked must be true or false")
+				return
+			}
+			if !unmasked {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if Role(r.Context()) != RoleAdmin {
+				response.Error(w, http.StatusForbidden, "unmasked data requires the admin role")
+				return
+			}
+
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			completed := false
+			// Deferred so that a streamed export aborted part way, which has
+			// already sent some of the data, is recorded as well.
+			defer func() {
+				status := ww.Status()
+				if status >= http.StatusMultipleChoices || (status == 0 && !completed) {
+					return
+				}
+				// The response is already sent, so record even if the client went away.
+				ctx := context.WithoutCancel(r.Context())
+				if err := recorder.RecordUnmask(ctx, Actor(r.Context()), r.URL.Path, r.URL.RawQuery, r.RemoteAddr); err != nil {
+					log.Printf("record unmasked read of %s: %v", r.URL.Path, err)
+				}
+			}()
+			next.ServeHTTP(ww, r.WithContext(context.WithValue(r.Context(), unmaskedContextKey{}, true)))
+			completed = true
+		})
+	}
+}
+
+// Unmasked reports whether the caller was allowed personal data in full.
+func Unmasked(ctx context.Context) bool {
+	unmasked, _ := ctx.Value(unmaskedContextKey{}).(bool)
+	return unmasked
+}
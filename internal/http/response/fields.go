@@ -0,0 +1,117 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// Fieldset is a JSON:API style sparse fieldset: the fields a client asked
+// for with ?fields=nik,name. The zero value keeps every field.
+type Fieldset struct {
+	names map[string]bool
+}
+
+// ParseFields reads the request's comma-separated fields parameter for
+// responses made of item, rejecting names item does not serialize.
+func ParseFields(r *http.Request, item interface{}) (Fieldset, error) {
+	raw := strings.TrimSpace(r.URL.Query().Get("fields"))
+	if raw == "" {
+		return Fieldset{}, nil
+	}
+	known := make(map[string]bool)
+	jsonFields(reflect.TypeOf(item), known)
+
+	names := make(map[string]bool)
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !known[name] {
+			return Fieldset{}, fmt.Errorf("unknown field %q", name)
+		}
+		names[name] = true
+	}
+	if len(names) == 0 {
+		return Fieldset{}, nil
+	}
+	return Fieldset{names: names}, nil
+}
+
+// Apply narrows v, an object or a list of objects, to the fieldset. Values
+// that do not serialize to objects are returned unchanged.
+func (f Fieldset) Apply(v interface{}) interface{} {
+	if f.names == nil {
+		return v
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return v
+	}
+
+	switch raw[0] {
+	case '{':
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &object); err != nil {
+			return v
+		}
+		return f.pick(object)
+	case '[':
+		var objects []map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &objects); err != nil {
+			return v
+		}
+		for i, object := range objects {
+			objects[i] = f.pick(object)
+		}
+		return objects
+	}
+	return v
+}
+
+func (f Fieldset) pick(object map[string]json.RawMessage) map[string]json.RawMessage {
+	for name := range object {
+		if !f.names[name] {
+			delete(object, name)
+		}
+	}
+	return object
+}
+
+// jsonFields collects the top-level JSON names t serializes, following
+// embedded structs the way encoding/json promotes their fields.
+func jsonFields(t reflect.Type, into map[string]bool) {
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" {
+			jsonFields(field.Type, into)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		into[name] = true
+	}
+}
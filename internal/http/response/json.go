@@ -3,8 +3,20 @@ package response
 import (
 	"encoding/json"
 	"net/http"
+
+	"life-certificates/internal/i18n"
 )
 
+// catalog translates messages into the Content-Language of the response; nil
+// leaves them in English.
+var catalog *i18n.Catalog
+
+// SetCatalog translates error and validation messages of responses carrying
+// a Content-Language header, as set by the language middleware.
+func SetCatalog(c *i18n.Catalog) {
+	catalog = c
+}
+
 // Success wraps payloads in the common envelope expected by clients.
 func Success(w http.ResponseWriter, statusCode int, data interface{}) {
 	writeJSON(w, statusCode, map[string]interface{}{
@@ -17,7 +29,34 @@ func Success(w http.ResponseWriter, statusCode int, data interface{}) {
 func Error(w http.ResponseWriter, statusCode int, message string) {
 	writeJSON(w, statusCode, map[string]interface{}{
 		"status":  "error",
-		"message": message,
+		"message": translate(w, message),
+	})
+}
+
+// ErrorWithCode wraps error responses that carry a machine readable code.
+func ErrorWithCode(w http.ResponseWriter, statusCode int, code, message string) {
+	writeJSON(w, statusCode, map[string]interface{}{
+		"status":  "error",
+		"code":    code,
+		"message": translate(w, message),
+	})
+}
+
+// ErrorWithData wraps error responses that carry diagnostic details.
+func ErrorWithData(w http.ResponseWriter, statusCode int, message string, data interface{}) {
+	writeJSON(w, statusCode, map[string]interface{}{
+		"status":  "error",
+		"message": translate(w, message),
+		"data":    data,
+	})
+}
+
+// ValidationError reports field-level validation failures.
+func ValidationError(w http.ResponseWriter, message string, fields map[string]string) {
+	writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+		"status":  "error",
+		"message": translate(w, message),
+		"errors":  translateFields(w, fields),
 	})
 }
 
@@ -26,3 +65,21 @@ func writeJSON(w http.ResponseWriter, statusCode int, payload interface{}) {
 	w.WriteHeader(statusCode)
 	_ = json.NewEncoder(w).Encode(payload)
 }
+
+func translate(w http.ResponseWriter, message string) string {
+	if catalog == nil {
+		return message
+	}
+	return catalog.Translate(w.Header().Get("Content-Language"), message)
+}
+
+func translateFields(w http.ResponseWriter, fields map[string]string) map[string]string {
+	if catalog == nil || len(fields) == 0 {
+		return fields
+	}
+	translated := make(map[string]string, len(fields))
+	for field, message := range fields {
+		translated[field] = translate(w, message)
+	}
+	return translated
+}
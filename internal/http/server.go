@@ -11,9 +11,13 @@ import (
 	"github.com/swaggo/http-swagger"
 
 	"life-certificates/internal/config"
+	"life-certificates/internal/domain"
 	handlers "life-certificates/internal/http/handler"
 	custommiddleware "life-certificates/internal/http/middleware"
 	"life-certificates/internal/http/response"
+	"life-certificates/internal/i18n"
+	"life-certificates/internal/metrics"
+	"life-certificates/internal/tracing"
 )
 
 // Server wraps the HTTP server lifecycle.
@@ -21,43 +25,374 @@ type Server struct {
 	httpServer *http.Server
 }
 
+// Handlers groups the endpoint handlers mounted by the server.
+type Handlers struct {
+	Health             *handlers.HealthHandler
+	Participant        *handlers.ParticipantHandler
+	Member             *handlers.MemberHandler
+	DataSubject        *handlers.DataSubjectHandler
+	Consent            *handlers.ConsentHandler
+	LifeCertificate    *handlers.LifeCertificateHandler
+	Verification       *handlers.VerificationRequestHandler
+	BulkRegistration   *handlers.BulkRegistrationHandler
+	Reconciliation     *handlers.ReconciliationHandler
+	Review             *handlers.ReviewHandler
+	Manual             *handlers.ManualVerificationHandler
+	StatusOverride     *handlers.StatusOverrideHandler
+	DeathReport        *handlers.DeathReportHandler
+	HomeVisit          *handlers.HomeVisitHandler
+	Webhook            *handlers.WebhookHandler
+	Stream             *handlers.VerificationStreamHandler
+	PaymentPush        *handlers.PaymentPushHandler
+	HoldRelease        *handlers.HoldReleaseHandler
+	PayrollFile        *handlers.PayrollFileHandler
+	Partner            *handlers.PartnerHandler
+	SelfService        *handlers.SelfServiceHandler
+	Kiosk              *handlers.KioskHandler
+	Officer            *handlers.OfficerHandler
+	Branch             *handlers.BranchHandler
+	Holiday            *handlers.HolidayHandler
+	Receipt            *handlers.ReceiptHandler
+	CertificatePDF     *handlers.CertificatePDFHandler
+	Signing            *handlers.SigningHandler
+	Version            *handlers.VersionHandler
+	AccessLog          *handlers.AccessLogHandler
+	UploadScan         *handlers.UploadScanHandler
+	Document           *handlers.DocumentHandler
+	KTPCheck           *handlers.KTPCheckHandler
+	Settings           *handlers.VerificationSettingsHandler
+	Profile            *handlers.VerificationProfileHandler
+	VerificationLock   *handlers.VerificationLockHandler
+	FacialConflict     *handlers.FacialConflictHandler
+	VerificationDevice *handlers.VerificationDeviceHandler
+	Stats              *handlers.StatsHandler
+	MonthlyReport      *handlers.MonthlyReportHandler
+	Campaign           *handlers.CampaignHandler
+	Job                *handlers.JobHandler
+	Scheduler          *handlers.SchedulerHandler
+	Notification       *handlers.NotificationHandler
+	Tenant             *handlers.TenantHandler
+	Export             *handlers.ExportHandler
+	Seed               *handlers.SeedHandler
+	GraphQL            http.Handler
+
+	// Access records reads of personal data on detail endpoints.
+	Access custommiddleware.AccessRecorder
+	// Unmask records requests for unmasked personal data in list responses.
+	Unmask custommiddleware.UnmaskRecorder
+	// Partners resolves partner API keys.
+	Partners custommiddleware.PartnerAuthenticator
+	// SelfTokens resolves self-service bearer tokens.
+	SelfTokens custommiddleware.SelfServiceAuthenticator
+	// Kiosks resolves kiosk device keys.
+	Kiosks custommiddleware.KioskAuthenticator
+	// Tenants resolves the tenant a request names.
+	Tenants custommiddleware.TenantLookup
+	// Catalog translates error messages into the language a client accepts.
+	Catalog *i18n.Catalog
+}
+
 // NewServer assembles the HTTP router and dependencies.
-func NewServer(cfg *config.Config, participantHandler *handlers.ParticipantHandler, memberHandler *handlers.MemberHandler, lifeHandler *handlers.LifeCertificateHandler) *Server {
+func NewServer(cfg *config.Config, h Handlers) *Server {
 	r := chi.NewRouter()
 
+	r.Use(tracing.Middleware(cfg.Tracing.SlowRequest))
+	r.Use(custommiddleware.Metrics)
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(30 * time.Second))
+	if h.Catalog != nil {
+		response.SetCatalog(h.Catalog)
+		r.Use(custommiddleware.Language(h.Catalog))
+	}
+	// Long-lived streams end when the client disconnects, exports when their last row
+	// is sent, and profiles run for the requested seconds.
+	r.Use(custommiddleware.Except(middleware.Timeout(30*time.Second),
+		"/life-certificate/stream", "/members/export", "/life-certificate/export", "/debug/pprof/profile", "/debug/pprof/trace"))
+
+	r.Get("/live", h.Health.Live)
+	r.Get("/ready", h.Health.Ready)
+	r.Get("/startup", h.Health.Startup)
+	// Deprecated: kept for existing probes, answers like /live.
+	r.Get("/health", h.Health.Live)
 
-	r.Get("/health", func(w http.ResponseWriter, _ *http.Request) {
-		response.Success(w, http.StatusOK, map[string]string{"status": "ok"})
+	r.Handle("/metrics", metrics.Handler())
+
+	// resolveTenant comes after authentication, which may bind the caller to a tenant.
+	resolveTenant := custommiddleware.ResolveTenant(h.Tenants, cfg.Tenant.BaseDomain)
+
+	// Unsubscribe links in emails carry a signed token instead of credentials.
+	r.With(resolveTenant).Get("/notifications/unsubscribe", h.Notification.Unsubscribe)
+	r.With(resolveTenant).Post("/notifications/unsubscribe", h.Notification.Unsubscribe)
+
+	// External institutions use their own API keys and only reach the status endpoints.
+	r.Route("/partner/v1", func(r chi.Router) {
+		r.Use(custommiddleware.PartnerAuth(h.Partners))
+		r.Use(resolveTenant)
+		r.With(custommiddleware.RequireScope(domain.PartnerScopeStatus)).Post("/status", h.Partner.Status)
+		r.With(custommiddleware.RequireScope(domain.PartnerScopeChanges)).Get("/changes", h.Partner.Changes)
 	})
 
+	// Institutions fetch the signing key to check certificates offline.
+	if cfg.Signing.Backend != "none" {
+		r.Get("/.well-known/jwks.json", h.Signing.Keys)
+		r.Get("/.well-known/certificate-chain.pem", h.Signing.Chain)
+	}
+
+	// Anyone holding a receipt can check it; the answer names no one.
+	if cfg.Receipt.Enabled {
+		r.With(resolveTenant).Get("/verify-receipt/{token}", h.Receipt.Check)
+	}
+
+	// Pensioners sign in with a one-time code and can only submit their own verification.
+	if cfg.SelfService.Enabled {
+		r.Route("/self", func(r chi.Router) {
+			r.Use(resolveTenant)
+			r.Post("/otp", h.SelfService.RequestCode)
+			r.Post("/token", h.SelfService.ExchangeCode)
+			r.With(custommiddleware.SelfServiceAuth(h.SelfTokens)).Post("/sessions", h.SelfService.CreateSession)
+			r.With(custommiddleware.SelfServiceAuth(h.SelfTokens)).Post("/verify", h.SelfService.Verify)
+		})
+	}
+
 	r.Group(func(r chi.Router) {
-		r.Use(custommiddleware.BasicAuth(cfg.Auth.Username, cfg.Auth.Password))
+		r.Use(custommiddleware.BasicAuth(custommiddleware.CredentialsFromConfig(cfg)))
+		r.Use(resolveTenant)
+
+		logParticipant := custommiddleware.AccessLog(h.Access, domain.AccessResourceParticipant, "participant_id")
+		logMember := custommiddleware.AccessLog(h.Access, domain.AccessResourceMember, "member_id")
+		logCertificateStatus := custommiddleware.AccessLog(h.Access, domain.AccessResourceCertificateStatus, "participant_id")
+		logCertificate := custommiddleware.AccessLog(h.Access, domain.AccessResourceLifeCertificate, "certificate_id")
+		logVerification := custommiddleware.AccessLog(h.Access, domain.AccessResourceVerificationRequest, "verification_id")
+		logHomeVisit := custommiddleware.AccessLog(h.Access, domain.AccessResourceHomeVisit, "visit_id")
+		logDocument := custommiddleware.AccessLog(h.Access, domain.AccessResourceDocument, "document_id")
+		unmask := custommiddleware.Unmask(h.Unmask)
 
 		r.Route("/participants", func(r chi.Router) {
-			r.Get("/", participantHandler.List)
-			r.Get("/{participant_id}", participantHandler.Get)
-			r.Put("/{participant_id}", participantHandler.Update)
-			r.Delete("/{participant_id}", participantHandler.Delete)
-			r.Post("/register", participantHandler.Register)
+			r.With(unmask).Get("/", h.Participant.List)
+			r.With(unmask).Get("/search", h.Participant.Search)
+			r.With(logParticipant).Get("/{participant_id}", h.Participant.Get)
+			r.With(logParticipant).Get("/{participant_id}/schedule", h.Participant.Schedule)
+			r.Put("/{participant_id}", h.Participant.Update)
+			r.Patch("/{participant_id}", h.Participant.Update)
+			r.Delete("/{participant_id}", h.Participant.Delete)
+			r.Post("/{participant_id}/faces", h.Participant.EnrollFace)
+			r.With(custommiddleware.RequireRole(custommiddleware.RoleAdmin)).Post("/{participant_id}/suspend", h.Participant.Suspend)
+			r.With(custommiddleware.RequireRole(custommiddleware.RoleAdmin)).Post("/{participant_id}/unsuspend", h.Participant.Unsuspend)
+			r.With(custommiddleware.RequireRole(custommiddleware.RoleAdmin)).Post("/{participant_id}/block", h.Participant.Block)
+			r.Post("/{participant_id}/devices", h.Notification.RegisterDevice)
+			r.Get("/{participant_id}/devices", h.Notification.Devices)
+			r.Delete("/{participant_id}/devices/{device_id}", h.Notification.RemoveDevice)
+			r.With(custommiddleware.RequireRole(custommiddleware.RoleAdmin)).Put("/{participant_id}/verification-profile", h.Profile.Assign)
+			r.With(custommiddleware.RequireRole(custommiddleware.RoleAdmin)).Delete("/{participant_id}/verification-lock", h.VerificationLock.Unlock)
+			r.Get("/{participant_id}/verification-devices", h.VerificationDevice.List)
+			r.With(custommiddleware.RequireRole(custommiddleware.RoleAdmin)).Put("/{participant_id}/verification-devices/{device_id}/trust", h.VerificationDevice.Trust)
+			r.With(custommiddleware.RequireRole(custommiddleware.RoleAdmin)).Delete("/{participant_id}/verification-devices/{device_id}/trust", h.VerificationDevice.Untrust)
+			r.Post("/register", h.Participant.Register)
+			r.Post("/register-from-member", h.Participant.RegisterFromMember)
+			r.With(unmask).Get("/ktp-checks", h.KTPCheck.List)
+			r.Get("/ktp-checks/{check_id}", h.KTPCheck.Get)
+			r.Post("/ktp-checks/{check_id}/confirm", h.KTPCheck.Confirm)
+			r.Post("/bulk-register", h.BulkRegistration.Submit)
+			r.Get("/bulk-register/{job_id}", h.BulkRegistration.Status)
+			r.With(unmask).Get("/bulk-register/{job_id}/failures", h.BulkRegistration.Failures)
 		})
 
 		r.Route("/members", func(r chi.Router) {
-			r.Post("/", memberHandler.Create)
-			r.Get("/", memberHandler.List)
-			r.Get("/{member_id}", memberHandler.Get)
-			r.Put("/{member_id}", memberHandler.Update)
-			r.Delete("/{member_id}", memberHandler.Delete)
+			r.Post("/", h.Member.Create)
+			r.With(unmask).Get("/", h.Member.List)
+			r.With(unmask).Get("/duplicates", h.Member.Duplicates)
+			r.With(unmask).Get("/export", h.Export.Members)
+			r.Post("/merge", h.Member.Merge)
+			r.Get("/merges", h.Member.Merges)
+			r.With(logMember).Get("/{member_id}", h.Member.Get)
+			r.Put("/{member_id}", h.Member.Update)
+			r.Delete("/{member_id}", h.Member.Delete)
+			r.With(custommiddleware.RequireRole(custommiddleware.RoleAdmin)).Get("/{member_id}/data-export", h.DataSubject.Export)
+			r.With(custommiddleware.RequireRole(custommiddleware.RoleAdmin)).Post("/{member_id}/erase", h.DataSubject.Erase)
+			r.Get("/{member_id}/notification-preferences", h.Notification.Preferences)
+			r.Put("/{member_id}/notification-preferences", h.Notification.SavePreferences)
+			r.Delete("/{member_id}/notification-preferences", h.Notification.ResetPreferences)
 		})
 
+		r.Post("/consents", h.Consent.Record)
+		r.With(unmask).Get("/consents", h.Consent.List)
+
+		r.With(unmask).Get("/notifications", h.Notification.List)
+		r.Post("/notifications/{notification_id}/retry", h.Notification.Retry)
+
 		r.Route("/life-certificate", func(r chi.Router) {
-			r.Post("/verify", lifeHandler.Verify)
-			r.Get("/status/{participant_id}", lifeHandler.LatestStatus)
+			r.Post("/sessions", h.LifeCertificate.CreateSession)
+			r.Post("/verify", h.LifeCertificate.Verify)
+			r.Post("/verify-async", h.Verification.Submit)
+			r.With(logVerification).Get("/verifications/{verification_id}", h.Verification.Get)
+			r.Post("/manual", h.Manual.Verify)
+			r.Post("/proxy", h.Manual.Proxy)
+			r.Get("/stream", h.Stream.Stream)
+			r.With(unmask).Get("/export", h.Export.Certificates)
+			r.With(logCertificateStatus).Get("/status/{participant_id}", h.LifeCertificate.LatestStatus)
+			r.With(logCertificate).Get("/{certificate_id}/selfie", h.LifeCertificate.Selfie)
+			if cfg.Receipt.Enabled {
+				r.With(logCertificate).Get("/{certificate_id}/receipt", h.Receipt.Receipt)
+			}
+			if cfg.CertificatePDF.Enabled {
+				r.With(logCertificate).Get("/{certificate_id}/document", h.CertificatePDF.Download)
+			}
+			r.With(logCertificate).Get("/{certificate_id}/documents", h.Manual.Documents)
+			r.With(logCertificate).Get("/{certificate_id}/documents/{document_id}", h.Manual.Download)
+			r.With(custommiddleware.RequireRole(custommiddleware.RoleAdmin)).Post("/{certificate_id}/override", h.StatusOverride.Propose)
+			r.With(custommiddleware.RequireRole(custommiddleware.RoleAdmin)).Get("/{certificate_id}/overrides", h.StatusOverride.ListByCertificate)
+		})
+
+		r.Route("/home-visits", func(r chi.Router) {
+			r.Post("/", h.HomeVisit.Request)
+			r.Get("/", h.HomeVisit.List)
+			r.With(logHomeVisit).Get("/{visit_id}", h.HomeVisit.Get)
+			r.Post("/{visit_id}/schedule", h.HomeVisit.Schedule)
+			r.Post("/{visit_id}/missed", h.HomeVisit.Missed)
+			r.Post("/{visit_id}/cancel", h.HomeVisit.Cancel)
 		})
 
+		r.Route("/documents", func(r chi.Router) {
+			r.Post("/", h.Document.Upload)
+			r.Get("/", h.Document.List)
+			r.Get("/{document_id}", h.Document.Get)
+			r.With(logDocument).Get("/{document_id}/content", h.Document.Download)
+			r.Delete("/{document_id}", h.Document.Delete)
+		})
+
+		// Operators at branch kiosks sign in as usual and the device adds its own key.
+		r.Route("/kiosk", func(r chi.Router) {
+			r.Use(custommiddleware.KioskAuth(h.Kiosks))
+			r.Post("/lookup", h.Kiosk.Lookup)
+			r.Post("/verify", h.Kiosk.Verify)
+		})
+
+		r.Route("/review", func(r chi.Router) {
+			r.Get("/queue", h.Review.Queue)
+			r.Get("/overdue", h.Review.Overdue)
+			r.Get("/metrics", h.Review.Metrics)
+			r.Get("/conflicts", h.FacialConflict.List)
+			r.Post("/conflicts/{conflict_id}/confirm", h.FacialConflict.Confirm)
+			r.Post("/conflicts/{conflict_id}/dismiss", h.FacialConflict.Dismiss)
+			r.Post("/{certificate_id}/claim", h.Review.Claim)
+			r.Post("/{certificate_id}/assign", h.Review.Assign)
+			r.Post("/{certificate_id}/resolve", h.Review.Resolve)
+			r.With(logCertificate).Get("/{certificate_id}/history", h.Review.History)
+		})
+
+		r.Route("/stats", func(r chi.Router) {
+			r.Get("/overview", h.Stats.Overview)
+			r.Get("/timeseries", h.Stats.Timeseries)
+			r.Get("/score-distribution", h.Stats.ScoreDistribution)
+			r.Post("/threshold-simulation", h.Stats.SimulateThresholds)
+		})
+		r.Route("/reports", func(r chi.Router) {
+			r.Get("/verification", h.Stats.VerificationReport)
+			r.Get("/monthly/{period}", h.MonthlyReport.Download)
+		})
+
+		r.Route("/campaigns", func(r chi.Router) {
+			r.With(custommiddleware.RequireRole(custommiddleware.RoleAdmin)).Post("/", h.Campaign.Create)
+			r.Get("/", h.Campaign.List)
+			r.Post("/preview", h.Campaign.Preview)
+			r.Get("/exclusions", h.Campaign.ListExclusions)
+			r.With(custommiddleware.RequireRole(custommiddleware.RoleAdmin)).Post("/exclusions", h.Campaign.AddExclusion)
+			r.With(custommiddleware.RequireRole(custommiddleware.RoleAdmin)).Delete("/exclusions/{exclusion_id}", h.Campaign.RemoveExclusion)
+			r.Get("/{campaign_id}", h.Campaign.Progress)
+			r.Get("/{campaign_id}/participants", h.Campaign.Participants)
+			r.With(custommiddleware.RequireRole(custommiddleware.RoleAdmin)).Put("/{campaign_id}/escalation", h.Campaign.UpdateEscalation)
+			r.With(custommiddleware.RequireRole(custommiddleware.RoleAdmin)).Post("/{campaign_id}/notify", h.Campaign.Notify)
+			r.Get("/{campaign_id}/notifications", h.Campaign.Notifications)
+			r.Get("/{campaign_id}/notifications/{notification_id}", h.Campaign.NotificationProgress)
+		})
+
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Use(custommiddleware.RequireRole(custommiddleware.RoleAdmin))
+			r.Post("/", h.Webhook.Create)
+			r.Get("/", h.Webhook.List)
+			r.Get("/{webhook_id}", h.Webhook.Get)
+			r.Patch("/{webhook_id}", h.Webhook.Update)
+			r.Delete("/{webhook_id}", h.Webhook.Delete)
+			r.Get("/{webhook_id}/deliveries", h.Webhook.Deliveries)
+		})
+
+		r.Route("/admin", func(r chi.Router) {
+			r.Group(func(r chi.Router) {
+				r.Use(custommiddleware.RequireRole(custommiddleware.RoleAdmin))
+				r.Post("/frcore/reconciliations", h.Reconciliation.Trigger)
+				r.Get("/frcore/reconciliations", h.Reconciliation.List)
+				r.Get("/frcore/reconciliations/{run_id}", h.Reconciliation.Get)
+				r.Get("/overrides", h.StatusOverride.List)
+				r.Post("/overrides/{override_id}/approve", h.StatusOverride.Approve)
+				r.Post("/overrides/{override_id}/reject", h.StatusOverride.Reject)
+				r.Get("/death-reports", h.DeathReport.List)
+				r.Post("/death-reports/{report_id}/confirm", h.DeathReport.Confirm)
+				r.Post("/death-reports/{report_id}/reject", h.DeathReport.Reject)
+				r.Get("/payment-pushes/unacknowledged", h.PaymentPush.Unacknowledged)
+				r.Post("/payment-pushes/{push_id}/retry", h.PaymentPush.Retry)
+				r.Get("/hold-releases", h.HoldRelease.List)
+				r.Post("/hold-releases/{release_id}/retry", h.HoldRelease.Retry)
+				r.Post("/hold-releases/{release_id}/resend", h.HoldRelease.Resend)
+				r.Get("/payroll-files", h.PayrollFile.List)
+				r.Get("/partner-keys", h.Partner.ListKeys)
+				r.Post("/partner-keys", h.Partner.CreateKey)
+				r.Delete("/partner-keys/{key_id}", h.Partner.RevokeKey)
+				r.Get("/kiosks", h.Kiosk.ListKiosks)
+				r.Post("/kiosks", h.Kiosk.CreateKiosk)
+				r.Delete("/kiosks/{kiosk_id}", h.Kiosk.RevokeKiosk)
+				r.Get("/officers", h.Officer.List)
+				r.Post("/officers", h.Officer.Create)
+				r.Get("/officers/report", h.Officer.Report)
+				r.Get("/officers/{officer_id}", h.Officer.Get)
+				r.Patch("/officers/{officer_id}", h.Officer.Update)
+				r.Get("/branches", h.Branch.List)
+				r.Post("/branches", h.Branch.Create)
+				r.Get("/branches/{branch_id}", h.Branch.Get)
+				r.Patch("/branches/{branch_id}", h.Branch.Update)
+				r.Delete("/branches/{branch_id}", h.Branch.Delete)
+				r.Get("/holidays", h.Holiday.List)
+				r.Post("/holidays", h.Holiday.Create)
+				r.Delete("/holidays/{holiday_id}", h.Holiday.Delete)
+				r.Get("/access-logs", h.AccessLog.List)
+				r.Get("/upload-scans", h.UploadScan.List)
+				r.Get("/config/verification", h.Settings.Get)
+				r.Put("/config/verification", h.Settings.Update)
+				r.Get("/verification-profiles", h.Profile.List)
+				r.Post("/verification-profiles", h.Profile.Create)
+				r.Get("/verification-profiles/{profile_id}", h.Profile.Get)
+				r.Patch("/verification-profiles/{profile_id}", h.Profile.Update)
+				r.Delete("/verification-profiles/{profile_id}", h.Profile.Delete)
+				r.Get("/jobs", h.Job.List)
+				r.Get("/jobs/{job_id}", h.Job.Get)
+				r.Post("/jobs/{job_id}/retry", h.Job.Retry)
+				r.Post("/jobs/{job_id}/cancel", h.Job.Cancel)
+				r.Get("/scheduled-tasks", h.Scheduler.List)
+				r.Get("/notification-templates", h.Notification.ListTemplates)
+				r.Put("/notification-templates/{name}", h.Notification.SaveTemplate)
+				r.Delete("/notification-templates/{name}", h.Notification.ResetTemplate)
+				r.Route("/tenants", func(r chi.Router) {
+					r.Use(custommiddleware.RequireUnboundTenant)
+					r.Get("/", h.Tenant.List)
+					r.Post("/", h.Tenant.Create)
+					r.Get("/{tenant_id}", h.Tenant.Get)
+					r.Patch("/{tenant_id}", h.Tenant.Update)
+					r.Put("/{tenant_id}/branding", h.Tenant.UpdateBranding)
+					r.Put("/{tenant_id}/branding/logo", h.Tenant.UploadLogo)
+					r.Get("/{tenant_id}/branding/logo", h.Tenant.Logo)
+					r.Delete("/{tenant_id}/branding/logo", h.Tenant.DeleteLogo)
+				})
+				if cfg.Seed.Enabled {
+					r.Post("/seed", h.Seed.Seed)
+				}
+			})
+		})
+
+		r.With(unmask).Handle("/graphql", h.GraphQL)
+
+		r.Get("/version", h.Version.Version)
+		r.With(custommiddleware.RequireRole(custommiddleware.RoleAdmin)).Mount("/debug", middleware.Profiler())
+
 		r.Get("/swagger/*", httpSwagger.Handler())
 	})
 
@@ -72,6 +407,11 @@ func NewServer(cfg *config.Config, participantHandler *handlers.ParticipantHandl
 	return &Server{httpServer: httpServer}
 }
 
+// Handler returns the router with its middleware, for serving it in-process.
+func (s *Server) Handler() http.Handler {
+	return s.httpServer.Handler
+}
+
 // Start begins serving HTTP traffic.
 func (s *Server) Start() error {
 	return s.httpServer.ListenAndServe()
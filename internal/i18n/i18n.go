@@ -0,0 +1,254 @@
+// Package i18n translates the service's user-facing messages.
+//
+// Messages are written in English in the code, which is the source language
+// and needs no catalog. A catalog for another language is a JSON object from
+// English message to translation. Keys may hold {placeholders} standing for
+// the variable parts of a message, e.g. "must be at most {n} characters",
+// which the translation repeats where they belong. Catalogs for Indonesian
+// are built in; a deployment can add or reword messages, and add languages,
+// with <language>.json files in a catalog directory.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SourceLanguage is the language messages are written in.
+const SourceLanguage = "en"
+
+//go:embed catalogs/*.json
+var builtin embed.FS
+
+// Catalog holds the translations of every supported language.
+type Catalog struct {
+	defaultLanguage string
+	languages       map[string]*messages
+}
+
+// messages is the catalog of one language.
+type messages struct {
+	exact    map[string]string
+	patterns []pattern
+}
+
+// pattern translates messages matching a key with placeholders.
+type pattern struct {
+	expr        *regexp.Regexp
+	names       []string
+	translation string
+	// literal is the length of the key without placeholders; longer, more
+	// specific keys are tried first.
+	literal int
+}
+
+var placeholder = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// Load reads the built-in catalogs and merges the <language>.json files of
+// dir over them. An empty dir uses the built-in catalogs only.
+// defaultLanguage answers requests that accept no supported language.
+func Load(dir, defaultLanguage string) (*Catalog, error) {
+	entries := make(map[string]map[string]string)
+	builtins, err := fs.ReadDir(builtin, "catalogs")
+	if err != nil {
+		return nil, fmt.Errorf("read built-in catalogs: %w", err)
+	}
+	for _, entry := range builtins {
+		data, err := builtin.ReadFile("catalogs/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("read built-in catalog %s: %w", entry.Name(), err)
+		}
+		if err := merge(entries, entry.Name(), data); err != nil {
+			return nil, err
+		}
+	}
+	if dir != "" {
+		files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		if err != nil {
+			return nil, fmt.Errorf("list catalogs: %w", err)
+		}
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("read catalog: %w", err)
+			}
+			if err := merge(entries, filepath.Base(file), data); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	c := &Catalog{
+		defaultLanguage: strings.ToLower(defaultLanguage),
+		languages:       map[string]*messages{SourceLanguage: {exact: map[string]string{}}},
+	}
+	for language, translations := range entries {
+		compiled, err := compile(translations)
+		if err != nil {
+			return nil, fmt.Errorf("catalog %s: %w", language, err)
+		}
+		c.languages[language] = compiled
+	}
+	if _, ok := c.languages[c.defaultLanguage]; !ok {
+		return nil, fmt.Errorf("default language %q has no catalog", defaultLanguage)
+	}
+	return c, nil
+}
+
+// merge adds the translations of a <language>.json file.
+func merge(entries map[string]map[string]string, name string, data []byte) error {
+	language := strings.ToLower(strings.TrimSuffix(name, ".json"))
+	var translations map[string]string
+	if err := json.Unmarshal(data, &translations); err != nil {
+		return fmt.Errorf("parse catalog %s: %w", name, err)
+	}
+	if entries[language] == nil {
+		entries[language] = make(map[string]string)
+	}
+	for message, translation := range translations {
+		entries[language][message] = translation
+	}
+	return nil
+}
+
+func compile(translations map[string]string) (*messages, error) {
+	m := &messages{exact: make(map[string]string)}
+	for key, translation := range translations {
+		locations := placeholder.FindAllStringSubmatchIndex(key, -1)
+		if len(locations) == 0 {
+			m.exact[key] = translation
+			continue
+		}
+		var expr strings.Builder
+		p := pattern{translation: translation, literal: len(key)}
+		expr.WriteString("^")
+		last := 0
+		for _, loc := range locations {
+			expr.WriteString(regexp.QuoteMeta(key[last:loc[0]]))
+			expr.WriteString("(.+?)")
+			p.names = append(p.names, key[loc[2]:loc[3]])
+			p.literal -= loc[1] - loc[0]
+			last = loc[1]
+		}
+		expr.WriteString(regexp.QuoteMeta(key[last:]))
+		expr.WriteString("$")
+		compiled, err := regexp.Compile(expr.String())
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", key, err)
+		}
+		p.expr = compiled
+		m.patterns = append(m.patterns, p)
+	}
+	sort.SliceStable(m.patterns, func(i, j int) bool {
+		if m.patterns[i].literal != m.patterns[j].literal {
+			return m.patterns[i].literal > m.patterns[j].literal
+		}
+		return m.patterns[i].expr.String() < m.patterns[j].expr.String()
+	})
+	return m, nil
+}
+
+// DefaultLanguage is the language of requests that accept no supported language.
+func (c *Catalog) DefaultLanguage() string {
+	return c.defaultLanguage
+}
+
+// Languages lists the supported languages.
+func (c *Catalog) Languages() []string {
+	languages := make([]string, 0, len(c.languages))
+	for language := range c.languages {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Negotiate picks the supported language a client prefers from an
+// Accept-Language header, matching regional tags such as id-ID by their
+// primary language, and falls back to the default language.
+func (c *Catalog) Negotiate(acceptLanguage string) string {
+	type weighted struct {
+		tag     string
+		quality float64
+	}
+	var tags []weighted
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality > 0 {
+			tags = append(tags, weighted{tag: tag, quality: quality})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].quality > tags[j].quality })
+
+	for _, t := range tags {
+		if t.tag == "*" {
+			return c.defaultLanguage
+		}
+		if _, ok := c.languages[t.tag]; ok {
+			return t.tag
+		}
+		primary, _, _ := strings.Cut(t.tag, "-")
+		if _, ok := c.languages[primary]; ok {
+			return primary
+		}
+	}
+	return c.defaultLanguage
+}
+
+// Translate returns a message in a language, or the message unchanged when
+// the language's catalog has no translation for it.
+func (c *Catalog) Translate(language, message string) string {
+	m, ok := c.languages[language]
+	if !ok || message == "" {
+		return message
+	}
+	if translation, ok := m.exact[message]; ok {
+		return translation
+	}
+	for _, p := range m.patterns {
+		match := p.expr.FindStringSubmatch(message)
+		if match == nil {
+			continue
+		}
+		translation := p.translation
+		for i, name := range p.names {
+			translation = strings.ReplaceAll(translation, "{"+name+"}", match[i+1])
+		}
+		return translation
+	}
+	return message
+}
+
+type contextKey struct{}
+
+// WithLanguage records the language negotiated for a request.
+func WithLanguage(ctx context.Context, language string) context.Context {
+	return context.WithValue(ctx, contextKey{}, language)
+}
+
+// FromContext returns the language negotiated for the request, or "" outside requests.
+func FromContext(ctx context.Context) string {
+	language, _ := ctx.Value(contextKey{}).(string)
+	return language
+}
@@ -0,0 +1,230 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"time"
+)
+
+// EXIF and GPS tags read from uploads.
+const (
+	exifOrientationTag     = 0x0112
+	exifIFDPointerTag      = 0x8769
+	gpsIFDPointerTag       = 0x8825
+	exifDateTimeOriginal   = 0x9003
+	exifOffsetTimeOriginal = 0x9011
+	gpsLatitudeRefTag      = 0x0001
+	gpsLatitudeTag         = 0x0002
+	gpsLongitudeRefTag     = 0x0003
+	gpsLongitudeTag        = 0x0004
+
+	exifDateTimeLayout = "2006:01:02 15:04:05"
+)
+
+// Metadata is what a photo's EXIF block says about when and where it was taken.
+type Metadata struct {
+	// TakenAt is DateTimeOriginal. Cameras usually record local time without
+	// a zone; it is then read in the location given to ReadMetadata.
+	TakenAt *time.Time
+	// Latitude and Longitude are the GPS position in decimal degrees.
+	Latitude  *float64
+	Longitude *float64
+}
+
+// ReadMetadata extracts the capture time and GPS position from a JPEG's EXIF
+// block. It must run on the upload itself, as Process strips EXIF. Fields
+// the photo does not carry, and every field of other formats, are left nil.
+func ReadMetadata(data []byte, location *time.Location) Metadata {
+	var meta Metadata
+	tiff := readTIFF(data)
+	if tiff == nil {
+		return meta
+	}
+	ifd0 := tiff.ifd(tiff.firstIFD())
+
+	if exif, ok := ifd0[exifIFDPointerTag]; ok {
+		tags := tiff.ifd(int(tiff.long(exif)))
+		if raw, ok := tags[exifDateTimeOriginal]; ok {
+			value := tiff.ascii(raw)
+			loc := location
+			if offset, ok := tags[exifOffsetTimeOriginal]; ok {
+				// OffsetTimeOriginal is "+07:00".
+				if zone, err := time.Parse("-07:00", tiff.ascii(offset)); err == nil {
+					loc = zone.Location()
+				}
+			}
+			if taken, err := time.ParseInLocation(exifDateTimeLayout, value, loc); err == nil {
+				taken = taken.UTC()
+				meta.TakenAt = &taken
+			}
+		}
+	}
+
+	if gps, ok := ifd0[gpsIFDPointerTag]; ok {
+		tags := tiff.ifd(int(tiff.long(gps)))
+		meta.Latitude = tiff.coordinate(tags[gpsLatitudeTag], tags[gpsLatitudeRefTag], "S")
+		meta.Longitude = tiff.coordinate(tags[gpsLongitudeTag], tags[gpsLongitudeRefTag], "W")
+		if meta.Latitude == nil || meta.Longitude == nil {
+			meta.Latitude, meta.Longitude = nil, nil
+		}
+	}
+	return meta
+}
+
+// exifOrientation reads the orientation tag from a JPEG's EXIF block,
+// returning 1 (upright) when there is none or it cannot be parsed.
+func exifOrientation(data []byte) int {
+	tiff := readTIFF(data)
+	if tiff == nil {
+		return 1
+	}
+	entry, ok := tiff.ifd(tiff.firstIFD())[exifOrientationTag]
+	if !ok {
+		return 1
+	}
+	// A SHORT value is stored inline in the first two bytes of the value field.
+	value := int(tiff.order.Uint16(tiff.data[entry+8 : entry+10]))
+	if value < 1 || value > 8 {
+		return 1
+	}
+	return value
+}
+
+// tiffData is the TIFF structure inside a JPEG's EXIF block.
+type tiffData struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+// readTIFF finds the EXIF block of a JPEG, returning nil when there is none
+// or its TIFF header cannot be parsed.
+func readTIFF(data []byte) *tiffData {
+	segment := exifSegment(data)
+	if len(segment) < 8 {
+		return nil
+	}
+	tiff := &tiffData{data: segment}
+	switch string(segment[:2]) {
+	case "II":
+		tiff.order = binary.LittleEndian
+	case "MM":
+		tiff.order = binary.BigEndian
+	default:
+		return nil
+	}
+	if tiff.order.Uint16(segment[2:4]) != 42 {
+		return nil
+	}
+	return tiff
+}
+
+// exifSegment returns the TIFF payload of a JPEG's APP1 EXIF segment.
+func exifSegment(data []byte) []byte {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil
+	}
+	for offset := 2; offset+4 <= len(data); {
+		if data[offset] != 0xFF {
+			return nil
+		}
+		marker := data[offset+1]
+		switch {
+		case marker == 0xFF:
+			// Fill byte before a marker.
+			offset++
+			continue
+		case marker == 0xD8 || marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7):
+			// Markers without a length.
+			offset += 2
+			continue
+		case marker == 0xDA || marker == 0xD9:
+			// Image data starts; metadata segments come before it.
+			return nil
+		}
+		length := int(binary.BigEndian.Uint16(data[offset+2 : offset+4]))
+		if length < 2 || offset+2+length > len(data) {
+			return nil
+		}
+		segment := data[offset+4 : offset+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return segment[6:]
+		}
+		offset += 2 + length
+	}
+	return nil
+}
+
+func (t *tiffData) firstIFD() int {
+	return int(t.order.Uint32(t.data[4:8]))
+}
+
+// ifd maps the tags of the IFD at offset to the offsets of their 12-byte entries.
+func (t *tiffData) ifd(offset int) map[uint16]int {
+	entries := map[uint16]int{}
+	if offset < 8 || offset+2 > len(t.data) {
+		return entries
+	}
+	count := int(t.order.Uint16(t.data[offset : offset+2]))
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(t.data) {
+			break
+		}
+		entries[t.order.Uint16(t.data[entry:entry+2])] = entry
+	}
+	return entries
+}
+
+// long reads the inline LONG value of an entry, such as an IFD pointer.
+func (t *tiffData) long(entry int) uint32 {
+	return t.order.Uint32(t.data[entry+8 : entry+12])
+}
+
+// value returns the bytes of an entry's value, stored inline up to four
+// bytes and at an offset beyond that.
+func (t *tiffData) value(entry, size int) []byte {
+	count := int(t.order.Uint32(t.data[entry+4 : entry+8]))
+	length := count * size
+	if count < 0 || length < 0 {
+		return nil
+	}
+	if length <= 4 {
+		return t.data[entry+8 : entry+8+length]
+	}
+	offset := int(t.long(entry))
+	if offset < 0 || offset+length > len(t.data) {
+		return nil
+	}
+	return t.data[offset : offset+length]
+}
+
+// ascii reads an ASCII entry without its NUL terminator.
+func (t *tiffData) ascii(entry int) string {
+	return strings.TrimRight(string(t.value(entry, 1)), "\x00 ")
+}
+
+// coordinate turns a GPS degrees, minutes, seconds RATIONAL triple and its
+// reference into decimal degrees, negative for the given hemisphere.
+func (t *tiffData) coordinate(entry, ref int, negative string) *float64 {
+	if entry == 0 || ref == 0 {
+		return nil
+	}
+	raw := t.value(entry, 8)
+	if len(raw) != 24 {
+		return nil
+	}
+	var degrees float64
+	for i, scale := range []float64{1, 60, 3600} {
+		numerator := t.order.Uint32(raw[i*8 : i*8+4])
+		denominator := t.order.Uint32(raw[i*8+4 : i*8+8])
+		if denominator == 0 {
+			return nil
+		}
+		degrees += float64(numerator) / float64(denominator) / scale
+	}
+	if strings.EqualFold(t.ascii(ref), negative) {
+		degrees = -degrees
+	}
+	return &degrees
+}
@@ -0,0 +1,93 @@
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Format is an accepted upload format, recognised by its magic bytes.
+type Format string
+
+const (
+	FormatJPEG Format = "jpeg"
+	FormatPNG  Format = "png"
+	FormatWebP Format = "webp"
+	// FormatHEIC covers HEIC and HEIF photos, the iPhone camera default.
+	FormatHEIC Format = "heic"
+)
+
+// ErrUnsupportedFormat reports an upload that is not one of the accepted formats.
+var ErrUnsupportedFormat = errors.New("unsupported image format, upload a JPEG, PNG, HEIC or WebP photo")
+
+// heifBrands are the ISO BMFF major brands of HEIC and HEIF still images.
+var heifBrands = []string{"heic", "heix", "heim", "heis", "hevc", "hevx", "mif1", "msf1"}
+
+// Detect recognises an accepted format from the first bytes of data,
+// ignoring the file name and declared content type. It returns "" for
+// anything else.
+func Detect(data []byte) Format {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8, 0xFF}):
+		return FormatJPEG
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return FormatPNG
+	case len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return FormatWebP
+	case len(data) >= 12 && string(data[4:8]) == "ftyp":
+		brand := string(data[8:12])
+		for _, known := range heifBrands {
+			if brand == known {
+				return FormatHEIC
+			}
+		}
+	}
+	return ""
+}
+
+// Formats lists the upload formats the processor accepts; HEIC needs a converter.
+func (p *Processor) Formats() []Format {
+	formats := []Format{FormatJPEG, FormatPNG, FormatWebP}
+	if len(strings.Fields(p.opts.HEICConverter)) > 0 {
+		formats = append(formats, FormatHEIC)
+	}
+	return formats
+}
+
+// convertHEIC turns a HEIC photo into JPEG with the configured converter,
+// run as "<converter> <input> <output>" (heif-convert and ImageMagick's
+// magick both take that form).
+func (p *Processor) convertHEIC(ctx context.Context, data []byte) ([]byte, error) {
+	args := strings.Fields(p.opts.HEICConverter)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%w: HEIC conversion is not enabled on this server", ErrUnsupportedFormat)
+	}
+
+	dir, err := os.MkdirTemp("", "heic-")
+	if err != nil {
+		return nil, fmt.Errorf("create conversion directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	input, output := filepath.Join(dir, "input.heic"), filepath.Join(dir, "output.jpg")
+	if err := os.WriteFile(input, data, 0o600); err != nil {
+		return nil, fmt.Errorf("write HEIC upload: %w", err)
+	}
+	cmd := exec.CommandContext(ctx, args[0], append(args[1:], input, output)...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if detail := strings.TrimSpace(string(out)); detail != "" {
+			err = fmt.Errorf("%v: %s", err, detail)
+		}
+		return nil, fmt.Errorf("%w: convert HEIC: %v", ErrUndecodable, err)
+	}
+	converted, err := os.ReadFile(output)
+	if err != nil {
+		return nil, fmt.Errorf("%w: read converted HEIC: %v", ErrUndecodable, err)
+	}
+	return converted, nil
+}
@@ -0,0 +1,199 @@
+// Package imaging normalises face photos before they are sent to FR Core.
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	// PNG and WebP uploads are decoded alongside JPEG.
+	_ "image/png"
+	"path/filepath"
+	"strings"
+
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const defaultQuality = 85
+
+// ErrUndecodable reports a photo in an accepted format that could not be decoded or converted.
+var ErrUndecodable = errors.New("image could not be decoded")
+
+// Options sizes and encodes processed photos.
+type Options struct {
+	// MaxDimension caps the longer side in pixels; 0 keeps the original size.
+	MaxDimension int
+	// Quality is the JPEG quality, 1 to 100.
+	Quality int
+	// HEICConverter is the command converting HEIC to JPEG; empty rejects HEIC uploads.
+	HEICConverter string
+}
+
+// Processor turns uploaded photos into upright, bounded, metadata-free JPEGs.
+type Processor struct {
+	opts Options
+}
+
+// NewProcessor applies defaults to opts.
+func NewProcessor(opts Options) *Processor {
+	if opts.Quality < 1 || opts.Quality > 100 {
+		opts.Quality = defaultQuality
+	}
+	if opts.MaxDimension < 0 {
+		opts.MaxDimension = 0
+	}
+	return &Processor{opts: opts}
+}
+
+// Process checks a photo is JPEG, PNG, HEIC or WebP, converting HEIC first,
+// then decodes it, downscales it to fit MaxDimension, turns it upright
+// according to its EXIF orientation and re-encodes it as JPEG. Re-encoding
+// drops EXIF and every other metadata block, including GPS coordinates.
+func (p *Processor) Process(ctx context.Context, data []byte) ([]byte, error) {
+	img, err := p.decode(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	return p.encode(img)
+}
+
+// Region is part of a photo, given as fractions of its width and height from
+// the top left corner.
+type Region struct {
+	Left, Top, Right, Bottom float64
+}
+
+// Crop processes a photo as Process does and keeps only region of it, such as
+// the portrait printed on an ID card.
+func (p *Processor) Crop(ctx context.Context, data []byte, region Region) ([]byte, error) {
+	if region.Left < 0 || region.Top < 0 || region.Right > 1 || region.Bottom > 1 || region.Left >= region.Right || region.Top >= region.Bottom {
+		return nil, fmt.Errorf("invalid crop region %+v", region)
+	}
+	img, err := p.decode(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	bounds := img.Bounds()
+	width, height := float64(bounds.Dx()), float64(bounds.Dy())
+	rect := image.Rect(
+		bounds.Min.X+int(region.Left*width), bounds.Min.Y+int(region.Top*height),
+		bounds.Min.X+int(region.Right*width), bounds.Min.Y+int(region.Bottom*height),
+	)
+	if rect.Empty() {
+		return nil, fmt.Errorf("%w: crop region is empty", ErrUndecodable)
+	}
+	return p.encode(img.SubImage(rect))
+}
+
+// decode turns a JPEG, PNG, HEIC or WebP photo into an upright image fitting MaxDimension.
+func (p *Processor) decode(ctx context.Context, data []byte) (*image.RGBA, error) {
+	orientation := 1
+	switch Detect(data) {
+	case FormatJPEG:
+		orientation = exifOrientation(data)
+	case FormatPNG, FormatWebP:
+	case FormatHEIC:
+		// The converter applies the HEIC rotation itself, so any EXIF copied into its output is ignored.
+		converted, err := p.convertHEIC(ctx, data)
+		if err != nil {
+			return nil, err
+		}
+		data = converted
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUndecodable, err)
+	}
+
+	// Scaling before rotating keeps the pixel shuffle on the smaller image.
+	return orient(p.fit(src), orientation), nil
+}
+
+func (p *Processor) encode(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: p.opts.Quality}); err != nil {
+		return nil, fmt.Errorf("encode jpeg: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// JPEGName gives an uploaded file name the .jpg extension of the processed photo.
+func JPEGName(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ""
+	}
+	return strings.TrimSuffix(name, filepath.Ext(name)) + ".jpg"
+}
+
+// fit copies src onto a white RGBA canvas, so transparent PNG areas do not
+// turn black in the JPEG, scaling it down when it exceeds MaxDimension.
+func (p *Processor) fit(src image.Image) *image.RGBA {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if longest := max(width, height); p.opts.MaxDimension > 0 && longest > p.opts.MaxDimension {
+		scale := float64(p.opts.MaxDimension) / float64(longest)
+		width = max(1, int(float64(width)*scale+0.5))
+		height = max(1, int(float64(height)*scale+0.5))
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	op := draw.Src
+	if opaque, ok := src.(interface{ Opaque() bool }); !ok || !opaque.Opaque() {
+		draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+		op = draw.Over
+	}
+	if width == bounds.Dx() && height == bounds.Dy() {
+		draw.Draw(dst, dst.Bounds(), src, bounds.Min, op)
+		return dst
+	}
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, op, nil)
+	return dst
+}
+
+// orient applies an EXIF orientation (1 to 8) so the photo displays upright.
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirrored
+				dx, dy = width-1-x, y
+			case 3: // upside down
+				dx, dy = width-1-x, height-1-y
+			case 4: // mirrored upside down
+				dx, dy = x, height-1-y
+			case 5: // mirrored along the top-left diagonal
+				dx, dy = y, x
+			case 6: // needs a quarter turn clockwise
+				dx, dy = height-1-y, x
+			case 7: // mirrored along the top-right diagonal
+				dx, dy = height-1-y, width-1-x
+			case 8: // needs a quarter turn counter-clockwise
+				dx, dy = y, width-1-x
+			}
+			from := src.PixOffset(bounds.Min.X+x, bounds.Min.Y+y)
+			to := dst.PixOffset(dx, dy)
+			copy(dst.Pix[to:to+4], src.Pix[from:from+4])
+		}
+	}
+	return dst
+}
@@ -0,0 +1,100 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"math"
+	"sort"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+const (
+	// phashSize is the side of the grayscale thumbnail transformed by the DCT.
+	phashSize = 32
+	// phashBits is the side of the low-frequency block kept for the hash.
+	phashBits = 8
+)
+
+// PHash computes the 64-bit DCT perceptual hash of a photo as 16 hex digits.
+// Recompressing or resizing a photo leaves its hash (nearly) unchanged, so
+// the same selfie submitted twice hashes alike even when re-encoded.
+func PHash(data []byte) (string, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUndecodable, err)
+	}
+
+	thumb := image.NewGray(image.Rect(0, 0, phashSize, phashSize))
+	xdraw.ApproxBiLinear.Scale(thumb, thumb.Bounds(), src, src.Bounds(), xdraw.Src, nil)
+
+	pixels := make([][]float64, phashSize)
+	for y := range pixels {
+		pixels[y] = make([]float64, phashSize)
+		for x := range pixels[y] {
+			pixels[y][x] = float64(thumb.GrayAt(x, y).Y)
+		}
+	}
+	coefficients := dct2(pixels)
+
+	// The top-left block holds the low frequencies; the DC term only tracks
+	// overall brightness, so it stays out of the median.
+	low := make([]float64, 0, phashBits*phashBits)
+	for y := 0; y < phashBits; y++ {
+		low = append(low, coefficients[y][:phashBits]...)
+	}
+	sorted := append([]float64(nil), low[1:]...)
+	sort.Float64s(sorted)
+	median := sorted[len(sorted)/2]
+
+	var hash uint64
+	for i, value := range low {
+		if value > median {
+			hash |= 1 << uint(len(low)-1-i)
+		}
+	}
+	return fmt.Sprintf("%016x", hash), nil
+}
+
+// dct2 is the two-dimensional type-II discrete cosine transform of a square matrix.
+func dct2(matrix [][]float64) [][]float64 {
+	n := len(matrix)
+	cosines := make([][]float64, n)
+	for k := range cosines {
+		cosines[k] = make([]float64, n)
+		for i := range cosines[k] {
+			cosines[k][i] = math.Cos(math.Pi / float64(n) * (float64(i) + 0.5) * float64(k))
+		}
+	}
+	transform := func(values []float64) []float64 {
+		out := make([]float64, n)
+		for k := range out {
+			var sum float64
+			for i, value := range values {
+				sum += value * cosines[k][i]
+			}
+			out[k] = sum
+		}
+		return out
+	}
+
+	rows := make([][]float64, n)
+	for y, row := range matrix {
+		rows[y] = transform(row)
+	}
+	out := make([][]float64, n)
+	for y := range out {
+		out[y] = make([]float64, n)
+	}
+	column := make([]float64, n)
+	for x := 0; x < n; x++ {
+		for y := 0; y < n; y++ {
+			column[y] = rows[y][x]
+		}
+		for y, value := range transform(column) {
+			out[y][x] = value
+		}
+	}
+	return out
+}
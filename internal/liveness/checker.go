@@ -4,9 +4,29 @@ import (
 	"context"
 )
 
+// Actions a participant can be challenged to perform on camera.
+const (
+	ActionBlink     = "blink"
+	ActionSmile     = "smile"
+	ActionTurnLeft  = "turn_left"
+	ActionTurnRight = "turn_right"
+)
+
+// Actions lists every challenge action.
+var Actions = []string{ActionBlink, ActionSmile, ActionTurnLeft, ActionTurnRight}
+
+// Challenge is what a participant was asked to do for one verification
+// attempt. Providers that support challenges check the capture shows the
+// action and carries the nonce, so a capture cannot be reused for another attempt.
+type Challenge struct {
+	Action string `json:"action"`
+	Nonce  string `json:"nonce"`
+}
+
 // Checker defines the behaviour for liveness detection providers.
 type Checker interface {
-	Evaluate(ctx context.Context, image []byte) (passed bool, reason string, err error)
+	// Evaluate checks the image; challenge is nil for attempts made without a session.
+	Evaluate(ctx context.Context, image []byte, challenge *Challenge) (passed bool, reason string, err error)
 }
 
 // NoopChecker is a simple implementation that always returns success.
@@ -15,7 +35,7 @@ type NoopChecker struct {
 }
 
 // Evaluate returns true when enabled or signals REVIEW when disabled.
-func (n NoopChecker) Evaluate(_ context.Context, _ []byte) (bool, string, error) {
+func (n NoopChecker) Evaluate(_ context.Context, _ []byte, _ *Challenge) (bool, string, error) {
 	if !n.Enabled {
 		return false, "liveness_disabled", nil
 	}
@@ -0,0 +1,155 @@
+package loadtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+)
+
+// Client calls the service's REST API with Basic Auth.
+type Client struct {
+	BaseURL  string
+	Username string
+	Password string
+	HTTP     *http.Client
+}
+
+// Participant is a registered participant the load is spread over.
+type Participant struct {
+	ID string
+	// Label is the participant's FR Core label; the mock FR Core recognises a
+	// selfie uploaded as <label>.jpg as this participant.
+	Label string
+}
+
+// VerifyResult is the service's answer to one verification.
+type VerifyResult struct {
+	StatusCode int
+	// Status is the verification_status of a successful call.
+	Status string
+	// Code is the error code of a failed call, when the service gave one.
+	Code string
+}
+
+// envelope is the response wrapper shared by every endpoint.
+type envelope struct {
+	Status  string          `json:"status"`
+	Code    string          `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// RecordConsent accepts the active biometric terms for nik, as a kiosk would.
+func (c *Client) RecordConsent(ctx context.Context, nik string) error {
+	body, err := json.Marshal(map[string]string{"nik": nik, "channel": "KIOSK", "evidence": "load test"})
+	if err != nil {
+		return err
+	}
+	statusCode, env, err := c.do(ctx, "/consents", "application/json", body)
+	if err != nil {
+		return err
+	}
+	if statusCode != http.StatusCreated {
+		return fmt.Errorf("record consent: status=%d %s", statusCode, env.Message)
+	}
+	return nil
+}
+
+// Register enrolls a participant with the given photo.
+func (c *Client) Register(ctx context.Context, nik, name string, image []byte) (Participant, error) {
+	contentType, body, err := multipartBody(map[string]string{"nik": nik, "name": name}, "registration.jpg", image)
+	if err != nil {
+		return Participant{}, err
+	}
+	statusCode, env, err := c.do(ctx, "/participants/register", contentType, body)
+	if err != nil {
+		return Participant{}, err
+	}
+	if statusCode != http.StatusCreated {
+		return Participant{}, fmt.Errorf("register participant: status=%d %s", statusCode, env.Message)
+	}
+	var data struct {
+		ParticipantID string `json:"participant_id"`
+		FRRef         string `json:"fr_ref"`
+	}
+	if err := json.Unmarshal(env.Data, &data); err != nil {
+		return Participant{}, fmt.Errorf("decode registration: %w", err)
+	}
+	return Participant{ID: data.ParticipantID, Label: data.FRRef}, nil
+}
+
+// Verify submits a selfie for the participant. Only transport failures are
+// returned as errors; refusals are reported in the result.
+func (c *Client) Verify(ctx context.Context, participant Participant, image []byte) (VerifyResult, error) {
+	contentType, body, err := multipartBody(map[string]string{"participant_id": participant.ID}, participant.Label+".jpg", image)
+	if err != nil {
+		return VerifyResult{}, err
+	}
+	statusCode, env, err := c.do(ctx, "/life-certificate/verify", contentType, body)
+	if err != nil {
+		return VerifyResult{}, err
+	}
+	result := VerifyResult{StatusCode: statusCode, Code: env.Code}
+	if statusCode == http.StatusOK {
+		var data struct {
+			Status string `json:"verification_status"`
+		}
+		if err := json.Unmarshal(env.Data, &data); err == nil {
+			result.Status = data.Status
+		}
+	}
+	return result, nil
+}
+
+func (c *Client) do(ctx context.Context, path, contentType string, body []byte) (int, envelope, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(c.BaseURL, "/")+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, envelope{}, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.SetBasicAuth(c.Username, c.Password)
+
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, envelope{}, fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+	payload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, envelope{}, fmt.Errorf("read response body: %w", err)
+	}
+	var env envelope
+	// Errors outside the API, such as a proxy's, are not JSON; the status code still counts.
+	_ = json.Unmarshal(payload, &env)
+	return resp.StatusCode, env, nil
+}
+
+func multipartBody(fields map[string]string, filename string, image []byte) (string, []byte, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			return "", nil, fmt.Errorf("write %s field: %w", name, err)
+		}
+	}
+	part, err := writer.CreateFormFile("image", filename)
+	if err != nil {
+		return "", nil, fmt.Errorf("create form file: %w", err)
+	}
+	if _, err := part.Write(image); err != nil {
+		return "", nil, fmt.Errorf("write image: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", nil, fmt.Errorf("close multipart writer: %w", err)
+	}
+	return writer.FormDataContentType(), buf.Bytes(), nil
+}
@@ -0,0 +1,76 @@
+// Package loadtest drives the verify endpoint of a running service and
+// reports its latency, for cmd/loadgen and the verify benchmarks.
+package loadtest
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// grid is how many tone cells a photo has across and down.
+const grid = 8
+
+// Size is a photo size in pixels.
+type Size struct {
+	Width  int
+	Height int
+}
+
+func (s Size) String() string {
+	return fmt.Sprintf("%dx%d", s.Width, s.Height)
+}
+
+// ParseSizes reads a comma-separated list such as 640x480,3024x4032.
+func ParseSizes(value string) ([]Size, error) {
+	var sizes []Size
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		width, height, ok := strings.Cut(item, "x")
+		w, werr := strconv.Atoi(width)
+		h, herr := strconv.Atoi(height)
+		if !ok || werr != nil || herr != nil || w < grid || h < grid {
+			return nil, fmt.Errorf("invalid image size %q, want WIDTHxHEIGHT of at least %dx%d", item, grid, grid)
+		}
+		sizes = append(sizes, Size{Width: w, Height: h})
+	}
+	if len(sizes) == 0 {
+		return nil, fmt.Errorf("no image sizes given")
+	}
+	return sizes, nil
+}
+
+// Selfie draws a JPEG photo of the given size. A coarse grid of random tones
+// gives every photo its own perceptual hash, so the service never takes one
+// for a replay, and fine noise keeps the file about as large as a camera's.
+func Selfie(rng *rand.Rand, size Size) ([]byte, error) {
+	var tones [grid][grid]int
+	for y := range tones {
+		for x := range tones[y] {
+			tones[y][x] = 40 + rng.Intn(176)
+		}
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, size.Width, size.Height))
+	for y := 0; y < size.Height; y++ {
+		row := tones[y*grid/size.Height]
+		for x := 0; x < size.Width; x++ {
+			tone := row[x*grid/size.Width] + rng.Intn(25) - 12
+			img.SetRGBA(x, y, color.RGBA{R: uint8(tone), G: uint8(tone * 9 / 10), B: uint8(tone * 8 / 10), A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90}); err != nil {
+		return nil, fmt.Errorf("encode selfie: %w", err)
+	}
+	return buf.Bytes(), nil
+}
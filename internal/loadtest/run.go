@@ -0,0 +1,137 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Options shapes a load run.
+type Options struct {
+	// Concurrency is how many verifications are in flight at once.
+	Concurrency int
+	// Requests is how many verifications are sent per image size.
+	Requests int
+	Sizes    []Size
+	// Seed makes the generated photos reproducible.
+	Seed int64
+}
+
+// Result is the outcome of the verifications sent with one image size.
+type Result struct {
+	Size    Size
+	Elapsed time.Duration
+	// Latencies holds the successful verifications only, so fast refusals do
+	// not flatter the percentiles.
+	Latencies *Latencies
+	// Outcomes counts verification statuses such as VALID, and failures by
+	// HTTP status and code, e.g. "503 FRCORE_BUSY".
+	Outcomes map[string]int
+	Failures int
+}
+
+// Throughput is the successful verifications per second.
+func (r Result) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Latencies.Count()) / r.Elapsed.Seconds()
+}
+
+// Setup registers n participants under fresh NIKs to spread the load over,
+// recording their consent first when consent is set.
+func Setup(ctx context.Context, client *Client, n int, consent bool, seed int64) ([]Participant, error) {
+	rng := rand.New(rand.NewSource(seed))
+	participants := make([]Participant, 0, n)
+	for i := 0; i < n; i++ {
+		nik := fmt.Sprintf("99%014d", rng.Int63n(1e14))
+		if consent {
+			if err := client.RecordConsent(ctx, nik); err != nil {
+				return nil, err
+			}
+		}
+		image, err := Selfie(rng, Size{Width: 640, Height: 480})
+		if err != nil {
+			return nil, err
+		}
+		participant, err := client.Register(ctx, nik, fmt.Sprintf("Load Test %d", i+1), image)
+		if err != nil {
+			return nil, err
+		}
+		participants = append(participants, participant)
+	}
+	return participants, nil
+}
+
+// Run sends opts.Requests verifications for each image size in turn, spread
+// round robin over participants. Each photo is drawn before its request is
+// timed.
+func Run(ctx context.Context, client *Client, participants []Participant, opts Options) ([]Result, error) {
+	if len(participants) == 0 {
+		return nil, fmt.Errorf("no participants to verify")
+	}
+	concurrency := max(opts.Concurrency, 1)
+	results := make([]Result, 0, len(opts.Sizes))
+	for i, size := range opts.Sizes {
+		result := Result{Size: size, Latencies: &Latencies{}, Outcomes: make(map[string]int)}
+		var (
+			mu   sync.Mutex
+			next atomic.Int64
+			wg   sync.WaitGroup
+		)
+		start := time.Now()
+		for worker := 0; worker < concurrency; worker++ {
+			rng := rand.New(rand.NewSource(opts.Seed + int64(i*concurrency+worker)))
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					n := next.Add(1) - 1
+					if n >= int64(opts.Requests) || ctx.Err() != nil {
+						return
+					}
+					outcome, latency, ok := verifyOnce(ctx, client, participants[n%int64(len(participants))], rng, size)
+					mu.Lock()
+					result.Outcomes[outcome]++
+					if ok {
+						result.Latencies.Add(latency)
+					} else {
+						result.Failures++
+					}
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+		result.Elapsed = time.Since(start)
+		results = append(results, result)
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+func verifyOnce(ctx context.Context, client *Client, participant Participant, rng *rand.Rand, size Size) (string, time.Duration, bool) {
+	image, err := Selfie(rng, size)
+	if err != nil {
+		return "photo error", 0, false
+	}
+	start := time.Now()
+	result, err := client.Verify(ctx, participant, image)
+	latency := time.Since(start)
+	switch {
+	case err != nil:
+		return "transport error", latency, false
+	case result.StatusCode == http.StatusOK:
+		return result.Status, latency, true
+	case result.Code != "":
+		return fmt.Sprintf("%d %s", result.StatusCode, result.Code), latency, false
+	default:
+		return fmt.Sprintf("%d", result.StatusCode), latency, false
+	}
+}
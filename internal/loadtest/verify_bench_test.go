@@ -0,0 +1,112 @@
+//go:build bench
+
+package loadtest
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"life-certificates/internal/frcore"
+	"life-certificates/internal/frcore/frcoretest"
+	"life-certificates/internal/imaging"
+)
+
+// benchSizes are a kiosk webcam, a downscaled upload and a phone camera photo.
+var benchSizes = []Size{{640, 480}, {1280, 960}, {3024, 4032}}
+
+// BenchmarkVerifyPipeline measures the in-process part of a verification:
+// normalising the photo, hashing it and sending it to a mock FR Core with no
+// added latency. It needs no database.
+func BenchmarkVerifyPipeline(b *testing.B) {
+	mock := httptest.NewServer(frcoretest.NewServer(frcoretest.Options{}))
+	defer mock.Close()
+	client, err := frcore.NewHTTPClient(frcore.Options{BaseURL: mock.URL, LogLevel: frcore.LogNone})
+	if err != nil {
+		b.Fatal(err)
+	}
+	processor := imaging.NewProcessor(imaging.Options{MaxDimension: 1600, Quality: 85})
+	ctx := context.Background()
+
+	for _, size := range benchSizes {
+		b.Run(size.String(), func(b *testing.B) {
+			photo, err := Selfie(rand.New(rand.NewSource(1)), size)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.SetBytes(int64(len(photo)))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				processed, err := processor.Process(ctx, photo)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if _, err := imaging.PHash(processed); err != nil {
+					b.Fatal(err)
+				}
+				if _, err := client.Recognize(ctx, frcore.RecognizeRequest{ImageName: "selfie.jpg", Image: processed}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkVerify drives POST /life-certificate/verify of the service at
+// LOADTEST_URL, authenticated as LOADTEST_USERNAME and LOADTEST_PASSWORD, with
+// LOADTEST_CONCURRENCY requests in flight (default 8). The service should
+// point FRCORE_BASE_URL at a mock FR Core, e.g. cmd/loadgen -mock-frcore.
+// Each operation includes drawing its photo; the p50, p95 and p99 metrics
+// time the requests alone.
+func BenchmarkVerify(b *testing.B) {
+	baseURL := os.Getenv("LOADTEST_URL")
+	if baseURL == "" {
+		b.Skip("LOADTEST_URL is not set")
+	}
+	concurrency := 8
+	if raw := os.Getenv("LOADTEST_CONCURRENCY"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			b.Fatalf("invalid LOADTEST_CONCURRENCY %q", raw)
+		}
+		concurrency = n
+	}
+	client := &Client{
+		BaseURL:  baseURL,
+		Username: os.Getenv("LOADTEST_USERNAME"),
+		Password: os.Getenv("LOADTEST_PASSWORD"),
+		HTTP:     &http.Client{Timeout: time.Minute, Transport: &http.Transport{MaxIdleConnsPerHost: concurrency}},
+	}
+	ctx := context.Background()
+	participants, err := Setup(ctx, client, concurrency, true, time.Now().UnixNano())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, size := range benchSizes {
+		b.Run(size.String(), func(b *testing.B) {
+			results, err := Run(ctx, client, participants, Options{
+				Concurrency: concurrency,
+				Requests:    b.N,
+				Sizes:       []Size{size},
+				Seed:        time.Now().UnixNano(),
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+			result := results[0]
+			if result.Failures > 0 {
+				b.Errorf("%d of %d verifications failed: %v", result.Failures, b.N, result.Outcomes)
+			}
+			b.ReportMetric(result.Throughput(), "req/s")
+			for _, p := range []float64{50, 95, 99} {
+				b.ReportMetric(float64(result.Latencies.Percentile(p))/float64(time.Millisecond), "p"+strconv.Itoa(int(p))+"-ms")
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"life-certificates/internal/frcore"
+)
+
+// InstrumentFRCore wraps an FR Core client to record call latency and errors.
+func InstrumentFRCore(next frcore.Client) frcore.Client {
+	return &instrumentedFRCore{next: next}
+}
+
+type instrumentedFRCore struct {
+	next frcore.Client
+}
+
+func (c *instrumentedFRCore) UploadFace(ctx context.Context, req frcore.UploadRequest) (*frcore.UploadResponse, error) {
+	start := time.Now()
+	resp, err := c.next.UploadFace(ctx, req)
+	ObserveFRCoreCall(ctx, "upload", time.Since(start), err)
+	return resp, err
+}
+
+func (c *instrumentedFRCore) Recognize(ctx context.Context, req frcore.RecognizeRequest) (*frcore.RecognizeResponse, error) {
+	start := time.Now()
+	resp, err := c.next.Recognize(ctx, req)
+	ObserveFRCoreCall(ctx, "recognize", time.Since(start), err)
+	return resp, err
+}
+
+func (c *instrumentedFRCore) AssessQuality(ctx context.Context, req frcore.QualityRequest) (*frcore.QualityResponse, error) {
+	start := time.Now()
+	resp, err := c.next.AssessQuality(ctx, req)
+	ObserveFRCoreCall(ctx, "quality", time.Since(start), err)
+	return resp, err
+}
+
+func (c *instrumentedFRCore) Compare(ctx context.Context, req frcore.CompareRequest) (*frcore.CompareResponse, error) {
+	start := time.Now()
+	resp, err := c.next.Compare(ctx, req)
+	ObserveFRCoreCall(ctx, "compare", time.Since(start), err)
+	return resp, err
+}
+
+func (c *instrumentedFRCore) ListFaces(ctx context.Context) ([]frcore.Face, error) {
+	start := time.Now()
+	faces, err := c.next.ListFaces(ctx)
+	ObserveFRCoreCall(ctx, "list_faces", time.Since(start), err)
+	return faces, err
+}
+
+func (c *instrumentedFRCore) DeleteFace(ctx context.Context, label string) error {
+	start := time.Now()
+	err := c.next.DeleteFace(ctx, label)
+	ObserveFRCoreCall(ctx, "delete_face", time.Since(start), err)
+	return err
+}
+
+func (c *instrumentedFRCore) Ping(ctx context.Context) error {
+	start := time.Now()
+	err := c.next.Ping(ctx)
+	ObserveFRCoreCall(ctx, "ping", time.Since(start), err)
+	return err
+}
+
+var (
+	frcoreQueueWait = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "frcore_queue_wait_seconds",
+		Help:      "Time FR Core calls waited for a concurrency slot, by operation.",
+		Buckets:   []float64{0.001, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10},
+	}, []string{"operation"})
+
+	frcoreRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "frcore_rejected_total",
+		Help:      "FR Core calls refused because the queue was full or the wait timed out, by operation.",
+	}, []string{"operation"})
+)
+
+// ObserveFRCoreQueueWait records how long a call waited for a slot; calls
+// refused as overloaded are counted separately.
+func ObserveFRCoreQueueWait(operation string, waited time.Duration, err error) {
+	frcoreQueueWait.WithLabelValues(operation).Observe(waited.Seconds())
+	if errors.Is(err, frcore.ErrOverloaded) {
+		frcoreRejected.WithLabelValues(operation).Inc()
+	}
+}
+
+// RegisterFRCoreQueue exports the FR Core calls in flight and queued, read from source on every scrape.
+func RegisterFRCoreQueue(source func() []frcore.QueueStats) {
+	prometheus.MustRegister(&frcoreQueueCollector{
+		source: source,
+		inFlight: prometheus.NewDesc(prometheus.BuildFQName(namespace, "frcore", "in_flight"),
+			"FR Core calls in progress, by operation.", []string{"operation"}, nil),
+		queued: prometheus.NewDesc(prometheus.BuildFQName(namespace, "frcore", "queued"),
+			"FR Core calls waiting for a concurrency slot, by operation.", []string{"operation"}, nil),
+	})
+}
+
+type frcoreQueueCollector struct {
+	source   func() []frcore.QueueStats
+	inFlight *prometheus.Desc
+	queued   *prometheus.Desc
+}
+
+func (c *frcoreQueueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.inFlight
+	ch <- c.queued
+}
+
+func (c *frcoreQueueCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, stats := range c.source() {
+		ch <- prometheus.MustNewConstMetric(c.inFlight, prometheus.GaugeValue, float64(stats.InFlight), stats.Operation)
+		ch <- prometheus.MustNewConstMetric(c.queued, prometheus.GaugeValue, float64(stats.Queued), stats.Operation)
+	}
+}
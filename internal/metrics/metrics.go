@@ -0,0 +1,238 @@
+// Package metrics defines the Prometheus metrics exported on /metrics.
+package metrics
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"life-certificates/internal/tracing"
+)
+
+const namespace = "lcs"
+
+// Plain totals mirror the FR Core series so alerting can read them without a scrape.
+var frcoreCalls, frcoreFailures atomic.Uint64
+
+var (
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route pattern and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	frcoreRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "frcore_request_duration_seconds",
+		Help:      "FR Core call latency by operation.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10},
+	}, []string{"operation"})
+
+	frcoreErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "frcore_errors_total",
+		Help:      "FR Core calls that returned an error, by operation.",
+	}, []string{"operation"})
+
+	verificationOutcomes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "verification_outcomes_total",
+		Help:      "Recorded verification outcomes by status and method.",
+	}, []string{"status", "method"})
+
+	livenessChecks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "liveness_checks_total",
+		Help:      "Liveness evaluations by result (pass or fail); the pass rate is pass over the total.",
+	}, []string{"result"})
+
+	selfieReplays = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "selfie_replays_total",
+		Help:      "Verification selfies whose perceptual hash matched an earlier submission.",
+	})
+
+	fraudRisks = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "fraud_risk_reviews_total",
+		Help:      "Verification attempts sent to review because their fraud risk score reached the review score.",
+	})
+
+	facialConflicts = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "facial_conflicts_total",
+		Help:      "Verification attempts whose selfie matched another participant's enrolled face.",
+	})
+
+	verificationLocks = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "verification_locks_total",
+		Help:      "Participants locked out of automatic verification after repeated INVALID attempts.",
+	})
+
+	dryRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "verification_dry_runs_total",
+		Help:      "Practice verification attempts decided without being recorded, by would-be status.",
+	}, []string{"status"})
+
+	stepUps = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "step_ups_total",
+		Help:      "Verification attempts asked for a step-up challenge, by gray zone (risk_score, similarity or distance).",
+	}, []string{"reason"})
+
+	uploadScans = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upload_scans_total",
+		Help:      "Malware scans of uploads by result (clean, infected or error).",
+	}, []string{"result"})
+
+	cacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_lookups_total",
+		Help:      "Cached lookups by cache and result (hit or miss).",
+	}, []string{"cache", "result"})
+)
+
+// Handler serves the default registry, which includes the Go runtime and process collectors.
+// OpenMetrics is negotiated so scrapers can read trace exemplars.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{EnableOpenMetrics: true})
+}
+
+// ObserveHTTPRequest records one served HTTP request.
+func ObserveHTTPRequest(ctx context.Context, method, route string, status int, elapsed time.Duration) {
+	observe(ctx, httpRequestDuration.WithLabelValues(method, route, strconv.Itoa(status)), elapsed)
+}
+
+// ObserveFRCoreCall records the latency and outcome of one FR Core call.
+func ObserveFRCoreCall(ctx context.Context, operation string, elapsed time.Duration, err error) {
+	observe(ctx, frcoreRequestDuration.WithLabelValues(operation), elapsed)
+	frcoreCalls.Add(1)
+	if err != nil {
+		frcoreErrors.WithLabelValues(operation).Inc()
+		frcoreFailures.Add(1)
+	}
+}
+
+// FRCoreTotals returns the FR Core calls and failures seen since start, for in-process alerting.
+func FRCoreTotals() (calls, failures uint64) {
+	return frcoreCalls.Load(), frcoreFailures.Load()
+}
+
+// observe attaches the trace ID as an exemplar so a slow bucket links to its trace.
+func observe(ctx context.Context, observer prometheus.Observer, elapsed time.Duration) {
+	if traceID := tracing.TraceID(ctx); traceID != "" {
+		if exemplar, ok := observer.(prometheus.ExemplarObserver); ok {
+			exemplar.ObserveWithExemplar(elapsed.Seconds(), prometheus.Labels{"trace_id": traceID})
+			return
+		}
+	}
+	observer.Observe(elapsed.Seconds())
+}
+
+// VerificationRecorded counts a persisted verification outcome.
+func VerificationRecorded(status, method string) {
+	verificationOutcomes.WithLabelValues(status, method).Inc()
+}
+
+// VerificationDryRun counts a practice attempt's would-be outcome.
+func VerificationDryRun(status string) {
+	dryRuns.WithLabelValues(status).Inc()
+}
+
+// LivenessEvaluated counts a liveness check result.
+func LivenessEvaluated(passed bool) {
+	result := "fail"
+	if passed {
+		result = "pass"
+	}
+	livenessChecks.WithLabelValues(result).Inc()
+}
+
+// SelfieReplayDetected counts a selfie routed to review as a possible replay.
+func SelfieReplayDetected() {
+	selfieReplays.Inc()
+}
+
+// FraudRiskDetected counts an attempt routed to review for its fraud risk score.
+func FraudRiskDetected() {
+	fraudRisks.Inc()
+}
+
+// FacialConflictDetected counts an attempt matched to another participant's face.
+func FacialConflictDetected() {
+	facialConflicts.Inc()
+}
+
+// VerificationLocked counts a participant locked after repeated INVALID attempts.
+func VerificationLocked() {
+	verificationLocks.Inc()
+}
+
+// StepUpRequired counts an attempt asked for a step-up challenge for each gray zone it fell in.
+func StepUpRequired(reasons []string) {
+	for _, reason := range reasons {
+		stepUps.WithLabelValues(reason).Inc()
+	}
+}
+
+// UploadScanned counts a malware scan of an upload.
+func UploadScanned(result string) {
+	uploadScans.WithLabelValues(result).Inc()
+}
+
+// CacheLookup counts a cached lookup and whether it was served from the cache.
+func CacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheLookups.WithLabelValues(cache, result).Inc()
+}
+
+// ReviewQueueSource reports the pending and overdue review counts.
+type ReviewQueueSource func(ctx context.Context) (pending, overdue int64, err error)
+
+// RegisterReviewQueue exports the review queue depth, read from source on every scrape.
+func RegisterReviewQueue(source ReviewQueueSource) {
+	prometheus.MustRegister(&reviewQueueCollector{
+		source: source,
+		pending: prometheus.NewDesc(prometheus.BuildFQName(namespace, "review", "queue_depth"),
+			"REVIEW attempts awaiting a decision.", nil, nil),
+		overdue: prometheus.NewDesc(prometheus.BuildFQName(namespace, "review", "overdue"),
+			"REVIEW attempts past their SLA due date.", nil, nil),
+	})
+}
+
+type reviewQueueCollector struct {
+	source  ReviewQueueSource
+	pending *prometheus.Desc
+	overdue *prometheus.Desc
+}
+
+func (c *reviewQueueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.pending
+	ch <- c.overdue
+}
+
+func (c *reviewQueueCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	pending, overdue, err := c.source(ctx)
+	if err != nil {
+		log.Printf("[metrics] read review queue: %v", err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.pending, prometheus.GaugeValue, float64(pending))
+	ch <- prometheus.MustNewConstMetric(c.overdue, prometheus.GaugeValue, float64(overdue))
+}
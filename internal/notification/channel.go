@@ -0,0 +1,97 @@
+// Package notification renders and sends notifications to participants.
+package notification
+
+import (
+	"context"
+	"log"
+	"strings"
+)
+
+// Channel names, recorded on each delivery.
+const (
+	ChannelEmail    = "email"
+	ChannelSMS      = "sms"
+	ChannelWhatsApp = "whatsapp"
+	// ChannelPush goes to the participant's registered devices, in addition to
+	// the member's channel.
+	ChannelPush = "push"
+)
+
+// Channels lists the member channels in the order they are tried when a member
+// has no preference.
+var Channels = []string{ChannelEmail, ChannelSMS, ChannelWhatsApp}
+
+const responseBodyLimit = 1024
+
+// Message is one notification to one recipient: an email address for email,
+// an E.164 phone number for SMS and WhatsApp, a device token for push. Text
+// channels ignore the subject.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+	// Data is passed to the mobile app with push notifications.
+	Data map[string]string
+	// UnsubscribeURL lets the recipient stop notification emails.
+	UnsubscribeURL string
+	// From overrides the channel's configured sender: the email address, the
+	// SMS sender number or ID, or the WhatsApp phone number ID.
+	From string
+}
+
+// Channel delivers messages through one medium.
+type Channel interface {
+	Name() string
+	// Send returns the provider's ID for the message when it reports one.
+	Send(ctx context.Context, msg Message) (string, error)
+}
+
+// IsChannel reports whether name is a member channel name.
+func IsChannel(name string) bool {
+	for _, known := range Channels {
+		if known == name {
+			return true
+		}
+	}
+	return false
+}
+
+type dryRun struct {
+	Channel
+}
+
+// DryRun wraps a channel so messages are logged instead of sent.
+func DryRun(channel Channel) Channel {
+	return dryRun{Channel: channel}
+}
+
+func (d dryRun) Send(_ context.Context, msg Message) (string, error) {
+	log.Printf("notification dry run: channel=%s to=%s subject=%q body=%q", d.Name(), msg.To, msg.Subject, msg.Body)
+	return "", nil
+}
+
+// NormalizePhone turns a member's phone number into E.164 form, replacing a
+// national leading 0 with countryCode. It returns "" when nothing dialable is left.
+func NormalizePhone(number, countryCode string) string {
+	var digits strings.Builder
+	for _, r := range number {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	national := digits.String()
+	switch {
+	case national == "":
+		return ""
+	case strings.HasPrefix(strings.TrimSpace(number), "+"):
+		return "+" + national
+	case strings.HasPrefix(national, "00"):
+		return "+" + national[2:]
+	case strings.HasPrefix(national, "0"):
+		return "+" + countryCode + national[1:]
+	case strings.HasPrefix(national, countryCode):
+		return "+" + national
+	default:
+		return "+" + countryCode + national
+	}
+}
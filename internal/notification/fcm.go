@@ -0,0 +1,261 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	fcmBaseURL = "https://fcm.googleapis.com"
+	fcmScope   = "https://www.googleapis.com/auth/firebase.messaging"
+)
+
+// ErrInvalidToken reports that FCM no longer accepts a device token, e.g.
+// because the app was uninstalled; the device should stop being used.
+var ErrInvalidToken = errors.New("device token is no longer valid")
+
+// FCMOptions configures Firebase Cloud Messaging.
+type FCMOptions struct {
+	// CredentialsFile is a service account JSON key with the Firebase messaging role.
+	CredentialsFile string
+	// ProjectID defaults to the service account's project.
+	ProjectID string
+	// URL overrides the FCM API base URL.
+	URL     string
+	Timeout time.Duration
+}
+
+// serviceAccount holds the fields of a Google service account key used to obtain access tokens.
+type serviceAccount struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// FCMSender is the push channel, sending through the FCM HTTP v1 API.
+type FCMSender struct {
+	account    serviceAccount
+	key        *rsa.PrivateKey
+	endpoint   string
+	httpClient *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// NewFCMSender reads and validates the service account key.
+func NewFCMSender(opts FCMOptions) (*FCMSender, error) {
+	raw, err := os.ReadFile(opts.CredentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("read FCM credentials: %w", err)
+	}
+	var account serviceAccount
+	if err := json.Unmarshal(raw, &account); err != nil {
+		return nil, fmt.Errorf("decode FCM credentials: %w", err)
+	}
+	if account.ClientEmail == "" || account.PrivateKey == "" || account.TokenURI == "" {
+		return nil, fmt.Errorf("FCM credentials must be a service account key")
+	}
+	block, _ := pem.Decode([]byte(account.PrivateKey))
+	if block == nil {
+		return nil, fmt.Errorf("FCM credentials private key is not PEM encoded")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse FCM private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("FCM private key must be RSA")
+	}
+
+	projectID := opts.ProjectID
+	if projectID == "" {
+		projectID = account.ProjectID
+	}
+	if projectID == "" {
+		return nil, fmt.Errorf("FCM project ID is required")
+	}
+	if opts.URL == "" {
+		opts.URL = fcmBaseURL
+	}
+	base, err := url.Parse(opts.URL)
+	if err != nil || base.Host == "" || (base.Scheme != "http" && base.Scheme != "https") {
+		return nil, fmt.Errorf("FCM URL must be an absolute http or https URL")
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	return &FCMSender{
+		account:    account,
+		key:        key,
+		endpoint:   strings.TrimRight(opts.URL, "/") + "/v1/projects/" + url.PathEscape(projectID) + "/messages:send",
+		httpClient: &http.Client{Timeout: opts.Timeout},
+	}, nil
+}
+
+// Name implements Channel.
+func (s *FCMSender) Name() string {
+	return ChannelPush
+}
+
+// Send pushes the message to one device token, returning the FCM message
+// name, or ErrInvalidToken when FCM reports the token unregistered or malformed.
+func (s *FCMSender) Send(ctx context.Context, msg Message) (string, error) {
+	accessToken, err := s.token(ctx)
+	if err != nil {
+		return "", err
+	}
+	message := map[string]interface{}{
+		"token":        msg.To,
+		"notification": map[string]string{"title": msg.Subject, "body": msg.Body},
+	}
+	if len(msg.Data) > 0 {
+		message["data"] = msg.Data
+	}
+	payload, err := json.Marshal(map[string]interface{}{"message": message})
+	if err != nil {
+		return "", fmt.Errorf("encode push message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, responseBodyLimit))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		var result struct {
+			Name string `json:"name"`
+		}
+		_ = json.Unmarshal(body, &result)
+		return result.Name, nil
+	}
+	if fcmTokenRejected(body) {
+		return "", fmt.Errorf("%w: %s", ErrInvalidToken, strings.TrimSpace(string(body)))
+	}
+	return "", fmt.Errorf("fcm returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+// fcmTokenRejected reports whether an FCM error response is about the device
+// token rather than the request or the service.
+func fcmTokenRejected(body []byte) bool {
+	var result struct {
+		Error struct {
+			Status  string `json:"status"`
+			Message string `json:"message"`
+			Details []struct {
+				ErrorCode string `json:"errorCode"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return false
+	}
+	for _, detail := range result.Error.Details {
+		switch detail.ErrorCode {
+		case "UNREGISTERED", "SENDER_ID_MISMATCH":
+			return true
+		case "INVALID_ARGUMENT":
+			return strings.Contains(result.Error.Message, "registration token")
+		}
+	}
+	return result.Error.Status == "NOT_FOUND"
+}
+
+// token returns a cached OAuth access token, exchanging a freshly signed
+// service account assertion when it is about to expire.
+func (s *FCMSender) token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.accessToken != "" && time.Now().Before(s.expiresAt) {
+		return s.accessToken, nil
+	}
+
+	assertion, err := s.assertion(time.Now())
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("build token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, responseBodyLimit*4))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("token endpoint returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil || result.AccessToken == "" {
+		return "", fmt.Errorf("decode access token response")
+	}
+	s.accessToken = result.AccessToken
+	// Refresh a minute early so a token never expires mid-request.
+	s.expiresAt = time.Now().Add(time.Duration(result.ExpiresIn)*time.Second - time.Minute)
+	return s.accessToken, nil
+}
+
+// assertion signs the RS256 JWT exchanged for an access token.
+func (s *FCMSender) assertion(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":   s.account.ClientEmail,
+		"scope": fcmScope,
+		"aud":   s.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("sign service account assertion: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
@@ -0,0 +1,91 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SMTPOptions configures the SMTP relay.
+type SMTPOptions struct {
+	// Addr is the SMTP relay as host:port.
+	Addr     string
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPMailer is the email channel, sending plain text email through an SMTP relay.
+type SMTPMailer struct {
+	opts SMTPOptions
+}
+
+// NewSMTPMailer validates the SMTP settings.
+func NewSMTPMailer(opts SMTPOptions) (*SMTPMailer, error) {
+	if _, _, err := net.SplitHostPort(opts.Addr); err != nil {
+		return nil, fmt.Errorf("SMTP address must be host:port: %w", err)
+	}
+	if opts.From == "" {
+		return nil, fmt.Errorf("email sender is required")
+	}
+	return &SMTPMailer{opts: opts}, nil
+}
+
+// Name implements Channel.
+func (m *SMTPMailer) Name() string {
+	return ChannelEmail
+}
+
+// Send mails msg as UTF-8 plain text, returning the Message-ID it was given.
+func (m *SMTPMailer) Send(_ context.Context, msg Message) (string, error) {
+	from := m.opts.From
+	if msg.From != "" {
+		from = msg.From
+	}
+	messageID := "<" + uuid.NewString() + "@" + senderDomain(from) + ">"
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: %s\r\n", messageID)
+	if msg.UnsubscribeURL != "" {
+		fmt.Fprintf(&buf, "List-Unsubscribe: <%s>\r\n", msg.UnsubscribeURL)
+		buf.WriteString("List-Unsubscribe-Post: List-Unsubscribe=One-Click\r\n")
+	}
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	buf.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	buf.WriteString("\r\n")
+	if msg.UnsubscribeURL != "" {
+		fmt.Fprintf(&buf, "\r\n-- \r\nUnsubscribe: %s\r\n", msg.UnsubscribeURL)
+	}
+
+	var auth smtp.Auth
+	if m.opts.Username != "" {
+		host, _, _ := net.SplitHostPort(m.opts.Addr)
+		auth = smtp.PlainAuth("", m.opts.Username, m.opts.Password, host)
+	}
+	if err := smtp.SendMail(m.opts.Addr, auth, from, []string{msg.To}, buf.Bytes()); err != nil {
+		return "", fmt.Errorf("send email: %w", err)
+	}
+	return messageID, nil
+}
+
+// senderDomain is the mail domain of from, used to make Message-IDs globally unique.
+func senderDomain(from string) string {
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if at := strings.LastIndex(addr.Address, "@"); at >= 0 {
+			return addr.Address[at+1:]
+		}
+	}
+	return "localhost"
+}
@@ -0,0 +1,224 @@
+package notification
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Template names, one per notification.
+const (
+	TemplateVerificationSuccess = "verification_success"
+	TemplateVerificationFailure = "verification_failure"
+	TemplateVerificationReview  = "verification_review"
+	TemplateReminder            = "reminder"
+	// TemplateOverdueWarning and TemplatePaymentHoldNotice are sent as a
+	// participant overdue in a campaign is escalated.
+	TemplateOverdueWarning    = "overdue_warning"
+	TemplatePaymentHoldNotice = "payment_hold_notice"
+	// TemplateOneTimeCode carries a self-service sign-in code; it is sent
+	// straight away and never written to the delivery log.
+	TemplateOneTimeCode = "one_time_code"
+)
+
+// Templates lists every template name.
+var Templates = []string{
+	TemplateVerificationSuccess,
+	TemplateVerificationFailure,
+	TemplateVerificationReview,
+	TemplateReminder,
+	TemplateOverdueWarning,
+	TemplatePaymentHoldNotice,
+	TemplateOneTimeCode,
+}
+
+// DefaultLanguage is the language of the base templates.
+const DefaultLanguage = "en"
+
+// Languages lists the languages with built-in templates. Templates in another
+// language than DefaultLanguage are named <name>.<language>, e.g. reminder.id.
+var Languages = []string{DefaultLanguage, "id"}
+
+// Where a template was loaded from.
+const (
+	SourceDatabase = "database"
+	SourceFile     = "file"
+	SourceBuiltin  = "builtin"
+)
+
+//go:embed templates/*.tmpl
+var builtin embed.FS
+
+// Template is the subject and body source of one notification.
+type Template struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+	Source  string `json:"source"`
+}
+
+// Data is what templates render: the recipient's name, the data of the
+// event that triggered the notification and the branding of the sender.
+type Data struct {
+	Name  string
+	Data  map[string]interface{}
+	Brand Brand
+}
+
+// Brand is how the pension fund sending a notification presents itself;
+// templates leave out what is empty.
+type Brand struct {
+	// DisplayName signs the message.
+	DisplayName string
+	// Footer closes the message, e.g. with contact details.
+	Footer string
+}
+
+// monthNames translates month names for languages other than English.
+var monthNames = map[string][]string{
+	"id": {"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"},
+}
+
+// funcs returns the template functions for a language.
+func funcs(language string) template.FuncMap {
+	format := func(t time.Time) string {
+		if months, ok := monthNames[language]; ok {
+			return fmt.Sprintf("%d %s %d", t.Day(), months[t.Month()-1], t.Year())
+		}
+		return t.Format("2 January 2006")
+	}
+	return template.FuncMap{
+		// date formats an RFC 3339 timestamp, as found in event data, as "2 January 2006".
+		"date": func(value interface{}) string {
+			switch v := value.(type) {
+			case time.Time:
+				return format(v)
+			case string:
+				if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+					return format(t)
+				}
+				return v
+			case nil:
+				return ""
+			default:
+				return fmt.Sprint(v)
+			}
+		},
+	}
+}
+
+// Variant names the template for a language, falling back to the base
+// template for DefaultLanguage and languages without templates.
+func Variant(name, language string) string {
+	if language == DefaultLanguage || !IsLanguage(language) {
+		return name
+	}
+	return name + "." + language
+}
+
+// Names lists every template in every language.
+func Names() []string {
+	var names []string
+	for _, language := range Languages {
+		for _, name := range Templates {
+			names = append(names, Variant(name, language))
+		}
+	}
+	return names
+}
+
+// IsLanguage reports whether language has templates.
+func IsLanguage(language string) bool {
+	for _, known := range Languages {
+		if known == language {
+			return true
+		}
+	}
+	return false
+}
+
+// IsKnown reports whether name is a template name, in any language.
+func IsKnown(name string) bool {
+	for _, known := range Names() {
+		if known == name {
+			return true
+		}
+	}
+	return false
+}
+
+// language is the language of a template, taken from its name.
+func (t Template) language() string {
+	if _, language, ok := strings.Cut(t.Name, "."); ok {
+		return language
+	}
+	return DefaultLanguage
+}
+
+// Load reads a template from dir, falling back to the built-in one. An empty
+// dir or a missing file uses the built-in template.
+func Load(dir, name string) (Template, error) {
+	if dir != "" {
+		subject, errSubject := os.ReadFile(filepath.Join(dir, name+".subject.tmpl"))
+		body, errBody := os.ReadFile(filepath.Join(dir, name+".body.tmpl"))
+		switch {
+		case errSubject == nil && errBody == nil:
+			return Template{Name: name, Subject: string(subject), Body: string(body), Source: SourceFile}, nil
+		case errSubject != nil && !errors.Is(errSubject, fs.ErrNotExist):
+			return Template{}, fmt.Errorf("read template %s: %w", name, errSubject)
+		case errBody != nil && !errors.Is(errBody, fs.ErrNotExist):
+			return Template{}, fmt.Errorf("read template %s: %w", name, errBody)
+		}
+	}
+	subject, err := builtin.ReadFile("templates/" + name + ".subject.tmpl")
+	if err != nil {
+		return Template{}, fmt.Errorf("unknown template %s", name)
+	}
+	body, err := builtin.ReadFile("templates/" + name + ".body.tmpl")
+	if err != nil {
+		return Template{}, fmt.Errorf("unknown template %s", name)
+	}
+	return Template{Name: name, Subject: string(subject), Body: string(body), Source: SourceBuiltin}, nil
+}
+
+// Check parses the subject and body, reporting the first syntax error.
+func (t Template) Check() error {
+	_, _, err := t.parse()
+	return err
+}
+
+// Render executes the template; the subject is trimmed to a single line.
+func (t Template) Render(data Data) (subject, body string, err error) {
+	subjectTmpl, bodyTmpl, err := t.parse()
+	if err != nil {
+		return "", "", err
+	}
+	var subjectOut, bodyOut strings.Builder
+	if err := subjectTmpl.Execute(&subjectOut, data); err != nil {
+		return "", "", fmt.Errorf("render %s subject: %w", t.Name, err)
+	}
+	if err := bodyTmpl.Execute(&bodyOut, data); err != nil {
+		return "", "", fmt.Errorf("render %s body: %w", t.Name, err)
+	}
+	subject = strings.Join(strings.Fields(subjectOut.String()), " ")
+	return subject, strings.TrimSpace(bodyOut.String()) + "\n", nil
+}
+
+func (t Template) parse() (*template.Template, *template.Template, error) {
+	funcs := funcs(t.language())
+	subject, err := template.New("subject").Funcs(funcs).Option("missingkey=zero").Parse(t.Subject)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse %s subject: %w", t.Name, err)
+	}
+	body, err := template.New("body").Funcs(funcs).Option("missingkey=zero").Parse(t.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse %s body: %w", t.Name, err)
+	}
+	return subject, body, nil
+}
@@ -0,0 +1,42 @@
+package notification
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+)
+
+// UnsubscribeToken signs a member ID and its tenant for the unsubscribe link
+// in emails. It does not expire: the link in an old email keeps working.
+func UnsubscribeToken(secret, tenantID, memberID string) string {
+	payload := tenantID + ":" + memberID
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + base64.RawURLEncoding.EncodeToString(unsubscribeMAC(secret, payload))
+}
+
+// ParseUnsubscribeToken returns the tenant and member ID of a token signed
+// with secret. Tokens issued before they named a tenant return an empty one.
+func ParseUnsubscribeToken(secret, token string) (tenantID, memberID string, ok bool) {
+	encodedPayload, encodedMAC, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", "", false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return "", "", false
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(encodedMAC)
+	if err != nil || !hmac.Equal(mac, unsubscribeMAC(secret, string(payload))) {
+		return "", "", false
+	}
+	if tenantID, memberID, ok := strings.Cut(string(payload), ":"); ok {
+		return tenantID, memberID, true
+	}
+	return "", string(payload), true
+}
+
+func unsubscribeMAC(secret, payload string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("unsubscribe:" + payload))
+	return mac.Sum(nil)
+}
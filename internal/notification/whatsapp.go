@@ -0,0 +1,125 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const whatsAppBaseURL = "https://graph.facebook.com/v19.0"
+
+// WhatsAppOptions configures the WhatsApp Business Cloud API.
+type WhatsAppOptions struct {
+	// URL overrides the Graph API base URL, including its version.
+	URL           string
+	PhoneNumberID string
+	Token         string
+	// Template is an approved message template with a single body parameter
+	// that receives the rendered notification. Without one, messages are sent
+	// as free-form text, which WhatsApp only delivers within 24 hours of the
+	// member last writing to the business number.
+	Template         string
+	TemplateLanguage string
+	Timeout          time.Duration
+}
+
+// WhatsAppSender is the WhatsApp channel.
+type WhatsAppSender struct {
+	opts       WhatsAppOptions
+	endpoint   string
+	httpClient *http.Client
+}
+
+// NewWhatsAppSender validates the WhatsApp Business settings.
+func NewWhatsAppSender(opts WhatsAppOptions) (*WhatsAppSender, error) {
+	if opts.PhoneNumberID == "" || opts.Token == "" {
+		return nil, fmt.Errorf("WhatsApp needs a phone number ID and access token")
+	}
+	if opts.URL == "" {
+		opts.URL = whatsAppBaseURL
+	}
+	parsed, err := url.Parse(opts.URL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, fmt.Errorf("WhatsApp API URL must be an absolute http or https URL")
+	}
+	if opts.TemplateLanguage == "" {
+		opts.TemplateLanguage = "id"
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	return &WhatsAppSender{
+		opts:       opts,
+		endpoint:   strings.TrimRight(opts.URL, "/") + "/" + url.PathEscape(opts.PhoneNumberID) + "/messages",
+		httpClient: &http.Client{Timeout: opts.Timeout},
+	}, nil
+}
+
+// Name implements Channel.
+func (s *WhatsAppSender) Name() string {
+	return ChannelWhatsApp
+}
+
+// Send delivers the message body to an E.164 number, returning the WhatsApp message ID.
+func (s *WhatsAppSender) Send(ctx context.Context, msg Message) (string, error) {
+	message := map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"to":                strings.TrimPrefix(msg.To, "+"),
+	}
+	if s.opts.Template != "" {
+		message["type"] = "template"
+		message["template"] = map[string]interface{}{
+			"name":     s.opts.Template,
+			"language": map[string]string{"code": s.opts.TemplateLanguage},
+			"components": []map[string]interface{}{{
+				"type": "body",
+				// Template parameters may not contain line breaks.
+				"parameters": []map[string]string{{"type": "text", "text": strings.Join(strings.Fields(msg.Body), " ")}},
+			}},
+		}
+	} else {
+		message["type"] = "text"
+		message["text"] = map[string]string{"body": msg.Body}
+	}
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return "", fmt.Errorf("encode whatsapp message: %w", err)
+	}
+
+	endpoint := s.endpoint
+	if msg.From != "" {
+		endpoint = strings.TrimRight(s.opts.URL, "/") + "/" + url.PathEscape(msg.From) + "/messages"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.opts.Token)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, responseBodyLimit))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("whatsapp returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var result struct {
+		Messages []struct {
+			ID string `json:"id"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil || len(result.Messages) == 0 {
+		return "", nil
+	}
+	return result.Messages[0].ID, nil
+}
@@ -0,0 +1,96 @@
+package ocr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const responseBodyLimit = 1024
+
+// Options configures the OCR HTTP client.
+type Options struct {
+	URL     string
+	APIKey  string
+	Timeout time.Duration
+}
+
+// HTTPReader posts the photo to an OCR service, such as one running next to
+// the server.
+type HTTPReader struct {
+	opts       Options
+	httpClient *http.Client
+}
+
+// NewHTTPReader constructs a client for an OCR service at opts.URL.
+func NewHTTPReader(opts Options) (*HTTPReader, error) {
+	parsed, err := url.Parse(opts.URL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, fmt.Errorf("OCR URL must be an absolute http or https URL")
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 30 * time.Second
+	}
+	return &HTTPReader{opts: opts, httpClient: &http.Client{Timeout: opts.Timeout}}, nil
+}
+
+// readResponse is the service's JSON answer: the recognised text, and the
+// card's fields when the service extracts them itself (birth_date is YYYY-MM-DD).
+type readResponse struct {
+	Text      string `json:"text"`
+	NIK       string `json:"nik"`
+	Name      string `json:"name"`
+	BirthDate string `json:"birth_date"`
+}
+
+// ReadKTP sends the photo as the request body. Fields the service returns
+// take precedence over those parsed from its text.
+func (c *HTTPReader) ReadKTP(ctx context.Context, image []byte) (*KTP, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.opts.URL, bytes.NewReader(image))
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", http.DetectContentType(image))
+	req.Header.Set("Accept", "application/json")
+	if c.opts.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.opts.APIKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, responseBodyLimit))
+		return nil, fmt.Errorf("OCR service returned %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+
+	var payload readResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	ktp := ParseKTP(payload.Text)
+	if nik := strings.TrimSpace(payload.NIK); nik != "" {
+		ktp.NIK = nik
+	}
+	if name := strings.TrimSpace(payload.Name); name != "" {
+		ktp.Name = name
+	}
+	if raw := strings.TrimSpace(payload.BirthDate); raw != "" {
+		date, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			return nil, fmt.Errorf("decode birth_date %q: %w", raw, err)
+		}
+		ktp.BirthDate = &date
+	}
+	return ktp, nil
+}
+
+var _ Reader = (*HTTPReader)(nil)
@@ -0,0 +1,62 @@
+// Package ocr reads the identity fields printed on a KTP (Indonesian ID card)
+// from a photo of it.
+package ocr
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// KTP is what could be read from an ID card; fields that could not be read are empty.
+type KTP struct {
+	NIK       string
+	Name      string
+	BirthDate *time.Time
+	// Text is the raw text recognised on the card.
+	Text string
+}
+
+// Reader recognises the text on a KTP photo.
+type Reader interface {
+	ReadKTP(ctx context.Context, image []byte) (*KTP, error)
+}
+
+var (
+	nikLine      = regexp.MustCompile(`(?i)^\s*NIK\s*[:.]?\s*([0-9OoIlSB ]{16,24})`)
+	nameLine     = regexp.MustCompile(`(?i)^\s*Nama\s*[:.]?\s*(.+)$`)
+	birthLine    = regexp.MustCompile(`(?i)^\s*Tempat\s*/?\s*Tgl\.?\s*Lahir\s*[:.]?\s*(.+)$`)
+	birthDate    = regexp.MustCompile(`(\d{2})[-/ .](\d{2})[-/ .](\d{4})`)
+	nameTrailing = regexp.MustCompile(`[^A-Za-z'., ]+`)
+	nikDigits    = strings.NewReplacer("O", "0", "o", "0", "I", "1", "l", "1", "S", "5", "B", "8", " ", "")
+)
+
+// ParseKTP picks the NIK, name and birth date out of the text recognised on a
+// KTP, whose fields are printed as "NIK : ...", "Nama : ..." and
+// "Tempat/Tgl Lahir : CITY, DD-MM-YYYY". Letters OCR commonly mistakes for
+// digits are corrected in the NIK.
+func ParseKTP(text string) *KTP {
+	ktp := &KTP{Text: text}
+	for _, line := range strings.Split(text, "\n") {
+		switch {
+		case ktp.NIK == "" && nikLine.MatchString(line):
+			nik := nikDigits.Replace(nikLine.FindStringSubmatch(line)[1])
+			if len(nik) >= 16 {
+				ktp.NIK = nik[:16]
+			}
+		case ktp.Name == "" && nameLine.MatchString(line):
+			name := nameTrailing.ReplaceAllString(nameLine.FindStringSubmatch(line)[1], " ")
+			ktp.Name = strings.Join(strings.Fields(name), " ")
+		case ktp.BirthDate == nil && birthLine.MatchString(line):
+			match := birthDate.FindStringSubmatch(birthLine.FindStringSubmatch(line)[1])
+			if match == nil {
+				continue
+			}
+			if date, err := time.Parse("02-01-2006", match[1]+"-"+match[2]+"-"+match[3]); err == nil {
+				ktp.BirthDate = &date
+			}
+		}
+	}
+	return ktp
+}
@@ -0,0 +1,49 @@
+package ocr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// TesseractReader runs the tesseract command line on the photo.
+type TesseractReader struct {
+	path     string
+	language string
+	timeout  time.Duration
+}
+
+// NewTesseractReader looks up the tesseract binary at path (a name on PATH or
+// a file) and reads cards in language, e.g. "ind".
+func NewTesseractReader(path, language string, timeout time.Duration) (*TesseractReader, error) {
+	resolved, err := exec.LookPath(strings.TrimSpace(path))
+	if err != nil {
+		return nil, fmt.Errorf("find tesseract: %w", err)
+	}
+	return &TesseractReader{path: resolved, language: language, timeout: timeout}, nil
+}
+
+// ReadKTP pipes the photo through tesseract and parses its output.
+func (t *TesseractReader) ReadKTP(ctx context.Context, image []byte) (*KTP, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	args := []string{"stdin", "stdout"}
+	if t.language != "" {
+		args = append(args, "-l", t.language)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, t.path, args...)
+	cmd.Stdin = bytes.NewReader(image)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("run tesseract: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return ParseKTP(stdout.String()), nil
+}
+
+var _ Reader = (*TesseractReader)(nil)
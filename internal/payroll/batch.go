@@ -0,0 +1,186 @@
+package payroll
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Payroll file layouts.
+const (
+	FileFormatCSV   = "csv"
+	FileFormatFixed = "fixed"
+)
+
+// StatusRecord is one participant's line in a payroll file. CertificateStatus
+// is VALID, EXPIRED, INVALID, REVIEW or NONE.
+type StatusRecord struct {
+	NIK               string
+	ParticipantID     string
+	MemberID          string
+	Fund              string
+	ParticipantStatus string
+	CertificateStatus string
+	LastValidAt       *time.Time
+	ValidUntil        *time.Time
+}
+
+// FileWriter writes a payroll status file. Close must be called once all
+// records are written; it writes the trailer, if any, and flushes.
+type FileWriter interface {
+	Write(record StatusRecord) error
+	Close() error
+}
+
+// NewFileWriter returns a FileWriter for the layout that writes to w. fund is
+// recorded in the fixed-width header and may be empty.
+func NewFileWriter(format string, w io.Writer, createdAt time.Time, fund string) (FileWriter, error) {
+	switch format {
+	case FileFormatCSV:
+		return newCSVFileWriter(w)
+	case FileFormatFixed:
+		return newFixedFileWriter(w, createdAt, fund)
+	default:
+		return nil, fmt.Errorf("unsupported payroll file format %q, use csv or fixed", format)
+	}
+}
+
+// FileExtension is the file name extension of the layout.
+func FileExtension(format string) string {
+	if format == FileFormatFixed {
+		return "txt"
+	}
+	return "csv"
+}
+
+// csvHeader names the CSV columns; dates are YYYY-MM-DD.
+var csvHeader = []string{"nik", "participant_id", "member_id", "fund", "participant_status", "certificate_status", "last_valid_date", "valid_until"}
+
+type csvFileWriter struct {
+	w *csv.Writer
+}
+
+func newCSVFileWriter(w io.Writer) (*csvFileWriter, error) {
+	c := &csvFileWriter{w: csv.NewWriter(w)}
+	if err := c.w.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *csvFileWriter) Write(record StatusRecord) error {
+	return c.w.Write([]string{
+		record.NIK,
+		record.ParticipantID,
+		record.MemberID,
+		record.Fund,
+		record.ParticipantStatus,
+		record.CertificateStatus,
+		formatDate(record.LastValidAt, "2006-01-02"),
+		formatDate(record.ValidUntil, "2006-01-02"),
+	})
+}
+
+func (c *csvFileWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// fixedField is one column of a fixed-width detail record.
+type fixedField struct {
+	name  string
+	width int
+}
+
+// fixedDetail is the layout of a D record after its type character. Text is
+// left-aligned and space padded; dates are YYYYMMDD, or spaces when unknown.
+var fixedDetail = []fixedField{
+	{"nik", 20},
+	{"participant_id", 36},
+	{"member_id", 36},
+	{"fund", 20},
+	{"participant_status", 9},
+	{"certificate_status", 8},
+	{"last_valid_date", 8},
+	{"valid_until", 8},
+}
+
+// fixedRecordWidth is the length of every record, line ending excluded.
+var fixedRecordWidth = func() int {
+	width := 1
+	for _, field := range fixedDetail {
+		width += field.width
+	}
+	return width
+}()
+
+type fixedFileWriter struct {
+	w       *bufio.Writer
+	records int
+}
+
+func newFixedFileWriter(w io.Writer, createdAt time.Time, fund string) (*fixedFileWriter, error) {
+	f := &fixedFileWriter{w: bufio.NewWriter(w)}
+	if len(fund) > 20 {
+		return nil, fmt.Errorf("fund %q is longer than 20 characters", fund)
+	}
+	created := createdAt.UTC()
+	header := "H" + created.Format("20060102") + created.Format("150405") + pad(fund, 20)
+	if err := f.line(header); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *fixedFileWriter) Write(record StatusRecord) error {
+	values := []string{
+		record.NIK,
+		record.ParticipantID,
+		record.MemberID,
+		record.Fund,
+		record.ParticipantStatus,
+		record.CertificateStatus,
+		formatDate(record.LastValidAt, "20060102"),
+		formatDate(record.ValidUntil, "20060102"),
+	}
+	var line strings.Builder
+	line.WriteString("D")
+	for i, field := range fixedDetail {
+		if len(values[i]) > field.width {
+			return fmt.Errorf("participant %s: %s %q is longer than %d characters", record.ParticipantID, field.name, values[i], field.width)
+		}
+		line.WriteString(pad(values[i], field.width))
+	}
+	f.records++
+	return f.line(line.String())
+}
+
+func (f *fixedFileWriter) Close() error {
+	if err := f.line(fmt.Sprintf("T%09d", f.records)); err != nil {
+		return err
+	}
+	return f.w.Flush()
+}
+
+// line writes one record padded to the record width.
+func (f *fixedFileWriter) line(record string) error {
+	_, err := f.w.WriteString(pad(record, fixedRecordWidth) + "\n")
+	return err
+}
+
+func pad(value string, width int) string {
+	if len(value) >= width {
+		return value
+	}
+	return value + strings.Repeat(" ", width-len(value))
+}
+
+func formatDate(t *time.Time, layout string) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(layout)
+}
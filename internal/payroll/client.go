@@ -0,0 +1,111 @@
+// Package payroll pushes life certificate status transitions to the pension
+// payment system and asks it to release payment holds.
+package payroll
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Authentication schemes supported by the payment system client.
+const (
+	AuthNone   = "none"
+	AuthBasic  = "basic"
+	AuthBearer = "bearer"
+)
+
+const responseBodyLimit = 1024
+
+// Client delivers status payloads to the payment system.
+type Client interface {
+	// Push sends the payload and returns the HTTP status code; a nil error means the push was acknowledged.
+	Push(ctx context.Context, payload map[string]interface{}) (int, error)
+}
+
+// Options configures the payment system HTTP client.
+type Options struct {
+	URL      string
+	AuthType string
+	Username string
+	Password string
+	Token    string
+	// FieldMap renames payload fields to the payment system's names. When set, unmapped fields are dropped.
+	FieldMap map[string]string
+	Timeout  time.Duration
+}
+
+type apiClient struct {
+	url        string
+	opts       Options
+	httpClient *http.Client
+}
+
+// NewHTTPClient constructs a REST client for the payment system.
+func NewHTTPClient(opts Options) (Client, error) {
+	parsed, err := url.Parse(opts.URL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, fmt.Errorf("payment system URL must be an absolute http or https URL")
+	}
+	switch opts.AuthType {
+	case "", AuthNone, AuthBasic, AuthBearer:
+	default:
+		return nil, fmt.Errorf("unsupported auth type %q", opts.AuthType)
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	return &apiClient{url: opts.URL, opts: opts, httpClient: &http.Client{Timeout: opts.Timeout}}, nil
+}
+
+func (c *apiClient) Push(ctx context.Context, payload map[string]interface{}) (int, error) {
+	body, err := json.Marshal(MapFields(payload, c.opts.FieldMap))
+	if err != nil {
+		return 0, fmt.Errorf("encode payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	switch c.opts.AuthType {
+	case AuthBasic:
+		req.SetBasicAuth(c.opts.Username, c.opts.Password)
+	case AuthBearer:
+		req.Header.Set("Authorization", "Bearer "+c.opts.Token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, responseBodyLimit))
+		return resp.StatusCode, fmt.Errorf("payment system returned %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
+// MapFields renames payload fields according to fieldMap, keeping the payload as is when the map is empty.
+func MapFields(payload map[string]interface{}, fieldMap map[string]string) map[string]interface{} {
+	if len(fieldMap) == 0 {
+		return payload
+	}
+	mapped := make(map[string]interface{}, len(fieldMap))
+	for source, target := range fieldMap {
+		if value, ok := payload[source]; ok {
+			mapped[target] = value
+		}
+	}
+	return mapped
+}
@@ -0,0 +1,243 @@
+package payroll
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// acknowledgmentLimit bounds how much of a response is read for the acknowledgment field.
+const acknowledgmentLimit = 64 << 10
+
+// DefaultHoldReleaseTemplate renders every template field as a JSON object.
+const DefaultHoldReleaseTemplate = `{"participant_id":{{json .ParticipantID}},"nik":{{json .NIK}},"member_id":{{json .MemberID}},"fund":{{json .Fund}},"certificate_id":{{json .CertificateID}},"method":{{json .Method}},"verified_at":{{json .VerifiedAt}},"valid_until":{{json .ValidUntil}}}`
+
+// HoldReleaseData is the certificate a hold release template renders.
+type HoldReleaseData struct {
+	ParticipantID string
+	NIK           string
+	MemberID      *string
+	Fund          *string
+	CertificateID string
+	Method        string
+	VerifiedAt    time.Time
+	ValidUntil    time.Time
+}
+
+// HoldReleaseTemplate renders hold release request bodies.
+type HoldReleaseTemplate struct {
+	tmpl *template.Template
+	json bool
+}
+
+var holdReleaseFuncs = template.FuncMap{
+	// json encodes a value as a JSON literal, quoting and escaping strings.
+	"json": func(v interface{}) (string, error) {
+		raw, err := json.Marshal(v)
+		return string(raw), err
+	},
+	"date": func(t time.Time) string {
+		return t.UTC().Format("2006-01-02")
+	},
+	"rfc3339": func(t time.Time) string {
+		return t.UTC().Format(time.RFC3339)
+	},
+}
+
+// LoadHoldReleaseTemplate parses the template file at path, or the default
+// template when path is empty.
+func LoadHoldReleaseTemplate(path, contentType string) (*HoldReleaseTemplate, error) {
+	text := DefaultHoldReleaseTemplate
+	if path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read hold release template: %w", err)
+		}
+		text = string(raw)
+	}
+	return ParseHoldReleaseTemplate(text, contentType)
+}
+
+// ParseHoldReleaseTemplate parses a Go text/template for the request body. The
+// template is rendered once against sample data so mistakes surface at startup;
+// with a JSON content type the output must also be valid JSON.
+func ParseHoldReleaseTemplate(text, contentType string) (*HoldReleaseTemplate, error) {
+	tmpl, err := template.New("hold_release").Funcs(holdReleaseFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse hold release template: %w", err)
+	}
+	t := &HoldReleaseTemplate{tmpl: tmpl, json: strings.Contains(strings.ToLower(contentType), "json")}
+
+	member, fund := "00000000-0000-0000-0000-000000000000", "SAMPLE"
+	now := time.Now().UTC()
+	if _, err := t.Render(HoldReleaseData{
+		ParticipantID: "00000000-0000-0000-0000-000000000000",
+		NIK:           "0000000000000000",
+		MemberID:      &member,
+		Fund:          &fund,
+		CertificateID: "00000000-0000-0000-0000-000000000000",
+		Method:        "AUTOMATIC",
+		VerifiedAt:    now,
+		ValidUntil:    now.AddDate(1, 0, 0),
+	}); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Render executes the template for one certificate.
+func (t *HoldReleaseTemplate) Render(data HoldReleaseData) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("render hold release template: %w", err)
+	}
+	if t.json && !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("hold release template did not render valid JSON")
+	}
+	return buf.Bytes(), nil
+}
+
+// Acknowledgment is the payment system's response to a hold release.
+type Acknowledgment struct {
+	StatusCode int
+	// Body is the start of the response body.
+	Body string
+	// Reference is the value of the configured acknowledgment field.
+	Reference string
+}
+
+// HoldReleaser asks the payment system to release a pension payment hold.
+type HoldReleaser interface {
+	// Release sends the rendered body. The acknowledgment is returned whenever a
+	// response arrived; a nil error means the release was acknowledged.
+	Release(ctx context.Context, body []byte) (*Acknowledgment, error)
+}
+
+// HoldReleaseOptions configures the hold release HTTP client.
+type HoldReleaseOptions struct {
+	URL         string
+	Method      string
+	AuthType    string
+	Username    string
+	Password    string
+	Token       string
+	ContentType string
+	// AckField is the dotted path of the acknowledgment reference in a JSON
+	// response, e.g. "data.release_id". When set, a 2xx response without it is
+	// not an acknowledgment.
+	AckField string
+	Timeout  time.Duration
+}
+
+type holdReleaseClient struct {
+	opts       HoldReleaseOptions
+	httpClient *http.Client
+}
+
+// NewHoldReleaseClient constructs a REST client for hold release callbacks.
+func NewHoldReleaseClient(opts HoldReleaseOptions) (HoldReleaser, error) {
+	parsed, err := url.Parse(opts.URL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, fmt.Errorf("hold release URL must be an absolute http or https URL")
+	}
+	switch opts.AuthType {
+	case "", AuthNone, AuthBasic, AuthBearer:
+	default:
+		return nil, fmt.Errorf("unsupported auth type %q", opts.AuthType)
+	}
+	opts.Method = strings.ToUpper(opts.Method)
+	if opts.Method == "" {
+		opts.Method = http.MethodPost
+	}
+	if opts.ContentType == "" {
+		opts.ContentType = "application/json"
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	return &holdReleaseClient{opts: opts, httpClient: &http.Client{Timeout: opts.Timeout}}, nil
+}
+
+func (c *holdReleaseClient) Release(ctx context.Context, body []byte) (*Acknowledgment, error) {
+	req, err := http.NewRequestWithContext(ctx, c.opts.Method, c.opts.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", c.opts.ContentType)
+	switch c.opts.AuthType {
+	case AuthBasic:
+		req.SetBasicAuth(c.opts.Username, c.opts.Password)
+	case AuthBearer:
+		req.Header.Set("Authorization", "Bearer "+c.opts.Token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, acknowledgmentLimit))
+	ack := &Acknowledgment{StatusCode: resp.StatusCode, Body: snippet(raw)}
+	if err != nil {
+		return ack, fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return ack, fmt.Errorf("payment system returned %d: %s", resp.StatusCode, ack.Body)
+	}
+	if c.opts.AckField != "" {
+		reference, err := lookupField(raw, c.opts.AckField)
+		if err != nil {
+			return ack, err
+		}
+		ack.Reference = reference
+	}
+	return ack, nil
+}
+
+// lookupField returns the value at a dotted path in a JSON document.
+func lookupField(raw []byte, path string) (string, error) {
+	var doc interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return "", fmt.Errorf("decode acknowledgment: %w", err)
+	}
+	for _, key := range strings.Split(path, ".") {
+		object, ok := doc.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("acknowledgment has no %s", path)
+		}
+		if doc, ok = object[key]; !ok {
+			return "", fmt.Errorf("acknowledgment has no %s", path)
+		}
+	}
+	switch value := doc.(type) {
+	case nil:
+		return "", fmt.Errorf("acknowledgment has no %s", path)
+	case string:
+		if strings.TrimSpace(value) == "" {
+			return "", fmt.Errorf("acknowledgment has an empty %s", path)
+		}
+		return value, nil
+	case map[string]interface{}, []interface{}:
+		return "", fmt.Errorf("acknowledgment %s is not a scalar", path)
+	default:
+		return fmt.Sprint(value), nil
+	}
+}
+
+func snippet(raw []byte) string {
+	if len(raw) > responseBodyLimit {
+		raw = raw[:responseBodyLimit]
+	}
+	return strings.TrimSpace(string(raw))
+}
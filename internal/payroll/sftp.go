@@ -0,0 +1,146 @@
+package payroll
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// Uploader delivers payroll files to a legacy payroll system.
+type Uploader interface {
+	// Upload writes the contents of r to name in the remote directory and
+	// returns the remote path. The file is written under a temporary name and
+	// renamed once complete, so the payroll system never picks up a partial file.
+	Upload(ctx context.Context, name string, r io.Reader) (string, error)
+}
+
+// SFTPOptions configures uploads to an SFTP server.
+type SFTPOptions struct {
+	// Addr is host:port; the port defaults to 22.
+	Addr string
+	User string
+	// KeyFile is the PEM private key used to authenticate, optionally
+	// encrypted with KeyPassphrase.
+	KeyFile       string
+	KeyPassphrase string
+	// HostKey is the server's public key in authorized_keys format. The
+	// connection is refused when the server presents another key.
+	HostKey string
+	Dir     string
+	Timeout time.Duration
+}
+
+type sftpUploader struct {
+	addr   string
+	dir    string
+	config *ssh.ClientConfig
+}
+
+// NewSFTPUploader checks the key and host key and returns an Uploader that
+// connects for each upload.
+func NewSFTPUploader(opts SFTPOptions) (Uploader, error) {
+	if opts.Addr == "" || opts.User == "" {
+		return nil, fmt.Errorf("SFTP host and user are required")
+	}
+	addr := opts.Addr
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "22")
+	}
+
+	raw, err := os.ReadFile(opts.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("read SFTP key: %w", err)
+	}
+	var signer ssh.Signer
+	if opts.KeyPassphrase != "" {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(raw, []byte(opts.KeyPassphrase))
+	} else {
+		signer, err = ssh.ParsePrivateKey(raw)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse SFTP key: %w", err)
+	}
+
+	if opts.HostKey == "" {
+		return nil, fmt.Errorf("SFTP host key is required")
+	}
+	hostKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(opts.HostKey))
+	if err != nil {
+		return nil, fmt.Errorf("parse SFTP host key: %w", err)
+	}
+
+	if opts.Dir == "" {
+		opts.Dir = "."
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = time.Minute
+	}
+	return &sftpUploader{
+		addr: addr,
+		dir:  opts.Dir,
+		config: &ssh.ClientConfig{
+			User:            opts.User,
+			Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+			HostKeyCallback: ssh.FixedHostKey(hostKey),
+			Timeout:         opts.Timeout,
+		},
+	}, nil
+}
+
+func (u *sftpUploader) Upload(ctx context.Context, name string, r io.Reader) (string, error) {
+	dialer := net.Dialer{Timeout: u.config.Timeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", u.addr)
+	if err != nil {
+		return "", fmt.Errorf("dial SFTP server: %w", err)
+	}
+	// Closing the connection aborts a transfer still running when ctx ends.
+	stop := context.AfterFunc(ctx, func() { netConn.Close() })
+	defer stop()
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(netConn, u.addr, u.config)
+	if err != nil {
+		netConn.Close()
+		return "", fmt.Errorf("SFTP handshake: %w", err)
+	}
+	sshClient := ssh.NewClient(sshConn, chans, reqs)
+	defer sshClient.Close()
+
+	client, err := sftp.NewClient(sshClient)
+	if err != nil {
+		return "", fmt.Errorf("start SFTP session: %w", err)
+	}
+	defer client.Close()
+
+	target := path.Join(u.dir, name)
+	partial := target + ".part"
+	file, err := client.Create(partial)
+	if err != nil {
+		return "", fmt.Errorf("create %s: %w", partial, err)
+	}
+	if _, err := io.Copy(file, r); err != nil {
+		file.Close()
+		_ = client.Remove(partial)
+		return "", fmt.Errorf("write %s: %w", partial, err)
+	}
+	if err := file.Close(); err != nil {
+		_ = client.Remove(partial)
+		return "", fmt.Errorf("close %s: %w", partial, err)
+	}
+
+	// Plain SFTP rename refuses to replace an existing file; the POSIX
+	// extension does so atomically where the server supports it.
+	if err := client.PosixRename(partial, target); err != nil {
+		_ = client.Remove(target)
+		if err := client.Rename(partial, target); err != nil {
+			return "", fmt.Errorf("rename %s: %w", partial, err)
+		}
+	}
+	return target, nil
+}
@@ -0,0 +1,50 @@
+// Package pii masks personal identifiers for callers who need to recognise a
+// record but not read the identifier in full.
+package pii
+
+import "strings"
+
+const maskRune = '*'
+
+// NIK keeps the region code and the serial, e.g. 3174********1234.
+func NIK(nik string) string {
+	return middle(nik, 4, 4)
+}
+
+// Phone keeps the country or operator prefix and the last four digits.
+func Phone(phone string) string {
+	return middle(phone, 4, 4)
+}
+
+// Email keeps the first character of the local part and the domain, e.g. b***@example.com.
+func Email(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok {
+		return middle(email, 1, 0)
+	}
+	return middle(local, 1, 0) + "@" + domain
+}
+
+// Contact masks an email address or a phone number, whichever it is.
+func Contact(contact string) string {
+	if strings.Contains(contact, "@") {
+		return Email(contact)
+	}
+	return Phone(contact)
+}
+
+// middle replaces all but the first keepStart and last keepEnd runes. Values
+// too short to keep both ends keep at most their last quarter.
+func middle(value string, keepStart, keepEnd int) string {
+	runes := []rune(value)
+	if len(runes) == 0 {
+		return value
+	}
+	if len(runes) <= keepStart+keepEnd {
+		keepStart, keepEnd = 0, min(keepEnd, len(runes)/4)
+	}
+	for i := keepStart; i < len(runes)-keepEnd; i++ {
+		runes[i] = maskRune
+	}
+	return string(runes)
+}
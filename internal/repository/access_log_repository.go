@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// AccessLogRepository persists reads of personal data.
+type AccessLogRepository interface {
+	Create(ctx context.Context, entry *domain.AccessLog) error
+	List(ctx context.Context, filter AccessLogFilter, page Pagination) ([]domain.AccessLog, int64, error)
+}
+
+// AccessLogFilter narrows access log queries; zero values match everything.
+type AccessLogFilter struct {
+	Actor        string
+	ResourceType string
+	ResourceID   string
+	From         *time.Time
+	To           *time.Time
+}
+
+type accessLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAccessLogRepository creates a gorm-backed repository.
+func NewAccessLogRepository(db *gorm.DB) AccessLogRepository {
+	return &accessLogRepository{db: db}
+}
+
+func (r *accessLogRepository) Create(ctx context.Context, entry *domain.AccessLog) error {
+	if err := conn(ctx, r.db).Create(entry).Error; err != nil {
+		return fmt.Errorf("create access log: %w", err)
+	}
+	return nil
+}
+
+func (r *accessLogRepository) List(ctx context.Context, filter AccessLogFilter, page Pagination) ([]domain.AccessLog, int64, error) {
+	query := conn(ctx, r.db).Model(&domain.AccessLog{})
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+	if filter.ResourceType != "" {
+		query = query.Where("resource_type = ?", filter.ResourceType)
+	}
+	if filter.ResourceID != "" {
+		query = query.Where("resource_id = ?", filter.ResourceID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count access logs: %w", err)
+	}
+
+	var entries []domain.AccessLog
+	if err := query.Order("created_at desc").Offset(page.Offset()).Limit(page.PageSize).Find(&entries).Error; err != nil {
+		return nil, 0, fmt.Errorf("list access logs: %w", err)
+	}
+	return entries, total, nil
+}
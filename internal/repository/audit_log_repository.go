@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// AuditLogRepository persists audit trail entries.
+type AuditLogRepository interface {
+	Create(ctx context.Context, entry *domain.AuditLog) error
+	ListByEntity(ctx context.Context, entityType, entityID string) ([]domain.AuditLog, error)
+}
+
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository creates a gorm-backed repository.
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+func (r *auditLogRepository) Create(ctx context.Context, entry *domain.AuditLog) error {
+	if err := conn(ctx, r.db).Create(entry).Error; err != nil {
+		return fmt.Errorf("create audit log: %w", err)
+	}
+	return nil
+}
+
+func (r *auditLogRepository) ListByEntity(ctx context.Context, entityType, entityID string) ([]domain.AuditLog, error) {
+	var entries []domain.AuditLog
+	if err := conn(ctx, r.db).
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("created_at asc").
+		Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("list audit logs: %w", err)
+	}
+	return entries, nil
+}
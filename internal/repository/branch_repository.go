@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"life-certificates/internal/domain"
+)
+
+// BranchRepository persists the branch registry.
+type BranchRepository interface {
+	Create(ctx context.Context, branch *domain.Branch) error
+	GetByID(ctx context.Context, id string) (*domain.Branch, error)
+	GetByCode(ctx context.Context, code string) (*domain.Branch, error)
+	List(ctx context.Context) ([]domain.Branch, error)
+	Update(ctx context.Context, branch *domain.Branch) error
+	Delete(ctx context.Context, id string) error
+	// InUse reports whether an active kiosk or officer belongs to the branch code.
+	InUse(ctx context.Context, code string) (bool, error)
+}
+
+type branchRepository struct {
+	db *gorm.DB
+}
+
+// NewBranchRepository creates a gorm-backed repository.
+func NewBranchRepository(db *gorm.DB) BranchRepository {
+	return &branchRepository{db: db}
+}
+
+func (r *branchRepository) Create(ctx context.Context, branch *domain.Branch) error {
+	if err := conn(ctx, r.db).Create(branch).Error; err != nil {
+		return fmt.Errorf("create branch: %w", err)
+	}
+	return nil
+}
+
+func (r *branchRepository) GetByID(ctx context.Context, id string) (*domain.Branch, error) {
+	return r.first(ctx, "id = ?", id)
+}
+
+func (r *branchRepository) GetByCode(ctx context.Context, code string) (*domain.Branch, error) {
+	return r.first(ctx, "code = ?", code)
+}
+
+func (r *branchRepository) first(ctx context.Context, query string, arg interface{}) (*domain.Branch, error) {
+	var branch domain.Branch
+	if err := conn(ctx, r.db).First(&branch, query, arg).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get branch: %w", err)
+	}
+	return &branch, nil
+}
+
+func (r *branchRepository) List(ctx context.Context) ([]domain.Branch, error) {
+	var branches []domain.Branch
+	if err := conn(ctx, r.db).Order("code").Find(&branches).Error; err != nil {
+		return nil, fmt.Errorf("list branches: %w", err)
+	}
+	return branches, nil
+}
+
+func (r *branchRepository) Update(ctx context.Context, branch *domain.Branch) error {
+	if err := conn(ctx, r.db).Model(&domain.Branch{}).
+		Where("id = ?", branch.ID).
+		Updates(map[string]interface{}{
+			"name":          branch.Name,
+			"address":       branch.Address,
+			"latitude":      branch.Latitude,
+			"longitude":     branch.Longitude,
+			"radius_meters": branch.RadiusMeters,
+			"updated_at":    branch.UpdatedAt,
+		}).Error; err != nil {
+		return fmt.Errorf("update branch: %w", err)
+	}
+	return nil
+}
+
+func (r *branchRepository) Delete(ctx context.Context, id string) error {
+	if err := conn(ctx, r.db).Delete(&domain.Branch{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("delete branch: %w", err)
+	}
+	return nil
+}
+
+func (r *branchRepository) InUse(ctx context.Context, code string) (bool, error) {
+	var kiosks int64
+	if err := conn(ctx, r.db).Model(&domain.Kiosk{}).
+		Where("branch_id = ? AND revoked_at IS NULL", code).
+		Count(&kiosks).Error; err != nil {
+		return false, fmt.Errorf("count branch kiosks: %w", err)
+	}
+	if kiosks > 0 {
+		return true, nil
+	}
+	var officers int64
+	if err := conn(ctx, r.db).Model(&domain.Officer{}).
+		Where("branch_id = ? AND status = ?", code, domain.OfficerActive).
+		Count(&officers).Error; err != nil {
+		return false, fmt.Errorf("count branch officers: %w", err)
+	}
+	return officers > 0, nil
+}
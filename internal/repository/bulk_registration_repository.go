@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// BulkRegistrationRepository persists bulk registration jobs and their rows.
+type BulkRegistrationRepository interface {
+	CreateJob(ctx context.Context, job *domain.BulkRegistrationJob, rows []domain.BulkRegistrationRow) error
+	GetJob(ctx context.Context, id string) (*domain.BulkRegistrationJob, error)
+	GetRow(ctx context.Context, id string) (*domain.BulkRegistrationRow, error)
+	ListRows(ctx context.Context, jobID string, status domain.BulkRegistrationRowStatus) ([]domain.BulkRegistrationRow, error)
+	ListPendingRowIDs(ctx context.Context) ([]string, error)
+	ClaimRow(ctx context.Context, id string, now time.Time, lease time.Duration) (bool, error)
+	ReleaseRow(ctx context.Context, id string) error
+	MarkJobRunning(ctx context.Context, jobID string, startedAt time.Time) error
+	CompleteRow(ctx context.Context, row *domain.BulkRegistrationRow) error
+}
+
+type bulkRegistrationRepository struct {
+	db *gorm.DB
+}
+
+// NewBulkRegistrationRepository creates a gorm-backed repository.
+func NewBulkRegistrationRepository(db *gorm.DB) BulkRegistrationRepository {
+	return &bulkRegistrationRepository{db: db}
+}
+
+func (r *bulkRegistrationRepository) CreateJob(ctx context.Context, job *domain.BulkRegistrationJob, rows []domain.BulkRegistrationRow) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(job).Error; err != nil {
+			return fmt.Errorf("create bulk registration job: %w", err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		if err := tx.CreateInBatches(rows, 100).Error; err != nil {
+			return fmt.Errorf("create bulk registration rows: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *bulkRegistrationRepository) GetJob(ctx context.Context, id string) (*domain.BulkRegistrationJob, error) {
+	var job domain.BulkRegistrationJob
+	if err := conn(ctx, r.db).First(&job, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get bulk registration job: %w", err)
+	}
+	return &job, nil
+}
+
+func (r *bulkRegistrationRepository) GetRow(ctx context.Context, id string) (*domain.BulkRegistrationRow, error) {
+	var row domain.BulkRegistrationRow
+	if err := conn(ctx, r.db).First(&row, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get bulk registration row: %w", err)
+	}
+	return &row, nil
+}
+
+func (r *bulkRegistrationRepository) ListRows(ctx context.Context, jobID string, status domain.BulkRegistrationRowStatus) ([]domain.BulkRegistrationRow, error) {
+	query := conn(ctx, r.db).Omit("image_data").Where("job_id = ?", jobID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var rows []domain.BulkRegistrationRow
+	if err := query.Order("row_number asc").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("list bulk registration rows: %w", err)
+	}
+	return rows, nil
+}
+
+func (r *bulkRegistrationRepository) ListPendingRowIDs(ctx context.Context) ([]string, error) {
+	var ids []string
+	if err := conn(ctx, r.db).
+		Model(&domain.BulkRegistrationRow{}).
+		Where("status = ?", domain.BulkRegistrationRowPending).
+		Order("job_id, row_number").
+		Pluck("id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("list pending bulk registration rows: %w", err)
+	}
+	return ids, nil
+}
+
+// ClaimRow takes a pending row for one worker. It reports false when the row
+// is no longer pending or another worker claimed it less than lease ago.
+func (r *bulkRegistrationRepository) ClaimRow(ctx context.Context, id string, now time.Time, lease time.Duration) (bool, error) {
+	result := conn(ctx, r.db).
+		Model(&domain.BulkRegistrationRow{}).
+		Where("id = ? AND status = ? AND (claimed_at IS NULL OR claimed_at < ?)", id, domain.BulkRegistrationRowPending, now.Add(-lease)).
+		Update("claimed_at", now)
+	if result.Error != nil {
+		return false, fmt.Errorf("claim bulk registration row: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ReleaseRow gives up the claim on a row left pending, so it is resumed
+// without waiting for the claim to go stale.
+func (r *bulkRegistrationRepository) ReleaseRow(ctx context.Context, id string) error {
+	if err := conn(ctx, r.db).
+		Model(&domain.BulkRegistrationRow{}).
+		Where("id = ? AND status = ?", id, domain.BulkRegistrationRowPending).
+		Update("claimed_at", nil).Error; err != nil {
+		return fmt.Errorf("release bulk registration row: %w", err)
+	}
+	return nil
+}
+
+func (r *bulkRegistrationRepository) MarkJobRunning(ctx context.Context, jobID string, startedAt time.Time) error {
+	if err := conn(ctx, r.db).
+		Model(&domain.BulkRegistrationJob{}).
+		Where("id = ? AND status = ?", jobID, domain.BulkRegistrationJobPending).
+		Updates(map[string]interface{}{
+			"status":     domain.BulkRegistrationJobRunning,
+			"started_at": startedAt,
+		}).Error; err != nil {
+		return fmt.Errorf("mark bulk registration job running: %w", err)
+	}
+	return nil
+}
+
+// CompleteRow stores the outcome of a pending row, drops its image and
+// advances the job counters, closing the job once every row has been
+// processed. A row completed already is left as it is and counted once.
+func (r *bulkRegistrationRepository) CompleteRow(ctx context.Context, row *domain.BulkRegistrationRow) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.BulkRegistrationRow{}).
+			Where("id = ? AND status = ?", row.ID, domain.BulkRegistrationRowPending).
+			Updates(map[string]interface{}{
+				"status":         row.Status,
+				"participant_id": row.ParticipantID,
+				"error":          row.Error,
+				"processed_at":   row.ProcessedAt,
+				"image_data":     nil,
+			})
+		if result.Error != nil {
+			return fmt.Errorf("complete bulk registration row: %w", result.Error)
+		}
+		if result.RowsAffected != 1 {
+			return nil
+		}
+
+		counter := "succeeded_rows"
+		if row.Status == domain.BulkRegistrationRowFailed {
+			counter = "failed_rows"
+		}
+		if err := tx.Model(&domain.BulkRegistrationJob{}).Where("id = ?", row.JobID).Updates(map[string]interface{}{
+			"processed_rows": gorm.Expr("processed_rows + 1"),
+			counter:          gorm.Expr(counter + " + 1"),
+		}).Error; err != nil {
+			return fmt.Errorf("advance bulk registration job: %w", err)
+		}
+
+		if err := tx.Model(&domain.BulkRegistrationJob{}).
+			Where("id = ? AND processed_rows >= total_rows", row.JobID).
+			Updates(map[string]interface{}{
+				"status":      domain.BulkRegistrationJobCompleted,
+				"finished_at": row.ProcessedAt,
+			}).Error; err != nil {
+			return fmt.Errorf("finish bulk registration job: %w", err)
+		}
+		return nil
+	})
+}
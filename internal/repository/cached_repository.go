@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"life-certificates/internal/cache"
+	"life-certificates/internal/domain"
+	"life-certificates/internal/metrics"
+	"life-certificates/internal/tenant"
+)
+
+const (
+	participantCacheKey = "participant:"
+	frIdentityCacheKey  = "fr_identity:"
+)
+
+// cachedParticipantRepository serves GetByID from a cache and drops the entry
+// whenever the participant is written.
+type cachedParticipantRepository struct {
+	ParticipantRepository
+	cache cache.Cache
+}
+
+// NewCachedParticipantRepository caches participants looked up by ID.
+func NewCachedParticipantRepository(inner ParticipantRepository, c cache.Cache) ParticipantRepository {
+	return &cachedParticipantRepository{ParticipantRepository: inner, cache: c}
+}
+
+func (r *cachedParticipantRepository) GetByID(ctx context.Context, id string) (*domain.Participant, error) {
+	// A transaction may read its own uncommitted writes, which must not be
+	// shared, and rows read across tenants must not be served to one.
+	if inTransaction(ctx) || tenant.IsSystem(ctx) {
+		return r.ParticipantRepository.GetByID(ctx, id)
+	}
+	var participant domain.Participant
+	if cacheGet(ctx, r.cache, "participant", cacheKey(ctx, participantCacheKey, id), &participant) {
+		return &participant, nil
+	}
+	found, err := r.ParticipantRepository.GetByID(ctx, id)
+	if err != nil || found == nil {
+		return found, err
+	}
+	cacheSet(ctx, r.cache, cacheKey(ctx, participantCacheKey, id), found)
+	return found, nil
+}
+
+func (r *cachedParticipantRepository) Update(ctx context.Context, participant *domain.Participant) error {
+	if err := r.ParticipantRepository.Update(ctx, participant); err != nil {
+		return err
+	}
+	invalidateKeys(ctx, r.cache, participantCacheKey, participant.ID)
+	return nil
+}
+
+func (r *cachedParticipantRepository) UpdateStatus(ctx context.Context, participant *domain.Participant) error {
+	if err := r.ParticipantRepository.UpdateStatus(ctx, participant); err != nil {
+		return err
+	}
+	invalidateKeys(ctx, r.cache, participantCacheKey, participant.ID)
+	return nil
+}
+
+func (r *cachedParticipantRepository) SetVerificationProfile(ctx context.Context, id string, profileID *string, at time.Time) error {
+	if err := r.ParticipantRepository.SetVerificationProfile(ctx, id, profileID, at); err != nil {
+		return err
+	}
+	invalidateKeys(ctx, r.cache, participantCacheKey, id)
+	return nil
+}
+
+// ClearVerificationProfile may touch any number of participants, so it drops them all.
+func (r *cachedParticipantRepository) ClearVerificationProfile(ctx context.Context, profileID string) error {
+	if err := r.ParticipantRepository.ClearVerificationProfile(ctx, profileID); err != nil {
+		return err
+	}
+	invalidatePrefix(ctx, r.cache, participantCacheKey)
+	return nil
+}
+
+func (r *cachedParticipantRepository) Delete(ctx context.Context, id string) error {
+	if err := r.ParticipantRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	invalidateKeys(ctx, r.cache, participantCacheKey, id)
+	return nil
+}
+
+// cachedMemberRepository drops cached participants when a merge re-points them
+// to another member.
+type cachedMemberRepository struct {
+	MemberRepository
+	cache cache.Cache
+}
+
+// NewCachedMemberRepository keeps the participant cache consistent with member merges.
+func NewCachedMemberRepository(inner MemberRepository, c cache.Cache) MemberRepository {
+	return &cachedMemberRepository{MemberRepository: inner, cache: c}
+}
+
+func (r *cachedMemberRepository) Merge(ctx context.Context, target *domain.Member, sourceID string, merge *domain.MemberMerge) error {
+	if err := r.MemberRepository.Merge(ctx, target, sourceID, merge); err != nil {
+		return err
+	}
+	invalidatePrefix(ctx, r.cache, participantCacheKey)
+	return nil
+}
+
+// cachedFRIdentityRepository serves GetByLabel from a cache. Labels are never
+// reassigned, so only deletions invalidate; misses are not cached, so creations
+// need not.
+type cachedFRIdentityRepository struct {
+	FRIdentityRepository
+	cache cache.Cache
+}
+
+// NewCachedFRIdentityRepository caches FR identities looked up by label.
+func NewCachedFRIdentityRepository(inner FRIdentityRepository, c cache.Cache) FRIdentityRepository {
+	return &cachedFRIdentityRepository{FRIdentityRepository: inner, cache: c}
+}
+
+func (r *cachedFRIdentityRepository) GetByLabel(ctx context.Context, label string) (*domain.FRIdentity, error) {
+	if inTransaction(ctx) || tenant.IsSystem(ctx) {
+		return r.FRIdentityRepository.GetByLabel(ctx, label)
+	}
+	var identity domain.FRIdentity
+	if cacheGet(ctx, r.cache, "fr_identity", cacheKey(ctx, frIdentityCacheKey, label), &identity) {
+		return &identity, nil
+	}
+	found, err := r.FRIdentityRepository.GetByLabel(ctx, label)
+	if err != nil || found == nil {
+		return found, err
+	}
+	cacheSet(ctx, r.cache, cacheKey(ctx, frIdentityCacheKey, label), found)
+	return found, nil
+}
+
+func (r *cachedFRIdentityRepository) DeleteByParticipantID(ctx context.Context, participantID string) error {
+	identities, err := r.FRIdentityRepository.ListByParticipantID(ctx, participantID)
+	if err != nil {
+		return err
+	}
+	if err := r.FRIdentityRepository.DeleteByParticipantID(ctx, participantID); err != nil {
+		return err
+	}
+	labels := make([]string, len(identities))
+	for i, identity := range identities {
+		labels[i] = identity.Label
+	}
+	invalidateKeys(ctx, r.cache, frIdentityCacheKey, labels...)
+	return nil
+}
+
+// cacheKey is the key of id cached for the tenant of ctx.
+func cacheKey(ctx context.Context, prefix, id string) string {
+	return prefix + tenant.ID(ctx) + ":" + id
+}
+
+// invalidateKeys drops the entries of ids cached for the tenant of ctx, and
+// every entry under prefix when ctx acts across tenants.
+func invalidateKeys(ctx context.Context, c cache.Cache, prefix string, ids ...string) {
+	if tenant.IsSystem(ctx) {
+		invalidatePrefix(ctx, c, prefix)
+		return
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = cacheKey(ctx, prefix, id)
+	}
+	invalidate(ctx, c, keys...)
+}
+
+// cacheGet decodes a cached value into dst. Cache failures are logged and read
+// as a miss so the database still answers.
+func cacheGet(ctx context.Context, c cache.Cache, name, key string, dst interface{}) bool {
+	raw, ok, err := c.Get(ctx, key)
+	if err != nil {
+		log.Printf("[cache] get %s: %v", key, err)
+	}
+	hit := ok && err == nil && json.Unmarshal(raw, dst) == nil
+	metrics.CacheLookup(name, hit)
+	return hit
+}
+
+func cacheSet(ctx context.Context, c cache.Cache, key string, value interface{}) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("[cache] encode %s: %v", key, err)
+		return
+	}
+	if err := c.Set(ctx, key, raw); err != nil {
+		log.Printf("[cache] set %s: %v", key, err)
+	}
+}
+
+// invalidate drops keys once the write in ctx is committed; dropping them
+// earlier would let a concurrent read cache the old row again. A failure only
+// leaves the entry stale until it expires, so it is logged rather than
+// failing the write.
+func invalidate(ctx context.Context, c cache.Cache, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	AfterCommit(ctx, func() {
+		if err := c.Delete(context.WithoutCancel(ctx), keys...); err != nil {
+			log.Printf("[cache] invalidate %v: %v", keys, err)
+		}
+	})
+}
+
+func invalidatePrefix(ctx context.Context, c cache.Cache, prefix string) {
+	AfterCommit(ctx, func() {
+		if err := c.DeletePrefix(context.WithoutCancel(ctx), prefix); err != nil {
+			log.Printf("[cache] invalidate %s*: %v", prefix, err)
+		}
+	})
+}
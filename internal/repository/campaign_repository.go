@@ -0,0 +1,449 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// CampaignRepository persists verification campaigns and their target participants.
+type CampaignRepository interface {
+	Create(ctx context.Context, campaign *domain.Campaign, participants []domain.CampaignParticipant) error
+	GetByID(ctx context.Context, id string) (*domain.Campaign, error)
+	List(ctx context.Context, page Pagination) ([]domain.Campaign, int64, error)
+	ListIDs(ctx context.Context) ([]string, error)
+	// Targets lists the active participants matching the rules, flagging those
+	// with an exclusion in force on the rules' date.
+	Targets(ctx context.Context, rules CampaignRules) ([]CampaignTarget, error)
+	// CountTargets counts what Targets would list without loading it.
+	CountTargets(ctx context.Context, rules CampaignRules) (*CampaignTargetCount, error)
+	ListParticipants(ctx context.Context, campaignID string, status domain.CampaignParticipantStatus, stage domain.CampaignEscalationStage, page Pagination) ([]domain.CampaignParticipant, int64, error)
+	CountByStatus(ctx context.Context, campaignID string) (map[domain.CampaignParticipantStatus]int64, error)
+	// UnfinishedParticipants lists up to limit participants who have not
+	// completed the campaign, in participant ID order after the given one.
+	UnfinishedParticipants(ctx context.Context, campaignID, after string, limit int) ([]domain.CampaignParticipant, error)
+	// MarkCompleted completes participants with a VALID certificate verified at or after since.
+	MarkCompleted(ctx context.Context, campaignID string, since time.Time) (int64, error)
+	// MarkOverdue flags pending participants whose due date is before today.
+	MarkOverdue(ctx context.Context, campaignID string, today time.Time) (int64, error)
+	// Escalatable lists up to limit overdue participants due on or before dueBy
+	// whose escalation stage is one of from.
+	Escalatable(ctx context.Context, campaignID string, from []domain.CampaignEscalationStage, dueBy time.Time, limit int) ([]domain.CampaignParticipant, error)
+	// Escalate moves an overdue participant from one stage to another; false
+	// means the participant was no longer overdue at that stage.
+	Escalate(ctx context.Context, campaignID, participantID string, from, to domain.CampaignEscalationStage, at time.Time) (bool, error)
+	// UpdateEscalation replaces a campaign's escalation policy.
+	UpdateEscalation(ctx context.Context, campaignID string, escalation domain.CampaignEscalation) error
+	// DeleteByParticipant removes the participant from campaigns and exclusion lists.
+	DeleteByParticipant(ctx context.Context, participantID string) error
+	CreateExclusion(ctx context.Context, exclusion *domain.CampaignExclusion) error
+	GetExclusion(ctx context.Context, id string) (*domain.CampaignExclusion, error)
+	// FindExclusion returns the participant's entry on a list; nil when none.
+	FindExclusion(ctx context.Context, participantID string, list domain.CampaignExclusionList) (*domain.CampaignExclusion, error)
+	ListExclusions(ctx context.Context, filter CampaignExclusionFilter, page Pagination) ([]domain.CampaignExclusion, int64, error)
+	DeleteExclusion(ctx context.Context, id string) error
+	CreateNotification(ctx context.Context, notification *domain.CampaignNotification) error
+	GetNotification(ctx context.Context, id string) (*domain.CampaignNotification, error)
+	UpdateNotification(ctx context.Context, notification *domain.CampaignNotification) error
+	// ListNotifications returns the campaign's notification kickoffs, latest first.
+	ListNotifications(ctx context.Context, campaignID string) ([]domain.CampaignNotification, error)
+	// NextDueForParticipant returns the earliest-due campaign, started by today, that the
+	// participant has not completed with a VALID certificate since it started; nil when none.
+	NextDueForParticipant(ctx context.Context, participantID string, today time.Time) (*CampaignDue, error)
+}
+
+// CampaignDue is a participant's unfinished campaign, with the stage they
+// have been escalated to and the campaign's escalation policy.
+type CampaignDue struct {
+	CampaignID  string
+	Kind        domain.CampaignKind
+	DueAt       time.Time
+	Stage       domain.CampaignEscalationStage
+	EscalatedAt *time.Time
+	Escalation  domain.CampaignEscalation `gorm:"embedded;embeddedPrefix:escalation_"`
+}
+
+// CampaignRules select a campaign's target population from the active
+// participants. Nil and empty rules match everyone; MinAge and Provinces
+// only match participants linked to a member.
+type CampaignRules struct {
+	Fund *string
+	// Provinces match the member's province case-insensitively.
+	Provinces []string
+	MinAge    *int
+	// LastVerifiedBefore matches participants without a VALID certificate
+	// verified on or after it.
+	LastVerifiedBefore *time.Time
+	// On is the date ages are reckoned and exclusions checked at.
+	On time.Time
+}
+
+// CampaignTarget is a participant eligible for a campaign with the member birth date, when linked.
+type CampaignTarget struct {
+	ParticipantID string
+	BirthDate     *time.Time
+	// Region is the member's province, empty without a member.
+	Region string
+	// Excluded is set when an exclusion in force keeps the participant out.
+	Excluded bool
+}
+
+// CampaignTargetCount is the size of a target population.
+type CampaignTargetCount struct {
+	// Matched counts the participants the rules match, Excluded those of them
+	// kept out by an exclusion.
+	Matched  int64
+	Excluded int64
+}
+
+// CampaignExclusionFilter narrows the exclusion entries listed.
+type CampaignExclusionFilter struct {
+	List          domain.CampaignExclusionList
+	ParticipantID string
+}
+
+type campaignRepository struct {
+	db *gorm.DB
+}
+
+// NewCampaignRepository creates a gorm-backed repository.
+func NewCampaignRepository(db *gorm.DB) CampaignRepository {
+	return &campaignRepository{db: db}
+}
+
+func (r *campaignRepository) Create(ctx context.Context, campaign *domain.Campaign, participants []domain.CampaignParticipant) error {
+	db := conn(ctx, r.db)
+	if err := db.Create(campaign).Error; err != nil {
+		return fmt.Errorf("create campaign: %w", err)
+	}
+	if len(participants) == 0 {
+		return nil
+	}
+	if err := db.CreateInBatches(participants, 500).Error; err != nil {
+		return fmt.Errorf("create campaign participants: %w", err)
+	}
+	return nil
+}
+
+func (r *campaignRepository) GetByID(ctx context.Context, id string) (*domain.Campaign, error) {
+	var campaign domain.Campaign
+	if err := conn(ctx, r.db).First(&campaign, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get campaign: %w", err)
+	}
+	return &campaign, nil
+}
+
+func (r *campaignRepository) List(ctx context.Context, page Pagination) ([]domain.Campaign, int64, error) {
+	query := conn(ctx, r.db).Model(&domain.Campaign{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count campaigns: %w", err)
+	}
+
+	var campaigns []domain.Campaign
+	if err := query.Order("starts_at desc, created_at desc").Offset(page.Offset()).Limit(page.PageSize).Find(&campaigns).Error; err != nil {
+		return nil, 0, fmt.Errorf("list campaigns: %w", err)
+	}
+	return campaigns, total, nil
+}
+
+func (r *campaignRepository) ListIDs(ctx context.Context) ([]string, error) {
+	var ids []string
+	if err := conn(ctx, r.db).Model(&domain.Campaign{}).Order("created_at").Pluck("id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("list campaign ids: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *campaignRepository) Targets(ctx context.Context, rules CampaignRules) ([]CampaignTarget, error) {
+	excluded := gorm.Expr(excludedCondition, rules.On)
+	query := r.targets(ctx, rules).
+		Select("p.id AS participant_id, m.birth_date AS birth_date, COALESCE(m.province, '') AS region, ? AS excluded", excluded)
+
+	var targets []CampaignTarget
+	if err := query.Order("p.id").Scan(&targets).Error; err != nil {
+		return nil, fmt.Errorf("list campaign targets: %w", err)
+	}
+	return targets, nil
+}
+
+func (r *campaignRepository) CountTargets(ctx context.Context, rules CampaignRules) (*CampaignTargetCount, error) {
+	excluded := gorm.Expr(excludedCondition, rules.On)
+	query := r.targets(ctx, rules).
+		Select("COUNT(*) AS matched, COUNT(*) FILTER (WHERE ?) AS excluded", excluded)
+
+	var count CampaignTargetCount
+	if err := query.Scan(&count).Error; err != nil {
+		return nil, fmt.Errorf("count campaign targets: %w", err)
+	}
+	return &count, nil
+}
+
+// excludedCondition holds for a participant p with an exclusion in force on the date bound to it.
+const excludedCondition = "EXISTS (SELECT 1 FROM campaign_exclusions ce WHERE ce.participant_id = p.id AND (ce.until IS NULL OR ce.until >= ?))"
+
+func (r *campaignRepository) targets(ctx context.Context, rules CampaignRules) *gorm.DB {
+	query := conn(ctx, r.db).Table("participants AS p").
+		Joins("LEFT JOIN members m ON m.id = p.member_id").
+		Where("p.status = ?", domain.ParticipantStatusActive)
+	if rules.Fund != nil {
+		query = query.Where("p.fund = ?", *rules.Fund)
+	}
+	if len(rules.Provinces) > 0 {
+		provinces := make([]string, len(rules.Provinces))
+		for i, province := range rules.Provinces {
+			provinces[i] = strings.ToLower(province)
+		}
+		query = query.Where("LOWER(m.province) IN ?", provinces)
+	}
+	if rules.MinAge != nil {
+		query = query.Where("m.birth_date <= ?", rules.On.AddDate(-*rules.MinAge, 0, 0))
+	}
+	if rules.LastVerifiedBefore != nil {
+		query = query.Where("NOT EXISTS (SELECT 1 FROM life_certificate lc WHERE lc.participant_id = p.id AND lc.status = ? AND lc.verified_at >= ?)",
+			domain.LifeCertificateStatusValid, *rules.LastVerifiedBefore)
+	}
+	return query
+}
+
+func (r *campaignRepository) ListParticipants(ctx context.Context, campaignID string, status domain.CampaignParticipantStatus, stage domain.CampaignEscalationStage, page Pagination) ([]domain.CampaignParticipant, int64, error) {
+	query := conn(ctx, r.db).Model(&domain.CampaignParticipant{}).Where("campaign_id = ?", campaignID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if stage != "" {
+		query = query.Where("stage = ?", stage)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count campaign participants: %w", err)
+	}
+
+	var participants []domain.CampaignParticipant
+	if err := query.Order("due_at, participant_id").Offset(page.Offset()).Limit(page.PageSize).Find(&participants).Error; err != nil {
+		return nil, 0, fmt.Errorf("list campaign participants: %w", err)
+	}
+	return participants, total, nil
+}
+
+func (r *campaignRepository) CountByStatus(ctx context.Context, campaignID string) (map[domain.CampaignParticipantStatus]int64, error) {
+	var rows []struct {
+		Status domain.CampaignParticipantStatus
+		Count  int64
+	}
+	if err := conn(ctx, r.db).Model(&domain.CampaignParticipant{}).
+		Select("status, COUNT(*) AS count").
+		Where("campaign_id = ?", campaignID).
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("count campaign participants by status: %w", err)
+	}
+	counts := make(map[domain.CampaignParticipantStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *campaignRepository) UnfinishedParticipants(ctx context.Context, campaignID, after string, limit int) ([]domain.CampaignParticipant, error) {
+	var participants []domain.CampaignParticipant
+	if err := conn(ctx, r.db).
+		Where("campaign_id = ? AND status <> ? AND participant_id > ?", campaignID, domain.CampaignParticipantCompleted, after).
+		Order("participant_id").Limit(limit).Find(&participants).Error; err != nil {
+		return nil, fmt.Errorf("list unfinished campaign participants: %w", err)
+	}
+	return participants, nil
+}
+
+func (r *campaignRepository) MarkCompleted(ctx context.Context, campaignID string, since time.Time) (int64, error) {
+	result := conn(ctx, r.db).Exec(`
+		UPDATE campaign_participants cp
+		SET status = ?, certificate_id = lc.id, completed_at = lc.verified_at
+		FROM (
+			SELECT DISTINCT ON (participant_id) id, participant_id, verified_at
+			FROM life_certificate
+			WHERE status = ? AND verified_at >= ?
+			ORDER BY participant_id, verified_at
+		) lc
+		WHERE ? AND cp.campaign_id = ? AND cp.status <> ? AND cp.participant_id = lc.participant_id`,
+		domain.CampaignParticipantCompleted, domain.LifeCertificateStatusValid, since,
+		tenantCondition(ctx, "cp.tenant_id"), campaignID, domain.CampaignParticipantCompleted)
+	if result.Error != nil {
+		return 0, fmt.Errorf("mark campaign participants completed: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *campaignRepository) MarkOverdue(ctx context.Context, campaignID string, today time.Time) (int64, error) {
+	result := conn(ctx, r.db).Model(&domain.CampaignParticipant{}).
+		Where("campaign_id = ? AND status = ? AND due_at < ?", campaignID, domain.CampaignParticipantPending, today).
+		Update("status", domain.CampaignParticipantOverdue)
+	if result.Error != nil {
+		return 0, fmt.Errorf("mark campaign participants overdue: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *campaignRepository) Escalatable(ctx context.Context, campaignID string, from []domain.CampaignEscalationStage, dueBy time.Time, limit int) ([]domain.CampaignParticipant, error) {
+	var participants []domain.CampaignParticipant
+	if err := conn(ctx, r.db).
+		Where("campaign_id = ? AND status = ? AND due_at <= ? AND stage IN ?", campaignID, domain.CampaignParticipantOverdue, dueBy, from).
+		Order("participant_id").Limit(limit).Find(&participants).Error; err != nil {
+		return nil, fmt.Errorf("list escalatable campaign participants: %w", err)
+	}
+	return participants, nil
+}
+
+func (r *campaignRepository) Escalate(ctx context.Context, campaignID, participantID string, from, to domain.CampaignEscalationStage, at time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.CampaignParticipant{}).
+		Where("campaign_id = ? AND participant_id = ? AND status = ? AND stage = ?", campaignID, participantID, domain.CampaignParticipantOverdue, from).
+		Updates(map[string]interface{}{"stage": to, "escalated_at": at})
+	if result.Error != nil {
+		return false, fmt.Errorf("escalate campaign participant: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *campaignRepository) UpdateEscalation(ctx context.Context, campaignID string, escalation domain.CampaignEscalation) error {
+	if err := conn(ctx, r.db).Model(&domain.Campaign{}).Where("id = ?", campaignID).Updates(map[string]interface{}{
+		"escalation_reminder_days": escalation.ReminderDays,
+		"escalation_warning_days":  escalation.WarningDays,
+		"escalation_hold_days":     escalation.HoldDays,
+	}).Error; err != nil {
+		return fmt.Errorf("update campaign escalation: %w", err)
+	}
+	return nil
+}
+
+func (r *campaignRepository) DeleteByParticipant(ctx context.Context, participantID string) error {
+	db := conn(ctx, r.db)
+	if err := db.Where("participant_id = ?", participantID).Delete(&domain.CampaignParticipant{}).Error; err != nil {
+		return fmt.Errorf("delete campaign participants: %w", err)
+	}
+	if err := db.Where("participant_id = ?", participantID).Delete(&domain.CampaignExclusion{}).Error; err != nil {
+		return fmt.Errorf("delete campaign exclusions: %w", err)
+	}
+	return nil
+}
+
+func (r *campaignRepository) CreateExclusion(ctx context.Context, exclusion *domain.CampaignExclusion) error {
+	if err := conn(ctx, r.db).Create(exclusion).Error; err != nil {
+		return fmt.Errorf("create campaign exclusion: %w", err)
+	}
+	return nil
+}
+
+func (r *campaignRepository) GetExclusion(ctx context.Context, id string) (*domain.CampaignExclusion, error) {
+	var exclusion domain.CampaignExclusion
+	if err := conn(ctx, r.db).First(&exclusion, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get campaign exclusion: %w", err)
+	}
+	return &exclusion, nil
+}
+
+func (r *campaignRepository) FindExclusion(ctx context.Context, participantID string, list domain.CampaignExclusionList) (*domain.CampaignExclusion, error) {
+	var exclusion domain.CampaignExclusion
+	if err := conn(ctx, r.db).First(&exclusion, "participant_id = ? AND list = ?", participantID, list).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find campaign exclusion: %w", err)
+	}
+	return &exclusion, nil
+}
+
+func (r *campaignRepository) ListExclusions(ctx context.Context, filter CampaignExclusionFilter, page Pagination) ([]domain.CampaignExclusion, int64, error) {
+	query := conn(ctx, r.db).Model(&domain.CampaignExclusion{})
+	if filter.List != "" {
+		query = query.Where("list = ?", filter.List)
+	}
+	if filter.ParticipantID != "" {
+		query = query.Where("participant_id = ?", filter.ParticipantID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count campaign exclusions: %w", err)
+	}
+
+	var exclusions []domain.CampaignExclusion
+	if err := query.Order("created_at desc, id").Offset(page.Offset()).Limit(page.PageSize).Find(&exclusions).Error; err != nil {
+		return nil, 0, fmt.Errorf("list campaign exclusions: %w", err)
+	}
+	return exclusions, total, nil
+}
+
+func (r *campaignRepository) DeleteExclusion(ctx context.Context, id string) error {
+	if err := conn(ctx, r.db).Where("id = ?", id).Delete(&domain.CampaignExclusion{}).Error; err != nil {
+		return fmt.Errorf("delete campaign exclusion: %w", err)
+	}
+	return nil
+}
+
+func (r *campaignRepository) CreateNotification(ctx context.Context, notification *domain.CampaignNotification) error {
+	if err := conn(ctx, r.db).Create(notification).Error; err != nil {
+		return fmt.Errorf("create campaign notification: %w", err)
+	}
+	return nil
+}
+
+func (r *campaignRepository) GetNotification(ctx context.Context, id string) (*domain.CampaignNotification, error) {
+	var notification domain.CampaignNotification
+	if err := conn(ctx, r.db).First(&notification, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get campaign notification: %w", err)
+	}
+	return &notification, nil
+}
+
+func (r *campaignRepository) UpdateNotification(ctx context.Context, notification *domain.CampaignNotification) error {
+	if err := conn(ctx, r.db).Save(notification).Error; err != nil {
+		return fmt.Errorf("update campaign notification: %w", err)
+	}
+	return nil
+}
+
+func (r *campaignRepository) ListNotifications(ctx context.Context, campaignID string) ([]domain.CampaignNotification, error) {
+	var notifications []domain.CampaignNotification
+	if err := conn(ctx, r.db).Where("campaign_id = ?", campaignID).Order("created_at desc").Find(&notifications).Error; err != nil {
+		return nil, fmt.Errorf("list campaign notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+func (r *campaignRepository) NextDueForParticipant(ctx context.Context, participantID string, today time.Time) (*CampaignDue, error) {
+	var dues []CampaignDue
+	if err := conn(ctx, r.db).Raw(`
+		SELECT cp.campaign_id, c.kind, cp.due_at, cp.stage, cp.escalated_at,
+			c.escalation_reminder_days, c.escalation_warning_days, c.escalation_hold_days
+		FROM campaign_participants cp
+		JOIN campaigns c ON c.id = cp.campaign_id
+		WHERE ? AND cp.participant_id = ? AND cp.status <> ? AND c.starts_at <= ?
+			AND NOT EXISTS (SELECT 1 FROM life_certificate lc WHERE lc.participant_id = cp.participant_id AND lc.status = ? AND lc.verified_at >= c.starts_at)
+		ORDER BY cp.due_at
+		LIMIT 1`,
+		tenantCondition(ctx, "cp.tenant_id"), participantID, domain.CampaignParticipantCompleted, today, domain.LifeCertificateStatusValid,
+	).Scan(&dues).Error; err != nil {
+		return nil, fmt.Errorf("get next campaign due: %w", err)
+	}
+	if len(dues) == 0 {
+		return nil, nil
+	}
+	return &dues[0], nil
+}
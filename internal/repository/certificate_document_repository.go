@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// CertificateDocumentRepository persists metadata of supporting documents.
+type CertificateDocumentRepository interface {
+	Create(ctx context.Context, document *domain.CertificateDocument) error
+	GetByID(ctx context.Context, id string) (*domain.CertificateDocument, error)
+	ListByCertificate(ctx context.Context, certificateID string) ([]domain.CertificateDocument, error)
+	DeleteByCertificate(ctx context.Context, certificateID string) error
+}
+
+type certificateDocumentRepository struct {
+	db *gorm.DB
+}
+
+// NewCertificateDocumentRepository creates a gorm-backed repository.
+func NewCertificateDocumentRepository(db *gorm.DB) CertificateDocumentRepository {
+	return &certificateDocumentRepository{db: db}
+}
+
+func (r *certificateDocumentRepository) Create(ctx context.Context, document *domain.CertificateDocument) error {
+	if err := conn(ctx, r.db).Create(document).Error; err != nil {
+		return fmt.Errorf("create certificate document: %w", err)
+	}
+	return nil
+}
+
+func (r *certificateDocumentRepository) GetByID(ctx context.Context, id string) (*domain.CertificateDocument, error) {
+	var document domain.CertificateDocument
+	if err := conn(ctx, r.db).First(&document, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get certificate document: %w", err)
+	}
+	return &document, nil
+}
+
+func (r *certificateDocumentRepository) ListByCertificate(ctx context.Context, certificateID string) ([]domain.CertificateDocument, error) {
+	var documents []domain.CertificateDocument
+	if err := conn(ctx, r.db).Where("certificate_id = ?", certificateID).Order("created_at asc").Find(&documents).Error; err != nil {
+		return nil, fmt.Errorf("list certificate documents: %w", err)
+	}
+	return documents, nil
+}
+
+func (r *certificateDocumentRepository) DeleteByCertificate(ctx context.Context, certificateID string) error {
+	if err := conn(ctx, r.db).Where("certificate_id = ?", certificateID).Delete(&domain.CertificateDocument{}).Error; err != nil {
+		return fmt.Errorf("delete certificate documents: %w", err)
+	}
+	return nil
+}
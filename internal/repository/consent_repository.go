@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// ConsentRepository persists acceptances of the biometric processing terms.
+type ConsentRepository interface {
+	Create(ctx context.Context, consent *domain.Consent) error
+	// GetLatest returns the latest acceptance of the terms version by the NIK.
+	GetLatest(ctx context.Context, nik, termsVersion string) (*domain.Consent, error)
+	ListByNIK(ctx context.Context, nik string) ([]domain.Consent, error)
+	// Anonymize moves the NIK's consents to a pseudonym and drops their evidence.
+	Anonymize(ctx context.Context, nik, pseudonym string) error
+}
+
+type consentRepository struct {
+	db *gorm.DB
+}
+
+// NewConsentRepository creates a gorm-backed repository.
+func NewConsentRepository(db *gorm.DB) ConsentRepository {
+	return &consentRepository{db: db}
+}
+
+func (r *consentRepository) Create(ctx context.Context, consent *domain.Consent) error {
+	if err := conn(ctx, r.db).Create(consent).Error; err != nil {
+		return fmt.Errorf("create consent: %w", err)
+	}
+	return nil
+}
+
+func (r *consentRepository) GetLatest(ctx context.Context, nik, termsVersion string) (*domain.Consent, error) {
+	var consent domain.Consent
+	if err := conn(ctx, r.db).Where("nik = ? AND terms_version = ?", nik, termsVersion).Order("accepted_at desc").First(&consent).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get consent: %w", err)
+	}
+	return &consent, nil
+}
+
+func (r *consentRepository) ListByNIK(ctx context.Context, nik string) ([]domain.Consent, error) {
+	var consents []domain.Consent
+	if err := conn(ctx, r.db).Where("nik = ?", nik).Order("accepted_at desc").Find(&consents).Error; err != nil {
+		return nil, fmt.Errorf("list consents: %w", err)
+	}
+	return consents, nil
+}
+
+func (r *consentRepository) Anonymize(ctx context.Context, nik, pseudonym string) error {
+	if err := conn(ctx, r.db).Model(&domain.Consent{}).Where("nik = ?", nik).Updates(map[string]interface{}{
+		"nik":      pseudonym,
+		"evidence": "",
+	}).Error; err != nil {
+		return fmt.Errorf("anonymize consents: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// DeathReportRepository persists deaths reported by the civil registry.
+type DeathReportRepository interface {
+	Create(ctx context.Context, report *domain.DeathReport) error
+	GetByID(ctx context.Context, id string) (*domain.DeathReport, error)
+	List(ctx context.Context, state domain.DeathReportState, page Pagination) ([]domain.DeathReport, int64, error)
+	Decide(ctx context.Context, report *domain.DeathReport) (bool, error)
+}
+
+type deathReportRepository struct {
+	db *gorm.DB
+}
+
+// NewDeathReportRepository creates a gorm-backed repository.
+func NewDeathReportRepository(db *gorm.DB) DeathReportRepository {
+	return &deathReportRepository{db: db}
+}
+
+func (r *deathReportRepository) Create(ctx context.Context, report *domain.DeathReport) error {
+	if err := conn(ctx, r.db).Create(report).Error; err != nil {
+		return fmt.Errorf("create death report: %w", err)
+	}
+	return nil
+}
+
+func (r *deathReportRepository) GetByID(ctx context.Context, id string) (*domain.DeathReport, error) {
+	var report domain.DeathReport
+	if err := conn(ctx, r.db).First(&report, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get death report: %w", err)
+	}
+	return &report, nil
+}
+
+// List returns reports in the given state, oldest first; an empty state lists all.
+func (r *deathReportRepository) List(ctx context.Context, state domain.DeathReportState, page Pagination) ([]domain.DeathReport, int64, error) {
+	query := conn(ctx, r.db).Model(&domain.DeathReport{})
+	if state != "" {
+		query = query.Where("state = ?", state)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count death reports: %w", err)
+	}
+
+	var reports []domain.DeathReport
+	if err := query.Order("reported_at asc").Offset(page.Offset()).Limit(page.PageSize).Find(&reports).Error; err != nil {
+		return nil, 0, fmt.Errorf("list death reports: %w", err)
+	}
+	return reports, total, nil
+}
+
+// Decide records the decision on a pending report. It reports false when the
+// report was already decided.
+func (r *deathReportRepository) Decide(ctx context.Context, report *domain.DeathReport) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.DeathReport{}).
+		Where("id = ? AND state = ?", report.ID, domain.DeathReportPending).
+		Updates(map[string]interface{}{
+			"state":          report.State,
+			"decided_by":     report.DecidedBy,
+			"decided_at":     report.DecidedAt,
+			"decision_notes": report.DecisionNotes,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("decide death report: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
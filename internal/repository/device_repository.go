@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DeviceRepository persists participant devices registered for push notifications.
+type DeviceRepository interface {
+	// Register stores the device, moving an already known token to the participant and revalidating it.
+	Register(ctx context.Context, device *domain.ParticipantDevice) error
+	GetByID(ctx context.Context, id string) (*domain.ParticipantDevice, error)
+	ListByParticipant(ctx context.Context, participantID string) ([]domain.ParticipantDevice, error)
+	// ListActive returns the participant's devices whose token has not been invalidated.
+	ListActive(ctx context.Context, participantID string) ([]domain.ParticipantDevice, error)
+	Invalidate(ctx context.Context, id string, at time.Time) error
+	Delete(ctx context.Context, id string) error
+}
+
+type deviceRepository struct {
+	db *gorm.DB
+}
+
+// NewDeviceRepository creates a gorm-backed repository.
+func NewDeviceRepository(db *gorm.DB) DeviceRepository {
+	return &deviceRepository{db: db}
+}
+
+func (r *deviceRepository) Register(ctx context.Context, device *domain.ParticipantDevice) error {
+	err := conn(ctx, r.db).Clauses(
+		clause.OnConflict{
+			Columns: []clause.Column{{Name: "token"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"participant_id": device.ParticipantID,
+				"tenant_id":      gorm.Expr("excluded.tenant_id"),
+				"platform":       device.Platform,
+				"updated_at":     device.UpdatedAt,
+				"invalidated_at": nil,
+			}),
+		},
+		clause.Returning{},
+	).Create(device).Error
+	if err != nil {
+		return fmt.Errorf("register device: %w", err)
+	}
+	return nil
+}
+
+func (r *deviceRepository) GetByID(ctx context.Context, id string) (*domain.ParticipantDevice, error) {
+	var device domain.ParticipantDevice
+	if err := conn(ctx, r.db).First(&device, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get device: %w", err)
+	}
+	return &device, nil
+}
+
+func (r *deviceRepository) ListByParticipant(ctx context.Context, participantID string) ([]domain.ParticipantDevice, error) {
+	var devices []domain.ParticipantDevice
+	if err := conn(ctx, r.db).Where("participant_id = ?", participantID).Order("created_at asc").Find(&devices).Error; err != nil {
+		return nil, fmt.Errorf("list devices: %w", err)
+	}
+	return devices, nil
+}
+
+func (r *deviceRepository) ListActive(ctx context.Context, participantID string) ([]domain.ParticipantDevice, error) {
+	var devices []domain.ParticipantDevice
+	if err := conn(ctx, r.db).
+		Where("participant_id = ? AND invalidated_at IS NULL", participantID).
+		Order("created_at asc").
+		Find(&devices).Error; err != nil {
+		return nil, fmt.Errorf("list active devices: %w", err)
+	}
+	return devices, nil
+}
+
+func (r *deviceRepository) Invalidate(ctx context.Context, id string, at time.Time) error {
+	if err := conn(ctx, r.db).Model(&domain.ParticipantDevice{}).
+		Where("id = ?", id).
+		Update("invalidated_at", at).Error; err != nil {
+		return fmt.Errorf("invalidate device: %w", err)
+	}
+	return nil
+}
+
+func (r *deviceRepository) Delete(ctx context.Context, id string) error {
+	if err := conn(ctx, r.db).Delete(&domain.ParticipantDevice{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("delete device: %w", err)
+	}
+	return nil
+}
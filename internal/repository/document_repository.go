@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// DocumentRepository persists metadata of documents filed under other records.
+type DocumentRepository interface {
+	Create(ctx context.Context, document *domain.Document) error
+	GetByID(ctx context.Context, id string) (*domain.Document, error)
+	ListByOwner(ctx context.Context, ownerType domain.DocumentOwnerType, ownerID string) ([]domain.Document, error)
+	Delete(ctx context.Context, id string) error
+	DeleteByOwner(ctx context.Context, ownerType domain.DocumentOwnerType, ownerID string) error
+}
+
+type documentRepository struct {
+	db *gorm.DB
+}
+
+// NewDocumentRepository creates a gorm-backed repository.
+func NewDocumentRepository(db *gorm.DB) DocumentRepository {
+	return &documentRepository{db: db}
+}
+
+func (r *documentRepository) Create(ctx context.Context, document *domain.Document) error {
+	if err := conn(ctx, r.db).Create(document).Error; err != nil {
+		return fmt.Errorf("create document: %w", err)
+	}
+	return nil
+}
+
+func (r *documentRepository) GetByID(ctx context.Context, id string) (*domain.Document, error) {
+	var document domain.Document
+	if err := conn(ctx, r.db).First(&document, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get document: %w", err)
+	}
+	return &document, nil
+}
+
+func (r *documentRepository) ListByOwner(ctx context.Context, ownerType domain.DocumentOwnerType, ownerID string) ([]domain.Document, error) {
+	var documents []domain.Document
+	if err := conn(ctx, r.db).Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).Order("created_at asc").Find(&documents).Error; err != nil {
+		return nil, fmt.Errorf("list documents: %w", err)
+	}
+	return documents, nil
+}
+
+func (r *documentRepository) Delete(ctx context.Context, id string) error {
+	if err := conn(ctx, r.db).Delete(&domain.Document{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("delete document: %w", err)
+	}
+	return nil
+}
+
+func (r *documentRepository) DeleteByOwner(ctx context.Context, ownerType domain.DocumentOwnerType, ownerID string) error {
+	if err := conn(ctx, r.db).Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).Delete(&domain.Document{}).Error; err != nil {
+		return fmt.Errorf("delete documents: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// FacialConflictRepository persists attempts matched to another participant's face.
+type FacialConflictRepository interface {
+	Create(ctx context.Context, conflict *domain.FacialConflict) error
+	GetByID(ctx context.Context, id string) (*domain.FacialConflict, error)
+	List(ctx context.Context, state domain.FacialConflictState, page Pagination) ([]domain.FacialConflict, int64, error)
+	Decide(ctx context.Context, conflict *domain.FacialConflict) (bool, error)
+	// DeleteByParticipant removes the conflicts the participant is either side of.
+	DeleteByParticipant(ctx context.Context, participantID string) error
+}
+
+type facialConflictRepository struct {
+	db *gorm.DB
+}
+
+// NewFacialConflictRepository creates a gorm-backed repository.
+func NewFacialConflictRepository(db *gorm.DB) FacialConflictRepository {
+	return &facialConflictRepository{db: db}
+}
+
+func (r *facialConflictRepository) Create(ctx context.Context, conflict *domain.FacialConflict) error {
+	if err := conn(ctx, r.db).Create(conflict).Error; err != nil {
+		return fmt.Errorf("create facial conflict: %w", err)
+	}
+	return nil
+}
+
+func (r *facialConflictRepository) GetByID(ctx context.Context, id string) (*domain.FacialConflict, error) {
+	var conflict domain.FacialConflict
+	if err := conn(ctx, r.db).First(&conflict, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get facial conflict: %w", err)
+	}
+	return &conflict, nil
+}
+
+// List returns conflicts in the given state, oldest first; an empty state lists all.
+func (r *facialConflictRepository) List(ctx context.Context, state domain.FacialConflictState, page Pagination) ([]domain.FacialConflict, int64, error) {
+	query := conn(ctx, r.db).Model(&domain.FacialConflict{})
+	if state != "" {
+		query = query.Where("state = ?", state)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count facial conflicts: %w", err)
+	}
+
+	var conflicts []domain.FacialConflict
+	if err := query.Order("detected_at asc").Offset(page.Offset()).Limit(page.PageSize).Find(&conflicts).Error; err != nil {
+		return nil, 0, fmt.Errorf("list facial conflicts: %w", err)
+	}
+	return conflicts, total, nil
+}
+
+// Decide records the decision on a pending conflict. It reports false when
+// the conflict was already decided.
+func (r *facialConflictRepository) Decide(ctx context.Context, conflict *domain.FacialConflict) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.FacialConflict{}).
+		Where("id = ? AND state = ?", conflict.ID, domain.FacialConflictPending).
+		Updates(map[string]interface{}{
+			"state":          conflict.State,
+			"decided_by":     conflict.DecidedBy,
+			"decided_at":     conflict.DecidedAt,
+			"decision_notes": conflict.DecisionNotes,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("decide facial conflict: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *facialConflictRepository) DeleteByParticipant(ctx context.Context, participantID string) error {
+	if err := conn(ctx, r.db).Where("participant_id = ? OR matched_participant_id = ?", participantID, participantID).Delete(&domain.FacialConflict{}).Error; err != nil {
+		return fmt.Errorf("delete facial conflicts: %w", err)
+	}
+	return nil
+}
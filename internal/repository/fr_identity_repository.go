@@ -15,6 +15,8 @@ import (
 type FRIdentityRepository interface {
 	Create(ctx context.Context, identity *domain.FRIdentity) error
 	GetByLabel(ctx context.Context, label string) (*domain.FRIdentity, error)
+	ListByParticipantID(ctx context.Context, participantID string) ([]domain.FRIdentity, error)
+	ListLabels(ctx context.Context) ([]string, error)
 	DeleteByParticipantID(ctx context.Context, participantID string) error
 }
 
@@ -31,7 +33,10 @@ func (r *frIdentityRepository) Create(ctx context.Context, identity *domain.FRId
 	if identity.CreatedAt.IsZero() {
 		identity.CreatedAt = time.Now().UTC()
 	}
-	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(identity).Error; err != nil {
+	if identity.Source == "" {
+		identity.Source = domain.FRIdentitySourceRegistration
+	}
+	if err := conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(identity).Error; err != nil {
 		return fmt.Errorf("create fr identity: %w", err)
 	}
 	return nil
@@ -39,7 +44,7 @@ func (r *frIdentityRepository) Create(ctx context.Context, identity *domain.FRId
 
 func (r *frIdentityRepository) GetByLabel(ctx context.Context, label string) (*domain.FRIdentity, error) {
 	var identity domain.FRIdentity
-	if err := r.db.WithContext(ctx).First(&identity, "label = ?", label).Error; err != nil {
+	if err := conn(ctx, r.db).First(&identity, "label = ?", label).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
 		}
@@ -48,8 +53,24 @@ func (r *frIdentityRepository) GetByLabel(ctx context.Context, label string) (*d
 	return &identity, nil
 }
 
+func (r *frIdentityRepository) ListByParticipantID(ctx context.Context, participantID string) ([]domain.FRIdentity, error) {
+	var identities []domain.FRIdentity
+	if err := conn(ctx, r.db).Where("participant_id = ?", participantID).Order("created_at asc").Find(&identities).Error; err != nil {
+		return nil, fmt.Errorf("list fr identities by participant: %w", err)
+	}
+	return identities, nil
+}
+
+func (r *frIdentityRepository) ListLabels(ctx context.Context) ([]string, error) {
+	var labels []string
+	if err := conn(ctx, r.db).Model(&domain.FRIdentity{}).Pluck("label", &labels).Error; err != nil {
+		return nil, fmt.Errorf("list fr identity labels: %w", err)
+	}
+	return labels, nil
+}
+
 func (r *frIdentityRepository) DeleteByParticipantID(ctx context.Context, participantID string) error {
-	if err := r.db.WithContext(ctx).Where("participant_id = ?", participantID).Delete(&domain.FRIdentity{}).Error; err != nil {
+	if err := conn(ctx, r.db).Where("participant_id = ?", participantID).Delete(&domain.FRIdentity{}).Error; err != nil {
 		return fmt.Errorf("delete fr identity: %w", err)
 	}
 	return nil
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// FRReconciliationRepository persists FR Core reconciliation runs.
+type FRReconciliationRepository interface {
+	Create(ctx context.Context, run *domain.FRReconciliationRun) error
+	Update(ctx context.Context, run *domain.FRReconciliationRun) error
+	GetByID(ctx context.Context, id string) (*domain.FRReconciliationRun, error)
+	List(ctx context.Context, limit int) ([]domain.FRReconciliationRun, error)
+}
+
+type frReconciliationRepository struct {
+	db *gorm.DB
+}
+
+// NewFRReconciliationRepository creates a gorm-backed repository.
+func NewFRReconciliationRepository(db *gorm.DB) FRReconciliationRepository {
+	return &frReconciliationRepository{db: db}
+}
+
+func (r *frReconciliationRepository) Create(ctx context.Context, run *domain.FRReconciliationRun) error {
+	if err := conn(ctx, r.db).Create(run).Error; err != nil {
+		return fmt.Errorf("create fr reconciliation run: %w", err)
+	}
+	return nil
+}
+
+func (r *frReconciliationRepository) Update(ctx context.Context, run *domain.FRReconciliationRun) error {
+	if err := conn(ctx, r.db).Save(run).Error; err != nil {
+		return fmt.Errorf("update fr reconciliation run: %w", err)
+	}
+	return nil
+}
+
+func (r *frReconciliationRepository) GetByID(ctx context.Context, id string) (*domain.FRReconciliationRun, error) {
+	var run domain.FRReconciliationRun
+	if err := conn(ctx, r.db).First(&run, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get fr reconciliation run: %w", err)
+	}
+	return &run, nil
+}
+
+func (r *frReconciliationRepository) List(ctx context.Context, limit int) ([]domain.FRReconciliationRun, error) {
+	var runs []domain.FRReconciliationRun
+	if err := conn(ctx, r.db).Order("started_at desc").Limit(limit).Find(&runs).Error; err != nil {
+		return nil, fmt.Errorf("list fr reconciliation runs: %w", err)
+	}
+	return runs, nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// HoldReleaseRepository persists hold release callbacks to the payment system.
+type HoldReleaseRepository interface {
+	Create(ctx context.Context, release *domain.HoldRelease) error
+	GetByID(ctx context.Context, id string) (*domain.HoldRelease, error)
+	ListDue(ctx context.Context, now time.Time, limit int) ([]domain.HoldRelease, error)
+	Claim(ctx context.Context, id string, scheduledAt, leaseUntil time.Time) (bool, error)
+	Reserve(ctx context.Context, id string, leaseUntil time.Time) (bool, error)
+	Update(ctx context.Context, release *domain.HoldRelease) error
+	Requeue(ctx context.Context, id string, now time.Time) (bool, error)
+	List(ctx context.Context, status domain.HoldReleaseStatus, page Pagination) ([]domain.HoldRelease, int64, error)
+}
+
+type holdReleaseRepository struct {
+	db *gorm.DB
+}
+
+// NewHoldReleaseRepository creates a gorm-backed repository.
+func NewHoldReleaseRepository(db *gorm.DB) HoldReleaseRepository {
+	return &holdReleaseRepository{db: db}
+}
+
+// Create queues the release, ignoring a certificate already queued.
+func (r *holdReleaseRepository) Create(ctx context.Context, release *domain.HoldRelease) error {
+	if err := conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(release).Error; err != nil {
+		return fmt.Errorf("create hold release: %w", err)
+	}
+	return nil
+}
+
+func (r *holdReleaseRepository) GetByID(ctx context.Context, id string) (*domain.HoldRelease, error) {
+	var release domain.HoldRelease
+	if err := conn(ctx, r.db).First(&release, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get hold release: %w", err)
+	}
+	return &release, nil
+}
+
+// ListDue returns pending releases whose next attempt is due, oldest first.
+func (r *holdReleaseRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]domain.HoldRelease, error) {
+	var releases []domain.HoldRelease
+	if err := conn(ctx, r.db).
+		Where("status = ? AND next_attempt_at <= ?", domain.HoldReleasePending, now).
+		Order("next_attempt_at asc").
+		Limit(limit).
+		Find(&releases).Error; err != nil {
+		return nil, fmt.Errorf("list due hold releases: %w", err)
+	}
+	return releases, nil
+}
+
+// Claim pushes the next attempt out to leaseUntil so concurrent dispatchers skip it.
+// It reports false when another dispatcher already claimed the release.
+func (r *holdReleaseRepository) Claim(ctx context.Context, id string, scheduledAt, leaseUntil time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.HoldRelease{}).
+		Where("id = ? AND status = ? AND next_attempt_at = ?", id, domain.HoldReleasePending, scheduledAt).
+		Update("next_attempt_at", leaseUntil)
+	if result.Error != nil {
+		return false, fmt.Errorf("claim hold release: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Reserve moves a FAILED release to PENDING with its next attempt at leaseUntil,
+// so a manual re-send owns it. It reports false when the release is not FAILED.
+func (r *holdReleaseRepository) Reserve(ctx context.Context, id string, leaseUntil time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.HoldRelease{}).
+		Where("id = ? AND status = ?", id, domain.HoldReleaseFailed).
+		Updates(map[string]interface{}{
+			"status":          domain.HoldReleasePending,
+			"next_attempt_at": leaseUntil,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("reserve hold release: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *holdReleaseRepository) Update(ctx context.Context, release *domain.HoldRelease) error {
+	if err := conn(ctx, r.db).Model(&domain.HoldRelease{}).
+		Where("id = ?", release.ID).
+		Updates(map[string]interface{}{
+			"status":          release.Status,
+			"attempts":        release.Attempts,
+			"next_attempt_at": release.NextAttemptAt,
+			"response_code":   release.ResponseCode,
+			"response_body":   release.ResponseBody,
+			"ack_reference":   release.AckReference,
+			"last_error":      release.LastError,
+			"acknowledged_at": release.AcknowledgedAt,
+		}).Error; err != nil {
+		return fmt.Errorf("update hold release: %w", err)
+	}
+	return nil
+}
+
+// Requeue moves a FAILED release back to PENDING with a fresh attempt budget.
+func (r *holdReleaseRepository) Requeue(ctx context.Context, id string, now time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.HoldRelease{}).
+		Where("id = ? AND status = ?", id, domain.HoldReleaseFailed).
+		Updates(map[string]interface{}{
+			"status":          domain.HoldReleasePending,
+			"attempts":        0,
+			"next_attempt_at": now,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("requeue hold release: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// List returns releases filtered by status, oldest first.
+func (r *holdReleaseRepository) List(ctx context.Context, status domain.HoldReleaseStatus, page Pagination) ([]domain.HoldRelease, int64, error) {
+	query := conn(ctx, r.db).Model(&domain.HoldRelease{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count hold releases: %w", err)
+	}
+
+	var releases []domain.HoldRelease
+	if err := query.Order("created_at asc").Offset(page.Offset()).Limit(page.PageSize).Find(&releases).Error; err != nil {
+		return nil, 0, fmt.Errorf("list hold releases: %w", err)
+	}
+	return releases, total, nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"life-certificates/internal/domain"
+)
+
+// HolidayFilter narrows the holiday list.
+type HolidayFilter struct {
+	// Region keeps the region's holidays, in any case, and the national ones;
+	// empty keeps all.
+	Region string
+	// From and To bound the dates, inclusive.
+	From *time.Time
+	To   *time.Time
+}
+
+// HolidayRepository persists the holiday calendar.
+type HolidayRepository interface {
+	Create(ctx context.Context, holiday *domain.Holiday) error
+	GetByID(ctx context.Context, id string) (*domain.Holiday, error)
+	GetByDate(ctx context.Context, region string, date time.Time) (*domain.Holiday, error)
+	List(ctx context.Context, filter HolidayFilter) ([]domain.Holiday, error)
+	Delete(ctx context.Context, id string) error
+}
+
+type holidayRepository struct {
+	db *gorm.DB
+}
+
+// NewHolidayRepository creates a gorm-backed repository.
+func NewHolidayRepository(db *gorm.DB) HolidayRepository {
+	return &holidayRepository{db: db}
+}
+
+func (r *holidayRepository) Create(ctx context.Context, holiday *domain.Holiday) error {
+	if err := conn(ctx, r.db).Create(holiday).Error; err != nil {
+		return fmt.Errorf("create holiday: %w", err)
+	}
+	return nil
+}
+
+func (r *holidayRepository) GetByID(ctx context.Context, id string) (*domain.Holiday, error) {
+	var holiday domain.Holiday
+	if err := conn(ctx, r.db).First(&holiday, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get holiday: %w", err)
+	}
+	return &holiday, nil
+}
+
+func (r *holidayRepository) GetByDate(ctx context.Context, region string, date time.Time) (*domain.Holiday, error) {
+	var holiday domain.Holiday
+	if err := conn(ctx, r.db).First(&holiday, "LOWER(region) = ? AND date = ?", strings.ToLower(region), date).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get holiday: %w", err)
+	}
+	return &holiday, nil
+}
+
+func (r *holidayRepository) List(ctx context.Context, filter HolidayFilter) ([]domain.Holiday, error) {
+	query := conn(ctx, r.db).Model(&domain.Holiday{})
+	if filter.Region != "" {
+		query = query.Where("region = '' OR LOWER(region) = ?", strings.ToLower(filter.Region))
+	}
+	if filter.From != nil {
+		query = query.Where("date >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("date <= ?", *filter.To)
+	}
+	var holidays []domain.Holiday
+	if err := query.Order("date").Order("region").Find(&holidays).Error; err != nil {
+		return nil, fmt.Errorf("list holidays: %w", err)
+	}
+	return holidays, nil
+}
+
+func (r *holidayRepository) Delete(ctx context.Context, id string) error {
+	if err := conn(ctx, r.db).Delete(&domain.Holiday{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("delete holiday: %w", err)
+	}
+	return nil
+}
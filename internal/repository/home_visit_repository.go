@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"life-certificates/internal/domain"
+)
+
+// HomeVisitRepository persists home visits.
+type HomeVisitRepository interface {
+	Create(ctx context.Context, visit *domain.HomeVisit) error
+	GetByID(ctx context.Context, id string) (*domain.HomeVisit, error)
+	// FindOpen returns the participant's REQUESTED or SCHEDULED visit, if any.
+	FindOpen(ctx context.Context, participantID string) (*domain.HomeVisit, error)
+	List(ctx context.Context, filter HomeVisitFilter, page Pagination) ([]domain.HomeVisit, int64, error)
+	ListByParticipant(ctx context.Context, participantID string) ([]domain.HomeVisit, error)
+	// Update stores the visit's status, schedule and notes if it is still in
+	// one of the from statuses, reporting whether it was.
+	Update(ctx context.Context, visit *domain.HomeVisit, from ...domain.HomeVisitStatus) (bool, error)
+	// Complete links a SCHEDULED visit to the verification made on it,
+	// reporting whether the visit was still scheduled.
+	Complete(ctx context.Context, id, certificateID string, at time.Time) (bool, error)
+	DeleteByParticipant(ctx context.Context, participantID string) error
+	AnonymizeByParticipant(ctx context.Context, participantID string) error
+}
+
+// HomeVisitFilter narrows the home visit list. Empty fields are ignored;
+// From and To bound the scheduled time.
+type HomeVisitFilter struct {
+	ParticipantID string
+	Status        domain.HomeVisitStatus
+	OfficerID     string
+	BranchID      string
+	From          *time.Time
+	To            *time.Time
+}
+
+type homeVisitRepository struct {
+	db *gorm.DB
+}
+
+// NewHomeVisitRepository creates a gorm-backed repository.
+func NewHomeVisitRepository(db *gorm.DB) HomeVisitRepository {
+	return &homeVisitRepository{db: db}
+}
+
+func (r *homeVisitRepository) Create(ctx context.Context, visit *domain.HomeVisit) error {
+	if err := conn(ctx, r.db).Create(visit).Error; err != nil {
+		return fmt.Errorf("create home visit: %w", err)
+	}
+	return nil
+}
+
+func (r *homeVisitRepository) GetByID(ctx context.Context, id string) (*domain.HomeVisit, error) {
+	var visit domain.HomeVisit
+	if err := conn(ctx, r.db).First(&visit, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get home visit: %w", err)
+	}
+	return &visit, nil
+}
+
+func (r *homeVisitRepository) FindOpen(ctx context.Context, participantID string) (*domain.HomeVisit, error) {
+	var visits []domain.HomeVisit
+	if err := conn(ctx, r.db).
+		Where("participant_id = ? AND status IN ?", participantID, []domain.HomeVisitStatus{domain.HomeVisitRequested, domain.HomeVisitScheduled}).
+		Limit(1).Find(&visits).Error; err != nil {
+		return nil, fmt.Errorf("find open home visit: %w", err)
+	}
+	if len(visits) == 0 {
+		return nil, nil
+	}
+	return &visits[0], nil
+}
+
+// List returns visits by scheduled time, unscheduled ones last in request order.
+func (r *homeVisitRepository) List(ctx context.Context, filter HomeVisitFilter, page Pagination) ([]domain.HomeVisit, int64, error) {
+	query := conn(ctx, r.db).Model(&domain.HomeVisit{})
+	if filter.ParticipantID != "" {
+		query = query.Where("participant_id = ?", filter.ParticipantID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.OfficerID != "" {
+		query = query.Where("officer_id = ?", filter.OfficerID)
+	}
+	if filter.BranchID != "" {
+		query = query.Where("branch_id = ?", filter.BranchID)
+	}
+	if filter.From != nil {
+		query = query.Where("scheduled_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("scheduled_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count home visits: %w", err)
+	}
+
+	var visits []domain.HomeVisit
+	if err := query.Order("scheduled_at ASC NULLS LAST, created_at, id").Offset(page.Offset()).Limit(page.PageSize).Find(&visits).Error; err != nil {
+		return nil, 0, fmt.Errorf("list home visits: %w", err)
+	}
+	return visits, total, nil
+}
+
+func (r *homeVisitRepository) ListByParticipant(ctx context.Context, participantID string) ([]domain.HomeVisit, error) {
+	var visits []domain.HomeVisit
+	if err := conn(ctx, r.db).Where("participant_id = ?", participantID).Order("created_at").Find(&visits).Error; err != nil {
+		return nil, fmt.Errorf("list participant home visits: %w", err)
+	}
+	return visits, nil
+}
+
+func (r *homeVisitRepository) Update(ctx context.Context, visit *domain.HomeVisit, from ...domain.HomeVisitStatus) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.HomeVisit{}).
+		Where("id = ? AND status IN ?", visit.ID, from).
+		Updates(map[string]interface{}{
+			"status":       visit.Status,
+			"scheduled_at": visit.ScheduledAt,
+			"officer_id":   visit.OfficerID,
+			"officer_name": visit.OfficerName,
+			"branch_id":    visit.BranchID,
+			"notes":        visit.Notes,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("update home visit: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *homeVisitRepository) Complete(ctx context.Context, id, certificateID string, at time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.HomeVisit{}).
+		Where("id = ? AND status = ?", id, domain.HomeVisitScheduled).
+		Updates(map[string]interface{}{
+			"status":         domain.HomeVisitCompleted,
+			"certificate_id": certificateID,
+			"completed_at":   at,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("complete home visit: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *homeVisitRepository) DeleteByParticipant(ctx context.Context, participantID string) error {
+	if err := conn(ctx, r.db).Where("participant_id = ?", participantID).Delete(&domain.HomeVisit{}).Error; err != nil {
+		return fmt.Errorf("delete home visits: %w", err)
+	}
+	return nil
+}
+
+func (r *homeVisitRepository) AnonymizeByParticipant(ctx context.Context, participantID string) error {
+	if err := conn(ctx, r.db).Model(&domain.HomeVisit{}).Where("participant_id = ?", participantID).Updates(map[string]interface{}{
+		"address": "",
+		"reason":  "",
+		"notes":   nil,
+	}).Error; err != nil {
+		return fmt.Errorf("anonymize home visits: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// JobRepository persists background jobs. Every claim increments Attempts,
+// which doubles as a version so a worker whose lease expired cannot overwrite
+// the outcome of the worker that took the job over.
+type JobRepository interface {
+	Create(ctx context.Context, job *domain.Job) error
+	GetByID(ctx context.Context, id string) (*domain.Job, error)
+	List(ctx context.Context, filter JobFilter, page Pagination) ([]domain.Job, int64, error)
+	// ListDue returns queued jobs due by now and running jobs whose lease expired, oldest first.
+	ListDue(ctx context.Context, now time.Time, limit int) ([]domain.Job, error)
+	// Claim marks the job RUNNING until leaseUntil; false means another worker took it first.
+	Claim(ctx context.Context, job *domain.Job, now, leaseUntil time.Time) (bool, error)
+	// Finish stores the outcome of an attempt; false means the job was cancelled or reclaimed meanwhile.
+	Finish(ctx context.Context, job *domain.Job) (bool, error)
+	// Retry requeues a failed or cancelled job with a fresh set of attempts.
+	Retry(ctx context.Context, id string, now time.Time) (bool, error)
+	// Cancel stops a queued or running job.
+	Cancel(ctx context.Context, id string, now time.Time) (bool, error)
+}
+
+// JobFilter narrows job listings; empty fields match everything.
+type JobFilter struct {
+	Type   string
+	Status domain.JobStatus
+}
+
+type jobRepository struct {
+	db *gorm.DB
+}
+
+// NewJobRepository creates a gorm-backed repository.
+func NewJobRepository(db *gorm.DB) JobRepository {
+	return &jobRepository{db: db}
+}
+
+func (r *jobRepository) Create(ctx context.Context, job *domain.Job) error {
+	if err := conn(ctx, r.db).Create(job).Error; err != nil {
+		return fmt.Errorf("create job: %w", err)
+	}
+	return nil
+}
+
+func (r *jobRepository) GetByID(ctx context.Context, id string) (*domain.Job, error) {
+	var job domain.Job
+	if err := conn(ctx, r.db).First(&job, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get job: %w", err)
+	}
+	return &job, nil
+}
+
+func (r *jobRepository) List(ctx context.Context, filter JobFilter, page Pagination) ([]domain.Job, int64, error) {
+	query := conn(ctx, r.db).Model(&domain.Job{})
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count jobs: %w", err)
+	}
+
+	var jobs []domain.Job
+	if err := query.Order("created_at desc").Offset(page.Offset()).Limit(page.PageSize).Find(&jobs).Error; err != nil {
+		return nil, 0, fmt.Errorf("list jobs: %w", err)
+	}
+	return jobs, total, nil
+}
+
+func (r *jobRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]domain.Job, error) {
+	var jobs []domain.Job
+	if err := conn(ctx, r.db).
+		Where("(status = ? AND run_at <= ?) OR (status = ? AND lease_until <= ?)",
+			domain.JobStatusQueued, now, domain.JobStatusRunning, now).
+		Order("run_at asc").
+		Limit(limit).
+		Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("list due jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+func (r *jobRepository) Claim(ctx context.Context, job *domain.Job, now, leaseUntil time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.Job{}).
+		Where("id = ? AND status = ? AND attempts = ?", job.ID, job.Status, job.Attempts).
+		Updates(map[string]interface{}{
+			"status":      domain.JobStatusRunning,
+			"attempts":    job.Attempts + 1,
+			"lease_until": leaseUntil,
+			"started_at":  now,
+			"updated_at":  now,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("claim job: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	job.Status = domain.JobStatusRunning
+	job.Attempts++
+	job.LeaseUntil = &leaseUntil
+	job.StartedAt = &now
+	job.UpdatedAt = now
+	return true, nil
+}
+
+func (r *jobRepository) Finish(ctx context.Context, job *domain.Job) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.Job{}).
+		Where("id = ? AND status = ? AND attempts = ?", job.ID, domain.JobStatusRunning, job.Attempts).
+		Updates(map[string]interface{}{
+			"status":      job.Status,
+			"run_at":      job.RunAt,
+			"lease_until": nil,
+			"last_error":  job.LastError,
+			"result":      job.Result,
+			"finished_at": job.FinishedAt,
+			"updated_at":  job.UpdatedAt,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("finish job: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *jobRepository) Retry(ctx context.Context, id string, now time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.Job{}).
+		Where("id = ? AND status IN ?", id, []domain.JobStatus{domain.JobStatusFailed, domain.JobStatusCancelled}).
+		Updates(map[string]interface{}{
+			"status":      domain.JobStatusQueued,
+			"attempts":    0,
+			"run_at":      now,
+			"finished_at": nil,
+			"updated_at":  now,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("retry job: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *jobRepository) Cancel(ctx context.Context, id string, now time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.Job{}).
+		Where("id = ? AND status IN ?", id, []domain.JobStatus{domain.JobStatusQueued, domain.JobStatusRunning}).
+		Updates(map[string]interface{}{
+			"status":      domain.JobStatusCancelled,
+			"lease_until": nil,
+			"finished_at": now,
+			"updated_at":  now,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("cancel job: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
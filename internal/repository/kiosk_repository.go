@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// KioskRepository persists branch kiosks and reads the statuses operators look up.
+type KioskRepository interface {
+	Create(ctx context.Context, kiosk *domain.Kiosk) error
+	Get(ctx context.Context, id string) (*domain.Kiosk, error)
+	GetByKeyHash(ctx context.Context, hash string) (*domain.Kiosk, error)
+	List(ctx context.Context) ([]domain.Kiosk, error)
+	Revoke(ctx context.Context, id string, at time.Time) (bool, error)
+	Touch(ctx context.Context, id string, at, staleBefore time.Time) error
+	GetStatus(ctx context.Context, participantID string) (*ParticipantStatusRow, error)
+}
+
+type kioskRepository struct {
+	db *gorm.DB
+}
+
+// NewKioskRepository creates a gorm-backed repository.
+func NewKioskRepository(db *gorm.DB) KioskRepository {
+	return &kioskRepository{db: db}
+}
+
+func (r *kioskRepository) Create(ctx context.Context, kiosk *domain.Kiosk) error {
+	if err := conn(ctx, r.db).Create(kiosk).Error; err != nil {
+		return fmt.Errorf("create kiosk: %w", err)
+	}
+	return nil
+}
+
+func (r *kioskRepository) Get(ctx context.Context, id string) (*domain.Kiosk, error) {
+	var kiosk domain.Kiosk
+	if err := conn(ctx, r.db).First(&kiosk, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get kiosk: %w", err)
+	}
+	return &kiosk, nil
+}
+
+func (r *kioskRepository) GetByKeyHash(ctx context.Context, hash string) (*domain.Kiosk, error) {
+	var kiosk domain.Kiosk
+	if err := conn(ctx, r.db).First(&kiosk, "key_hash = ?", hash).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get kiosk: %w", err)
+	}
+	return &kiosk, nil
+}
+
+func (r *kioskRepository) List(ctx context.Context) ([]domain.Kiosk, error) {
+	var kiosks []domain.Kiosk
+	if err := conn(ctx, r.db).Order("branch_id asc, name asc").Find(&kiosks).Error; err != nil {
+		return nil, fmt.Errorf("list kiosks: %w", err)
+	}
+	return kiosks, nil
+}
+
+// Revoke revokes an active kiosk, reporting false when it was already revoked.
+func (r *kioskRepository) Revoke(ctx context.Context, id string, at time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.Kiosk{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return false, fmt.Errorf("revoke kiosk: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Touch records the kiosk's use unless it was already recorded after staleBefore.
+func (r *kioskRepository) Touch(ctx context.Context, id string, at, staleBefore time.Time) error {
+	if err := conn(ctx, r.db).Model(&domain.Kiosk{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, staleBefore).
+		Update("last_used_at", at).Error; err != nil {
+		return fmt.Errorf("touch kiosk: %w", err)
+	}
+	return nil
+}
+
+func (r *kioskRepository) GetStatus(ctx context.Context, participantID string) (*ParticipantStatusRow, error) {
+	var rows []ParticipantStatusRow
+	if err := participantStatusQuery(conn(ctx, r.db)).Where("t.participant_id = ?", participantID).Limit(1).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("get participant status: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return &rows[0], nil
+}
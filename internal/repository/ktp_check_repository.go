@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// KTPCheckRepository persists KTP checks made at registration.
+type KTPCheckRepository interface {
+	Create(ctx context.Context, check *domain.KTPCheck) error
+	GetByID(ctx context.Context, id string) (*domain.KTPCheck, error)
+	List(ctx context.Context, filter KTPCheckFilter, page Pagination) ([]domain.KTPCheck, int64, error)
+	ListByParticipant(ctx context.Context, participantID string) ([]domain.KTPCheck, error)
+	// Confirm moves a check awaiting confirmation to CONFIRMED, reporting
+	// false when it is no longer awaiting one.
+	Confirm(ctx context.Context, check *domain.KTPCheck) (bool, error)
+	DeleteByParticipant(ctx context.Context, participantID string) error
+	// AnonymizeByParticipant clears what was read from a participant's cards,
+	// keeping the checks' outcomes.
+	AnonymizeByParticipant(ctx context.Context, participantID string) error
+}
+
+// KTPCheckFilter narrows check queries; zero values match everything.
+type KTPCheckFilter struct {
+	ParticipantID string
+	Statuses      []domain.KTPCheckStatus
+}
+
+type ktpCheckRepository struct {
+	db *gorm.DB
+}
+
+// NewKTPCheckRepository creates a gorm-backed repository.
+func NewKTPCheckRepository(db *gorm.DB) KTPCheckRepository {
+	return &ktpCheckRepository{db: db}
+}
+
+func (r *ktpCheckRepository) Create(ctx context.Context, check *domain.KTPCheck) error {
+	if err := conn(ctx, r.db).Create(check).Error; err != nil {
+		return fmt.Errorf("create ktp check: %w", err)
+	}
+	return nil
+}
+
+func (r *ktpCheckRepository) GetByID(ctx context.Context, id string) (*domain.KTPCheck, error) {
+	var check domain.KTPCheck
+	if err := conn(ctx, r.db).First(&check, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get ktp check: %w", err)
+	}
+	return &check, nil
+}
+
+func (r *ktpCheckRepository) List(ctx context.Context, filter KTPCheckFilter, page Pagination) ([]domain.KTPCheck, int64, error) {
+	query := conn(ctx, r.db).Model(&domain.KTPCheck{})
+	if filter.ParticipantID != "" {
+		query = query.Where("participant_id = ?", filter.ParticipantID)
+	}
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count ktp checks: %w", err)
+	}
+
+	var checks []domain.KTPCheck
+	if err := query.Order("checked_at desc").Offset(page.Offset()).Limit(page.PageSize).Find(&checks).Error; err != nil {
+		return nil, 0, fmt.Errorf("list ktp checks: %w", err)
+	}
+	return checks, total, nil
+}
+
+func (r *ktpCheckRepository) ListByParticipant(ctx context.Context, participantID string) ([]domain.KTPCheck, error) {
+	var checks []domain.KTPCheck
+	if err := conn(ctx, r.db).Where("participant_id = ?", participantID).Order("checked_at asc").Find(&checks).Error; err != nil {
+		return nil, fmt.Errorf("list ktp checks by participant: %w", err)
+	}
+	return checks, nil
+}
+
+func (r *ktpCheckRepository) Confirm(ctx context.Context, check *domain.KTPCheck) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.KTPCheck{}).
+		Where("id = ? AND status IN ?", check.ID, []domain.KTPCheckStatus{domain.KTPCheckPending, domain.KTPCheckUnreadable}).
+		Updates(map[string]interface{}{
+			"status":       check.Status,
+			"confirmed_by": check.ConfirmedBy,
+			"confirmed_at": check.ConfirmedAt,
+			"notes":        check.Notes,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("confirm ktp check: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *ktpCheckRepository) DeleteByParticipant(ctx context.Context, participantID string) error {
+	if err := conn(ctx, r.db).Where("participant_id = ?", participantID).Delete(&domain.KTPCheck{}).Error; err != nil {
+		return fmt.Errorf("delete ktp checks: %w", err)
+	}
+	return nil
+}
+
+func (r *ktpCheckRepository) AnonymizeByParticipant(ctx context.Context, participantID string) error {
+	if err := conn(ctx, r.db).Model(&domain.KTPCheck{}).Where("participant_id = ?", participantID).Updates(map[string]interface{}{
+		"nik":        nil,
+		"name":       nil,
+		"birth_date": nil,
+		"warnings":   "[]",
+		"notes":      nil,
+	}).Error; err != nil {
+		return fmt.Errorf("anonymize ktp checks: %w", err)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"life-certificates/internal/domain"
 
@@ -12,8 +13,77 @@ import (
 // LifeCertificateRepository exposes persistence for verification attempts.
 type LifeCertificateRepository interface {
 	Create(ctx context.Context, record *domain.LifeCertificate) error
+	GetByID(ctx context.Context, id string) (*domain.LifeCertificate, error)
+	ListReviewQueue(ctx context.Context, filter ReviewQueueFilter, page Pagination) ([]domain.LifeCertificate, int64, error)
+	Claim(ctx context.Context, id, reviewer string, at time.Time) (bool, error)
+	Assign(ctx context.Context, id, reviewer string, at time.Time) (bool, error)
+	Resolve(ctx context.Context, record *domain.LifeCertificate) (bool, error)
+	OverrideStatus(ctx context.Context, record *domain.LifeCertificate, from domain.LifeCertificateStatus) (bool, error)
+	ListOverdueReviews(ctx context.Context, now time.Time) ([]domain.LifeCertificate, error)
+	BackfillReviewDueAt(ctx context.Context, sla time.Duration) (int64, error)
+	ReviewStats(ctx context.Context, from, to *time.Time, now time.Time) (*ReviewStats, error)
 	GetLatestByParticipant(ctx context.Context, participantID string) (*domain.LifeCertificate, error)
+	GetLatestByParticipantAndStatus(ctx context.Context, participantID string, status domain.LifeCertificateStatus) (*domain.LifeCertificate, error)
+	GetFirstBySelfieHash(ctx context.Context, hash string) (*domain.LifeCertificate, error)
+	CountOtherParticipantsBySelfieHash(ctx context.Context, hash, participantID string) (int64, error)
+	CountOtherParticipantsByDeviceSince(ctx context.Context, deviceID, participantID string, since time.Time) (int64, error)
+	CountOtherParticipantsByClientIPSince(ctx context.Context, clientIP, participantID string, since time.Time) (int64, error)
+	GetLatestPositionedByParticipant(ctx context.Context, participantID string) (*domain.LifeCertificate, error)
+	CountByParticipant(ctx context.Context, participantID string) (int64, error)
+	CountAutomaticSince(ctx context.Context, participantID string, since time.Time) (int64, error)
+	CountByStatusSince(ctx context.Context, since time.Time) (map[domain.LifeCertificateStatus]int64, error)
+	RepeatedStatus(ctx context.Context, status domain.LifeCertificateStatus, since time.Time, min int) ([]ParticipantCount, error)
 	DeleteByParticipant(ctx context.Context, participantID string) error
+	ListSelfiesToPurge(ctx context.Context, takenBefore *time.Time, latestValidOnly bool, limit int) ([]domain.LifeCertificate, error)
+	ClearSelfiePath(ctx context.Context, id string) error
+	SetDocument(ctx context.Context, id, path, signature string) error
+	ListByParticipant(ctx context.Context, participantID string) ([]domain.LifeCertificate, error)
+	AnonymizeByParticipant(ctx context.Context, participantID string) error
+	Stream(ctx context.Context, filter CertificateExportFilter, fn func(*CertificateExportRow) error) error
+}
+
+// ReviewQueueFilter narrows the pending review queue. Empty fields are ignored.
+type ReviewQueueFilter struct {
+	ParticipantID string
+	AssignedTo    string
+	Unassigned    bool
+	Senior        *bool
+	From          *time.Time
+	To            *time.Time
+}
+
+// CertificateExportFilter narrows a certificate export. Empty fields are ignored.
+type CertificateExportFilter struct {
+	Status   domain.LifeCertificateStatus
+	Method   domain.VerificationMethod
+	Channel  domain.VerificationChannel
+	Location string
+	// CampaignID keeps the attempts of the campaign's participants from its start date.
+	CampaignID string
+	From       *time.Time
+	To         *time.Time
+}
+
+// CertificateExportRow is an attempt together with the participant it belongs to.
+type CertificateExportRow struct {
+	domain.LifeCertificate
+	ParticipantNIK  string
+	ParticipantName string
+}
+
+// ReviewStats summarises the review backlog and decisions taken within a window.
+type ReviewStats struct {
+	Pending            int64
+	Overdue            int64
+	Decided            int64
+	DecidedLate        int64
+	AvgDecisionSeconds float64
+}
+
+// ParticipantCount is the number of attempts a participant made with a given outcome.
+type ParticipantCount struct {
+	ParticipantID string
+	Count         int64
 }
 
 type lifeCertificateRepository struct {
@@ -26,15 +96,176 @@ func NewLifeCertificateRepository(db *gorm.DB) LifeCertificateRepository {
 }
 
 func (r *lifeCertificateRepository) Create(ctx context.Context, record *domain.LifeCertificate) error {
-	if err := r.db.WithContext(ctx).Create(record).Error; err != nil {
+	if err := conn(ctx, r.db).Create(record).Error; err != nil {
 		return fmt.Errorf("create life certificate: %w", err)
 	}
 	return nil
 }
 
+func (r *lifeCertificateRepository) GetByID(ctx context.Context, id string) (*domain.LifeCertificate, error) {
+	var record domain.LifeCertificate
+	if err := conn(ctx, r.db).First(&record, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get life certificate: %w", err)
+	}
+	return &record, nil
+}
+
+// ListReviewQueue returns unresolved REVIEW attempts, oldest first.
+func (r *lifeCertificateRepository) ListReviewQueue(ctx context.Context, filter ReviewQueueFilter, page Pagination) ([]domain.LifeCertificate, int64, error) {
+	query := conn(ctx, r.db).Model(&domain.LifeCertificate{}).
+		Where("status = ? AND reviewed_at IS NULL", domain.LifeCertificateStatusReview)
+	if filter.ParticipantID != "" {
+		query = query.Where("participant_id = ?", filter.ParticipantID)
+	}
+	if filter.AssignedTo != "" {
+		query = query.Where("assigned_to = ?", filter.AssignedTo)
+	}
+	if filter.Unassigned {
+		query = query.Where("assigned_to IS NULL")
+	}
+	if filter.Senior != nil {
+		query = query.Where("senior_review = ?", *filter.Senior)
+	}
+	if filter.From != nil {
+		query = query.Where("verified_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("verified_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count review queue: %w", err)
+	}
+
+	var records []domain.LifeCertificate
+	if err := query.Order("verified_at asc").Offset(page.Offset()).Limit(page.PageSize).Find(&records).Error; err != nil {
+		return nil, 0, fmt.Errorf("list review queue: %w", err)
+	}
+	return records, total, nil
+}
+
+// Claim assigns an unresolved review to the reviewer unless someone else already holds it.
+func (r *lifeCertificateRepository) Claim(ctx context.Context, id, reviewer string, at time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.LifeCertificate{}).
+		Where("id = ? AND status = ? AND reviewed_at IS NULL", id, domain.LifeCertificateStatusReview).
+		Where("assigned_to IS NULL OR assigned_to = ?", reviewer).
+		Updates(map[string]interface{}{"assigned_to": reviewer, "assigned_at": at})
+	if result.Error != nil {
+		return false, fmt.Errorf("claim review: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Assign hands an unresolved review to the reviewer regardless of the current holder.
+func (r *lifeCertificateRepository) Assign(ctx context.Context, id, reviewer string, at time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.LifeCertificate{}).
+		Where("id = ? AND status = ? AND reviewed_at IS NULL", id, domain.LifeCertificateStatusReview).
+		Updates(map[string]interface{}{"assigned_to": reviewer, "assigned_at": at})
+	if result.Error != nil {
+		return false, fmt.Errorf("assign review: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Resolve stores the review decision if the attempt is still awaiting review.
+func (r *lifeCertificateRepository) Resolve(ctx context.Context, record *domain.LifeCertificate) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.LifeCertificate{}).
+		Where("id = ? AND status = ? AND reviewed_at IS NULL", record.ID, domain.LifeCertificateStatusReview).
+		Updates(map[string]interface{}{
+			"status":       record.Status,
+			"reviewed_by":  record.ReviewedBy,
+			"reviewed_at":  record.ReviewedAt,
+			"review_notes": record.ReviewNotes,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("resolve review: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// OverrideStatus sets the attempt's status to record.Status if it is still from.
+func (r *lifeCertificateRepository) OverrideStatus(ctx context.Context, record *domain.LifeCertificate, from domain.LifeCertificateStatus) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.LifeCertificate{}).
+		Where("id = ? AND status = ?", record.ID, from).
+		Update("status", record.Status)
+	if result.Error != nil {
+		return false, fmt.Errorf("override certificate status: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListOverdueReviews returns unresolved reviews whose SLA has passed, most overdue first.
+func (r *lifeCertificateRepository) ListOverdueReviews(ctx context.Context, now time.Time) ([]domain.LifeCertificate, error) {
+	var records []domain.LifeCertificate
+	if err := conn(ctx, r.db).
+		Where("status = ? AND reviewed_at IS NULL AND review_due_at < ?", domain.LifeCertificateStatusReview, now).
+		Order("review_due_at asc").
+		Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("list overdue reviews: %w", err)
+	}
+	return records, nil
+}
+
+// BackfillReviewDueAt sets a due date on pending reviews created before SLA tracking existed.
+func (r *lifeCertificateRepository) BackfillReviewDueAt(ctx context.Context, sla time.Duration) (int64, error) {
+	result := conn(ctx, r.db).Model(&domain.LifeCertificate{}).
+		Where("status = ? AND reviewed_at IS NULL AND review_due_at IS NULL", domain.LifeCertificateStatusReview).
+		Update("review_due_at", gorm.Expr("verified_at + (? * INTERVAL '1 second')", int64(sla.Seconds())))
+	if result.Error != nil {
+		return 0, fmt.Errorf("backfill review due dates: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// ReviewStats counts the current backlog and measures decisions made between from and to.
+func (r *lifeCertificateRepository) ReviewStats(ctx context.Context, from, to *time.Time, now time.Time) (*ReviewStats, error) {
+	stats := &ReviewStats{}
+	pending := func() *gorm.DB {
+		return conn(ctx, r.db).Model(&domain.LifeCertificate{}).
+			Where("status = ? AND reviewed_at IS NULL", domain.LifeCertificateStatusReview)
+	}
+	if err := pending().Count(&stats.Pending).Error; err != nil {
+		return nil, fmt.Errorf("count pending reviews: %w", err)
+	}
+	if err := pending().Where("review_due_at < ?", now).Count(&stats.Overdue).Error; err != nil {
+		return nil, fmt.Errorf("count overdue reviews: %w", err)
+	}
+
+	decided := conn(ctx, r.db).Model(&domain.LifeCertificate{}).Where("reviewed_at IS NOT NULL")
+	if from != nil {
+		decided = decided.Where("reviewed_at >= ?", *from)
+	}
+	if to != nil {
+		decided = decided.Where("reviewed_at < ?", *to)
+	}
+
+	var row struct {
+		Decided    int64
+		Late       int64
+		AvgSeconds *float64
+	}
+	if err := decided.Select(
+		"COUNT(*) AS decided, " +
+			"COUNT(*) FILTER (WHERE review_due_at IS NOT NULL AND reviewed_at > review_due_at) AS late, " +
+			"AVG(EXTRACT(EPOCH FROM (reviewed_at - verified_at))) AS avg_seconds",
+	).Scan(&row).Error; err != nil {
+		return nil, fmt.Errorf("aggregate review decisions: %w", err)
+	}
+	stats.Decided = row.Decided
+	stats.DecidedLate = row.Late
+	if row.AvgSeconds != nil {
+		stats.AvgDecisionSeconds = *row.AvgSeconds
+	}
+	return stats, nil
+}
+
 func (r *lifeCertificateRepository) GetLatestByParticipant(ctx context.Context, participantID string) (*domain.LifeCertificate, error) {
 	var record domain.LifeCertificate
-	if err := r.db.WithContext(ctx).
+	if err := conn(ctx, r.db).
 		Where("participant_id = ?", participantID).
 		Order("verified_at desc").
 		First(&record).Error; err != nil {
@@ -46,9 +277,273 @@ func (r *lifeCertificateRepository) GetLatestByParticipant(ctx context.Context,
 	return &record, nil
 }
 
+func (r *lifeCertificateRepository) GetLatestByParticipantAndStatus(ctx context.Context, participantID string, status domain.LifeCertificateStatus) (*domain.LifeCertificate, error) {
+	var record domain.LifeCertificate
+	if err := conn(ctx, r.db).
+		Where("participant_id = ? AND status = ?", participantID, status).
+		Order("verified_at desc").
+		First(&record).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get latest life certificate by status: %w", err)
+	}
+	return &record, nil
+}
+
+// GetFirstBySelfieHash returns the earliest attempt whose selfie has the given perceptual hash.
+func (r *lifeCertificateRepository) GetFirstBySelfieHash(ctx context.Context, hash string) (*domain.LifeCertificate, error) {
+	var record domain.LifeCertificate
+	if err := conn(ctx, r.db).
+		Where("selfie_hash = ?", hash).
+		Order("verified_at asc").
+		First(&record).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get life certificate by selfie hash: %w", err)
+	}
+	return &record, nil
+}
+
+// CountOtherParticipantsBySelfieHash counts the participants other than
+// participantID who submitted a selfie with the hash.
+func (r *lifeCertificateRepository) CountOtherParticipantsBySelfieHash(ctx context.Context, hash, participantID string) (int64, error) {
+	var count int64
+	if err := conn(ctx, r.db).Model(&domain.LifeCertificate{}).
+		Where("selfie_hash = ? AND participant_id <> ?", hash, participantID).
+		Distinct("participant_id").
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("count participants by selfie hash: %w", err)
+	}
+	return count, nil
+}
+
+// CountOtherParticipantsByDeviceSince counts the participants other than
+// participantID who submitted an attempt from the device at or after since.
+func (r *lifeCertificateRepository) CountOtherParticipantsByDeviceSince(ctx context.Context, deviceID, participantID string, since time.Time) (int64, error) {
+	var count int64
+	if err := conn(ctx, r.db).Model(&domain.LifeCertificate{}).
+		Where("device_id = ? AND participant_id <> ? AND verified_at >= ?", deviceID, participantID, since).
+		Distinct("participant_id").
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("count participants by device: %w", err)
+	}
+	return count, nil
+}
+
+// CountOtherParticipantsByClientIPSince counts the participants other than
+// participantID who submitted an attempt from the IP address at or after since.
+func (r *lifeCertificateRepository) CountOtherParticipantsByClientIPSince(ctx context.Context, clientIP, participantID string, since time.Time) (int64, error) {
+	var count int64
+	if err := conn(ctx, r.db).Model(&domain.LifeCertificate{}).
+		Where("client_ip = ? AND participant_id <> ? AND verified_at >= ?", clientIP, participantID, since).
+		Distinct("participant_id").
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("count participants by client IP: %w", err)
+	}
+	return count, nil
+}
+
+// GetLatestPositionedByParticipant returns the participant's latest attempt
+// with a declared or EXIF position.
+func (r *lifeCertificateRepository) GetLatestPositionedByParticipant(ctx context.Context, participantID string) (*domain.LifeCertificate, error) {
+	var record domain.LifeCertificate
+	if err := conn(ctx, r.db).
+		Where("participant_id = ? AND (latitude IS NOT NULL OR capture_latitude IS NOT NULL)", participantID).
+		Order("verified_at desc").
+		First(&record).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get latest positioned life certificate: %w", err)
+	}
+	return &record, nil
+}
+
+func (r *lifeCertificateRepository) CountByParticipant(ctx context.Context, participantID string) (int64, error) {
+	var count int64
+	if err := conn(ctx, r.db).Model(&domain.LifeCertificate{}).Where("participant_id = ?", participantID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("count life certificates: %w", err)
+	}
+	return count, nil
+}
+
+// CountAutomaticSince counts the participant's automatic attempts verified at or after since.
+func (r *lifeCertificateRepository) CountAutomaticSince(ctx context.Context, participantID string, since time.Time) (int64, error) {
+	var count int64
+	if err := conn(ctx, r.db).Model(&domain.LifeCertificate{}).
+		Where("participant_id = ? AND method = ? AND verified_at >= ?", participantID, domain.VerificationMethodAutomatic, since).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("count automatic attempts: %w", err)
+	}
+	return count, nil
+}
+
+// CountByStatusSince counts attempts per status verified at or after since.
+func (r *lifeCertificateRepository) CountByStatusSince(ctx context.Context, since time.Time) (map[domain.LifeCertificateStatus]int64, error) {
+	var rows []struct {
+		Status domain.LifeCertificateStatus
+		Count  int64
+	}
+	if err := conn(ctx, r.db).Model(&domain.LifeCertificate{}).
+		Select("status, COUNT(*) AS count").
+		Where("verified_at >= ?", since).
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("count life certificates by status: %w", err)
+	}
+	counts := make(map[domain.LifeCertificateStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// RepeatedStatus lists participants with at least min attempts of the status verified at or after since.
+func (r *lifeCertificateRepository) RepeatedStatus(ctx context.Context, status domain.LifeCertificateStatus, since time.Time, min int) ([]ParticipantCount, error) {
+	var rows []ParticipantCount
+	if err := conn(ctx, r.db).Model(&domain.LifeCertificate{}).
+		Select("participant_id, COUNT(*) AS count").
+		Where("status = ? AND verified_at >= ?", status, since).
+		Group("participant_id").
+		Having("COUNT(*) >= ?", min).
+		Order("count desc").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("list repeated statuses: %w", err)
+	}
+	return rows, nil
+}
+
 func (r *lifeCertificateRepository) DeleteByParticipant(ctx context.Context, participantID string) error {
-	if err := r.db.WithContext(ctx).Where("participant_id = ?", participantID).Delete(&domain.LifeCertificate{}).Error; err != nil {
+	if err := conn(ctx, r.db).Where("participant_id = ?", participantID).Delete(&domain.LifeCertificate{}).Error; err != nil {
 		return fmt.Errorf("delete life certificates: %w", err)
 	}
 	return nil
 }
+
+// ListSelfiesToPurge returns attempts with a stored selfie that a retention
+// rule no longer allows: verified before takenBefore, or, with
+// latestValidOnly, anything but the participant's latest VALID attempt and
+// attempts awaiting review.
+func (r *lifeCertificateRepository) ListSelfiesToPurge(ctx context.Context, takenBefore *time.Time, latestValidOnly bool, limit int) ([]domain.LifeCertificate, error) {
+	if takenBefore == nil && !latestValidOnly {
+		return nil, nil
+	}
+	db := conn(ctx, r.db)
+	expired := db.Where("1 = 0")
+	if takenBefore != nil {
+		expired = db.Where("verified_at < ?", *takenBefore)
+	}
+	if latestValidOnly {
+		superseded := db.Where("status <> ?", domain.LifeCertificateStatusReview).
+			Where("NOT (status = ? AND NOT EXISTS (?))", domain.LifeCertificateStatusValid,
+				db.Table("life_certificate AS newer").Select("1").
+					Where("newer.participant_id = life_certificate.participant_id AND newer.status = ? AND newer.verified_at > life_certificate.verified_at", domain.LifeCertificateStatusValid))
+		expired = expired.Or(superseded)
+	}
+
+	var records []domain.LifeCertificate
+	if err := db.Model(&domain.LifeCertificate{}).
+		Where("selfie_path <> ''").
+		Where(expired).
+		Order("verified_at asc").
+		Limit(limit).
+		Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("list selfies to purge: %w", err)
+	}
+	return records, nil
+}
+
+// ClearSelfiePath forgets the stored selfie of an attempt.
+func (r *lifeCertificateRepository) ClearSelfiePath(ctx context.Context, id string) error {
+	if err := conn(ctx, r.db).Model(&domain.LifeCertificate{}).Where("id = ?", id).Update("selfie_path", "").Error; err != nil {
+		return fmt.Errorf("clear selfie path: %w", err)
+	}
+	return nil
+}
+
+// SetDocument records where the PDF certificate of an attempt is stored and
+// its signature; empty values forget them.
+func (r *lifeCertificateRepository) SetDocument(ctx context.Context, id, path, signature string) error {
+	if err := conn(ctx, r.db).Model(&domain.LifeCertificate{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"document_path":      path,
+		"document_signature": signature,
+	}).Error; err != nil {
+		return fmt.Errorf("set document: %w", err)
+	}
+	return nil
+}
+
+func (r *lifeCertificateRepository) ListByParticipant(ctx context.Context, participantID string) ([]domain.LifeCertificate, error) {
+	var records []domain.LifeCertificate
+	if err := conn(ctx, r.db).Where("participant_id = ?", participantID).Order("verified_at asc").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("list life certificates by participant: %w", err)
+	}
+	return records, nil
+}
+
+// AnonymizeByParticipant clears the selfie, PDF certificate, capture position
+// and free-text notes of a participant's attempts, keeping their outcomes and
+// scores.
+func (r *lifeCertificateRepository) AnonymizeByParticipant(ctx context.Context, participantID string) error {
+	if err := conn(ctx, r.db).Model(&domain.LifeCertificate{}).Where("participant_id = ?", participantID).Updates(map[string]interface{}{
+		"selfie_path":        "",
+		"document_path":      "",
+		"document_signature": "",
+		"selfie_hash":        nil,
+		"capture_latitude":   nil,
+		"capture_longitude":  nil,
+		"capture_findings":   nil,
+		"latitude":           nil,
+		"longitude":          nil,
+		"client_ip":          nil,
+		"device_id":          nil,
+		"device_model":       nil,
+		"device_os":          nil,
+		"risk_signals":       nil,
+		"notes":              nil,
+		"review_notes":       nil,
+		"proxy_name":         nil,
+		"proxy_relationship": nil,
+	}).Error; err != nil {
+		return fmt.Errorf("anonymize life certificates: %w", err)
+	}
+	return nil
+}
+
+// Stream hands the attempts matching the filter to fn in verification order,
+// reading them from the database as fn consumes them.
+func (r *lifeCertificateRepository) Stream(ctx context.Context, filter CertificateExportFilter, fn func(*CertificateExportRow) error) error {
+	query := conn(ctx, r.db).Model(&domain.LifeCertificate{}).
+		Select("life_certificate.*, participants.nik AS participant_nik, participants.name AS participant_name").
+		Joins("LEFT JOIN participants ON participants.id = life_certificate.participant_id")
+	if filter.Status != "" {
+		query = query.Where("life_certificate.status = ?", filter.Status)
+	}
+	if filter.Method != "" {
+		query = query.Where("life_certificate.method = ?", filter.Method)
+	}
+	if filter.Channel != "" {
+		query = query.Where("life_certificate.channel = ?", filter.Channel)
+	}
+	if filter.Location != "" {
+		query = query.Where("life_certificate.location = ?", filter.Location)
+	}
+	if filter.CampaignID != "" {
+		query = query.Joins("JOIN campaign_participants ON campaign_participants.participant_id = life_certificate.participant_id AND campaign_participants.campaign_id = ?", filter.CampaignID).
+			Joins("JOIN campaigns ON campaigns.id = campaign_participants.campaign_id").
+			Where("life_certificate.verified_at >= campaigns.starts_at")
+	}
+	if filter.From != nil {
+		query = query.Where("life_certificate.verified_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("life_certificate.verified_at < ?", *filter.To)
+	}
+
+	if err := streamRows(query.Order("life_certificate.verified_at asc, life_certificate.id asc"), fn); err != nil {
+		return fmt.Errorf("stream life certificates: %w", err)
+	}
+	return nil
+}
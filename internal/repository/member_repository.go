@@ -16,8 +16,13 @@ type MemberRepository interface {
 	GetByNIK(ctx context.Context, nik string) (*domain.Member, error)
 	GetByNomorPeserta(ctx context.Context, nomorPeserta string) (*domain.Member, error)
 	List(ctx context.Context) ([]domain.Member, error)
+	Stream(ctx context.Context, fn func(*domain.Member) error) error
+	ListActiveAfter(ctx context.Context, afterID string, limit int) ([]domain.Member, error)
 	Update(ctx context.Context, member *domain.Member) error
 	Delete(ctx context.Context, id string) error
+	Merge(ctx context.Context, target *domain.Member, sourceID string, merge *domain.MemberMerge) error
+	ListMerges(ctx context.Context) ([]domain.MemberMerge, error)
+	Erase(ctx context.Context, member *domain.Member) error
 }
 
 type memberRepository struct {
@@ -30,7 +35,7 @@ func NewMemberRepository(db *gorm.DB) MemberRepository {
 }
 
 func (r *memberRepository) Create(ctx context.Context, member *domain.Member) error {
-	if err := r.db.WithContext(ctx).Create(member).Error; err != nil {
+	if err := conn(ctx, r.db).Create(member).Error; err != nil {
 		return fmt.Errorf("create member: %w", err)
 	}
 	return nil
@@ -38,7 +43,7 @@ func (r *memberRepository) Create(ctx context.Context, member *domain.Member) er
 
 func (r *memberRepository) GetByID(ctx context.Context, id string) (*domain.Member, error) {
 	var member domain.Member
-	if err := r.db.WithContext(ctx).First(&member, "id = ?", id).Error; err != nil {
+	if err := conn(ctx, r.db).First(&member, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
 		}
@@ -49,7 +54,7 @@ func (r *memberRepository) GetByID(ctx context.Context, id string) (*domain.Memb
 
 func (r *memberRepository) GetByNIK(ctx context.Context, nik string) (*domain.Member, error) {
 	var member domain.Member
-	if err := r.db.WithContext(ctx).First(&member, "nik = ?", nik).Error; err != nil {
+	if err := conn(ctx, r.db).First(&member, "nik = ?", nik).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
 		}
@@ -60,7 +65,7 @@ func (r *memberRepository) GetByNIK(ctx context.Context, nik string) (*domain.Me
 
 func (r *memberRepository) GetByNomorPeserta(ctx context.Context, nomorPeserta string) (*domain.Member, error) {
 	var member domain.Member
-	if err := r.db.WithContext(ctx).First(&member, "nomor_peserta = ?", nomorPeserta).Error; err != nil {
+	if err := conn(ctx, r.db).First(&member, "nomor_peserta = ?", nomorPeserta).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
 		}
@@ -71,14 +76,39 @@ func (r *memberRepository) GetByNomorPeserta(ctx context.Context, nomorPeserta s
 
 func (r *memberRepository) List(ctx context.Context) ([]domain.Member, error) {
 	var members []domain.Member
-	if err := r.db.WithContext(ctx).Order("created_at desc").Find(&members).Error; err != nil {
+	if err := conn(ctx, r.db).Order("created_at desc").Find(&members).Error; err != nil {
 		return nil, fmt.Errorf("list members: %w", err)
 	}
 	return members, nil
 }
 
+// ListActiveAfter returns up to limit ACTIVE, unerased members with IDs after
+// afterID, in ID order, so callers can page through them without holding a
+// cursor open.
+func (r *memberRepository) ListActiveAfter(ctx context.Context, afterID string, limit int) ([]domain.Member, error) {
+	var members []domain.Member
+	if err := conn(ctx, r.db).
+		Where("status = ? AND erased_at IS NULL AND id > ?", domain.MemberStatusActive, afterID).
+		Order("id asc").
+		Limit(limit).
+		Find(&members).Error; err != nil {
+		return nil, fmt.Errorf("list active members: %w", err)
+	}
+	return members, nil
+}
+
+// Stream hands every member to fn, newest first, reading them from the
+// database as fn consumes them.
+func (r *memberRepository) Stream(ctx context.Context, fn func(*domain.Member) error) error {
+	query := conn(ctx, r.db).Model(&domain.Member{}).Order("created_at desc, id asc")
+	if err := streamRows(query, fn); err != nil {
+		return fmt.Errorf("stream members: %w", err)
+	}
+	return nil
+}
+
 func (r *memberRepository) Update(ctx context.Context, member *domain.Member) error {
-	if err := r.db.WithContext(ctx).
+	if err := conn(ctx, r.db).
 		Model(&domain.Member{}).
 		Where("id = ?", member.ID).
 		Updates(map[string]interface{}{
@@ -91,6 +121,7 @@ func (r *memberRepository) Update(ctx context.Context, member *domain.Member) er
 			"province":      member.Province,
 			"phone_number":  member.PhoneNumber,
 			"email":         member.Email,
+			"status":        member.Status,
 			"updated_at":    member.UpdatedAt,
 		}).Error; err != nil {
 		return fmt.Errorf("update member: %w", err)
@@ -99,8 +130,90 @@ func (r *memberRepository) Update(ctx context.Context, member *domain.Member) er
 }
 
 func (r *memberRepository) Delete(ctx context.Context, id string) error {
-	if err := r.db.WithContext(ctx).Delete(&domain.Member{}, "id = ?", id).Error; err != nil {
-		return fmt.Errorf("delete member: %w", err)
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&domain.NotificationPreference{}, "member_id = ?", id).Error; err != nil {
+			return fmt.Errorf("delete member notification preferences: %w", err)
+		}
+		if err := tx.Delete(&domain.Member{}, "id = ?", id).Error; err != nil {
+			return fmt.Errorf("delete member: %w", err)
+		}
+		return nil
+	})
+}
+
+// Merge folds the source member into target within a single transaction: the
+// target row is updated, linked participants are re-pointed, the source's
+// notification preferences are kept only when the target has none, the source
+// row is removed and the merge is recorded.
+func (r *memberRepository) Merge(ctx context.Context, target *domain.Member, sourceID string, merge *domain.MemberMerge) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.Member{}).Where("id = ?", target.ID).Updates(map[string]interface{}{
+			"fullname":     target.FullName,
+			"address":      target.Address,
+			"city":         target.City,
+			"province":     target.Province,
+			"phone_number": target.PhoneNumber,
+			"email":        target.Email,
+			"updated_at":   target.UpdatedAt,
+		}).Error; err != nil {
+			return fmt.Errorf("update merge target: %w", err)
+		}
+
+		moved := tx.Model(&domain.Participant{}).Where("member_id = ?", sourceID).Update("member_id", target.ID)
+		if moved.Error != nil {
+			return fmt.Errorf("re-point participants: %w", moved.Error)
+		}
+		merge.ParticipantsMoved = int(moved.RowsAffected)
+
+		if err := tx.Exec(`UPDATE notification_preferences SET member_id = ?
+			WHERE ? AND member_id = ? AND NOT EXISTS (SELECT 1 FROM notification_preferences WHERE member_id = ?)`,
+			target.ID, tenantCondition(ctx, "tenant_id"), sourceID, target.ID).Error; err != nil {
+			return fmt.Errorf("move notification preferences: %w", err)
+		}
+		if err := tx.Delete(&domain.NotificationPreference{}, "member_id = ?", sourceID).Error; err != nil {
+			return fmt.Errorf("delete merge source notification preferences: %w", err)
+		}
+
+		if err := tx.Delete(&domain.Member{}, "id = ?", sourceID).Error; err != nil {
+			return fmt.Errorf("delete merge source: %w", err)
+		}
+
+		if err := tx.Create(merge).Error; err != nil {
+			return fmt.Errorf("record member merge: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *memberRepository) ListMerges(ctx context.Context) ([]domain.MemberMerge, error) {
+	var merges []domain.MemberMerge
+	if err := conn(ctx, r.db).Order("merged_at desc").Find(&merges).Error; err != nil {
+		return nil, fmt.Errorf("list member merges: %w", err)
+	}
+	return merges, nil
+}
+
+// Erase stores the anonymized member and redacts the snapshots of duplicates
+// merged into it, which hold the same person's data.
+func (r *memberRepository) Erase(ctx context.Context, member *domain.Member) error {
+	if err := conn(ctx, r.db).Model(&domain.Member{}).Where("id = ?", member.ID).Updates(map[string]interface{}{
+		"nik":           member.NIK,
+		"nomor_peserta": member.NomorPeserta,
+		"birth_date":    member.BirthDate,
+		"fullname":      member.FullName,
+		"address":       member.Address,
+		"phone_number":  member.PhoneNumber,
+		"email":         member.Email,
+		"erased_at":     member.ErasedAt,
+		"updated_at":    member.UpdatedAt,
+	}).Error; err != nil {
+		return fmt.Errorf("erase member: %w", err)
+	}
+	if err := conn(ctx, r.db).Model(&domain.MemberMerge{}).Where("target_member_id = ?", member.ID).Updates(map[string]interface{}{
+		"source_snapshot": "{}",
+		"notes":           "",
+	}).Error; err != nil {
+		return fmt.Errorf("redact member merges: %w", err)
 	}
 	return nil
 }
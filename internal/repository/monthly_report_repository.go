@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MonthlyReportRepository persists the generated monthly reports.
+type MonthlyReportRepository interface {
+	Get(ctx context.Context, period string) (*domain.MonthlyReport, error)
+	// Save stores the report, replacing one generated earlier for its period.
+	Save(ctx context.Context, report *domain.MonthlyReport) error
+}
+
+type monthlyReportRepository struct {
+	db *gorm.DB
+}
+
+// NewMonthlyReportRepository creates a gorm-backed repository.
+func NewMonthlyReportRepository(db *gorm.DB) MonthlyReportRepository {
+	return &monthlyReportRepository{db: db}
+}
+
+func (r *monthlyReportRepository) Get(ctx context.Context, period string) (*domain.MonthlyReport, error) {
+	var report domain.MonthlyReport
+	if err := conn(ctx, r.db).First(&report, "period = ?", period).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get monthly report: %w", err)
+	}
+	return &report, nil
+}
+
+func (r *monthlyReportRepository) Save(ctx context.Context, report *domain.MonthlyReport) error {
+	if err := conn(ctx, r.db).Clauses(clause.OnConflict{UpdateAll: true}).Create(report).Error; err != nil {
+		return fmt.Errorf("save monthly report: %w", err)
+	}
+	return nil
+}
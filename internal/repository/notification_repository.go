@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationRepository persists the notification delivery log, template overrides and member preferences.
+type NotificationRepository interface {
+	// CreateDelivery stores a delivery; false means one already exists for the event and template.
+	CreateDelivery(ctx context.Context, delivery *domain.NotificationDelivery) (bool, error)
+	GetDelivery(ctx context.Context, id string) (*domain.NotificationDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *domain.NotificationDelivery) error
+	// RequeueDelivery moves a FAILED delivery back to PENDING; false means it was not FAILED.
+	RequeueDelivery(ctx context.Context, id string) (bool, error)
+	ListDeliveries(ctx context.Context, filter NotificationFilter, page Pagination) ([]domain.NotificationDelivery, int64, error)
+	// CountBatch counts a batch's deliveries by status.
+	CountBatch(ctx context.Context, batchID string) (map[domain.NotificationStatus]int64, error)
+	GetTemplate(ctx context.Context, name string) (*domain.NotificationTemplate, error)
+	SaveTemplate(ctx context.Context, tmpl *domain.NotificationTemplate) error
+	DeleteTemplate(ctx context.Context, name string) error
+	GetPreference(ctx context.Context, memberID string) (*domain.NotificationPreference, error)
+	SavePreference(ctx context.Context, preference *domain.NotificationPreference) error
+	DeletePreference(ctx context.Context, memberID string) error
+	// AnonymizeDeliveries drops the recipient and content of a participant's notifications.
+	AnonymizeDeliveries(ctx context.Context, participantID string) error
+}
+
+// NotificationFilter narrows delivery listings; empty fields match everything.
+type NotificationFilter struct {
+	ParticipantID string
+	Channel       string
+	Recipient     string
+	Template      string
+	Status        domain.NotificationStatus
+	BatchID       string
+	From          *time.Time
+	To            *time.Time
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository creates a gorm-backed repository.
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) CreateDelivery(ctx context.Context, delivery *domain.NotificationDelivery) (bool, error) {
+	result := conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(delivery)
+	if result.Error != nil {
+		return false, fmt.Errorf("create notification delivery: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *notificationRepository) GetDelivery(ctx context.Context, id string) (*domain.NotificationDelivery, error) {
+	var delivery domain.NotificationDelivery
+	if err := conn(ctx, r.db).First(&delivery, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get notification delivery: %w", err)
+	}
+	return &delivery, nil
+}
+
+func (r *notificationRepository) UpdateDelivery(ctx context.Context, delivery *domain.NotificationDelivery) error {
+	if err := conn(ctx, r.db).Model(&domain.NotificationDelivery{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]interface{}{
+			"status":              delivery.Status,
+			"attempts":            delivery.Attempts,
+			"provider_message_id": delivery.ProviderMessageID,
+			"last_error":          delivery.LastError,
+			"sent_at":             delivery.SentAt,
+		}).Error; err != nil {
+		return fmt.Errorf("update notification delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *notificationRepository) RequeueDelivery(ctx context.Context, id string) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.NotificationDelivery{}).
+		Where("id = ? AND status = ?", id, domain.NotificationFailed).
+		Update("status", domain.NotificationPending)
+	if result.Error != nil {
+		return false, fmt.Errorf("requeue notification delivery: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *notificationRepository) ListDeliveries(ctx context.Context, filter NotificationFilter, page Pagination) ([]domain.NotificationDelivery, int64, error) {
+	query := conn(ctx, r.db).Model(&domain.NotificationDelivery{})
+	if filter.ParticipantID != "" {
+		query = query.Where("participant_id = ?", filter.ParticipantID)
+	}
+	if filter.Channel != "" {
+		query = query.Where("channel = ?", filter.Channel)
+	}
+	if filter.Recipient != "" {
+		query = query.Where("recipient = ?", filter.Recipient)
+	}
+	if filter.Template != "" {
+		query = query.Where("template = ?", filter.Template)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.BatchID != "" {
+		query = query.Where("batch_id = ?", filter.BatchID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count notification deliveries: %w", err)
+	}
+
+	var deliveries []domain.NotificationDelivery
+	if err := query.Order("created_at desc").Offset(page.Offset()).Limit(page.PageSize).Find(&deliveries).Error; err != nil {
+		return nil, 0, fmt.Errorf("list notification deliveries: %w", err)
+	}
+	return deliveries, total, nil
+}
+
+func (r *notificationRepository) CountBatch(ctx context.Context, batchID string) (map[domain.NotificationStatus]int64, error) {
+	var rows []struct {
+		Status domain.NotificationStatus
+		Count  int64
+	}
+	if err := conn(ctx, r.db).Model(&domain.NotificationDelivery{}).
+		Select("status, COUNT(*) AS count").
+		Where("batch_id = ?", batchID).
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("count notification batch: %w", err)
+	}
+	counts := make(map[domain.NotificationStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *notificationRepository) GetTemplate(ctx context.Context, name string) (*domain.NotificationTemplate, error) {
+	var tmpl domain.NotificationTemplate
+	if err := conn(ctx, r.db).First(&tmpl, "name = ?", name).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get notification template: %w", err)
+	}
+	return &tmpl, nil
+}
+
+func (r *notificationRepository) SaveTemplate(ctx context.Context, tmpl *domain.NotificationTemplate) error {
+	if err := conn(ctx, r.db).Save(tmpl).Error; err != nil {
+		return fmt.Errorf("save notification template: %w", err)
+	}
+	return nil
+}
+
+func (r *notificationRepository) DeleteTemplate(ctx context.Context, name string) error {
+	if err := conn(ctx, r.db).Delete(&domain.NotificationTemplate{}, "name = ?", name).Error; err != nil {
+		return fmt.Errorf("delete notification template: %w", err)
+	}
+	return nil
+}
+
+func (r *notificationRepository) GetPreference(ctx context.Context, memberID string) (*domain.NotificationPreference, error) {
+	var preference domain.NotificationPreference
+	if err := conn(ctx, r.db).First(&preference, "member_id = ?", memberID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get notification preference: %w", err)
+	}
+	return &preference, nil
+}
+
+func (r *notificationRepository) SavePreference(ctx context.Context, preference *domain.NotificationPreference) error {
+	if err := conn(ctx, r.db).Save(preference).Error; err != nil {
+		return fmt.Errorf("save notification preference: %w", err)
+	}
+	return nil
+}
+
+func (r *notificationRepository) DeletePreference(ctx context.Context, memberID string) error {
+	if err := conn(ctx, r.db).Delete(&domain.NotificationPreference{}, "member_id = ?", memberID).Error; err != nil {
+		return fmt.Errorf("delete notification preference: %w", err)
+	}
+	return nil
+}
+
+func (r *notificationRepository) AnonymizeDeliveries(ctx context.Context, participantID string) error {
+	// The recipient is part of the idempotency key, so each row gets a distinct placeholder.
+	if err := conn(ctx, r.db).Model(&domain.NotificationDelivery{}).Where("participant_id = ?", participantID).Updates(map[string]interface{}{
+		"recipient": gorm.Expr("'erased:' || id"),
+		"subject":   "",
+		"body":      "",
+	}).Error; err != nil {
+		return fmt.Errorf("anonymize notification deliveries: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"life-certificates/internal/domain"
+)
+
+// OfficerRepository persists officers and aggregates the verifications they performed.
+type OfficerRepository interface {
+	Create(ctx context.Context, officer *domain.Officer) error
+	GetByID(ctx context.Context, id string) (*domain.Officer, error)
+	GetByCode(ctx context.Context, code string) (*domain.Officer, error)
+	GetByUsername(ctx context.Context, username string) (*domain.Officer, error)
+	List(ctx context.Context, filter OfficerFilter, page Pagination) ([]domain.Officer, int64, error)
+	// ListAll returns every officer, for reports.
+	ListAll(ctx context.Context) ([]domain.Officer, error)
+	Update(ctx context.Context, officer *domain.Officer) error
+	// Activity counts, per officer ID on certificates, the verifications of
+	// [from, to). Manual and proxy verifications recorded less than
+	// fieldInterval after the officer's previous one count as rapid.
+	Activity(ctx context.Context, from, to time.Time, fieldInterval time.Duration) ([]OfficerActivity, error)
+}
+
+// OfficerFilter narrows the officer list. Empty fields are ignored.
+type OfficerFilter struct {
+	BranchID string
+	Region   string
+	Status   domain.OfficerStatus
+}
+
+// OfficerActivity is what an officer verified in a period. PeakDay is the
+// most verifications on one day.
+type OfficerActivity struct {
+	OfficerID     string
+	Verifications int64
+	Manual        int64
+	Proxy         int64
+	Kiosk         int64
+	Valid         int64
+	Invalid       int64
+	Review        int64
+	Participants  int64
+	PeakDay       int64
+	Rapid         int64
+}
+
+type officerRepository struct {
+	db *gorm.DB
+}
+
+// NewOfficerRepository creates a gorm-backed repository.
+func NewOfficerRepository(db *gorm.DB) OfficerRepository {
+	return &officerRepository{db: db}
+}
+
+func (r *officerRepository) Create(ctx context.Context, officer *domain.Officer) error {
+	if err := conn(ctx, r.db).Create(officer).Error; err != nil {
+		return fmt.Errorf("create officer: %w", err)
+	}
+	return nil
+}
+
+func (r *officerRepository) GetByID(ctx context.Context, id string) (*domain.Officer, error) {
+	return r.first(ctx, "id = ?", id)
+}
+
+func (r *officerRepository) GetByCode(ctx context.Context, code string) (*domain.Officer, error) {
+	return r.first(ctx, "code = ?", code)
+}
+
+func (r *officerRepository) GetByUsername(ctx context.Context, username string) (*domain.Officer, error) {
+	return r.first(ctx, "username = ?", username)
+}
+
+func (r *officerRepository) first(ctx context.Context, query string, arg interface{}) (*domain.Officer, error) {
+	var officer domain.Officer
+	if err := conn(ctx, r.db).First(&officer, query, arg).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get officer: %w", err)
+	}
+	return &officer, nil
+}
+
+func (r *officerRepository) List(ctx context.Context, filter OfficerFilter, page Pagination) ([]domain.Officer, int64, error) {
+	query := conn(ctx, r.db).Model(&domain.Officer{})
+	if filter.BranchID != "" {
+		query = query.Where("branch_id = ?", filter.BranchID)
+	}
+	if filter.Region != "" {
+		query = query.Where("region = ?", filter.Region)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count officers: %w", err)
+	}
+
+	var officers []domain.Officer
+	if err := query.Order("name, id").Offset(page.Offset()).Limit(page.PageSize).Find(&officers).Error; err != nil {
+		return nil, 0, fmt.Errorf("list officers: %w", err)
+	}
+	return officers, total, nil
+}
+
+func (r *officerRepository) ListAll(ctx context.Context) ([]domain.Officer, error) {
+	var officers []domain.Officer
+	if err := conn(ctx, r.db).Order("name, id").Find(&officers).Error; err != nil {
+		return nil, fmt.Errorf("list officers: %w", err)
+	}
+	return officers, nil
+}
+
+func (r *officerRepository) Update(ctx context.Context, officer *domain.Officer) error {
+	if err := conn(ctx, r.db).Model(&domain.Officer{}).
+		Where("id = ?", officer.ID).
+		Updates(map[string]interface{}{
+			"code":                  officer.Code,
+			"name":                  officer.Name,
+			"branch_id":             officer.BranchID,
+			"region":                officer.Region,
+			"username":              officer.Username,
+			"credential_number":     officer.CredentialNumber,
+			"credential_expires_at": officer.CredentialExpiresAt,
+			"status":                officer.Status,
+			"updated_at":            officer.UpdatedAt,
+		}).Error; err != nil {
+		return fmt.Errorf("update officer: %w", err)
+	}
+	return nil
+}
+
+func (r *officerRepository) Activity(ctx context.Context, from, to time.Time, fieldInterval time.Duration) ([]OfficerActivity, error) {
+	var rows []OfficerActivity
+	if err := conn(ctx, r.db).Table("life_certificate AS lc").
+		Select("lc.officer_id, COUNT(*) AS verifications, "+
+			"COUNT(*) FILTER (WHERE lc.method = ?) AS manual, "+
+			"COUNT(*) FILTER (WHERE lc.method = ?) AS proxy, "+
+			"COUNT(*) FILTER (WHERE lc.kiosk_id IS NOT NULL) AS kiosk, "+
+			"COUNT(*) FILTER (WHERE lc.status = ?) AS valid, "+
+			"COUNT(*) FILTER (WHERE lc.status = ?) AS invalid, "+
+			"COUNT(*) FILTER (WHERE lc.status = ?) AS review, "+
+			"COUNT(DISTINCT lc.participant_id) AS participants",
+			domain.VerificationMethodManual, domain.VerificationMethodProxy,
+			domain.LifeCertificateStatusValid, domain.LifeCertificateStatusInvalid, domain.LifeCertificateStatusReview).
+		Where("lc.officer_id IS NOT NULL AND lc.verified_at >= ? AND lc.verified_at < ?", from, to).
+		Group("lc.officer_id").Order("lc.officer_id").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("aggregate officer activity: %w", err)
+	}
+	if len(rows) == 0 {
+		return rows, nil
+	}
+
+	daily := conn(ctx, r.db).Table("life_certificate").
+		Select("officer_id, COUNT(*) AS verifications").
+		Where("officer_id IS NOT NULL AND verified_at >= ? AND verified_at < ?", from, to).
+		Group("officer_id, DATE(verified_at)")
+	var peaks []OfficerActivity
+	if err := conn(ctx, r.db).Table("(?) AS d", daily).
+		Select("officer_id, MAX(verifications) AS peak_day").
+		Group("officer_id").
+		Scan(&peaks).Error; err != nil {
+		return nil, fmt.Errorf("aggregate officer daily peaks: %w", err)
+	}
+
+	gaps := conn(ctx, r.db).Table("life_certificate").
+		Select("officer_id, verified_at - LAG(verified_at) OVER (PARTITION BY officer_id ORDER BY verified_at) AS gap").
+		Where("officer_id IS NOT NULL AND method IN ? AND verified_at >= ? AND verified_at < ?",
+			[]domain.VerificationMethod{domain.VerificationMethodManual, domain.VerificationMethodProxy}, from, to)
+	var rapid []OfficerActivity
+	if err := conn(ctx, r.db).Table("(?) AS g", gaps).
+		Select("officer_id, COUNT(*) AS rapid").
+		Where("gap < ? * INTERVAL '1 second'", fieldInterval.Seconds()).
+		Group("officer_id").
+		Scan(&rapid).Error; err != nil {
+		return nil, fmt.Errorf("aggregate officer rapid verifications: %w", err)
+	}
+
+	byOfficer := make(map[string]*OfficerActivity, len(rows))
+	for i := range rows {
+		byOfficer[rows[i].OfficerID] = &rows[i]
+	}
+	for _, peak := range peaks {
+		if row := byOfficer[peak.OfficerID]; row != nil {
+			row.PeakDay = peak.PeakDay
+		}
+	}
+	for _, count := range rapid {
+		if row := byOfficer[count.OfficerID]; row != nil {
+			row.Rapid = count.Rapid
+		}
+	}
+	return rows, nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// OutboxRepository persists events awaiting publication.
+type OutboxRepository interface {
+	Create(ctx context.Context, event *domain.OutboxEvent) error
+	ListDue(ctx context.Context, now time.Time, limit int) ([]domain.OutboxEvent, error)
+	Claim(ctx context.Context, id string, scheduledAt, leaseUntil time.Time) (bool, error)
+	MarkPublished(ctx context.Context, id string, at time.Time) error
+	MarkFailed(ctx context.Context, id string, attempts int, nextAttemptAt time.Time, lastError string) error
+	CountPending(ctx context.Context) (int64, error)
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository creates a gorm-backed repository.
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) Create(ctx context.Context, event *domain.OutboxEvent) error {
+	if err := conn(ctx, r.db).Create(event).Error; err != nil {
+		return fmt.Errorf("create outbox event: %w", err)
+	}
+	return nil
+}
+
+// ListDue returns unpublished events whose next attempt is due, in the order they were written.
+func (r *outboxRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]domain.OutboxEvent, error) {
+	var events []domain.OutboxEvent
+	if err := conn(ctx, r.db).
+		Where("published_at IS NULL AND next_attempt_at <= ?", now).
+		Order("created_at asc").
+		Limit(limit).
+		Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("list due outbox events: %w", err)
+	}
+	return events, nil
+}
+
+// Claim leases an event to this dispatcher; false means another dispatcher holds it.
+func (r *outboxRepository) Claim(ctx context.Context, id string, scheduledAt, leaseUntil time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.OutboxEvent{}).
+		Where("id = ? AND published_at IS NULL AND next_attempt_at = ?", id, scheduledAt).
+		Update("next_attempt_at", leaseUntil)
+	if result.Error != nil {
+		return false, fmt.Errorf("claim outbox event: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *outboxRepository) MarkPublished(ctx context.Context, id string, at time.Time) error {
+	if err := conn(ctx, r.db).Model(&domain.OutboxEvent{}).
+		Where("id = ?", id).
+		Update("published_at", at).Error; err != nil {
+		return fmt.Errorf("mark outbox event published: %w", err)
+	}
+	return nil
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, id string, attempts int, nextAttemptAt time.Time, lastError string) error {
+	if err := conn(ctx, r.db).Model(&domain.OutboxEvent{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":        attempts,
+			"next_attempt_at": nextAttemptAt,
+			"last_error":      lastError,
+		}).Error; err != nil {
+		return fmt.Errorf("mark outbox event failed: %w", err)
+	}
+	return nil
+}
+
+func (r *outboxRepository) CountPending(ctx context.Context) (int64, error) {
+	var count int64
+	if err := conn(ctx, r.db).Model(&domain.OutboxEvent{}).Where("published_at IS NULL").Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("count pending outbox events: %w", err)
+	}
+	return count, nil
+}
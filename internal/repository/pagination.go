@@ -0,0 +1,15 @@
+package repository
+
+// Pagination describes a page of results for list queries. Page is 1-based.
+type Pagination struct {
+	Page     int
+	PageSize int
+}
+
+// Offset returns the number of rows to skip for the requested page.
+func (p Pagination) Offset() int {
+	if p.Page <= 1 {
+		return 0
+	}
+	return (p.Page - 1) * p.PageSize
+}
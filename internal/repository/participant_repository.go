@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"life-certificates/internal/domain"
 
@@ -14,11 +16,25 @@ type ParticipantRepository interface {
 	Create(ctx context.Context, participant *domain.Participant) error
 	GetByID(ctx context.Context, id string) (*domain.Participant, error)
 	GetByNIK(ctx context.Context, nik string) (*domain.Participant, error)
+	GetByMemberID(ctx context.Context, memberID string) (*domain.Participant, error)
 	List(ctx context.Context) ([]domain.Participant, error)
+	Search(ctx context.Context, filter ParticipantFilter, page Pagination) ([]domain.Participant, int64, error)
 	Update(ctx context.Context, participant *domain.Participant) error
+	UpdateStatus(ctx context.Context, participant *domain.Participant) error
+	SetVerificationProfile(ctx context.Context, id string, profileID *string, at time.Time) error
+	ClearVerificationProfile(ctx context.Context, profileID string) error
 	Delete(ctx context.Context, id string) error
 }
 
+// ParticipantFilter narrows participant searches. Empty fields are ignored.
+type ParticipantFilter struct {
+	NIK     string
+	Name    string
+	FRLabel string
+	// Status matches the participant's latest life certificate status.
+	Status domain.LifeCertificateStatus
+}
+
 type participantRepository struct {
 	db *gorm.DB
 }
@@ -29,7 +45,7 @@ func NewParticipantRepository(db *gorm.DB) ParticipantRepository {
 }
 
 func (r *participantRepository) Create(ctx context.Context, participant *domain.Participant) error {
-	if err := r.db.WithContext(ctx).Create(participant).Error; err != nil {
+	if err := conn(ctx, r.db).Create(participant).Error; err != nil {
 		return fmt.Errorf("create participant: %w", err)
 	}
 	return nil
@@ -37,7 +53,7 @@ func (r *participantRepository) Create(ctx context.Context, participant *domain.
 
 func (r *participantRepository) GetByID(ctx context.Context, id string) (*domain.Participant, error) {
 	var participant domain.Participant
-	if err := r.db.WithContext(ctx).First(&participant, "id = ?", id).Error; err != nil {
+	if err := conn(ctx, r.db).First(&participant, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
 		}
@@ -48,7 +64,7 @@ func (r *participantRepository) GetByID(ctx context.Context, id string) (*domain
 
 func (r *participantRepository) GetByNIK(ctx context.Context, nik string) (*domain.Participant, error) {
 	var participant domain.Participant
-	if err := r.db.WithContext(ctx).First(&participant, "nik = ?", nik).Error; err != nil {
+	if err := conn(ctx, r.db).First(&participant, "nik = ?", nik).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
 		}
@@ -57,28 +73,104 @@ func (r *participantRepository) GetByNIK(ctx context.Context, nik string) (*doma
 	return &participant, nil
 }
 
+func (r *participantRepository) GetByMemberID(ctx context.Context, memberID string) (*domain.Participant, error) {
+	var participant domain.Participant
+	if err := conn(ctx, r.db).First(&participant, "member_id = ?", memberID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get participant by member id: %w", err)
+	}
+	return &participant, nil
+}
+
 func (r *participantRepository) List(ctx context.Context) ([]domain.Participant, error) {
 	var participants []domain.Participant
-	if err := r.db.WithContext(ctx).Order("created_at desc").Find(&participants).Error; err != nil {
+	if err := conn(ctx, r.db).Order("created_at desc").Find(&participants).Error; err != nil {
 		return nil, fmt.Errorf("list participants: %w", err)
 	}
 	return participants, nil
 }
 
+func (r *participantRepository) Search(ctx context.Context, filter ParticipantFilter, page Pagination) ([]domain.Participant, int64, error) {
+	query := conn(ctx, r.db).Model(&domain.Participant{})
+	if filter.NIK != "" {
+		query = query.Where("nik = ?", filter.NIK)
+	}
+	if filter.Name != "" {
+		query = query.Where(`name ILIKE ? ESCAPE '\'`, "%"+likeEscaper.Replace(filter.Name)+"%")
+	}
+	if filter.FRLabel != "" {
+		query = query.Where("id IN (?)", conn(ctx, r.db).Model(&domain.FRIdentity{}).Select("participant_id").Where("label = ?", filter.FRLabel))
+	}
+	if filter.Status != "" {
+		query = query.Where("id IN (?)", conn(ctx, r.db).Model(&domain.ParticipantVerificationState{}).Select("participant_id").Where("status = ?", filter.Status))
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count participants: %w", err)
+	}
+
+	var participants []domain.Participant
+	if err := query.Order("created_at desc").Offset(page.Offset()).Limit(page.PageSize).Find(&participants).Error; err != nil {
+		return nil, 0, fmt.Errorf("search participants: %w", err)
+	}
+	return participants, total, nil
+}
+
 func (r *participantRepository) Update(ctx context.Context, participant *domain.Participant) error {
-	if err := r.db.WithContext(ctx).Model(&domain.Participant{}).Where("id = ?", participant.ID).Updates(map[string]interface{}{
-		"nik":        participant.NIK,
-		"name":       participant.Name,
-		"updated_at": participant.UpdatedAt,
+	if err := conn(ctx, r.db).Model(&domain.Participant{}).Where("id = ?", participant.ID).Updates(map[string]interface{}{
+		"nik":          participant.NIK,
+		"name":         participant.Name,
+		"phone_number": participant.PhoneNumber,
+		"email":        participant.Email,
+		"address":      participant.Address,
+		"fund":         participant.Fund,
+		"updated_at":   participant.UpdatedAt,
 	}).Error; err != nil {
 		return fmt.Errorf("update participant: %w", err)
 	}
 	return nil
 }
 
+func (r *participantRepository) UpdateStatus(ctx context.Context, participant *domain.Participant) error {
+	if err := conn(ctx, r.db).Model(&domain.Participant{}).Where("id = ?", participant.ID).Updates(map[string]interface{}{
+		"status":            participant.Status,
+		"status_reason":     participant.StatusReason,
+		"status_changed_at": participant.StatusChangedAt,
+		"updated_at":        participant.UpdatedAt,
+	}).Error; err != nil {
+		return fmt.Errorf("update participant status: %w", err)
+	}
+	return nil
+}
+
+func (r *participantRepository) SetVerificationProfile(ctx context.Context, id string, profileID *string, at time.Time) error {
+	if err := conn(ctx, r.db).Model(&domain.Participant{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"verification_profile_id": profileID,
+		"updated_at":              at,
+	}).Error; err != nil {
+		return fmt.Errorf("set participant verification profile: %w", err)
+	}
+	return nil
+}
+
+// ClearVerificationProfile detaches every participant from the profile so they fall back to their fund's profile.
+func (r *participantRepository) ClearVerificationProfile(ctx context.Context, profileID string) error {
+	if err := conn(ctx, r.db).Model(&domain.Participant{}).Where("verification_profile_id = ?", profileID).
+		Update("verification_profile_id", nil).Error; err != nil {
+		return fmt.Errorf("clear participant verification profiles: %w", err)
+	}
+	return nil
+}
+
 func (r *participantRepository) Delete(ctx context.Context, id string) error {
-	if err := r.db.WithContext(ctx).Delete(&domain.Participant{}, "id = ?", id).Error; err != nil {
+	if err := conn(ctx, r.db).Delete(&domain.Participant{}, "id = ?", id).Error; err != nil {
 		return fmt.Errorf("delete participant: %w", err)
 	}
 	return nil
 }
+
+// likeEscaper makes %, _ and \ match literally in a LIKE pattern escaped with \.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"time"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// ParticipantStatusRow is a participant with their latest verification
+// outcome. The state columns are nil before the first attempt.
+type ParticipantStatusRow struct {
+	ParticipantID     string
+	NIK               string
+	MemberID          *string
+	Fund              *string
+	ParticipantStatus domain.ParticipantStatus
+	LatestStatus      *domain.LifeCertificateStatus
+	VerifiedAt        *time.Time
+	LastValidAt       *time.Time
+	ValidUntil        *time.Time
+	// ChangedAt is the later of the participant's and the state's last update.
+	ChangedAt time.Time
+}
+
+// participantStatusQuery selects ParticipantStatusRow columns from every
+// participant joined with their verification state, as the table "t".
+func participantStatusQuery(db *gorm.DB) *gorm.DB {
+	inner := db.Table("participants").
+		Select("participants.id AS participant_id, participants.nik, participants.member_id, participants.fund, " +
+			"participants.status AS participant_status, s.status AS latest_status, s.verified_at, s.last_valid_at, s.valid_until, " +
+			"GREATEST(participants.updated_at, COALESCE(s.updated_at, participants.updated_at)) AS changed_at").
+		Joins("LEFT JOIN participant_verification_state s ON s.participant_id = participants.id")
+	return db.Table("(?) AS t", inner)
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// PartnerChangesFilter selects a page of the partner change feed: participants
+// changed after (After, AfterID) in (changed_at, participant_id) order.
+type PartnerChangesFilter struct {
+	After   time.Time
+	AfterID string
+	// Fund limits the feed to one fund's participants when set.
+	Fund  string
+	Limit int
+}
+
+// PartnerRepository persists partner API keys and reads the statuses partners see.
+type PartnerRepository interface {
+	CreateKey(ctx context.Context, key *domain.PartnerAPIKey) error
+	GetKey(ctx context.Context, id string) (*domain.PartnerAPIKey, error)
+	GetKeyByHash(ctx context.Context, hash string) (*domain.PartnerAPIKey, error)
+	ListKeys(ctx context.Context) ([]domain.PartnerAPIKey, error)
+	RevokeKey(ctx context.Context, id string, at time.Time) (bool, error)
+	TouchKey(ctx context.Context, id string, at, staleBefore time.Time) error
+	GetStatusByNIK(ctx context.Context, nik string) (*ParticipantStatusRow, error)
+	ListChanges(ctx context.Context, filter PartnerChangesFilter) ([]ParticipantStatusRow, error)
+}
+
+type partnerRepository struct {
+	db *gorm.DB
+}
+
+// NewPartnerRepository creates a gorm-backed repository.
+func NewPartnerRepository(db *gorm.DB) PartnerRepository {
+	return &partnerRepository{db: db}
+}
+
+func (r *partnerRepository) CreateKey(ctx context.Context, key *domain.PartnerAPIKey) error {
+	if err := conn(ctx, r.db).Create(key).Error; err != nil {
+		return fmt.Errorf("create partner key: %w", err)
+	}
+	return nil
+}
+
+func (r *partnerRepository) GetKey(ctx context.Context, id string) (*domain.PartnerAPIKey, error) {
+	var key domain.PartnerAPIKey
+	if err := conn(ctx, r.db).First(&key, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get partner key: %w", err)
+	}
+	return &key, nil
+}
+
+func (r *partnerRepository) GetKeyByHash(ctx context.Context, hash string) (*domain.PartnerAPIKey, error) {
+	var key domain.PartnerAPIKey
+	if err := conn(ctx, r.db).First(&key, "key_hash = ?", hash).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get partner key: %w", err)
+	}
+	return &key, nil
+}
+
+func (r *partnerRepository) ListKeys(ctx context.Context) ([]domain.PartnerAPIKey, error) {
+	var keys []domain.PartnerAPIKey
+	if err := conn(ctx, r.db).Order("created_at asc").Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("list partner keys: %w", err)
+	}
+	return keys, nil
+}
+
+// RevokeKey revokes an active key, reporting false when it was already revoked.
+func (r *partnerRepository) RevokeKey(ctx context.Context, id string, at time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.PartnerAPIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return false, fmt.Errorf("revoke partner key: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// TouchKey records the key's use unless it was already recorded after staleBefore,
+// so busy partners do not write on every request.
+func (r *partnerRepository) TouchKey(ctx context.Context, id string, at, staleBefore time.Time) error {
+	if err := conn(ctx, r.db).Model(&domain.PartnerAPIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, staleBefore).
+		Update("last_used_at", at).Error; err != nil {
+		return fmt.Errorf("touch partner key: %w", err)
+	}
+	return nil
+}
+
+func (r *partnerRepository) GetStatusByNIK(ctx context.Context, nik string) (*ParticipantStatusRow, error) {
+	var rows []ParticipantStatusRow
+	if err := participantStatusQuery(conn(ctx, r.db)).Where("t.nik = ?", nik).Limit(1).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("get participant status: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return &rows[0], nil
+}
+
+// ListChanges returns the next page of the change feed.
+func (r *partnerRepository) ListChanges(ctx context.Context, filter PartnerChangesFilter) ([]ParticipantStatusRow, error) {
+	query := participantStatusQuery(conn(ctx, r.db)).
+		Where("(t.changed_at > ? OR (t.changed_at = ? AND t.participant_id > ?))", filter.After, filter.After, filter.AfterID)
+	if filter.Fund != "" {
+		query = query.Where("t.fund = ?", filter.Fund)
+	}
+
+	var rows []ParticipantStatusRow
+	if err := query.Order("t.changed_at asc, t.participant_id asc").Limit(filter.Limit).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("list partner changes: %w", err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PaymentPushSummary aggregates pushes the payment system has not acknowledged.
+type PaymentPushSummary struct {
+	Pending      int64
+	Failed       int64
+	Valid        int64
+	Expired      int64
+	OldestQueued *time.Time
+}
+
+// PaymentPushRepository persists status pushes to the payment system.
+type PaymentPushRepository interface {
+	Create(ctx context.Context, push *domain.PaymentPush) error
+	GetByID(ctx context.Context, id string) (*domain.PaymentPush, error)
+	ListDue(ctx context.Context, now time.Time, limit int) ([]domain.PaymentPush, error)
+	Claim(ctx context.Context, id string, scheduledAt, leaseUntil time.Time) (bool, error)
+	Update(ctx context.Context, push *domain.PaymentPush) error
+	Requeue(ctx context.Context, id string, now time.Time) (bool, error)
+	ListUnacknowledged(ctx context.Context, status domain.PaymentPushStatus, page Pagination) ([]domain.PaymentPush, int64, error)
+	SummarizeUnacknowledged(ctx context.Context) (*PaymentPushSummary, error)
+	ListExpiredWithoutPush(ctx context.Context, cutoff time.Time, limit int) ([]domain.LifeCertificate, error)
+}
+
+type paymentPushRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentPushRepository creates a gorm-backed repository.
+func NewPaymentPushRepository(db *gorm.DB) PaymentPushRepository {
+	return &paymentPushRepository{db: db}
+}
+
+// Create queues the push, ignoring a transition already queued for the certificate.
+func (r *paymentPushRepository) Create(ctx context.Context, push *domain.PaymentPush) error {
+	if err := conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(push).Error; err != nil {
+		return fmt.Errorf("create payment push: %w", err)
+	}
+	return nil
+}
+
+func (r *paymentPushRepository) GetByID(ctx context.Context, id string) (*domain.PaymentPush, error) {
+	var push domain.PaymentPush
+	if err := conn(ctx, r.db).First(&push, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get payment push: %w", err)
+	}
+	return &push, nil
+}
+
+// ListDue returns pending pushes whose next attempt is due, oldest first.
+func (r *paymentPushRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]domain.PaymentPush, error) {
+	var pushes []domain.PaymentPush
+	if err := conn(ctx, r.db).
+		Where("status = ? AND next_attempt_at <= ?", domain.PaymentPushPending, now).
+		Order("next_attempt_at asc").
+		Limit(limit).
+		Find(&pushes).Error; err != nil {
+		return nil, fmt.Errorf("list due payment pushes: %w", err)
+	}
+	return pushes, nil
+}
+
+// Claim pushes the next attempt out to leaseUntil so concurrent dispatchers skip it.
+// It reports false when another dispatcher already claimed the push.
+func (r *paymentPushRepository) Claim(ctx context.Context, id string, scheduledAt, leaseUntil time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.PaymentPush{}).
+		Where("id = ? AND status = ? AND next_attempt_at = ?", id, domain.PaymentPushPending, scheduledAt).
+		Update("next_attempt_at", leaseUntil)
+	if result.Error != nil {
+		return false, fmt.Errorf("claim payment push: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *paymentPushRepository) Update(ctx context.Context, push *domain.PaymentPush) error {
+	if err := conn(ctx, r.db).Model(&domain.PaymentPush{}).
+		Where("id = ?", push.ID).
+		Updates(map[string]interface{}{
+			"status":          push.Status,
+			"attempts":        push.Attempts,
+			"next_attempt_at": push.NextAttemptAt,
+			"response_code":   push.ResponseCode,
+			"last_error":      push.LastError,
+			"acknowledged_at": push.AcknowledgedAt,
+		}).Error; err != nil {
+		return fmt.Errorf("update payment push: %w", err)
+	}
+	return nil
+}
+
+// Requeue moves a FAILED push back to PENDING with a fresh attempt budget.
+func (r *paymentPushRepository) Requeue(ctx context.Context, id string, now time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.PaymentPush{}).
+		Where("id = ? AND status = ?", id, domain.PaymentPushFailed).
+		Updates(map[string]interface{}{
+			"status":          domain.PaymentPushPending,
+			"attempts":        0,
+			"next_attempt_at": now,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("requeue payment push: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListUnacknowledged returns pending and failed pushes, oldest first.
+func (r *paymentPushRepository) ListUnacknowledged(ctx context.Context, status domain.PaymentPushStatus, page Pagination) ([]domain.PaymentPush, int64, error) {
+	query := conn(ctx, r.db).Model(&domain.PaymentPush{}).Where("status <> ?", domain.PaymentPushAcknowledged)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count unacknowledged payment pushes: %w", err)
+	}
+
+	var pushes []domain.PaymentPush
+	if err := query.Order("created_at asc").Offset(page.Offset()).Limit(page.PageSize).Find(&pushes).Error; err != nil {
+		return nil, 0, fmt.Errorf("list unacknowledged payment pushes: %w", err)
+	}
+	return pushes, total, nil
+}
+
+func (r *paymentPushRepository) SummarizeUnacknowledged(ctx context.Context) (*PaymentPushSummary, error) {
+	var row struct {
+		Pending      int64
+		Failed       int64
+		Valid        int64
+		Expired      int64
+		OldestQueued *time.Time
+	}
+	if err := conn(ctx, r.db).Model(&domain.PaymentPush{}).
+		Where("status <> ?", domain.PaymentPushAcknowledged).
+		Select(
+			"COUNT(*) FILTER (WHERE status = ?) AS pending, "+
+				"COUNT(*) FILTER (WHERE status = ?) AS failed, "+
+				"COUNT(*) FILTER (WHERE transition = ?) AS valid, "+
+				"COUNT(*) FILTER (WHERE transition = ?) AS expired, "+
+				"MIN(created_at) AS oldest_queued",
+			domain.PaymentPushPending, domain.PaymentPushFailed, domain.PaymentPushValid, domain.PaymentPushExpired,
+		).Scan(&row).Error; err != nil {
+		return nil, fmt.Errorf("summarize payment pushes: %w", err)
+	}
+	return &PaymentPushSummary{
+		Pending:      row.Pending,
+		Failed:       row.Failed,
+		Valid:        row.Valid,
+		Expired:      row.Expired,
+		OldestQueued: row.OldestQueued,
+	}, nil
+}
+
+// ListExpiredWithoutPush returns each participant's latest VALID certificate when it was
+// verified before cutoff and its expiry has not been queued yet.
+func (r *paymentPushRepository) ListExpiredWithoutPush(ctx context.Context, cutoff time.Time, limit int) ([]domain.LifeCertificate, error) {
+	var records []domain.LifeCertificate
+	if err := conn(ctx, r.db).Model(&domain.LifeCertificate{}).
+		Where("life_certificate.status = ? AND life_certificate.verified_at < ?", domain.LifeCertificateStatusValid, cutoff).
+		Where("NOT EXISTS (SELECT 1 FROM life_certificate newer WHERE newer.participant_id = life_certificate.participant_id AND newer.status = ? AND newer.verified_at > life_certificate.verified_at)", domain.LifeCertificateStatusValid).
+		Where("NOT EXISTS (SELECT 1 FROM payment_pushes p WHERE p.certificate_id = life_certificate.id AND p.transition = ?)", domain.PaymentPushExpired).
+		Order("life_certificate.verified_at asc").
+		Limit(limit).
+		Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("list expired certificates: %w", err)
+	}
+	return records, nil
+}
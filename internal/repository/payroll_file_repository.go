@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// PayrollFileRepository logs payroll file deliveries and reads the statuses they carry.
+type PayrollFileRepository interface {
+	Create(ctx context.Context, delivery *domain.PayrollFileDelivery) error
+	List(ctx context.Context, page Pagination) ([]domain.PayrollFileDelivery, int64, error)
+	StreamStatuses(ctx context.Context, fund string, fn func(*ParticipantStatusRow) error) error
+}
+
+type payrollFileRepository struct {
+	db *gorm.DB
+}
+
+// NewPayrollFileRepository creates a gorm-backed repository.
+func NewPayrollFileRepository(db *gorm.DB) PayrollFileRepository {
+	return &payrollFileRepository{db: db}
+}
+
+func (r *payrollFileRepository) Create(ctx context.Context, delivery *domain.PayrollFileDelivery) error {
+	if err := conn(ctx, r.db).Create(delivery).Error; err != nil {
+		return fmt.Errorf("create payroll file delivery: %w", err)
+	}
+	return nil
+}
+
+// List returns deliveries, latest first.
+func (r *payrollFileRepository) List(ctx context.Context, page Pagination) ([]domain.PayrollFileDelivery, int64, error) {
+	query := conn(ctx, r.db).Model(&domain.PayrollFileDelivery{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count payroll file deliveries: %w", err)
+	}
+
+	var deliveries []domain.PayrollFileDelivery
+	if err := query.Order("started_at desc").Offset(page.Offset()).Limit(page.PageSize).Find(&deliveries).Error; err != nil {
+		return nil, 0, fmt.Errorf("list payroll file deliveries: %w", err)
+	}
+	return deliveries, total, nil
+}
+
+// StreamStatuses hands every participant, or those of fund when set, to fn in
+// NIK order, reading them from the database as fn consumes them.
+func (r *payrollFileRepository) StreamStatuses(ctx context.Context, fund string, fn func(*ParticipantStatusRow) error) error {
+	query := participantStatusQuery(conn(ctx, r.db))
+	if fund != "" {
+		query = query.Where("t.fund = ?", fund)
+	}
+	if err := streamRows(query.Order("t.nik asc"), fn); err != nil {
+		return fmt.Errorf("stream payroll statuses: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// ReminderRepository persists dispatched reminders and finds participants due one.
+type ReminderRepository interface {
+	Create(ctx context.Context, reminder *domain.Reminder) error
+	// DueExpiring lists active participants whose latest VALID certificate was verified at or before cutoff.
+	DueExpiring(ctx context.Context, cutoff time.Time, limits ReminderLimits) ([]ReminderCandidate, error)
+	// DueCampaign lists active participants with an unfinished campaign, started by today, due on or before dueBy.
+	DueCampaign(ctx context.Context, today, dueBy time.Time, limits ReminderLimits) ([]ReminderCandidate, error)
+}
+
+// ReminderLimits keeps candidates from being reminded too often.
+type ReminderLimits struct {
+	// QuietSince excludes participants reminded about anything after this instant.
+	QuietSince time.Time
+	// MaxPerTarget excludes certificates or campaigns already reminded about this many times.
+	MaxPerTarget int
+	Limit        int
+}
+
+// ReminderCandidate is a participant due a reminder about Reference.
+type ReminderCandidate struct {
+	ParticipantID string
+	Reference     string
+	// At is the certificate's verification time or the campaign due date.
+	At time.Time
+	// Region is the member's province for a lapsing certificate.
+	Region string
+}
+
+type reminderRepository struct {
+	db *gorm.DB
+}
+
+// NewReminderRepository creates a gorm-backed repository.
+func NewReminderRepository(db *gorm.DB) ReminderRepository {
+	return &reminderRepository{db: db}
+}
+
+func (r *reminderRepository) Create(ctx context.Context, reminder *domain.Reminder) error {
+	if err := conn(ctx, r.db).Create(reminder).Error; err != nil {
+		return fmt.Errorf("create reminder: %w", err)
+	}
+	return nil
+}
+
+func (r *reminderRepository) DueExpiring(ctx context.Context, cutoff time.Time, limits ReminderLimits) ([]ReminderCandidate, error) {
+	var candidates []ReminderCandidate
+	if err := conn(ctx, r.db).Raw(`
+		SELECT lc.participant_id, lc.id AS reference, lc.verified_at AS at, COALESCE(m.province, '') AS region
+		FROM (
+			SELECT DISTINCT ON (participant_id) id, participant_id, verified_at
+			FROM life_certificate
+			WHERE ? AND status = ?
+			ORDER BY participant_id, verified_at DESC
+		) lc
+		JOIN participants p ON p.id = lc.participant_id AND p.status = ?
+		LEFT JOIN members m ON m.id = p.member_id
+		WHERE lc.verified_at <= ?
+			AND NOT EXISTS (SELECT 1 FROM reminders r WHERE r.participant_id = lc.participant_id AND r.created_at > ?)
+			AND (SELECT COUNT(*) FROM reminders r WHERE r.participant_id = lc.participant_id AND r.kind = ? AND r.reference = lc.id) < ?
+		ORDER BY lc.verified_at
+		LIMIT ?`,
+		tenantCondition(ctx, "tenant_id"), domain.LifeCertificateStatusValid, domain.ParticipantStatusActive, cutoff,
+		limits.QuietSince, domain.ReminderKindExpiring, limits.MaxPerTarget, limits.Limit,
+	).Scan(&candidates).Error; err != nil {
+		return nil, fmt.Errorf("list expiring reminder candidates: %w", err)
+	}
+	return candidates, nil
+}
+
+func (r *reminderRepository) DueCampaign(ctx context.Context, today, dueBy time.Time, limits ReminderLimits) ([]ReminderCandidate, error) {
+	var candidates []ReminderCandidate
+	if err := conn(ctx, r.db).Raw(`
+		SELECT cp.participant_id, cp.campaign_id AS reference, cp.due_at AS at
+		FROM campaign_participants cp
+		JOIN campaigns c ON c.id = cp.campaign_id
+		JOIN participants p ON p.id = cp.participant_id AND p.status = ?
+		WHERE ? AND cp.status <> ? AND cp.due_at <= ? AND c.starts_at <= ?
+			AND NOT EXISTS (SELECT 1 FROM life_certificate lc WHERE lc.participant_id = cp.participant_id AND lc.status = ? AND lc.verified_at >= c.starts_at)
+			AND NOT EXISTS (SELECT 1 FROM reminders r WHERE r.participant_id = cp.participant_id AND r.created_at > ?)
+			AND (SELECT COUNT(*) FROM reminders r WHERE r.participant_id = cp.participant_id AND r.kind = ? AND r.reference = cp.campaign_id) < ?
+		ORDER BY cp.due_at
+		LIMIT ?`,
+		domain.ParticipantStatusActive, tenantCondition(ctx, "cp.tenant_id"), domain.CampaignParticipantCompleted, dueBy, today,
+		domain.LifeCertificateStatusValid,
+		limits.QuietSince, domain.ReminderKindCampaign, limits.MaxPerTarget, limits.Limit,
+	).Scan(&candidates).Error; err != nil {
+		return nil, fmt.Errorf("list campaign reminder candidates: %w", err)
+	}
+	return candidates, nil
+}
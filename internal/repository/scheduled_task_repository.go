@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ScheduledTaskRepository coordinates cron tasks between replicas.
+type ScheduledTaskRepository interface {
+	// Ensure creates the task row if missing and records its current schedule.
+	Ensure(ctx context.Context, name, schedule string, now time.Time) error
+	// Claim takes the slot for owner unless a replica already claimed it or a run is in progress.
+	Claim(ctx context.Context, name string, slot time.Time, owner string, now, leaseUntil time.Time) (bool, error)
+	// Finish records the outcome of owner's run and releases the lease.
+	Finish(ctx context.Context, name, owner string, finishedAt time.Time, lastError *string) error
+	List(ctx context.Context) ([]domain.ScheduledTask, error)
+}
+
+type scheduledTaskRepository struct {
+	db *gorm.DB
+}
+
+// NewScheduledTaskRepository creates a gorm-backed repository.
+func NewScheduledTaskRepository(db *gorm.DB) ScheduledTaskRepository {
+	return &scheduledTaskRepository{db: db}
+}
+
+func (r *scheduledTaskRepository) Ensure(ctx context.Context, name, schedule string, now time.Time) error {
+	task := &domain.ScheduledTask{Name: name, Schedule: schedule, UpdatedAt: now}
+	if err := conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"schedule"}),
+	}).Create(task).Error; err != nil {
+		return fmt.Errorf("ensure scheduled task: %w", err)
+	}
+	return nil
+}
+
+func (r *scheduledTaskRepository) Claim(ctx context.Context, name string, slot time.Time, owner string, now, leaseUntil time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.ScheduledTask{}).
+		Where("name = ? AND (last_slot IS NULL OR last_slot < ?) AND (lease_until IS NULL OR lease_until < ?)", name, slot, now).
+		Updates(map[string]interface{}{
+			"last_slot":       slot,
+			"owner":           owner,
+			"lease_until":     leaseUntil,
+			"last_started_at": now,
+			"updated_at":      now,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("claim scheduled task: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *scheduledTaskRepository) Finish(ctx context.Context, name, owner string, finishedAt time.Time, lastError *string) error {
+	if err := conn(ctx, r.db).Model(&domain.ScheduledTask{}).
+		Where("name = ? AND owner = ?", name, owner).
+		Updates(map[string]interface{}{
+			"lease_until":      nil,
+			"last_finished_at": finishedAt,
+			"last_error":       lastError,
+			"updated_at":       finishedAt,
+		}).Error; err != nil {
+		return fmt.Errorf("finish scheduled task: %w", err)
+	}
+	return nil
+}
+
+func (r *scheduledTaskRepository) List(ctx context.Context) ([]domain.ScheduledTask, error) {
+	var tasks []domain.ScheduledTask
+	if err := conn(ctx, r.db).Order("name").Find(&tasks).Error; err != nil {
+		return nil, fmt.Errorf("list scheduled tasks: %w", err)
+	}
+	return tasks, nil
+}
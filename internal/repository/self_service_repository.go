@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// SelfServiceRepository persists the one-time codes of self-service sign-in.
+type SelfServiceRepository interface {
+	CreateOTP(ctx context.Context, otp *domain.SelfServiceOTP) error
+	LatestOTP(ctx context.Context, participantID string) (*domain.SelfServiceOTP, error)
+	AddOTPAttempt(ctx context.Context, id string, maxAttempts int) (bool, error)
+	ConsumeOTP(ctx context.Context, id string, at time.Time) (bool, error)
+}
+
+type selfServiceRepository struct {
+	db *gorm.DB
+}
+
+// NewSelfServiceRepository creates a gorm-backed repository.
+func NewSelfServiceRepository(db *gorm.DB) SelfServiceRepository {
+	return &selfServiceRepository{db: db}
+}
+
+func (r *selfServiceRepository) CreateOTP(ctx context.Context, otp *domain.SelfServiceOTP) error {
+	if err := conn(ctx, r.db).Create(otp).Error; err != nil {
+		return fmt.Errorf("create self-service code: %w", err)
+	}
+	return nil
+}
+
+// LatestOTP returns the participant's most recent code, used or not.
+func (r *selfServiceRepository) LatestOTP(ctx context.Context, participantID string) (*domain.SelfServiceOTP, error) {
+	var otp domain.SelfServiceOTP
+	if err := conn(ctx, r.db).Where("participant_id = ?", participantID).Order("created_at desc").First(&otp).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get self-service code: %w", err)
+	}
+	return &otp, nil
+}
+
+// AddOTPAttempt counts an attempt at the code before it is checked, reporting
+// false once maxAttempts were made, so concurrent guesses cannot exceed it.
+func (r *selfServiceRepository) AddOTPAttempt(ctx context.Context, id string, maxAttempts int) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.SelfServiceOTP{}).
+		Where("id = ? AND attempts < ?", id, maxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		return false, fmt.Errorf("count self-service code attempt: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ConsumeOTP marks an unused code used, reporting false when another request
+// used it first.
+func (r *selfServiceRepository) ConsumeOTP(ctx context.Context, id string, at time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.SelfServiceOTP{}).
+		Where("id = ? AND consumed_at IS NULL", id).
+		Update("consumed_at", at)
+	if result.Error != nil {
+		return false, fmt.Errorf("consume self-service code: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
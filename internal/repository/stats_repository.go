@@ -0,0 +1,365 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// StatsRepository aggregates verifications, reviews and campaigns for the ops dashboard.
+type StatsRepository interface {
+	// Verifications counts the attempts verified in the filter's range.
+	Verifications(ctx context.Context, filter StatsFilter) (*VerificationStats, error)
+	// VerificationSeries counts the attempts per interval (day, week or
+	// month) starting bucket, leaving out empty buckets.
+	VerificationSeries(ctx context.Context, filter StatsFilter, interval string) ([]VerificationStatsBucket, error)
+	// ReviewBacklog counts the pending reviews of the filter's participants, whatever its range.
+	ReviewBacklog(ctx context.Context, filter StatsFilter, now time.Time) (pending, overdue int64, err error)
+	// Campaigns measures the completion of campaigns whose window overlaps the filter's range.
+	Campaigns(ctx context.Context, filter StatsFilter) ([]CampaignCompletion, error)
+	// CampaignChannels counts, per campaign and channel, the filter's
+	// participants with a VALID certificate linked to the campaign, whatever
+	// its range.
+	CampaignChannels(ctx context.Context, filter StatsFilter, campaignIDs []string) ([]CampaignChannelCount, error)
+	// ScoreHistogram counts the automatic attempts per bucket of width of
+	// the score (similarity or distance) and status.
+	ScoreHistogram(ctx context.Context, filter StatsFilter, score string, width float64) ([]ScoreHistogramRow, error)
+	// StreamDecided hands fn the automatic attempts decided by the decision
+	// rules, one at a time.
+	StreamDecided(ctx context.Context, filter StatsFilter, fn func(*DecidedAttempt) error) error
+	// VerificationReport counts, per province, city or fund, the participants
+	// registered before to and their attempts in [from, to).
+	VerificationReport(ctx context.Context, groupBy string, from, to time.Time) ([]VerificationReportRow, error)
+	// MonthlyFigures counts the attempts, exceptions, status overrides and
+	// death reports of [from, to).
+	MonthlyFigures(ctx context.Context, from, to time.Time) (*MonthlyFigures, error)
+}
+
+// StatsFilter narrows statistics to a verification range and the
+// participants of a province and fund. Empty fields are ignored.
+type StatsFilter struct {
+	From     *time.Time
+	To       *time.Time
+	Province string
+	Fund     string
+}
+
+// VerificationStats counts attempts per status and averages the similarity of automatic ones.
+type VerificationStats struct {
+	Valid             int64
+	Invalid           int64
+	Review            int64
+	AverageSimilarity *float64
+}
+
+// VerificationStatsBucket is VerificationStats for the interval starting at Start.
+type VerificationStatsBucket struct {
+	Start time.Time
+	VerificationStats
+}
+
+// CampaignCompletion is how many of a campaign's targeted participants completed it.
+type CampaignCompletion struct {
+	CampaignID   string
+	Name         string
+	StartsAt     time.Time
+	DueAt        time.Time
+	Participants int64
+	Completed    int64
+}
+
+// CampaignChannelCount is how many participants completed a campaign on a channel.
+type CampaignChannelCount struct {
+	CampaignID string
+	Channel    domain.VerificationChannel
+	Completed  int64
+}
+
+// ScoreHistogramRow counts the attempts of a status whose score fell in
+// [Bucket*width, (Bucket+1)*width).
+type ScoreHistogramRow struct {
+	Bucket int64
+	Status domain.LifeCertificateStatus
+	Count  int64
+}
+
+// DecidedAttempt is an attempt's status and the trace of the rules that
+// decided it. Conflict is set when its face matched another participant's.
+type DecidedAttempt struct {
+	Status        domain.LifeCertificateStatus
+	DecisionTrace string
+	Conflict      bool
+}
+
+// VerificationReportRow is a group's participants and attempts. Verified
+// participants had a VALID attempt; failed ones had attempts but none VALID.
+type VerificationReportRow struct {
+	Group        string
+	Participants int64
+	Verified     int64
+	Failed       int64
+	Attempts     int64
+	Valid        int64
+	Invalid      int64
+	Review       int64
+}
+
+// MonthlyFigures are the figures of a monthly report. Overrides and death
+// reports are counted as proposed or reported and, separately, as decided.
+type MonthlyFigures struct {
+	VerificationStats
+	Automatic            int64
+	Manual               int64
+	ParticipantsVerified int64
+	CaptureFindings      int64
+	ReviewsPending       int64
+	FacialConflicts      int64
+	Overrides            DecisionCounts
+	Deaths               DecisionCounts
+}
+
+// DecisionCounts counts the overrides or reports raised in a range and those
+// approved (or confirmed) and rejected in it.
+type DecisionCounts struct {
+	Raised   int64
+	Approved int64
+	Rejected int64
+}
+
+type statsRepository struct {
+	db *gorm.DB
+}
+
+// NewStatsRepository creates a gorm-backed repository.
+func NewStatsRepository(db *gorm.DB) StatsRepository {
+	return &statsRepository{db: db}
+}
+
+// verificationCounts selects the columns of VerificationStats.
+const verificationCounts = "COUNT(*) FILTER (WHERE lc.status = ?) AS valid, " +
+	"COUNT(*) FILTER (WHERE lc.status = ?) AS invalid, " +
+	"COUNT(*) FILTER (WHERE lc.status = ?) AS review, " +
+	"AVG(lc.similarity) FILTER (WHERE lc.method = ?) AS average_similarity"
+
+func verificationCountArgs() []interface{} {
+	return []interface{}{domain.LifeCertificateStatusValid, domain.LifeCertificateStatusInvalid, domain.LifeCertificateStatusReview, domain.VerificationMethodAutomatic}
+}
+
+func (r *statsRepository) Verifications(ctx context.Context, filter StatsFilter) (*VerificationStats, error) {
+	var stats VerificationStats
+	if err := r.certificates(ctx, filter).Select(verificationCounts, verificationCountArgs()...).Scan(&stats).Error; err != nil {
+		return nil, fmt.Errorf("aggregate verifications: %w", err)
+	}
+	return &stats, nil
+}
+
+func (r *statsRepository) VerificationSeries(ctx context.Context, filter StatsFilter, interval string) ([]VerificationStatsBucket, error) {
+	args := append([]interface{}{interval}, verificationCountArgs()...)
+	var buckets []VerificationStatsBucket
+	if err := r.certificates(ctx, filter).
+		Select("date_trunc(?, lc.verified_at AT TIME ZONE 'UTC') AS start, "+verificationCounts, args...).
+		Group("start").Order("start").
+		Scan(&buckets).Error; err != nil {
+		return nil, fmt.Errorf("aggregate verification series: %w", err)
+	}
+	return buckets, nil
+}
+
+func (r *statsRepository) ReviewBacklog(ctx context.Context, filter StatsFilter, now time.Time) (int64, int64, error) {
+	var row struct {
+		Pending int64
+		Overdue int64
+	}
+	backlog := StatsFilter{Province: filter.Province, Fund: filter.Fund}
+	if err := r.certificates(ctx, backlog).
+		Where("lc.status = ? AND lc.reviewed_at IS NULL", domain.LifeCertificateStatusReview).
+		Select("COUNT(*) AS pending, COUNT(*) FILTER (WHERE lc.review_due_at < ?) AS overdue", now).
+		Scan(&row).Error; err != nil {
+		return 0, 0, fmt.Errorf("count review backlog: %w", err)
+	}
+	return row.Pending, row.Overdue, nil
+}
+
+func (r *statsRepository) Campaigns(ctx context.Context, filter StatsFilter) ([]CampaignCompletion, error) {
+	query := r.participants(conn(ctx, r.db).Table("campaigns AS c").
+		Joins("JOIN campaign_participants cp ON cp.campaign_id = c.id").
+		Joins("JOIN participants p ON p.id = cp.participant_id"), filter)
+	if filter.From != nil {
+		query = query.Where("c.due_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("c.starts_at < ?", *filter.To)
+	}
+
+	var campaigns []CampaignCompletion
+	if err := query.
+		Select("c.id AS campaign_id, c.name, c.starts_at, c.due_at, COUNT(*) AS participants, "+
+			"COUNT(*) FILTER (WHERE cp.status = ?) AS completed", domain.CampaignParticipantCompleted).
+		Group("c.id, c.name, c.starts_at, c.due_at").
+		Order("c.due_at, c.id").
+		Scan(&campaigns).Error; err != nil {
+		return nil, fmt.Errorf("aggregate campaign completion: %w", err)
+	}
+	return campaigns, nil
+}
+
+func (r *statsRepository) CampaignChannels(ctx context.Context, filter StatsFilter, campaignIDs []string) ([]CampaignChannelCount, error) {
+	if len(campaignIDs) == 0 {
+		return nil, nil
+	}
+	scope := StatsFilter{Province: filter.Province, Fund: filter.Fund}
+	var counts []CampaignChannelCount
+	if err := r.certificates(ctx, scope).
+		Where("lc.campaign_id IN ? AND lc.status = ?", campaignIDs, domain.LifeCertificateStatusValid).
+		Select("lc.campaign_id, lc.channel, COUNT(DISTINCT lc.participant_id) AS completed").
+		Group("lc.campaign_id, lc.channel").Order("lc.campaign_id, lc.channel").
+		Scan(&counts).Error; err != nil {
+		return nil, fmt.Errorf("aggregate campaign channels: %w", err)
+	}
+	return counts, nil
+}
+
+func (r *statsRepository) ScoreHistogram(ctx context.Context, filter StatsFilter, score string, width float64) ([]ScoreHistogramRow, error) {
+	var column string
+	switch score {
+	case "similarity":
+		column = "lc.similarity"
+	case "distance":
+		column = "lc.distance"
+	default:
+		return nil, fmt.Errorf("unknown score %q", score)
+	}
+
+	var rows []ScoreHistogramRow
+	if err := r.certificates(ctx, filter).
+		Where("lc.method = ? AND "+column+" IS NOT NULL", domain.VerificationMethodAutomatic).
+		Select("FLOOR("+column+" / ?)::bigint AS bucket, lc.status, COUNT(*) AS count", width).
+		Group("bucket, lc.status").Order("bucket, lc.status").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("aggregate score histogram: %w", err)
+	}
+	return rows, nil
+}
+
+func (r *statsRepository) StreamDecided(ctx context.Context, filter StatsFilter, fn func(*DecidedAttempt) error) error {
+	query := r.certificates(ctx, filter).
+		Where("lc.method = ? AND lc.decision_trace IS NOT NULL", domain.VerificationMethodAutomatic).
+		Select("lc.status, lc.decision_trace, COALESCE(lc.notes LIKE 'facial_conflict:%', false) AS conflict")
+	if err := streamRows(query, fn); err != nil {
+		return fmt.Errorf("stream decided attempts: %w", err)
+	}
+	return nil
+}
+
+// VerificationReport leaves out participants whose member is deceased.
+// Provinces and cities are grouped regardless of case and surrounding spaces,
+// and participants without one fall in the group "".
+func (r *statsRepository) VerificationReport(ctx context.Context, groupBy string, from, to time.Time) ([]VerificationReportRow, error) {
+	var group string
+	switch groupBy {
+	case "province":
+		group = "COALESCE(UPPER(TRIM(m.province)), '')"
+	case "city":
+		group = "COALESCE(UPPER(TRIM(m.city)), '')"
+	case "fund":
+		group = "COALESCE(p.fund, '')"
+	default:
+		return nil, fmt.Errorf("unknown report grouping %q", groupBy)
+	}
+
+	attempts := conn(ctx, r.db).Table("life_certificate").
+		Select("participant_id, COUNT(*) AS attempts, "+
+			"COUNT(*) FILTER (WHERE status = ?) AS valid, "+
+			"COUNT(*) FILTER (WHERE status = ?) AS invalid, "+
+			"COUNT(*) FILTER (WHERE status = ?) AS review",
+			domain.LifeCertificateStatusValid, domain.LifeCertificateStatusInvalid, domain.LifeCertificateStatusReview).
+		Where("verified_at >= ? AND verified_at < ?", from, to).
+		Group("participant_id")
+
+	var rows []VerificationReportRow
+	if err := conn(ctx, r.db).Table("participants AS p").
+		Joins("LEFT JOIN members m ON m.id = p.member_id").
+		Joins("LEFT JOIN (?) a ON a.participant_id = p.id", attempts).
+		Where("p.created_at < ? AND (m.status IS NULL OR m.status <> ?)", to, domain.MemberStatusDeceased).
+		Select(group + " AS \"group\", COUNT(*) AS participants, " +
+			"COUNT(*) FILTER (WHERE a.valid > 0) AS verified, " +
+			"COUNT(*) FILTER (WHERE a.attempts > 0 AND a.valid = 0) AS failed, " +
+			"COALESCE(SUM(a.attempts), 0) AS attempts, COALESCE(SUM(a.valid), 0) AS valid, " +
+			"COALESCE(SUM(a.invalid), 0) AS invalid, COALESCE(SUM(a.review), 0) AS review").
+		Group(group).Order(group).
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("aggregate verification report: %w", err)
+	}
+	return rows, nil
+}
+
+func (r *statsRepository) MonthlyFigures(ctx context.Context, from, to time.Time) (*MonthlyFigures, error) {
+	var figures MonthlyFigures
+	args := append(verificationCountArgs(), domain.VerificationMethodAutomatic, domain.VerificationMethodManual,
+		domain.LifeCertificateStatusValid, domain.LifeCertificateStatusReview)
+	if err := r.certificates(ctx, StatsFilter{From: &from, To: &to}).
+		Select(verificationCounts+", "+
+			"COUNT(*) FILTER (WHERE lc.method = ?) AS automatic, "+
+			"COUNT(*) FILTER (WHERE lc.method = ?) AS manual, "+
+			"COUNT(DISTINCT lc.participant_id) FILTER (WHERE lc.status = ?) AS participants_verified, "+
+			"COUNT(*) FILTER (WHERE lc.capture_findings IS NOT NULL) AS capture_findings, "+
+			"COUNT(*) FILTER (WHERE lc.status = ? AND lc.reviewed_at IS NULL) AS reviews_pending", args...).
+		Scan(&figures).Error; err != nil {
+		return nil, fmt.Errorf("aggregate monthly verifications: %w", err)
+	}
+	if err := conn(ctx, r.db).Model(&domain.FacialConflict{}).
+		Where("detected_at >= ? AND detected_at < ?", from, to).
+		Count(&figures.FacialConflicts).Error; err != nil {
+		return nil, fmt.Errorf("count monthly facial conflicts: %w", err)
+	}
+	if err := conn(ctx, r.db).Model(&domain.StatusOverride{}).
+		Select(decisionCounts("proposed_at"), from, to, domain.StatusOverrideApproved, from, to, domain.StatusOverrideRejected, from, to).
+		Scan(&figures.Overrides).Error; err != nil {
+		return nil, fmt.Errorf("count monthly status overrides: %w", err)
+	}
+	if err := conn(ctx, r.db).Model(&domain.DeathReport{}).
+		Select(decisionCounts("reported_at"), from, to, domain.DeathReportConfirmed, from, to, domain.DeathReportRejected, from, to).
+		Scan(&figures.Deaths).Error; err != nil {
+		return nil, fmt.Errorf("count monthly death reports: %w", err)
+	}
+	return &figures, nil
+}
+
+// decisionCounts selects the columns of DecisionCounts for a table raising
+// rows at raisedAt and deciding them with a state and decided_at.
+func decisionCounts(raisedAt string) string {
+	return "COUNT(*) FILTER (WHERE " + raisedAt + " >= ? AND " + raisedAt + " < ?) AS raised, " +
+		"COUNT(*) FILTER (WHERE state = ? AND decided_at >= ? AND decided_at < ?) AS approved, " +
+		"COUNT(*) FILTER (WHERE state = ? AND decided_at >= ? AND decided_at < ?) AS rejected"
+}
+
+// certificates selects the attempts matching the filter as lc.
+func (r *statsRepository) certificates(ctx context.Context, filter StatsFilter) *gorm.DB {
+	query := conn(ctx, r.db).Table("life_certificate AS lc")
+	if filter.From != nil {
+		query = query.Where("lc.verified_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("lc.verified_at < ?", *filter.To)
+	}
+	if filter.Province != "" || filter.Fund != "" {
+		query = r.participants(query.Joins("JOIN participants p ON p.id = lc.participant_id"), filter)
+	}
+	return query
+}
+
+// participants narrows a query joined to participants as p to the filter's province and fund.
+func (r *statsRepository) participants(query *gorm.DB, filter StatsFilter) *gorm.DB {
+	if filter.Fund != "" {
+		query = query.Where("p.fund = ?", filter.Fund)
+	}
+	if filter.Province != "" {
+		query = query.Joins("JOIN members m ON m.id = p.member_id").
+			Where("LOWER(m.province) = LOWER(?)", filter.Province)
+	}
+	return query
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// StatusOverrideRepository persists dual-control status override proposals.
+type StatusOverrideRepository interface {
+	Create(ctx context.Context, override *domain.StatusOverride) error
+	GetByID(ctx context.Context, id string) (*domain.StatusOverride, error)
+	GetPendingByCertificate(ctx context.Context, certificateID string) (*domain.StatusOverride, error)
+	ListByCertificate(ctx context.Context, certificateID string) ([]domain.StatusOverride, error)
+	List(ctx context.Context, state domain.StatusOverrideState, page Pagination) ([]domain.StatusOverride, int64, error)
+	Approve(ctx context.Context, override *domain.StatusOverride) (bool, error)
+	Reject(ctx context.Context, override *domain.StatusOverride) (bool, error)
+}
+
+type statusOverrideRepository struct {
+	db *gorm.DB
+}
+
+// NewStatusOverrideRepository creates a gorm-backed repository.
+func NewStatusOverrideRepository(db *gorm.DB) StatusOverrideRepository {
+	return &statusOverrideRepository{db: db}
+}
+
+func (r *statusOverrideRepository) Create(ctx context.Context, override *domain.StatusOverride) error {
+	if err := conn(ctx, r.db).Create(override).Error; err != nil {
+		return fmt.Errorf("create status override: %w", err)
+	}
+	return nil
+}
+
+func (r *statusOverrideRepository) GetByID(ctx context.Context, id string) (*domain.StatusOverride, error) {
+	var override domain.StatusOverride
+	if err := conn(ctx, r.db).First(&override, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get status override: %w", err)
+	}
+	return &override, nil
+}
+
+func (r *statusOverrideRepository) GetPendingByCertificate(ctx context.Context, certificateID string) (*domain.StatusOverride, error) {
+	var override domain.StatusOverride
+	if err := conn(ctx, r.db).
+		Where("certificate_id = ? AND state = ?", certificateID, domain.StatusOverridePending).
+		First(&override).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get pending status override: %w", err)
+	}
+	return &override, nil
+}
+
+func (r *statusOverrideRepository) ListByCertificate(ctx context.Context, certificateID string) ([]domain.StatusOverride, error) {
+	var overrides []domain.StatusOverride
+	if err := conn(ctx, r.db).Where("certificate_id = ?", certificateID).Order("proposed_at desc").Find(&overrides).Error; err != nil {
+		return nil, fmt.Errorf("list status overrides: %w", err)
+	}
+	return overrides, nil
+}
+
+// List returns overrides in the given state, oldest first; an empty state lists all.
+func (r *statusOverrideRepository) List(ctx context.Context, state domain.StatusOverrideState, page Pagination) ([]domain.StatusOverride, int64, error) {
+	query := conn(ctx, r.db).Model(&domain.StatusOverride{})
+	if state != "" {
+		query = query.Where("state = ?", state)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count status overrides: %w", err)
+	}
+
+	var overrides []domain.StatusOverride
+	if err := query.Order("proposed_at asc").Offset(page.Offset()).Limit(page.PageSize).Find(&overrides).Error; err != nil {
+		return nil, 0, fmt.Errorf("list status overrides: %w", err)
+	}
+	return overrides, total, nil
+}
+
+// Approve marks a pending override approved. It reports false when the
+// override is no longer pending; the caller applies the status change through
+// LifeCertificateRepository.OverrideStatus in the same transaction.
+func (r *statusOverrideRepository) Approve(ctx context.Context, override *domain.StatusOverride) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.StatusOverride{}).
+		Where("id = ? AND state = ?", override.ID, domain.StatusOverridePending).
+		Updates(map[string]interface{}{
+			"state":          domain.StatusOverrideApproved,
+			"decided_by":     override.DecidedBy,
+			"decided_at":     override.DecidedAt,
+			"decision_notes": override.DecisionNotes,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("approve status override: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Reject marks a pending override rejected without touching the certificate.
+func (r *statusOverrideRepository) Reject(ctx context.Context, override *domain.StatusOverride) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.StatusOverride{}).
+		Where("id = ? AND state = ?", override.ID, domain.StatusOverridePending).
+		Updates(map[string]interface{}{
+			"state":          domain.StatusOverrideRejected,
+			"decided_by":     override.DecidedBy,
+			"decided_at":     override.DecidedAt,
+			"decision_notes": override.DecisionNotes,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("reject status override: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,25 @@
+package repository
+
+import "gorm.io/gorm"
+
+// streamRows runs query and hands its rows to fn one at a time, so a large
+// result never sits in memory at once. The connection stays checked out until
+// the last row is read, and an error from fn stops the iteration.
+func streamRows[T any](query *gorm.DB, fn func(*T) error) error {
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item T
+		if err := query.ScanRows(rows, &item); err != nil {
+			return err
+		}
+		if err := fn(&item); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
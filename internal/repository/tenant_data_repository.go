@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+
+	"life-certificates/internal/tenant"
+)
+
+// TenantRow is a row of a tenant table by column name, without its tenant_id.
+type TenantRow map[string]json.RawMessage
+
+// TenantDataRepository copies every row a tenant owns, for moving a tenant
+// between deployments. Rows are read and written through the models' fields,
+// so every column survives the trip whatever its JSON tag.
+type TenantDataRepository interface {
+	// Tables names the tenant tables, in the order Export visits them.
+	Tables() []string
+	// Export hands fn the rows of the tenant of ctx table by table, read from
+	// one snapshot of the database.
+	Export(ctx context.Context, fn func(table string, row TenantRow) error) error
+	// Import creates rows in a table for the tenant of ctx, as they are.
+	Import(ctx context.Context, table string, rows []TenantRow) error
+	// Count returns how many rows of a table the tenant of ctx owns.
+	Count(ctx context.Context, table string) (int64, error)
+}
+
+type tenantDataRepository struct {
+	db     *gorm.DB
+	tables []tenantTable
+}
+
+type tenantTable struct {
+	name   string
+	model  reflect.Type
+	fields []*schema.Field
+}
+
+// NewTenantDataRepository covers the tables of models that have a tenant_id.
+func NewTenantDataRepository(db *gorm.DB, models ...interface{}) (TenantDataRepository, error) {
+	r := &tenantDataRepository{db: db}
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, fmt.Errorf("parse model: %w", err)
+		}
+		if stmt.Schema.LookUpField(tenantColumn) == nil {
+			continue
+		}
+		table := tenantTable{name: stmt.Table, model: stmt.Schema.ModelType}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName != "" && field.DBName != tenantColumn && field.Readable && field.Creatable {
+				table.fields = append(table.fields, field)
+			}
+		}
+		r.tables = append(r.tables, table)
+	}
+	return r, nil
+}
+
+func (r *tenantDataRepository) Tables() []string {
+	names := make([]string, len(r.tables))
+	for i, table := range r.tables {
+		names[i] = table.name
+	}
+	return names
+}
+
+func (r *tenantDataRepository) Export(ctx context.Context, fn func(table string, row TenantRow) error) error {
+	// A repeatable read transaction sees every table as of its first query.
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for _, table := range r.tables {
+			if err := r.exportTable(tx, table, fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("export tenant data: %w", err)
+	}
+	return nil
+}
+
+func (r *tenantDataRepository) exportTable(tx *gorm.DB, table tenantTable, fn func(table string, row TenantRow) error) error {
+	query := tx.Model(reflect.New(table.model).Interface())
+	rows, err := query.Rows()
+	if err != nil {
+		return fmt.Errorf("read %s: %w", table.name, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := reflect.New(table.model)
+		if err := query.ScanRows(rows, item.Interface()); err != nil {
+			return fmt.Errorf("read %s: %w", table.name, err)
+		}
+		row := make(TenantRow, len(table.fields))
+		for _, field := range table.fields {
+			value, _ := field.ValueOf(tx.Statement.Context, item.Elem())
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return fmt.Errorf("encode %s.%s: %w", table.name, field.DBName, err)
+			}
+			row[field.DBName] = raw
+		}
+		if err := fn(table.name, row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *tenantDataRepository) Import(ctx context.Context, name string, rows []TenantRow) error {
+	table, err := r.table(name)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	// Inserting through Create would swap zero values for column defaults,
+	// reviving e.g. inactive webhooks; the statement is written out instead.
+	var sql strings.Builder
+	vars := []interface{}{clause.Table{Name: name}, clause.Column{Name: tenantColumn}}
+	sql.WriteString("INSERT INTO ? (?")
+	for _, field := range table.fields {
+		sql.WriteString(",?")
+		vars = append(vars, clause.Column{Name: field.DBName})
+	}
+	sql.WriteString(") VALUES ")
+	placeholders := "(?" + strings.Repeat(",?", len(table.fields)) + ")"
+	for i, row := range rows {
+		if i > 0 {
+			sql.WriteString(",")
+		}
+		sql.WriteString(placeholders)
+		vars = append(vars, tenant.ID(ctx))
+		for _, field := range table.fields {
+			value := reflect.New(field.FieldType)
+			if raw, ok := row[field.DBName]; ok {
+				if err := json.Unmarshal(raw, value.Interface()); err != nil {
+					return fmt.Errorf("decode %s.%s: %w", name, field.DBName, err)
+				}
+			}
+			vars = append(vars, value.Elem().Interface())
+		}
+	}
+	if err := conn(ctx, r.db).Exec(sql.String(), vars...).Error; err != nil {
+		return fmt.Errorf("import %s: %w", name, err)
+	}
+	return nil
+}
+
+func (r *tenantDataRepository) Count(ctx context.Context, name string) (int64, error) {
+	table, err := r.table(name)
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	if err := conn(ctx, r.db).Model(reflect.New(table.model).Interface()).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("count %s: %w", name, err)
+	}
+	return count, nil
+}
+
+func (r *tenantDataRepository) table(name string) (tenantTable, error) {
+	for _, table := range r.tables {
+		if table.name == name {
+			return table, nil
+		}
+	}
+	return tenantTable{}, fmt.Errorf("unknown tenant table %s", name)
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"life-certificates/internal/cache"
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+const tenantCacheKey = "tenant:"
+
+// TenantRepository persists the pension funds served by the deployment.
+type TenantRepository interface {
+	Create(ctx context.Context, t *domain.Tenant) error
+	Get(ctx context.Context, id string) (*domain.Tenant, error)
+	List(ctx context.Context) ([]domain.Tenant, error)
+	Update(ctx context.Context, t *domain.Tenant) error
+}
+
+type tenantRepository struct {
+	db *gorm.DB
+}
+
+// NewTenantRepository creates a gorm-backed repository.
+func NewTenantRepository(db *gorm.DB) TenantRepository {
+	return &tenantRepository{db: db}
+}
+
+func (r *tenantRepository) Create(ctx context.Context, t *domain.Tenant) error {
+	if err := conn(ctx, r.db).Create(t).Error; err != nil {
+		return fmt.Errorf("create tenant: %w", err)
+	}
+	return nil
+}
+
+func (r *tenantRepository) Get(ctx context.Context, id string) (*domain.Tenant, error) {
+	var t domain.Tenant
+	if err := conn(ctx, r.db).First(&t, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get tenant: %w", err)
+	}
+	return &t, nil
+}
+
+func (r *tenantRepository) List(ctx context.Context) ([]domain.Tenant, error) {
+	var tenants []domain.Tenant
+	if err := conn(ctx, r.db).Order("id asc").Find(&tenants).Error; err != nil {
+		return nil, fmt.Errorf("list tenants: %w", err)
+	}
+	return tenants, nil
+}
+
+func (r *tenantRepository) Update(ctx context.Context, t *domain.Tenant) error {
+	if err := conn(ctx, r.db).Save(t).Error; err != nil {
+		return fmt.Errorf("update tenant: %w", err)
+	}
+	return nil
+}
+
+// cachedTenantRepository serves Get from a cache, since every request and
+// FR Core call looks its tenant up, and drops the entry on update.
+type cachedTenantRepository struct {
+	TenantRepository
+	cache cache.Cache
+}
+
+// NewCachedTenantRepository caches tenants looked up by ID.
+func NewCachedTenantRepository(inner TenantRepository, c cache.Cache) TenantRepository {
+	return &cachedTenantRepository{TenantRepository: inner, cache: c}
+}
+
+// Get caches the tenant whole; unlike its JSON form, the entry keeps the FR Core keys.
+func (r *cachedTenantRepository) Get(ctx context.Context, id string) (*domain.Tenant, error) {
+	if inTransaction(ctx) {
+		return r.TenantRepository.Get(ctx, id)
+	}
+	var entry cachedTenant
+	if cacheGet(ctx, r.cache, "tenant", tenantCacheKey+id, &entry) {
+		return entry.tenant(), nil
+	}
+	found, err := r.TenantRepository.Get(ctx, id)
+	if err != nil || found == nil {
+		return found, err
+	}
+	cacheSet(ctx, r.cache, tenantCacheKey+id, newCachedTenant(found))
+	return found, nil
+}
+
+func (r *cachedTenantRepository) Update(ctx context.Context, t *domain.Tenant) error {
+	if err := r.TenantRepository.Update(ctx, t); err != nil {
+		return err
+	}
+	invalidate(ctx, r.cache, tenantCacheKey+t.ID)
+	return nil
+}
+
+// cachedTenant is a tenant with the fields its JSON form leaves out.
+type cachedTenant struct {
+	domain.Tenant
+	UploadAPIKey    string `json:"frcore_upload_api_key"`
+	RecognizeAPIKey string `json:"frcore_recognize_api_key"`
+	LogoKey         string `json:"logo_key"`
+}
+
+func newCachedTenant(t *domain.Tenant) cachedTenant {
+	return cachedTenant{Tenant: *t, UploadAPIKey: t.FRCoreUploadAPIKey, RecognizeAPIKey: t.FRCoreRecognizeAPIKey, LogoKey: t.Branding.LogoKey}
+}
+
+func (c cachedTenant) tenant() *domain.Tenant {
+	t := c.Tenant
+	t.FRCoreUploadAPIKey, t.FRCoreRecognizeAPIKey = c.UploadAPIKey, c.RecognizeAPIKey
+	t.Branding.LogoKey = c.LogoKey
+	return &t
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"life-certificates/internal/tenant"
+)
+
+// tenantColumn holds the tenant of every tenant-owned row.
+const tenantColumn = "tenant_id"
+
+// ErrTenantRequired indicates a row created across tenants without naming its tenant.
+var ErrTenantRequired = errors.New("row has no tenant")
+
+// RegisterTenantScope makes every statement built on the tables of models
+// with a TenantID act for the tenant of its context only: queries, counts,
+// updates and deletes get a tenant_id condition on their main table, and
+// created rows get the tenant. Joined tables are reached through rows that
+// are already scoped. Raw SQL is not rewritten and names the tenant itself.
+func RegisterTenantScope(db *gorm.DB, models ...interface{}) error {
+	tables := make(map[string]bool)
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return fmt.Errorf("parse model: %w", err)
+		}
+		if stmt.Schema.LookUpField(tenantColumn) != nil {
+			tables[stmt.Table] = true
+		}
+	}
+	scope := func(db *gorm.DB) { scopeTenant(db, tables) }
+
+	callbacks := db.Callback()
+	if err := callbacks.Create().Before("gorm:create").Register("tenant:create", stampTenant); err != nil {
+		return fmt.Errorf("register tenant create: %w", err)
+	}
+	if err := callbacks.Query().Before("gorm:query").Register("tenant:query", scope); err != nil {
+		return fmt.Errorf("register tenant query: %w", err)
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("tenant:row", scope); err != nil {
+		return fmt.Errorf("register tenant row: %w", err)
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("tenant:update", func(db *gorm.DB) {
+		fillTenant(db)
+		scope(db)
+	}); err != nil {
+		return fmt.Errorf("register tenant update: %w", err)
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("tenant:delete", scope); err != nil {
+		return fmt.Errorf("register tenant delete: %w", err)
+	}
+	return nil
+}
+
+// scopeTenant adds the tenant condition to a statement on a tenant table,
+// qualified with the table's alias when it has one.
+func scopeTenant(db *gorm.DB, tables map[string]bool) {
+	stmt := db.Statement
+	if stmt.SQL.Len() > 0 || tenant.IsSystem(stmt.Context) {
+		return
+	}
+	table := stmt.Table
+	if stmt.TableExpr != nil {
+		fields := strings.Fields(stmt.TableExpr.SQL)
+		if len(fields) == 0 {
+			return
+		}
+		table = fields[0]
+	}
+	if !tables[table] {
+		return
+	}
+	stmt.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: stmt.Table, Name: tenantColumn}, Value: tenant.ID(stmt.Context)},
+	}})
+}
+
+// stampTenant sets the tenant of the rows being created. Across tenants the
+// rows must already carry theirs.
+func stampTenant(db *gorm.DB) {
+	stmt := db.Statement
+	if stmt.Schema == nil {
+		return
+	}
+	field := stmt.Schema.LookUpField(tenantColumn)
+	if field == nil {
+		return
+	}
+	system := tenant.IsSystem(stmt.Context)
+	id := tenant.ID(stmt.Context)
+	set := func(row reflect.Value) error {
+		if !system {
+			return field.Set(stmt.Context, row, id)
+		}
+		if _, zero := field.ValueOf(stmt.Context, row); zero {
+			return ErrTenantRequired
+		}
+		return nil
+	}
+
+	switch stmt.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < stmt.ReflectValue.Len(); i++ {
+			if err := set(reflect.Indirect(stmt.ReflectValue.Index(i))); err != nil {
+				db.AddError(err)
+				return
+			}
+		}
+	case reflect.Struct:
+		if err := set(stmt.ReflectValue); err != nil {
+			db.AddError(err)
+		}
+	}
+}
+
+// fillTenant sets the tenant of a row saved whole without one, which would
+// otherwise clear its tenant_id.
+func fillTenant(db *gorm.DB) {
+	stmt := db.Statement
+	if stmt.Schema == nil || stmt.ReflectValue.Kind() != reflect.Struct || tenant.IsSystem(stmt.Context) {
+		return
+	}
+	field := stmt.Schema.LookUpField(tenantColumn)
+	if field == nil {
+		return
+	}
+	if _, zero := field.ValueOf(stmt.Context, stmt.ReflectValue); zero {
+		if err := field.Set(stmt.Context, stmt.ReflectValue, tenant.ID(stmt.Context)); err != nil {
+			db.AddError(err)
+		}
+	}
+}
+
+// tenantCondition keeps the rows of column, a tenant_id in raw SQL, to the
+// tenant of ctx; across tenants it holds for every row.
+func tenantCondition(ctx context.Context, column string) clause.Expr {
+	if tenant.IsSystem(ctx) {
+		return clause.Expr{SQL: "TRUE"}
+	}
+	return clause.Expr{SQL: column + " = ?", Vars: []interface{}{tenant.ID(ctx)}}
+}
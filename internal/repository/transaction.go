@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+type txContextKey struct{}
+
+type afterCommitKey struct{}
+
+// commitHooks collects the functions to run once a transaction commits.
+type commitHooks struct {
+	mu  sync.Mutex
+	fns []func()
+}
+
+// Transactor runs a function inside a database transaction. Repositories
+// called with the context passed to fn join that transaction.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type gormTransactor struct {
+	db *gorm.DB
+}
+
+// NewTransactor creates a gorm-backed transactor.
+func NewTransactor(db *gorm.DB) Transactor {
+	return &gormTransactor{db: db}
+}
+
+// WithinTransaction commits when fn returns nil and rolls back otherwise. Nested
+// calls reuse the outer transaction.
+func (t *gormTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txContextKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+	hooks := &commitHooks{}
+	ctx = context.WithValue(ctx, afterCommitKey{}, hooks)
+	if err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txContextKey{}, tx))
+	}); err != nil {
+		return err
+	}
+	hooks.mu.Lock()
+	fns := hooks.fns
+	hooks.mu.Unlock()
+	for _, fn := range fns {
+		fn()
+	}
+	return nil
+}
+
+// AfterCommit runs fn once the transaction carried by ctx commits, and never
+// when it rolls back. Without a transaction fn runs straight away.
+func AfterCommit(ctx context.Context, fn func()) {
+	if !inTransaction(ctx) {
+		fn()
+		return
+	}
+	hooks := ctx.Value(afterCommitKey{}).(*commitHooks)
+	hooks.mu.Lock()
+	hooks.fns = append(hooks.fns, fn)
+	hooks.mu.Unlock()
+}
+
+// inTransaction reports whether ctx carries a transaction.
+func inTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(txContextKey{}).(*gorm.DB)
+	return ok
+}
+
+// conn returns the transaction carried by ctx, or db when there is none.
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txContextKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// UploadScanRepository persists malware scan results of uploads.
+type UploadScanRepository interface {
+	Create(ctx context.Context, scan *domain.UploadScan) error
+	List(ctx context.Context, filter UploadScanFilter, page Pagination) ([]domain.UploadScan, int64, error)
+}
+
+// UploadScanFilter narrows scan queries; zero values match everything.
+type UploadScanFilter struct {
+	Result string
+	From   *time.Time
+	To     *time.Time
+}
+
+type uploadScanRepository struct {
+	db *gorm.DB
+}
+
+// NewUploadScanRepository creates a gorm-backed repository.
+func NewUploadScanRepository(db *gorm.DB) UploadScanRepository {
+	return &uploadScanRepository{db: db}
+}
+
+func (r *uploadScanRepository) Create(ctx context.Context, scan *domain.UploadScan) error {
+	if err := conn(ctx, r.db).Create(scan).Error; err != nil {
+		return fmt.Errorf("create upload scan: %w", err)
+	}
+	return nil
+}
+
+func (r *uploadScanRepository) List(ctx context.Context, filter UploadScanFilter, page Pagination) ([]domain.UploadScan, int64, error) {
+	query := conn(ctx, r.db).Model(&domain.UploadScan{})
+	if filter.Result != "" {
+		query = query.Where("result = ?", filter.Result)
+	}
+	if filter.From != nil {
+		query = query.Where("scanned_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("scanned_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count upload scans: %w", err)
+	}
+
+	var scans []domain.UploadScan
+	if err := query.Order("scanned_at desc").Offset(page.Offset()).Limit(page.PageSize).Find(&scans).Error; err != nil {
+		return nil, 0, fmt.Errorf("list upload scans: %w", err)
+	}
+	return scans, total, nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// VerificationDeviceRepository persists the devices participants verify from.
+type VerificationDeviceRepository interface {
+	Get(ctx context.Context, participantID, deviceID string) (*domain.VerificationDevice, error)
+	ListByParticipant(ctx context.Context, participantID string) ([]domain.VerificationDevice, error)
+	// Record counts an attempt from the device, registering it on its first
+	// attempt and keeping the last reported model and OS.
+	Record(ctx context.Context, device *domain.VerificationDevice) error
+	// SetTrust trusts the device from at, or withdraws trust when at is nil,
+	// and reports whether the device exists.
+	SetTrust(ctx context.Context, participantID, deviceID string, at *time.Time, by *string) (bool, error)
+	DeleteByParticipant(ctx context.Context, participantID string) error
+}
+
+type verificationDeviceRepository struct {
+	db *gorm.DB
+}
+
+// NewVerificationDeviceRepository creates a gorm-backed repository.
+func NewVerificationDeviceRepository(db *gorm.DB) VerificationDeviceRepository {
+	return &verificationDeviceRepository{db: db}
+}
+
+func (r *verificationDeviceRepository) Get(ctx context.Context, participantID, deviceID string) (*domain.VerificationDevice, error) {
+	var device domain.VerificationDevice
+	if err := conn(ctx, r.db).First(&device, "participant_id = ? AND device_id = ?", participantID, deviceID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get verification device: %w", err)
+	}
+	return &device, nil
+}
+
+func (r *verificationDeviceRepository) ListByParticipant(ctx context.Context, participantID string) ([]domain.VerificationDevice, error) {
+	var devices []domain.VerificationDevice
+	if err := conn(ctx, r.db).Where("participant_id = ?", participantID).Order("last_seen_at DESC").Find(&devices).Error; err != nil {
+		return nil, fmt.Errorf("list verification devices: %w", err)
+	}
+	return devices, nil
+}
+
+// Record upserts in a single statement, so concurrent attempts from one
+// device each count once.
+func (r *verificationDeviceRepository) Record(ctx context.Context, device *domain.VerificationDevice) error {
+	err := conn(ctx, r.db).Clauses(
+		clause.OnConflict{
+			Columns: []clause.Column{{Name: "participant_id"}, {Name: "device_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"model":        gorm.Expr("COALESCE(excluded.model, verification_devices.model)"),
+				"os":           gorm.Expr("COALESCE(excluded.os, verification_devices.os)"),
+				"attempts":     gorm.Expr("verification_devices.attempts + 1"),
+				"last_seen_at": device.LastSeenAt,
+			}),
+		},
+	).Create(device).Error
+	if err != nil {
+		return fmt.Errorf("record verification device: %w", err)
+	}
+	return nil
+}
+
+func (r *verificationDeviceRepository) SetTrust(ctx context.Context, participantID, deviceID string, at *time.Time, by *string) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.VerificationDevice{}).
+		Where("participant_id = ? AND device_id = ?", participantID, deviceID).
+		Updates(map[string]interface{}{"trusted_at": at, "trusted_by": by})
+	if result.Error != nil {
+		return false, fmt.Errorf("set verification device trust: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *verificationDeviceRepository) DeleteByParticipant(ctx context.Context, participantID string) error {
+	if err := conn(ctx, r.db).Where("participant_id = ?", participantID).Delete(&domain.VerificationDevice{}).Error; err != nil {
+		return fmt.Errorf("delete verification devices: %w", err)
+	}
+	return nil
+}
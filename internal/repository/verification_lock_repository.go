@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// VerificationLockRepository persists participants' failed attempt counts and locks.
+type VerificationLockRepository interface {
+	GetByParticipant(ctx context.Context, participantID string) (*domain.VerificationLock, error)
+	// RecordFailure counts one more INVALID attempt and returns the updated row.
+	RecordFailure(ctx context.Context, participantID string, at time.Time) (*domain.VerificationLock, error)
+	// Lock locks the participant from at until until, nil for an indefinite
+	// lock, and starts the failure count over.
+	Lock(ctx context.Context, participantID string, at time.Time, until *time.Time) error
+	// ResetFailures starts the failure count over, leaving any lock in place.
+	ResetFailures(ctx context.Context, participantID string, at time.Time) error
+	// Unlock lifts the lock and starts the failure count over.
+	Unlock(ctx context.Context, participantID string, at time.Time) error
+	DeleteByParticipant(ctx context.Context, participantID string) error
+}
+
+type verificationLockRepository struct {
+	db *gorm.DB
+}
+
+// NewVerificationLockRepository creates a gorm-backed repository.
+func NewVerificationLockRepository(db *gorm.DB) VerificationLockRepository {
+	return &verificationLockRepository{db: db}
+}
+
+func (r *verificationLockRepository) GetByParticipant(ctx context.Context, participantID string) (*domain.VerificationLock, error) {
+	var lock domain.VerificationLock
+	if err := conn(ctx, r.db).First(&lock, "participant_id = ?", participantID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get verification lock: %w", err)
+	}
+	return &lock, nil
+}
+
+// RecordFailure increments the count in a single upsert, so concurrent
+// attempts for one participant each count once.
+func (r *verificationLockRepository) RecordFailure(ctx context.Context, participantID string, at time.Time) (*domain.VerificationLock, error) {
+	lock := &domain.VerificationLock{ParticipantID: participantID, FailedAttempts: 1, UpdatedAt: at}
+	err := conn(ctx, r.db).Clauses(
+		clause.OnConflict{
+			Columns: []clause.Column{{Name: "participant_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"failed_attempts": gorm.Expr("participant_verification_lock.failed_attempts + 1"),
+				"updated_at":      at,
+			}),
+		},
+		clause.Returning{},
+	).Create(lock).Error
+	if err != nil {
+		return nil, fmt.Errorf("record verification failure: %w", err)
+	}
+	return lock, nil
+}
+
+func (r *verificationLockRepository) Lock(ctx context.Context, participantID string, at time.Time, until *time.Time) error {
+	return r.update(ctx, participantID, "lock participant verification", map[string]interface{}{
+		"failed_attempts": 0,
+		"locked_at":       at,
+		"locked_until":    until,
+		"updated_at":      at,
+	})
+}
+
+func (r *verificationLockRepository) ResetFailures(ctx context.Context, participantID string, at time.Time) error {
+	return r.update(ctx, participantID, "reset verification failures", map[string]interface{}{
+		"failed_attempts": 0,
+		"updated_at":      at,
+	})
+}
+
+func (r *verificationLockRepository) Unlock(ctx context.Context, participantID string, at time.Time) error {
+	return r.update(ctx, participantID, "unlock participant verification", map[string]interface{}{
+		"failed_attempts": 0,
+		"locked_at":       nil,
+		"locked_until":    nil,
+		"updated_at":      at,
+	})
+}
+
+func (r *verificationLockRepository) update(ctx context.Context, participantID, action string, values map[string]interface{}) error {
+	if err := conn(ctx, r.db).Model(&domain.VerificationLock{}).
+		Where("participant_id = ?", participantID).
+		Updates(values).Error; err != nil {
+		return fmt.Errorf("%s: %w", action, err)
+	}
+	return nil
+}
+
+func (r *verificationLockRepository) DeleteByParticipant(ctx context.Context, participantID string) error {
+	if err := conn(ctx, r.db).Where("participant_id = ?", participantID).Delete(&domain.VerificationLock{}).Error; err != nil {
+		return fmt.Errorf("delete verification lock: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// VerificationProfileRepository persists verification profiles.
+type VerificationProfileRepository interface {
+	Create(ctx context.Context, profile *domain.VerificationProfile) error
+	GetByID(ctx context.Context, id string) (*domain.VerificationProfile, error)
+	GetByName(ctx context.Context, name string) (*domain.VerificationProfile, error)
+	GetByFund(ctx context.Context, fund string) (*domain.VerificationProfile, error)
+	List(ctx context.Context) ([]domain.VerificationProfile, error)
+	Update(ctx context.Context, profile *domain.VerificationProfile) error
+	Delete(ctx context.Context, id string) error
+}
+
+type verificationProfileRepository struct {
+	db *gorm.DB
+}
+
+// NewVerificationProfileRepository creates a gorm-backed repository.
+func NewVerificationProfileRepository(db *gorm.DB) VerificationProfileRepository {
+	return &verificationProfileRepository{db: db}
+}
+
+func (r *verificationProfileRepository) Create(ctx context.Context, profile *domain.VerificationProfile) error {
+	if err := conn(ctx, r.db).Create(profile).Error; err != nil {
+		return fmt.Errorf("create verification profile: %w", err)
+	}
+	return nil
+}
+
+func (r *verificationProfileRepository) GetByID(ctx context.Context, id string) (*domain.VerificationProfile, error) {
+	return r.first(ctx, "id = ?", id)
+}
+
+func (r *verificationProfileRepository) GetByName(ctx context.Context, name string) (*domain.VerificationProfile, error) {
+	return r.first(ctx, "name = ?", name)
+}
+
+func (r *verificationProfileRepository) GetByFund(ctx context.Context, fund string) (*domain.VerificationProfile, error) {
+	return r.first(ctx, "fund = ?", fund)
+}
+
+func (r *verificationProfileRepository) first(ctx context.Context, query string, arg interface{}) (*domain.VerificationProfile, error) {
+	var profile domain.VerificationProfile
+	if err := conn(ctx, r.db).First(&profile, query, arg).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get verification profile: %w", err)
+	}
+	return &profile, nil
+}
+
+func (r *verificationProfileRepository) List(ctx context.Context) ([]domain.VerificationProfile, error) {
+	var profiles []domain.VerificationProfile
+	if err := conn(ctx, r.db).Order("name asc").Find(&profiles).Error; err != nil {
+		return nil, fmt.Errorf("list verification profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+func (r *verificationProfileRepository) Update(ctx context.Context, profile *domain.VerificationProfile) error {
+	if err := conn(ctx, r.db).Model(&domain.VerificationProfile{}).
+		Where("id = ?", profile.ID).
+		Updates(map[string]interface{}{
+			"name":                 profile.Name,
+			"fund":                 profile.Fund,
+			"distance_threshold":   profile.DistanceThreshold,
+			"similarity_threshold": profile.SimilarityThreshold,
+			"liveness_policy":      profile.LivenessPolicy,
+			"max_attempts_per_day": profile.MaxAttemptsPerDay,
+			"schedule_policy":      profile.SchedulePolicy,
+			"schedule_date":        profile.ScheduleDate,
+			"schedule_months":      profile.ScheduleMonths,
+			"updated_at":           profile.UpdatedAt,
+		}).Error; err != nil {
+		return fmt.Errorf("update verification profile: %w", err)
+	}
+	return nil
+}
+
+func (r *verificationProfileRepository) Delete(ctx context.Context, id string) error {
+	if err := conn(ctx, r.db).Delete(&domain.VerificationProfile{}, "id = ?", id).Error; err != nil {
+		return fmt.Errorf("delete verification profile: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// VerificationRequestRepository persists asynchronous verification requests.
+type VerificationRequestRepository interface {
+	Create(ctx context.Context, request *domain.VerificationRequest) error
+	Update(ctx context.Context, request *domain.VerificationRequest) error
+	GetByID(ctx context.Context, id string) (*domain.VerificationRequest, error)
+}
+
+type verificationRequestRepository struct {
+	db *gorm.DB
+}
+
+// NewVerificationRequestRepository creates a gorm-backed repository.
+func NewVerificationRequestRepository(db *gorm.DB) VerificationRequestRepository {
+	return &verificationRequestRepository{db: db}
+}
+
+func (r *verificationRequestRepository) Create(ctx context.Context, request *domain.VerificationRequest) error {
+	if err := conn(ctx, r.db).Create(request).Error; err != nil {
+		return fmt.Errorf("create verification request: %w", err)
+	}
+	return nil
+}
+
+func (r *verificationRequestRepository) Update(ctx context.Context, request *domain.VerificationRequest) error {
+	if err := conn(ctx, r.db).Save(request).Error; err != nil {
+		return fmt.Errorf("update verification request: %w", err)
+	}
+	return nil
+}
+
+func (r *verificationRequestRepository) GetByID(ctx context.Context, id string) (*domain.VerificationRequest, error) {
+	var request domain.VerificationRequest
+	if err := conn(ctx, r.db).First(&request, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get verification request: %w", err)
+	}
+	return &request, nil
+}
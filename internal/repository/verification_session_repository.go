@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// VerificationSessionRepository persists verification sessions.
+type VerificationSessionRepository interface {
+	Create(ctx context.Context, session *domain.VerificationSession) error
+	GetByID(ctx context.Context, id string) (*domain.VerificationSession, error)
+	GetByTokenHash(ctx context.Context, hash string) (*domain.VerificationSession, error)
+	Consume(ctx context.Context, id, certificateID string, at time.Time) (bool, error)
+	// Close uses up a session whose attempt recorded no certificate, because
+	// it stepped up to another session.
+	Close(ctx context.Context, id string, at time.Time) (bool, error)
+}
+
+type verificationSessionRepository struct {
+	db *gorm.DB
+}
+
+// NewVerificationSessionRepository creates a gorm-backed repository.
+func NewVerificationSessionRepository(db *gorm.DB) VerificationSessionRepository {
+	return &verificationSessionRepository{db: db}
+}
+
+func (r *verificationSessionRepository) Create(ctx context.Context, session *domain.VerificationSession) error {
+	if err := conn(ctx, r.db).Create(session).Error; err != nil {
+		return fmt.Errorf("create verification session: %w", err)
+	}
+	return nil
+}
+
+func (r *verificationSessionRepository) GetByID(ctx context.Context, id string) (*domain.VerificationSession, error) {
+	var session domain.VerificationSession
+	if err := conn(ctx, r.db).First(&session, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get verification session: %w", err)
+	}
+	return &session, nil
+}
+
+func (r *verificationSessionRepository) GetByTokenHash(ctx context.Context, hash string) (*domain.VerificationSession, error) {
+	var session domain.VerificationSession
+	if err := conn(ctx, r.db).First(&session, "token_hash = ?", hash).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get verification session: %w", err)
+	}
+	return &session, nil
+}
+
+// Consume marks an unused session used by the certificate, reporting false
+// when another attempt used it first.
+func (r *verificationSessionRepository) Consume(ctx context.Context, id, certificateID string, at time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.VerificationSession{}).
+		Where("id = ? AND consumed_at IS NULL", id).
+		Updates(map[string]interface{}{"consumed_at": at, "certificate_id": certificateID})
+	if result.Error != nil {
+		return false, fmt.Errorf("consume verification session: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *verificationSessionRepository) Close(ctx context.Context, id string, at time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.VerificationSession{}).
+		Where("id = ? AND consumed_at IS NULL", id).
+		Update("consumed_at", at)
+	if result.Error != nil {
+		return false, fmt.Errorf("close verification session: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// VerificationStateRepository maintains participant_verification_state.
+// validityMonths sets valid_until from the latest VALID attempt.
+type VerificationStateRepository interface {
+	GetByParticipant(ctx context.Context, participantID string) (*domain.ParticipantVerificationState, error)
+	Record(ctx context.Context, record *domain.LifeCertificate, validityMonths int) error
+	UpdateOutcome(ctx context.Context, record *domain.LifeCertificate, validityMonths int) error
+	ClearSelfiePath(ctx context.Context, certificateID string) error
+	ClearSelfiesByParticipant(ctx context.Context, participantID string) error
+	DeleteByParticipant(ctx context.Context, participantID string) error
+	Backfill(ctx context.Context, validityMonths int) (int64, error)
+	Rebuild(ctx context.Context, participantID string, validityMonths int) (int64, error)
+}
+
+type verificationStateRepository struct {
+	db *gorm.DB
+}
+
+// NewVerificationStateRepository creates a gorm-backed repository.
+func NewVerificationStateRepository(db *gorm.DB) VerificationStateRepository {
+	return &verificationStateRepository{db: db}
+}
+
+func (r *verificationStateRepository) GetByParticipant(ctx context.Context, participantID string) (*domain.ParticipantVerificationState, error) {
+	var state domain.ParticipantVerificationState
+	if err := conn(ctx, r.db).First(&state, "participant_id = ?", participantID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get verification state: %w", err)
+	}
+	return &state, nil
+}
+
+// Record folds a newly created attempt into the participant's state. The
+// update only moves the state forward, so concurrent attempts for one
+// participant commit in either order; a missing row is built from the
+// participant's attempts, the new one included.
+func (r *verificationStateRepository) Record(ctx context.Context, record *domain.LifeCertificate, validityMonths int) error {
+	if err := conn(ctx, r.db).Exec(`
+		INSERT INTO participant_verification_state AS s
+			(participant_id, tenant_id, certificate_id, status, method, distance, similarity, verified_at, selfie_path, last_valid_at, valid_until, attempts, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?,
+			(SELECT MAX(verified_at) FROM life_certificate WHERE participant_id = ? AND status = ?),
+			(SELECT MAX(verified_at) + make_interval(months => ?) FROM life_certificate WHERE participant_id = ? AND status = ?),
+			(SELECT COUNT(*) FROM life_certificate WHERE participant_id = ?),
+			?)
+		ON CONFLICT (participant_id) DO UPDATE SET
+			certificate_id = CASE WHEN EXCLUDED.verified_at >= s.verified_at THEN EXCLUDED.certificate_id ELSE s.certificate_id END,
+			status = CASE WHEN EXCLUDED.verified_at >= s.verified_at THEN EXCLUDED.status ELSE s.status END,
+			method = CASE WHEN EXCLUDED.verified_at >= s.verified_at THEN EXCLUDED.method ELSE s.method END,
+			distance = CASE WHEN EXCLUDED.verified_at >= s.verified_at THEN EXCLUDED.distance ELSE s.distance END,
+			similarity = CASE WHEN EXCLUDED.verified_at >= s.verified_at THEN EXCLUDED.similarity ELSE s.similarity END,
+			selfie_path = CASE WHEN EXCLUDED.verified_at >= s.verified_at THEN EXCLUDED.selfie_path ELSE s.selfie_path END,
+			verified_at = GREATEST(s.verified_at, EXCLUDED.verified_at),
+			last_valid_at = GREATEST(s.last_valid_at, EXCLUDED.last_valid_at),
+			valid_until = CASE WHEN s.last_valid_at IS NULL OR EXCLUDED.last_valid_at > s.last_valid_at THEN EXCLUDED.valid_until ELSE s.valid_until END,
+			attempts = s.attempts + 1,
+			updated_at = EXCLUDED.updated_at`,
+		record.ParticipantID, record.TenantID, record.ID, record.Status, record.Method, record.Distance, record.Similarity, record.VerifiedAt, record.SelfiePath,
+		record.ParticipantID, domain.LifeCertificateStatusValid,
+		validityMonths, record.ParticipantID, domain.LifeCertificateStatusValid,
+		record.ParticipantID,
+		time.Now().UTC(),
+	).Error; err != nil {
+		return fmt.Errorf("record verification state: %w", err)
+	}
+	return nil
+}
+
+// UpdateOutcome applies a reviewed attempt's new status: to the latest status
+// if it is still the latest attempt, and to valid_until if it passed.
+func (r *verificationStateRepository) UpdateOutcome(ctx context.Context, record *domain.LifeCertificate, validityMonths int) error {
+	now := time.Now().UTC()
+	db := conn(ctx, r.db)
+	if err := db.Model(&domain.ParticipantVerificationState{}).
+		Where("participant_id = ? AND certificate_id = ?", record.ParticipantID, record.ID).
+		Updates(map[string]interface{}{"status": record.Status, "updated_at": now}).Error; err != nil {
+		return fmt.Errorf("update verification state status: %w", err)
+	}
+	if record.Status != domain.LifeCertificateStatusValid {
+		return nil
+	}
+	if err := db.Exec(`
+		UPDATE participant_verification_state
+		SET last_valid_at = ?, valid_until = ?::timestamptz + make_interval(months => ?), updated_at = ?
+		WHERE ? AND participant_id = ? AND (last_valid_at IS NULL OR last_valid_at < ?)`,
+		record.VerifiedAt, record.VerifiedAt, validityMonths, now, tenantCondition(ctx, "tenant_id"), record.ParticipantID, record.VerifiedAt,
+	).Error; err != nil {
+		return fmt.Errorf("update verification state validity: %w", err)
+	}
+	return nil
+}
+
+// ClearSelfiePath forgets the stored selfie if it belongs to the latest attempt.
+func (r *verificationStateRepository) ClearSelfiePath(ctx context.Context, certificateID string) error {
+	if err := conn(ctx, r.db).Model(&domain.ParticipantVerificationState{}).
+		Where("certificate_id = ?", certificateID).
+		Update("selfie_path", "").Error; err != nil {
+		return fmt.Errorf("clear verification state selfie: %w", err)
+	}
+	return nil
+}
+
+func (r *verificationStateRepository) ClearSelfiesByParticipant(ctx context.Context, participantID string) error {
+	if err := conn(ctx, r.db).Model(&domain.ParticipantVerificationState{}).
+		Where("participant_id = ?", participantID).
+		Update("selfie_path", "").Error; err != nil {
+		return fmt.Errorf("clear verification state selfie: %w", err)
+	}
+	return nil
+}
+
+func (r *verificationStateRepository) DeleteByParticipant(ctx context.Context, participantID string) error {
+	if err := conn(ctx, r.db).Where("participant_id = ?", participantID).Delete(&domain.ParticipantVerificationState{}).Error; err != nil {
+		return fmt.Errorf("delete verification state: %w", err)
+	}
+	return nil
+}
+
+// Backfill builds the state of participants whose attempts predate the table.
+func (r *verificationStateRepository) Backfill(ctx context.Context, validityMonths int) (int64, error) {
+	built, err := buildStates(conn(ctx, r.db), validityMonths, "? AND participant_id NOT IN (SELECT participant_id FROM participant_verification_state)", tenantCondition(ctx, "tenant_id"))
+	if err != nil {
+		return 0, fmt.Errorf("backfill verification state: %w", err)
+	}
+	return built, nil
+}
+
+// Rebuild recomputes the state of one participant, or of every participant
+// when participantID is empty, from their attempts.
+func (r *verificationStateRepository) Rebuild(ctx context.Context, participantID string, validityMonths int) (int64, error) {
+	var built int64
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		scope, args := "?", []interface{}{tenantCondition(ctx, "tenant_id")}
+		if participantID != "" {
+			scope, args = "? AND participant_id = ?", append(args, participantID)
+		}
+		if err := tx.Exec("DELETE FROM participant_verification_state WHERE "+scope, args...).Error; err != nil {
+			return err
+		}
+		var err error
+		built, err = buildStates(tx, validityMonths, scope, args...)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("rebuild verification state: %w", err)
+	}
+	return built, nil
+}
+
+// buildStates inserts the state of the participants whose attempts match
+// scope, a condition on life_certificate, leaving existing rows alone.
+func buildStates(db *gorm.DB, validityMonths int, scope string, scopeArgs ...interface{}) (int64, error) {
+	args := []interface{}{validityMonths, time.Now().UTC()}
+	args = append(args, scopeArgs...)
+	args = append(args, domain.LifeCertificateStatusValid)
+	args = append(args, scopeArgs...)
+	result := db.Exec(`
+		INSERT INTO participant_verification_state
+			(participant_id, tenant_id, certificate_id, status, method, distance, similarity, verified_at, selfie_path, last_valid_at, valid_until, attempts, updated_at)
+		SELECT latest.participant_id, latest.tenant_id, latest.id, latest.status, latest.method, latest.distance, latest.similarity, latest.verified_at, latest.selfie_path,
+			v.last_valid_at, v.last_valid_at + make_interval(months => ?), v.attempts, ?
+		FROM (
+			SELECT DISTINCT ON (participant_id) *
+			FROM life_certificate
+			WHERE `+scope+`
+			ORDER BY participant_id, verified_at DESC
+		) latest
+		JOIN (
+			SELECT participant_id, MAX(verified_at) FILTER (WHERE status = ?) AS last_valid_at, COUNT(*) AS attempts
+			FROM life_certificate
+			WHERE `+scope+`
+			GROUP BY participant_id
+		) v ON v.participant_id = latest.participant_id
+		ON CONFLICT (participant_id) DO NOTHING`,
+		args...,
+	)
+	return result.RowsAffected, result.Error
+}
+
+// stateTrackingLifeCertificateRepository keeps participant_verification_state
+// in step with every write to life_certificate, in the same transaction.
+type stateTrackingLifeCertificateRepository struct {
+	LifeCertificateRepository
+	states         VerificationStateRepository
+	tx             Transactor
+	validityMonths int
+}
+
+// NewStateTrackingLifeCertificateRepository maintains states alongside inner.
+func NewStateTrackingLifeCertificateRepository(inner LifeCertificateRepository, states VerificationStateRepository, tx Transactor, validityMonths int) LifeCertificateRepository {
+	return &stateTrackingLifeCertificateRepository{LifeCertificateRepository: inner, states: states, tx: tx, validityMonths: validityMonths}
+}
+
+func (r *stateTrackingLifeCertificateRepository) Create(ctx context.Context, record *domain.LifeCertificate) error {
+	return r.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := r.LifeCertificateRepository.Create(ctx, record); err != nil {
+			return err
+		}
+		return r.states.Record(ctx, record, r.validityMonths)
+	})
+}
+
+func (r *stateTrackingLifeCertificateRepository) Resolve(ctx context.Context, record *domain.LifeCertificate) (bool, error) {
+	var resolved bool
+	err := r.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		ok, err := r.LifeCertificateRepository.Resolve(ctx, record)
+		if err != nil || !ok {
+			return err
+		}
+		resolved = true
+		return r.states.UpdateOutcome(ctx, record, r.validityMonths)
+	})
+	return resolved, err
+}
+
+// OverrideStatus rebuilds the participant's state rather than patching it:
+// an override can move any attempt, including one that was VALID, so
+// last_valid_at and valid_until may have to go back.
+func (r *stateTrackingLifeCertificateRepository) OverrideStatus(ctx context.Context, record *domain.LifeCertificate, from domain.LifeCertificateStatus) (bool, error) {
+	var applied bool
+	err := r.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		ok, err := r.LifeCertificateRepository.OverrideStatus(ctx, record, from)
+		if err != nil || !ok {
+			return err
+		}
+		applied = true
+		_, err = r.states.Rebuild(ctx, record.ParticipantID, r.validityMonths)
+		return err
+	})
+	return applied, err
+}
+
+func (r *stateTrackingLifeCertificateRepository) ClearSelfiePath(ctx context.Context, id string) error {
+	return r.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := r.LifeCertificateRepository.ClearSelfiePath(ctx, id); err != nil {
+			return err
+		}
+		return r.states.ClearSelfiePath(ctx, id)
+	})
+}
+
+func (r *stateTrackingLifeCertificateRepository) AnonymizeByParticipant(ctx context.Context, participantID string) error {
+	return r.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := r.LifeCertificateRepository.AnonymizeByParticipant(ctx, participantID); err != nil {
+			return err
+		}
+		return r.states.ClearSelfiesByParticipant(ctx, participantID)
+	})
+}
+
+func (r *stateTrackingLifeCertificateRepository) DeleteByParticipant(ctx context.Context, participantID string) error {
+	return r.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := r.LifeCertificateRepository.DeleteByParticipant(ctx, participantID); err != nil {
+			return err
+		}
+		return r.states.DeleteByParticipant(ctx, participantID)
+	})
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"life-certificates/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// WebhookRepository persists webhook subscriptions and their delivery log.
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error
+	GetSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+	ListActiveSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error
+	DeleteSubscription(ctx context.Context, id string) error
+	CreateDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error
+	HasDeliveriesForEvent(ctx context.Context, eventID string) (bool, error)
+	ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error)
+	ClaimDelivery(ctx context.Context, id string, scheduledAt, leaseUntil time.Time) (bool, error)
+	UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+	ListDeliveries(ctx context.Context, subscriptionID string, status domain.WebhookDeliveryStatus, page Pagination) ([]domain.WebhookDelivery, int64, error)
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository creates a gorm-backed repository.
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	if err := conn(ctx, r.db).Create(sub).Error; err != nil {
+		return fmt.Errorf("create webhook subscription: %w", err)
+	}
+	return nil
+}
+
+func (r *webhookRepository) GetSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	var sub domain.WebhookSubscription
+	if err := conn(ctx, r.db).First(&sub, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get webhook subscription: %w", err)
+	}
+	return &sub, nil
+}
+
+func (r *webhookRepository) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	var subs []domain.WebhookSubscription
+	if err := conn(ctx, r.db).Order("created_at asc").Find(&subs).Error; err != nil {
+		return nil, fmt.Errorf("list webhook subscriptions: %w", err)
+	}
+	return subs, nil
+}
+
+func (r *webhookRepository) ListActiveSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	var subs []domain.WebhookSubscription
+	if err := conn(ctx, r.db).Where("active = ?", true).Find(&subs).Error; err != nil {
+		return nil, fmt.Errorf("list active webhook subscriptions: %w", err)
+	}
+	return subs, nil
+}
+
+func (r *webhookRepository) UpdateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	if err := conn(ctx, r.db).Model(&domain.WebhookSubscription{}).
+		Where("id = ?", sub.ID).
+		Updates(map[string]interface{}{
+			"url":         sub.URL,
+			"event_types": sub.EventTypes,
+			"active":      sub.Active,
+			"updated_at":  sub.UpdatedAt,
+		}).Error; err != nil {
+		return fmt.Errorf("update webhook subscription: %w", err)
+	}
+	return nil
+}
+
+// DeleteSubscription removes the subscription and its delivery log.
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, id string) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("subscription_id = ?", id).Delete(&domain.WebhookDelivery{}).Error; err != nil {
+			return fmt.Errorf("delete webhook deliveries: %w", err)
+		}
+		if err := tx.Delete(&domain.WebhookSubscription{}, "id = ?", id).Error; err != nil {
+			return fmt.Errorf("delete webhook subscription: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *webhookRepository) CreateDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := conn(ctx, r.db).Create(&deliveries).Error; err != nil {
+		return fmt.Errorf("create webhook deliveries: %w", err)
+	}
+	return nil
+}
+
+func (r *webhookRepository) HasDeliveriesForEvent(ctx context.Context, eventID string) (bool, error) {
+	var count int64
+	if err := conn(ctx, r.db).Model(&domain.WebhookDelivery{}).Where("event_id = ?", eventID).Limit(1).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("check webhook deliveries: %w", err)
+	}
+	return count > 0, nil
+}
+
+// ListDueDeliveries returns pending deliveries whose next attempt is due, oldest first.
+func (r *webhookRepository) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	if err := conn(ctx, r.db).
+		Where("status = ? AND next_attempt_at <= ?", domain.WebhookDeliveryPending, now).
+		Order("next_attempt_at asc").
+		Limit(limit).
+		Find(&deliveries).Error; err != nil {
+		return nil, fmt.Errorf("list due webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// ClaimDelivery pushes the next attempt out to leaseUntil so concurrent dispatchers skip it.
+// It reports false when another dispatcher already claimed the delivery.
+func (r *webhookRepository) ClaimDelivery(ctx context.Context, id string, scheduledAt, leaseUntil time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&domain.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at = ?", id, domain.WebhookDeliveryPending, scheduledAt).
+		Update("next_attempt_at", leaseUntil)
+	if result.Error != nil {
+		return false, fmt.Errorf("claim webhook delivery: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	if err := conn(ctx, r.db).Model(&domain.WebhookDelivery{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]interface{}{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"response_code":   delivery.ResponseCode,
+			"last_error":      delivery.LastError,
+			"delivered_at":    delivery.DeliveredAt,
+		}).Error; err != nil {
+		return fmt.Errorf("update webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries returns the delivery log of a subscription, newest first.
+func (r *webhookRepository) ListDeliveries(ctx context.Context, subscriptionID string, status domain.WebhookDeliveryStatus, page Pagination) ([]domain.WebhookDelivery, int64, error) {
+	query := conn(ctx, r.db).Model(&domain.WebhookDelivery{}).Where("subscription_id = ?", subscriptionID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count webhook deliveries: %w", err)
+	}
+
+	var deliveries []domain.WebhookDelivery
+	if err := query.Order("created_at desc").Offset(page.Offset()).Limit(page.PageSize).Find(&deliveries).Error; err != nil {
+		return nil, 0, fmt.Errorf("list webhook deliveries: %w", err)
+	}
+	return deliveries, total, nil
+}
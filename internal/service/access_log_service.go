@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+)
+
+const (
+	auditEntityEndpoint  = "endpoint"
+	auditActionPIIUnmask = "pii.unmask"
+)
+
+// AccessLogService records and queries who read which pensioner's data.
+type AccessLogService struct {
+	logs  repository.AccessLogRepository
+	audit repository.AuditLogRepository
+}
+
+// NewAccessLogService wires dependencies for access logging.
+func NewAccessLogService(logs repository.AccessLogRepository, audit repository.AuditLogRepository) *AccessLogService {
+	return &AccessLogService{logs: logs, audit: audit}
+}
+
+// AccessLogQueryInput carries access log filters and paging.
+type AccessLogQueryInput struct {
+	Actor        string
+	ResourceType string
+	ResourceID   string
+	From         string
+	To           string
+	Page         int
+	PageSize     int
+}
+
+// AccessLogListOutput is a page of access log entries, newest first.
+type AccessLogListOutput struct {
+	Items    []domain.AccessLog `json:"items"`
+	Page     int                `json:"page"`
+	PageSize int                `json:"page_size"`
+	Total    int64              `json:"total"`
+}
+
+// RecordAccess stores a read of a resource by the actor.
+func (s *AccessLogService) RecordAccess(ctx context.Context, actor, resourceType, resourceID, path, remoteIP string) error {
+	if actor == "" {
+		actor = systemActor
+	}
+	return s.logs.Create(ctx, &domain.AccessLog{
+		ID:           uuid.NewString(),
+		Actor:        actor,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Path:         path,
+		RemoteIP:     remoteIP,
+		CreatedAt:    time.Now().UTC(),
+	})
+}
+
+// RecordUnmask audit-logs a response that listed personal data unmasked.
+func (s *AccessLogService) RecordUnmask(ctx context.Context, actor, path, query, remoteIP string) error {
+	return recordAudit(ctx, s.audit, actor, auditActionPIIUnmask, auditEntityEndpoint, path, map[string]interface{}{
+		"query":     query,
+		"remote_ip": remoteIP,
+	})
+}
+
+// List returns access log entries matching the filters, newest first.
+func (s *AccessLogService) List(ctx context.Context, input AccessLogQueryInput) (*AccessLogListOutput, error) {
+	filter := repository.AccessLogFilter{
+		Actor:        strings.TrimSpace(input.Actor),
+		ResourceType: strings.TrimSpace(input.ResourceType),
+		ResourceID:   strings.TrimSpace(input.ResourceID),
+	}
+	verr := &ValidationError{}
+	filter.From, filter.To = parseDateRange(verr, input.From, input.To)
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	page := normalizePagination(input.Page, input.PageSize)
+	items, total, err := s.logs.List(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccessLogListOutput{Items: items, Page: page.Page, PageSize: page.PageSize, Total: total}, nil
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/events"
+	"life-certificates/internal/metrics"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/tenant"
+)
+
+const (
+	alertEvaluateInterval = 5 * time.Minute
+	alertFRCoreMinCalls   = 10
+	alertInvalidWindow    = time.Hour
+	alertRepeatedWindow   = 24 * time.Hour
+
+	alertSeverityWarning  = "warning"
+	alertSeverityCritical = "critical"
+)
+
+// AlertThresholds sets when operational alerts fire; a zero threshold disables its alert.
+type AlertThresholds struct {
+	// FRCoreErrorRate is the share of failed FR Core calls within one evaluation interval.
+	FRCoreErrorRate float64
+	// InvalidRatio is the share of INVALID attempts within the last hour, once InvalidMin attempts are INVALID.
+	InvalidRatio float64
+	InvalidMin   int64
+	// ReviewBacklog is the number of pending REVIEW attempts.
+	ReviewBacklog int64
+	// RepeatedFailures is the number of INVALID attempts by one participant within a day.
+	RepeatedFailures int
+	// Cooldown suppresses repeats of the same alert.
+	Cooldown time.Duration
+}
+
+// AlertService watches operational thresholds and raises alert.triggered
+// events, which reach webhooks, brokers and the alert notifiers through the outbox.
+// It watches the whole deployment, so alerts belong to the default tenant.
+type AlertService struct {
+	certificates repository.LifeCertificateRepository
+	reviews      *ReviewService
+	events       events.Publisher
+	thresholds   AlertThresholds
+
+	lastFired     map[string]time.Time
+	lastFRCalls   uint64
+	lastFRFailure uint64
+}
+
+// NewAlertService wires dependencies for operational alerting.
+func NewAlertService(certificates repository.LifeCertificateRepository, reviews *ReviewService, publisher events.Publisher, thresholds AlertThresholds) *AlertService {
+	return &AlertService{
+		certificates: certificates,
+		reviews:      reviews,
+		events:       publisher,
+		thresholds:   thresholds,
+		lastFired:    make(map[string]time.Time),
+	}
+}
+
+// Start evaluates the thresholds periodically until ctx is cancelled.
+func (s *AlertService) Start(ctx context.Context) {
+	s.lastFRCalls, s.lastFRFailure = metrics.FRCoreTotals()
+	go func() {
+		ticker := time.NewTicker(alertEvaluateInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.evaluate(tenant.System(ctx), time.Now().UTC())
+			}
+		}
+	}()
+}
+
+func (s *AlertService) evaluate(ctx context.Context, now time.Time) {
+	for key, at := range s.lastFired {
+		if now.Sub(at) >= s.thresholds.Cooldown {
+			delete(s.lastFired, key)
+		}
+	}
+	s.checkFRCore(ctx, now)
+	if err := s.checkInvalidSpike(ctx, now); err != nil {
+		log.Printf("[alert] check invalid results: %v", err)
+	}
+	if err := s.checkReviewBacklog(ctx, now); err != nil {
+		log.Printf("[alert] check review backlog: %v", err)
+	}
+	if err := s.checkRepeatedFailures(ctx, now); err != nil {
+		log.Printf("[alert] check repeated failures: %v", err)
+	}
+}
+
+func (s *AlertService) checkFRCore(ctx context.Context, now time.Time) {
+	calls, failures := metrics.FRCoreTotals()
+	deltaCalls, deltaFailures := calls-s.lastFRCalls, failures-s.lastFRFailure
+	s.lastFRCalls, s.lastFRFailure = calls, failures
+
+	if s.thresholds.FRCoreErrorRate <= 0 || deltaCalls < alertFRCoreMinCalls {
+		return
+	}
+	rate := float64(deltaFailures) / float64(deltaCalls)
+	if rate < s.thresholds.FRCoreErrorRate {
+		return
+	}
+	s.fire(ctx, now, "frcore_error_rate", "frcore_error_rate", alertSeverityCritical,
+		fmt.Sprintf("FR Core error rate %.0f%% over the last %s", rate*100, alertEvaluateInterval),
+		map[string]interface{}{"calls": deltaCalls, "failures": deltaFailures, "threshold": s.thresholds.FRCoreErrorRate})
+}
+
+func (s *AlertService) checkInvalidSpike(ctx context.Context, now time.Time) error {
+	if s.thresholds.InvalidRatio <= 0 {
+		return nil
+	}
+	counts, err := s.certificates.CountByStatusSince(ctx, now.Add(-alertInvalidWindow))
+	if err != nil {
+		return err
+	}
+	var total int64
+	for _, count := range counts {
+		total += count
+	}
+	invalid := counts[domain.LifeCertificateStatusInvalid]
+	if total == 0 || invalid < s.thresholds.InvalidMin {
+		return nil
+	}
+	ratio := float64(invalid) / float64(total)
+	if ratio < s.thresholds.InvalidRatio {
+		return nil
+	}
+	s.fire(ctx, now, "invalid_spike", "invalid_spike", alertSeverityWarning,
+		fmt.Sprintf("%d of %d verifications in the last hour were INVALID", invalid, total),
+		map[string]interface{}{"invalid": invalid, "total": total, "threshold": s.thresholds.InvalidRatio})
+	return nil
+}
+
+func (s *AlertService) checkReviewBacklog(ctx context.Context, now time.Time) error {
+	if s.thresholds.ReviewBacklog <= 0 {
+		return nil
+	}
+	pending, overdue, err := s.reviews.QueueDepth(ctx)
+	if err != nil {
+		return err
+	}
+	if pending < s.thresholds.ReviewBacklog {
+		return nil
+	}
+	s.fire(ctx, now, "review_backlog", "review_backlog", alertSeverityWarning,
+		fmt.Sprintf("%d attempts are waiting for manual review", pending),
+		map[string]interface{}{"pending": pending, "overdue": overdue, "threshold": s.thresholds.ReviewBacklog})
+	return nil
+}
+
+func (s *AlertService) checkRepeatedFailures(ctx context.Context, now time.Time) error {
+	if s.thresholds.RepeatedFailures <= 0 {
+		return nil
+	}
+	participants, err := s.certificates.RepeatedStatus(ctx, domain.LifeCertificateStatusInvalid, now.Add(-alertRepeatedWindow), s.thresholds.RepeatedFailures)
+	if err != nil {
+		return err
+	}
+	for _, p := range participants {
+		s.fire(ctx, now, "repeated_failures:"+p.ParticipantID, "repeated_failures", alertSeverityWarning,
+			fmt.Sprintf("participant %s failed verification %d times in the last day", p.ParticipantID, p.Count),
+			map[string]interface{}{"participant_id": p.ParticipantID, "failures": p.Count, "threshold": s.thresholds.RepeatedFailures})
+	}
+	return nil
+}
+
+// fire publishes the alert unless the same key fired within the cooldown.
+func (s *AlertService) fire(ctx context.Context, now time.Time, key, kind, severity, message string, details map[string]interface{}) {
+	if last, ok := s.lastFired[key]; ok && now.Sub(last) < s.thresholds.Cooldown {
+		return
+	}
+	log.Printf("[alert] %s: %s", kind, message)
+	err := publishEvent(ctx, s.events, events.TypeAlertTriggered, map[string]interface{}{
+		"kind":     kind,
+		"severity": severity,
+		"message":  message,
+		"details":  details,
+	})
+	if err != nil {
+		log.Printf("[alert] publish %s: %v", kind, err)
+		return
+	}
+	s.lastFired[key] = now
+}
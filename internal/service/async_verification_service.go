@@ -0,0 +1,345 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/events"
+	"life-certificates/internal/imaging"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/storage"
+)
+
+// JobTypeVerificationProcess runs an asynchronous verification request.
+const JobTypeVerificationProcess = "verification.process"
+
+// ErrVerificationRequestNotFound indicates the requested asynchronous verification does not exist.
+var ErrVerificationRequestNotFound = errors.New("verification request not found")
+
+// AsyncVerificationService accepts selfies for verification in the background,
+// so clients need not hold a connection through liveness and FR Core.
+type AsyncVerificationService struct {
+	requests     repository.VerificationRequestRepository
+	verification *VerificationService
+	sessions     *VerificationSessionService
+	blobs        storage.BlobStore
+	scans        *UploadScanService
+	jobs         *JobService
+	tx           repository.Transactor
+	events       events.Publisher
+}
+
+// verificationJob is the payload of a JobTypeVerificationProcess job.
+type verificationJob struct {
+	VerificationID string `json:"verification_id"`
+}
+
+// NewAsyncVerificationService wires dependencies for asynchronous
+// verification and registers its job handler. Uploads are scanned by scans
+// and wait in blobs until they are processed.
+func NewAsyncVerificationService(requests repository.VerificationRequestRepository, verification *VerificationService, sessions *VerificationSessionService, blobs storage.BlobStore, scans *UploadScanService, jobs *JobService, tx repository.Transactor, publisher events.Publisher) *AsyncVerificationService {
+	s := &AsyncVerificationService{
+		requests:     requests,
+		verification: verification,
+		sessions:     sessions,
+		blobs:        blobs,
+		scans:        scans,
+		jobs:         jobs,
+		tx:           tx,
+		events:       publisher,
+	}
+	jobs.Register(JobTypeVerificationProcess, s.processJob)
+	return s
+}
+
+// Submit refuses what can be refused without processing the photo, then
+// scans and stores it and queues its verification. A session token is checked now and
+// the session used up when the queued attempt records its certificate.
+func (s *AsyncVerificationService) Submit(ctx context.Context, actor, sessionToken string, input VerifyInput) (*domain.VerificationRequest, error) {
+	input, err := s.sessions.Attach(ctx, sessionToken, input)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateVerifyInput(input); err != nil {
+		return nil, err
+	}
+	participant, err := s.verification.eligibleParticipant(ctx, input.ParticipantID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	request := &domain.VerificationRequest{
+		ID:               uuid.NewString(),
+		ParticipantID:    participant.ID,
+		Status:           domain.VerificationRequestQueued,
+		OriginalFilename: input.OriginalFilename,
+		Latitude:         input.Latitude,
+		Longitude:        input.Longitude,
+		SubmittedBy:      actor,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if location := strings.TrimSpace(input.Location); location != "" {
+		request.Location = &location
+	}
+	if input.ClientIP != "" {
+		request.ClientIP = &input.ClientIP
+	}
+	if input.DeviceID != "" {
+		request.DeviceID = &input.DeviceID
+	}
+	if input.DeviceModel != "" {
+		request.DeviceModel = &input.DeviceModel
+	}
+	if input.DeviceOS != "" {
+		request.DeviceOS = &input.DeviceOS
+	}
+	if input.SessionID != "" {
+		request.SessionID = &input.SessionID
+	}
+	// The upload is kept as sent until the job decodes it, so it is scanned
+	// like any other stored file.
+	scan, err := s.scans.Check(ctx, actor, input.OriginalFilename, input.ImageBytes)
+	if err != nil {
+		return nil, err
+	}
+	request.ImageKey = fmt.Sprintf("verifications/%s/upload", request.ID)
+	if err := s.blobs.Put(ctx, request.ImageKey, input.ImageBytes); err != nil {
+		return nil, fmt.Errorf("store upload: %w", err)
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.requests.Create(ctx, request); err != nil {
+			return err
+		}
+		if err := s.scans.Record(ctx, scan); err != nil {
+			return err
+		}
+		_, err := s.jobs.Enqueue(ctx, actor, JobTypeVerificationProcess, verificationJob{VerificationID: request.ID})
+		return err
+	})
+	if err != nil {
+		s.discardUpload(ctx, request)
+		return nil, err
+	}
+	return request, nil
+}
+
+// Get returns an asynchronous verification request.
+func (s *AsyncVerificationService) Get(ctx context.Context, id string) (*domain.VerificationRequest, error) {
+	request, err := s.requests.GetByID(ctx, strings.TrimSpace(id))
+	if err != nil {
+		return nil, err
+	}
+	if request == nil {
+		return nil, ErrVerificationRequestNotFound
+	}
+	return request, nil
+}
+
+// processJob verifies a queued request. Refusals such as a suspended
+// participant fail the request straight away; other errors fail the attempt
+// so the job retries it, and fail the request once the attempts run out.
+func (s *AsyncVerificationService) processJob(ctx context.Context, payload json.RawMessage) (interface{}, error) {
+	var input verificationJob
+	if err := json.Unmarshal(payload, &input); err != nil {
+		return nil, fmt.Errorf("decode verification job: %w", err)
+	}
+	request, err := s.requests.GetByID(ctx, input.VerificationID)
+	if err != nil {
+		return nil, err
+	}
+	if request == nil || request.Status == domain.VerificationRequestCompleted || request.Status == domain.VerificationRequestFailed {
+		return nil, nil
+	}
+
+	request.Status = domain.VerificationRequestProcessing
+	request.UpdatedAt = time.Now().UTC()
+	if err := s.requests.Update(ctx, request); err != nil {
+		return nil, err
+	}
+
+	image, err := s.readUpload(ctx, request)
+	if errors.Is(err, storage.ErrNotFound) {
+		return s.fail(ctx, request, "UPLOAD_MISSING", errors.New("the uploaded image is no longer stored"))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var location, clientIP, deviceID, deviceModel, deviceOS string
+	if request.Location != nil {
+		location = *request.Location
+	}
+	if request.ClientIP != nil {
+		clientIP = *request.ClientIP
+	}
+	if request.DeviceID != nil {
+		deviceID = *request.DeviceID
+	}
+	if request.DeviceModel != nil {
+		deviceModel = *request.DeviceModel
+	}
+	if request.DeviceOS != nil {
+		deviceOS = *request.DeviceOS
+	}
+	verify := VerifyInput{
+		ParticipantID:    request.ParticipantID,
+		ImageBytes:       image,
+		OriginalFilename: request.OriginalFilename,
+		Location:         location,
+		Latitude:         request.Latitude,
+		Longitude:        request.Longitude,
+		ClientIP:         clientIP,
+		DeviceID:         deviceID,
+		DeviceModel:      deviceModel,
+		DeviceOS:         deviceOS,
+		// Completing the request with the certificate keeps a retried job from verifying twice.
+		OnRecorded: func(ctx context.Context, record *domain.LifeCertificate) error {
+			now := time.Now().UTC()
+			request.Status = domain.VerificationRequestCompleted
+			request.CertificateID = &record.ID
+			request.VerificationStatus = &record.Status
+			request.Similarity = record.Similarity
+			request.Distance = record.Distance
+			request.CompletedAt = &now
+			request.UpdatedAt = now
+			if err := s.requests.Update(ctx, request); err != nil {
+				return err
+			}
+			return publishEvent(ctx, s.events, events.TypeVerificationRequestCompleted, verificationRequestData(request))
+		},
+	}
+	if request.SessionID != nil {
+		verify, err = s.sessions.Resume(ctx, *request.SessionID, verify)
+	}
+	if err == nil {
+		_, err = s.verification.Verify(ctx, verify)
+	}
+	if err == nil {
+		s.discardUpload(ctx, request)
+		return map[string]interface{}{
+			"verification_id": request.ID,
+			"certificate_id":  request.CertificateID,
+		}, nil
+	}
+
+	code := verificationErrorCode(err)
+	switch {
+	case code != "":
+		return s.fail(ctx, request, code, err)
+	case finalJobAttempt(ctx):
+		if _, failErr := s.fail(ctx, request, "PROCESSING_FAILED", err); failErr != nil {
+			return nil, failErr
+		}
+		return nil, err
+	default:
+		return nil, err
+	}
+}
+
+// fail records a request that will not produce a certificate and notifies subscribers.
+func (s *AsyncVerificationService) fail(ctx context.Context, request *domain.VerificationRequest, code string, cause error) (interface{}, error) {
+	// The outcome is recorded even if the attempt timed out.
+	ctx = context.WithoutCancel(ctx)
+	now := time.Now().UTC()
+	message := cause.Error()
+	request.Status = domain.VerificationRequestFailed
+	// A hook that ran in a rolled-back transaction may have filled in an outcome.
+	request.CertificateID, request.VerificationStatus, request.Similarity, request.Distance = nil, nil, nil, nil
+	request.ErrorCode = &code
+	request.Error = &message
+	request.CompletedAt = &now
+	request.UpdatedAt = now
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.requests.Update(ctx, request); err != nil {
+			return err
+		}
+		return publishEvent(ctx, s.events, events.TypeVerificationRequestCompleted, verificationRequestData(request))
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.discardUpload(ctx, request)
+	return map[string]interface{}{
+		"verification_id": request.ID,
+		"error_code":      code,
+	}, nil
+}
+
+func (s *AsyncVerificationService) readUpload(ctx context.Context, request *domain.VerificationRequest) ([]byte, error) {
+	content, err := s.blobs.Get(ctx, request.ImageKey)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+	image, err := io.ReadAll(content)
+	if err != nil {
+		return nil, fmt.Errorf("read upload: %w", err)
+	}
+	return image, nil
+}
+
+// discardUpload removes the upload of a finished request; the selfie is kept
+// only through selfie storage.
+func (s *AsyncVerificationService) discardUpload(ctx context.Context, request *domain.VerificationRequest) {
+	if err := s.blobs.Delete(ctx, request.ImageKey); err != nil {
+		log.Printf("discard verification upload %s: %v", request.ImageKey, err)
+	}
+}
+
+// verificationErrorCode names the refusals a retry cannot change, using the
+// codes of the synchronous endpoint, and returns "" for other errors.
+func verificationErrorCode(err error) string {
+	var verr *ValidationError
+	switch {
+	case errors.As(err, &verr):
+		return "VALIDATION_FAILED"
+	case errors.Is(err, ErrParticipantNotFound):
+		return "PARTICIPANT_NOT_FOUND"
+	case errors.Is(err, ErrParticipantSuspended):
+		return "PARTICIPANT_SUSPENDED"
+	case errors.Is(err, ErrParticipantBlocked):
+		return "PARTICIPANT_BLOCKED"
+	case errors.Is(err, ErrConsentRequired):
+		return "CONSENT_REQUIRED"
+	case errors.Is(err, ErrAttemptLimitReached):
+		return "ATTEMPT_LIMIT_REACHED"
+	case errors.Is(err, ErrVerificationLocked):
+		return "VERIFICATION_LOCKED"
+	case errors.Is(err, ErrInvalidVerificationSession):
+		return "SESSION_INVALID"
+	case errors.Is(err, ErrUnsupportedImageFormat):
+		return "UNSUPPORTED_IMAGE_FORMAT"
+	case errors.Is(err, imaging.ErrUndecodable):
+		return "INVALID_IMAGE"
+	default:
+		return ""
+	}
+}
+
+// verificationRequestData describes a finished request for verification.request_completed events.
+func verificationRequestData(request *domain.VerificationRequest) map[string]interface{} {
+	return map[string]interface{}{
+		"verification_id":     request.ID,
+		"participant_id":      request.ParticipantID,
+		"status":              request.Status,
+		"certificate_id":      request.CertificateID,
+		"verification_status": request.VerificationStatus,
+		"similarity":          request.Similarity,
+		"distance":            request.Distance,
+		"error_code":          request.ErrorCode,
+		"error":               request.Error,
+		"completed_at":        request.CompletedAt,
+	}
+}
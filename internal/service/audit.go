@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+)
+
+// systemActor attributes audit entries produced by background processes.
+const systemActor = "system"
+
+// recordAudit appends an audit entry describing a change to an entity.
+func recordAudit(ctx context.Context, audit repository.AuditLogRepository, actor, action, entityType, entityID string, details map[string]interface{}) error {
+	if actor == "" {
+		actor = systemActor
+	}
+	payload, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
+	return audit.Create(ctx, &domain.AuditLog{
+		ID:         uuid.NewString(),
+		Actor:      actor,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Details:    string(payload),
+		CreatedAt:  time.Now().UTC(),
+	})
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+)
+
+// Audit vocabulary for the branch registry.
+const (
+	auditEntityBranch       = "branch"
+	auditActionBranchCreate = "branch.create"
+	auditActionBranchUpdate = "branch.update"
+	auditActionBranchDelete = "branch.delete"
+)
+
+// maxBranchRadiusMeters bounds a geofence to a city-sized service area.
+const maxBranchRadiusMeters = 100000
+
+var (
+	// ErrBranchNotFound indicates the requested branch does not exist.
+	ErrBranchNotFound = errors.New("branch not found")
+	// ErrBranchConflict indicates another branch already uses the code.
+	ErrBranchConflict = errors.New("branch code already in use")
+	// ErrBranchInUse indicates active kiosks or officers still belong to the branch.
+	ErrBranchInUse = errors.New("branch has active kiosks or officers")
+)
+
+// BranchService maintains the branch registry whose geofences kiosk and
+// officer submissions are checked against.
+type BranchService struct {
+	branches repository.BranchRepository
+	audit    repository.AuditLogRepository
+	tx       repository.Transactor
+}
+
+// NewBranchService wires dependencies for the branch registry.
+func NewBranchService(branches repository.BranchRepository, audit repository.AuditLogRepository, tx repository.Transactor) *BranchService {
+	return &BranchService{branches: branches, audit: audit, tx: tx}
+}
+
+// BranchInput registers a branch and its geofence.
+type BranchInput struct {
+	Code         string   `json:"code"`
+	Name         string   `json:"name"`
+	Address      string   `json:"address"`
+	Latitude     *float64 `json:"latitude"`
+	Longitude    *float64 `json:"longitude"`
+	RadiusMeters float64  `json:"radius_meters"`
+}
+
+// UpdateBranchInput changes a branch; nil fields are left untouched. The
+// code cannot change, as kiosks, officers and certificates carry it.
+type UpdateBranchInput struct {
+	Name         *string  `json:"name"`
+	Address      *string  `json:"address"`
+	Latitude     *float64 `json:"latitude"`
+	Longitude    *float64 `json:"longitude"`
+	RadiusMeters *float64 `json:"radius_meters"`
+}
+
+// Geofence is where a kiosk or officer submission was made relative to its branch.
+type Geofence struct {
+	// Distance is how far from the branch's position the submission was
+	// made, nil without a position or a registered branch.
+	Distance *float64
+	// Finding says why the submission is flagged; empty inside the geofence.
+	Finding string
+}
+
+// Create registers a branch.
+func (s *BranchService) Create(ctx context.Context, actor string, input BranchInput) (*domain.Branch, error) {
+	verr := &ValidationError{}
+	code := strings.TrimSpace(input.Code)
+	switch {
+	case code == "":
+		verr.add("code", "is required")
+	case len(code) > 64:
+		verr.add("code", "must be at most 64 characters")
+	}
+	if input.Latitude == nil {
+		verr.add("latitude", "is required")
+	}
+	if input.Longitude == nil {
+		verr.add("longitude", "is required")
+	}
+	now := time.Now().UTC()
+	branch := &domain.Branch{
+		ID:           uuid.NewString(),
+		Code:         code,
+		Name:         strings.TrimSpace(input.Name),
+		Address:      strings.TrimSpace(input.Address),
+		RadiusMeters: input.RadiusMeters,
+		CreatedBy:    actor,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if input.Latitude != nil && input.Longitude != nil {
+		branch.Latitude, branch.Longitude = *input.Latitude, *input.Longitude
+	}
+	validateBranch(branch, verr)
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.branches.GetByCode(ctx, branch.Code)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrBranchConflict
+	}
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.branches.Create(ctx, branch); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionBranchCreate, auditEntityBranch, branch.ID, map[string]interface{}{
+			"branch": branch,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return branch, nil
+}
+
+// Update changes the provided fields of a branch.
+func (s *BranchService) Update(ctx context.Context, actor, id string, input UpdateBranchInput) (*domain.Branch, error) {
+	branch, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *branch
+
+	if input.Name != nil {
+		branch.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Address != nil {
+		branch.Address = strings.TrimSpace(*input.Address)
+	}
+	if input.Latitude != nil {
+		branch.Latitude = *input.Latitude
+	}
+	if input.Longitude != nil {
+		branch.Longitude = *input.Longitude
+	}
+	if input.RadiusMeters != nil {
+		branch.RadiusMeters = *input.RadiusMeters
+	}
+	verr := &ValidationError{}
+	validateBranch(branch, verr)
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+	branch.UpdatedAt = time.Now().UTC()
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.branches.Update(ctx, branch); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionBranchUpdate, auditEntityBranch, branch.ID, map[string]interface{}{
+			"before": before,
+			"after":  branch,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return branch, nil
+}
+
+// Delete removes a branch no active kiosk or officer belongs to.
+func (s *BranchService) Delete(ctx context.Context, actor, id string) error {
+	branch, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	inUse, err := s.branches.InUse(ctx, branch.Code)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return ErrBranchInUse
+	}
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.branches.Delete(ctx, branch.ID); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionBranchDelete, auditEntityBranch, branch.ID, map[string]interface{}{
+			"code": branch.Code,
+			"name": branch.Name,
+		})
+	})
+}
+
+// Get returns a single branch.
+func (s *BranchService) Get(ctx context.Context, id string) (*domain.Branch, error) {
+	branch, err := s.branches.GetByID(ctx, strings.TrimSpace(id))
+	if err != nil {
+		return nil, err
+	}
+	if branch == nil {
+		return nil, ErrBranchNotFound
+	}
+	return branch, nil
+}
+
+// List returns every branch ordered by code.
+func (s *BranchService) List(ctx context.Context) ([]domain.Branch, error) {
+	return s.branches.List(ctx)
+}
+
+func validateBranch(branch *domain.Branch, verr *ValidationError) {
+	switch {
+	case branch.Name == "":
+		verr.add("name", "is required")
+	case len(branch.Name) > 150:
+		verr.add("name", "must be at most 150 characters")
+	}
+	if len(branch.Address) > 500 {
+		verr.add("address", "must be at most 500 characters")
+	}
+	if branch.Latitude < -90 || branch.Latitude > 90 {
+		verr.add("latitude", "must be between -90 and 90")
+	}
+	if branch.Longitude < -180 || branch.Longitude > 180 {
+		verr.add("longitude", "must be between -180 and 180")
+	}
+	if branch.RadiusMeters <= 0 || branch.RadiusMeters > maxBranchRadiusMeters {
+		verr.add("radius_meters", "must be greater than 0 and at most 100000")
+	}
+}
+
+// registeredBranch checks that a kiosk or officer is assigned to a branch of
+// the registry.
+func registeredBranch(ctx context.Context, branches repository.BranchRepository, code string, verr *ValidationError) error {
+	if code == "" {
+		return nil
+	}
+	branch, err := branches.GetByCode(ctx, code)
+	if err != nil {
+		return err
+	}
+	if branch == nil {
+		verr.add("branch_id", "is not a registered branch")
+	}
+	return nil
+}
+
+// checkGeofence places a submission attributed to a branch against the
+// branch's geofence. Submissions without a position, for an unregistered
+// branch or beyond the radius are flagged.
+func checkGeofence(ctx context.Context, branches repository.BranchRepository, code string, latitude, longitude *float64) (*Geofence, error) {
+	branch, err := branches.GetByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case branch == nil:
+		return &Geofence{Finding: fmt.Sprintf("branch %s is not registered", code)}, nil
+	case latitude == nil || longitude == nil:
+		return &Geofence{Finding: "no position submitted"}, nil
+	}
+	distance := haversineMeters(branch.Latitude, branch.Longitude, *latitude, *longitude)
+	geofence := &Geofence{Distance: &distance}
+	if distance > branch.RadiusMeters {
+		geofence.Finding = fmt.Sprintf("submitted %.0f m from branch %s, beyond its %.0f m radius", distance, branch.Code, branch.RadiusMeters)
+	}
+	return geofence, nil
+}
+
+// apply stores the geofence check on the certificate.
+func (g *Geofence) apply(record *domain.LifeCertificate) {
+	if g == nil {
+		return
+	}
+	record.GeofenceDistance = g.Distance
+	if g.Finding != "" {
+		finding := g.Finding
+		record.OutsideGeofence = true
+		record.GeofenceFinding = &finding
+	}
+}
@@ -0,0 +1,387 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/tenant"
+)
+
+const (
+	bulkManifestName    = "manifest.csv"
+	bulkMaxRows         = 10000
+	bulkMaxImageBytes   = 10 << 20
+	defaultBulkWorkers  = 4
+	bulkEnqueueCapacity = 256
+	// bulkRowLease is how long a claimed row is left to its worker before
+	// another replica may take it over.
+	bulkRowLease = 15 * time.Minute
+)
+
+var (
+	// ErrBulkJobNotFound indicates the requested bulk registration job does not exist.
+	ErrBulkJobNotFound = errors.New("bulk registration job not found")
+)
+
+// BulkRegistrationService registers participants from an uploaded archive in the
+// background using a bounded pool of workers.
+type BulkRegistrationService struct {
+	jobs         repository.BulkRegistrationRepository
+	participants *ParticipantService
+	workers      int
+	queue        chan string
+	wg           sync.WaitGroup
+}
+
+// BulkRegisterInput carries the uploaded ZIP archive.
+type BulkRegisterInput struct {
+	SourceName string
+	Archive    []byte
+}
+
+// BulkRegistrationReport lists the failed rows of a job.
+type BulkRegistrationReport struct {
+	Job      *domain.BulkRegistrationJob  `json:"job"`
+	Failures []domain.BulkRegistrationRow `json:"failures"`
+}
+
+// NewBulkRegistrationService wires dependencies; workers bounds the number of
+// concurrent FR Core registrations.
+func NewBulkRegistrationService(jobs repository.BulkRegistrationRepository, participants *ParticipantService, workers int) *BulkRegistrationService {
+	if workers <= 0 {
+		workers = defaultBulkWorkers
+	}
+	return &BulkRegistrationService{
+		jobs:         jobs,
+		participants: participants,
+		workers:      workers,
+		queue:        make(chan string, bulkEnqueueCapacity),
+	}
+}
+
+// Start launches the worker pool and re-enqueues rows left pending by a previous run.
+// Workers stop when ctx is cancelled; call Wait to block until they have exited.
+func (s *BulkRegistrationService) Start(ctx context.Context) error {
+	for i := 0; i < s.workers; i++ {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case rowID := <-s.queue:
+					s.process(ctx, rowID)
+				}
+			}
+		}()
+	}
+
+	pending, err := s.jobs.ListPendingRowIDs(tenant.System(ctx))
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		log.Printf("[bulk] resuming %d pending registration rows", len(pending))
+		s.enqueue(ctx, pending)
+	}
+	return nil
+}
+
+// Wait blocks until all workers have stopped.
+func (s *BulkRegistrationService) Wait() {
+	s.wg.Wait()
+}
+
+// Submit parses the archive manifest, persists the job and queues its rows.
+// The archive must contain manifest.csv with nik, name and image columns, where
+// image is the path of the selfie inside the archive.
+func (s *BulkRegistrationService) Submit(ctx context.Context, input BulkRegisterInput) (*domain.BulkRegistrationJob, error) {
+	if len(input.Archive) == 0 {
+		return nil, fmt.Errorf("archive is required")
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(input.Archive), int64(len(input.Archive)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid zip archive: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(reader.File))
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		files[path.Clean(file.Name)] = file
+	}
+
+	manifest, ok := files[bulkManifestName]
+	if !ok {
+		return nil, fmt.Errorf("archive must contain %s", bulkManifestName)
+	}
+	records, err := readManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	job := &domain.BulkRegistrationJob{
+		ID:         uuid.NewString(),
+		Status:     domain.BulkRegistrationJobPending,
+		SourceName: input.SourceName,
+		TotalRows:  len(records),
+		CreatedAt:  now,
+	}
+
+	rows := make([]domain.BulkRegistrationRow, 0, len(records))
+	var queued []string
+	for i, record := range records {
+		row := domain.BulkRegistrationRow{
+			ID:        uuid.NewString(),
+			JobID:     job.ID,
+			RowNumber: i + 1,
+			NIK:       record.nik,
+			Name:      record.name,
+			ImageName: record.image,
+			Status:    domain.BulkRegistrationRowPending,
+		}
+
+		if problem := record.validate(); problem != "" {
+			failRow(&row, problem, now)
+		} else if file, ok := files[path.Clean(record.image)]; !ok {
+			failRow(&row, "image not found in archive", now)
+		} else if data, err := readZipFile(file); err != nil {
+			failRow(&row, err.Error(), now)
+		} else {
+			row.ImageData = data
+			queued = append(queued, row.ID)
+		}
+
+		if row.Status == domain.BulkRegistrationRowFailed {
+			job.ProcessedRows++
+			job.FailedRows++
+		}
+		rows = append(rows, row)
+	}
+
+	if job.ProcessedRows == job.TotalRows {
+		job.Status = domain.BulkRegistrationJobCompleted
+		job.FinishedAt = &now
+	}
+
+	if err := s.jobs.CreateJob(ctx, job, rows); err != nil {
+		return nil, err
+	}
+
+	// Enqueue outside the request lifecycle so large uploads return immediately.
+	go s.enqueue(context.Background(), queued)
+
+	return job, nil
+}
+
+// GetJob returns progress for a bulk registration job.
+func (s *BulkRegistrationService) GetJob(ctx context.Context, id string) (*domain.BulkRegistrationJob, error) {
+	job, err := s.jobs.GetJob(ctx, strings.TrimSpace(id))
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, ErrBulkJobNotFound
+	}
+	return job, nil
+}
+
+// FailureReport returns the job with every row that failed to register.
+func (s *BulkRegistrationService) FailureReport(ctx context.Context, id string) (*BulkRegistrationReport, error) {
+	job, err := s.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	failures, err := s.jobs.ListRows(ctx, job.ID, domain.BulkRegistrationRowFailed)
+	if err != nil {
+		return nil, err
+	}
+	return &BulkRegistrationReport{Job: job, Failures: failures}, nil
+}
+
+func (s *BulkRegistrationService) enqueue(ctx context.Context, rowIDs []string) {
+	for _, id := range rowIDs {
+		select {
+		case s.queue <- id:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *BulkRegistrationService) process(ctx context.Context, rowID string) {
+	// The queue holds row IDs of every tenant; the row names its own.
+	row, err := s.jobs.GetRow(tenant.System(ctx), rowID)
+	if err != nil {
+		log.Printf("[bulk] load row %s: %v", rowID, err)
+		return
+	}
+	if row == nil || row.Status != domain.BulkRegistrationRowPending {
+		return
+	}
+	ctx = tenant.WithID(ctx, row.TenantID)
+
+	// Every replica resumes the pending rows at startup; only one registers each.
+	claimed, err := s.jobs.ClaimRow(ctx, row.ID, time.Now().UTC(), bulkRowLease)
+	if err != nil {
+		log.Printf("[bulk] claim row %s: %v", row.ID, err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	if err := s.jobs.MarkJobRunning(ctx, row.JobID, time.Now().UTC()); err != nil {
+		log.Printf("[bulk] mark job %s running: %v", row.JobID, err)
+	}
+
+	out, err := s.participants.Register(ctx, RegisterInput{
+		NIK:       row.NIK,
+		Name:      row.Name,
+		Image:     row.ImageData,
+		ImageName: path.Base(row.ImageName),
+	})
+	if ctx.Err() != nil {
+		// Shutting down: leave the row pending so it is resumed on the next start.
+		if err := s.jobs.ReleaseRow(context.WithoutCancel(ctx), row.ID); err != nil {
+			log.Printf("[bulk] release row %s: %v", row.ID, err)
+		}
+		return
+	}
+
+	now := time.Now().UTC()
+	if err != nil {
+		failRow(row, err.Error(), now)
+	} else {
+		row.Status = domain.BulkRegistrationRowSucceeded
+		row.ParticipantID = &out.ParticipantID
+		row.ProcessedAt = &now
+	}
+
+	if err := s.jobs.CompleteRow(ctx, row); err != nil {
+		log.Printf("[bulk] complete row %s: %v", row.ID, err)
+	}
+}
+
+type manifestRecord struct {
+	nik   string
+	name  string
+	image string
+}
+
+func (r manifestRecord) validate() string {
+	switch {
+	case r.nik == "":
+		return "nik is required"
+	case r.name == "":
+		return "name is required"
+	case r.image == "":
+		return "image is required"
+	}
+	return ""
+}
+
+func readManifest(file *zip.File) ([]manifestRecord, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open manifest: %w", err)
+	}
+	defer rc.Close()
+
+	reader := csv.NewReader(rc)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read manifest header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"nik", "name", "image"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("manifest is missing the %s column", required)
+		}
+	}
+
+	field := func(record []string, name string) string {
+		idx := columns[name]
+		if idx >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[idx])
+	}
+
+	var records []manifestRecord
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read manifest: %w", err)
+		}
+		records = append(records, manifestRecord{
+			nik:   field(record, "nik"),
+			name:  field(record, "name"),
+			image: field(record, "image"),
+		})
+		if len(records) > bulkMaxRows {
+			return nil, fmt.Errorf("manifest exceeds %d rows", bulkMaxRows)
+		}
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("manifest has no rows")
+	}
+	return records, nil
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	if file.UncompressedSize64 > bulkMaxImageBytes {
+		return nil, fmt.Errorf("image exceeds %d bytes", bulkMaxImageBytes)
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open image: %w", err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, bulkMaxImageBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read image: %w", err)
+	}
+	if len(data) > bulkMaxImageBytes {
+		return nil, fmt.Errorf("image exceeds %d bytes", bulkMaxImageBytes)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("image is empty")
+	}
+	return data, nil
+}
+
+func failRow(row *domain.BulkRegistrationRow, message string, at time.Time) {
+	row.Status = domain.BulkRegistrationRowFailed
+	row.Error = &message
+	row.ProcessedAt = &at
+}
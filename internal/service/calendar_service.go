@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+)
+
+// Audit vocabulary for the holiday calendar.
+const (
+	auditEntityHoliday       = "holiday"
+	auditActionHolidayCreate = "holiday.create"
+	auditActionHolidayDelete = "holiday.delete"
+)
+
+// calendarSlackDays is how far past the end of a computation holidays are
+// loaded, enough to cover a run of consecutive days off.
+const calendarSlackDays = 60
+
+var (
+	// ErrHolidayNotFound indicates the requested holiday does not exist.
+	ErrHolidayNotFound = errors.New("holiday not found")
+	// ErrHolidayConflict indicates the region already has a holiday on the date.
+	ErrHolidayConflict = errors.New("holiday already registered for the date")
+)
+
+// CalendarService keeps the national and regional holidays and, with the
+// configured weekend, decides which days are working days. Verification due
+// dates, review SLAs and reminders are counted in working days.
+type CalendarService struct {
+	holidays repository.HolidayRepository
+	audit    repository.AuditLogRepository
+	tx       repository.Transactor
+	weekend  map[time.Weekday]bool
+}
+
+// NewCalendarService wires dependencies for the working calendar.
+func NewCalendarService(holidays repository.HolidayRepository, audit repository.AuditLogRepository, tx repository.Transactor, weekend []time.Weekday) *CalendarService {
+	s := &CalendarService{holidays: holidays, audit: audit, tx: tx, weekend: make(map[time.Weekday]bool)}
+	for _, day := range weekend {
+		s.weekend[day] = true
+	}
+	return s
+}
+
+// HolidayInput adds holidays of a region, or national ones without a region.
+type HolidayInput struct {
+	Region   string       `json:"region"`
+	Holidays []HolidayDay `json:"holidays"`
+}
+
+// HolidayDay is one day off.
+type HolidayDay struct {
+	// Date is a YYYY-MM-DD date.
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+// HolidayListInput filters the holiday list.
+type HolidayListInput struct {
+	// Region lists the region's holidays with the national ones.
+	Region string
+	// From and To are YYYY-MM-DD dates.
+	From string
+	To   string
+}
+
+// Create adds a list of holidays for one region; a date the region already
+// has fails the whole list.
+func (s *CalendarService) Create(ctx context.Context, actor string, input HolidayInput) ([]domain.Holiday, error) {
+	region := strings.TrimSpace(input.Region)
+	verr := &ValidationError{}
+	if len(region) > 100 {
+		verr.add("region", "must be at most 100 characters")
+	}
+	if len(input.Holidays) == 0 {
+		verr.add("holidays", "at least one holiday is required")
+	}
+	now := time.Now().UTC()
+	seen := make(map[time.Time]bool)
+	holidays := make([]domain.Holiday, 0, len(input.Holidays))
+	for i, day := range input.Holidays {
+		field := fmt.Sprintf("holidays[%d]", i)
+		date, err := parseDateParam(field+".date", day.Date)
+		switch {
+		case err != nil:
+			verr.add(field+".date", "must be a YYYY-MM-DD date")
+		case date == nil:
+			verr.add(field+".date", "is required")
+		case seen[*date]:
+			verr.add(field+".date", "is listed twice")
+		}
+		name := strings.TrimSpace(day.Name)
+		switch {
+		case name == "":
+			verr.add(field+".name", "is required")
+		case len(name) > 150:
+			verr.add(field+".name", "must be at most 150 characters")
+		}
+		if date == nil {
+			continue
+		}
+		seen[*date] = true
+		holidays = append(holidays, domain.Holiday{
+			ID:        uuid.NewString(),
+			Region:    region,
+			Date:      *date,
+			Name:      name,
+			CreatedBy: actor,
+			CreatedAt: now,
+		})
+	}
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	for _, holiday := range holidays {
+		existing, err := s.holidays.GetByDate(ctx, region, holiday.Date)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return nil, ErrHolidayConflict
+		}
+	}
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		for i := range holidays {
+			if err := s.holidays.Create(ctx, &holidays[i]); err != nil {
+				return err
+			}
+			if err := recordAudit(ctx, s.audit, actor, auditActionHolidayCreate, auditEntityHoliday, holidays[i].ID, map[string]interface{}{
+				"region": holidays[i].Region,
+				"date":   holidays[i].Date.Format("2006-01-02"),
+				"name":   holidays[i].Name,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return holidays, nil
+}
+
+// List returns holidays by date.
+func (s *CalendarService) List(ctx context.Context, input HolidayListInput) ([]domain.Holiday, error) {
+	verr := &ValidationError{}
+	from, err := parseDateParam("from", input.From)
+	if err != nil {
+		verr.add("from", "must be a YYYY-MM-DD date")
+	}
+	to, err := parseDateParam("to", input.To)
+	if err != nil {
+		verr.add("to", "must be a YYYY-MM-DD date")
+	}
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+	return s.holidays.List(ctx, repository.HolidayFilter{
+		Region: strings.TrimSpace(input.Region),
+		From:   from,
+		To:     to,
+	})
+}
+
+// Delete removes a holiday. Due dates already computed keep their value.
+func (s *CalendarService) Delete(ctx context.Context, actor, id string) error {
+	holiday, err := s.holidays.GetByID(ctx, strings.TrimSpace(id))
+	if err != nil {
+		return err
+	}
+	if holiday == nil {
+		return ErrHolidayNotFound
+	}
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.holidays.Delete(ctx, holiday.ID); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionHolidayDelete, auditEntityHoliday, holiday.ID, map[string]interface{}{
+			"region": holiday.Region,
+			"date":   holiday.Date.Format("2006-01-02"),
+			"name":   holiday.Name,
+		})
+	})
+}
+
+// Load reads the holidays of every region between from and to, plus some
+// slack, for computations over many participants.
+func (s *CalendarService) Load(ctx context.Context, from, to time.Time) (*WorkingCalendar, error) {
+	from, to = dateOf(from), dateOf(to).AddDate(0, 0, calendarSlackDays)
+	holidays, err := s.holidays.List(ctx, repository.HolidayFilter{From: &from, To: &to})
+	if err != nil {
+		return nil, err
+	}
+	calendar := &WorkingCalendar{weekend: s.weekend, holidays: make(map[string]map[time.Time]bool)}
+	for _, holiday := range holidays {
+		region := strings.ToLower(holiday.Region)
+		if calendar.holidays[region] == nil {
+			calendar.holidays[region] = make(map[time.Time]bool)
+		}
+		calendar.holidays[region][dateOf(holiday.Date)] = true
+	}
+	return calendar, nil
+}
+
+// NextWorkingDay returns day when it is a working day in the region, or the first one after it.
+func (s *CalendarService) NextWorkingDay(ctx context.Context, day time.Time, region string) (time.Time, error) {
+	calendar, err := s.Load(ctx, day, day)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return calendar.NextWorkingDay(day, region), nil
+}
+
+// AddWorkingDays moves n working days in the region past day.
+func (s *CalendarService) AddWorkingDays(ctx context.Context, day time.Time, n int, region string) (time.Time, error) {
+	calendar, err := s.Load(ctx, day, day.AddDate(0, 0, 2*n))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return calendar.AddWorkingDays(day, n, region), nil
+}
+
+// AddWorkingTime adds d to start counting only the time on working days in the region.
+func (s *CalendarService) AddWorkingTime(ctx context.Context, start time.Time, d time.Duration, region string) (time.Time, error) {
+	calendar, err := s.Load(ctx, start, start.Add(2*d))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return calendar.AddWorkingTime(start, d, region), nil
+}
+
+// WorkingCalendar is the weekend and the holidays loaded for a date range.
+// Days past the range are only checked against the weekend.
+type WorkingCalendar struct {
+	weekend map[time.Weekday]bool
+	// holidays holds the days off by lower-cased region, "" for national ones.
+	holidays map[string]map[time.Time]bool
+}
+
+// WorkingDay tells whether offices in the region are open on day (UTC).
+func (c *WorkingCalendar) WorkingDay(day time.Time, region string) bool {
+	day = dateOf(day)
+	if c.weekend[day.Weekday()] || c.holidays[""][day] {
+		return false
+	}
+	region = strings.ToLower(strings.TrimSpace(region))
+	return region == "" || !c.holidays[region][day]
+}
+
+// NextWorkingDay returns day when it is a working day, or the first one after it, keeping the time of day.
+func (c *WorkingCalendar) NextWorkingDay(day time.Time, region string) time.Time {
+	for !c.WorkingDay(day, region) {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+// AddWorkingDays moves n working days past day, keeping the time of day.
+func (c *WorkingCalendar) AddWorkingDays(day time.Time, n int, region string) time.Time {
+	for n > 0 {
+		day = day.AddDate(0, 0, 1)
+		if c.WorkingDay(day, region) {
+			n--
+		}
+	}
+	return day
+}
+
+// AddWorkingTime adds d to start counting only the time on working days, so
+// time left over at the end of a working day carries over to the next one.
+func (c *WorkingCalendar) AddWorkingTime(start time.Time, d time.Duration, region string) time.Time {
+	cursor := start.UTC()
+	for {
+		midnight := dateOf(cursor).AddDate(0, 0, 1)
+		if c.WorkingDay(cursor, region) {
+			left := midnight.Sub(cursor)
+			if d <= left {
+				return cursor.Add(d)
+			}
+			d -= left
+		}
+		cursor = midnight
+	}
+}
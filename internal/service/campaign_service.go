@@ -0,0 +1,971 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/events"
+	"life-certificates/internal/repository"
+)
+
+// JobTypeCampaignNotify reminds the participants yet to complete a campaign.
+const JobTypeCampaignNotify = "campaign.notify"
+
+// campaignNotifyBatchSize is how many participants a kickoff loads at a time.
+const campaignNotifyBatchSize = 200
+
+// campaignEscalateBatchSize is how many participants are escalated at a time.
+const campaignEscalateBatchSize = 200
+
+// Audit vocabulary for campaign management.
+const (
+	auditEntityCampaign                = "campaign"
+	auditEntityCampaignExclusion       = "campaign_exclusion"
+	auditActionCampaignCreate          = "campaign.create"
+	auditActionCampaignNotify          = "campaign.notify"
+	auditActionCampaignEscalation      = "campaign.escalation_update"
+	auditActionCampaignExclusionAdd    = "campaign.exclusion_add"
+	auditActionCampaignExclusionRemove = "campaign.exclusion_remove"
+)
+
+var (
+	// ErrCampaignNotFound indicates the requested campaign does not exist.
+	ErrCampaignNotFound = errors.New("campaign not found")
+	// ErrCampaignExclusionNotFound indicates the requested exclusion does not exist.
+	ErrCampaignExclusionNotFound = errors.New("campaign exclusion not found")
+	// ErrCampaignExclusionExists indicates the participant is already on the exclusion list.
+	ErrCampaignExclusionExists = errors.New("participant is already on the exclusion list")
+	// ErrCampaignNotificationNotFound indicates the requested kickoff does not exist for the campaign.
+	ErrCampaignNotificationNotFound = errors.New("campaign notification not found")
+	// ErrCampaignNotificationInProgress signals the campaign's previous kickoff has not finished.
+	ErrCampaignNotificationInProgress = errors.New("campaign notification is already in progress")
+	// ErrNotificationsDisabled indicates no notification channel is enabled.
+	ErrNotificationsDisabled = errors.New("notifications are not enabled")
+)
+
+// CampaignService runs life certificate campaigns: it snapshots the target
+// population when a cycle is created and keeps each participant's status current.
+type CampaignService struct {
+	campaigns     repository.CampaignRepository
+	participants  repository.ParticipantRepository
+	reminders     repository.ReminderRepository
+	audit         repository.AuditLogRepository
+	notifications *NotificationService
+	jobs          *JobService
+	events        events.Publisher
+	tx            repository.Transactor
+	calendar      *CalendarService
+	options       CampaignOptions
+}
+
+// CampaignOptions configures campaign notification kickoffs.
+type CampaignOptions struct {
+	// NotifyPerMinute spreads a kickoff's notifications so at most this many
+	// participants are notified a minute; zero sends them all at once.
+	NotifyPerMinute int
+}
+
+// campaignNotifyJob is the payload of a JobTypeCampaignNotify job.
+type campaignNotifyJob struct {
+	NotificationID string `json:"notification_id"`
+}
+
+// NewCampaignService wires dependencies for campaign management and
+// registers the kickoff job handler.
+func NewCampaignService(campaigns repository.CampaignRepository, participants repository.ParticipantRepository, reminders repository.ReminderRepository, audit repository.AuditLogRepository, notifications *NotificationService, jobs *JobService, publisher events.Publisher, tx repository.Transactor, calendar *CalendarService, options CampaignOptions) *CampaignService {
+	s := &CampaignService{
+		campaigns:     campaigns,
+		participants:  participants,
+		reminders:     reminders,
+		audit:         audit,
+		notifications: notifications,
+		jobs:          jobs,
+		events:        publisher,
+		tx:            tx,
+		calendar:      calendar,
+		options:       options,
+	}
+	jobs.Register(JobTypeCampaignNotify, s.notifyJob)
+	return s
+}
+
+// CampaignTargetingInput holds the rules selecting a campaign's population
+// from the active participants; unset rules match everyone.
+type CampaignTargetingInput struct {
+	Fund *string `json:"fund"`
+	// Province targets one province, Provinces any of several; not both.
+	Province  *string  `json:"province"`
+	Provinces []string `json:"provinces"`
+	// MinAge keeps participants at least this many years old when the campaign starts.
+	MinAge *int `json:"min_age"`
+	// LastVerifiedBefore is a YYYY-MM-DD date keeping participants without a
+	// VALID certificate verified on or after it.
+	LastVerifiedBefore string `json:"last_verified_before"`
+}
+
+// CreateCampaignInput is the payload for a new campaign.
+type CreateCampaignInput struct {
+	Name string `json:"name"`
+	// Kind is ANNUAL (default) or BIRTHDAY_MONTH.
+	Kind domain.CampaignKind `json:"kind"`
+	// StartsAt and DueAt are YYYY-MM-DD dates bounding the window.
+	StartsAt string `json:"starts_at"`
+	DueAt    string `json:"due_at"`
+	CampaignTargetingInput
+	// Escalation is the grace-period policy for participants still pending after their due date.
+	Escalation domain.CampaignEscalation `json:"escalation"`
+}
+
+// CampaignPreviewInput is a campaign's targeting rules to size up.
+type CampaignPreviewInput struct {
+	// StartsAt is the YYYY-MM-DD date the campaign would start, today by default.
+	StartsAt string `json:"starts_at"`
+	CampaignTargetingInput
+}
+
+// CampaignPreview is the population a campaign would target.
+type CampaignPreview struct {
+	// Matched counts the participants the rules match and Excluded those of
+	// them on an exclusion list; Targets is the rest, who would be enrolled.
+	Matched  int64 `json:"matched"`
+	Excluded int64 `json:"excluded"`
+	Targets  int64 `json:"targets"`
+}
+
+// CampaignNotificationProgress is a kickoff with its deliveries so far.
+type CampaignNotificationProgress struct {
+	Notification domain.CampaignNotification `json:"notification"`
+	// Deliveries counts the kickoff's deliveries by status; SKIPPED ones went
+	// to members who opted out or cannot be reached.
+	Deliveries CampaignDeliverySummary `json:"deliveries"`
+}
+
+// CampaignDeliverySummary counts deliveries by status.
+type CampaignDeliverySummary struct {
+	Pending int64 `json:"pending"`
+	Sent    int64 `json:"sent"`
+	Failed  int64 `json:"failed"`
+	Skipped int64 `json:"skipped"`
+}
+
+// CampaignExclusionInput puts a participant on an exclusion list.
+type CampaignExclusionInput struct {
+	ParticipantID string `json:"participant_id"`
+	// List is HOSPITALIZED or ABROAD.
+	List   domain.CampaignExclusionList `json:"list"`
+	Reason string                       `json:"reason"`
+	// Until is an optional YYYY-MM-DD date, the last the exclusion applies on.
+	Until string `json:"until"`
+}
+
+// CampaignExclusionsInput filters and pages the exclusion lists.
+type CampaignExclusionsInput struct {
+	List          string
+	ParticipantID string
+	Page          int
+	PageSize      int
+}
+
+// CampaignExclusionListOutput is a page of exclusions, latest first.
+type CampaignExclusionListOutput struct {
+	Items    []domain.CampaignExclusion `json:"items"`
+	Page     int                        `json:"page"`
+	PageSize int                        `json:"page_size"`
+	Total    int64                      `json:"total"`
+}
+
+// CampaignListOutput is a page of campaigns, latest first.
+type CampaignListOutput struct {
+	Items    []domain.Campaign `json:"items"`
+	Page     int               `json:"page"`
+	PageSize int               `json:"page_size"`
+	Total    int64             `json:"total"`
+}
+
+// CampaignProgress summarises how far a campaign has come.
+type CampaignProgress struct {
+	Campaign  domain.Campaign `json:"campaign"`
+	Total     int64           `json:"total"`
+	Pending   int64           `json:"pending"`
+	Completed int64           `json:"completed"`
+	Overdue   int64           `json:"overdue"`
+	// CompletionRate is completed over total, zero for an empty campaign.
+	CompletionRate float64 `json:"completion_rate"`
+}
+
+// CampaignParticipantsInput filters and pages a campaign's participants.
+type CampaignParticipantsInput struct {
+	Status   string
+	Stage    string
+	Page     int
+	PageSize int
+}
+
+// CampaignParticipantListOutput is a page of campaign participants, earliest due first.
+type CampaignParticipantListOutput struct {
+	Items    []domain.CampaignParticipant `json:"items"`
+	Page     int                          `json:"page"`
+	PageSize int                          `json:"page_size"`
+	Total    int64                        `json:"total"`
+}
+
+// Create validates the window, enrols every active participant matching the
+// targeting rules and not on an exclusion list, and records the campaign.
+func (s *CampaignService) Create(ctx context.Context, actor string, input CreateCampaignInput) (*domain.Campaign, error) {
+	verr := &ValidationError{}
+	name := strings.TrimSpace(input.Name)
+	switch {
+	case name == "":
+		verr.add("name", "is required")
+	case len(name) > 150:
+		verr.add("name", "must be at most 150 characters")
+	}
+	kind := domain.CampaignKind(strings.ToUpper(strings.TrimSpace(string(input.Kind))))
+	switch kind {
+	case "":
+		kind = domain.CampaignKindAnnual
+	case domain.CampaignKindAnnual, domain.CampaignKindBirthdayMonth:
+	default:
+		verr.add("kind", "must be ANNUAL or BIRTHDAY_MONTH")
+	}
+	startsAt, err := parseDateParam("starts_at", input.StartsAt)
+	if err != nil {
+		verr.add("starts_at", "must be a YYYY-MM-DD date")
+	} else if startsAt == nil {
+		verr.add("starts_at", "is required")
+	}
+	dueAt, err := parseDateParam("due_at", input.DueAt)
+	if err != nil {
+		verr.add("due_at", "must be a YYYY-MM-DD date")
+	} else if dueAt == nil {
+		verr.add("due_at", "is required")
+	}
+	if startsAt != nil && dueAt != nil && dueAt.Before(*startsAt) {
+		verr.add("due_at", "must not be before starts_at")
+	}
+	rules := targetingRules(input.CampaignTargetingInput, verr)
+	validateEscalation(input.Escalation, verr)
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+	rules.On = *startsAt
+
+	targets, err := s.campaigns.Targets(ctx, rules)
+	if err != nil {
+		return nil, err
+	}
+	calendar, err := s.calendar.Load(ctx, *startsAt, *dueAt)
+	if err != nil {
+		return nil, err
+	}
+
+	campaign := &domain.Campaign{
+		ID:                 uuid.NewString(),
+		Name:               name,
+		Kind:               kind,
+		StartsAt:           *startsAt,
+		DueAt:              *dueAt,
+		Fund:               rules.Fund,
+		Provinces:          strings.Join(rules.Provinces, ","),
+		MinAge:             rules.MinAge,
+		LastVerifiedBefore: rules.LastVerifiedBefore,
+		Escalation:         input.Escalation,
+		CreatedBy:          actor,
+		CreatedAt:          time.Now().UTC(),
+	}
+	if len(rules.Provinces) == 1 {
+		campaign.Province = &rules.Provinces[0]
+	}
+	participants := make([]domain.CampaignParticipant, 0, len(targets))
+	for _, target := range targets {
+		if target.Excluded {
+			campaign.Excluded++
+			continue
+		}
+		due := campaign.DueAt
+		if kind == domain.CampaignKindBirthdayMonth && target.BirthDate != nil {
+			due = birthdayMonthDue(*target.BirthDate, campaign.StartsAt, campaign.DueAt)
+		}
+		// A deadline on a day the participant's offices are closed moves to the next working day.
+		due = calendar.NextWorkingDay(due, target.Region)
+		participants = append(participants, domain.CampaignParticipant{
+			CampaignID:    campaign.ID,
+			ParticipantID: target.ParticipantID,
+			Status:        domain.CampaignParticipantPending,
+			DueAt:         due,
+		})
+	}
+	campaign.Participants = len(participants)
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.campaigns.Create(ctx, campaign, participants); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionCampaignCreate, auditEntityCampaign, campaign.ID, map[string]interface{}{
+			"name":                 campaign.Name,
+			"kind":                 campaign.Kind,
+			"starts_at":            campaign.StartsAt.Format("2006-01-02"),
+			"due_at":               campaign.DueAt.Format("2006-01-02"),
+			"fund":                 campaign.Fund,
+			"provinces":            rules.Provinces,
+			"min_age":              campaign.MinAge,
+			"last_verified_before": input.LastVerifiedBefore,
+			"participants":         campaign.Participants,
+			"excluded":             campaign.Excluded,
+			"escalation":           campaign.Escalation,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+// Preview sizes the population a campaign with the rules would target,
+// without creating it.
+func (s *CampaignService) Preview(ctx context.Context, input CampaignPreviewInput) (*CampaignPreview, error) {
+	verr := &ValidationError{}
+	startsAt, err := parseDateParam("starts_at", input.StartsAt)
+	if err != nil {
+		verr.add("starts_at", "must be a YYYY-MM-DD date")
+	}
+	rules := targetingRules(input.CampaignTargetingInput, verr)
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+	if startsAt != nil {
+		rules.On = *startsAt
+	} else {
+		now := time.Now().UTC()
+		rules.On = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	count, err := s.campaigns.CountTargets(ctx, rules)
+	if err != nil {
+		return nil, err
+	}
+	return &CampaignPreview{Matched: count.Matched, Excluded: count.Excluded, Targets: count.Matched - count.Excluded}, nil
+}
+
+// AddExclusion puts a participant on an exclusion list, keeping them out of
+// the campaigns created while it is in force.
+func (s *CampaignService) AddExclusion(ctx context.Context, actor string, input CampaignExclusionInput) (*domain.CampaignExclusion, error) {
+	verr := &ValidationError{}
+	participantID := strings.TrimSpace(input.ParticipantID)
+	if participantID == "" {
+		verr.add("participant_id", "is required")
+	}
+	list, ok := exclusionList(string(input.List))
+	if !ok || list == "" {
+		verr.add("list", "must be HOSPITALIZED or ABROAD")
+	}
+	reason := strings.TrimSpace(input.Reason)
+	switch {
+	case reason == "":
+		verr.add("reason", "is required")
+	case len(reason) > 500:
+		verr.add("reason", "must be at most 500 characters")
+	}
+	until, err := parseDateParam("until", input.Until)
+	if err != nil {
+		verr.add("until", "must be a YYYY-MM-DD date")
+	}
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	participant, err := s.participants.GetByID(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return nil, ErrParticipantNotFound
+	}
+	existing, err := s.campaigns.FindExclusion(ctx, participant.ID, list)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrCampaignExclusionExists
+	}
+
+	exclusion := &domain.CampaignExclusion{
+		ID:            uuid.NewString(),
+		ParticipantID: participant.ID,
+		List:          list,
+		Reason:        reason,
+		Until:         until,
+		CreatedBy:     actor,
+		CreatedAt:     time.Now().UTC(),
+	}
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.campaigns.CreateExclusion(ctx, exclusion); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionCampaignExclusionAdd, auditEntityCampaignExclusion, exclusion.ID, map[string]interface{}{
+			"participant_id": exclusion.ParticipantID,
+			"list":           exclusion.List,
+			"until":          input.Until,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return exclusion, nil
+}
+
+// RemoveExclusion takes a participant off an exclusion list. Campaigns
+// already created keep their population.
+func (s *CampaignService) RemoveExclusion(ctx context.Context, actor, id string) error {
+	exclusion, err := s.campaigns.GetExclusion(ctx, strings.TrimSpace(id))
+	if err != nil {
+		return err
+	}
+	if exclusion == nil {
+		return ErrCampaignExclusionNotFound
+	}
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.campaigns.DeleteExclusion(ctx, exclusion.ID); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionCampaignExclusionRemove, auditEntityCampaignExclusion, exclusion.ID, map[string]interface{}{
+			"participant_id": exclusion.ParticipantID,
+			"list":           exclusion.List,
+		})
+	})
+}
+
+// ListExclusions returns the exclusion lists' entries, latest first.
+func (s *CampaignService) ListExclusions(ctx context.Context, input CampaignExclusionsInput) (*CampaignExclusionListOutput, error) {
+	list, ok := exclusionList(input.List)
+	if !ok {
+		return nil, &ValidationError{Fields: map[string]string{"list": "must be HOSPITALIZED or ABROAD"}}
+	}
+	paging := normalizePagination(input.Page, input.PageSize)
+	items, total, err := s.campaigns.ListExclusions(ctx, repository.CampaignExclusionFilter{
+		List:          list,
+		ParticipantID: strings.TrimSpace(input.ParticipantID),
+	}, paging)
+	if err != nil {
+		return nil, err
+	}
+	return &CampaignExclusionListOutput{Items: items, Page: paging.Page, PageSize: paging.PageSize, Total: total}, nil
+}
+
+// List returns campaigns, latest first.
+func (s *CampaignService) List(ctx context.Context, page, pageSize int) (*CampaignListOutput, error) {
+	paging := normalizePagination(page, pageSize)
+	items, total, err := s.campaigns.List(ctx, paging)
+	if err != nil {
+		return nil, err
+	}
+	return &CampaignListOutput{Items: items, Page: paging.Page, PageSize: paging.PageSize, Total: total}, nil
+}
+
+// Progress refreshes the campaign's participant statuses and returns the counts.
+func (s *CampaignService) Progress(ctx context.Context, id string) (*CampaignProgress, error) {
+	campaign, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.refresh(ctx, campaign, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+
+	counts, err := s.campaigns.CountByStatus(ctx, campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+	progress := &CampaignProgress{
+		Campaign:  *campaign,
+		Pending:   counts[domain.CampaignParticipantPending],
+		Completed: counts[domain.CampaignParticipantCompleted],
+		Overdue:   counts[domain.CampaignParticipantOverdue],
+	}
+	progress.Total = progress.Pending + progress.Completed + progress.Overdue
+	if progress.Total > 0 {
+		progress.CompletionRate = float64(progress.Completed) / float64(progress.Total)
+	}
+	return progress, nil
+}
+
+// Participants lists the campaign's participants, optionally by status.
+func (s *CampaignService) Participants(ctx context.Context, id string, input CampaignParticipantsInput) (*CampaignParticipantListOutput, error) {
+	status := domain.CampaignParticipantStatus(strings.ToUpper(strings.TrimSpace(input.Status)))
+	switch status {
+	case "", domain.CampaignParticipantPending, domain.CampaignParticipantCompleted, domain.CampaignParticipantOverdue:
+	default:
+		return nil, &ValidationError{Fields: map[string]string{"status": "must be PENDING, COMPLETED or OVERDUE"}}
+	}
+	stage := domain.CampaignEscalationStage(strings.ToUpper(strings.TrimSpace(input.Stage)))
+	switch stage {
+	case "", domain.CampaignStageReminder, domain.CampaignStageWarning, domain.CampaignStageHoldRecommended:
+	default:
+		return nil, &ValidationError{Fields: map[string]string{"stage": "must be REMINDER, WARNING or HOLD_RECOMMENDED"}}
+	}
+
+	campaign, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.refresh(ctx, campaign, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+
+	paging := normalizePagination(input.Page, input.PageSize)
+	items, total, err := s.campaigns.ListParticipants(ctx, campaign.ID, status, stage, paging)
+	if err != nil {
+		return nil, err
+	}
+	return &CampaignParticipantListOutput{Items: items, Page: paging.Page, PageSize: paging.PageSize, Total: total}, nil
+}
+
+// UpdateEscalation replaces the campaign's grace-period policy. Participants
+// keep the stages they reached; later stages follow the new policy.
+func (s *CampaignService) UpdateEscalation(ctx context.Context, actor, id string, escalation domain.CampaignEscalation) (*domain.Campaign, error) {
+	verr := &ValidationError{}
+	validateEscalation(escalation, verr)
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+	campaign, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	before := campaign.Escalation
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.campaigns.UpdateEscalation(ctx, campaign.ID, escalation); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionCampaignEscalation, auditEntityCampaign, campaign.ID, map[string]interface{}{
+			"before": before,
+			"after":  escalation,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	campaign.Escalation = escalation
+	return campaign, nil
+}
+
+// Notify starts a kickoff reminding every participant yet to complete the
+// campaign on their preferred channels, queued in the background and spread
+// out at NotifyPerMinute. A campaign has one kickoff in progress at a time;
+// retrying a cancelled kickoff's job resumes it.
+func (s *CampaignService) Notify(ctx context.Context, actor, id string) (*domain.CampaignNotification, error) {
+	if !s.notifications.Enabled() {
+		return nil, ErrNotificationsDisabled
+	}
+	campaign, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := s.campaigns.ListNotifications(ctx, campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, kickoff := range previous {
+		if kickoff.Status != domain.CampaignNotificationQueued && kickoff.Status != domain.CampaignNotificationRunning {
+			continue
+		}
+		// A kickoff whose job was cancelled no longer holds the campaign.
+		job, err := s.jobs.Get(ctx, kickoff.JobID)
+		if err != nil && !errors.Is(err, ErrJobNotFound) {
+			return nil, err
+		}
+		if job != nil && (job.Status == domain.JobStatusQueued || job.Status == domain.JobStatusRunning) {
+			return nil, ErrCampaignNotificationInProgress
+		}
+	}
+
+	kickoff := &domain.CampaignNotification{
+		ID:         uuid.NewString(),
+		CampaignID: campaign.ID,
+		Status:     domain.CampaignNotificationQueued,
+		CreatedBy:  actor,
+		CreatedAt:  time.Now().UTC(),
+	}
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		job, err := s.jobs.Enqueue(ctx, actor, JobTypeCampaignNotify, campaignNotifyJob{NotificationID: kickoff.ID})
+		if err != nil {
+			return err
+		}
+		kickoff.JobID = job.ID
+		if err := s.campaigns.CreateNotification(ctx, kickoff); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionCampaignNotify, auditEntityCampaign, campaign.ID, map[string]interface{}{
+			"notification_id": kickoff.ID,
+			"job_id":          kickoff.JobID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return kickoff, nil
+}
+
+// Notifications lists the campaign's kickoffs, latest first.
+func (s *CampaignService) Notifications(ctx context.Context, id string) ([]domain.CampaignNotification, error) {
+	campaign, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.campaigns.ListNotifications(ctx, campaign.ID)
+}
+
+// NotificationProgress returns a kickoff with its deliveries by status.
+func (s *CampaignService) NotificationProgress(ctx context.Context, campaignID, notificationID string) (*CampaignNotificationProgress, error) {
+	kickoff, err := s.campaigns.GetNotification(ctx, strings.TrimSpace(notificationID))
+	if err != nil {
+		return nil, err
+	}
+	if kickoff == nil || kickoff.CampaignID != strings.TrimSpace(campaignID) {
+		return nil, ErrCampaignNotificationNotFound
+	}
+	counts, err := s.notifications.BatchSummary(ctx, kickoff.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &CampaignNotificationProgress{
+		Notification: *kickoff,
+		Deliveries: CampaignDeliverySummary{
+			Pending: counts[domain.NotificationPending],
+			Sent:    counts[domain.NotificationSent],
+			Failed:  counts[domain.NotificationFailed],
+			Skipped: counts[domain.NotificationSkipped],
+		},
+	}, nil
+}
+
+// notifyJob runs a kickoff. Each participant's reminder is recorded with the
+// kickoff's progress, so a failed attempt resumes after the last one reminded.
+func (s *CampaignService) notifyJob(ctx context.Context, payload json.RawMessage) (interface{}, error) {
+	var input campaignNotifyJob
+	if err := json.Unmarshal(payload, &input); err != nil {
+		return nil, fmt.Errorf("decode campaign notify job: %w", err)
+	}
+	kickoff, err := s.campaigns.GetNotification(ctx, input.NotificationID)
+	if err != nil {
+		return nil, err
+	}
+	if kickoff == nil || kickoff.Status == domain.CampaignNotificationCompleted || kickoff.Status == domain.CampaignNotificationFailed {
+		return nil, nil
+	}
+
+	err = s.remindAll(ctx, kickoff)
+	if err != nil {
+		if finalJobAttempt(ctx) {
+			// The outcome is recorded even if the attempt timed out.
+			finished := time.Now().UTC()
+			message := err.Error()
+			kickoff.Status = domain.CampaignNotificationFailed
+			kickoff.Error = &message
+			kickoff.FinishedAt = &finished
+			if updateErr := s.campaigns.UpdateNotification(context.WithoutCancel(ctx), kickoff); updateErr != nil {
+				return nil, updateErr
+			}
+		}
+		return nil, err
+	}
+
+	finished := time.Now().UTC()
+	kickoff.Status = domain.CampaignNotificationCompleted
+	kickoff.Error = nil
+	kickoff.FinishedAt = &finished
+	if err := s.campaigns.UpdateNotification(ctx, kickoff); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"notification_id": kickoff.ID,
+		"processed":       kickoff.Processed,
+		"queued":          kickoff.Queued,
+	}, nil
+}
+
+// remindAll records a reminder for every participant yet to complete the
+// kickoff's campaign and queues its notifications, the n-th participant's
+// no earlier than n intervals after the kickoff started.
+func (s *CampaignService) remindAll(ctx context.Context, kickoff *domain.CampaignNotification) error {
+	campaign, err := s.get(ctx, kickoff.CampaignID)
+	if err != nil {
+		return err
+	}
+	if kickoff.StartedAt == nil {
+		now := time.Now().UTC()
+		if err := s.refresh(ctx, campaign, now); err != nil {
+			return err
+		}
+		counts, err := s.campaigns.CountByStatus(ctx, campaign.ID)
+		if err != nil {
+			return err
+		}
+		kickoff.Status = domain.CampaignNotificationRunning
+		kickoff.Targets = int(counts[domain.CampaignParticipantPending] + counts[domain.CampaignParticipantOverdue])
+		kickoff.StartedAt = &now
+		if err := s.campaigns.UpdateNotification(ctx, kickoff); err != nil {
+			return err
+		}
+	}
+
+	var interval time.Duration
+	if s.options.NotifyPerMinute > 0 {
+		interval = time.Minute / time.Duration(s.options.NotifyPerMinute)
+	}
+	for {
+		participants, err := s.campaigns.UnfinishedParticipants(ctx, campaign.ID, kickoff.Cursor, campaignNotifyBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, participant := range participants {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			now := time.Now().UTC()
+			reminder := &domain.Reminder{
+				ID:            uuid.NewString(),
+				ParticipantID: participant.ParticipantID,
+				Kind:          domain.ReminderKindCampaign,
+				Reference:     campaign.ID,
+				DueAt:         participant.DueAt,
+				CreatedAt:     now,
+			}
+			notBefore := kickoff.StartedAt.Add(time.Duration(kickoff.Processed) * interval)
+			if notBefore.Before(now) {
+				notBefore = now
+			}
+			next := *kickoff
+			err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+				if err := s.reminders.Create(ctx, reminder); err != nil {
+					return err
+				}
+				queued, err := s.notifications.Remind(ctx, kickoff.ID, reminder, notBefore)
+				if err != nil {
+					return err
+				}
+				next.Processed++
+				next.Queued += queued
+				next.Cursor = participant.ParticipantID
+				return s.campaigns.UpdateNotification(ctx, &next)
+			})
+			if err != nil {
+				return err
+			}
+			*kickoff = next
+		}
+		if len(participants) < campaignNotifyBatchSize {
+			return nil
+		}
+	}
+}
+
+// RefreshAll refreshes every campaign's participant statuses.
+func (s *CampaignService) RefreshAll(ctx context.Context) error {
+	now := time.Now().UTC()
+	ids, err := s.campaigns.ListIDs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		campaign, err := s.campaigns.GetByID(ctx, id)
+		if err != nil || campaign == nil {
+			continue
+		}
+		if err := s.refresh(ctx, campaign, now); err != nil {
+			log.Printf("campaign refresh %s: %v", id, err)
+			continue
+		}
+		if campaign.Escalation.Enabled() {
+			if err := s.escalate(ctx, campaign, now); err != nil {
+				log.Printf("campaign escalation %s: %v", id, err)
+			}
+		}
+	}
+	return nil
+}
+
+// escalate moves every overdue participant to the latest stage of the
+// campaign's policy they have reached, publishing a participant.escalated
+// event for each. A participant who reached several stages since the last
+// run goes straight to the latest.
+func (s *CampaignService) escalate(ctx context.Context, campaign *domain.Campaign, now time.Time) error {
+	today := dateOf(now)
+	stages := domain.CampaignEscalationStages
+	for i := len(stages) - 1; i >= 0; i-- {
+		days, ok := campaign.Escalation.Days(stages[i])
+		if !ok {
+			continue
+		}
+		from := append([]domain.CampaignEscalationStage{""}, stages[:i]...)
+		for {
+			participants, err := s.campaigns.Escalatable(ctx, campaign.ID, from, today.AddDate(0, 0, -days), campaignEscalateBatchSize)
+			if err != nil {
+				return err
+			}
+			for _, participant := range participants {
+				if err := s.escalateParticipant(ctx, campaign, participant, stages[i], now); err != nil {
+					return err
+				}
+			}
+			if len(participants) < campaignEscalateBatchSize {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func (s *CampaignService) escalateParticipant(ctx context.Context, campaign *domain.Campaign, participant domain.CampaignParticipant, stage domain.CampaignEscalationStage, now time.Time) error {
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		ok, err := s.campaigns.Escalate(ctx, campaign.ID, participant.ParticipantID, participant.Stage, stage, now)
+		if err != nil || !ok {
+			return err
+		}
+		return publishEvent(ctx, s.events, events.TypeParticipantEscalated, map[string]interface{}{
+			"participant_id": participant.ParticipantID,
+			"campaign_id":    campaign.ID,
+			"stage":          string(stage),
+			"previous_stage": string(participant.Stage),
+			"due_at":         participant.DueAt,
+			"days_overdue":   int(dateOf(now).Sub(dateOf(participant.DueAt)).Hours() / 24),
+			"overdue":        true,
+		})
+	})
+}
+
+// refresh completes participants verified since the campaign started, then
+// flags those still pending after their due date.
+func (s *CampaignService) refresh(ctx context.Context, campaign *domain.Campaign, now time.Time) error {
+	if _, err := s.campaigns.MarkCompleted(ctx, campaign.ID, campaign.StartsAt); err != nil {
+		return err
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	_, err := s.campaigns.MarkOverdue(ctx, campaign.ID, today)
+	return err
+}
+
+func (s *CampaignService) get(ctx context.Context, id string) (*domain.Campaign, error) {
+	campaign, err := s.campaigns.GetByID(ctx, strings.TrimSpace(id))
+	if err != nil {
+		return nil, err
+	}
+	if campaign == nil {
+		return nil, ErrCampaignNotFound
+	}
+	return campaign, nil
+}
+
+// targetingRules validates a campaign's targeting rules into verr, leaving
+// the date they apply on to the caller.
+func targetingRules(input CampaignTargetingInput, verr *ValidationError) repository.CampaignRules {
+	var rules repository.CampaignRules
+	rules.Fund = optionalString(input.Fund)
+	if rules.Fund != nil && len(*rules.Fund) > fundMaxLen {
+		verr.add("fund", "must be at most 64 characters")
+	}
+
+	provinces := input.Provinces
+	if province := optionalString(input.Province); province != nil {
+		if len(provinces) > 0 {
+			verr.add("province", "must not be combined with provinces")
+		}
+		provinces = []string{*province}
+	}
+	seen := make(map[string]bool, len(provinces))
+	for _, province := range provinces {
+		province = strings.TrimSpace(province)
+		switch {
+		case province == "":
+			verr.add("provinces", "must not contain blank entries")
+		case len(province) > 100:
+			verr.add("provinces", "entries must be at most 100 characters")
+		case strings.Contains(province, ","):
+			verr.add("provinces", "entries must not contain commas")
+		case !seen[strings.ToLower(province)]:
+			seen[strings.ToLower(province)] = true
+			rules.Provinces = append(rules.Provinces, province)
+		}
+	}
+
+	if input.MinAge != nil {
+		if *input.MinAge < 0 || *input.MinAge > 150 {
+			verr.add("min_age", "must be between 0 and 150")
+		}
+		rules.MinAge = input.MinAge
+	}
+	lastVerifiedBefore, err := parseDateParam("last_verified_before", input.LastVerifiedBefore)
+	if err != nil {
+		verr.add("last_verified_before", "must be a YYYY-MM-DD date")
+	}
+	rules.LastVerifiedBefore = lastVerifiedBefore
+	return rules
+}
+
+// validateEscalation checks a grace-period policy's stages are within a
+// year of the due date and come in order.
+func validateEscalation(escalation domain.CampaignEscalation, verr *ValidationError) {
+	fields := map[domain.CampaignEscalationStage]string{
+		domain.CampaignStageReminder:        "escalation.reminder_days",
+		domain.CampaignStageWarning:         "escalation.warning_days",
+		domain.CampaignStageHoldRecommended: "escalation.hold_days",
+	}
+	previous := -1
+	for _, stage := range domain.CampaignEscalationStages {
+		days, ok := escalation.Days(stage)
+		if !ok {
+			continue
+		}
+		switch {
+		case days < 0 || days > 366:
+			verr.add(fields[stage], "must be between 0 and 366")
+		case days <= previous:
+			verr.add(fields[stage], "must be after the earlier stages")
+		}
+		previous = days
+	}
+}
+
+// exclusionList upper-cases an exclusion list name; blank is allowed.
+func exclusionList(raw string) (domain.CampaignExclusionList, bool) {
+	list := domain.CampaignExclusionList(strings.ToUpper(strings.TrimSpace(raw)))
+	switch list {
+	case "", domain.CampaignExclusionHospitalized, domain.CampaignExclusionAbroad:
+		return list, true
+	}
+	return list, false
+}
+
+// birthdayMonthDue is the last day of the first birthday month ending inside
+// the window, or the window's due date when none does.
+func birthdayMonthDue(birthDate, startsAt, dueAt time.Time) time.Time {
+	for year := startsAt.Year(); year <= dueAt.Year(); year++ {
+		monthEnd := time.Date(year, birthDate.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+		if !monthEnd.Before(startsAt) && !monthEnd.After(dueAt) {
+			return monthEnd
+		}
+	}
+	return dueAt
+}
+
+// linkCampaign soft-links a certificate to the participant's earliest-due
+// unfinished campaign, the one the attempt counts towards, if any.
+func linkCampaign(ctx context.Context, campaigns repository.CampaignRepository, record *domain.LifeCertificate) error {
+	due, err := campaigns.NextDueForParticipant(ctx, record.ParticipantID, dateOf(record.VerifiedAt))
+	if err != nil {
+		return err
+	}
+	if due != nil {
+		record.CampaignID = &due.CampaignID
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"life-certificates/internal/imaging"
+)
+
+const earthRadiusMeters = 6371000
+
+// CaptureCheck bounds when and where a verification selfie may have been
+// taken, according to its EXIF metadata.
+type CaptureCheck struct {
+	Enabled bool
+	// MaxAge is how long before submission the photo may have been taken.
+	MaxAge time.Duration
+	// MaxDistanceMeters is how far from the declared position the photo may have been taken.
+	MaxDistanceMeters float64
+	// Location reads EXIF capture times that carry no UTC offset.
+	Location *time.Location
+}
+
+// findings lists why a selfie's metadata does not fit the submission made at
+// now from the declared position. Missing metadata is not a finding, as many
+// apps strip EXIF before uploading.
+func (c CaptureCheck) findings(meta imaging.Metadata, now time.Time, latitude, longitude *float64) []string {
+	if !c.Enabled {
+		return nil
+	}
+	var findings []string
+	if meta.TakenAt != nil {
+		switch age := now.Sub(*meta.TakenAt); {
+		case age > c.MaxAge:
+			findings = append(findings, fmt.Sprintf("photo taken %s before submission", age.Round(time.Minute)))
+		case age < -c.MaxAge:
+			// A capture time well after submission means the camera clock or the metadata was tampered with.
+			findings = append(findings, fmt.Sprintf("photo capture time is %s after submission", (-age).Round(time.Minute)))
+		}
+	}
+	if meta.Latitude != nil && latitude != nil && longitude != nil {
+		if distance := haversineMeters(*meta.Latitude, *meta.Longitude, *latitude, *longitude); distance > c.MaxDistanceMeters {
+			findings = append(findings, fmt.Sprintf("photo taken %.0f m from the declared location", distance))
+		}
+	}
+	return findings
+}
+
+// haversineMeters is the great-circle distance between two positions in decimal degrees.
+func haversineMeters(lat1, lon1, lat2, lon2 float64) float64 {
+	radians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+	dLat, dLon := radians(lat2-lat1), radians(lon2-lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(radians(lat1))*math.Cos(radians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(a))
+}
@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"life-certificates/internal/document"
+	"life-certificates/internal/domain"
+	"life-certificates/internal/events"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/signing"
+	"life-certificates/internal/storage"
+)
+
+// JobTypeCertificatePDF generates the PDF certificate of one VALID attempt.
+const JobTypeCertificatePDF = "certificate.pdf"
+
+var (
+	// ErrCertificateDocumentNotIssuable indicates a PDF certificate was requested for an attempt that is not VALID.
+	ErrCertificateDocumentNotIssuable = errors.New("PDF certificates are only issued for VALID certificates")
+	// ErrCertificateSigningDisabled indicates a signed certificate was requested without a signing key.
+	ErrCertificateSigningDisabled = errors.New("certificate signing is not configured")
+)
+
+// CertificatePDFService issues the official PDF certificate of every VALID
+// verification. As an outbox sink it queues a job per verification.completed
+// event that renders the PDF into the blob store; downloads render it on
+// demand when the job has not run yet or the PDF was purged. With a signing
+// key every PDF gets a detached signature and the certificate is also issued
+// as signed JSON.
+type CertificatePDFService struct {
+	certificates repository.LifeCertificateRepository
+	participants repository.ParticipantRepository
+	members      repository.MemberRepository
+	tenants      repository.TenantRepository
+	blobs        storage.BlobStore
+	template     *document.CertificateTemplate
+	// receipts adds the receipt QR code; nil when receipts are disabled.
+	receipts *ReceiptService
+	// signer signs issued certificates; nil when signing is disabled.
+	signer         *signing.Key
+	jobs           *JobService
+	issuer         string
+	validityMonths int
+}
+
+// certificatePDFJob is the payload of a JobTypeCertificatePDF job.
+type certificatePDFJob struct {
+	CertificateID string `json:"certificate_id"`
+}
+
+// NewCertificatePDFService wires dependencies for PDF certificates and
+// registers the generation job handler.
+func NewCertificatePDFService(certificates repository.LifeCertificateRepository, participants repository.ParticipantRepository, members repository.MemberRepository, tenants repository.TenantRepository, blobs storage.BlobStore, template *document.CertificateTemplate, receipts *ReceiptService, signer *signing.Key, jobs *JobService, issuer string, validityMonths int) *CertificatePDFService {
+	s := &CertificatePDFService{
+		certificates:   certificates,
+		participants:   participants,
+		members:        members,
+		tenants:        tenants,
+		blobs:          blobs,
+		template:       template,
+		receipts:       receipts,
+		signer:         signer,
+		jobs:           jobs,
+		issuer:         issuer,
+		validityMonths: validityMonths,
+	}
+	jobs.Register(JobTypeCertificatePDF, s.generateJob)
+	return s
+}
+
+// Publish queues the PDF certificate of a certificate verified as VALID.
+func (s *CertificatePDFService) Publish(ctx context.Context, event events.Event) error {
+	if event.Type != events.TypeVerificationCompleted || event.Data["status"] != string(domain.LifeCertificateStatusValid) {
+		return nil
+	}
+	certificateID, _ := event.Data["certificate_id"].(string)
+	if certificateID == "" {
+		return nil
+	}
+	_, err := s.jobs.Enqueue(ctx, systemActor, JobTypeCertificatePDF, certificatePDFJob{CertificateID: certificateID})
+	return err
+}
+
+// CertificateDocument is a PDF certificate and its detached JWS, empty when
+// signing is disabled.
+type CertificateDocument struct {
+	Content   []byte
+	Signature string
+}
+
+// SignedCertificate is the payload of a signed JSON certificate.
+type SignedCertificate struct {
+	Issuer        string    `json:"issuer"`
+	CertificateID string    `json:"certificate_id"`
+	ParticipantID string    `json:"participant_id"`
+	Name          string    `json:"name"`
+	NIK           string    `json:"nik"`
+	MemberNumber  string    `json:"member_number,omitempty"`
+	Fund          string    `json:"fund,omitempty"`
+	Method        string    `json:"method"`
+	Location      string    `json:"location,omitempty"`
+	Similarity    *float64  `json:"similarity,omitempty"`
+	Distance      *float64  `json:"distance,omitempty"`
+	VerifiedAt    time.Time `json:"verified_at"`
+	ValidUntil    time.Time `json:"valid_until"`
+	IssuedAt      time.Time `json:"issued_at"`
+	ReceiptURL    string    `json:"receipt_url,omitempty"`
+}
+
+// Open returns the PDF certificate of a VALID attempt, generating it first
+// when it is not stored yet. A stored PDF without a signature from the
+// current key, e.g. after the key was rotated, is signed again.
+func (s *CertificatePDFService) Open(ctx context.Context, certificateID string) (*CertificateDocument, error) {
+	record, err := s.validCertificate(ctx, certificateID)
+	if err != nil {
+		return nil, err
+	}
+	if record.DocumentPath == "" {
+		return s.generate(ctx, record)
+	}
+	content, err := s.blobs.Get(ctx, record.DocumentPath)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return s.generate(ctx, record)
+		}
+		return nil, err
+	}
+	defer content.Close()
+	pdf, err := io.ReadAll(content)
+	if err != nil {
+		return nil, fmt.Errorf("read certificate PDF: %w", err)
+	}
+
+	doc := &CertificateDocument{Content: pdf, Signature: record.DocumentSignature}
+	if s.signer == nil {
+		doc.Signature = ""
+	} else if signing.SignatureKeyID(doc.Signature) != s.signer.ID() {
+		if doc.Signature, err = s.signer.SignDetached(ctx, "application/pdf", pdf); err != nil {
+			return nil, err
+		}
+		if err := s.certificates.SetDocument(ctx, record.ID, record.DocumentPath, doc.Signature); err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+// SignedJSON returns the certificate of a VALID attempt as a flattened JWS
+// over a SignedCertificate.
+func (s *CertificatePDFService) SignedJSON(ctx context.Context, certificateID string) (*signing.JWS, error) {
+	if s.signer == nil {
+		return nil, ErrCertificateSigningDisabled
+	}
+	record, err := s.validCertificate(ctx, certificateID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := s.certificateData(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(SignedCertificate{
+		Issuer:        data.Issuer,
+		CertificateID: data.CertificateID,
+		ParticipantID: data.ParticipantID,
+		Name:          data.Name,
+		NIK:           data.NIK,
+		MemberNumber:  data.MemberNumber,
+		Fund:          data.Fund,
+		Method:        data.Method,
+		Location:      data.Location,
+		Similarity:    data.Similarity,
+		Distance:      data.Distance,
+		VerifiedAt:    data.VerifiedAt,
+		ValidUntil:    data.ValidUntil,
+		IssuedAt:      data.IssuedAt,
+		ReceiptURL:    data.ReceiptURL,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.signer.SignJSON(ctx, payload)
+}
+
+func (s *CertificatePDFService) validCertificate(ctx context.Context, certificateID string) (*domain.LifeCertificate, error) {
+	record, err := s.certificates.GetByID(ctx, certificateID)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, ErrCertificateNotFound
+	}
+	if record.Status != domain.LifeCertificateStatusValid {
+		return nil, ErrCertificateDocumentNotIssuable
+	}
+	return record, nil
+}
+
+func (s *CertificatePDFService) generateJob(ctx context.Context, payload json.RawMessage) (interface{}, error) {
+	var input certificatePDFJob
+	if err := json.Unmarshal(payload, &input); err != nil {
+		return nil, fmt.Errorf("decode certificate PDF job: %w", err)
+	}
+	record, err := s.certificates.GetByID(ctx, input.CertificateID)
+	if err != nil {
+		return nil, err
+	}
+	// The certificate may have been overridden or erased since it was queued.
+	if record == nil || record.Status != domain.LifeCertificateStatusValid {
+		return nil, nil
+	}
+	if _, err := s.generate(ctx, record); err != nil {
+		return nil, err
+	}
+	return map[string]string{"certificate_id": record.ID}, nil
+}
+
+// generate renders the PDF of a VALID attempt, signs it and stores it.
+func (s *CertificatePDFService) generate(ctx context.Context, record *domain.LifeCertificate) (*CertificateDocument, error) {
+	data, err := s.certificateData(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+	branding, err := currentBranding(ctx, s.tenants)
+	if err != nil {
+		return nil, err
+	}
+	if data.Logo, err = tenantLogo(ctx, s.blobs, branding); err != nil {
+		return nil, err
+	}
+	if record.SelfiePath != "" {
+		// A purged selfie leaves the certificate without a photo.
+		content, err := s.blobs.Get(ctx, record.SelfiePath)
+		switch {
+		case err == nil:
+			data.Photo, err = io.ReadAll(content)
+			content.Close()
+			if err != nil {
+				return nil, fmt.Errorf("read selfie: %w", err)
+			}
+		case !errors.Is(err, storage.ErrNotFound):
+			return nil, err
+		}
+	}
+
+	pdf, err := s.template.Render(*data)
+	if err != nil {
+		return nil, err
+	}
+	doc := &CertificateDocument{Content: pdf}
+	if s.signer != nil {
+		if doc.Signature, err = s.signer.SignDetached(ctx, "application/pdf", pdf); err != nil {
+			return nil, err
+		}
+	}
+	key := certificateDocumentKey(record.ID)
+	if err := s.blobs.Put(ctx, key, pdf); err != nil {
+		return nil, err
+	}
+	if err := s.certificates.SetDocument(ctx, record.ID, key, doc.Signature); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// certificateData collects what a certificate states, without the photo and
+// logo. The tenant's display name, when set, replaces the configured issuer.
+func (s *CertificatePDFService) certificateData(ctx context.Context, record *domain.LifeCertificate) (*document.CertificateData, error) {
+	participant, err := s.participants.GetByID(ctx, record.ParticipantID)
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return nil, ErrParticipantNotFound
+	}
+	branding, err := currentBranding(ctx, s.tenants)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &document.CertificateData{
+		Issuer:        s.issuer,
+		Footer:        branding.FooterText,
+		CertificateID: record.ID,
+		ParticipantID: participant.ID,
+		Name:          participant.Name,
+		NIK:           participant.NIK,
+		Method:        string(record.Method),
+		Similarity:    record.Similarity,
+		Distance:      record.Distance,
+		VerifiedAt:    record.VerifiedAt,
+		ValidUntil:    record.VerifiedAt.AddDate(0, s.validityMonths, 0),
+		IssuedAt:      time.Now().UTC(),
+	}
+	if branding.DisplayName != "" {
+		data.Issuer = branding.DisplayName
+	}
+	if participant.Fund != nil {
+		data.Fund = *participant.Fund
+	}
+	if record.Location != nil {
+		data.Location = *record.Location
+	}
+	if participant.MemberID != nil {
+		member, err := s.members.GetByID(ctx, *participant.MemberID)
+		if err != nil {
+			return nil, err
+		}
+		if member != nil {
+			data.MemberNumber = member.NomorPeserta
+		}
+	}
+	if s.receipts != nil {
+		receipt, err := s.receipts.Issue(ctx, record.ID)
+		if err != nil {
+			return nil, err
+		}
+		data.ReceiptURL = receipt.URL
+	}
+	return data, nil
+}
+
+func certificateDocumentKey(certificateID string) string {
+	return fmt.Sprintf("certificates/%s/certificate.pdf", certificateID)
+}
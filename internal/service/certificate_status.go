@@ -0,0 +1,32 @@
+package service
+
+import (
+	"time"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+)
+
+// Certificate statuses reported to external systems besides the attempt outcomes.
+const (
+	// certificateStatusExpired marks participants whose latest VALID attempt lapsed.
+	certificateStatusExpired = "EXPIRED"
+	// certificateStatusNone marks participants without any verification attempt.
+	certificateStatusNone = "NONE"
+)
+
+// currentCertificateStatus reports a participant as VALID while their latest
+// VALID attempt is in force and EXPIRED once it lapsed; participants never
+// verified VALID carry their latest outcome, or NONE without any attempt.
+func currentCertificateStatus(row *repository.ParticipantStatusRow, now time.Time) string {
+	switch {
+	case row.ValidUntil != nil && row.ValidUntil.After(now):
+		return string(domain.LifeCertificateStatusValid)
+	case row.LastValidAt != nil:
+		return certificateStatusExpired
+	case row.LatestStatus != nil:
+		return string(*row.LatestStatus)
+	default:
+		return certificateStatusNone
+	}
+}
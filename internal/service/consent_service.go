@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+)
+
+const (
+	auditEntityConsent       = "consent"
+	auditActionConsentRecord = "consent.record"
+
+	maxConsentEvidenceLength = 2000
+)
+
+// ErrConsentRequired rejects registration and verification of a person who has
+// not accepted the active biometric processing terms.
+var ErrConsentRequired = errors.New("consent to the active biometric processing terms is required")
+
+var consentChannels = map[domain.ConsentChannel]bool{
+	domain.ConsentChannelMobile: true,
+	domain.ConsentChannelWeb:    true,
+	domain.ConsentChannelKiosk:  true,
+	domain.ConsentChannelPaper:  true,
+}
+
+// ConsentService records acceptance of the biometric processing terms and
+// checks it before faces are processed.
+type ConsentService struct {
+	consents     repository.ConsentRepository
+	participants repository.ParticipantRepository
+	audit        repository.AuditLogRepository
+	termsVersion string
+}
+
+// NewConsentService wires dependencies for consent capture. An empty
+// termsVersion does not require consent.
+func NewConsentService(consents repository.ConsentRepository, participants repository.ParticipantRepository, audit repository.AuditLogRepository, termsVersion string) *ConsentService {
+	return &ConsentService{consents: consents, participants: participants, audit: audit, termsVersion: strings.TrimSpace(termsVersion)}
+}
+
+// RecordConsentInput is one person's acceptance of the terms.
+type RecordConsentInput struct {
+	NIK string `json:"nik"`
+	// TermsVersion defaults to the active version.
+	TermsVersion string `json:"terms_version"`
+	Channel      string `json:"channel"`
+	Evidence     string `json:"evidence"`
+	// AcceptedAt is an RFC 3339 time, for acceptances given earlier such as on
+	// paper; it defaults to now.
+	AcceptedAt string `json:"accepted_at"`
+}
+
+// ConsentQueryInput selects whose consents to list, by NIK or participant.
+type ConsentQueryInput struct {
+	NIK           string
+	ParticipantID string
+}
+
+// ConsentListOutput lists a person's consents, newest first.
+type ConsentListOutput struct {
+	NIK           string `json:"nik"`
+	ActiveVersion string `json:"active_version"`
+	// Current reports whether the active version was accepted, or none is required.
+	Current bool             `json:"current"`
+	Items   []domain.Consent `json:"items"`
+}
+
+// Record stores an acceptance of the terms and audit-logs who recorded it.
+func (s *ConsentService) Record(ctx context.Context, actor string, input RecordConsentInput) (*domain.Consent, error) {
+	verr := &ValidationError{}
+	now := time.Now().UTC()
+
+	nik := strings.TrimSpace(input.NIK)
+	switch {
+	case nik == "":
+		verr.add("nik", "is required")
+	case len(nik) > 20:
+		verr.add("nik", "must be at most 20 characters")
+	}
+
+	version := strings.TrimSpace(input.TermsVersion)
+	switch {
+	case version == "" && s.termsVersion == "":
+		verr.add("terms_version", "is required")
+	case version == "":
+		version = s.termsVersion
+	case s.termsVersion != "" && version != s.termsVersion:
+		verr.add("terms_version", "must be the active version "+s.termsVersion)
+	case len(version) > 32:
+		verr.add("terms_version", "must be at most 32 characters")
+	}
+
+	channel := domain.ConsentChannel(strings.ToUpper(strings.TrimSpace(input.Channel)))
+	if !consentChannels[channel] {
+		verr.add("channel", "must be MOBILE, WEB, KIOSK or PAPER")
+	}
+
+	evidence := strings.TrimSpace(input.Evidence)
+	switch {
+	case evidence == "":
+		verr.add("evidence", "is required")
+	case len(evidence) > maxConsentEvidenceLength:
+		verr.add("evidence", "must be at most 2000 characters")
+	}
+
+	acceptedAt := now
+	if raw := strings.TrimSpace(input.AcceptedAt); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		switch {
+		case err != nil:
+			verr.add("accepted_at", "must be an RFC 3339 time")
+		case parsed.After(now):
+			verr.add("accepted_at", "must not be in the future")
+		default:
+			acceptedAt = parsed.UTC()
+		}
+	}
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	if actor == "" {
+		actor = systemActor
+	}
+	consent := &domain.Consent{
+		ID:           uuid.NewString(),
+		NIK:          nik,
+		TermsVersion: version,
+		Channel:      channel,
+		Evidence:     evidence,
+		AcceptedAt:   acceptedAt,
+		RecordedBy:   actor,
+		CreatedAt:    now,
+	}
+	if err := s.consents.Create(ctx, consent); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.audit, actor, auditActionConsentRecord, auditEntityConsent, consent.ID, map[string]interface{}{
+		"terms_version": consent.TermsVersion,
+		"channel":       consent.Channel,
+		"accepted_at":   consent.AcceptedAt,
+	}); err != nil {
+		return nil, err
+	}
+	return consent, nil
+}
+
+// List returns the consents given under a NIK, or under a participant's NIK.
+func (s *ConsentService) List(ctx context.Context, input ConsentQueryInput) (*ConsentListOutput, error) {
+	nik := strings.TrimSpace(input.NIK)
+	if participantID := strings.TrimSpace(input.ParticipantID); participantID != "" {
+		participant, err := s.participants.GetByID(ctx, participantID)
+		if err != nil {
+			return nil, err
+		}
+		if participant == nil {
+			return nil, ErrParticipantNotFound
+		}
+		nik = participant.NIK
+	}
+	if nik == "" {
+		return nil, &ValidationError{Fields: map[string]string{"nik": "or participant_id is required"}}
+	}
+
+	items, err := s.consents.ListByNIK(ctx, nik)
+	if err != nil {
+		return nil, err
+	}
+	out := &ConsentListOutput{NIK: nik, ActiveVersion: s.termsVersion, Current: s.termsVersion == "", Items: items}
+	for _, item := range items {
+		if item.TermsVersion == s.termsVersion {
+			out.Current = true
+		}
+	}
+	return out, nil
+}
+
+// Require returns ErrConsentRequired unless the NIK accepted the active terms.
+func (s *ConsentService) Require(ctx context.Context, nik string) error {
+	if s.termsVersion == "" {
+		return nil
+	}
+	consent, err := s.consents.GetLatest(ctx, strings.TrimSpace(nik), s.termsVersion)
+	if err != nil {
+		return err
+	}
+	if consent == nil {
+		return ErrConsentRequired
+	}
+	return nil
+}
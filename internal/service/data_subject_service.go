@@ -0,0 +1,750 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/frcore"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/storage"
+)
+
+const (
+	auditEntityMember       = "member"
+	auditActionMemberExport = "member.data_export"
+	auditActionMemberErase  = "member.erase"
+	// auditActionParticipantPurge records a participant deleted with their faces and files.
+	auditActionParticipantPurge = "participant.purge"
+
+	// erasedName replaces names of erased members and their participants.
+	erasedName = "ERASED"
+	// erasedStatusReason blocks the participant of an erased member.
+	erasedStatusReason = "personal data erased"
+	// exportPageSize is the page size used to collect paged records for an export.
+	exportPageSize = 100
+)
+
+// Data export formats accepted by Export.
+const (
+	DataExportFormatJSON = "json"
+	DataExportFormatZIP  = "zip"
+)
+
+// DataSubjectService answers data subject requests: exporting everything held
+// about a member, and erasing their personal data on request. It also purges
+// participants together with their faces and files.
+type DataSubjectService struct {
+	members      repository.MemberRepository
+	participants repository.ParticipantRepository
+	certificates repository.LifeCertificateRepository
+	// certificateDocuments are those submitted with manual and proxy
+	// verifications; documents are those filed under the participant, its
+	// certificates and its home visits.
+	certificateDocuments repository.CertificateDocumentRepository
+	documents            repository.DocumentRepository
+	frIdentities         repository.FRIdentityRepository
+	campaigns            repository.CampaignRepository
+	locks                repository.VerificationLockRepository
+	conflicts            repository.FacialConflictRepository
+	// verificationDevices is the registry of devices participants verified
+	// from; devices holds their push tokens.
+	verificationDevices repository.VerificationDeviceRepository
+	devices             repository.DeviceRepository
+	homeVisits          repository.HomeVisitRepository
+	ktpChecks           repository.KTPCheckRepository
+	notifications       repository.NotificationRepository
+	consents            repository.ConsentRepository
+	audit               repository.AuditLogRepository
+	accessLogs          repository.AccessLogRepository
+	blobs               storage.BlobStore
+	frClient            frcore.Client
+	tx                  repository.Transactor
+}
+
+// NewDataSubjectService wires dependencies for data subject requests.
+func NewDataSubjectService(
+	members repository.MemberRepository,
+	participants repository.ParticipantRepository,
+	certificates repository.LifeCertificateRepository,
+	certificateDocuments repository.CertificateDocumentRepository,
+	documents repository.DocumentRepository,
+	frIdentities repository.FRIdentityRepository,
+	campaigns repository.CampaignRepository,
+	locks repository.VerificationLockRepository,
+	conflicts repository.FacialConflictRepository,
+	verificationDevices repository.VerificationDeviceRepository,
+	devices repository.DeviceRepository,
+	homeVisits repository.HomeVisitRepository,
+	ktpChecks repository.KTPCheckRepository,
+	notifications repository.NotificationRepository,
+	consents repository.ConsentRepository,
+	audit repository.AuditLogRepository,
+	accessLogs repository.AccessLogRepository,
+	blobs storage.BlobStore,
+	frClient frcore.Client,
+	tx repository.Transactor,
+) *DataSubjectService {
+	return &DataSubjectService{
+		members:              members,
+		participants:         participants,
+		certificates:         certificates,
+		certificateDocuments: certificateDocuments,
+		documents:            documents,
+		frIdentities:         frIdentities,
+		campaigns:            campaigns,
+		locks:                locks,
+		conflicts:            conflicts,
+		verificationDevices:  verificationDevices,
+		devices:              devices,
+		homeVisits:           homeVisits,
+		ktpChecks:            ktpChecks,
+		notifications:        notifications,
+		consents:             consents,
+		audit:                audit,
+		accessLogs:           accessLogs,
+		blobs:                blobs,
+		frClient:             frClient,
+		tx:                   tx,
+	}
+}
+
+// MemberDataExport is everything held about a member.
+type MemberDataExport struct {
+	ExportedAt   time.Time                  `json:"exported_at"`
+	Member       *domain.Member             `json:"member"`
+	Participant  *domain.Participant        `json:"participant"`
+	Consents     []domain.Consent           `json:"consents"`
+	FRIdentities []domain.FRIdentity        `json:"fr_identities"`
+	Certificates []ExportedCertificate      `json:"certificates"`
+	Devices      []domain.ParticipantDevice `json:"devices"`
+	HomeVisits   []domain.HomeVisit         `json:"home_visits"`
+	KTPChecks    []domain.KTPCheck          `json:"ktp_checks"`
+	// Documents are filed under the participant, its certificates and its home visits.
+	Documents              []domain.Document              `json:"documents"`
+	NotificationPreference *domain.NotificationPreference `json:"notification_preference"`
+	Notifications          []domain.NotificationDelivery  `json:"notifications"`
+	AuditEntries           []domain.AuditLog              `json:"audit_entries"`
+	AccessLogs             []domain.AccessLog             `json:"access_logs"`
+}
+
+// ExportedCertificate is a verification attempt with its supporting documents.
+type ExportedCertificate struct {
+	domain.LifeCertificate
+	Documents []domain.CertificateDocument `json:"documents"`
+}
+
+// EraseMemberInput explains why a member's data is erased.
+type EraseMemberInput struct {
+	Reason string `json:"reason"`
+}
+
+// MemberErasureOutput summarises what an erasure removed.
+type MemberErasureOutput struct {
+	Member           *domain.Member `json:"member"`
+	ParticipantID    *string        `json:"participant_id"`
+	FacesDeleted     int            `json:"faces_deleted"`
+	SelfiesDeleted   int            `json:"selfies_deleted"`
+	DocumentsDeleted int            `json:"documents_deleted"`
+	DevicesDeleted   int            `json:"devices_deleted"`
+}
+
+// ParticipantPurgeOutput summarises what a purge removed.
+type ParticipantPurgeOutput struct {
+	ParticipantID    string `json:"participant_id"`
+	Certificates     int    `json:"certificates"`
+	FacesDeleted     int    `json:"faces_deleted"`
+	SelfiesDeleted   int    `json:"selfies_deleted"`
+	DocumentsDeleted int    `json:"documents_deleted"`
+	DevicesDeleted   int    `json:"devices_deleted"`
+}
+
+// Export collects everything held about a member and records who exported it
+// and in which format.
+func (s *DataSubjectService) Export(ctx context.Context, actor, memberID, format string) (*MemberDataExport, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = DataExportFormatJSON
+	}
+	if format != DataExportFormatJSON && format != DataExportFormatZIP {
+		return nil, fmt.Errorf("format must be json or zip")
+	}
+
+	member, err := s.members.GetByID(ctx, strings.TrimSpace(memberID))
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, ErrMemberNotFound
+	}
+
+	export := &MemberDataExport{ExportedAt: time.Now().UTC(), Member: member}
+	if err := s.collect(ctx, export); err != nil {
+		return nil, err
+	}
+
+	details := map[string]interface{}{
+		"format":       format,
+		"certificates": len(export.Certificates),
+	}
+	if export.Participant != nil {
+		details["participant_id"] = export.Participant.ID
+	}
+	if err := recordAudit(ctx, s.audit, actor, auditActionMemberExport, auditEntityMember, member.ID, details); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+func (s *DataSubjectService) collect(ctx context.Context, export *MemberDataExport) error {
+	member := export.Member
+	preference, err := s.notifications.GetPreference(ctx, member.ID)
+	if err != nil {
+		return err
+	}
+	export.NotificationPreference = preference
+
+	// Audit entries about the member, then about each record linked to it.
+	entities := [][2]string{
+		{auditEntityMember, member.ID},
+		{auditEntityNotificationPreference, member.ID},
+	}
+	resources := [][2]string{{domain.AccessResourceMember, member.ID}}
+
+	if export.Consents, err = s.consents.ListByNIK(ctx, member.NIK); err != nil {
+		return err
+	}
+	for _, consent := range export.Consents {
+		entities = append(entities, [2]string{auditEntityConsent, consent.ID})
+	}
+
+	participant, err := s.participants.GetByMemberID(ctx, member.ID)
+	if err != nil {
+		return err
+	}
+	export.Participant = participant
+	if participant != nil {
+		entities = append(entities, [2]string{auditEntityParticipant, participant.ID})
+		resources = append(resources,
+			[2]string{domain.AccessResourceParticipant, participant.ID},
+			[2]string{domain.AccessResourceCertificateStatus, participant.ID},
+		)
+
+		if export.FRIdentities, err = s.frIdentities.ListByParticipantID(ctx, participant.ID); err != nil {
+			return err
+		}
+		certificates, err := s.certificates.ListByParticipant(ctx, participant.ID)
+		if err != nil {
+			return err
+		}
+		for _, certificate := range certificates {
+			documents, err := s.certificateDocuments.ListByCertificate(ctx, certificate.ID)
+			if err != nil {
+				return err
+			}
+			export.Certificates = append(export.Certificates, ExportedCertificate{LifeCertificate: certificate, Documents: documents})
+			entities = append(entities, [2]string{auditEntityLifeCertificate, certificate.ID})
+			resources = append(resources, [2]string{domain.AccessResourceLifeCertificate, certificate.ID})
+		}
+		if export.Devices, err = s.devices.ListByParticipant(ctx, participant.ID); err != nil {
+			return err
+		}
+		if export.HomeVisits, err = s.homeVisits.ListByParticipant(ctx, participant.ID); err != nil {
+			return err
+		}
+		for _, visit := range export.HomeVisits {
+			entities = append(entities, [2]string{auditEntityHomeVisit, visit.ID})
+			resources = append(resources, [2]string{domain.AccessResourceHomeVisit, visit.ID})
+		}
+		if export.KTPChecks, err = s.ktpChecks.ListByParticipant(ctx, participant.ID); err != nil {
+			return err
+		}
+		for _, check := range export.KTPChecks {
+			entities = append(entities, [2]string{auditEntityKTPCheck, check.ID})
+		}
+		if export.Documents, err = s.filedDocuments(ctx, documentOwners(participant.ID, certificates, export.HomeVisits)); err != nil {
+			return err
+		}
+		for _, document := range export.Documents {
+			entities = append(entities, [2]string{auditEntityDocument, document.ID})
+			resources = append(resources, [2]string{domain.AccessResourceDocument, document.ID})
+		}
+		if export.Notifications, err = s.listNotifications(ctx, participant.ID); err != nil {
+			return err
+		}
+	}
+
+	for _, entity := range entities {
+		entries, err := s.audit.ListByEntity(ctx, entity[0], entity[1])
+		if err != nil {
+			return err
+		}
+		export.AuditEntries = append(export.AuditEntries, entries...)
+	}
+	for _, resource := range resources {
+		entries, err := s.listAccessLogs(ctx, resource[0], resource[1])
+		if err != nil {
+			return err
+		}
+		export.AccessLogs = append(export.AccessLogs, entries...)
+	}
+	return nil
+}
+
+func (s *DataSubjectService) listNotifications(ctx context.Context, participantID string) ([]domain.NotificationDelivery, error) {
+	var all []domain.NotificationDelivery
+	for page := 1; ; page++ {
+		items, total, err := s.notifications.ListDeliveries(ctx, repository.NotificationFilter{ParticipantID: participantID}, repository.Pagination{Page: page, PageSize: exportPageSize})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		if len(items) == 0 || int64(len(all)) >= total {
+			return all, nil
+		}
+	}
+}
+
+func (s *DataSubjectService) listAccessLogs(ctx context.Context, resourceType, resourceID string) ([]domain.AccessLog, error) {
+	var all []domain.AccessLog
+	for page := 1; ; page++ {
+		items, total, err := s.accessLogs.List(ctx, repository.AccessLogFilter{ResourceType: resourceType, ResourceID: resourceID}, repository.Pagination{Page: page, PageSize: exportPageSize})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+		if len(items) == 0 || int64(len(all)) >= total {
+			return all, nil
+		}
+	}
+}
+
+// WriteArchive writes an export as a ZIP holding data.json, the stored
+// selfies under selfies/, the PDF certificates under certificates/ and the
+// supporting and filed documents under documents/, by the record they belong to.
+// Files already removed from the blob store are left out.
+func (s *DataSubjectService) WriteArchive(ctx context.Context, export *MemberDataExport, w io.Writer) error {
+	archive := zip.NewWriter(w)
+
+	data, err := archive.Create("data.json")
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(data)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(export); err != nil {
+		return err
+	}
+
+	for _, certificate := range export.Certificates {
+		if certificate.SelfiePath != "" {
+			if err := s.addBlob(ctx, archive, "selfies/"+certificate.ID+".jpg", certificate.SelfiePath); err != nil {
+				return err
+			}
+		}
+		if certificate.DocumentPath != "" {
+			if err := s.addBlob(ctx, archive, "certificates/"+certificate.ID+".pdf", certificate.DocumentPath); err != nil {
+				return err
+			}
+		}
+		for _, document := range certificate.Documents {
+			name := fmt.Sprintf("documents/%s/%s-%s", certificate.ID, document.ID, filepath.Base(document.FileName))
+			if err := s.addBlob(ctx, archive, name, document.StorageKey); err != nil {
+				return err
+			}
+		}
+	}
+	for _, document := range export.Documents {
+		name := fmt.Sprintf("documents/%s/%s-%s", document.OwnerID, document.ID, filepath.Base(document.FileName))
+		if err := s.addBlob(ctx, archive, name, document.StorageKey); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+func (s *DataSubjectService) addBlob(ctx context.Context, archive *zip.Writer, name, key string) error {
+	content, err := s.blobs.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	defer content.Close()
+
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(file, content)
+	return err
+}
+
+// Erase anonymizes a member's personal data while keeping the records that
+// feed statistics: attempts keep their outcome and scores, the member keeps
+// their city, province, status and birth year. Faces are removed from FR Core
+// and selfies and documents from the blob store first, so a failure there
+// leaves the member untouched and the request can be repeated. Audit entries
+// are kept as the record of who did what.
+func (s *DataSubjectService) Erase(ctx context.Context, actor, memberID string, input EraseMemberInput) (*MemberErasureOutput, error) {
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" {
+		return nil, ErrStatusReasonRequired
+	}
+
+	member, err := s.members.GetByID(ctx, strings.TrimSpace(memberID))
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, ErrMemberNotFound
+	}
+	if member.ErasedAt != nil {
+		return nil, ErrMemberErased
+	}
+
+	export := &MemberDataExport{Member: member}
+	participant, err := s.participants.GetByMemberID(ctx, member.ID)
+	if err != nil {
+		return nil, err
+	}
+	export.Participant = participant
+
+	output := &MemberErasureOutput{Member: member}
+	var owners []documentOwner
+	if participant != nil {
+		output.ParticipantID = &participant.ID
+		if export.FRIdentities, err = s.frIdentities.ListByParticipantID(ctx, participant.ID); err != nil {
+			return nil, err
+		}
+		certificates, err := s.certificates.ListByParticipant(ctx, participant.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, certificate := range certificates {
+			documents, err := s.certificateDocuments.ListByCertificate(ctx, certificate.ID)
+			if err != nil {
+				return nil, err
+			}
+			export.Certificates = append(export.Certificates, ExportedCertificate{LifeCertificate: certificate, Documents: documents})
+		}
+		if export.Devices, err = s.devices.ListByParticipant(ctx, participant.ID); err != nil {
+			return nil, err
+		}
+		visits, err := s.homeVisits.ListByParticipant(ctx, participant.ID)
+		if err != nil {
+			return nil, err
+		}
+		owners = documentOwners(participant.ID, certificates, visits)
+		if export.Documents, err = s.filedDocuments(ctx, owners); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, identity := range export.FRIdentities {
+		if err := s.frClient.DeleteFace(ctx, identity.Label); err != nil {
+			return nil, fmt.Errorf("delete face %s: %w", identity.Label, err)
+		}
+		output.FacesDeleted++
+	}
+	for _, certificate := range export.Certificates {
+		if certificate.SelfiePath != "" {
+			if err := s.blobs.Delete(ctx, certificate.SelfiePath); err != nil {
+				return nil, err
+			}
+			output.SelfiesDeleted++
+		}
+		if certificate.DocumentPath != "" {
+			if err := s.blobs.Delete(ctx, certificate.DocumentPath); err != nil {
+				return nil, err
+			}
+		}
+		for _, document := range certificate.Documents {
+			if err := s.blobs.Delete(ctx, document.StorageKey); err != nil {
+				return nil, err
+			}
+			output.DocumentsDeleted++
+		}
+	}
+	for _, document := range export.Documents {
+		if err := s.blobs.Delete(ctx, document.StorageKey); err != nil {
+			return nil, err
+		}
+		output.DocumentsDeleted++
+	}
+
+	now := time.Now().UTC()
+	niks := []string{member.NIK}
+	if participant != nil && participant.NIK != member.NIK {
+		niks = append(niks, participant.NIK)
+	}
+	anonymizeMember(member, now)
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.members.Erase(ctx, member); err != nil {
+			return err
+		}
+		for _, nik := range niks {
+			if err := s.consents.Anonymize(ctx, nik, member.NIK); err != nil {
+				return err
+			}
+		}
+		if err := s.notifications.DeletePreference(ctx, member.ID); err != nil {
+			return err
+		}
+
+		if participant != nil {
+			participant.NIK = member.NIK
+			participant.Name = erasedName
+			participant.PhoneNumber = ""
+			participant.Email = ""
+			participant.Address = ""
+			participant.UpdatedAt = now
+			if err := s.participants.Update(ctx, participant); err != nil {
+				return err
+			}
+			statusReason := erasedStatusReason
+			participant.Status = domain.ParticipantStatusBlocked
+			participant.StatusReason = &statusReason
+			participant.StatusChangedAt = &now
+			if err := s.participants.UpdateStatus(ctx, participant); err != nil {
+				return err
+			}
+			if err := s.frIdentities.DeleteByParticipantID(ctx, participant.ID); err != nil {
+				return err
+			}
+			if err := s.certificates.AnonymizeByParticipant(ctx, participant.ID); err != nil {
+				return err
+			}
+			if err := s.homeVisits.AnonymizeByParticipant(ctx, participant.ID); err != nil {
+				return err
+			}
+			if err := s.ktpChecks.AnonymizeByParticipant(ctx, participant.ID); err != nil {
+				return err
+			}
+			for _, certificate := range export.Certificates {
+				if err := s.certificateDocuments.DeleteByCertificate(ctx, certificate.ID); err != nil {
+					return err
+				}
+			}
+			if err := s.deleteFiledDocuments(ctx, owners); err != nil {
+				return err
+			}
+			for _, device := range export.Devices {
+				if err := s.devices.Delete(ctx, device.ID); err != nil {
+					return err
+				}
+				output.DevicesDeleted++
+			}
+			if err := s.notifications.AnonymizeDeliveries(ctx, participant.ID); err != nil {
+				return err
+			}
+		}
+
+		return recordAudit(ctx, s.audit, actor, auditActionMemberErase, auditEntityMember, member.ID, map[string]interface{}{
+			"reason":            reason,
+			"participant_id":    output.ParticipantID,
+			"certificates":      len(export.Certificates),
+			"faces_deleted":     output.FacesDeleted,
+			"selfies_deleted":   output.SelfiesDeleted,
+			"documents_deleted": output.DocumentsDeleted,
+			"devices_deleted":   len(export.Devices),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// PurgeParticipant deletes a participant as ParticipantService.Delete does,
+// and also what that leaves behind: the faces enrolled in FR Core, stored
+// selfies and supporting documents, and registered devices. Notification
+// deliveries and consents are kept as records of what was sent and agreed.
+// Faces and files go first, so a purge that fails part way can be repeated.
+func (s *DataSubjectService) PurgeParticipant(ctx context.Context, actor, participantID string) (*ParticipantPurgeOutput, error) {
+	participant, err := s.participants.GetByID(ctx, strings.TrimSpace(participantID))
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return nil, ErrParticipantNotFound
+	}
+
+	identities, err := s.frIdentities.ListByParticipantID(ctx, participant.ID)
+	if err != nil {
+		return nil, err
+	}
+	certificates, err := s.certificates.ListByParticipant(ctx, participant.ID)
+	if err != nil {
+		return nil, err
+	}
+	devices, err := s.devices.ListByParticipant(ctx, participant.ID)
+	if err != nil {
+		return nil, err
+	}
+	visits, err := s.homeVisits.ListByParticipant(ctx, participant.ID)
+	if err != nil {
+		return nil, err
+	}
+	owners := documentOwners(participant.ID, certificates, visits)
+	filed, err := s.filedDocuments(ctx, owners)
+	if err != nil {
+		return nil, err
+	}
+
+	output := &ParticipantPurgeOutput{ParticipantID: participant.ID, Certificates: len(certificates)}
+	for _, identity := range identities {
+		if err := s.frClient.DeleteFace(ctx, identity.Label); err != nil {
+			return nil, fmt.Errorf("delete face %s: %w", identity.Label, err)
+		}
+		output.FacesDeleted++
+	}
+	for _, certificate := range certificates {
+		if certificate.SelfiePath != "" {
+			if err := s.blobs.Delete(ctx, certificate.SelfiePath); err != nil {
+				return nil, err
+			}
+			output.SelfiesDeleted++
+		}
+		if certificate.DocumentPath != "" {
+			if err := s.blobs.Delete(ctx, certificate.DocumentPath); err != nil {
+				return nil, err
+			}
+		}
+		documents, err := s.certificateDocuments.ListByCertificate(ctx, certificate.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, document := range documents {
+			if err := s.blobs.Delete(ctx, document.StorageKey); err != nil {
+				return nil, err
+			}
+			output.DocumentsDeleted++
+		}
+	}
+	for _, document := range filed {
+		if err := s.blobs.Delete(ctx, document.StorageKey); err != nil {
+			return nil, err
+		}
+		output.DocumentsDeleted++
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, certificate := range certificates {
+			if err := s.certificateDocuments.DeleteByCertificate(ctx, certificate.ID); err != nil {
+				return err
+			}
+		}
+		if err := s.deleteFiledDocuments(ctx, owners); err != nil {
+			return err
+		}
+		for _, device := range devices {
+			if err := s.devices.Delete(ctx, device.ID); err != nil {
+				return err
+			}
+			output.DevicesDeleted++
+		}
+		if err := s.certificates.DeleteByParticipant(ctx, participant.ID); err != nil {
+			return err
+		}
+		if err := s.frIdentities.DeleteByParticipantID(ctx, participant.ID); err != nil {
+			return err
+		}
+		if err := s.campaigns.DeleteByParticipant(ctx, participant.ID); err != nil {
+			return err
+		}
+		if err := s.locks.DeleteByParticipant(ctx, participant.ID); err != nil {
+			return err
+		}
+		if err := s.conflicts.DeleteByParticipant(ctx, participant.ID); err != nil {
+			return err
+		}
+		if err := s.verificationDevices.DeleteByParticipant(ctx, participant.ID); err != nil {
+			return err
+		}
+		if err := s.homeVisits.DeleteByParticipant(ctx, participant.ID); err != nil {
+			return err
+		}
+		if err := s.ktpChecks.DeleteByParticipant(ctx, participant.ID); err != nil {
+			return err
+		}
+		if err := s.participants.Delete(ctx, participant.ID); err != nil {
+			return err
+		}
+
+		return recordAudit(ctx, s.audit, actor, auditActionParticipantPurge, auditEntityParticipant, participant.ID, map[string]interface{}{
+			"member_id":         participant.MemberID,
+			"certificates":      output.Certificates,
+			"faces_deleted":     output.FacesDeleted,
+			"selfies_deleted":   output.SelfiesDeleted,
+			"documents_deleted": output.DocumentsDeleted,
+			"devices_deleted":   output.DevicesDeleted,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// anonymizeMember replaces a member's identifying fields. The pseudonymous
+// NIK and nomor peserta stay unique; the birth date keeps only the year.
+func anonymizeMember(member *domain.Member, now time.Time) {
+	compact := strings.ReplaceAll(member.ID, "-", "")
+	member.NIK = "ERASED" + compact[:14]
+	member.NomorPeserta = "ERASED-" + member.ID
+	member.BirthDate = time.Date(member.BirthDate.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	member.FullName = erasedName
+	member.Address = ""
+	member.PhoneNumber = ""
+	member.Email = ""
+	member.ErasedAt = &now
+	member.UpdatedAt = now
+}
+
+// documentOwner is a record documents may be filed under.
+type documentOwner struct {
+	ownerType domain.DocumentOwnerType
+	id        string
+}
+
+// documentOwners lists a participant and its certificates and home visits.
+func documentOwners(participantID string, certificates []domain.LifeCertificate, visits []domain.HomeVisit) []documentOwner {
+	owners := []documentOwner{{domain.DocumentOwnerParticipant, participantID}}
+	for _, certificate := range certificates {
+		owners = append(owners, documentOwner{domain.DocumentOwnerLifeCertificate, certificate.ID})
+	}
+	for _, visit := range visits {
+		owners = append(owners, documentOwner{domain.DocumentOwnerHomeVisit, visit.ID})
+	}
+	return owners
+}
+
+func (s *DataSubjectService) filedDocuments(ctx context.Context, owners []documentOwner) ([]domain.Document, error) {
+	var all []domain.Document
+	for _, owner := range owners {
+		documents, err := s.documents.ListByOwner(ctx, owner.ownerType, owner.id)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, documents...)
+	}
+	return all, nil
+}
+
+func (s *DataSubjectService) deleteFiledDocuments(ctx context.Context, owners []documentOwner) error {
+	for _, owner := range owners {
+		if err := s.documents.DeleteByOwner(ctx, owner.ownerType, owner.id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/civilregistry"
+	"life-certificates/internal/domain"
+	"life-certificates/internal/events"
+	"life-certificates/internal/repository"
+)
+
+const (
+	auditActionDeathReported = "member.death_reported"
+	auditActionDeathConfirm  = "member.death_confirm"
+	auditActionDeathReject   = "member.death_reject"
+
+	// deathCheckActor is recorded as the actor of reports raised by the scheduled check.
+	deathCheckActor = "civil-registry"
+	// deathCheckPageSize is how many members are loaded at a time; the
+	// registry is queried between pages, with no database cursor open.
+	deathCheckPageSize = 500
+	// deathBlockReason marks participants blocked because of a death report,
+	// so a rejection only reactivates participants nobody blocked otherwise.
+	deathBlockReason = "death reported by the civil registry"
+)
+
+var (
+	// ErrDeathReportNotFound indicates the requested death report does not exist.
+	ErrDeathReportNotFound = errors.New("death report not found")
+	// ErrDeathReportNotPending signals the report was already confirmed or rejected.
+	ErrDeathReportNotPending = errors.New("death report is not pending")
+)
+
+// DeathReportService checks members against the civil registry. A reported
+// death marks the member DECEASED and blocks their participant at once, so no
+// further verification is accepted, and raises an alert asking staff to
+// confirm the report or reject it.
+type DeathReportService struct {
+	reports      repository.DeathReportRepository
+	members      repository.MemberRepository
+	participants repository.ParticipantRepository
+	audit        repository.AuditLogRepository
+	registry     civilregistry.Client
+	tx           repository.Transactor
+	events       events.Publisher
+}
+
+// NewDeathReportService wires dependencies for civil registry death checks.
+// registry may be nil when no civil registry is configured; Check then fails.
+func NewDeathReportService(reports repository.DeathReportRepository, members repository.MemberRepository, participants repository.ParticipantRepository, audit repository.AuditLogRepository, registry civilregistry.Client, tx repository.Transactor, publisher events.Publisher) *DeathReportService {
+	return &DeathReportService{
+		reports:      reports,
+		members:      members,
+		participants: participants,
+		audit:        audit,
+		registry:     registry,
+		tx:           tx,
+		events:       publisher,
+	}
+}
+
+// DecideDeathReportInput carries the staff member's notes.
+type DecideDeathReportInput struct {
+	Notes string `json:"notes"`
+}
+
+// DeathReportListOutput is a page of death reports.
+type DeathReportListOutput struct {
+	Items    []domain.DeathReport `json:"items"`
+	Page     int                  `json:"page"`
+	PageSize int                  `json:"page_size"`
+	Total    int64                `json:"total"`
+}
+
+// Check looks up every ACTIVE member in the civil registry and records a
+// report for each reported death. A failed lookup does not stop the run; the
+// failures are returned together once every member was tried.
+func (s *DeathReportService) Check(ctx context.Context) error {
+	if s.registry == nil {
+		return fmt.Errorf("civil registry is not configured")
+	}
+	var (
+		checked, reported, failed int
+		lastErr                   error
+		afterID                   string
+	)
+	for {
+		members, err := s.members.ListActiveAfter(ctx, afterID, deathCheckPageSize)
+		if err != nil {
+			return err
+		}
+		for i := range members {
+			member := &members[i]
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			checked++
+			record, err := s.registry.DeathStatus(ctx, member.NIK)
+			if err == nil && record.Deceased {
+				err = s.record(ctx, member, record)
+				if err == nil {
+					reported++
+				}
+			}
+			if err != nil {
+				failed++
+				lastErr = err
+				log.Printf("[civil-registry] member %s: %v", member.ID, err)
+			}
+		}
+		if len(members) < deathCheckPageSize {
+			break
+		}
+		afterID = members[len(members)-1].ID
+	}
+
+	log.Printf("[civil-registry] checked=%d reported=%d failed=%d", checked, reported, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d civil registry checks failed, last: %w", failed, checked, lastErr)
+	}
+	return nil
+}
+
+// record marks the member DECEASED, blocks their participant and stores a
+// pending report, alerting staff through the outbox in the same transaction.
+func (s *DeathReportService) record(ctx context.Context, member *domain.Member, record *civilregistry.Record) error {
+	now := time.Now().UTC()
+	report := &domain.DeathReport{
+		ID:          uuid.NewString(),
+		MemberID:    member.ID,
+		NIK:         member.NIK,
+		DateOfDeath: record.DateOfDeath,
+		State:       domain.DeathReportPending,
+		ReportedAt:  now,
+	}
+	if record.Reference != "" {
+		report.Reference = &record.Reference
+	}
+
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		member.Status = domain.MemberStatusDeceased
+		member.UpdatedAt = now
+		if err := s.members.Update(ctx, member); err != nil {
+			return err
+		}
+		participant, err := s.participants.GetByMemberID(ctx, member.ID)
+		if err != nil {
+			return err
+		}
+		if participant != nil && participant.Status != domain.ParticipantStatusBlocked {
+			reason := deathBlockReason
+			participant.Status = domain.ParticipantStatusBlocked
+			participant.StatusReason = &reason
+			participant.StatusChangedAt = &now
+			participant.UpdatedAt = now
+			if err := s.participants.UpdateStatus(ctx, participant); err != nil {
+				return err
+			}
+			report.ParticipantID = &participant.ID
+		}
+		if err := s.reports.Create(ctx, report); err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, s.audit, deathCheckActor, auditActionDeathReported, auditEntityMember, member.ID, map[string]interface{}{
+			"report_id":      report.ID,
+			"participant_id": report.ParticipantID,
+			"date_of_death":  report.DateOfDeath,
+			"reference":      report.Reference,
+		}); err != nil {
+			return err
+		}
+		return publishEvent(ctx, s.events, events.TypeAlertTriggered, map[string]interface{}{
+			"kind":     "member_reported_deceased",
+			"severity": alertSeverityWarning,
+			"message":  fmt.Sprintf("civil registry reports member %s deceased; confirm or reject death report %s", member.ID, report.ID),
+			"details": map[string]interface{}{
+				"report_id":      report.ID,
+				"member_id":      member.ID,
+				"participant_id": report.ParticipantID,
+				"date_of_death":  report.DateOfDeath,
+				"reference":      report.Reference,
+			},
+		})
+	})
+}
+
+// List returns reports filtered by state (PENDING, CONFIRMED, REJECTED or empty for all).
+func (s *DeathReportService) List(ctx context.Context, state string, pageNum, pageSize int) (*DeathReportListOutput, error) {
+	filter := domain.DeathReportState(strings.ToUpper(strings.TrimSpace(state)))
+	switch filter {
+	case "", domain.DeathReportPending, domain.DeathReportConfirmed, domain.DeathReportRejected:
+	default:
+		return nil, fmt.Errorf("state must be one of PENDING, CONFIRMED, REJECTED")
+	}
+
+	page := normalizePagination(pageNum, pageSize)
+	items, total, err := s.reports.List(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+	return &DeathReportListOutput{Items: items, Page: page.Page, PageSize: page.PageSize, Total: total}, nil
+}
+
+// Confirm accepts a pending report; the member stays DECEASED and their participant blocked.
+func (s *DeathReportService) Confirm(ctx context.Context, actor, reportID string, input DecideDeathReportInput) (*domain.DeathReport, error) {
+	report, err := s.pending(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+	stampDeathReportDecision(report, domain.DeathReportConfirmed, actor, input)
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		ok, err := s.reports.Decide(ctx, report)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrDeathReportNotPending
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionDeathConfirm, auditEntityMember, report.MemberID, map[string]interface{}{
+			"report_id": report.ID,
+			"notes":     report.DecisionNotes,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// Reject dismisses a pending report as a registry error: the member becomes
+// ACTIVE again and the participant the report blocked is reactivated, unless
+// either was changed since.
+func (s *DeathReportService) Reject(ctx context.Context, actor, reportID string, input DecideDeathReportInput) (*domain.DeathReport, error) {
+	if strings.TrimSpace(input.Notes) == "" {
+		return nil, fmt.Errorf("notes are required when rejecting")
+	}
+	report, err := s.pending(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+	stampDeathReportDecision(report, domain.DeathReportRejected, actor, input)
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		ok, err := s.reports.Decide(ctx, report)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrDeathReportNotPending
+		}
+
+		now := time.Now().UTC()
+		member, err := s.members.GetByID(ctx, report.MemberID)
+		if err != nil {
+			return err
+		}
+		if member != nil && member.Status == domain.MemberStatusDeceased {
+			member.Status = domain.MemberStatusActive
+			member.UpdatedAt = now
+			if err := s.members.Update(ctx, member); err != nil {
+				return err
+			}
+		}
+		reactivated := false
+		if report.ParticipantID != nil {
+			participant, err := s.participants.GetByID(ctx, *report.ParticipantID)
+			if err != nil {
+				return err
+			}
+			if participant != nil && participant.Status == domain.ParticipantStatusBlocked &&
+				participant.StatusReason != nil && *participant.StatusReason == deathBlockReason {
+				participant.Status = domain.ParticipantStatusActive
+				participant.StatusReason = nil
+				participant.StatusChangedAt = &now
+				participant.UpdatedAt = now
+				if err := s.participants.UpdateStatus(ctx, participant); err != nil {
+					return err
+				}
+				reactivated = true
+			}
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionDeathReject, auditEntityMember, report.MemberID, map[string]interface{}{
+			"report_id":               report.ID,
+			"participant_reactivated": reactivated,
+			"notes":                   report.DecisionNotes,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *DeathReportService) pending(ctx context.Context, reportID string) (*domain.DeathReport, error) {
+	report, err := s.reports.GetByID(ctx, strings.TrimSpace(reportID))
+	if err != nil {
+		return nil, err
+	}
+	if report == nil {
+		return nil, ErrDeathReportNotFound
+	}
+	if report.State != domain.DeathReportPending {
+		return nil, ErrDeathReportNotPending
+	}
+	return report, nil
+}
+
+func stampDeathReportDecision(report *domain.DeathReport, state domain.DeathReportState, actor string, input DecideDeathReportInput) {
+	now := time.Now().UTC()
+	report.State = state
+	report.DecidedBy = &actor
+	report.DecidedAt = &now
+	if notes := strings.TrimSpace(input.Notes); notes != "" {
+		report.DecisionNotes = &notes
+	}
+}
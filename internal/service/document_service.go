@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/storage"
+)
+
+// Audit vocabulary for filed documents.
+const (
+	auditEntityDocument       = "document"
+	auditActionDocumentUpload = "document.upload"
+	auditActionDocumentDelete = "document.delete"
+)
+
+// ErrDocumentRestricted indicates a restricted document was requested by
+// someone other than its uploader or an administrator.
+var ErrDocumentRestricted = errors.New("document is restricted to its uploader and administrators")
+
+var documentOwnerTypes = map[domain.DocumentOwnerType]bool{
+	domain.DocumentOwnerParticipant:     true,
+	domain.DocumentOwnerLifeCertificate: true,
+	domain.DocumentOwnerHomeVisit:       true,
+}
+
+var documentCategories = map[domain.DocumentCategory]bool{
+	domain.DocumentCategoryIdentityCard:     true,
+	domain.DocumentCategoryFamilyCard:       true,
+	domain.DocumentCategoryDoctorLetter:     true,
+	domain.DocumentCategoryHomeVisitPhoto:   true,
+	domain.DocumentCategoryPowerOfAttorney:  true,
+	domain.DocumentCategoryDeathCertificate: true,
+	domain.DocumentCategoryOther:            true,
+}
+
+// restrictedDocumentCategories hold health data, downloadable only by the
+// uploader and administrators.
+var restrictedDocumentCategories = map[domain.DocumentCategory]bool{
+	domain.DocumentCategoryDoctorLetter: true,
+}
+
+// DocumentService files supporting documents under participants, life
+// certificates and home visits, keeping their content in the blob store.
+type DocumentService struct {
+	documents    repository.DocumentRepository
+	participants repository.ParticipantRepository
+	certificates repository.LifeCertificateRepository
+	visits       repository.HomeVisitRepository
+	blobs        storage.BlobStore
+	scans        *UploadScanService
+	audit        repository.AuditLogRepository
+	tx           repository.Transactor
+}
+
+// NewDocumentService wires dependencies for filed documents.
+func NewDocumentService(documents repository.DocumentRepository, participants repository.ParticipantRepository, certificates repository.LifeCertificateRepository, visits repository.HomeVisitRepository, blobs storage.BlobStore, scans *UploadScanService, audit repository.AuditLogRepository, tx repository.Transactor) *DocumentService {
+	return &DocumentService{
+		documents:    documents,
+		participants: participants,
+		certificates: certificates,
+		visits:       visits,
+		blobs:        blobs,
+		scans:        scans,
+		audit:        audit,
+		tx:           tx,
+	}
+}
+
+// DocumentCaller is who asks for a document; restricted documents and
+// deletions are reserved to the uploader and administrators.
+type DocumentCaller struct {
+	Username string
+	Admin    bool
+}
+
+// UploadDocumentInput files one document under a record.
+type UploadDocumentInput struct {
+	OwnerType   string
+	OwnerID     string
+	Category    string
+	Description string
+	FileName    string
+	Data        []byte
+}
+
+// Upload validates, scans and stores a document and files it under its owner.
+func (s *DocumentService) Upload(ctx context.Context, actor string, input UploadDocumentInput) (*domain.Document, error) {
+	ownerType := domain.DocumentOwnerType(strings.ToLower(strings.TrimSpace(input.OwnerType)))
+	ownerID := strings.TrimSpace(input.OwnerID)
+	category := domain.DocumentCategory(strings.ToUpper(strings.TrimSpace(input.Category)))
+	description := optionalString(&input.Description)
+
+	verr := &ValidationError{}
+	switch {
+	case ownerType == "":
+		verr.add("owner_type", "is required")
+	case !documentOwnerTypes[ownerType]:
+		verr.add("owner_type", "must be participant, life_certificate or home_visit")
+	}
+	if ownerID == "" {
+		verr.add("owner_id", "is required")
+	}
+	switch {
+	case category == "":
+		verr.add("category", "is required")
+	case !documentCategories[category]:
+		verr.add("category", "must be IDENTITY_CARD, FAMILY_CARD, DOCTOR_LETTER, HOME_VISIT_PHOTO, POWER_OF_ATTORNEY, DEATH_CERTIFICATE or OTHER")
+	}
+	if description != nil && len(*description) > 500 {
+		verr.add("description", "must be at most 500 characters")
+	}
+	contentType, problem := checkSupportingDocument(DocumentUpload{FileName: input.FileName, Data: input.Data})
+	if problem != "" {
+		verr.add("file", fmt.Sprintf("%s: %s", input.FileName, problem))
+	}
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkOwner(ctx, ownerType, ownerID); err != nil {
+		return nil, err
+	}
+	scan, err := s.scans.Check(ctx, actor, input.FileName, input.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	document := newDocument(actor, ownerType, ownerID, category, description, input.FileName, contentType, input.Data)
+	if err := s.store(ctx, document, input.Data); err != nil {
+		return nil, err
+	}
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		return s.create(ctx, actor, document, scan)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return document, nil
+}
+
+// List returns the documents filed under a record, oldest first.
+func (s *DocumentService) List(ctx context.Context, ownerType, ownerID string) ([]domain.Document, error) {
+	owner := domain.DocumentOwnerType(strings.ToLower(strings.TrimSpace(ownerType)))
+	ownerID = strings.TrimSpace(ownerID)
+	verr := &ValidationError{}
+	if !documentOwnerTypes[owner] {
+		verr.add("owner_type", "must be participant, life_certificate or home_visit")
+	}
+	if ownerID == "" {
+		verr.add("owner_id", "is required")
+	}
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkOwner(ctx, owner, ownerID); err != nil {
+		return nil, err
+	}
+	return s.documents.ListByOwner(ctx, owner, ownerID)
+}
+
+// Get returns a document's metadata.
+func (s *DocumentService) Get(ctx context.Context, id string) (*domain.Document, error) {
+	document, err := s.documents.GetByID(ctx, strings.TrimSpace(id))
+	if err != nil {
+		return nil, err
+	}
+	if document == nil {
+		return nil, ErrDocumentNotFound
+	}
+	return document, nil
+}
+
+// Open returns a document's metadata and content. Restricted documents are
+// only opened for their uploader and administrators.
+func (s *DocumentService) Open(ctx context.Context, caller DocumentCaller, id string) (*domain.Document, io.ReadCloser, error) {
+	document, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if restrictedDocumentCategories[document.Category] && !caller.privileged(document) {
+		return nil, nil, ErrDocumentRestricted
+	}
+
+	content, err := s.blobs.Get(ctx, document.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, ErrDocumentNotFound
+		}
+		return nil, nil, err
+	}
+	return document, content, nil
+}
+
+// Delete removes a document on behalf of its uploader or an administrator.
+// The content goes first, so a failure leaves the document listed.
+func (s *DocumentService) Delete(ctx context.Context, caller DocumentCaller, id string) error {
+	document, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !caller.privileged(document) {
+		return ErrDocumentRestricted
+	}
+
+	if err := s.blobs.Delete(ctx, document.StorageKey); err != nil {
+		return err
+	}
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.documents.Delete(ctx, document.ID); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit, caller.Username, auditActionDocumentDelete, auditEntityDocument, document.ID, map[string]interface{}{
+			"owner_type": document.OwnerType,
+			"owner_id":   document.OwnerID,
+			"category":   document.Category,
+			"file_name":  document.FileName,
+		})
+	})
+}
+
+// checkOwner makes sure the record a document is filed under exists.
+func (s *DocumentService) checkOwner(ctx context.Context, ownerType domain.DocumentOwnerType, ownerID string) error {
+	switch ownerType {
+	case domain.DocumentOwnerParticipant:
+		participant, err := s.participants.GetByID(ctx, ownerID)
+		if err != nil {
+			return err
+		}
+		if participant == nil {
+			return ErrParticipantNotFound
+		}
+	case domain.DocumentOwnerLifeCertificate:
+		record, err := s.certificates.GetByID(ctx, ownerID)
+		if err != nil {
+			return err
+		}
+		if record == nil {
+			return ErrCertificateNotFound
+		}
+	case domain.DocumentOwnerHomeVisit:
+		visit, err := s.visits.GetByID(ctx, ownerID)
+		if err != nil {
+			return err
+		}
+		if visit == nil {
+			return ErrHomeVisitNotFound
+		}
+	}
+	return nil
+}
+
+// newDocument describes an upload filed under its owner.
+func newDocument(actor string, ownerType domain.DocumentOwnerType, ownerID string, category domain.DocumentCategory, description *string, fileName, contentType string, data []byte) *domain.Document {
+	digest := sha256.Sum256(data)
+	document := &domain.Document{
+		ID:          uuid.NewString(),
+		OwnerType:   ownerType,
+		OwnerID:     ownerID,
+		Category:    category,
+		Description: description,
+		FileName:    filepath.Base(fileName),
+		ContentType: contentType,
+		SizeBytes:   int64(len(data)),
+		SHA256:      hex.EncodeToString(digest[:]),
+		UploadedBy:  actor,
+		CreatedAt:   time.Now().UTC(),
+	}
+	document.StorageKey = fmt.Sprintf("documents/%s/%s/%s%s", ownerType, ownerID, document.ID, strings.ToLower(filepath.Ext(document.FileName)))
+	return document
+}
+
+// store writes a document's content to the blob store, ahead of the
+// transaction that creates the document.
+func (s *DocumentService) store(ctx context.Context, document *domain.Document, data []byte) error {
+	return s.blobs.Put(ctx, document.StorageKey, data)
+}
+
+// create records a stored document and the malware scan of its upload,
+// within the caller's transaction.
+func (s *DocumentService) create(ctx context.Context, actor string, document *domain.Document, scan *domain.UploadScan) error {
+	if err := s.documents.Create(ctx, document); err != nil {
+		return err
+	}
+	if scan != nil {
+		scan.DocumentID = &document.ID
+		if err := s.scans.Record(ctx, scan); err != nil {
+			return err
+		}
+	}
+	return recordAudit(ctx, s.audit, actor, auditActionDocumentUpload, auditEntityDocument, document.ID, map[string]interface{}{
+		"owner_type": document.OwnerType,
+		"owner_id":   document.OwnerID,
+		"category":   document.Category,
+		"file_name":  document.FileName,
+		"sha256":     document.SHA256,
+	})
+}
+
+func (c DocumentCaller) privileged(document *domain.Document) bool {
+	return c.Admin || c.Username == document.UploadedBy
+}
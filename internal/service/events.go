@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"life-certificates/internal/events"
+)
+
+// publishEvent emits a domain event. With the outbox publisher the event joins
+// the transaction carried by ctx, so it is only published if that commits.
+func publishEvent(ctx context.Context, publisher events.Publisher, eventType string, data map[string]interface{}) error {
+	if publisher == nil {
+		return nil
+	}
+	return publisher.Publish(ctx, events.New(eventType, data))
+}
+
+// exponentialBackoff doubles base after every failed attempt, capped at max.
+func exponentialBackoff(base, max time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	return delay
+}
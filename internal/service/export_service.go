@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+)
+
+// ExportService streams members and verification attempts for bulk exports.
+type ExportService struct {
+	members      repository.MemberRepository
+	certificates repository.LifeCertificateRepository
+	campaigns    repository.CampaignRepository
+}
+
+// NewExportService wires dependencies for exports.
+func NewExportService(members repository.MemberRepository, certificates repository.LifeCertificateRepository, campaigns repository.CampaignRepository) *ExportService {
+	return &ExportService{members: members, certificates: certificates, campaigns: campaigns}
+}
+
+// CertificateExportInput carries the certificate export filters.
+type CertificateExportInput struct {
+	Status   string
+	Method   string
+	Channel  string
+	Location string
+	// CampaignID narrows the export to the campaign's participants since it started.
+	CampaignID string
+	From       string
+	To         string
+}
+
+// Members hands every member to fn, newest first.
+func (s *ExportService) Members(ctx context.Context, fn func(*domain.Member) error) error {
+	return s.members.Stream(ctx, fn)
+}
+
+// Certificates hands the attempts matching the filters to fn in verification
+// order. Invalid filters are reported before fn is called.
+func (s *ExportService) Certificates(ctx context.Context, input CertificateExportInput, fn func(*repository.CertificateExportRow) error) error {
+	filter := repository.CertificateExportFilter{
+		Status:     domain.LifeCertificateStatus(strings.ToUpper(strings.TrimSpace(input.Status))),
+		Method:     domain.VerificationMethod(strings.ToUpper(strings.TrimSpace(input.Method))),
+		Channel:    domain.VerificationChannel(strings.ToUpper(strings.TrimSpace(input.Channel))),
+		Location:   strings.TrimSpace(input.Location),
+		CampaignID: strings.TrimSpace(input.CampaignID),
+	}
+	verr := &ValidationError{}
+	switch filter.Status {
+	case "", domain.LifeCertificateStatusValid, domain.LifeCertificateStatusInvalid, domain.LifeCertificateStatusReview:
+	default:
+		verr.add("status", "must be one of VALID, INVALID, REVIEW")
+	}
+	switch filter.Method {
+	case "", domain.VerificationMethodAutomatic, domain.VerificationMethodManual, domain.VerificationMethodProxy:
+	default:
+		verr.add("method", "must be one of AUTOMATIC, MANUAL, PROXY")
+	}
+	switch filter.Channel {
+	case "", domain.VerificationChannelMobile, domain.VerificationChannelKiosk, domain.VerificationChannelField, domain.VerificationChannelProxy, domain.VerificationChannelManual:
+	default:
+		verr.add("channel", "must be one of MOBILE, KIOSK, FIELD, PROXY, MANUAL")
+	}
+	filter.From, filter.To = parseDateRange(verr, input.From, input.To)
+	if filter.CampaignID != "" {
+		campaign, err := s.campaigns.GetByID(ctx, filter.CampaignID)
+		if err != nil {
+			return err
+		}
+		if campaign == nil {
+			verr.add("campaign_id", "campaign not found")
+		}
+	}
+	if err := verr.errOrNil(); err != nil {
+		return err
+	}
+
+	return s.certificates.Stream(ctx, filter, fn)
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"life-certificates/internal/frcore"
+)
+
+// FaceQualityThresholds bound the FR Core quality assessment a registration
+// or enrolment photo must pass.
+type FaceQualityThresholds struct {
+	Enabled bool
+	// MinFaceRatio is the smallest share of the photo's width the face may cover.
+	MinFaceRatio float64
+	// MaxPoseDegrees bounds the head's yaw and pitch away from the camera.
+	MaxPoseDegrees float64
+	// MinBrightness and MaxBrightness bound the face's mean brightness, 0 to 255.
+	MinBrightness float64
+	MaxBrightness float64
+}
+
+// FaceQualityError rejects a photo that failed the quality gate, with what to fix.
+type FaceQualityError struct {
+	Reasons []string
+}
+
+// Error lists the reasons the photo was rejected.
+func (e *FaceQualityError) Error() string {
+	return "photo failed the face quality check: " + strings.Join(e.Reasons, "; ")
+}
+
+// checkFaceQuality asks FR Core to assess a processed photo and returns a
+// FaceQualityError when it falls outside the thresholds.
+func (s *ParticipantService) checkFaceQuality(ctx context.Context, imageName string, image []byte) error {
+	if !s.quality.Enabled {
+		return nil
+	}
+	assessment, err := s.frClient.AssessQuality(ctx, frcore.QualityRequest{ImageName: imageName, Image: image})
+	if err != nil {
+		return err
+	}
+	if reasons := s.quality.reasons(assessment); len(reasons) > 0 {
+		return &FaceQualityError{Reasons: reasons}
+	}
+	return nil
+}
+
+// reasons explains, as instructions to the person taking the photo, every
+// threshold the assessment misses.
+func (t FaceQualityThresholds) reasons(assessment *frcore.QualityResponse) []string {
+	switch {
+	case assessment.FaceCount == 0:
+		return []string{"no face was found; make sure the whole face is visible and well lit"}
+	case assessment.FaceCount > 1:
+		return []string{"more than one face was found; make sure only the participant is in the photo"}
+	}
+
+	var reasons []string
+	if assessment.FaceRatio < t.MinFaceRatio {
+		reasons = append(reasons, "the face is too small; move closer to the camera")
+	}
+	if math.Abs(assessment.Yaw) > t.MaxPoseDegrees || math.Abs(assessment.Pitch) > t.MaxPoseDegrees {
+		reasons = append(reasons, "the head is turned or tilted; look straight at the camera")
+	}
+	switch {
+	case assessment.Brightness < t.MinBrightness:
+		reasons = append(reasons, "the photo is too dark; face a light source")
+	case assessment.Brightness > t.MaxBrightness:
+		reasons = append(reasons, "the photo is overexposed; avoid direct light or flash")
+	}
+	return reasons
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/events"
+	"life-certificates/internal/metrics"
+	"life-certificates/internal/repository"
+)
+
+const (
+	auditActionFacialConflict        = "participant.facial_conflict"
+	auditActionFacialConflictConfirm = "participant.facial_conflict_confirm"
+	auditActionFacialConflictDismiss = "participant.facial_conflict_dismiss"
+
+	// facialConflictBlockReason marks participants blocked because a conflict was confirmed.
+	facialConflictBlockReason = "facial conflict confirmed"
+)
+
+var (
+	// ErrFacialConflictNotFound indicates the requested facial conflict does not exist.
+	ErrFacialConflictNotFound = errors.New("facial conflict not found")
+	// ErrFacialConflictNotPending signals the conflict was already confirmed or dismissed.
+	ErrFacialConflictNotPending = errors.New("facial conflict is not pending")
+)
+
+// FacialConflictService records attempts whose selfie matched another
+// participant's enrolled face and lets fraud reviewers decide them.
+type FacialConflictService struct {
+	conflicts    repository.FacialConflictRepository
+	participants repository.ParticipantRepository
+	audit        repository.AuditLogRepository
+	tx           repository.Transactor
+	events       events.Publisher
+}
+
+// NewFacialConflictService wires dependencies for facial conflicts.
+func NewFacialConflictService(conflicts repository.FacialConflictRepository, participants repository.ParticipantRepository, audit repository.AuditLogRepository, tx repository.Transactor, publisher events.Publisher) *FacialConflictService {
+	return &FacialConflictService{
+		conflicts:    conflicts,
+		participants: participants,
+		audit:        audit,
+		tx:           tx,
+		events:       publisher,
+	}
+}
+
+// DecideFacialConflictInput carries the reviewer's notes.
+type DecideFacialConflictInput struct {
+	Notes string `json:"notes"`
+}
+
+// FacialConflictListOutput is a page of facial conflicts.
+type FacialConflictListOutput struct {
+	Items    []domain.FacialConflict `json:"items"`
+	Page     int                     `json:"page"`
+	PageSize int                     `json:"page_size"`
+	Total    int64                   `json:"total"`
+}
+
+// record stores a pending conflict for an attempt and alerts fraud reviewers
+// through the outbox. It runs inside the transaction that stores the attempt.
+func (s *FacialConflictService) record(ctx context.Context, record *domain.LifeCertificate, matched *domain.FRIdentity, similarity float64) error {
+	conflict := &domain.FacialConflict{
+		ID:                   uuid.NewString(),
+		CertificateID:        record.ID,
+		ParticipantID:        record.ParticipantID,
+		MatchedParticipantID: matched.ParticipantID,
+		Label:                matched.Label,
+		Distance:             record.Distance,
+		Similarity:           similarity,
+		State:                domain.FacialConflictPending,
+		DetectedAt:           record.VerifiedAt,
+	}
+	if err := s.conflicts.Create(ctx, conflict); err != nil {
+		return err
+	}
+	details := map[string]interface{}{
+		"conflict_id":            conflict.ID,
+		"certificate_id":         conflict.CertificateID,
+		"participant_id":         conflict.ParticipantID,
+		"matched_participant_id": conflict.MatchedParticipantID,
+		"label":                  conflict.Label,
+		"distance":               conflict.Distance,
+		"similarity":             conflict.Similarity,
+	}
+	if err := recordAudit(ctx, s.audit, systemActor, auditActionFacialConflict, auditEntityParticipant, conflict.ParticipantID, details); err != nil {
+		return err
+	}
+	metrics.FacialConflictDetected()
+	return publishEvent(ctx, s.events, events.TypeAlertTriggered, map[string]interface{}{
+		"kind":     "facial_conflict",
+		"severity": alertSeverityCritical,
+		"message": fmt.Sprintf("selfie submitted for participant %s matches the face of participant %s; review facial conflict %s",
+			conflict.ParticipantID, conflict.MatchedParticipantID, conflict.ID),
+		"details": details,
+	})
+}
+
+// List returns conflicts filtered by state (PENDING, CONFIRMED, DISMISSED or empty for all).
+func (s *FacialConflictService) List(ctx context.Context, state string, pageNum, pageSize int) (*FacialConflictListOutput, error) {
+	filter := domain.FacialConflictState(strings.ToUpper(strings.TrimSpace(state)))
+	switch filter {
+	case "", domain.FacialConflictPending, domain.FacialConflictConfirmed, domain.FacialConflictDismissed:
+	default:
+		return nil, fmt.Errorf("state must be one of PENDING, CONFIRMED, DISMISSED")
+	}
+
+	page := normalizePagination(pageNum, pageSize)
+	items, total, err := s.conflicts.List(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+	return &FacialConflictListOutput{Items: items, Page: page.Page, PageSize: page.PageSize, Total: total}, nil
+}
+
+// Confirm upholds a pending conflict as impersonation and blocks the
+// participant the attempt was submitted for, unless already blocked.
+func (s *FacialConflictService) Confirm(ctx context.Context, actor, conflictID string, input DecideFacialConflictInput) (*domain.FacialConflict, error) {
+	conflict, err := s.pending(ctx, conflictID)
+	if err != nil {
+		return nil, err
+	}
+	stampFacialConflictDecision(conflict, domain.FacialConflictConfirmed, actor, input)
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		ok, err := s.conflicts.Decide(ctx, conflict)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrFacialConflictNotPending
+		}
+		blocked := false
+		participant, err := s.participants.GetByID(ctx, conflict.ParticipantID)
+		if err != nil {
+			return err
+		}
+		if participant != nil && participant.Status != domain.ParticipantStatusBlocked {
+			now := time.Now().UTC()
+			reason := facialConflictBlockReason
+			participant.Status = domain.ParticipantStatusBlocked
+			participant.StatusReason = &reason
+			participant.StatusChangedAt = &now
+			participant.UpdatedAt = now
+			if err := s.participants.UpdateStatus(ctx, participant); err != nil {
+				return err
+			}
+			blocked = true
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionFacialConflictConfirm, auditEntityParticipant, conflict.ParticipantID, map[string]interface{}{
+			"conflict_id":         conflict.ID,
+			"participant_blocked": blocked,
+			"notes":               conflict.DecisionNotes,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return conflict, nil
+}
+
+// Dismiss closes a pending conflict as a false match, e.g. twins; notes are required.
+func (s *FacialConflictService) Dismiss(ctx context.Context, actor, conflictID string, input DecideFacialConflictInput) (*domain.FacialConflict, error) {
+	if strings.TrimSpace(input.Notes) == "" {
+		return nil, fmt.Errorf("notes are required when dismissing")
+	}
+	conflict, err := s.pending(ctx, conflictID)
+	if err != nil {
+		return nil, err
+	}
+	stampFacialConflictDecision(conflict, domain.FacialConflictDismissed, actor, input)
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		ok, err := s.conflicts.Decide(ctx, conflict)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrFacialConflictNotPending
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionFacialConflictDismiss, auditEntityParticipant, conflict.ParticipantID, map[string]interface{}{
+			"conflict_id": conflict.ID,
+			"notes":       conflict.DecisionNotes,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return conflict, nil
+}
+
+func (s *FacialConflictService) pending(ctx context.Context, conflictID string) (*domain.FacialConflict, error) {
+	conflict, err := s.conflicts.GetByID(ctx, strings.TrimSpace(conflictID))
+	if err != nil {
+		return nil, err
+	}
+	if conflict == nil {
+		return nil, ErrFacialConflictNotFound
+	}
+	if conflict.State != domain.FacialConflictPending {
+		return nil, ErrFacialConflictNotPending
+	}
+	return conflict, nil
+}
+
+func stampFacialConflictDecision(conflict *domain.FacialConflict, state domain.FacialConflictState, actor string, input DecideFacialConflictInput) {
+	now := time.Now().UTC()
+	conflict.State = state
+	conflict.DecidedBy = &actor
+	conflict.DecidedAt = &now
+	if notes := strings.TrimSpace(input.Notes); notes != "" {
+		conflict.DecisionNotes = &notes
+	}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+)
+
+// Weights of the fraud signals in an attempt's risk score, which is capped at 100.
+const (
+	riskWeightDevice           = 40
+	riskWeightNetwork          = 30
+	riskWeightSharedSelfie     = 60
+	riskWeightImpossibleTravel = 50
+)
+
+// minTravelMeters ignores jumps between positions closer than this, which
+// GPS and browser geolocation errors alone can explain.
+const minTravelMeters = 10000
+
+// FraudCheck scores automatic attempts for patterns of fraud rings: many
+// participants verified from one device or IP address, the same selfie
+// submitted for different participants, and positions a participant could
+// not have travelled between.
+type FraudCheck struct {
+	Enabled bool
+	// Window is how far back attempts from the same device or IP address count.
+	Window time.Duration
+	// MaxParticipantsPerSource is how many participants may verify from one
+	// device or IP address within Window before it counts as a signal.
+	MaxParticipantsPerSource int
+	// MaxTravelSpeedKMH is the fastest plausible travel between two geotagged attempts.
+	MaxTravelSpeedKMH float64
+	// ReviewScore is the risk score from which attempts go to review.
+	ReviewScore int
+}
+
+// fraudSource is where an attempt was submitted from and what it showed.
+type fraudSource struct {
+	ParticipantID string
+	DeviceID      string
+	ClientIP      string
+	SelfieHash    string
+	// Latitude and Longitude are the attempt's declared position, else its EXIF one.
+	Latitude  *float64
+	Longitude *float64
+}
+
+// fraudAssessment is an attempt's risk score and the signals behind it.
+type fraudAssessment struct {
+	Score   int
+	Signals []string
+}
+
+// apply stores the score and signals on the attempt's certificate.
+func (a *fraudAssessment) apply(record *domain.LifeCertificate) {
+	if a == nil {
+		return
+	}
+	score := a.Score
+	record.RiskScore = &score
+	if len(a.Signals) > 0 {
+		signals := strings.Join(a.Signals, "; ")
+		record.RiskSignals = &signals
+	}
+}
+
+// review reports whether the attempt is risky enough to need a reviewer.
+func (c FraudCheck) review(assessment *fraudAssessment) bool {
+	return assessment != nil && c.ReviewScore > 0 && assessment.Score >= c.ReviewScore
+}
+
+// assess scores an attempt made at now against the attempts already stored.
+// Sources the attempt did not provide, such as a device ID, are not scored.
+func (c FraudCheck) assess(ctx context.Context, certificates repository.LifeCertificateRepository, source fraudSource, now time.Time) (*fraudAssessment, error) {
+	if !c.Enabled {
+		return nil, nil
+	}
+	assessment := &fraudAssessment{}
+	add := func(weight int, signal string) {
+		assessment.Score += weight
+		assessment.Signals = append(assessment.Signals, signal)
+	}
+
+	since := now.Add(-c.Window)
+	if source.DeviceID != "" {
+		others, err := certificates.CountOtherParticipantsByDeviceSince(ctx, source.DeviceID, source.ParticipantID, since)
+		if err != nil {
+			return nil, err
+		}
+		if others >= int64(c.MaxParticipantsPerSource) {
+			add(riskWeightDevice, fmt.Sprintf("%s verified from device %s within %s", otherParticipants(others), source.DeviceID, c.Window))
+		}
+	}
+	if source.ClientIP != "" {
+		others, err := certificates.CountOtherParticipantsByClientIPSince(ctx, source.ClientIP, source.ParticipantID, since)
+		if err != nil {
+			return nil, err
+		}
+		if others >= int64(c.MaxParticipantsPerSource) {
+			add(riskWeightNetwork, fmt.Sprintf("%s verified from IP address %s within %s", otherParticipants(others), source.ClientIP, c.Window))
+		}
+	}
+	if source.SelfieHash != "" {
+		others, err := certificates.CountOtherParticipantsBySelfieHash(ctx, source.SelfieHash, source.ParticipantID)
+		if err != nil {
+			return nil, err
+		}
+		if others > 0 {
+			add(riskWeightSharedSelfie, "same selfie submitted for "+otherParticipants(others))
+		}
+	}
+	if source.Latitude != nil && source.Longitude != nil {
+		previous, err := certificates.GetLatestPositionedByParticipant(ctx, source.ParticipantID)
+		if err != nil {
+			return nil, err
+		}
+		if signal := c.travel(previous, *source.Latitude, *source.Longitude, now); signal != "" {
+			add(riskWeightImpossibleTravel, signal)
+		}
+	}
+
+	if assessment.Score > 100 {
+		assessment.Score = 100
+	}
+	return assessment, nil
+}
+
+// travel describes the jump from the previous attempt's position to this one
+// when it would take travelling faster than MaxTravelSpeedKMH.
+func (c FraudCheck) travel(previous *domain.LifeCertificate, latitude, longitude float64, now time.Time) string {
+	if previous == nil {
+		return ""
+	}
+	fromLatitude, fromLongitude := previous.Latitude, previous.Longitude
+	if fromLatitude == nil || fromLongitude == nil {
+		fromLatitude, fromLongitude = previous.CaptureLatitude, previous.CaptureLongitude
+	}
+	if fromLatitude == nil || fromLongitude == nil {
+		return ""
+	}
+	distance := haversineMeters(*fromLatitude, *fromLongitude, latitude, longitude)
+	if distance < minTravelMeters {
+		return ""
+	}
+	elapsed := now.Sub(previous.VerifiedAt)
+	if elapsed > 0 && distance/1000/elapsed.Hours() <= c.MaxTravelSpeedKMH {
+		return ""
+	}
+	return fmt.Sprintf("%.0f km from the position of certificate %s %s earlier", distance/1000, previous.ID, elapsed.Round(time.Minute))
+}
+
+func otherParticipants(n int64) string {
+	if n == 1 {
+		return "1 other participant"
+	}
+	return fmt.Sprintf("%d other participants", n)
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/events"
+	"life-certificates/internal/payroll"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/tenant"
+)
+
+const (
+	holdReleasePollInterval = 5 * time.Second
+	holdReleaseBatchSize    = 50
+	holdReleaseLease        = 2 * time.Minute
+	holdReleaseBaseBackoff  = time.Minute
+	holdReleaseMaxBackoff   = 6 * time.Hour
+	auditEntityHoldRelease  = "hold_release"
+	auditActionHoldRetry    = "hold_release.retry"
+	auditActionHoldResend   = "hold_release.resend"
+)
+
+var (
+	// ErrHoldReleaseNotFound indicates the requested hold release does not exist.
+	ErrHoldReleaseNotFound = errors.New("hold release not found")
+	// ErrHoldReleaseNotFailed signals only FAILED releases can be retried or re-sent.
+	ErrHoldReleaseNotFailed = errors.New("hold release is not failed")
+)
+
+// HoldReleaseService calls the payment system when a certificate turns VALID
+// so it releases the participant's pension payment hold, retrying until the
+// payment system acknowledges the release.
+type HoldReleaseService struct {
+	releases       repository.HoldReleaseRepository
+	participants   repository.ParticipantRepository
+	certificates   repository.LifeCertificateRepository
+	audit          repository.AuditLogRepository
+	client         payroll.HoldReleaser
+	template       *payroll.HoldReleaseTemplate
+	validityMonths int
+	maxAttempts    int
+}
+
+// NewHoldReleaseService wires dependencies for hold release callbacks.
+func NewHoldReleaseService(releases repository.HoldReleaseRepository, participants repository.ParticipantRepository, certificates repository.LifeCertificateRepository, audit repository.AuditLogRepository, client payroll.HoldReleaser, template *payroll.HoldReleaseTemplate, validityMonths, maxAttempts int) *HoldReleaseService {
+	return &HoldReleaseService{
+		releases:       releases,
+		participants:   participants,
+		certificates:   certificates,
+		audit:          audit,
+		client:         client,
+		template:       template,
+		validityMonths: validityMonths,
+		maxAttempts:    maxAttempts,
+	}
+}
+
+// HoldReleaseListOutput is a page of hold releases.
+type HoldReleaseListOutput struct {
+	Items    []domain.HoldRelease `json:"items"`
+	Page     int                  `json:"page"`
+	PageSize int                  `json:"page_size"`
+	Total    int64                `json:"total"`
+}
+
+// Publish queues a hold release when a certificate is verified as VALID.
+// A certificate already queued is ignored, so redelivery from the outbox is safe.
+func (s *HoldReleaseService) Publish(ctx context.Context, event events.Event) error {
+	if event.Type != events.TypeVerificationCompleted || event.Data["status"] != string(domain.LifeCertificateStatusValid) {
+		return nil
+	}
+	certificateID, _ := event.Data["certificate_id"].(string)
+	record, err := s.certificates.GetByID(ctx, certificateID)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return nil
+	}
+	participant, err := s.participants.GetByID(ctx, record.ParticipantID)
+	if err != nil {
+		return err
+	}
+	if participant == nil {
+		return nil
+	}
+
+	payload, err := s.template.Render(payroll.HoldReleaseData{
+		ParticipantID: participant.ID,
+		NIK:           participant.NIK,
+		MemberID:      participant.MemberID,
+		Fund:          participant.Fund,
+		CertificateID: record.ID,
+		Method:        string(record.Method),
+		VerifiedAt:    record.VerifiedAt,
+		ValidUntil:    record.VerifiedAt.AddDate(0, s.validityMonths, 0),
+	})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	return s.releases.Create(ctx, &domain.HoldRelease{
+		ID:            uuid.NewString(),
+		ParticipantID: participant.ID,
+		CertificateID: record.ID,
+		Payload:       string(payload),
+		Status:        domain.HoldReleasePending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	})
+}
+
+// Start runs the release dispatcher until ctx is cancelled.
+func (s *HoldReleaseService) Start(ctx context.Context) {
+	go func() {
+		poll := time.NewTicker(holdReleasePollInterval)
+		defer poll.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-poll.C:
+				s.dispatchDue(ctx)
+			}
+		}
+	}()
+}
+
+// List returns releases filtered by status (PENDING, ACKNOWLEDGED, FAILED or empty for all).
+func (s *HoldReleaseService) List(ctx context.Context, status string, pageNum, pageSize int) (*HoldReleaseListOutput, error) {
+	filter := domain.HoldReleaseStatus(strings.ToUpper(strings.TrimSpace(status)))
+	switch filter {
+	case "", domain.HoldReleasePending, domain.HoldReleaseAcknowledged, domain.HoldReleaseFailed:
+	default:
+		return nil, fmt.Errorf("status must be one of PENDING, ACKNOWLEDGED, FAILED")
+	}
+
+	page := normalizePagination(pageNum, pageSize)
+	items, total, err := s.releases.List(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+	return &HoldReleaseListOutput{Items: items, Page: page.Page, PageSize: page.PageSize, Total: total}, nil
+}
+
+// Retry requeues a FAILED release with a fresh attempt budget for the dispatcher.
+func (s *HoldReleaseService) Retry(ctx context.Context, actor, id string) (*domain.HoldRelease, error) {
+	release, err := s.failed(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := s.releases.Requeue(ctx, release.ID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrHoldReleaseNotFailed
+	}
+
+	if err := recordAudit(ctx, s.audit, actor, auditActionHoldRetry, auditEntityHoldRelease, release.ID, map[string]interface{}{
+		"certificate_id": release.CertificateID,
+		"attempts":       release.Attempts,
+		"last_error":     release.LastError,
+	}); err != nil {
+		return nil, err
+	}
+	return s.releases.GetByID(ctx, release.ID)
+}
+
+// Resend sends a FAILED release once, right away, and returns the outcome. An
+// unacknowledged re-send leaves the release FAILED with the new error.
+func (s *HoldReleaseService) Resend(ctx context.Context, actor, id string) (*domain.HoldRelease, error) {
+	release, err := s.failed(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := s.releases.Reserve(ctx, release.ID, time.Now().UTC().Add(holdReleaseLease))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrHoldReleaseNotFailed
+	}
+	s.attempt(ctx, release, true)
+
+	if err := recordAudit(ctx, s.audit, actor, auditActionHoldResend, auditEntityHoldRelease, release.ID, map[string]interface{}{
+		"certificate_id": release.CertificateID,
+		"status":         release.Status,
+		"response_code":  release.ResponseCode,
+		"last_error":     release.LastError,
+	}); err != nil {
+		return nil, err
+	}
+	return release, nil
+}
+
+func (s *HoldReleaseService) failed(ctx context.Context, id string) (*domain.HoldRelease, error) {
+	release, err := s.releases.GetByID(ctx, strings.TrimSpace(id))
+	if err != nil {
+		return nil, err
+	}
+	if release == nil {
+		return nil, ErrHoldReleaseNotFound
+	}
+	if release.Status != domain.HoldReleaseFailed {
+		return nil, ErrHoldReleaseNotFailed
+	}
+	return release, nil
+}
+
+func (s *HoldReleaseService) dispatchDue(ctx context.Context) {
+	now := time.Now().UTC()
+	due, err := s.releases.ListDue(tenant.System(ctx), now, holdReleaseBatchSize)
+	if err != nil {
+		log.Printf("[hold-release] list due releases: %v", err)
+		return
+	}
+
+	for i := range due {
+		release := &due[i]
+		ctx := tenant.WithID(ctx, release.TenantID)
+		ok, err := s.releases.Claim(ctx, release.ID, release.NextAttemptAt, now.Add(holdReleaseLease))
+		if err != nil {
+			log.Printf("[hold-release] claim release %s: %v", release.ID, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		s.attempt(ctx, release, false)
+	}
+}
+
+// attempt sends the release and records the outcome. A manual attempt that is
+// not acknowledged fails the release at once instead of scheduling a retry.
+func (s *HoldReleaseService) attempt(ctx context.Context, release *domain.HoldRelease, manual bool) {
+	ack, err := s.client.Release(ctx, []byte(release.Payload))
+
+	release.Attempts++
+	now := time.Now().UTC()
+	if ack != nil {
+		release.ResponseCode = &ack.StatusCode
+		release.ResponseBody = &ack.Body
+	}
+	switch {
+	case err == nil:
+		release.Status = domain.HoldReleaseAcknowledged
+		release.AcknowledgedAt = &now
+		release.LastError = nil
+		if ack.Reference != "" {
+			release.AckReference = &ack.Reference
+		}
+	case manual || release.Attempts >= s.maxAttempts:
+		msg := err.Error()
+		release.Status = domain.HoldReleaseFailed
+		release.LastError = &msg
+	default:
+		msg := err.Error()
+		release.Status = domain.HoldReleasePending
+		release.LastError = &msg
+		release.NextAttemptAt = now.Add(exponentialBackoff(holdReleaseBaseBackoff, holdReleaseMaxBackoff, release.Attempts))
+	}
+
+	if err := s.releases.Update(ctx, release); err != nil {
+		log.Printf("[hold-release] update release %s: %v", release.ID, err)
+	}
+}
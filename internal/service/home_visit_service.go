@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+)
+
+const (
+	auditEntityHomeVisit         = "home_visit"
+	auditActionHomeVisitRequest  = "home_visit.request"
+	auditActionHomeVisitSchedule = "home_visit.schedule"
+	auditActionHomeVisitMissed   = "home_visit.missed"
+	auditActionHomeVisitCancel   = "home_visit.cancel"
+
+	maxHomeVisitTextLength = 500
+)
+
+var (
+	// ErrHomeVisitNotFound indicates the requested home visit does not exist.
+	ErrHomeVisitNotFound = errors.New("home visit not found")
+	// ErrHomeVisitOpen signals the participant already has a visit requested or scheduled.
+	ErrHomeVisitOpen = errors.New("participant already has an open home visit")
+	// ErrHomeVisitNotScheduled signals the visit is not in a state allowing the change.
+	ErrHomeVisitNotScheduled = errors.New("home visit is not scheduled")
+	// ErrHomeVisitClosed signals the visit was already completed, missed or cancelled.
+	ErrHomeVisitClosed = errors.New("home visit is closed")
+)
+
+// HomeVisitService schedules officers' visits to participants who cannot
+// verify remotely. A visit is completed by the manual or proxy verification
+// recorded on it.
+type HomeVisitService struct {
+	visits       repository.HomeVisitRepository
+	participants repository.ParticipantRepository
+	members      repository.MemberRepository
+	officers     repository.OfficerRepository
+	audit        repository.AuditLogRepository
+	tx           repository.Transactor
+}
+
+// NewHomeVisitService wires dependencies for home visits.
+func NewHomeVisitService(visits repository.HomeVisitRepository, participants repository.ParticipantRepository, members repository.MemberRepository, officers repository.OfficerRepository, audit repository.AuditLogRepository, tx repository.Transactor) *HomeVisitService {
+	return &HomeVisitService{visits: visits, participants: participants, members: members, officers: officers, audit: audit, tx: tx}
+}
+
+// RequestHomeVisitInput asks for a visit within a slot. Without an address
+// the linked member's is used.
+type RequestHomeVisitInput struct {
+	ParticipantID  string `json:"participant_id"`
+	Address        string `json:"address"`
+	Reason         string `json:"reason"`
+	RequestedStart string `json:"requested_start"`
+	RequestedEnd   string `json:"requested_end"`
+}
+
+// ScheduleHomeVisitInput assigns the visit to a registered officer. The
+// branch defaults to the officer's.
+type ScheduleHomeVisitInput struct {
+	ScheduledAt string `json:"scheduled_at"`
+	OfficerID   string `json:"officer_id"`
+	BranchID    string `json:"branch_id"`
+}
+
+// CloseHomeVisitInput explains why a visit was missed or cancelled.
+type CloseHomeVisitInput struct {
+	Notes string `json:"notes"`
+}
+
+// HomeVisitListInput filters and pages home visits; From and To are
+// YYYY-MM-DD bounds of the scheduled date.
+type HomeVisitListInput struct {
+	ParticipantID string
+	Status        string
+	OfficerID     string
+	BranchID      string
+	From          string
+	To            string
+	Page          int
+	PageSize      int
+}
+
+// HomeVisitListOutput is a page of home visits.
+type HomeVisitListOutput struct {
+	Items    []domain.HomeVisit `json:"items"`
+	Page     int                `json:"page"`
+	PageSize int                `json:"page_size"`
+	Total    int64              `json:"total"`
+}
+
+// Request records a REQUESTED visit. A participant has at most one visit
+// requested or scheduled at a time.
+func (s *HomeVisitService) Request(ctx context.Context, actor string, input RequestHomeVisitInput) (*domain.HomeVisit, error) {
+	now := time.Now().UTC()
+	participantID := strings.TrimSpace(input.ParticipantID)
+	address := strings.TrimSpace(input.Address)
+	reason := strings.TrimSpace(input.Reason)
+
+	verr := &ValidationError{}
+	if participantID == "" {
+		verr.add("participant_id", "is required")
+	}
+	if len(address) > maxHomeVisitTextLength {
+		verr.add("address", "must be at most 500 characters")
+	}
+	switch {
+	case reason == "":
+		verr.add("reason", "is required")
+	case len(reason) > maxHomeVisitTextLength:
+		verr.add("reason", "must be at most 500 characters")
+	}
+	start := parseVisitTime("requested_start", input.RequestedStart, verr)
+	end := parseVisitTime("requested_end", input.RequestedEnd, verr)
+	if start != nil && end != nil {
+		switch {
+		case !end.After(*start):
+			verr.add("requested_end", "must be after requested_start")
+		case end.Before(now):
+			verr.add("requested_end", "must not be in the past")
+		}
+	}
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	participant, err := s.participants.GetByID(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return nil, ErrParticipantNotFound
+	}
+	switch participant.Status {
+	case domain.ParticipantStatusSuspended:
+		return nil, ErrParticipantSuspended
+	case domain.ParticipantStatusBlocked:
+		return nil, ErrParticipantBlocked
+	}
+	if address == "" && participant.MemberID != nil {
+		member, err := s.members.GetByID(ctx, *participant.MemberID)
+		if err != nil {
+			return nil, err
+		}
+		if member != nil {
+			address = memberAddress(member)
+		}
+	}
+	if address == "" {
+		return nil, &ValidationError{Fields: map[string]string{"address": "is required when the participant has no member address"}}
+	}
+
+	visit := &domain.HomeVisit{
+		ID:             uuid.NewString(),
+		ParticipantID:  participant.ID,
+		Status:         domain.HomeVisitRequested,
+		Address:        address,
+		Reason:         reason,
+		RequestedStart: *start,
+		RequestedEnd:   *end,
+		RequestedBy:    actor,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		open, err := s.visits.FindOpen(ctx, participant.ID)
+		if err != nil {
+			return err
+		}
+		if open != nil {
+			return ErrHomeVisitOpen
+		}
+		if err := s.visits.Create(ctx, visit); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionHomeVisitRequest, auditEntityHomeVisit, visit.ID, map[string]interface{}{
+			"participant_id":  participant.ID,
+			"requested_start": visit.RequestedStart,
+			"requested_end":   visit.RequestedEnd,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return visit, nil
+}
+
+// Schedule assigns a requested visit, or reschedules a scheduled one.
+func (s *HomeVisitService) Schedule(ctx context.Context, actor, id string, input ScheduleHomeVisitInput) (*domain.HomeVisit, error) {
+	verr := &ValidationError{}
+	scheduledAt := parseVisitTime("scheduled_at", input.ScheduledAt, verr)
+	if scheduledAt != nil && scheduledAt.Before(time.Now().UTC()) {
+		verr.add("scheduled_at", "must not be in the past")
+	}
+	officerID := strings.TrimSpace(input.OfficerID)
+	if officerID == "" {
+		verr.add("officer_id", "is required")
+	}
+	branchID := optionalString(&input.BranchID)
+	if branchID != nil && len(*branchID) > 64 {
+		verr.add("branch_id", "must be at most 64 characters")
+	}
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+	officer, err := performingOfficer(ctx, s.officers, officerID, *scheduledAt)
+	if err != nil {
+		return nil, err
+	}
+	if branchID == nil {
+		branchID = &officer.BranchID
+	}
+
+	visit, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !visit.Open() {
+		return nil, ErrHomeVisitClosed
+	}
+	previous := visit.ScheduledAt
+	visit.Status = domain.HomeVisitScheduled
+	visit.ScheduledAt = scheduledAt
+	visit.OfficerID = &officer.ID
+	visit.OfficerName = &officer.Name
+	visit.BranchID = branchID
+	err = s.update(ctx, actor, visit, auditActionHomeVisitSchedule, map[string]interface{}{
+		"previous_scheduled_at": previous,
+		"scheduled_at":          scheduledAt,
+		"officer_id":            officer.ID,
+		"branch_id":             branchID,
+	}, ErrHomeVisitClosed, domain.HomeVisitRequested, domain.HomeVisitScheduled)
+	if err != nil {
+		return nil, err
+	}
+	return visit, nil
+}
+
+// Missed records that the officer could not carry out a scheduled visit.
+func (s *HomeVisitService) Missed(ctx context.Context, actor, id string, input CloseHomeVisitInput) (*domain.HomeVisit, error) {
+	return s.close(ctx, actor, id, input, domain.HomeVisitMissed, auditActionHomeVisitMissed, ErrHomeVisitNotScheduled, domain.HomeVisitScheduled)
+}
+
+// Cancel withdraws a requested or scheduled visit.
+func (s *HomeVisitService) Cancel(ctx context.Context, actor, id string, input CloseHomeVisitInput) (*domain.HomeVisit, error) {
+	return s.close(ctx, actor, id, input, domain.HomeVisitCancelled, auditActionHomeVisitCancel, ErrHomeVisitClosed, domain.HomeVisitRequested, domain.HomeVisitScheduled)
+}
+
+// Get returns a home visit.
+func (s *HomeVisitService) Get(ctx context.Context, id string) (*domain.HomeVisit, error) {
+	return s.get(ctx, id)
+}
+
+// List pages through home visits, by scheduled time.
+func (s *HomeVisitService) List(ctx context.Context, input HomeVisitListInput) (*HomeVisitListOutput, error) {
+	filter := repository.HomeVisitFilter{
+		ParticipantID: strings.TrimSpace(input.ParticipantID),
+		Status:        domain.HomeVisitStatus(strings.ToUpper(strings.TrimSpace(input.Status))),
+		OfficerID:     strings.TrimSpace(input.OfficerID),
+		BranchID:      strings.TrimSpace(input.BranchID),
+	}
+	verr := &ValidationError{}
+	switch filter.Status {
+	case "", domain.HomeVisitRequested, domain.HomeVisitScheduled, domain.HomeVisitCompleted, domain.HomeVisitMissed, domain.HomeVisitCancelled:
+	default:
+		verr.add("status", "must be REQUESTED, SCHEDULED, COMPLETED, MISSED or CANCELLED")
+	}
+	filter.From, filter.To = parseDateRange(verr, input.From, input.To)
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+	page := normalizePagination(input.Page, input.PageSize)
+	items, total, err := s.visits.List(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+	return &HomeVisitListOutput{Items: items, Page: page.Page, PageSize: page.PageSize, Total: total}, nil
+}
+
+func (s *HomeVisitService) close(ctx context.Context, actor, id string, input CloseHomeVisitInput, status domain.HomeVisitStatus, action string, conflict error, from ...domain.HomeVisitStatus) (*domain.HomeVisit, error) {
+	notes := strings.TrimSpace(input.Notes)
+	if notes == "" {
+		return nil, &ValidationError{Fields: map[string]string{"notes": "is required"}}
+	}
+	visit, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	previous := visit.Status
+	visit.Status = status
+	visit.Notes = &notes
+	err = s.update(ctx, actor, visit, action, map[string]interface{}{
+		"from_status": previous,
+		"notes":       notes,
+	}, conflict, from...)
+	if err != nil {
+		return nil, err
+	}
+	return visit, nil
+}
+
+// update stores the visit if it is still in one of the from statuses,
+// failing with conflict otherwise.
+func (s *HomeVisitService) update(ctx context.Context, actor string, visit *domain.HomeVisit, action string, details map[string]interface{}, conflict error, from ...domain.HomeVisitStatus) error {
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		ok, err := s.visits.Update(ctx, visit, from...)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return conflict
+		}
+		return recordAudit(ctx, s.audit, actor, action, auditEntityHomeVisit, visit.ID, details)
+	})
+}
+
+func (s *HomeVisitService) get(ctx context.Context, id string) (*domain.HomeVisit, error) {
+	visit, err := s.visits.GetByID(ctx, strings.TrimSpace(id))
+	if err != nil {
+		return nil, err
+	}
+	if visit == nil {
+		return nil, ErrHomeVisitNotFound
+	}
+	return visit, nil
+}
+
+// memberAddress joins the member's street address and city.
+func memberAddress(member *domain.Member) string {
+	var parts []string
+	for _, part := range []string{member.Address, member.City} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// parseVisitTime parses a required RFC 3339 time.
+func parseVisitTime(field, raw string, verr *ValidationError) *time.Time {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		verr.add(field, "is required")
+		return nil
+	}
+	parsed, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		verr.add(field, "must be an RFC 3339 time")
+		return nil
+	}
+	parsed = parsed.UTC()
+	return &parsed
+}
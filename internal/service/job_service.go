@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/tenant"
+)
+
+const (
+	jobBaseBackoff = 30 * time.Second
+	jobMaxBackoff  = time.Hour
+)
+
+// Audit vocabulary for job administration.
+const (
+	auditEntityJob       = "job"
+	auditActionJobRetry  = "job.retry"
+	auditActionJobCancel = "job.cancel"
+)
+
+var (
+	// ErrJobNotFound indicates the requested job does not exist.
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobNotRetryable signals only FAILED or CANCELLED jobs can be retried.
+	ErrJobNotRetryable = errors.New("job is not failed or cancelled")
+	// ErrJobNotCancellable signals only QUEUED or RUNNING jobs can be cancelled.
+	ErrJobNotCancellable = errors.New("job is not queued or running")
+)
+
+type finalAttemptKey struct{}
+
+// JobHandler runs one attempt of a job. The payload is the JSON given to
+// Enqueue; a non-nil result is stored as JSON on success. Handlers must stop
+// when ctx is cancelled and should be safe to run again after a failure.
+type JobHandler func(ctx context.Context, payload json.RawMessage) (interface{}, error)
+
+// JobOptions sizes the worker pool and its retry policy.
+type JobOptions struct {
+	Workers      int
+	PollInterval time.Duration
+	// MaxAttempts is the default attempt budget for new jobs.
+	MaxAttempts int
+	// Timeout bounds one attempt; it is also the lease after which a job held
+	// by a crashed worker is picked up again.
+	Timeout time.Duration
+}
+
+// JobService persists background jobs and runs them on a pool of workers
+// shared by every replica. Failed attempts are retried with exponential
+// backoff until the job's attempts run out.
+type JobService struct {
+	jobs     repository.JobRepository
+	audit    repository.AuditLogRepository
+	options  JobOptions
+	handlers map[string]JobHandler
+
+	mu      sync.Mutex
+	running map[string]context.CancelFunc
+}
+
+// JobListInput filters and pages the job listing.
+type JobListInput struct {
+	Type     string
+	Status   string
+	Page     int
+	PageSize int
+}
+
+// JobListOutput is a page of jobs, latest first.
+type JobListOutput struct {
+	Items    []domain.Job `json:"items"`
+	Page     int          `json:"page"`
+	PageSize int          `json:"page_size"`
+	Total    int64        `json:"total"`
+}
+
+// NewJobService wires dependencies for the job subsystem.
+func NewJobService(jobs repository.JobRepository, audit repository.AuditLogRepository, options JobOptions) *JobService {
+	return &JobService{
+		jobs:     jobs,
+		audit:    audit,
+		options:  options,
+		handlers: make(map[string]JobHandler),
+		running:  make(map[string]context.CancelFunc),
+	}
+}
+
+// Register installs the handler for a job type. Call it before Start.
+func (s *JobService) Register(jobType string, handler JobHandler) {
+	s.handlers[jobType] = handler
+}
+
+// Enqueue stores a job using the transaction carried by ctx, so work can be
+// queued atomically with the change that needs it.
+func (s *JobService) Enqueue(ctx context.Context, actor, jobType string, payload interface{}) (*domain.Job, error) {
+	return s.EnqueueAt(ctx, actor, jobType, payload, time.Now().UTC())
+}
+
+// EnqueueAt queues a job that workers pick up no earlier than runAt.
+func (s *JobService) EnqueueAt(ctx context.Context, actor, jobType string, payload interface{}, runAt time.Time) (*domain.Job, error) {
+	if _, ok := s.handlers[jobType]; !ok {
+		return nil, fmt.Errorf("no handler for job type %q", jobType)
+	}
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encode job payload: %w", err)
+	}
+
+	now := time.Now().UTC()
+	job := &domain.Job{
+		ID:          uuid.NewString(),
+		Type:        jobType,
+		Status:      domain.JobStatusQueued,
+		Payload:     string(encoded),
+		MaxAttempts: s.options.MaxAttempts,
+		RunAt:       runAt.UTC(),
+		CreatedBy:   actor,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.jobs.Create(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// List returns jobs, latest first.
+func (s *JobService) List(ctx context.Context, input JobListInput) (*JobListOutput, error) {
+	status := domain.JobStatus(strings.ToUpper(strings.TrimSpace(input.Status)))
+	switch status {
+	case "", domain.JobStatusQueued, domain.JobStatusRunning, domain.JobStatusSucceeded, domain.JobStatusFailed, domain.JobStatusCancelled:
+	default:
+		return nil, &ValidationError{Fields: map[string]string{"status": "must be one of QUEUED, RUNNING, SUCCEEDED, FAILED, CANCELLED"}}
+	}
+
+	paging := normalizePagination(input.Page, input.PageSize)
+	items, total, err := s.jobs.List(ctx, repository.JobFilter{Type: strings.TrimSpace(input.Type), Status: status}, paging)
+	if err != nil {
+		return nil, err
+	}
+	return &JobListOutput{Items: items, Page: paging.Page, PageSize: paging.PageSize, Total: total}, nil
+}
+
+// Get returns a single job.
+func (s *JobService) Get(ctx context.Context, id string) (*domain.Job, error) {
+	job, err := s.jobs.GetByID(ctx, strings.TrimSpace(id))
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, ErrJobNotFound
+	}
+	return job, nil
+}
+
+// Retry requeues a FAILED or CANCELLED job with a fresh attempt budget.
+func (s *JobService) Retry(ctx context.Context, actor, id string) (*domain.Job, error) {
+	job, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.jobs.Retry(ctx, job.ID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrJobNotRetryable
+	}
+
+	if err := recordAudit(ctx, s.audit, actor, auditActionJobRetry, auditEntityJob, job.ID, map[string]interface{}{
+		"type":       job.Type,
+		"status":     job.Status,
+		"attempts":   job.Attempts,
+		"last_error": job.LastError,
+	}); err != nil {
+		return nil, err
+	}
+	return s.jobs.GetByID(ctx, job.ID)
+}
+
+// Cancel stops a QUEUED or RUNNING job. A running attempt on this replica is
+// interrupted; one on another replica runs on but its outcome is discarded.
+func (s *JobService) Cancel(ctx context.Context, actor, id string) (*domain.Job, error) {
+	job, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.jobs.Cancel(ctx, job.ID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrJobNotCancellable
+	}
+
+	s.mu.Lock()
+	if cancel, ok := s.running[job.ID]; ok {
+		cancel()
+	}
+	s.mu.Unlock()
+
+	if err := recordAudit(ctx, s.audit, actor, auditActionJobCancel, auditEntityJob, job.ID, map[string]interface{}{
+		"type":     job.Type,
+		"status":   job.Status,
+		"attempts": job.Attempts,
+	}); err != nil {
+		return nil, err
+	}
+	return s.jobs.GetByID(ctx, job.ID)
+}
+
+// Start runs the worker pool until ctx is cancelled.
+func (s *JobService) Start(ctx context.Context) {
+	for i := 0; i < s.options.Workers; i++ {
+		go func() {
+			ticker := time.NewTicker(s.options.PollInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					// Keep draining while work is available instead of waiting a tick per job.
+					for ctx.Err() == nil && s.runNext(ctx) {
+					}
+				}
+			}
+		}()
+	}
+}
+
+// runNext claims and runs one due job, reporting whether it found one.
+func (s *JobService) runNext(ctx context.Context) bool {
+	now := time.Now().UTC()
+	due, err := s.jobs.ListDue(tenant.System(ctx), now, s.options.Workers)
+	if err != nil {
+		log.Printf("[jobs] list due jobs: %v", err)
+		return false
+	}
+	for i := range due {
+		job := &due[i]
+		ctx := tenant.WithID(ctx, job.TenantID)
+		ok, err := s.jobs.Claim(ctx, job, now, now.Add(s.options.Timeout))
+		if err != nil {
+			log.Printf("[jobs] claim job %s: %v", job.ID, err)
+			continue
+		}
+		if ok {
+			s.run(ctx, job)
+			return true
+		}
+	}
+	return false
+}
+
+func (s *JobService) run(ctx context.Context, job *domain.Job) {
+	runCtx, cancel := context.WithTimeout(context.WithValue(ctx, finalAttemptKey{}, job.Attempts >= job.MaxAttempts), s.options.Timeout)
+	s.mu.Lock()
+	s.running[job.ID] = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, job.ID)
+		s.mu.Unlock()
+		cancel()
+	}()
+
+	result, err := s.execute(runCtx, job)
+
+	now := time.Now().UTC()
+	job.UpdatedAt = now
+	switch {
+	case err == nil:
+		job.Status = domain.JobStatusSucceeded
+		job.LastError = nil
+		job.FinishedAt = &now
+		if result != nil {
+			if encoded, marshalErr := json.Marshal(result); marshalErr == nil {
+				output := string(encoded)
+				job.Result = &output
+			}
+		}
+	case job.Attempts >= job.MaxAttempts:
+		message := err.Error()
+		job.Status = domain.JobStatusFailed
+		job.LastError = &message
+		job.FinishedAt = &now
+		log.Printf("[jobs] job %s (%s) failed after %d attempts: %v", job.ID, job.Type, job.Attempts, err)
+	default:
+		message := err.Error()
+		job.Status = domain.JobStatusQueued
+		job.LastError = &message
+		job.RunAt = now.Add(exponentialBackoff(jobBaseBackoff, jobMaxBackoff, job.Attempts))
+		log.Printf("[jobs] job %s (%s) attempt %d: %v", job.ID, job.Type, job.Attempts, err)
+	}
+
+	// The attempt is finished even if the worker is shutting down.
+	ok, err := s.jobs.Finish(context.WithoutCancel(ctx), job)
+	if err != nil {
+		log.Printf("[jobs] record job %s outcome: %v", job.ID, err)
+		return
+	}
+	if !ok {
+		log.Printf("[jobs] job %s was cancelled or reclaimed; outcome discarded", job.ID)
+	}
+}
+
+// finalJobAttempt reports whether the job attempt running with ctx is the last
+// one, so a handler can record a failure that will not be retried.
+func finalJobAttempt(ctx context.Context) bool {
+	final, _ := ctx.Value(finalAttemptKey{}).(bool)
+	return final
+}
+
+// execute runs the handler, converting a panic into a failed attempt.
+func (s *JobService) execute(ctx context.Context, job *domain.Job) (result interface{}, err error) {
+	handler, ok := s.handlers[job.Type]
+	if !ok {
+		// Another replica may know the type; let the attempt count run out.
+		return nil, fmt.Errorf("no handler for job type %q", job.Type)
+	}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("job panicked: %v", recovered)
+		}
+	}()
+	return handler(ctx, json.RawMessage(job.Payload))
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+)
+
+const (
+	// kioskKeyPrefix starts every kiosk device key so leaked keys are recognisable.
+	kioskKeyPrefix = "lck_"
+	// kioskTouchInterval bounds how often a kiosk's last use is written.
+	kioskTouchInterval = time.Minute
+)
+
+// Audit vocabulary for kiosk management.
+const (
+	auditEntityKiosk       = "kiosk"
+	auditActionKioskCreate = "kiosk.create"
+	auditActionKioskRevoke = "kiosk.revoke"
+)
+
+var (
+	// ErrKioskNotFound indicates the requested kiosk does not exist.
+	ErrKioskNotFound = errors.New("kiosk not found")
+	// ErrKioskRevoked indicates the kiosk was already revoked.
+	ErrKioskRevoked = errors.New("kiosk already revoked")
+)
+
+// KioskService manages the shared kiosks in branch offices and runs the
+// operator-assisted flow on them: look the participant up by NIK, capture the
+// selfie and submit it. Every kiosk certificate records the kiosk, its branch
+// and the operator, who must be a registered officer; a submission made
+// outside the branch's geofence goes to review.
+type KioskService struct {
+	kiosks        repository.KioskRepository
+	participants  repository.ParticipantRepository
+	officers      repository.OfficerRepository
+	branches      repository.BranchRepository
+	audit         repository.AuditLogRepository
+	consents      *ConsentService
+	verifications *VerificationService
+}
+
+// NewKioskService wires dependencies for kiosk mode.
+func NewKioskService(kiosks repository.KioskRepository, participants repository.ParticipantRepository, officers repository.OfficerRepository, branches repository.BranchRepository, audit repository.AuditLogRepository, consents *ConsentService, verifications *VerificationService) *KioskService {
+	return &KioskService{kiosks: kiosks, participants: participants, officers: officers, branches: branches, audit: audit, consents: consents, verifications: verifications}
+}
+
+// CreateKioskInput registers a kiosk in a branch.
+type CreateKioskInput struct {
+	Name     string `json:"name"`
+	BranchID string `json:"branch_id"`
+}
+
+// CreatedKiosk returns the device key once, at creation time.
+type CreatedKiosk struct {
+	domain.Kiosk
+	Key string `json:"key"`
+}
+
+// KioskParticipant is what an operator sees after looking up a NIK: enough to
+// confirm the person in front of the kiosk and whether they can verify.
+type KioskParticipant struct {
+	ParticipantID     string `json:"participant_id"`
+	Name              string `json:"name"`
+	ParticipantStatus string `json:"participant_status"`
+	// CertificateStatus is VALID, EXPIRED, NONE or the latest attempt's outcome.
+	CertificateStatus string     `json:"certificate_status"`
+	VerifiedAt        *time.Time `json:"verified_at"`
+	ValidUntil        *time.Time `json:"valid_until"`
+	// ConsentRequired tells the operator to record consent before capturing.
+	ConsentRequired bool `json:"consent_required"`
+}
+
+// Create registers a kiosk and issues its device key.
+func (s *KioskService) Create(ctx context.Context, actor string, input CreateKioskInput) (*CreatedKiosk, error) {
+	verr := &ValidationError{}
+	name := strings.TrimSpace(input.Name)
+	switch {
+	case name == "":
+		verr.add("name", "is required")
+	case len(name) > 100:
+		verr.add("name", "must be at most 100 characters")
+	}
+	branchID := strings.TrimSpace(input.BranchID)
+	switch {
+	case branchID == "":
+		verr.add("branch_id", "is required")
+	case len(branchID) > 64:
+		verr.add("branch_id", "must be at most 64 characters")
+	default:
+		if err := registeredBranch(ctx, s.branches, branchID, verr); err != nil {
+			return nil, err
+		}
+	}
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("generate kiosk key: %w", err)
+	}
+	secret := kioskKeyPrefix + base64.RawURLEncoding.EncodeToString(buf)
+
+	kiosk := &domain.Kiosk{
+		ID:        uuid.NewString(),
+		Name:      name,
+		BranchID:  branchID,
+		KeyHash:   hashKioskKey(secret),
+		KeyPrefix: secret[:len(kioskKeyPrefix)+8],
+		CreatedBy: actor,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.kiosks.Create(ctx, kiosk); err != nil {
+		return nil, err
+	}
+
+	if err := recordAudit(ctx, s.audit, actor, auditActionKioskCreate, auditEntityKiosk, kiosk.ID, map[string]interface{}{
+		"name":      kiosk.Name,
+		"branch_id": kiosk.BranchID,
+	}); err != nil {
+		return nil, err
+	}
+	return &CreatedKiosk{Kiosk: *kiosk, Key: secret}, nil
+}
+
+// List returns every kiosk, revoked ones included.
+func (s *KioskService) List(ctx context.Context) ([]domain.Kiosk, error) {
+	return s.kiosks.List(ctx)
+}
+
+// Revoke stops a kiosk's key from authenticating.
+func (s *KioskService) Revoke(ctx context.Context, actor, id string) (*domain.Kiosk, error) {
+	kiosk, err := s.kiosks.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if kiosk == nil {
+		return nil, ErrKioskNotFound
+	}
+
+	now := time.Now().UTC()
+	revoked, err := s.kiosks.Revoke(ctx, id, now)
+	if err != nil {
+		return nil, err
+	}
+	if !revoked {
+		return nil, ErrKioskRevoked
+	}
+	kiosk.RevokedAt = &now
+
+	if err := recordAudit(ctx, s.audit, actor, auditActionKioskRevoke, auditEntityKiosk, kiosk.ID, map[string]interface{}{
+		"name":      kiosk.Name,
+		"branch_id": kiosk.BranchID,
+	}); err != nil {
+		return nil, err
+	}
+	return kiosk, nil
+}
+
+// Authenticate returns the active kiosk with the presented device key, or nil.
+func (s *KioskService) Authenticate(ctx context.Context, secret string) (*domain.Kiosk, error) {
+	if !strings.HasPrefix(secret, kioskKeyPrefix) {
+		return nil, nil
+	}
+	kiosk, err := s.kiosks.GetByKeyHash(ctx, hashKioskKey(secret))
+	if err != nil || kiosk == nil || kiosk.RevokedAt != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if err := s.kiosks.Touch(ctx, kiosk.ID, now, now.Add(-kioskTouchInterval)); err != nil {
+		return nil, err
+	}
+	return kiosk, nil
+}
+
+// Lookup finds the participant with the NIK.
+func (s *KioskService) Lookup(ctx context.Context, nik string) (*KioskParticipant, error) {
+	nik = strings.TrimSpace(nik)
+	if nik == "" {
+		return nil, &ValidationError{Fields: map[string]string{"nik": "is required"}}
+	}
+	participant, err := s.participants.GetByNIK(ctx, nik)
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return nil, ErrParticipantNotFound
+	}
+	row, err := s.kiosks.GetStatus(ctx, participant.ID)
+	if err != nil {
+		return nil, err
+	}
+	if row == nil {
+		return nil, ErrParticipantNotFound
+	}
+
+	consentRequired := false
+	if err := s.consents.Require(ctx, participant.NIK); err != nil {
+		if err != ErrConsentRequired {
+			return nil, err
+		}
+		consentRequired = true
+	}
+	return &KioskParticipant{
+		ParticipantID:     participant.ID,
+		Name:              participant.Name,
+		ParticipantStatus: string(participant.Status),
+		CertificateStatus: currentCertificateStatus(row, time.Now().UTC()),
+		VerifiedAt:        row.VerifiedAt,
+		ValidUntil:        row.ValidUntil,
+		ConsentRequired:   consentRequired,
+	}, nil
+}
+
+// Verify submits a selfie captured on the kiosk by the operator, attributed
+// to the officer the operator signs in as. The location defaults to the
+// kiosk's name, and the submitted position is checked against the kiosk
+// branch's geofence.
+func (s *KioskService) Verify(ctx context.Context, kiosk *domain.Kiosk, operator string, input VerifyInput) (*VerifyOutput, error) {
+	officer, err := s.officers.GetByUsername(ctx, operator)
+	if err != nil {
+		return nil, err
+	}
+	if officer == nil {
+		return nil, ErrOperatorNotOfficer
+	}
+	if err := officerEligible(officer, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	geofence, err := checkGeofence(ctx, s.branches, kiosk.BranchID, input.Latitude, input.Longitude)
+	if err != nil {
+		return nil, err
+	}
+	input.Geofence = geofence
+	input.KioskID = kiosk.ID
+	input.BranchID = kiosk.BranchID
+	input.Operator = operator
+	input.OfficerID = officer.ID
+	input.OfficerName = officer.Name
+	if strings.TrimSpace(input.Location) == "" {
+		input.Location = kiosk.Name
+	}
+	return s.verifications.Verify(ctx, input)
+}
+
+func hashKioskKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,405 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/frcore"
+	"life-certificates/internal/imaging"
+	"life-certificates/internal/ocr"
+	"life-certificates/internal/repository"
+)
+
+// Audit vocabulary for KTP checks.
+const (
+	auditEntityKTPCheck        = "ktp_check"
+	auditActionKTPCheckCreate  = "ktp_check.create"
+	auditActionKTPCheckConfirm = "ktp_check.confirm"
+)
+
+var (
+	// ErrKTPCheckNotFound indicates the requested KTP check does not exist.
+	ErrKTPCheckNotFound = errors.New("ktp check not found")
+	// ErrKTPCheckNotOpen indicates the KTP check has nothing left to confirm.
+	ErrKTPCheckNotOpen = errors.New("ktp check is not awaiting confirmation")
+	// ErrKTPFaceMismatch rejects a registration whose selfie does not match
+	// the portrait on the KTP.
+	ErrKTPFaceMismatch = errors.New("the selfie does not match the portrait on the KTP")
+)
+
+// Modes of the KTP face match.
+const (
+	KTPFaceMatchOff    = "off"
+	KTPFaceMatchFlag   = "flag"
+	KTPFaceMatchReject = "reject"
+)
+
+// ktpPortraitRegion is where an e-KTP prints its holder's portrait, right of
+// the personal details, in a photo the card fills.
+var ktpPortraitRegion = imaging.Region{Left: 0.66, Top: 0.15, Right: 0.98, Bottom: 0.80}
+
+// KTPFaceMatch compares the portrait on a registration's KTP photo with the
+// registration selfie.
+type KTPFaceMatch struct {
+	// Mode is off, flag (a warning for an operator) or reject (the
+	// registration is refused) when the similarity is below MinSimilarity.
+	Mode          string
+	MinSimilarity float64
+}
+
+// KTPCheckService reads the KTP (ID card) photo submitted at registration
+// with OCR, compares it with the registration and keeps the disagreements as
+// warnings for an operator to confirm. The photo is filed as the
+// participant's IDENTITY_CARD document.
+type KTPCheckService struct {
+	checks    repository.KTPCheckRepository
+	documents *DocumentService
+	reader    ocr.Reader
+	provider  string
+	frClient  frcore.Client
+	images    *imaging.Processor
+	faceMatch KTPFaceMatch
+	audit     repository.AuditLogRepository
+	tx        repository.Transactor
+}
+
+// NewKTPCheckService wires dependencies for KTP checks. A nil reader records
+// every KTP photo as UNREADABLE for an operator to compare; documents, the FR
+// Core client and the image processor may be nil where no KTP photos are
+// submitted, as in the admin CLI.
+func NewKTPCheckService(checks repository.KTPCheckRepository, documents *DocumentService, reader ocr.Reader, provider string, frClient frcore.Client, images *imaging.Processor, faceMatch KTPFaceMatch, audit repository.AuditLogRepository, tx repository.Transactor) *KTPCheckService {
+	return &KTPCheckService{
+		checks:    checks,
+		documents: documents,
+		reader:    reader,
+		provider:  provider,
+		frClient:  frClient,
+		images:    images,
+		faceMatch: faceMatch,
+		audit:     audit,
+		tx:        tx,
+	}
+}
+
+// KTPPhoto is a photo of the participant's KTP submitted with a registration.
+type KTPPhoto struct {
+	Image    []byte
+	FileName string
+}
+
+// KTPWarning is a field the KTP and the registration disagree on. Extracted
+// is empty when the field could not be read from the card.
+type KTPWarning struct {
+	Field     string `json:"field"`
+	Submitted string `json:"submitted"`
+	Extracted string `json:"extracted"`
+}
+
+// KTPCheckQueryInput carries check filters and paging.
+type KTPCheckQueryInput struct {
+	ParticipantID string
+	// Status is a comma separated list of statuses.
+	Status   string
+	Page     int
+	PageSize int
+}
+
+// KTPCheckListOutput is a page of KTP checks, newest first.
+type KTPCheckListOutput struct {
+	Items    []domain.KTPCheck `json:"items"`
+	Page     int               `json:"page"`
+	PageSize int               `json:"page_size"`
+	Total    int64             `json:"total"`
+}
+
+// ConfirmKTPCheckInput records why an operator accepted the registration
+// despite the warnings.
+type ConfirmKTPCheckInput struct {
+	Notes string `json:"notes"`
+}
+
+// ktpCapture is a KTP photo read ahead of the registration it came with.
+type ktpCapture struct {
+	photo       KTPPhoto
+	contentType string
+	scan        *domain.UploadScan
+	document    *domain.Document
+	check       *domain.KTPCheck
+}
+
+// ktpClaim is what the registration says about the card holder.
+type ktpClaim struct {
+	nik       string
+	name      string
+	birthDate *time.Time
+}
+
+// prepare validates, scans and reads a KTP photo and compares it and its
+// portrait with the registration. A card OCR cannot read does not stop the
+// registration; a portrait unlike the selfie does in reject mode.
+func (s *KTPCheckService) prepare(ctx context.Context, actor string, claim ktpClaim, photo KTPPhoto, selfie []byte) (*ktpCapture, error) {
+	contentType, problem := checkSupportingDocument(DocumentUpload{FileName: photo.FileName, Data: photo.Image})
+	if problem == "" && contentType == "application/pdf" {
+		problem = "must be a JPEG or PNG photo"
+	}
+	if problem != "" {
+		verr := &ValidationError{}
+		verr.add("ktp_image", fmt.Sprintf("%s: %s", photo.FileName, problem))
+		return nil, verr
+	}
+	scan, err := s.documents.scans.Check(ctx, actor, photo.FileName, photo.Image)
+	if err != nil {
+		return nil, err
+	}
+
+	check := &domain.KTPCheck{
+		ID:        uuid.NewString(),
+		Provider:  s.provider,
+		CheckedBy: actor,
+		CheckedAt: time.Now().UTC(),
+	}
+	capture := &ktpCapture{photo: photo, contentType: contentType, scan: scan, check: check}
+	warnings, readable := s.read(ctx, check, claim, photo.Image)
+	warning, err := s.matchFace(ctx, check, photo.Image, selfie)
+	if err != nil {
+		return nil, err
+	}
+	if warning != nil {
+		warnings = append(warnings, *warning)
+	}
+
+	encoded, err := json.Marshal(warnings)
+	if err != nil {
+		return nil, err
+	}
+	check.Warnings = string(encoded)
+	switch {
+	case !readable:
+		check.Status = domain.KTPCheckUnreadable
+	case len(warnings) > 0:
+		check.Status = domain.KTPCheckPending
+	default:
+		check.Status = domain.KTPCheckMatched
+	}
+	return capture, nil
+}
+
+// read extracts the card's fields with OCR and lists where they disagree
+// with the registration, reporting false when nothing could be read.
+func (s *KTPCheckService) read(ctx context.Context, check *domain.KTPCheck, claim ktpClaim, image []byte) ([]KTPWarning, bool) {
+	if s.reader == nil {
+		reason := "no OCR provider is configured"
+		check.Error = &reason
+		return []KTPWarning{}, false
+	}
+	card, err := s.reader.ReadKTP(ctx, image)
+	if err == nil && card.NIK == "" && card.Name == "" && card.BirthDate == nil {
+		err = errors.New("no KTP fields recognised")
+	}
+	if err != nil {
+		reason := err.Error()
+		check.Error = &reason
+		return []KTPWarning{}, false
+	}
+
+	check.NIK = optionalString(&card.NIK)
+	check.Name = optionalString(&card.Name)
+	check.BirthDate = card.BirthDate
+	return compareKTP(claim, card), true
+}
+
+// matchFace compares the card's portrait with the selfie. A comparison that
+// fails is left to an operator rather than refusing the registration.
+func (s *KTPCheckService) matchFace(ctx context.Context, check *domain.KTPCheck, image, selfie []byte) (*KTPWarning, error) {
+	if s.faceMatch.Mode == "" || s.faceMatch.Mode == KTPFaceMatchOff || s.frClient == nil {
+		return nil, nil
+	}
+	similarity, err := s.compareFace(ctx, image, selfie)
+	if err != nil {
+		reason := err.Error()
+		check.FaceError = &reason
+		return &KTPWarning{Field: "face"}, nil
+	}
+	check.FaceSimilarity = &similarity
+	if similarity >= s.faceMatch.MinSimilarity {
+		return nil, nil
+	}
+	if s.faceMatch.Mode == KTPFaceMatchReject {
+		return nil, ErrKTPFaceMismatch
+	}
+	return &KTPWarning{Field: "face", Extracted: strconv.FormatFloat(similarity, 'f', 2, 64)}, nil
+}
+
+func (s *KTPCheckService) compareFace(ctx context.Context, image, selfie []byte) (float64, error) {
+	portrait, err := s.images.Crop(ctx, image, ktpPortraitRegion)
+	if err != nil {
+		return 0, fmt.Errorf("crop portrait: %w", err)
+	}
+	selfie, err = s.images.Process(ctx, selfie)
+	if err != nil {
+		return 0, fmt.Errorf("process selfie: %w", err)
+	}
+	result, err := s.frClient.Compare(ctx, frcore.CompareRequest{
+		ImageName:     "selfie.jpg",
+		Image:         selfie,
+		ReferenceName: "ktp-portrait.jpg",
+		Reference:     portrait,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.Similarity, nil
+}
+
+// store files the photo under the registered participant in the blob store,
+// ahead of the registration's transaction.
+func (s *KTPCheckService) store(ctx context.Context, capture *ktpCapture, participantID string) error {
+	capture.document = newDocument(capture.check.CheckedBy, domain.DocumentOwnerParticipant, participantID,
+		domain.DocumentCategoryIdentityCard, nil, capture.photo.FileName, capture.contentType, capture.photo.Image)
+	capture.check.ParticipantID = participantID
+	capture.check.DocumentID = capture.document.ID
+	return s.documents.store(ctx, capture.document, capture.photo.Image)
+}
+
+// record creates the photo's document and the check within the
+// registration's transaction.
+func (s *KTPCheckService) record(ctx context.Context, capture *ktpCapture) error {
+	check := capture.check
+	if err := s.documents.create(ctx, check.CheckedBy, capture.document, capture.scan); err != nil {
+		return err
+	}
+	if err := s.checks.Create(ctx, check); err != nil {
+		return err
+	}
+	return recordAudit(ctx, s.audit, check.CheckedBy, auditActionKTPCheckCreate, auditEntityKTPCheck, check.ID, map[string]interface{}{
+		"participant_id":  check.ParticipantID,
+		"document_id":     check.DocumentID,
+		"status":          check.Status,
+		"provider":        check.Provider,
+		"face_similarity": check.FaceSimilarity,
+	})
+}
+
+// List returns KTP checks matching the filters, newest first.
+func (s *KTPCheckService) List(ctx context.Context, input KTPCheckQueryInput) (*KTPCheckListOutput, error) {
+	filter := repository.KTPCheckFilter{ParticipantID: strings.TrimSpace(input.ParticipantID)}
+	for _, raw := range strings.Split(input.Status, ",") {
+		status := domain.KTPCheckStatus(strings.ToUpper(strings.TrimSpace(raw)))
+		switch status {
+		case "":
+		case domain.KTPCheckMatched, domain.KTPCheckPending, domain.KTPCheckUnreadable, domain.KTPCheckConfirmed:
+			filter.Statuses = append(filter.Statuses, status)
+		default:
+			return nil, fmt.Errorf("status must be MATCHED, PENDING, UNREADABLE or CONFIRMED")
+		}
+	}
+
+	page := normalizePagination(input.Page, input.PageSize)
+	items, total, err := s.checks.List(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+	return &KTPCheckListOutput{Items: items, Page: page.Page, PageSize: page.PageSize, Total: total}, nil
+}
+
+// Get returns a single KTP check.
+func (s *KTPCheckService) Get(ctx context.Context, id string) (*domain.KTPCheck, error) {
+	check, err := s.checks.GetByID(ctx, strings.TrimSpace(id))
+	if err != nil {
+		return nil, err
+	}
+	if check == nil {
+		return nil, ErrKTPCheckNotFound
+	}
+	return check, nil
+}
+
+// Confirm records that an operator compared the card with the registration
+// and accepts it despite the warnings.
+func (s *KTPCheckService) Confirm(ctx context.Context, actor, id string, input ConfirmKTPCheckInput) (*domain.KTPCheck, error) {
+	notes := strings.TrimSpace(input.Notes)
+	if notes == "" {
+		verr := &ValidationError{}
+		verr.add("notes", "is required")
+		return nil, verr
+	}
+	check, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if check.Status != domain.KTPCheckPending && check.Status != domain.KTPCheckUnreadable {
+		return nil, ErrKTPCheckNotOpen
+	}
+
+	now := time.Now().UTC()
+	previous := check.Status
+	check.Status = domain.KTPCheckConfirmed
+	check.ConfirmedBy = &actor
+	check.ConfirmedAt = &now
+	check.Notes = &notes
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		ok, err := s.checks.Confirm(ctx, check)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrKTPCheckNotOpen
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionKTPCheckConfirm, auditEntityKTPCheck, check.ID, map[string]interface{}{
+			"participant_id": check.ParticipantID,
+			"previous":       previous,
+			"notes":          notes,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return check, nil
+}
+
+// DeleteByParticipant removes a participant's KTP checks.
+func (s *KTPCheckService) DeleteByParticipant(ctx context.Context, participantID string) error {
+	return s.checks.DeleteByParticipant(ctx, participantID)
+}
+
+// compareKTP lists the fields the card and the registration disagree on.
+// Names are compared ignoring case, punctuation and spacing; the birth date
+// only when the registration gives one.
+func compareKTP(claim ktpClaim, card *ocr.KTP) []KTPWarning {
+	warnings := []KTPWarning{}
+	if card.NIK != claim.nik {
+		warnings = append(warnings, KTPWarning{Field: "nik", Submitted: claim.nik, Extracted: card.NIK})
+	}
+	if normalizeKTPName(card.Name) != normalizeKTPName(claim.name) {
+		warnings = append(warnings, KTPWarning{Field: "name", Submitted: claim.name, Extracted: card.Name})
+	}
+	if claim.birthDate != nil {
+		submitted := claim.birthDate.Format("2006-01-02")
+		extracted := ""
+		if card.BirthDate != nil {
+			extracted = card.BirthDate.Format("2006-01-02")
+		}
+		if extracted != submitted {
+			warnings = append(warnings, KTPWarning{Field: "birth_date", Submitted: submitted, Extracted: extracted})
+		}
+	}
+	return warnings
+}
+
+func normalizeKTPName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '.' || r == ',' || r == '\'' || r == '-' {
+			return ' '
+		}
+		return r
+	}, strings.ToUpper(name))
+	return strings.Join(strings.Fields(name), " ")
+}
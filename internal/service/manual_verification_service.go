@@ -0,0 +1,562 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/events"
+	"life-certificates/internal/metrics"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/storage"
+	"life-certificates/internal/tracing"
+)
+
+const (
+	maxSupportingDocumentBytes = 10 << 20
+	auditActionManualVerify    = "certificate.manual_verify"
+	auditActionProxyVerify     = "certificate.proxy_verify"
+)
+
+// allowedDocumentTypes lists the content types accepted as supporting evidence.
+var allowedDocumentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/jpeg":      true,
+	"image/png":       true,
+}
+
+var (
+	// ErrDocumentNotFound indicates the requested supporting document does not exist.
+	ErrDocumentNotFound = errors.New("document not found")
+)
+
+// ManualVerificationService records in-person verifications performed by
+// officers and proxy verifications submitted for bedridden participants.
+type ManualVerificationService struct {
+	participants repository.ParticipantRepository
+	certificates repository.LifeCertificateRepository
+	documents    repository.CertificateDocumentRepository
+	audit        repository.AuditLogRepository
+	blobs        storage.BlobStore
+	scans        *UploadScanService
+	visits       repository.HomeVisitRepository
+	campaigns    repository.CampaignRepository
+	officers     repository.OfficerRepository
+	branches     repository.BranchRepository
+	tx           repository.Transactor
+	events       events.Publisher
+	reviewSLA    time.Duration
+	calendar     *CalendarService
+}
+
+// NewManualVerificationService wires dependencies for manual verification.
+func NewManualVerificationService(participants repository.ParticipantRepository, certificates repository.LifeCertificateRepository, documents repository.CertificateDocumentRepository, audit repository.AuditLogRepository, blobs storage.BlobStore, scans *UploadScanService, visits repository.HomeVisitRepository, campaigns repository.CampaignRepository, officers repository.OfficerRepository, branches repository.BranchRepository, tx repository.Transactor, publisher events.Publisher, reviewSLA time.Duration, calendar *CalendarService) *ManualVerificationService {
+	return &ManualVerificationService{
+		participants: participants,
+		certificates: certificates,
+		documents:    documents,
+		audit:        audit,
+		blobs:        blobs,
+		scans:        scans,
+		visits:       visits,
+		campaigns:    campaigns,
+		officers:     officers,
+		branches:     branches,
+		tx:           tx,
+		events:       publisher,
+		reviewSLA:    reviewSLA,
+		calendar:     calendar,
+	}
+}
+
+// DocumentUpload is a supporting document submitted with a manual or proxy verification.
+type DocumentUpload struct {
+	FileName string
+	Data     []byte
+	// Kind is the evidence the document stands for, if any.
+	Kind domain.DocumentKind
+}
+
+// ManualVerifyInput captures an officer's in-person verification.
+type ManualVerifyInput struct {
+	ParticipantID string
+	// OfficerID is the registered officer who performed the verification.
+	OfficerID string
+	Notes     string
+	Location  string
+	// Latitude and Longitude are where the officer made the verification,
+	// checked against the branch's geofence.
+	Latitude  *float64
+	Longitude *float64
+	// VisitID is the scheduled home visit the verification was made on, if any.
+	VisitID   string
+	Documents []DocumentUpload
+}
+
+// ProxyVerifyInput captures a verification submitted on behalf of a
+// bedridden participant. Documents must include a doctor letter and a
+// home-visit photo.
+type ProxyVerifyInput struct {
+	ParticipantID string
+	Kind          string
+	ProxyName     string
+	// Relationship is the family member's relationship to the participant.
+	Relationship string
+	// OfficerID is the registered field officer who took the submission.
+	OfficerID string
+	Notes     string
+	Location  string
+	// Latitude and Longitude are where the officer made the verification,
+	// checked against the branch's geofence.
+	Latitude  *float64
+	Longitude *float64
+	// VisitID is the scheduled home visit the verification was made on, if any.
+	VisitID   string
+	Documents []DocumentUpload
+}
+
+// ManualVerifyOutput returns the created certificate and its documents.
+type ManualVerifyOutput struct {
+	Certificate *domain.LifeCertificate      `json:"certificate"`
+	Documents   []domain.CertificateDocument `json:"documents"`
+}
+
+// Verify creates a VALID certificate flagged as MANUAL, storing the supporting documents in the blob store.
+func (s *ManualVerificationService) Verify(ctx context.Context, actor string, input ManualVerifyInput) (*ManualVerifyOutput, error) {
+	ctx, span := tracing.Start(ctx, "ManualVerificationService.Verify", attribute.String("participant_id", input.ParticipantID))
+	defer span.End()
+
+	participantID := strings.TrimSpace(input.ParticipantID)
+	officerID := strings.TrimSpace(input.OfficerID)
+	notes := strings.TrimSpace(input.Notes)
+
+	verr := &ValidationError{}
+	if participantID == "" {
+		verr.add("participant_id", "is required")
+	}
+	if officerID == "" {
+		verr.add("officer_id", "is required")
+	}
+	if notes == "" {
+		verr.add("notes", "is required")
+	}
+	if len(input.Documents) == 0 {
+		verr.add("documents", "at least one supporting document is required")
+	}
+	contentTypes := checkSupportingDocuments(input.Documents, verr)
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	participant, err := s.verifiable(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+	visit, err := s.scheduledVisit(ctx, input.VisitID, participant.ID)
+	if err != nil {
+		return nil, err
+	}
+	location := optionalString(&input.Location)
+
+	now := time.Now().UTC()
+	officer, err := performingOfficer(ctx, s.officers, officerID, now)
+	if err != nil {
+		return nil, err
+	}
+	channel := domain.VerificationChannelManual
+	if visit != nil {
+		channel = domain.VerificationChannelField
+	}
+	record := &domain.LifeCertificate{
+		ID:            uuid.NewString(),
+		ParticipantID: participant.ID,
+		Status:        domain.LifeCertificateStatusValid,
+		Method:        domain.VerificationMethodManual,
+		Channel:       channel,
+		VerifiedAt:    now,
+		Notes:         &notes,
+		Location:      location,
+		Latitude:      input.Latitude,
+		Longitude:     input.Longitude,
+		OfficerID:     &officer.ID,
+		OfficerName:   &officer.Name,
+		BranchID:      &officer.BranchID,
+		RecordedBy:    &actor,
+	}
+
+	documents, err := s.record(ctx, actor, record, visit, input.Documents, contentTypes, func(ctx context.Context) error {
+		if err := recordAudit(ctx, s.audit, actor, auditActionManualVerify, auditEntityLifeCertificate, record.ID, map[string]interface{}{
+			"participant_id": participant.ID,
+			"officer_id":     officer.ID,
+			"officer_name":   officer.Name,
+			"documents":      len(input.Documents),
+			"visit_id":       optionalString(&input.VisitID),
+		}); err != nil {
+			return err
+		}
+		return publishEvent(ctx, s.events, events.TypeVerificationCompleted, verificationCompletedData(record))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ManualVerifyOutput{Certificate: record, Documents: documents}, nil
+}
+
+// Proxy creates a REVIEW certificate flagged as PROXY for a participant who
+// cannot verify in person, routed to senior review with its evidence.
+func (s *ManualVerificationService) Proxy(ctx context.Context, actor string, input ProxyVerifyInput) (*ManualVerifyOutput, error) {
+	ctx, span := tracing.Start(ctx, "ManualVerificationService.Proxy", attribute.String("participant_id", input.ParticipantID))
+	defer span.End()
+
+	participantID := strings.TrimSpace(input.ParticipantID)
+	kind := domain.ProxyKind(strings.ToUpper(strings.TrimSpace(input.Kind)))
+	proxyName := strings.TrimSpace(input.ProxyName)
+	notes := strings.TrimSpace(input.Notes)
+
+	verr := &ValidationError{}
+	if participantID == "" {
+		verr.add("participant_id", "is required")
+	}
+	officerID := strings.TrimSpace(input.OfficerID)
+	if officerID == "" {
+		verr.add("officer_id", "is required")
+	}
+	var relationship *string
+	switch kind {
+	case domain.ProxyKindFamily:
+		if proxyName == "" {
+			verr.add("proxy_name", "is required for a family member")
+		}
+		if relationship = optionalString(&input.Relationship); relationship == nil {
+			verr.add("relationship", "is required for a family member")
+		}
+	case domain.ProxyKindOfficer:
+	default:
+		verr.add("proxy_kind", "must be FAMILY or OFFICER")
+	}
+	if notes == "" {
+		verr.add("notes", "is required")
+	}
+	evidence := map[domain.DocumentKind]bool{}
+	for _, doc := range input.Documents {
+		evidence[doc.Kind] = true
+	}
+	if !evidence[domain.DocumentKindDoctorLetter] {
+		verr.add("doctor_letter", "is required")
+	}
+	if !evidence[domain.DocumentKindHomeVisitPhoto] {
+		verr.add("home_visit_photo", "is required")
+	}
+	contentTypes := checkSupportingDocuments(input.Documents, verr)
+	for i, doc := range input.Documents {
+		if doc.Kind == domain.DocumentKindHomeVisitPhoto && contentTypes[i] == "application/pdf" {
+			verr.add("home_visit_photo", fmt.Sprintf("%s: must be a JPEG or PNG photo", doc.FileName))
+		}
+	}
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	participant, err := s.verifiable(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+	visit, err := s.scheduledVisit(ctx, input.VisitID, participant.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	officer, err := performingOfficer(ctx, s.officers, officerID, now)
+	if err != nil {
+		return nil, err
+	}
+	if kind == domain.ProxyKindOfficer {
+		proxyName = officer.Name
+	}
+	dueAt, err := s.calendar.AddWorkingTime(ctx, now, s.reviewSLA, "")
+	if err != nil {
+		return nil, err
+	}
+	record := &domain.LifeCertificate{
+		ID:                uuid.NewString(),
+		ParticipantID:     participant.ID,
+		Status:            domain.LifeCertificateStatusReview,
+		Method:            domain.VerificationMethodProxy,
+		Channel:           domain.VerificationChannelProxy,
+		VerifiedAt:        now,
+		Notes:             &notes,
+		Location:          optionalString(&input.Location),
+		Latitude:          input.Latitude,
+		Longitude:         input.Longitude,
+		ProxyKind:         &kind,
+		ProxyName:         &proxyName,
+		ProxyRelationship: relationship,
+		OfficerID:         &officer.ID,
+		OfficerName:       &officer.Name,
+		BranchID:          &officer.BranchID,
+		RecordedBy:        &actor,
+		ReviewDueAt:       &dueAt,
+		SeniorReview:      true,
+	}
+
+	documents, err := s.record(ctx, actor, record, visit, input.Documents, contentTypes, func(ctx context.Context) error {
+		if err := recordAudit(ctx, s.audit, actor, auditActionProxyVerify, auditEntityLifeCertificate, record.ID, map[string]interface{}{
+			"participant_id": participant.ID,
+			"proxy_kind":     kind,
+			"proxy_name":     proxyName,
+			"relationship":   relationship,
+			"officer_id":     officer.ID,
+			"documents":      len(input.Documents),
+			"visit_id":       optionalString(&input.VisitID),
+		}); err != nil {
+			return err
+		}
+		return publishEvent(ctx, s.events, events.TypeVerificationReviewRequired, map[string]interface{}{
+			"certificate_id":   record.ID,
+			"participant_id":   participant.ID,
+			"status":           record.Status,
+			"method":           record.Method,
+			"location":         record.Location,
+			"reason":           "proxy",
+			"senior_review":    true,
+			"verified_at":      now,
+			"review_due_at":    record.ReviewDueAt,
+			"outside_geofence": record.OutsideGeofence,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ManualVerifyOutput{Certificate: record, Documents: documents}, nil
+}
+
+// verifiable loads a participant who may be verified.
+func (s *ManualVerificationService) verifiable(ctx context.Context, participantID string) (*domain.Participant, error) {
+	participant, err := s.participants.GetByID(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return nil, ErrParticipantNotFound
+	}
+	switch participant.Status {
+	case domain.ParticipantStatusSuspended:
+		return nil, ErrParticipantSuspended
+	case domain.ParticipantStatusBlocked:
+		return nil, ErrParticipantBlocked
+	}
+	return participant, nil
+}
+
+// scheduledVisit loads the participant's SCHEDULED home visit a verification
+// is made on; it is nil without a visit ID.
+func (s *ManualVerificationService) scheduledVisit(ctx context.Context, visitID, participantID string) (*domain.HomeVisit, error) {
+	visitID = strings.TrimSpace(visitID)
+	if visitID == "" {
+		return nil, nil
+	}
+	visit, err := s.visits.GetByID(ctx, visitID)
+	if err != nil {
+		return nil, err
+	}
+	if visit == nil {
+		return nil, ErrHomeVisitNotFound
+	}
+	if visit.ParticipantID != participantID {
+		return nil, &ValidationError{Fields: map[string]string{"visit_id": "is a visit to another participant"}}
+	}
+	if visit.Status != domain.HomeVisitScheduled {
+		return nil, ErrHomeVisitNotScheduled
+	}
+	return visit, nil
+}
+
+// record stores the documents and creates the certificate with them,
+// completing the home visit it was made on and calling recorded within the
+// same transaction. A verification made outside its branch's geofence is
+// still recorded, but flagged and alerted on.
+func (s *ManualVerificationService) record(ctx context.Context, actor string, record *domain.LifeCertificate, visit *domain.HomeVisit, uploads []DocumentUpload, contentTypes []string, recorded func(ctx context.Context) error) ([]domain.CertificateDocument, error) {
+	if visit != nil && visit.BranchID != nil {
+		record.BranchID = visit.BranchID
+	}
+	geofence, err := checkGeofence(ctx, s.branches, *record.BranchID, record.Latitude, record.Longitude)
+	if err != nil {
+		return nil, err
+	}
+	geofence.apply(record)
+	if err := linkCampaign(ctx, s.campaigns, record); err != nil {
+		return nil, err
+	}
+
+	// Every document is scanned before any is stored, so an infected one rejects the whole verification.
+	scans := make([]*domain.UploadScan, len(uploads))
+	for i, doc := range uploads {
+		if scans[i], err = s.scans.Check(ctx, actor, doc.FileName, doc.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	documents := make([]domain.CertificateDocument, 0, len(uploads))
+	for i, doc := range uploads {
+		stored, err := s.storeDocument(ctx, actor, record.ID, doc, contentTypes[i], record.VerifiedAt)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, *stored)
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.certificates.Create(ctx, record); err != nil {
+			return err
+		}
+		if visit != nil {
+			ok, err := s.visits.Complete(ctx, visit.ID, record.ID, record.VerifiedAt)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return ErrHomeVisitNotScheduled
+			}
+		}
+		for i := range documents {
+			if err := s.documents.Create(ctx, &documents[i]); err != nil {
+				return err
+			}
+			if scans[i] != nil {
+				scans[i].DocumentID = &documents[i].ID
+				if err := s.scans.Record(ctx, scans[i]); err != nil {
+					return err
+				}
+			}
+		}
+		if record.OutsideGeofence {
+			if err := s.alertOutsideGeofence(ctx, record); err != nil {
+				return err
+			}
+		}
+		return recorded(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	metrics.VerificationRecorded(string(record.Status), string(record.Method))
+	return documents, nil
+}
+
+// alertOutsideGeofence raises an alert for a verification made away from its branch.
+func (s *ManualVerificationService) alertOutsideGeofence(ctx context.Context, record *domain.LifeCertificate) error {
+	return publishEvent(ctx, s.events, events.TypeAlertTriggered, map[string]interface{}{
+		"kind":     "outside_geofence",
+		"severity": alertSeverityWarning,
+		"message": fmt.Sprintf("%s verification %s by officer %s flagged: %s",
+			strings.ToLower(string(record.Method)), record.ID, *record.OfficerName, *record.GeofenceFinding),
+		"details": map[string]interface{}{
+			"certificate_id": record.ID,
+			"participant_id": record.ParticipantID,
+			"officer_id":     record.OfficerID,
+			"branch_id":      record.BranchID,
+			"latitude":       record.Latitude,
+			"longitude":      record.Longitude,
+			"distance":       record.GeofenceDistance,
+		},
+	})
+}
+
+// Documents lists the supporting documents of a certificate.
+func (s *ManualVerificationService) Documents(ctx context.Context, certificateID string) ([]domain.CertificateDocument, error) {
+	record, err := s.certificates.GetByID(ctx, strings.TrimSpace(certificateID))
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, ErrCertificateNotFound
+	}
+	return s.documents.ListByCertificate(ctx, record.ID)
+}
+
+// OpenDocument returns a supporting document's metadata and content.
+func (s *ManualVerificationService) OpenDocument(ctx context.Context, certificateID, documentID string) (*domain.CertificateDocument, io.ReadCloser, error) {
+	document, err := s.documents.GetByID(ctx, strings.TrimSpace(documentID))
+	if err != nil {
+		return nil, nil, err
+	}
+	if document == nil || document.CertificateID != strings.TrimSpace(certificateID) {
+		return nil, nil, ErrDocumentNotFound
+	}
+
+	content, err := s.blobs.Get(ctx, document.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, ErrDocumentNotFound
+		}
+		return nil, nil, err
+	}
+	return document, content, nil
+}
+
+func (s *ManualVerificationService) storeDocument(ctx context.Context, actor, certificateID string, doc DocumentUpload, contentType string, now time.Time) (*domain.CertificateDocument, error) {
+	id := uuid.NewString()
+	key := fmt.Sprintf("certificates/%s/documents/%s%s", certificateID, id, strings.ToLower(filepath.Ext(doc.FileName)))
+	if err := s.blobs.Put(ctx, key, doc.Data); err != nil {
+		return nil, err
+	}
+
+	return &domain.CertificateDocument{
+		ID:            id,
+		CertificateID: certificateID,
+		Kind:          doc.Kind,
+		FileName:      filepath.Base(doc.FileName),
+		ContentType:   contentType,
+		SizeBytes:     int64(len(doc.Data)),
+		StorageKey:    key,
+		UploadedBy:    actor,
+		CreatedAt:     now,
+	}, nil
+}
+
+// checkSupportingDocuments checks every document, returning their content
+// types. Problems are reported under the form field of the document's kind.
+func checkSupportingDocuments(docs []DocumentUpload, verr *ValidationError) []string {
+	contentTypes := make([]string, len(docs))
+	for i, doc := range docs {
+		contentType, problem := checkSupportingDocument(doc)
+		if problem != "" {
+			field := "documents"
+			if doc.Kind != "" {
+				field = strings.ToLower(string(doc.Kind))
+			}
+			verr.add(field, fmt.Sprintf("%s: %s", doc.FileName, problem))
+			continue
+		}
+		contentTypes[i] = contentType
+	}
+	return contentTypes
+}
+
+// checkSupportingDocument sniffs the content type and enforces size and type limits.
+func checkSupportingDocument(doc DocumentUpload) (string, string) {
+	if len(doc.Data) == 0 {
+		return "", "file is empty"
+	}
+	if len(doc.Data) > maxSupportingDocumentBytes {
+		return "", fmt.Sprintf("file exceeds %d bytes", maxSupportingDocumentBytes)
+	}
+	contentType := http.DetectContentType(doc.Data)
+	if idx := strings.Index(contentType, ";"); idx >= 0 {
+		contentType = contentType[:idx]
+	}
+	if !allowedDocumentTypes[contentType] {
+		return "", fmt.Sprintf("unsupported content type %s", contentType)
+	}
+	return contentType, ""
+}
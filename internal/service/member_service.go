@@ -2,10 +2,13 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/google/uuid"
 
@@ -20,6 +23,21 @@ var (
 	ErrMemberNIKExists = errors.New("member with nik already exists")
 	// ErrMemberNomorPesertaExists signals that the nomor peserta is already registered.
 	ErrMemberNomorPesertaExists = errors.New("member with nomor peserta already exists")
+	// ErrMemberMergeSameRecord signals an attempt to merge a member into itself.
+	ErrMemberMergeSameRecord = errors.New("source and target member must differ")
+	// ErrMemberErased signals that the member's personal data has been erased.
+	ErrMemberErased = errors.New("member data has been erased")
+)
+
+// nikFragmentLength is the number of trailing NIK digits (birth date and serial)
+// compared when looking for probable duplicates. Imports frequently mangle the
+// leading region code or drop leading zeros, so the tail is the stable part.
+const nikFragmentLength = 12
+
+// Duplicate group reasons reported by FindDuplicates.
+const (
+	DuplicateReasonNIKFragment   = "nik_fragment"
+	DuplicateReasonNameBirthDate = "name_birth_date"
 )
 
 // MemberService provides CRUD operations for members.
@@ -43,6 +61,7 @@ type CreateMemberInput struct {
 	Province     string `json:"province"`
 	PhoneNumber  string `json:"phone_number"`
 	Email        string `json:"email"`
+	Status       string `json:"status"`
 }
 
 // UpdateMemberInput captures optional member fields for update operations.
@@ -56,6 +75,7 @@ type UpdateMemberInput struct {
 	Province     *string `json:"province"`
 	PhoneNumber  *string `json:"phone_number"`
 	Email        *string `json:"email"`
+	Status       *string `json:"status"`
 }
 
 // Create inserts a new member into the repository.
@@ -83,6 +103,14 @@ func (s *MemberService) Create(ctx context.Context, input CreateMemberInput) (*d
 		return nil, fmt.Errorf("invalid birth_date format, use YYYY-MM-DD")
 	}
 
+	status := domain.MemberStatusActive
+	if raw := strings.TrimSpace(input.Status); raw != "" {
+		status, err = parseMemberStatus(raw)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	existingByNIK, err := s.members.GetByNIK(ctx, nik)
 	if err != nil {
 		return nil, err
@@ -111,6 +139,7 @@ func (s *MemberService) Create(ctx context.Context, input CreateMemberInput) (*d
 		Province:     strings.TrimSpace(input.Province),
 		PhoneNumber:  strings.TrimSpace(input.PhoneNumber),
 		Email:        strings.TrimSpace(input.Email),
+		Status:       status,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
@@ -148,6 +177,9 @@ func (s *MemberService) Update(ctx context.Context, id string, input UpdateMembe
 	if member == nil {
 		return nil, ErrMemberNotFound
 	}
+	if member.ErasedAt != nil {
+		return nil, ErrMemberErased
+	}
 
 	if input.NIK != nil {
 		newNIK := strings.TrimSpace(*input.NIK)
@@ -218,6 +250,13 @@ func (s *MemberService) Update(ctx context.Context, id string, input UpdateMembe
 	if input.Email != nil {
 		member.Email = strings.TrimSpace(*input.Email)
 	}
+	if input.Status != nil {
+		status, err := parseMemberStatus(*input.Status)
+		if err != nil {
+			return nil, err
+		}
+		member.Status = status
+	}
 
 	member.UpdatedAt = time.Now().UTC()
 
@@ -240,3 +279,161 @@ func (s *MemberService) Delete(ctx context.Context, id string) error {
 
 	return s.members.Delete(ctx, id)
 }
+
+// DuplicateGroup lists members that probably describe the same person.
+type DuplicateGroup struct {
+	Reason  string          `json:"reason"`
+	Key     string          `json:"key"`
+	Members []domain.Member `json:"members"`
+}
+
+// MergeMembersInput identifies the duplicate to fold into the surviving member.
+type MergeMembersInput struct {
+	SourceMemberID string `json:"source_member_id"`
+	TargetMemberID string `json:"target_member_id"`
+	Notes          string `json:"notes"`
+}
+
+// MergeMembersOutput returns the surviving member and the merge record.
+type MergeMembersOutput struct {
+	Member *domain.Member      `json:"member"`
+	Merge  *domain.MemberMerge `json:"merge"`
+}
+
+// FindDuplicates groups members sharing a NIK fragment or the same name and birth date.
+func (s *MemberService) FindDuplicates(ctx context.Context) ([]DuplicateGroup, error) {
+	members, err := s.members.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byNIK := make(map[string][]domain.Member)
+	byNameBirth := make(map[string][]domain.Member)
+	for _, member := range members {
+		if fragment := nikFragment(member.NIK); fragment != "" {
+			byNIK[fragment] = append(byNIK[fragment], member)
+		}
+		key := strings.ToLower(strings.Join(strings.Fields(member.FullName), " ")) + "|" + member.BirthDate.Format("2006-01-02")
+		byNameBirth[key] = append(byNameBirth[key], member)
+	}
+
+	groups := collectDuplicateGroups(DuplicateReasonNIKFragment, byNIK)
+	groups = append(groups, collectDuplicateGroups(DuplicateReasonNameBirthDate, byNameBirth)...)
+	return groups, nil
+}
+
+// Merge folds the source member into the target, filling blank target fields
+// from the source and re-pointing linked participants.
+func (s *MemberService) Merge(ctx context.Context, input MergeMembersInput) (*MergeMembersOutput, error) {
+	sourceID := strings.TrimSpace(input.SourceMemberID)
+	targetID := strings.TrimSpace(input.TargetMemberID)
+	if sourceID == "" {
+		return nil, fmt.Errorf("source_member_id is required")
+	}
+	if targetID == "" {
+		return nil, fmt.Errorf("target_member_id is required")
+	}
+	if sourceID == targetID {
+		return nil, ErrMemberMergeSameRecord
+	}
+
+	source, err := s.members.GetByID(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return nil, ErrMemberNotFound
+	}
+	target, err := s.members.GetByID(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, ErrMemberNotFound
+	}
+	if source.ErasedAt != nil || target.ErasedAt != nil {
+		return nil, ErrMemberErased
+	}
+
+	snapshot, err := json.Marshal(source)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot source member: %w", err)
+	}
+
+	target.FullName = firstNonEmpty(target.FullName, source.FullName)
+	target.Address = firstNonEmpty(target.Address, source.Address)
+	target.City = firstNonEmpty(target.City, source.City)
+	target.Province = firstNonEmpty(target.Province, source.Province)
+	target.PhoneNumber = firstNonEmpty(target.PhoneNumber, source.PhoneNumber)
+	target.Email = firstNonEmpty(target.Email, source.Email)
+
+	now := time.Now().UTC()
+	target.UpdatedAt = now
+
+	merge := &domain.MemberMerge{
+		ID:             uuid.NewString(),
+		SourceMemberID: source.ID,
+		TargetMemberID: target.ID,
+		SourceSnapshot: string(snapshot),
+		Notes:          strings.TrimSpace(input.Notes),
+		MergedAt:       now,
+	}
+
+	if err := s.members.Merge(ctx, target, source.ID, merge); err != nil {
+		return nil, err
+	}
+
+	return &MergeMembersOutput{Member: target, Merge: merge}, nil
+}
+
+// ListMerges returns recorded member merges, newest first.
+func (s *MemberService) ListMerges(ctx context.Context) ([]domain.MemberMerge, error) {
+	return s.members.ListMerges(ctx)
+}
+
+func parseMemberStatus(raw string) (domain.MemberStatus, error) {
+	switch status := domain.MemberStatus(strings.ToUpper(strings.TrimSpace(raw))); status {
+	case domain.MemberStatusActive, domain.MemberStatusDeceased:
+		return status, nil
+	default:
+		return "", fmt.Errorf("invalid status, use ACTIVE or DECEASED")
+	}
+}
+
+func nikFragment(nik string) string {
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, nik)
+	if len(digits) < nikFragmentLength {
+		return ""
+	}
+	return digits[len(digits)-nikFragmentLength:]
+}
+
+func collectDuplicateGroups(reason string, buckets map[string][]domain.Member) []DuplicateGroup {
+	keys := make([]string, 0, len(buckets))
+	for key, members := range buckets {
+		if len(members) > 1 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	groups := make([]DuplicateGroup, 0, len(keys))
+	for _, key := range keys {
+		groups = append(groups, DuplicateGroup{Reason: reason, Key: key, Members: buckets[key]})
+	}
+	return groups
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
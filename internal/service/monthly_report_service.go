@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"life-certificates/internal/document"
+	"life-certificates/internal/domain"
+	"life-certificates/internal/export"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/signing"
+	"life-certificates/internal/storage"
+	"life-certificates/internal/tenant"
+)
+
+// Monthly report formats.
+const (
+	MonthlyReportFormatPDF  = "pdf"
+	MonthlyReportFormatXLSX = "xlsx"
+)
+
+var (
+	// ErrInvalidReportPeriod indicates a monthly report period that is not YYYY-MM.
+	ErrInvalidReportPeriod = errors.New("period must be a month, e.g. 2024-07")
+	// ErrMonthlyReportNotReady indicates a monthly report was requested before its month ended.
+	ErrMonthlyReportNotReady = errors.New("the monthly report is available once the month has ended")
+)
+
+// MonthlyReportService compiles the monthly regulator report: the month's
+// verifications, exceptions, status overrides and deaths reported by the
+// civil registry. Reports are stored in the blob store and, with a signing
+// key, get a detached signature like PDF certificates.
+type MonthlyReportService struct {
+	stats    repository.StatsRepository
+	reports  repository.MonthlyReportRepository
+	blobs    storage.BlobStore
+	template *document.ReportTemplate
+	// signer signs the reports; nil when signing is disabled.
+	signer   *signing.Key
+	issuer   string
+	format   string
+	location *time.Location
+}
+
+// NewMonthlyReportService wires dependencies for monthly reports. Months
+// start and end in location.
+func NewMonthlyReportService(stats repository.StatsRepository, reports repository.MonthlyReportRepository, blobs storage.BlobStore, template *document.ReportTemplate, signer *signing.Key, issuer, format string, location *time.Location) *MonthlyReportService {
+	if location == nil {
+		location = time.UTC
+	}
+	return &MonthlyReportService{
+		stats:    stats,
+		reports:  reports,
+		blobs:    blobs,
+		template: template,
+		signer:   signer,
+		issuer:   issuer,
+		format:   format,
+		location: location,
+	}
+}
+
+// MonthlyReportDocument is a stored monthly report.
+type MonthlyReportDocument struct {
+	Period      string
+	Format      string
+	ContentType string
+	Content     []byte
+	// Signature is the detached JWS of Content, empty when signing is disabled.
+	Signature string
+}
+
+// GeneratePrevious compiles the report of the month before the current one;
+// it is the scheduled task.
+func (s *MonthlyReportService) GeneratePrevious(ctx context.Context) error {
+	now := time.Now().In(s.location)
+	period := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, s.location).AddDate(0, -1, 0).Format("2006-01")
+	_, err := s.Generate(ctx, period)
+	return err
+}
+
+// Generate compiles, signs and stores the report of an ended month,
+// replacing the one stored for it.
+func (s *MonthlyReportService) Generate(ctx context.Context, period string) (*MonthlyReportDocument, error) {
+	from, end, err := s.month(period)
+	if err != nil {
+		return nil, err
+	}
+	figures, err := s.stats.MonthlyFigures(ctx, from, end)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	data := document.MonthlyReportData{
+		Issuer:      s.issuer,
+		Period:      period,
+		From:        from,
+		To:          end.AddDate(0, 0, -1),
+		GeneratedAt: now,
+		Verifications: document.ReportVerifications{
+			Attempts:             figures.Valid + figures.Invalid + figures.Review,
+			Valid:                figures.Valid,
+			Invalid:              figures.Invalid,
+			Review:               figures.Review,
+			Automatic:            figures.Automatic,
+			Manual:               figures.Manual,
+			ParticipantsVerified: figures.ParticipantsVerified,
+		},
+		Exceptions: document.ReportExceptions{
+			FacialConflicts: figures.FacialConflicts,
+			CaptureFindings: figures.CaptureFindings,
+			ReviewsPending:  figures.ReviewsPending,
+		},
+		Overrides: document.ReportOverrides{
+			Proposed: figures.Overrides.Raised,
+			Approved: figures.Overrides.Approved,
+			Rejected: figures.Overrides.Rejected,
+		},
+		Deaths: document.ReportDeaths{
+			Reported:  figures.Deaths.Raised,
+			Confirmed: figures.Deaths.Approved,
+			Rejected:  figures.Deaths.Rejected,
+		},
+	}
+	if decided := figures.Valid + figures.Invalid; decided > 0 {
+		rate := percentage(figures.Valid, decided)
+		data.Verifications.PassRate = &rate
+	}
+
+	doc := &MonthlyReportDocument{Period: period, Format: s.format, ContentType: monthlyReportContentType(s.format)}
+	if s.format == MonthlyReportFormatXLSX {
+		doc.Content, err = s.template.XLSX(data)
+	} else {
+		doc.Content, err = s.template.PDF(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.signer != nil {
+		if doc.Signature, err = s.signer.SignDetached(ctx, doc.ContentType, doc.Content); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &domain.MonthlyReport{
+		Period:      period,
+		Format:      s.format,
+		Path:        monthlyReportPath(ctx, period, s.format),
+		Signature:   doc.Signature,
+		GeneratedAt: now,
+	}
+	if err := s.blobs.Put(ctx, report.Path, doc.Content); err != nil {
+		return nil, fmt.Errorf("store monthly report: %w", err)
+	}
+	if err := s.reports.Save(ctx, report); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// Open returns the stored report of an ended month, compiling it first when
+// the scheduled task has not, or its file is gone.
+func (s *MonthlyReportService) Open(ctx context.Context, period string) (*MonthlyReportDocument, error) {
+	period = strings.TrimSpace(period)
+	if _, _, err := s.month(period); err != nil {
+		return nil, err
+	}
+	report, err := s.reports.Get(ctx, period)
+	if err != nil {
+		return nil, err
+	}
+	if report == nil {
+		return s.Generate(ctx, period)
+	}
+	content, err := s.blobs.Get(ctx, report.Path)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return s.Generate(ctx, period)
+		}
+		return nil, err
+	}
+	defer content.Close()
+	raw, err := io.ReadAll(content)
+	if err != nil {
+		return nil, fmt.Errorf("read monthly report: %w", err)
+	}
+	return &MonthlyReportDocument{
+		Period:      report.Period,
+		Format:      report.Format,
+		ContentType: monthlyReportContentType(report.Format),
+		Content:     raw,
+		Signature:   report.Signature,
+	}, nil
+}
+
+// month returns the start of an ended month and of the month after it.
+func (s *MonthlyReportService) month(period string) (time.Time, time.Time, error) {
+	parsed, err := time.Parse("2006-01", period)
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrInvalidReportPeriod
+	}
+	from := time.Date(parsed.Year(), parsed.Month(), 1, 0, 0, 0, 0, s.location)
+	end := from.AddDate(0, 1, 0)
+	if end.After(time.Now()) {
+		return time.Time{}, time.Time{}, ErrMonthlyReportNotReady
+	}
+	return from, end, nil
+}
+
+// monthlyReportPath keeps the reports of tenants other than the default one
+// in a directory of their own.
+func monthlyReportPath(ctx context.Context, period, format string) string {
+	if id := tenant.ID(ctx); id != tenant.DefaultID {
+		return fmt.Sprintf("reports/monthly/%s/%s.%s", id, period, format)
+	}
+	return fmt.Sprintf("reports/monthly/%s.%s", period, format)
+}
+
+func monthlyReportContentType(format string) string {
+	if format == MonthlyReportFormatXLSX {
+		return export.ContentType(export.FormatXLSX)
+	}
+	return "application/pdf"
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/notification"
+	"life-certificates/internal/tenant"
+)
+
+// Audit vocabulary for notification preferences.
+const (
+	auditEntityNotificationPreference       = "notification_preference"
+	auditActionNotificationPreferenceSave   = "notification_preference.update"
+	auditActionNotificationPreferenceReset  = "notification_preference.reset"
+	auditActionNotificationPreferenceUnsub  = "notification_preference.unsubscribe"
+	notificationUnsubscribeActor            = "unsubscribe-link"
+	notificationQuietLayout                 = "15:04"
+	notificationPreferenceChannelsSeparator = ","
+)
+
+var (
+	// ErrInvalidUnsubscribeToken indicates an unsubscribe link that was not issued by this service.
+	ErrInvalidUnsubscribeToken = errors.New("invalid unsubscribe token")
+)
+
+// NotificationPreferenceInput replaces a member's notification preferences.
+type NotificationPreferenceInput struct {
+	// Channels in order of preference: email, sms, whatsapp and push. Empty allows all.
+	Channels []string `json:"channels"`
+	Language string   `json:"language"`
+	// QuietStart and QuietEnd are HH:MM; set both or neither.
+	QuietStart      *string `json:"quiet_start"`
+	QuietEnd        *string `json:"quiet_end"`
+	OptOutReminders bool    `json:"opt_out_reminders"`
+	OptOutResults   bool    `json:"opt_out_results"`
+	EmailOptOut     bool    `json:"email_opt_out"`
+}
+
+// Preferences returns a member's notification preferences, or the defaults when none were saved.
+func (s *NotificationService) Preferences(ctx context.Context, memberID string) (*domain.NotificationPreference, error) {
+	member, err := s.members.GetByID(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, ErrMemberNotFound
+	}
+	preference, err := s.notifications.GetPreference(ctx, member.ID)
+	if err != nil {
+		return nil, err
+	}
+	if preference == nil {
+		preference = &domain.NotificationPreference{MemberID: member.ID, Language: s.defaultLanguage()}
+	}
+	return preference, nil
+}
+
+// SavePreferences replaces a member's notification preferences.
+func (s *NotificationService) SavePreferences(ctx context.Context, actor, memberID string, input NotificationPreferenceInput) (*domain.NotificationPreference, error) {
+	before, err := s.Preferences(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	verr := &ValidationError{}
+	channels := make([]string, 0, len(input.Channels))
+	for _, raw := range input.Channels {
+		channel := strings.ToLower(strings.TrimSpace(raw))
+		switch {
+		case !notification.IsChannel(channel) && channel != notification.ChannelPush:
+			verr.add("channels", "must list email, sms, whatsapp or push")
+		case containsString(channels, channel):
+			verr.add("channels", "must not repeat a channel")
+		default:
+			channels = append(channels, channel)
+		}
+	}
+	language := strings.ToLower(strings.TrimSpace(input.Language))
+	if language == "" {
+		language = s.defaultLanguage()
+	}
+	if !notification.IsLanguage(language) {
+		verr.add("language", "must be one of "+strings.Join(notification.Languages, ", "))
+	}
+	quietStart, quietEnd := optionalString(input.QuietStart), optionalString(input.QuietEnd)
+	switch {
+	case (quietStart == nil) != (quietEnd == nil):
+		verr.add("quiet_start", "must be set together with quiet_end")
+	case quietStart != nil:
+		start, errStart := time.Parse(notificationQuietLayout, *quietStart)
+		end, errEnd := time.Parse(notificationQuietLayout, *quietEnd)
+		if errStart != nil {
+			verr.add("quiet_start", "must be HH:MM")
+		}
+		if errEnd != nil {
+			verr.add("quiet_end", "must be HH:MM")
+		}
+		if errStart == nil && errEnd == nil && start.Equal(end) {
+			verr.add("quiet_end", "must differ from quiet_start")
+		}
+	}
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	preference := &domain.NotificationPreference{
+		MemberID:        before.MemberID,
+		Channels:        strings.Join(channels, notificationPreferenceChannelsSeparator),
+		Language:        language,
+		QuietStart:      quietStart,
+		QuietEnd:        quietEnd,
+		OptOutReminders: input.OptOutReminders,
+		OptOutResults:   input.OptOutResults,
+		EmailOptOut:     input.EmailOptOut,
+		UpdatedBy:       actor,
+		UpdatedAt:       time.Now().UTC(),
+	}
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.notifications.SavePreference(ctx, preference); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionNotificationPreferenceSave, auditEntityNotificationPreference, preference.MemberID, map[string]interface{}{
+			"before": before,
+			"after":  preference,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return preference, nil
+}
+
+// ResetPreferences drops a member's saved preferences, including an email
+// unsubscribe, returning the defaults now in force.
+func (s *NotificationService) ResetPreferences(ctx context.Context, actor, memberID string) (*domain.NotificationPreference, error) {
+	before, err := s.Preferences(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.notifications.DeletePreference(ctx, before.MemberID); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionNotificationPreferenceReset, auditEntityNotificationPreference, before.MemberID, map[string]interface{}{
+			"before": before,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &domain.NotificationPreference{MemberID: before.MemberID, Language: s.defaultLanguage()}, nil
+}
+
+// Unsubscribe stops notification emails to the member named by an unsubscribe link.
+func (s *NotificationService) Unsubscribe(ctx context.Context, token string) error {
+	if s.options.UnsubscribeSecret == "" {
+		return ErrInvalidUnsubscribeToken
+	}
+	tenantID, memberID, ok := notification.ParseUnsubscribeToken(s.options.UnsubscribeSecret, token)
+	if !ok {
+		return ErrInvalidUnsubscribeToken
+	}
+	if tenantID != "" {
+		ctx = tenant.WithID(ctx, tenantID)
+	}
+	preference, err := s.Preferences(ctx, memberID)
+	if errors.Is(err, ErrMemberNotFound) {
+		return ErrInvalidUnsubscribeToken
+	}
+	if err != nil || preference.EmailOptOut {
+		return err
+	}
+
+	preference.EmailOptOut = true
+	preference.UpdatedBy = notificationUnsubscribeActor
+	preference.UpdatedAt = time.Now().UTC()
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.notifications.SavePreference(ctx, preference); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit, notificationUnsubscribeActor, auditActionNotificationPreferenceUnsub, auditEntityNotificationPreference, memberID, nil)
+	})
+}
+
+// unsubscribeURL is the link that stops emails to a member, empty when unsubscribe links are not configured.
+func (s *NotificationService) unsubscribeURL(ctx context.Context, memberID string) string {
+	if s.options.PublicURL == "" || s.options.UnsubscribeSecret == "" {
+		return ""
+	}
+	return strings.TrimRight(s.options.PublicURL, "/") + "/notifications/unsubscribe?token=" + notification.UnsubscribeToken(s.options.UnsubscribeSecret, tenant.ID(ctx), memberID)
+}
+
+// optedOut explains why a member does not want a notification, or returns "" when they do.
+func optedOut(preference *domain.NotificationPreference, template string) string {
+	switch {
+	case preference == nil:
+		return ""
+	case reminderTemplate(template) && preference.OptOutReminders:
+		return "member opted out of reminders"
+	case !reminderTemplate(template) && preference.OptOutResults:
+		return "member opted out of verification results"
+	}
+	return ""
+}
+
+// reminderTemplate reports whether a template is about a verification due,
+// which the reminders opt-out covers, rather than a verification result.
+func reminderTemplate(template string) bool {
+	switch template {
+	case notification.TemplateReminder, notification.TemplateOverdueWarning, notification.TemplatePaymentHoldNotice:
+		return true
+	}
+	return false
+}
+
+// allowedChannels lists the channels a member accepts, in order of preference.
+func allowedChannels(preference *domain.NotificationPreference) []string {
+	channels := append(append([]string{}, notification.Channels...), notification.ChannelPush)
+	if preference != nil && preference.Channels != "" {
+		channels = strings.Split(preference.Channels, notificationPreferenceChannelsSeparator)
+	}
+	if preference != nil && preference.EmailOptOut {
+		kept := channels[:0:0]
+		for _, channel := range channels {
+			if channel != notification.ChannelEmail {
+				kept = append(kept, channel)
+			}
+		}
+		channels = kept
+	}
+	return channels
+}
+
+// afterQuietHours returns when a notification may go out: now, or the end of
+// the member's quiet hours when now falls within them.
+func afterQuietHours(preference *domain.NotificationPreference, now time.Time, location *time.Location) time.Time {
+	if preference == nil || preference.QuietStart == nil || preference.QuietEnd == nil {
+		return now
+	}
+	start, errStart := time.Parse(notificationQuietLayout, *preference.QuietStart)
+	end, errEnd := time.Parse(notificationQuietLayout, *preference.QuietEnd)
+	if errStart != nil || errEnd != nil {
+		return now
+	}
+
+	local := now.In(location)
+	at := func(clock time.Time, dayOffset int) time.Time {
+		return time.Date(local.Year(), local.Month(), local.Day()+dayOffset, clock.Hour(), clock.Minute(), 0, 0, location)
+	}
+	startToday, endToday := at(start, 0), at(end, 0)
+	if startToday.Before(endToday) {
+		if !local.Before(startToday) && local.Before(endToday) {
+			return endToday.UTC()
+		}
+		return now
+	}
+	// The window wraps past midnight, e.g. 21:00 to 07:00.
+	switch {
+	case !local.Before(startToday):
+		return at(end, 1).UTC()
+	case local.Before(endToday):
+		return endToday.UTC()
+	}
+	return now
+}
+
+// language is the template language for a member: their preference, else
+// requested when it has templates, else the configured default.
+func (s *NotificationService) language(preference *domain.NotificationPreference, requested string) string {
+	switch {
+	case preference != nil && preference.Language != "":
+		return preference.Language
+	case requested != "" && notification.IsLanguage(requested):
+		return requested
+	}
+	return s.defaultLanguage()
+}
+
+// defaultLanguage is the template language of members without a preference.
+func (s *NotificationService) defaultLanguage() string {
+	if !notification.IsLanguage(s.options.Language) {
+		return notification.DefaultLanguage
+	}
+	return s.options.Language
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// preferenceFor loads the preferences of the member linked to a participant, nil when there are none.
+func (s *NotificationService) preferenceFor(ctx context.Context, participantID string) (*domain.NotificationPreference, error) {
+	participant, err := s.participants.GetByID(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil || participant.MemberID == nil {
+		return nil, nil
+	}
+	preference, err := s.notifications.GetPreference(ctx, *participant.MemberID)
+	if err != nil {
+		return nil, fmt.Errorf("load preferences of member %s: %w", *participant.MemberID, err)
+	}
+	if preference == nil {
+		preference = &domain.NotificationPreference{MemberID: *participant.MemberID}
+	}
+	return preference, nil
+}
@@ -0,0 +1,748 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/events"
+	"life-certificates/internal/i18n"
+	"life-certificates/internal/notification"
+	"life-certificates/internal/repository"
+)
+
+// JobTypeNotificationSend delivers one logged notification.
+const JobTypeNotificationSend = "notification.send"
+
+// Audit vocabulary for notification template management and delivery retries.
+const (
+	auditEntityNotificationTemplate  = "notification_template"
+	auditActionNotificationTmplSave  = "notification_template.update"
+	auditActionNotificationTmplReset = "notification_template.reset"
+	auditEntityNotificationDelivery  = "notification_delivery"
+	auditActionNotificationRetry     = "notification_delivery.retry"
+)
+
+var (
+	// ErrNotificationTemplateNotFound indicates the template name is not one the service sends.
+	ErrNotificationTemplateNotFound = errors.New("notification template not found")
+	// ErrDeviceNotFound indicates the device is not registered for the participant.
+	ErrDeviceNotFound = errors.New("device not found")
+	// ErrNotificationNotFound indicates the delivery does not exist.
+	ErrNotificationNotFound = errors.New("notification not found")
+	// ErrNotificationNotFailed signals only FAILED deliveries can be retried.
+	ErrNotificationNotFailed = errors.New("notification is not failed")
+)
+
+// NotificationService tells participants about verification outcomes and
+// reminders by email, SMS or WhatsApp and by push to their devices. As an outbox sink it renders a
+// notification per relevant event into the delivery log and queues a job that
+// sends it, retrying failures.
+type NotificationService struct {
+	notifications repository.NotificationRepository
+	participants  repository.ParticipantRepository
+	members       repository.MemberRepository
+	devices       repository.DeviceRepository
+	tenants       repository.TenantRepository
+	audit         repository.AuditLogRepository
+	jobs          *JobService
+	tx            repository.Transactor
+	// channels holds the enabled channels by name.
+	channels map[string]notification.Channel
+	options  NotificationOptions
+}
+
+// NotificationOptions configures rendering and member preferences.
+type NotificationOptions struct {
+	TemplateDir string
+	// PhoneCountryCode replaces the leading 0 of national phone numbers.
+	PhoneCountryCode string
+	// Location is the time zone members' quiet hours are in.
+	Location *time.Location
+	// PublicURL is the externally reachable base URL unsubscribe links point to;
+	// without it and UnsubscribeSecret emails carry no unsubscribe link.
+	PublicURL         string
+	UnsubscribeSecret string
+	// Language is the template language of members without a preference;
+	// languages without templates use notification.DefaultLanguage.
+	Language string
+}
+
+// NotificationTemplateInput replaces a template's subject and body.
+type NotificationTemplateInput struct {
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// RegisterDeviceInput registers the mobile app on a device for push notifications.
+type RegisterDeviceInput struct {
+	// Token is the FCM registration token.
+	Token string `json:"token"`
+	// Platform is ANDROID, IOS or WEB.
+	Platform string `json:"platform"`
+}
+
+// NotificationListInput filters and pages the delivery log.
+type NotificationListInput struct {
+	ParticipantID string
+	Channel       string
+	Recipient     string
+	Template      string
+	Status        string
+	// BatchID keeps the deliveries of a bulk send such as a campaign kickoff.
+	BatchID string
+	// From and To bound the creation date (YYYY-MM-DD), both inclusive.
+	From     string
+	To       string
+	Page     int
+	PageSize int
+}
+
+// NotificationListOutput is a page of deliveries, newest first.
+type NotificationListOutput struct {
+	Items    []domain.NotificationDelivery `json:"items"`
+	Page     int                           `json:"page"`
+	PageSize int                           `json:"page_size"`
+	Total    int64                         `json:"total"`
+}
+
+// notificationJob is the payload of a JobTypeNotificationSend job.
+type notificationJob struct {
+	DeliveryID string `json:"delivery_id"`
+}
+
+// NewNotificationService wires dependencies for participant notifications and
+// registers the delivery job handler. Without channels nothing is sent.
+func NewNotificationService(notifications repository.NotificationRepository, participants repository.ParticipantRepository, members repository.MemberRepository, devices repository.DeviceRepository, tenants repository.TenantRepository, audit repository.AuditLogRepository, jobs *JobService, tx repository.Transactor, channels []notification.Channel, options NotificationOptions) *NotificationService {
+	if options.Location == nil {
+		options.Location = time.UTC
+	}
+	byName := make(map[string]notification.Channel, len(channels))
+	for _, channel := range channels {
+		byName[channel.Name()] = channel
+	}
+	s := &NotificationService{
+		notifications: notifications,
+		participants:  participants,
+		members:       members,
+		devices:       devices,
+		tenants:       tenants,
+		audit:         audit,
+		jobs:          jobs,
+		tx:            tx,
+		channels:      byName,
+		options:       options,
+	}
+	jobs.Register(JobTypeNotificationSend, s.sendJob)
+	return s
+}
+
+// Publish logs and queues the notifications for a verification outcome or
+// reminder: one on the member's channel and one per registered device when
+// push is enabled, following the member's preferences. Notifications falling
+// in the member's quiet hours are sent when they end. Redelivered events find
+// their deliveries already logged and are ignored.
+func (s *NotificationService) Publish(ctx context.Context, event events.Event) error {
+	if len(s.channels) == 0 {
+		return nil
+	}
+	name := notificationTemplateFor(event)
+	if name == "" {
+		return nil
+	}
+	participantID, _ := event.Data["participant_id"].(string)
+	_, err := s.queue(ctx, event.ID, name, participantID, event.Data, nil, time.Now().UTC())
+	return err
+}
+
+// Enabled reports whether any channel is enabled, without which nothing is sent.
+func (s *NotificationService) Enabled() bool {
+	return len(s.channels) > 0
+}
+
+// Remind logs and queues the notifications for a reminder as part of a bulk
+// send, as its participant.reminder_due event would, to go out no earlier
+// than notBefore. It returns how many deliveries were queued for sending.
+func (s *NotificationService) Remind(ctx context.Context, batchID string, reminder *domain.Reminder, notBefore time.Time) (int, error) {
+	if len(s.channels) == 0 {
+		return 0, nil
+	}
+	return s.queue(ctx, reminder.ID, notification.TemplateReminder, reminder.ParticipantID, reminderData(reminder, time.Now().UTC()), &batchID, notBefore)
+}
+
+// queue renders template name with data for a participant and logs a
+// delivery per channel, queueing those to be sent for notBefore or the end
+// of the member's quiet hours. It returns how many deliveries were queued.
+func (s *NotificationService) queue(ctx context.Context, eventID, name, participantID string, data map[string]interface{}, batchID *string, notBefore time.Time) (int, error) {
+	participant, err := s.participants.GetByID(ctx, participantID)
+	if err != nil {
+		return 0, err
+	}
+	if participant == nil {
+		return 0, nil
+	}
+
+	var member *domain.Member
+	var preference *domain.NotificationPreference
+	if participant.MemberID != nil {
+		if member, err = s.members.GetByID(ctx, *participant.MemberID); err != nil {
+			return 0, err
+		}
+	}
+	if member != nil {
+		if preference, err = s.notifications.GetPreference(ctx, member.ID); err != nil {
+			return 0, err
+		}
+	}
+	contact := contactOf(participant, member)
+
+	var deliveries []*domain.NotificationDelivery
+	newDelivery := func(channel, recipient string) *domain.NotificationDelivery {
+		delivery := &domain.NotificationDelivery{
+			ID:            uuid.NewString(),
+			EventID:       eventID,
+			Template:      name,
+			Channel:       channel,
+			ParticipantID: participant.ID,
+			Recipient:     recipient,
+			Status:        domain.NotificationPending,
+			BatchID:       batchID,
+			CreatedAt:     time.Now().UTC(),
+		}
+		deliveries = append(deliveries, delivery)
+		return delivery
+	}
+	allowed := allowedChannels(preference)
+	if reason := optedOut(preference, name); reason != "" {
+		// Log a single skipped delivery so the decision shows in the delivery log.
+		delivery := newDelivery("", "")
+		delivery.Status = domain.NotificationSkipped
+		delivery.LastError = &reason
+	} else {
+		if s.memberChannels() {
+			channel, recipient := s.route(contact, allowed)
+			if delivery := newDelivery(channel, recipient); recipient == "" {
+				reason := "participant has no contact details for an enabled channel"
+				delivery.Status = domain.NotificationSkipped
+				delivery.LastError = &reason
+			}
+		}
+		if _, ok := s.channels[notification.ChannelPush]; ok && containsString(allowed, notification.ChannelPush) {
+			devices, err := s.devices.ListActive(ctx, participant.ID)
+			if err != nil {
+				return 0, err
+			}
+			for _, device := range devices {
+				newDelivery(notification.ChannelPush, device.ID)
+			}
+		}
+	}
+
+	tmpl, err := s.template(ctx, notification.Variant(name, s.language(preference, "")))
+	if err != nil {
+		return 0, err
+	}
+	branding, err := currentBranding(ctx, s.tenants)
+	if err != nil {
+		return 0, err
+	}
+	subject, body, renderErr := tmpl.Render(notification.Data{Name: contact.name, Data: data, Brand: brand(branding)})
+	for _, delivery := range deliveries {
+		switch {
+		case delivery.Status != domain.NotificationPending:
+		case renderErr != nil:
+			// A broken template will not fix itself on redelivery; log it as failed instead.
+			message := renderErr.Error()
+			delivery.Status = domain.NotificationFailed
+			delivery.LastError = &message
+		default:
+			delivery.Subject, delivery.Body = subject, body
+		}
+	}
+
+	runAt := afterQuietHours(preference, notBefore, s.options.Location)
+	queued := 0
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		for _, delivery := range deliveries {
+			created, err := s.notifications.CreateDelivery(ctx, delivery)
+			if err != nil {
+				return err
+			}
+			if !created || delivery.Status != domain.NotificationPending {
+				continue
+			}
+			if _, err := s.jobs.EnqueueAt(ctx, "system", JobTypeNotificationSend, notificationJob{DeliveryID: delivery.ID}, runAt); err != nil {
+				return err
+			}
+			queued++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return queued, nil
+}
+
+// BatchSummary counts a bulk send's deliveries by status.
+func (s *NotificationService) BatchSummary(ctx context.Context, batchID string) (map[domain.NotificationStatus]int64, error) {
+	return s.notifications.CountBatch(ctx, batchID)
+}
+
+// Deliveries returns the delivery log matching the filters, newest first.
+func (s *NotificationService) Deliveries(ctx context.Context, input NotificationListInput) (*NotificationListOutput, error) {
+	filter := repository.NotificationFilter{
+		ParticipantID: strings.TrimSpace(input.ParticipantID),
+		Channel:       strings.ToLower(strings.TrimSpace(input.Channel)),
+		Recipient:     strings.TrimSpace(input.Recipient),
+		Template:      strings.TrimSpace(input.Template),
+		Status:        domain.NotificationStatus(strings.ToUpper(strings.TrimSpace(input.Status))),
+		BatchID:       strings.TrimSpace(input.BatchID),
+	}
+	verr := &ValidationError{}
+	if filter.Channel != "" && !notification.IsChannel(filter.Channel) && filter.Channel != notification.ChannelPush {
+		verr.add("channel", "must be one of email, sms, whatsapp, push")
+	}
+	switch filter.Status {
+	case "", domain.NotificationPending, domain.NotificationSent, domain.NotificationFailed, domain.NotificationSkipped:
+	default:
+		verr.add("status", "must be one of PENDING, SENT, FAILED, SKIPPED")
+	}
+	filter.From, filter.To = parseDateRange(verr, input.From, input.To)
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+	paging := normalizePagination(input.Page, input.PageSize)
+	items, total, err := s.notifications.ListDeliveries(ctx, filter, paging)
+	if err != nil {
+		return nil, err
+	}
+	return &NotificationListOutput{Items: items, Page: paging.Page, PageSize: paging.PageSize, Total: total}, nil
+}
+
+// RetryDelivery queues another send of a FAILED delivery, e.g. once the
+// provider is reachable again or the member's contact details were fixed.
+func (s *NotificationService) RetryDelivery(ctx context.Context, actor, id string) (*domain.NotificationDelivery, error) {
+	delivery, err := s.notifications.GetDelivery(ctx, strings.TrimSpace(id))
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, ErrNotificationNotFound
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		ok, err := s.notifications.RequeueDelivery(ctx, delivery.ID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrNotificationNotFailed
+		}
+		if _, err := s.jobs.Enqueue(ctx, actor, JobTypeNotificationSend, notificationJob{DeliveryID: delivery.ID}); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionNotificationRetry, auditEntityNotificationDelivery, delivery.ID, map[string]interface{}{
+			"channel":    delivery.Channel,
+			"template":   delivery.Template,
+			"attempts":   delivery.Attempts,
+			"last_error": delivery.LastError,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.notifications.GetDelivery(ctx, delivery.ID)
+}
+
+// RegisterDevice records a device token for a participant's push notifications.
+// A token already registered, possibly to another participant after the app
+// was signed in again, moves to this participant and becomes valid again.
+func (s *NotificationService) RegisterDevice(ctx context.Context, participantID string, input RegisterDeviceInput) (*domain.ParticipantDevice, error) {
+	participant, err := s.participants.GetByID(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return nil, ErrParticipantNotFound
+	}
+
+	verr := &ValidationError{}
+	token := strings.TrimSpace(input.Token)
+	if token == "" {
+		verr.add("token", "is required")
+	} else if len(token) > 512 {
+		verr.add("token", "must be at most 512 characters")
+	}
+	platform := domain.DevicePlatform(strings.ToUpper(strings.TrimSpace(input.Platform)))
+	switch platform {
+	case domain.DevicePlatformAndroid, domain.DevicePlatformIOS, domain.DevicePlatformWeb:
+	default:
+		verr.add("platform", "must be ANDROID, IOS or WEB")
+	}
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	device := &domain.ParticipantDevice{
+		ID:            uuid.NewString(),
+		ParticipantID: participant.ID,
+		Token:         token,
+		Platform:      platform,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := s.devices.Register(ctx, device); err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// Devices lists a participant's registered devices, including invalidated ones.
+func (s *NotificationService) Devices(ctx context.Context, participantID string) ([]domain.ParticipantDevice, error) {
+	participant, err := s.participants.GetByID(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return nil, ErrParticipantNotFound
+	}
+	return s.devices.ListByParticipant(ctx, participant.ID)
+}
+
+// RemoveDevice unregisters a device, e.g. when the participant signs out of the app.
+func (s *NotificationService) RemoveDevice(ctx context.Context, participantID, deviceID string) error {
+	device, err := s.devices.GetByID(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+	if device == nil || device.ParticipantID != participantID {
+		return ErrDeviceNotFound
+	}
+	return s.devices.Delete(ctx, device.ID)
+}
+
+// Templates returns every template in force with where it came from.
+func (s *NotificationService) Templates(ctx context.Context) ([]notification.Template, error) {
+	names := notification.Names()
+	templates := make([]notification.Template, 0, len(names))
+	for _, name := range names {
+		tmpl, err := s.template(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, tmpl)
+	}
+	return templates, nil
+}
+
+// SaveTemplate stores a template override in the database after checking it parses.
+func (s *NotificationService) SaveTemplate(ctx context.Context, actor, name string, input NotificationTemplateInput) (*notification.Template, error) {
+	name = strings.TrimSpace(name)
+	if !notification.IsKnown(name) {
+		return nil, ErrNotificationTemplateNotFound
+	}
+	tmpl := notification.Template{Name: name, Subject: input.Subject, Body: input.Body, Source: notification.SourceDatabase}
+	verr := &ValidationError{}
+	if strings.TrimSpace(tmpl.Subject) == "" {
+		verr.add("subject", "is required")
+	}
+	if strings.TrimSpace(tmpl.Body) == "" {
+		verr.add("body", "is required")
+	}
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+	if err := tmpl.Check(); err != nil {
+		return nil, &ValidationError{Fields: map[string]string{"template": err.Error()}}
+	}
+
+	before, err := s.template(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.notifications.SaveTemplate(ctx, &domain.NotificationTemplate{
+			Name:      name,
+			Subject:   tmpl.Subject,
+			Body:      tmpl.Body,
+			UpdatedBy: actor,
+			UpdatedAt: time.Now().UTC(),
+		}); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionNotificationTmplSave, auditEntityNotificationTemplate, name, map[string]interface{}{
+			"before": before,
+			"after":  tmpl,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tmpl, nil
+}
+
+// ResetTemplate removes the database override, returning the file or built-in template now in force.
+func (s *NotificationService) ResetTemplate(ctx context.Context, actor, name string) (*notification.Template, error) {
+	name = strings.TrimSpace(name)
+	if !notification.IsKnown(name) {
+		return nil, ErrNotificationTemplateNotFound
+	}
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.notifications.DeleteTemplate(ctx, name); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionNotificationTmplReset, auditEntityNotificationTemplate, name, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := notification.Load(s.options.TemplateDir, name)
+	if err != nil {
+		return nil, err
+	}
+	return &tmpl, nil
+}
+
+// sendJob sends a logged notification; a failure fails the attempt so the job retries it.
+func (s *NotificationService) sendJob(ctx context.Context, payload json.RawMessage) (interface{}, error) {
+	var input notificationJob
+	if err := json.Unmarshal(payload, &input); err != nil {
+		return nil, fmt.Errorf("decode notification job: %w", err)
+	}
+	delivery, err := s.notifications.GetDelivery(ctx, input.DeliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil || delivery.Status == domain.NotificationSent || delivery.Status == domain.NotificationSkipped {
+		return nil, nil
+	}
+	channel, ok := s.channels[delivery.Channel]
+	if !ok {
+		return nil, fmt.Errorf("notification channel %s is not enabled", delivery.Channel)
+	}
+	// Preferences may have changed while the delivery waited, e.g. through an unsubscribe link.
+	preference, err := s.preferenceFor(ctx, delivery.ParticipantID)
+	if err != nil {
+		return nil, err
+	}
+	reason := optedOut(preference, delivery.Template)
+	if reason == "" && !containsString(allowedChannels(preference), delivery.Channel) {
+		reason = "member no longer accepts " + delivery.Channel + " notifications"
+	}
+	if reason != "" {
+		delivery.Status = domain.NotificationSkipped
+		delivery.LastError = &reason
+		return nil, s.notifications.UpdateDelivery(ctx, delivery)
+	}
+
+	branding, err := currentBranding(ctx, s.tenants)
+	if err != nil {
+		return nil, err
+	}
+	msg := notification.Message{To: delivery.Recipient, From: brandSender(branding, delivery.Channel), Subject: delivery.Subject, Body: delivery.Body}
+	if delivery.Channel == notification.ChannelEmail && preference != nil {
+		msg.UnsubscribeURL = s.unsubscribeURL(ctx, preference.MemberID)
+	}
+	if delivery.Channel == notification.ChannelPush {
+		device, err := s.devices.GetByID(ctx, delivery.Recipient)
+		if err != nil {
+			return nil, err
+		}
+		if device == nil || device.InvalidatedAt != nil {
+			reason := "device is no longer registered"
+			delivery.Status = domain.NotificationSkipped
+			delivery.LastError = &reason
+			return nil, s.notifications.UpdateDelivery(ctx, delivery)
+		}
+		msg.To = device.Token
+		msg.Data = map[string]string{"template": delivery.Template, "participant_id": delivery.ParticipantID}
+	}
+
+	delivery.Attempts++
+	providerID, sendErr := channel.Send(ctx, msg)
+	switch {
+	case errors.Is(sendErr, notification.ErrInvalidToken):
+		// The app is gone from this device: stop using it rather than retrying.
+		now := time.Now().UTC()
+		if err := s.devices.Invalidate(context.WithoutCancel(ctx), delivery.Recipient, now); err != nil {
+			return nil, err
+		}
+		message := sendErr.Error()
+		delivery.Status = domain.NotificationSkipped
+		delivery.LastError = &message
+		sendErr = nil
+	case sendErr != nil:
+		message := sendErr.Error()
+		delivery.Status = domain.NotificationFailed
+		delivery.LastError = &message
+	default:
+		now := time.Now().UTC()
+		delivery.Status = domain.NotificationSent
+		delivery.LastError = nil
+		delivery.SentAt = &now
+		if providerID != "" {
+			delivery.ProviderMessageID = &providerID
+		}
+	}
+	if err := s.notifications.UpdateDelivery(context.WithoutCancel(ctx), delivery); err != nil {
+		return nil, err
+	}
+	if sendErr != nil {
+		return nil, sendErr
+	}
+	return map[string]interface{}{"delivery_id": delivery.ID}, nil
+}
+
+// SendCode sends a one-time code to the participant on the first enabled
+// channel in their member's order of preference, in their language or else the
+// request's. member is nil for participants without a member record, who are
+// reached on their own contact details. Unlike other notifications it is sent
+// straight away, ignoring quiet hours and opt-outs the participant asked for
+// it, and never written to the delivery log so the code is not stored.
+func (s *NotificationService) SendCode(ctx context.Context, participant *domain.Participant, member *domain.Member, code string, ttl time.Duration) (channel, recipient string, err error) {
+	var preference *domain.NotificationPreference
+	if member != nil {
+		if preference, err = s.notifications.GetPreference(ctx, member.ID); err != nil {
+			return "", "", err
+		}
+	}
+	contact := contactOf(participant, member)
+	channel, recipient = s.route(contact, allowedChannels(preference))
+	if recipient == "" {
+		return "", "", fmt.Errorf("participant has no contact details for an enabled channel")
+	}
+
+	// A member without a preference gets the code in the language they asked for it in.
+	tmpl, err := s.template(ctx, notification.Variant(notification.TemplateOneTimeCode, s.language(preference, i18n.FromContext(ctx))))
+	if err != nil {
+		return "", "", err
+	}
+	branding, err := currentBranding(ctx, s.tenants)
+	if err != nil {
+		return "", "", err
+	}
+	subject, body, err := tmpl.Render(notification.Data{Name: contact.name, Brand: brand(branding), Data: map[string]interface{}{
+		"code":            code,
+		"expires_minutes": int(ttl.Round(time.Minute) / time.Minute),
+	}})
+	if err != nil {
+		return "", "", err
+	}
+	msg := notification.Message{To: recipient, From: brandSender(branding, channel), Subject: subject, Body: body}
+	if _, err := s.channels[channel].Send(ctx, msg); err != nil {
+		return "", "", fmt.Errorf("send code by %s: %w", channel, err)
+	}
+	return channel, recipient, nil
+}
+
+// memberChannels reports whether any channel reaching members by address or phone is enabled.
+func (s *NotificationService) memberChannels() bool {
+	for _, name := range notification.Channels {
+		if _, ok := s.channels[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// recipientContact is where a participant's notifications go.
+type recipientContact struct {
+	name        string
+	email       string
+	phoneNumber string
+}
+
+// contactOf prefers the linked member's name and contact details and falls
+// back to the participant's own for those the member lacks. member may be nil.
+func contactOf(participant *domain.Participant, member *domain.Member) recipientContact {
+	contact := recipientContact{name: participant.Name, email: participant.Email, phoneNumber: participant.PhoneNumber}
+	if member != nil {
+		contact.name = firstNonEmpty(member.FullName, contact.name)
+		contact.email = firstNonEmpty(strings.TrimSpace(member.Email), contact.email)
+		contact.phoneNumber = firstNonEmpty(strings.TrimSpace(member.PhoneNumber), contact.phoneNumber)
+	}
+	return contact
+}
+
+// route picks the first enabled member channel in the member's order of
+// preference that the participant has contact details for.
+func (s *NotificationService) route(contact recipientContact, allowed []string) (channel, recipient string) {
+	for _, name := range allowed {
+		if _, ok := s.channels[name]; !ok || !notification.IsChannel(name) {
+			continue
+		}
+		recipient := strings.TrimSpace(contact.email)
+		if name != notification.ChannelEmail {
+			recipient = notification.NormalizePhone(contact.phoneNumber, s.options.PhoneCountryCode)
+		}
+		if recipient != "" {
+			return name, recipient
+		}
+	}
+	return "", ""
+}
+
+// template resolves a database override, then a file in templateDir, then the built-in template.
+func (s *NotificationService) template(ctx context.Context, name string) (notification.Template, error) {
+	override, err := s.notifications.GetTemplate(ctx, name)
+	if err != nil {
+		return notification.Template{}, err
+	}
+	if override != nil {
+		return notification.Template{Name: name, Subject: override.Subject, Body: override.Body, Source: notification.SourceDatabase}, nil
+	}
+	return notification.Load(s.options.TemplateDir, name)
+}
+
+// notificationTemplateFor picks the template for an event, or "" when the event does not notify.
+func notificationTemplateFor(event events.Event) string {
+	switch event.Type {
+	case events.TypeVerificationCompleted:
+		switch event.Data["status"] {
+		case string(domain.LifeCertificateStatusValid):
+			return notification.TemplateVerificationSuccess
+		case string(domain.LifeCertificateStatusInvalid):
+			return notification.TemplateVerificationFailure
+		}
+	case events.TypeVerificationReviewRequired:
+		return notification.TemplateVerificationReview
+	case events.TypeReminderDue:
+		return notification.TemplateReminder
+	case events.TypeParticipantEscalated:
+		switch event.Data["stage"] {
+		case string(domain.CampaignStageReminder):
+			return notification.TemplateReminder
+		case string(domain.CampaignStageWarning):
+			return notification.TemplateOverdueWarning
+		case string(domain.CampaignStageHoldRecommended):
+			return notification.TemplatePaymentHoldNotice
+		}
+	}
+	return ""
+}
+
+// brand is the part of a tenant's branding templates can print.
+func brand(branding domain.TenantBranding) notification.Brand {
+	return notification.Brand{DisplayName: branding.DisplayName, Footer: branding.FooterText}
+}
+
+// brandSender returns the tenant's sender for a channel, "" to use the configured one.
+func brandSender(branding domain.TenantBranding, channel string) string {
+	switch channel {
+	case notification.ChannelEmail:
+		return branding.EmailFrom
+	case notification.ChannelSMS:
+		return branding.SMSFrom
+	case notification.ChannelWhatsApp:
+		return branding.WhatsAppPhoneNumberID
+	}
+	return ""
+}
@@ -0,0 +1,475 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+)
+
+// Audit vocabulary for officer management.
+const (
+	auditEntityOfficer       = "officer"
+	auditActionOfficerCreate = "officer.create"
+	auditActionOfficerUpdate = "officer.update"
+)
+
+// Officer report flags.
+const (
+	OfficerFlagDailyPeak  = "DAILY_PEAK"
+	OfficerFlagRapidField = "RAPID_FIELD_VERIFICATIONS"
+	OfficerFlagHighVolume = "HIGH_VOLUME"
+)
+
+const (
+	// officerVolumeFactor is how many times the median of the period's
+	// active officers an officer must verify to be flagged HIGH_VOLUME.
+	officerVolumeFactor = 3
+	// officerVolumeMin keeps officers with a handful of verifications from
+	// being flagged HIGH_VOLUME in quiet periods.
+	officerVolumeMin = 10
+	// officerVolumePeers is how many active officers make a median worth comparing to.
+	officerVolumePeers = 3
+)
+
+var (
+	// ErrOfficerNotFound indicates the requested officer does not exist.
+	ErrOfficerNotFound = errors.New("officer not found")
+	// ErrOfficerConflict indicates another officer already uses the code or username.
+	ErrOfficerConflict = errors.New("officer code or username already in use")
+	// ErrOfficerInactive indicates the officer may no longer perform verifications.
+	ErrOfficerInactive = errors.New("officer is inactive")
+	// ErrOfficerCredentialExpired indicates the officer's field credential has lapsed.
+	ErrOfficerCredentialExpired = errors.New("officer credential has expired")
+	// ErrOperatorNotOfficer indicates a kiosk operator's account belongs to no officer.
+	ErrOperatorNotOfficer = errors.New("operator is not registered as an officer")
+)
+
+// OfficerThresholds sets when the officer report flags an officer.
+type OfficerThresholds struct {
+	// MaxDailyVerifications is the most verifications expected of an officer on one day.
+	MaxDailyVerifications int
+	// MinFieldInterval is the shortest plausible time between two manual or
+	// proxy verifications by one officer; zero disables the check.
+	MinFieldInterval time.Duration
+}
+
+// OfficerService manages the officers who perform manual, proxy and kiosk
+// verifications and reports what each of them verified.
+type OfficerService struct {
+	officers   repository.OfficerRepository
+	branches   repository.BranchRepository
+	audit      repository.AuditLogRepository
+	tx         repository.Transactor
+	thresholds OfficerThresholds
+}
+
+// NewOfficerService wires dependencies for officer management.
+func NewOfficerService(officers repository.OfficerRepository, branches repository.BranchRepository, audit repository.AuditLogRepository, tx repository.Transactor, thresholds OfficerThresholds) *OfficerService {
+	return &OfficerService{officers: officers, branches: branches, audit: audit, tx: tx, thresholds: thresholds}
+}
+
+// CreateOfficerInput registers an officer. CredentialExpiresAt is a
+// YYYY-MM-DD date; Username is the account the officer signs in with.
+type CreateOfficerInput struct {
+	Code                string  `json:"code"`
+	Name                string  `json:"name"`
+	BranchID            string  `json:"branch_id"`
+	Region              string  `json:"region"`
+	Username            *string `json:"username"`
+	CredentialNumber    string  `json:"credential_number"`
+	CredentialExpiresAt string  `json:"credential_expires_at"`
+}
+
+// UpdateOfficerInput changes an officer; nil fields are left untouched. An
+// empty username or credential_expires_at clears it.
+type UpdateOfficerInput struct {
+	Code                *string               `json:"code"`
+	Name                *string               `json:"name"`
+	BranchID            *string               `json:"branch_id"`
+	Region              *string               `json:"region"`
+	Username            *string               `json:"username"`
+	CredentialNumber    *string               `json:"credential_number"`
+	CredentialExpiresAt *string               `json:"credential_expires_at"`
+	Status              *domain.OfficerStatus `json:"status"`
+}
+
+// OfficerListInput filters and paginates officers.
+type OfficerListInput struct {
+	BranchID string
+	Region   string
+	Status   string
+	Page     int
+	PageSize int
+}
+
+// OfficerListOutput is a page of officers.
+type OfficerListOutput struct {
+	Items    []domain.Officer `json:"items"`
+	Page     int              `json:"page"`
+	PageSize int              `json:"page_size"`
+	Total    int64            `json:"total"`
+}
+
+// OfficerReportInput selects a report's period, a year (2024), a quarter
+// (2024-Q3) or a month (2024-07), and optionally a branch or region.
+type OfficerReportInput struct {
+	Period   string
+	BranchID string
+	Region   string
+}
+
+// OfficerReportRow is what an officer verified in the period. Kiosk counts
+// the selfies the officer captured as a kiosk operator; PeakDay is the most
+// verifications on one day and Rapid the manual or proxy verifications that
+// followed the officer's previous one too closely. Officer IDs found on
+// certificates but not registered have an empty code and name.
+type OfficerReportRow struct {
+	OfficerID     string   `json:"officer_id"`
+	Code          string   `json:"code"`
+	Name          string   `json:"name"`
+	BranchID      string   `json:"branch_id"`
+	Region        string   `json:"region"`
+	Verifications int64    `json:"verifications"`
+	Manual        int64    `json:"manual"`
+	Proxy         int64    `json:"proxy"`
+	Kiosk         int64    `json:"kiosk"`
+	Valid         int64    `json:"valid"`
+	Invalid       int64    `json:"invalid"`
+	Review        int64    `json:"review"`
+	Participants  int64    `json:"participants"`
+	PeakDay       int64    `json:"peak_day"`
+	Rapid         int64    `json:"rapid"`
+	Flags         []string `json:"flags"`
+}
+
+// OfficerReport lists the officers' verifications of a period, busiest first.
+type OfficerReport struct {
+	Period  string             `json:"period"`
+	From    string             `json:"from"`
+	To      string             `json:"to"`
+	Rows    []OfficerReportRow `json:"rows"`
+	Flagged int                `json:"flagged"`
+}
+
+// Create registers an ACTIVE officer.
+func (s *OfficerService) Create(ctx context.Context, actor string, input CreateOfficerInput) (*domain.Officer, error) {
+	now := time.Now().UTC()
+	officer := &domain.Officer{
+		ID:               uuid.NewString(),
+		Code:             strings.TrimSpace(input.Code),
+		Name:             strings.TrimSpace(input.Name),
+		BranchID:         strings.TrimSpace(input.BranchID),
+		Region:           strings.TrimSpace(input.Region),
+		Username:         optionalString(input.Username),
+		CredentialNumber: strings.TrimSpace(input.CredentialNumber),
+		Status:           domain.OfficerActive,
+		CreatedBy:        actor,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	verr := &ValidationError{}
+	officer.CredentialExpiresAt = parseCredentialExpiry(input.CredentialExpiresAt, verr)
+	if err := registeredBranch(ctx, s.branches, officer.BranchID, verr); err != nil {
+		return nil, err
+	}
+	if err := s.validate(ctx, officer, verr); err != nil {
+		return nil, err
+	}
+
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.officers.Create(ctx, officer); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionOfficerCreate, auditEntityOfficer, officer.ID, map[string]interface{}{
+			"officer": officer,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return officer, nil
+}
+
+// Update changes the provided fields of an officer. Setting the status to
+// INACTIVE stops the officer from performing verifications.
+func (s *OfficerService) Update(ctx context.Context, actor, id string, input UpdateOfficerInput) (*domain.Officer, error) {
+	officer, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *officer
+
+	verr := &ValidationError{}
+	if input.Code != nil {
+		officer.Code = strings.TrimSpace(*input.Code)
+	}
+	if input.Name != nil {
+		officer.Name = strings.TrimSpace(*input.Name)
+	}
+	// Officers registered before their branch keep it until they move.
+	if input.BranchID != nil && strings.TrimSpace(*input.BranchID) != officer.BranchID {
+		officer.BranchID = strings.TrimSpace(*input.BranchID)
+		if err := registeredBranch(ctx, s.branches, officer.BranchID, verr); err != nil {
+			return nil, err
+		}
+	}
+	if input.Region != nil {
+		officer.Region = strings.TrimSpace(*input.Region)
+	}
+	if input.Username != nil {
+		officer.Username = optionalString(input.Username)
+	}
+	if input.CredentialNumber != nil {
+		officer.CredentialNumber = strings.TrimSpace(*input.CredentialNumber)
+	}
+	if input.CredentialExpiresAt != nil {
+		officer.CredentialExpiresAt = parseCredentialExpiry(*input.CredentialExpiresAt, verr)
+	}
+	if input.Status != nil {
+		officer.Status = domain.OfficerStatus(strings.ToUpper(strings.TrimSpace(string(*input.Status))))
+	}
+	if err := s.validate(ctx, officer, verr); err != nil {
+		return nil, err
+	}
+	officer.UpdatedAt = time.Now().UTC()
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.officers.Update(ctx, officer); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionOfficerUpdate, auditEntityOfficer, officer.ID, map[string]interface{}{
+			"before": before,
+			"after":  officer,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return officer, nil
+}
+
+// Get returns a single officer.
+func (s *OfficerService) Get(ctx context.Context, id string) (*domain.Officer, error) {
+	officer, err := s.officers.GetByID(ctx, strings.TrimSpace(id))
+	if err != nil {
+		return nil, err
+	}
+	if officer == nil {
+		return nil, ErrOfficerNotFound
+	}
+	return officer, nil
+}
+
+// List returns officers by name.
+func (s *OfficerService) List(ctx context.Context, input OfficerListInput) (*OfficerListOutput, error) {
+	filter := repository.OfficerFilter{
+		BranchID: strings.TrimSpace(input.BranchID),
+		Region:   strings.TrimSpace(input.Region),
+	}
+	if status := strings.ToUpper(strings.TrimSpace(input.Status)); status != "" {
+		switch domain.OfficerStatus(status) {
+		case domain.OfficerActive, domain.OfficerInactive:
+			filter.Status = domain.OfficerStatus(status)
+		default:
+			return nil, &ValidationError{Fields: map[string]string{"status": "must be ACTIVE or INACTIVE"}}
+		}
+	}
+
+	page := normalizePagination(input.Page, input.PageSize)
+	officers, total, err := s.officers.List(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+	return &OfficerListOutput{Items: officers, Page: page.Page, PageSize: page.PageSize, Total: total}, nil
+}
+
+// Report aggregates the period's verifications by the officer who performed
+// them and flags officers whose activity looks implausible: more than the
+// expected verifications on a day, field verifications in too quick a
+// succession, or several times the volume of the typical officer.
+func (s *OfficerService) Report(ctx context.Context, input OfficerReportInput) (*OfficerReport, error) {
+	period := strings.ToUpper(strings.TrimSpace(input.Period))
+	from, end, err := parseReportPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+	activity, err := s.officers.Activity(ctx, from, end, s.thresholds.MinFieldInterval)
+	if err != nil {
+		return nil, err
+	}
+	officers, err := s.officers.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	branchID, region := strings.TrimSpace(input.BranchID), strings.TrimSpace(input.Region)
+	filtered := branchID != "" || region != ""
+	byID := make(map[string]domain.Officer, len(officers))
+	for _, officer := range officers {
+		byID[officer.ID] = officer
+	}
+	var volumes []int64
+	for _, row := range activity {
+		volumes = append(volumes, row.Verifications)
+	}
+	typical := median(volumes)
+
+	out := &OfficerReport{
+		Period: period,
+		From:   from.Format("2006-01-02"),
+		To:     end.AddDate(0, 0, -1).Format("2006-01-02"),
+		Rows:   make([]OfficerReportRow, 0, len(activity)),
+	}
+	for _, row := range activity {
+		officer, registered := byID[row.OfficerID]
+		if filtered && (!registered || (branchID != "" && officer.BranchID != branchID) || (region != "" && officer.Region != region)) {
+			continue
+		}
+		reported := OfficerReportRow{
+			OfficerID:     row.OfficerID,
+			Code:          officer.Code,
+			Name:          officer.Name,
+			BranchID:      officer.BranchID,
+			Region:        officer.Region,
+			Verifications: row.Verifications,
+			Manual:        row.Manual,
+			Proxy:         row.Proxy,
+			Kiosk:         row.Kiosk,
+			Valid:         row.Valid,
+			Invalid:       row.Invalid,
+			Review:        row.Review,
+			Participants:  row.Participants,
+			PeakDay:       row.PeakDay,
+			Rapid:         row.Rapid,
+			Flags:         []string{},
+		}
+		if row.PeakDay > int64(s.thresholds.MaxDailyVerifications) {
+			reported.Flags = append(reported.Flags, OfficerFlagDailyPeak)
+		}
+		if s.thresholds.MinFieldInterval > 0 && row.Rapid > 0 {
+			reported.Flags = append(reported.Flags, OfficerFlagRapidField)
+		}
+		if len(volumes) >= officerVolumePeers && row.Verifications >= officerVolumeMin && float64(row.Verifications) > officerVolumeFactor*typical {
+			reported.Flags = append(reported.Flags, OfficerFlagHighVolume)
+		}
+		if len(reported.Flags) > 0 {
+			out.Flagged++
+		}
+		out.Rows = append(out.Rows, reported)
+	}
+	sort.SliceStable(out.Rows, func(i, j int) bool {
+		return out.Rows[i].Verifications > out.Rows[j].Verifications
+	})
+	return out, nil
+}
+
+func (s *OfficerService) validate(ctx context.Context, officer *domain.Officer, verr *ValidationError) error {
+	switch {
+	case officer.Code == "":
+		verr.add("code", "is required")
+	case len(officer.Code) > 64:
+		verr.add("code", "must be at most 64 characters")
+	}
+	switch {
+	case officer.Name == "":
+		verr.add("name", "is required")
+	case len(officer.Name) > 150:
+		verr.add("name", "must be at most 150 characters")
+	}
+	switch {
+	case officer.BranchID == "":
+		verr.add("branch_id", "is required")
+	case len(officer.BranchID) > 64:
+		verr.add("branch_id", "must be at most 64 characters")
+	}
+	if len(officer.Region) > 100 {
+		verr.add("region", "must be at most 100 characters")
+	}
+	if officer.Username != nil && len(*officer.Username) > 100 {
+		verr.add("username", "must be at most 100 characters")
+	}
+	if len(officer.CredentialNumber) > 64 {
+		verr.add("credential_number", "must be at most 64 characters")
+	}
+	switch officer.Status {
+	case domain.OfficerActive, domain.OfficerInactive:
+	default:
+		verr.add("status", "must be ACTIVE or INACTIVE")
+	}
+	if err := verr.errOrNil(); err != nil {
+		return err
+	}
+
+	existing, err := s.officers.GetByCode(ctx, officer.Code)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != officer.ID {
+		return ErrOfficerConflict
+	}
+	if officer.Username != nil {
+		existing, err := s.officers.GetByUsername(ctx, *officer.Username)
+		if err != nil {
+			return err
+		}
+		if existing != nil && existing.ID != officer.ID {
+			return ErrOfficerConflict
+		}
+	}
+	return nil
+}
+
+// performingOfficer returns the officer a verification names, provided they
+// may still perform verifications at now.
+func performingOfficer(ctx context.Context, officers repository.OfficerRepository, id string, now time.Time) (*domain.Officer, error) {
+	officer, err := officers.GetByID(ctx, strings.TrimSpace(id))
+	if err != nil {
+		return nil, err
+	}
+	if officer == nil {
+		return nil, ErrOfficerNotFound
+	}
+	return officer, officerEligible(officer, now)
+}
+
+// officerEligible tells why an officer may not perform a verification at now, if so.
+func officerEligible(officer *domain.Officer, now time.Time) error {
+	if officer.Status != domain.OfficerActive {
+		return ErrOfficerInactive
+	}
+	if officer.CredentialExpiresAt != nil && !now.Before(officer.CredentialExpiresAt.AddDate(0, 0, 1)) {
+		return ErrOfficerCredentialExpired
+	}
+	return nil
+}
+
+// parseCredentialExpiry reads a YYYY-MM-DD credential expiry; empty means none.
+func parseCredentialExpiry(raw string, verr *ValidationError) *time.Time {
+	expires, err := parseDateParam("credential_expires_at", raw)
+	if err != nil {
+		verr.add("credential_expires_at", "must be a YYYY-MM-DD date")
+		return nil
+	}
+	return expires
+}
+
+// median returns the middle of the values, or the mean of the middle two.
+func median(values []int64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]int64(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return float64(sorted[middle])
+	}
+	return float64(sorted[middle-1]+sorted[middle]) / 2
+}
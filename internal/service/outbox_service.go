@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/events"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/tenant"
+)
+
+const (
+	outboxPollInterval = 2 * time.Second
+	outboxBatchSize    = 100
+	outboxLease        = time.Minute
+	outboxBaseBackoff  = 5 * time.Second
+	outboxMaxBackoff   = 10 * time.Minute
+)
+
+// OutboxService stores events in the caller's transaction and publishes them
+// afterwards to every sink, guaranteeing at-least-once delivery. Consumers
+// should deduplicate on the event ID.
+type OutboxService struct {
+	outbox repository.OutboxRepository
+	sinks  []events.Publisher
+}
+
+// NewOutboxService wires the outbox with the sinks events are published to.
+func NewOutboxService(outbox repository.OutboxRepository, sinks ...events.Publisher) *OutboxService {
+	return &OutboxService{outbox: outbox, sinks: sinks}
+}
+
+// Publish writes the event to the outbox using the transaction carried by ctx,
+// stamped with the tenant of ctx.
+func (s *OutboxService) Publish(ctx context.Context, event events.Event) error {
+	event.Tenant = tenant.ID(ctx)
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+	return s.outbox.Create(ctx, &domain.OutboxEvent{
+		ID:            event.ID,
+		TenantID:      event.Tenant,
+		Type:          event.Type,
+		Payload:       string(payload),
+		NextAttemptAt: event.OccurredAt,
+		CreatedAt:     event.OccurredAt,
+	})
+}
+
+// Start runs the dispatcher until ctx is cancelled.
+func (s *OutboxService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(outboxPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.dispatchDue(ctx)
+			}
+		}
+	}()
+}
+
+func (s *OutboxService) dispatchDue(ctx context.Context) {
+	now := time.Now().UTC()
+	due, err := s.outbox.ListDue(tenant.System(ctx), now, outboxBatchSize)
+	if err != nil {
+		log.Printf("[outbox] list due events: %v", err)
+		return
+	}
+
+	for i := range due {
+		entry := &due[i]
+		ctx := tenant.WithID(ctx, entry.TenantID)
+		ok, err := s.outbox.Claim(ctx, entry.ID, entry.NextAttemptAt, now.Add(outboxLease))
+		if err != nil {
+			log.Printf("[outbox] claim event %s: %v", entry.ID, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		s.publish(ctx, entry)
+	}
+}
+
+func (s *OutboxService) publish(ctx context.Context, entry *domain.OutboxEvent) {
+	var event events.Event
+	err := json.Unmarshal([]byte(entry.Payload), &event)
+	if err == nil {
+		for _, sink := range s.sinks {
+			if err = sink.Publish(ctx, event); err != nil {
+				break
+			}
+		}
+	}
+
+	now := time.Now().UTC()
+	if err == nil {
+		if err := s.outbox.MarkPublished(ctx, entry.ID, now); err != nil {
+			log.Printf("[outbox] mark event %s published: %v", entry.ID, err)
+		}
+		return
+	}
+
+	attempts := entry.Attempts + 1
+	log.Printf("[outbox] publish event %s (attempt %d): %v", entry.ID, attempts, err)
+	next := now.Add(exponentialBackoff(outboxBaseBackoff, outboxMaxBackoff, attempts))
+	if err := s.outbox.MarkFailed(ctx, entry.ID, attempts, next, err.Error()); err != nil {
+		log.Printf("[outbox] mark event %s failed: %v", entry.ID, err)
+	}
+}
@@ -0,0 +1,22 @@
+package service
+
+import "life-certificates/internal/repository"
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// normalizePagination applies defaults and caps to client supplied paging values.
+func normalizePagination(page, pageSize int) repository.Pagination {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return repository.Pagination{Page: page, PageSize: pageSize}
+}
@@ -4,20 +4,34 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/mail"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 
 	"life-certificates/internal/domain"
+	"life-certificates/internal/events"
 	"life-certificates/internal/frcore"
+	"life-certificates/internal/imaging"
 	"life-certificates/internal/repository"
+	"life-certificates/internal/tracing"
 )
 
 // Domain level errors used by handlers for precise status codes.
 var (
-	ErrParticipantExists   = errors.New("participant with nik already exists")
-	ErrParticipantNotFound = errors.New("participant not found")
+	ErrParticipantExists       = errors.New("participant with nik already exists")
+	ErrParticipantNotFound     = errors.New("participant not found")
+	ErrMemberDeceased          = errors.New("member is deceased")
+	ErrMemberAlreadyRegistered = errors.New("member is already registered as a participant")
+	ErrParticipantSuspended    = errors.New("participant is suspended")
+	ErrParticipantBlocked      = errors.New("participant is blocked")
+	ErrStatusReasonRequired    = errors.New("reason is required")
+	// ErrUnsupportedImageFormat rejects uploads that are not JPEG, PNG, HEIC or WebP photos.
+	ErrUnsupportedImageFormat = imaging.ErrUnsupportedFormat
+	// ErrFRCoreBusy refuses work while FR Core calls are queued beyond their limits.
+	ErrFRCoreBusy = frcore.ErrOverloaded
 )
 
 // ParticipantService provides registration operations.
@@ -25,7 +39,24 @@ type ParticipantService struct {
 	participants repository.ParticipantRepository
 	frIdentities repository.FRIdentityRepository
 	frClient     frcore.Client
+	images       *imaging.Processor
 	certificates repository.LifeCertificateRepository
+	states       repository.VerificationStateRepository
+	locks        repository.VerificationLockRepository
+	conflicts    repository.FacialConflictRepository
+	// verificationDevices is the registry of devices the participant verified from.
+	verificationDevices repository.VerificationDeviceRepository
+	homeVisits          repository.HomeVisitRepository
+	members             repository.MemberRepository
+	campaigns           repository.CampaignRepository
+	profiles            repository.VerificationProfileRepository
+	tx                  repository.Transactor
+	events              events.Publisher
+	schedule            ScheduleSettings
+	calendar            *CalendarService
+	quality             FaceQualityThresholds
+	consents            *ConsentService
+	ktp                 *KTPCheckService
 }
 
 // RegisterInput contains the payload required to register a participant.
@@ -34,22 +65,64 @@ type RegisterInput struct {
 	Name      string
 	Image     []byte
 	ImageName string
+	// BirthDate is an optional YYYY-MM-DD date compared with the KTP.
+	BirthDate string
+	// PhoneNumber, Email and Address are optional contact details, used for
+	// notifications and one-time codes when no member record is linked.
+	PhoneNumber string
+	Email       string
+	Address     string
+	// KTP is an optional photo of the participant's ID card, read by OCR and
+	// compared with the registration.
+	KTP *KTPPhoto
+	// Actor is who submitted the registration, recorded with the KTP photo.
+	Actor string
+}
+
+// RegisterFromMemberInput registers a participant using an existing member's identity data.
+type RegisterFromMemberInput struct {
+	MemberID  string
+	Image     []byte
+	ImageName string
+	// KTP is an optional photo of the participant's ID card, compared with
+	// the member's NIK, name and birth date.
+	KTP   *KTPPhoto
+	Actor string
 }
 
 // RegisterOutput returns identifiers produced during registration.
 type RegisterOutput struct {
 	ParticipantID string
+	MemberID      *string
 	FRRef         string
 	FRExternalRef string
+	// KTPCheck is the comparison with the KTP photo, when one was submitted.
+	KTPCheck *domain.KTPCheck
 }
 
 // NewParticipantService wires dependencies for participant registration.
-func NewParticipantService(participants repository.ParticipantRepository, frIdentities repository.FRIdentityRepository, certificates repository.LifeCertificateRepository, frClient frcore.Client) *ParticipantService {
+func NewParticipantService(participants repository.ParticipantRepository, frIdentities repository.FRIdentityRepository, certificates repository.LifeCertificateRepository, states repository.VerificationStateRepository, locks repository.VerificationLockRepository, conflicts repository.FacialConflictRepository, verificationDevices repository.VerificationDeviceRepository, homeVisits repository.HomeVisitRepository, members repository.MemberRepository, campaigns repository.CampaignRepository, profiles repository.VerificationProfileRepository, frClient frcore.Client, images *imaging.Processor, tx repository.Transactor, publisher events.Publisher, schedule ScheduleSettings, calendar *CalendarService, quality FaceQualityThresholds, consents *ConsentService, ktp *KTPCheckService) *ParticipantService {
 	return &ParticipantService{
-		participants: participants,
-		frIdentities: frIdentities,
-		frClient:     frClient,
-		certificates: certificates,
+		participants:        participants,
+		frIdentities:        frIdentities,
+		frClient:            frClient,
+		images:              images,
+		certificates:        certificates,
+		states:              states,
+		locks:               locks,
+		conflicts:           conflicts,
+		verificationDevices: verificationDevices,
+		homeVisits:          homeVisits,
+		members:             members,
+		campaigns:           campaigns,
+		profiles:            profiles,
+		tx:                  tx,
+		events:              publisher,
+		schedule:            schedule,
+		calendar:            calendar,
+		quality:             quality,
+		consents:            consents,
+		ktp:                 ktp,
 	}
 }
 
@@ -64,69 +137,224 @@ func (s *ParticipantService) Register(ctx context.Context, input RegisterInput)
 	if len(input.Image) == 0 {
 		return nil, fmt.Errorf("image is required")
 	}
-
-	existing, err := s.participants.GetByNIK(ctx, input.NIK)
+	birthDate, err := parseDateParam("birth_date", input.BirthDate)
 	if err != nil {
 		return nil, err
 	}
-	if existing != nil {
-		return nil, ErrParticipantExists
+	contact := participantContact{
+		phoneNumber: strings.TrimSpace(input.PhoneNumber),
+		email:       strings.TrimSpace(input.Email),
+		address:     strings.TrimSpace(input.Address),
+	}
+	verr := &ValidationError{}
+	contact.validate(verr)
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
 	}
 
-	participantID := uuid.NewString()
-	imageName := input.ImageName
-	if strings.TrimSpace(imageName) == "" {
-		imageName = "registration.jpg"
+	claim := ktpClaim{nik: strings.TrimSpace(input.NIK), name: strings.TrimSpace(input.Name), birthDate: birthDate}
+	return s.register(ctx, input.NIK, input.Name, nil, contact, input.Image, input.ImageName, input.Actor, claim, input.KTP)
+}
+
+// RegisterFromMember registers a participant from a member record, copying the
+// member's NIK and name and linking both records.
+func (s *ParticipantService) RegisterFromMember(ctx context.Context, input RegisterFromMemberInput) (*RegisterOutput, error) {
+	memberID := strings.TrimSpace(input.MemberID)
+	if memberID == "" {
+		return nil, fmt.Errorf("member_id is required")
+	}
+	if len(input.Image) == 0 {
+		return nil, fmt.Errorf("image is required")
 	}
 
-	frLabel := uuid.NewString()
-	frExternalRef := participantID
-	uploadResp, err := s.frClient.UploadFace(ctx, frcore.UploadRequest{
-		Label:       frLabel,
-		ExternalRef: frExternalRef,
-		ImageName:   imageName,
-		Image:       input.Image,
-	})
+	member, err := s.members.GetByID(ctx, memberID)
 	if err != nil {
 		return nil, err
 	}
+	if member == nil {
+		return nil, ErrMemberNotFound
+	}
+	if member.Status == domain.MemberStatusDeceased {
+		return nil, ErrMemberDeceased
+	}
 
-	frRef := uploadResp.Label
-	if strings.TrimSpace(frRef) == "" {
-		frRef = uploadResp.ID
+	linked, err := s.participants.GetByMemberID(ctx, member.ID)
+	if err != nil {
+		return nil, err
 	}
-	if strings.TrimSpace(frRef) == "" {
-		frRef = frLabel
+	if linked != nil {
+		return nil, ErrMemberAlreadyRegistered
 	}
-	frExternal := uploadResp.ExternalRef
-	if strings.TrimSpace(frExternal) == "" {
-		frExternal = frExternalRef
+
+	claim := ktpClaim{nik: member.NIK, name: member.FullName, birthDate: &member.BirthDate}
+	return s.register(ctx, member.NIK, member.FullName, &member.ID, participantContact{}, input.Image, input.ImageName, input.Actor, claim, input.KTP)
+}
+
+// register enrolls the participant's face and creates the participant. A KTP
+// photo is checked before anything is stored, and filed with the participant.
+func (s *ParticipantService) register(ctx context.Context, nik, name string, memberID *string, contact participantContact, image []byte, imageName, actor string, claim ktpClaim, ktp *KTPPhoto) (*RegisterOutput, error) {
+	ctx, span := tracing.Start(ctx, "ParticipantService.Register")
+	defer span.End()
+
+	existing, err := s.participants.GetByNIK(ctx, nik)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrParticipantExists
+	}
+	if err := s.consents.Require(ctx, nik); err != nil {
+		return nil, err
+	}
+	if actor == "" {
+		actor = systemActor
+	}
+	var capture *ktpCapture
+	if ktp != nil {
+		if capture, err = s.ktp.prepare(ctx, actor, claim, *ktp, image); err != nil {
+			return nil, err
+		}
+	}
+
+	participantID := uuid.NewString()
+	frRef, frExternal, err := s.uploadFace(ctx, participantID, imageName, "registration.jpg", image)
+	if err != nil {
+		return nil, err
+	}
+	if capture != nil {
+		if err := s.ktp.store(ctx, capture, participantID); err != nil {
+			return nil, err
+		}
 	}
 
 	now := time.Now().UTC()
 	participant := &domain.Participant{
 		ID:            participantID,
-		NIK:           strings.TrimSpace(input.NIK),
-		Name:          strings.TrimSpace(input.Name),
-		FRLabel:       frRef,
+		NIK:           strings.TrimSpace(nik),
+		MemberID:      memberID,
+		Name:          strings.TrimSpace(name),
+		PhoneNumber:   contact.phoneNumber,
+		Email:         contact.email,
+		Address:       contact.address,
 		FRExternalRef: frExternal,
+		Status:        domain.ParticipantStatusActive,
 		CreatedAt:     now,
 		UpdatedAt:     now,
 	}
 
-	if err := s.participants.Create(ctx, participant); err != nil {
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.participants.Create(ctx, participant); err != nil {
+			return err
+		}
+		if err := s.frIdentities.Create(ctx, &domain.FRIdentity{
+			Label:         frRef,
+			ParticipantID: participant.ID,
+			ExternalRef:   frExternal,
+			Source:        domain.FRIdentitySourceRegistration,
+		}); err != nil {
+			return err
+		}
+		if capture != nil {
+			if err := s.ktp.record(ctx, capture); err != nil {
+				return err
+			}
+		}
+		return publishEvent(ctx, s.events, events.TypeParticipantRegistered, map[string]interface{}{
+			"participant_id": participant.ID,
+			"member_id":      participant.MemberID,
+			"registered_at":  participant.CreatedAt,
+		})
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	if err := s.frIdentities.Create(ctx, &domain.FRIdentity{
+	output := &RegisterOutput{ParticipantID: participant.ID, MemberID: participant.MemberID, FRRef: frRef, FRExternalRef: participant.FRExternalRef}
+	if capture != nil {
+		output.KTPCheck = capture.check
+	}
+	return output, nil
+}
+
+// EnrollFaceInput carries an additional face image for an existing participant.
+type EnrollFaceInput struct {
+	ParticipantID string
+	Image         []byte
+	ImageName     string
+}
+
+// EnrollFace uploads another face for the participant to FR Core and records the
+// resulting label so verifications may match against it.
+func (s *ParticipantService) EnrollFace(ctx context.Context, input EnrollFaceInput) (*domain.FRIdentity, error) {
+	if len(input.Image) == 0 {
+		return nil, fmt.Errorf("image is required")
+	}
+
+	participant, err := s.participants.GetByID(ctx, strings.TrimSpace(input.ParticipantID))
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return nil, ErrParticipantNotFound
+	}
+
+	frRef, frExternal, err := s.uploadFace(ctx, participant.FRExternalRef, input.ImageName, "enrollment.jpg", input.Image)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &domain.FRIdentity{
 		Label:         frRef,
 		ParticipantID: participant.ID,
 		ExternalRef:   frExternal,
-	}); err != nil {
+		Source:        domain.FRIdentitySourceEnrollment,
+	}
+	if err := s.frIdentities.Create(ctx, identity); err != nil {
 		return nil, err
 	}
 
-	return &RegisterOutput{ParticipantID: participant.ID, FRRef: participant.FRLabel, FRExternalRef: participant.FRExternalRef}, nil
+	return identity, nil
+}
+
+// uploadFace normalises an image, registers it with FR Core under a fresh label
+// and returns the label and external reference FR Core acknowledged.
+func (s *ParticipantService) uploadFace(ctx context.Context, externalRef, imageName, defaultImageName string, image []byte) (string, string, error) {
+	image, err := s.images.Process(ctx, image)
+	if err != nil {
+		return "", "", err
+	}
+	imageName = imaging.JPEGName(imageName)
+	if imageName == "" {
+		imageName = defaultImageName
+	}
+	if err := s.checkFaceQuality(ctx, imageName, image); err != nil {
+		return "", "", err
+	}
+
+	frLabel := uuid.NewString()
+	uploadResp, err := s.frClient.UploadFace(ctx, frcore.UploadRequest{
+		Label:       frLabel,
+		ExternalRef: externalRef,
+		ImageName:   imageName,
+		Image:       image,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	frRef := uploadResp.Label
+	if strings.TrimSpace(frRef) == "" {
+		frRef = uploadResp.ID
+	}
+	if strings.TrimSpace(frRef) == "" {
+		frRef = frLabel
+	}
+	frExternal := uploadResp.ExternalRef
+	if strings.TrimSpace(frExternal) == "" {
+		frExternal = externalRef
+	}
+
+	return frRef, frExternal, nil
 }
 
 // List returns all participants ordered by creation date desc.
@@ -134,8 +362,73 @@ func (s *ParticipantService) List(ctx context.Context) ([]domain.Participant, er
 	return s.participants.List(ctx)
 }
 
-// Get returns a participant by ID.
-func (s *ParticipantService) Get(ctx context.Context, id string) (*domain.Participant, error) {
+// ParticipantDetail extends a participant with its enrolled faces and verification summary.
+type ParticipantDetail struct {
+	domain.Participant
+	Faces   []domain.FRIdentity `json:"faces"`
+	Summary VerificationSummary `json:"verification_summary"`
+}
+
+// VerificationSummary condenses a participant's verification history.
+type VerificationSummary struct {
+	LatestStatus   *domain.LifeCertificateStatus `json:"latest_status"`
+	LastVerifiedAt *time.Time                    `json:"last_verified_at"`
+	TotalAttempts  int64                         `json:"total_attempts"`
+	// ValidUntil is when the latest VALID verification lapses under the default validity period.
+	ValidUntil    *time.Time `json:"valid_until"`
+	NextDueAt     time.Time  `json:"next_due_at"`
+	EnrolledFaces int        `json:"enrolled_faces"`
+}
+
+// SearchParticipantsInput carries participant search criteria and paging.
+type SearchParticipantsInput struct {
+	NIK      string
+	Name     string
+	FRLabel  string
+	Status   string
+	Page     int
+	PageSize int
+}
+
+// SearchParticipantsOutput is a page of matching participants.
+type SearchParticipantsOutput struct {
+	Participants []domain.Participant `json:"participants"`
+	Page         int                  `json:"page"`
+	PageSize     int                  `json:"page_size"`
+	Total        int64                `json:"total"`
+}
+
+// Search finds participants by exact NIK, partial name, FR label and latest verification status.
+func (s *ParticipantService) Search(ctx context.Context, input SearchParticipantsInput) (*SearchParticipantsOutput, error) {
+	filter := repository.ParticipantFilter{
+		NIK:     strings.TrimSpace(input.NIK),
+		Name:    strings.TrimSpace(input.Name),
+		FRLabel: strings.TrimSpace(input.FRLabel),
+	}
+	if raw := strings.TrimSpace(input.Status); raw != "" {
+		status, err := parseLifeCertificateStatus(raw)
+		if err != nil {
+			return nil, err
+		}
+		filter.Status = status
+	}
+
+	page := normalizePagination(input.Page, input.PageSize)
+	participants, total, err := s.participants.Search(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SearchParticipantsOutput{
+		Participants: participants,
+		Page:         page.Page,
+		PageSize:     page.PageSize,
+		Total:        total,
+	}, nil
+}
+
+// Get returns a participant by ID together with its enrolled faces and verification summary.
+func (s *ParticipantService) Get(ctx context.Context, id string) (*ParticipantDetail, error) {
 	participant, err := s.participants.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -143,16 +436,84 @@ func (s *ParticipantService) Get(ctx context.Context, id string) (*domain.Partic
 	if participant == nil {
 		return nil, ErrParticipantNotFound
 	}
-	return participant, nil
+
+	faces, err := s.frIdentities.ListByParticipantID(ctx, participant.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.verificationSummary(ctx, participant)
+	if err != nil {
+		return nil, err
+	}
+	summary.EnrolledFaces = len(faces)
+
+	return &ParticipantDetail{Participant: *participant, Faces: faces, Summary: *summary}, nil
+}
+
+// BackfillVerificationStates builds the latest-status rows of participants
+// verified before they were maintained.
+func (s *ParticipantService) BackfillVerificationStates(ctx context.Context) (int64, error) {
+	return s.states.Backfill(ctx, s.schedule.ValidityMonths)
+}
+
+// RecomputeVerificationStates rebuilds the latest-status row of a participant,
+// or of every participant when participantID is empty, from their attempts,
+// e.g. after VERIFICATION_VALIDITY_MONTHS changed.
+func (s *ParticipantService) RecomputeVerificationStates(ctx context.Context, participantID string) (int64, error) {
+	participantID = strings.TrimSpace(participantID)
+	if participantID != "" {
+		participant, err := s.participants.GetByID(ctx, participantID)
+		if err != nil {
+			return 0, err
+		}
+		if participant == nil {
+			return 0, ErrParticipantNotFound
+		}
+	}
+	return s.states.Rebuild(ctx, participantID, s.schedule.ValidityMonths)
 }
 
-// UpdateParticipantInput captures mutable participant fields.
+func (s *ParticipantService) verificationSummary(ctx context.Context, participant *domain.Participant) (*VerificationSummary, error) {
+	summary := &VerificationSummary{}
+
+	state, err := s.states.GetByParticipant(ctx, participant.ID)
+	if err != nil {
+		return nil, err
+	}
+	var lastValidAt *time.Time
+	if state != nil {
+		status := state.Status
+		verifiedAt := state.VerifiedAt
+		summary.LatestStatus = &status
+		summary.LastVerifiedAt = &verifiedAt
+		summary.TotalAttempts = state.Attempts
+		summary.ValidUntil = state.ValidUntil
+		lastValidAt = state.LastValidAt
+	}
+
+	schedule, err := s.verificationSchedule(ctx, participant, lastValidAt, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	summary.NextDueAt = schedule.DueAt
+
+	return summary, nil
+}
+
+// UpdateParticipantInput captures mutable participant fields. Nil fields are left unchanged.
 type UpdateParticipantInput struct {
-	NIK  string `json:"nik"`
-	Name string `json:"name"`
+	NIK  *string `json:"nik"`
+	Name *string `json:"name"`
+	// Fund selects the fund's verification profile; an empty value clears it.
+	Fund *string `json:"fund"`
+	// Contact details; an empty value clears them.
+	PhoneNumber *string `json:"phone_number"`
+	Email       *string `json:"email"`
+	Address     *string `json:"address"`
 }
 
-// Update modifies participant metadata.
+// Update modifies participant metadata, applying only the fields provided.
 func (s *ParticipantService) Update(ctx context.Context, id string, input UpdateParticipantInput) (*domain.Participant, error) {
 	participant, err := s.participants.GetByID(ctx, id)
 	if err != nil {
@@ -162,14 +523,48 @@ func (s *ParticipantService) Update(ctx context.Context, id string, input Update
 		return nil, ErrParticipantNotFound
 	}
 
-	newNIK := strings.TrimSpace(input.NIK)
-	newName := strings.TrimSpace(input.Name)
+	newNIK := participant.NIK
+	newName := participant.Name
+	newFund := participant.Fund
+	contact := participantContact{phoneNumber: participant.PhoneNumber, email: participant.Email, address: participant.Address}
+	verr := &ValidationError{}
 
-	if newNIK == "" {
-		newNIK = participant.NIK
+	if input.NIK != nil {
+		newNIK = strings.TrimSpace(*input.NIK)
+		switch {
+		case newNIK == "":
+			verr.add("nik", "cannot be empty")
+		case len(newNIK) > 20:
+			verr.add("nik", "must be at most 20 characters")
+		}
 	}
-	if newName == "" {
-		newName = participant.Name
+	if input.Name != nil {
+		newName = strings.TrimSpace(*input.Name)
+		switch {
+		case newName == "":
+			verr.add("name", "cannot be empty")
+		case len(newName) > 100:
+			verr.add("name", "must be at most 100 characters")
+		}
+	}
+	if input.Fund != nil {
+		newFund = optionalString(input.Fund)
+		if newFund != nil && len(*newFund) > fundMaxLen {
+			verr.add("fund", "must be at most 64 characters")
+		}
+	}
+	if input.PhoneNumber != nil {
+		contact.phoneNumber = strings.TrimSpace(*input.PhoneNumber)
+	}
+	if input.Email != nil {
+		contact.email = strings.TrimSpace(*input.Email)
+	}
+	if input.Address != nil {
+		contact.address = strings.TrimSpace(*input.Address)
+	}
+	contact.validate(verr)
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
 	}
 
 	if newNIK != participant.NIK {
@@ -184,6 +579,10 @@ func (s *ParticipantService) Update(ctx context.Context, id string, input Update
 
 	participant.NIK = newNIK
 	participant.Name = newName
+	participant.Fund = newFund
+	participant.PhoneNumber = contact.phoneNumber
+	participant.Email = contact.email
+	participant.Address = contact.address
 	participant.UpdatedAt = time.Now().UTC()
 
 	if err := s.participants.Update(ctx, participant); err != nil {
@@ -193,6 +592,86 @@ func (s *ParticipantService) Update(ctx context.Context, id string, input Update
 	return participant, nil
 }
 
+// participantPhonePattern accepts 8 to 15 digits with an optional leading +,
+// once spaces and dashes are removed.
+var participantPhonePattern = regexp.MustCompile(`^\+?[0-9]{8,15}$`)
+
+// participantContact is a participant's own contact details, trimmed.
+type participantContact struct {
+	phoneNumber string
+	email       string
+	address     string
+}
+
+// validate records the contact details that could not reach the participant.
+// Each of them is optional.
+func (c participantContact) validate(verr *ValidationError) {
+	if c.phoneNumber != "" {
+		digits := strings.NewReplacer(" ", "", "-", "").Replace(c.phoneNumber)
+		if len(c.phoneNumber) > 30 || !participantPhonePattern.MatchString(digits) {
+			verr.add("phone_number", "must be a phone number of 8 to 15 digits, optionally starting with +")
+		}
+	}
+	if c.email != "" {
+		address, err := mail.ParseAddress(c.email)
+		if err != nil || address.Address != c.email || len(c.email) > 120 {
+			verr.add("email", "must be a plain email address of at most 120 characters")
+		}
+	}
+	if len(c.address) > 255 {
+		verr.add("address", "must be at most 255 characters")
+	}
+}
+
+// ChangeStatusInput carries the reason for a participant status change.
+type ChangeStatusInput struct {
+	Reason string `json:"reason"`
+}
+
+// Suspend temporarily prevents the participant from submitting verifications.
+func (s *ParticipantService) Suspend(ctx context.Context, id string, input ChangeStatusInput) (*domain.Participant, error) {
+	return s.changeStatus(ctx, id, domain.ParticipantStatusSuspended, input.Reason, true)
+}
+
+// Block prevents the participant from submitting verifications until explicitly reactivated.
+func (s *ParticipantService) Block(ctx context.Context, id string, input ChangeStatusInput) (*domain.Participant, error) {
+	return s.changeStatus(ctx, id, domain.ParticipantStatusBlocked, input.Reason, true)
+}
+
+// Unsuspend reactivates a suspended or blocked participant.
+func (s *ParticipantService) Unsuspend(ctx context.Context, id string, input ChangeStatusInput) (*domain.Participant, error) {
+	return s.changeStatus(ctx, id, domain.ParticipantStatusActive, input.Reason, false)
+}
+
+func (s *ParticipantService) changeStatus(ctx context.Context, id string, status domain.ParticipantStatus, reason string, reasonRequired bool) (*domain.Participant, error) {
+	reason = strings.TrimSpace(reason)
+	if reasonRequired && reason == "" {
+		return nil, ErrStatusReasonRequired
+	}
+
+	participant, err := s.participants.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return nil, ErrParticipantNotFound
+	}
+
+	now := time.Now().UTC()
+	participant.Status = status
+	participant.StatusReason = nil
+	if reason != "" {
+		participant.StatusReason = &reason
+	}
+	participant.StatusChangedAt = &now
+	participant.UpdatedAt = now
+
+	if err := s.participants.UpdateStatus(ctx, participant); err != nil {
+		return nil, err
+	}
+	return participant, nil
+}
+
 // Delete removes a participant and related records.
 func (s *ParticipantService) Delete(ctx context.Context, id string) error {
 	participant, err := s.participants.GetByID(ctx, id)
@@ -209,6 +688,24 @@ func (s *ParticipantService) Delete(ctx context.Context, id string) error {
 	if err := s.frIdentities.DeleteByParticipantID(ctx, id); err != nil {
 		return err
 	}
+	if err := s.campaigns.DeleteByParticipant(ctx, id); err != nil {
+		return err
+	}
+	if err := s.locks.DeleteByParticipant(ctx, id); err != nil {
+		return err
+	}
+	if err := s.conflicts.DeleteByParticipant(ctx, id); err != nil {
+		return err
+	}
+	if err := s.verificationDevices.DeleteByParticipant(ctx, id); err != nil {
+		return err
+	}
+	if err := s.homeVisits.DeleteByParticipant(ctx, id); err != nil {
+		return err
+	}
+	if err := s.ktp.DeleteByParticipant(ctx, id); err != nil {
+		return err
+	}
 
 	return s.participants.Delete(ctx, id)
 }
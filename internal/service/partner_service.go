@@ -0,0 +1,362 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/tenant"
+)
+
+const (
+	// partnerKeyPrefix starts every partner API key so leaked keys are recognisable.
+	partnerKeyPrefix = "lcp_"
+	// partnerKeyTouchInterval bounds how often a key's last use is written.
+	partnerKeyTouchInterval = time.Minute
+
+	partnerFeedDefaultLimit = 100
+	partnerFeedMaxLimit     = 1000
+)
+
+// Audit vocabulary for partner key management.
+const (
+	auditEntityPartnerKey       = "partner_api_key"
+	auditActionPartnerKeyCreate = "partner_key.create"
+	auditActionPartnerKeyRevoke = "partner_key.revoke"
+)
+
+var (
+	// ErrPartnerKeyNotFound indicates the requested partner key does not exist.
+	ErrPartnerKeyNotFound = errors.New("partner key not found")
+	// ErrPartnerKeyRevoked indicates the partner key was already revoked.
+	ErrPartnerKeyRevoked = errors.New("partner key already revoked")
+	// ErrPartnerStatusNotFound indicates no participant visible to the partner has the NIK.
+	ErrPartnerStatusNotFound = errors.New("participant not found")
+)
+
+var partnerScopes = []string{domain.PartnerScopeStatus, domain.PartnerScopeChanges}
+
+// PartnerService manages partner API keys and answers the partner API: status
+// lookups by NIK and a feed of status changes. Partners see statuses only,
+// never names, contact details or biometric data.
+type PartnerService struct {
+	partners repository.PartnerRepository
+	audit    repository.AuditLogRepository
+}
+
+// NewPartnerService wires dependencies for the partner API.
+func NewPartnerService(partners repository.PartnerRepository, audit repository.AuditLogRepository) *PartnerService {
+	return &PartnerService{partners: partners, audit: audit}
+}
+
+// CreatePartnerKeyInput issues a key to an institution.
+type CreatePartnerKeyInput struct {
+	Name string `json:"name"`
+	// Scopes lists status:read and/or changes:read.
+	Scopes []string `json:"scopes"`
+	// Fund restricts the key to one fund's participants when set.
+	Fund string `json:"fund"`
+}
+
+// CreatedPartnerKey returns the API key once, at creation time.
+type CreatedPartnerKey struct {
+	domain.PartnerAPIKey
+	Key string `json:"key"`
+}
+
+// PartnerStatus is what a partner sees of a participant.
+type PartnerStatus struct {
+	NIK               string  `json:"nik"`
+	ParticipantID     string  `json:"participant_id"`
+	MemberID          *string `json:"member_id"`
+	Fund              *string `json:"fund"`
+	ParticipantStatus string  `json:"participant_status"`
+	// CertificateStatus is VALID, EXPIRED, NONE or the latest attempt's outcome.
+	CertificateStatus string     `json:"certificate_status"`
+	VerifiedAt        *time.Time `json:"verified_at"`
+	LastValidAt       *time.Time `json:"last_valid_at"`
+	ValidUntil        *time.Time `json:"valid_until"`
+	ChangedAt         time.Time  `json:"changed_at"`
+}
+
+// PartnerChangesInput selects a page of the change feed. Cursor, when set,
+// continues a previous page and takes precedence over Since.
+type PartnerChangesInput struct {
+	Since  string
+	Cursor string
+	Limit  int
+}
+
+// PartnerChangesOutput is a page of the change feed. NextCursor continues
+// after the last item, or repeats the request's position when the page is empty.
+type PartnerChangesOutput struct {
+	Items      []PartnerStatus `json:"items"`
+	NextCursor string          `json:"next_cursor"`
+	HasMore    bool            `json:"has_more"`
+}
+
+// CreateKey issues a new partner API key.
+func (s *PartnerService) CreateKey(ctx context.Context, actor string, input CreatePartnerKeyInput) (*CreatedPartnerKey, error) {
+	verr := &ValidationError{}
+	name := strings.TrimSpace(input.Name)
+	switch {
+	case name == "":
+		verr.add("name", "is required")
+	case len(name) > 100:
+		verr.add("name", "must be at most 100 characters")
+	}
+	scopes, problem := normalizePartnerScopes(input.Scopes)
+	if problem != "" {
+		verr.add("scopes", problem)
+	}
+	fund := strings.TrimSpace(input.Fund)
+	if len(fund) > 64 {
+		verr.add("fund", "must be at most 64 characters")
+	}
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("generate partner key: %w", err)
+	}
+	secret := partnerKeyPrefix + base64.RawURLEncoding.EncodeToString(buf)
+
+	key := &domain.PartnerAPIKey{
+		ID:        uuid.NewString(),
+		Name:      name,
+		KeyHash:   hashPartnerKey(secret),
+		KeyPrefix: secret[:len(partnerKeyPrefix)+8],
+		Scopes:    strings.Join(scopes, ","),
+		CreatedBy: actor,
+		CreatedAt: time.Now().UTC(),
+	}
+	if fund != "" {
+		key.Fund = &fund
+	}
+	if err := s.partners.CreateKey(ctx, key); err != nil {
+		return nil, err
+	}
+
+	if err := recordAudit(ctx, s.audit, actor, auditActionPartnerKeyCreate, auditEntityPartnerKey, key.ID, map[string]interface{}{
+		"name":   key.Name,
+		"scopes": key.Scopes,
+		"fund":   key.Fund,
+	}); err != nil {
+		return nil, err
+	}
+	return &CreatedPartnerKey{PartnerAPIKey: *key, Key: secret}, nil
+}
+
+// ListKeys returns every partner key, revoked ones included.
+func (s *PartnerService) ListKeys(ctx context.Context) ([]domain.PartnerAPIKey, error) {
+	return s.partners.ListKeys(ctx)
+}
+
+// RevokeKey stops a partner key from authenticating.
+func (s *PartnerService) RevokeKey(ctx context.Context, actor, id string) (*domain.PartnerAPIKey, error) {
+	key, err := s.partners.GetKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, ErrPartnerKeyNotFound
+	}
+
+	now := time.Now().UTC()
+	revoked, err := s.partners.RevokeKey(ctx, id, now)
+	if err != nil {
+		return nil, err
+	}
+	if !revoked {
+		return nil, ErrPartnerKeyRevoked
+	}
+	key.RevokedAt = &now
+
+	if err := recordAudit(ctx, s.audit, actor, auditActionPartnerKeyRevoke, auditEntityPartnerKey, key.ID, map[string]interface{}{
+		"name": key.Name,
+	}); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Authenticate returns the active key matching the presented API key, or nil.
+func (s *PartnerService) Authenticate(ctx context.Context, secret string) (*domain.PartnerAPIKey, error) {
+	if !strings.HasPrefix(secret, partnerKeyPrefix) {
+		return nil, nil
+	}
+	// The key names its tenant, so it is looked up across all of them.
+	ctx = tenant.System(ctx)
+	key, err := s.partners.GetKeyByHash(ctx, hashPartnerKey(secret))
+	if err != nil || key == nil || key.RevokedAt != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if err := s.partners.TouchKey(ctx, key.ID, now, now.Add(-partnerKeyTouchInterval)); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// Status looks up a participant by NIK. Participants outside the partner's
+// fund are reported as not found.
+func (s *PartnerService) Status(ctx context.Context, fund, nik string) (*PartnerStatus, error) {
+	nik = strings.TrimSpace(nik)
+	if nik == "" {
+		verr := &ValidationError{}
+		verr.add("nik", "is required")
+		return nil, verr
+	}
+	row, err := s.partners.GetStatusByNIK(ctx, nik)
+	if err != nil {
+		return nil, err
+	}
+	if row == nil || (fund != "" && (row.Fund == nil || *row.Fund != fund)) {
+		return nil, ErrPartnerStatusNotFound
+	}
+	status := partnerStatus(row, time.Now().UTC())
+	return &status, nil
+}
+
+// Changes returns participants whose status changed after the since timestamp
+// or the cursor, oldest change first.
+func (s *PartnerService) Changes(ctx context.Context, fund string, input PartnerChangesInput) (*PartnerChangesOutput, error) {
+	filter := repository.PartnerChangesFilter{Fund: fund, Limit: input.Limit}
+	if filter.Limit < 1 {
+		filter.Limit = partnerFeedDefaultLimit
+	}
+	if filter.Limit > partnerFeedMaxLimit {
+		filter.Limit = partnerFeedMaxLimit
+	}
+
+	cursor := strings.TrimSpace(input.Cursor)
+	since := strings.TrimSpace(input.Since)
+	switch {
+	case cursor != "":
+		var err error
+		if filter.After, filter.AfterID, err = decodePartnerCursor(cursor); err != nil {
+			return nil, err
+		}
+	case since != "":
+		parsed, err := time.Parse(time.RFC3339Nano, since)
+		if err != nil {
+			return nil, fmt.Errorf("invalid since timestamp, use RFC 3339")
+		}
+		filter.After = parsed.UTC()
+	default:
+		return nil, fmt.Errorf("since or cursor is required")
+	}
+
+	// One extra row tells whether another page follows.
+	limit := filter.Limit
+	filter.Limit++
+	rows, err := s.partners.ListChanges(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &PartnerChangesOutput{Items: make([]PartnerStatus, 0, limit)}
+	if len(rows) > limit {
+		out.HasMore = true
+		rows = rows[:limit]
+	}
+	now := time.Now().UTC()
+	for i := range rows {
+		out.Items = append(out.Items, partnerStatus(&rows[i], now))
+	}
+	if len(rows) == 0 {
+		out.NextCursor = encodePartnerCursor(filter.After, filter.AfterID)
+	} else {
+		last := rows[len(rows)-1]
+		out.NextCursor = encodePartnerCursor(last.ChangedAt, last.ParticipantID)
+	}
+	return out, nil
+}
+
+// PartnerScopes splits a key's stored scopes.
+func PartnerScopes(key *domain.PartnerAPIKey) []string {
+	if key.Scopes == "" {
+		return nil
+	}
+	return strings.Split(key.Scopes, ",")
+}
+
+func partnerStatus(row *repository.ParticipantStatusRow, now time.Time) PartnerStatus {
+	return PartnerStatus{
+		NIK:               row.NIK,
+		ParticipantID:     row.ParticipantID,
+		MemberID:          row.MemberID,
+		Fund:              row.Fund,
+		ParticipantStatus: string(row.ParticipantStatus),
+		CertificateStatus: currentCertificateStatus(row, now),
+		VerifiedAt:        row.VerifiedAt,
+		LastValidAt:       row.LastValidAt,
+		ValidUntil:        row.ValidUntil,
+		ChangedAt:         row.ChangedAt.UTC(),
+	}
+}
+
+func normalizePartnerScopes(scopes []string) ([]string, string) {
+	if len(scopes) == 0 {
+		return nil, "at least one scope is required"
+	}
+	seen := make(map[string]bool, len(scopes))
+	var out []string
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		known := false
+		for _, allowed := range partnerScopes {
+			if scope == allowed {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Sprintf("unknown scope %q, use %s", scope, strings.Join(partnerScopes, " or "))
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			out = append(out, scope)
+		}
+	}
+	return out, ""
+}
+
+func hashPartnerKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// The cursor is opaque to partners: the last change time and participant ID.
+func encodePartnerCursor(at time.Time, participantID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(at.UTC().Format(time.RFC3339Nano) + "|" + participantID))
+}
+
+func decodePartnerCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("invalid cursor")
+	}
+	at, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return time.Time{}, "", fmt.Errorf("invalid cursor")
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("invalid cursor")
+	}
+	return parsed, id, nil
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/events"
+	"life-certificates/internal/payroll"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/tenant"
+)
+
+const (
+	paymentPushPollInterval   = 5 * time.Second
+	paymentPushBatchSize      = 50
+	paymentPushLease          = 2 * time.Minute
+	paymentPushBaseBackoff    = time.Minute
+	paymentPushMaxBackoff     = 6 * time.Hour
+	auditEntityPaymentPush    = "payment_push"
+	auditActionPaymentRequeue = "payment_push.retry"
+)
+
+var (
+	// ErrPaymentPushNotFound indicates the requested payment push does not exist.
+	ErrPaymentPushNotFound = errors.New("payment push not found")
+	// ErrPaymentPushNotFailed signals only FAILED pushes can be retried.
+	ErrPaymentPushNotFailed = errors.New("payment push is not failed")
+)
+
+// PaymentPushService reports VALID and EXPIRED certificate transitions to the
+// pension payment system, retrying until the payment system acknowledges them.
+type PaymentPushService struct {
+	pushes         repository.PaymentPushRepository
+	participants   repository.ParticipantRepository
+	certificates   repository.LifeCertificateRepository
+	audit          repository.AuditLogRepository
+	client         payroll.Client
+	validityMonths int
+	maxAttempts    int
+}
+
+// NewPaymentPushService wires dependencies for payment system pushes.
+func NewPaymentPushService(pushes repository.PaymentPushRepository, participants repository.ParticipantRepository, certificates repository.LifeCertificateRepository, audit repository.AuditLogRepository, client payroll.Client, validityMonths, maxAttempts int) *PaymentPushService {
+	return &PaymentPushService{
+		pushes:         pushes,
+		participants:   participants,
+		certificates:   certificates,
+		audit:          audit,
+		client:         client,
+		validityMonths: validityMonths,
+		maxAttempts:    maxAttempts,
+	}
+}
+
+// PaymentPushReport summarises pushes the payment system has not acknowledged.
+type PaymentPushReport struct {
+	Pending      int64                                  `json:"pending"`
+	Failed       int64                                  `json:"failed"`
+	ByTransition map[domain.PaymentPushTransition]int64 `json:"by_transition"`
+	OldestQueued *time.Time                             `json:"oldest_queued_at"`
+	Items        []domain.PaymentPush                   `json:"items"`
+	Page         int                                    `json:"page"`
+	PageSize     int                                    `json:"page_size"`
+	Total        int64                                  `json:"total"`
+}
+
+// Publish queues a VALID push when a certificate is verified as VALID.
+// A transition already queued for the certificate is ignored, so redelivery from the outbox is safe.
+func (s *PaymentPushService) Publish(ctx context.Context, event events.Event) error {
+	if event.Type != events.TypeVerificationCompleted || event.Data["status"] != string(domain.LifeCertificateStatusValid) {
+		return nil
+	}
+	certificateID, _ := event.Data["certificate_id"].(string)
+	record, err := s.certificates.GetByID(ctx, certificateID)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return nil
+	}
+	return s.enqueue(ctx, record, domain.PaymentPushValid, record.VerifiedAt)
+}
+
+// Start runs the push dispatcher until ctx is cancelled.
+func (s *PaymentPushService) Start(ctx context.Context) {
+	go func() {
+		poll := time.NewTicker(paymentPushPollInterval)
+		defer poll.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-poll.C:
+				s.dispatchDue(ctx)
+			}
+		}
+	}()
+}
+
+// Unacknowledged reports pending and failed pushes for reconciliation with the payment system.
+func (s *PaymentPushService) Unacknowledged(ctx context.Context, status string, pageNum, pageSize int) (*PaymentPushReport, error) {
+	filter := domain.PaymentPushStatus(strings.ToUpper(strings.TrimSpace(status)))
+	switch filter {
+	case "", domain.PaymentPushPending, domain.PaymentPushFailed:
+	default:
+		return nil, fmt.Errorf("status must be PENDING or FAILED")
+	}
+
+	summary, err := s.pushes.SummarizeUnacknowledged(ctx)
+	if err != nil {
+		return nil, err
+	}
+	page := normalizePagination(pageNum, pageSize)
+	items, total, err := s.pushes.ListUnacknowledged(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PaymentPushReport{
+		Pending: summary.Pending,
+		Failed:  summary.Failed,
+		ByTransition: map[domain.PaymentPushTransition]int64{
+			domain.PaymentPushValid:   summary.Valid,
+			domain.PaymentPushExpired: summary.Expired,
+		},
+		OldestQueued: summary.OldestQueued,
+		Items:        items,
+		Page:         page.Page,
+		PageSize:     page.PageSize,
+		Total:        total,
+	}, nil
+}
+
+// Retry requeues a FAILED push with a fresh attempt budget.
+func (s *PaymentPushService) Retry(ctx context.Context, actor, id string) (*domain.PaymentPush, error) {
+	push, err := s.pushes.GetByID(ctx, strings.TrimSpace(id))
+	if err != nil {
+		return nil, err
+	}
+	if push == nil {
+		return nil, ErrPaymentPushNotFound
+	}
+
+	ok, err := s.pushes.Requeue(ctx, push.ID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrPaymentPushNotFailed
+	}
+
+	if err := recordAudit(ctx, s.audit, actor, auditActionPaymentRequeue, auditEntityPaymentPush, push.ID, map[string]interface{}{
+		"certificate_id": push.CertificateID,
+		"transition":     push.Transition,
+		"attempts":       push.Attempts,
+		"last_error":     push.LastError,
+	}); err != nil {
+		return nil, err
+	}
+	return s.pushes.GetByID(ctx, push.ID)
+}
+
+// SweepExpired queues EXPIRED pushes for participants whose latest VALID certificate lapsed.
+func (s *PaymentPushService) SweepExpired(ctx context.Context) error {
+	cutoff := time.Now().UTC().AddDate(0, -s.validityMonths, 0)
+	for {
+		records, err := s.pushes.ListExpiredWithoutPush(ctx, cutoff, paymentPushBatchSize)
+		if err != nil {
+			return fmt.Errorf("list expired certificates: %w", err)
+		}
+		for i := range records {
+			record := &records[i]
+			if err := s.enqueue(ctx, record, domain.PaymentPushExpired, record.VerifiedAt.AddDate(0, s.validityMonths, 0)); err != nil {
+				return fmt.Errorf("queue expiry of certificate %s: %w", record.ID, err)
+			}
+		}
+		if len(records) < paymentPushBatchSize {
+			return nil
+		}
+	}
+}
+
+func (s *PaymentPushService) enqueue(ctx context.Context, record *domain.LifeCertificate, transition domain.PaymentPushTransition, effectiveAt time.Time) error {
+	participant, err := s.participants.GetByID(ctx, record.ParticipantID)
+	if err != nil {
+		return err
+	}
+	if participant == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"participant_id": participant.ID,
+		"nik":            participant.NIK,
+		"member_id":      participant.MemberID,
+		"certificate_id": record.ID,
+		"status":         transition,
+		"effective_at":   effectiveAt,
+		"valid_until":    record.VerifiedAt.AddDate(0, s.validityMonths, 0),
+	})
+	if err != nil {
+		return fmt.Errorf("encode payment push: %w", err)
+	}
+
+	now := time.Now().UTC()
+	return s.pushes.Create(ctx, &domain.PaymentPush{
+		ID:            uuid.NewString(),
+		ParticipantID: participant.ID,
+		CertificateID: record.ID,
+		Transition:    transition,
+		Payload:       string(payload),
+		Status:        domain.PaymentPushPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	})
+}
+
+func (s *PaymentPushService) dispatchDue(ctx context.Context) {
+	now := time.Now().UTC()
+	due, err := s.pushes.ListDue(tenant.System(ctx), now, paymentPushBatchSize)
+	if err != nil {
+		log.Printf("[payroll] list due pushes: %v", err)
+		return
+	}
+
+	for i := range due {
+		push := &due[i]
+		ctx := tenant.WithID(ctx, push.TenantID)
+		ok, err := s.pushes.Claim(ctx, push.ID, push.NextAttemptAt, now.Add(paymentPushLease))
+		if err != nil {
+			log.Printf("[payroll] claim push %s: %v", push.ID, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		s.attempt(ctx, push)
+	}
+}
+
+func (s *PaymentPushService) attempt(ctx context.Context, push *domain.PaymentPush) {
+	var payload map[string]interface{}
+	err := json.Unmarshal([]byte(push.Payload), &payload)
+	var code int
+	if err == nil {
+		code, err = s.client.Push(ctx, payload)
+	}
+
+	push.Attempts++
+	now := time.Now().UTC()
+	if code != 0 {
+		push.ResponseCode = &code
+	}
+	switch {
+	case err == nil:
+		push.Status = domain.PaymentPushAcknowledged
+		push.AcknowledgedAt = &now
+		push.LastError = nil
+	case push.Attempts >= s.maxAttempts:
+		msg := err.Error()
+		push.Status = domain.PaymentPushFailed
+		push.LastError = &msg
+	default:
+		msg := err.Error()
+		push.LastError = &msg
+		push.NextAttemptAt = now.Add(exponentialBackoff(paymentPushBaseBackoff, paymentPushMaxBackoff, push.Attempts))
+	}
+
+	if err := s.pushes.Update(ctx, push); err != nil {
+		log.Printf("[payroll] update push %s: %v", push.ID, err)
+	}
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/payroll"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/tenant"
+)
+
+// PayrollFileOptions selects the file layout and contents.
+type PayrollFileOptions struct {
+	// Format is payroll.FileFormatCSV or payroll.FileFormatFixed.
+	Format string
+	// Prefix starts every file name: <prefix>_<YYYYMMDD>.<csv|txt>, with the
+	// tenant after the prefix for tenants other than the default one.
+	Prefix string
+	// Fund limits the file to one fund's participants; empty includes everyone.
+	Fund string
+}
+
+// PayrollFileService generates verification status files for legacy payroll
+// systems that only consume flat files, uploads them and logs each delivery.
+type PayrollFileService struct {
+	files    repository.PayrollFileRepository
+	uploader payroll.Uploader
+	options  PayrollFileOptions
+}
+
+// NewPayrollFileService wires dependencies for payroll file exports.
+// uploader may be nil when no SFTP server is configured; Export then fails.
+func NewPayrollFileService(files repository.PayrollFileRepository, uploader payroll.Uploader, options PayrollFileOptions) *PayrollFileService {
+	return &PayrollFileService{files: files, uploader: uploader, options: options}
+}
+
+// PayrollFileListOutput is a page of deliveries.
+type PayrollFileListOutput struct {
+	Items    []domain.PayrollFileDelivery `json:"items"`
+	Page     int                          `json:"page"`
+	PageSize int                          `json:"page_size"`
+	Total    int64                        `json:"total"`
+}
+
+// Export writes every participant's current status to a file, uploads it
+// and logs the delivery, failed or not.
+func (s *PayrollFileService) Export(ctx context.Context) error {
+	if s.uploader == nil {
+		return fmt.Errorf("payroll file SFTP server is not configured")
+	}
+	started := time.Now().UTC()
+	// Tenants other than the default one name themselves, so their files do not overwrite each other.
+	prefix := s.options.Prefix
+	if id := tenant.ID(ctx); id != tenant.DefaultID {
+		prefix += "_" + id
+	}
+	delivery := &domain.PayrollFileDelivery{
+		ID:        uuid.NewString(),
+		FileName:  fmt.Sprintf("%s_%s.%s", prefix, started.Format("20060102"), payroll.FileExtension(s.options.Format)),
+		Format:    s.options.Format,
+		StartedAt: started,
+	}
+	if s.options.Fund != "" {
+		fund := s.options.Fund
+		delivery.Fund = &fund
+	}
+
+	err := s.deliver(ctx, delivery)
+	finished := time.Now().UTC()
+	delivery.FinishedAt = &finished
+	delivery.Status = domain.PayrollFileDelivered
+	if err != nil {
+		msg := err.Error()
+		delivery.Status = domain.PayrollFileFailed
+		delivery.Error = &msg
+	}
+	// The run may have been cancelled; the delivery is still logged.
+	if logErr := s.files.Create(context.WithoutCancel(ctx), delivery); logErr != nil {
+		log.Printf("[payroll-file] log delivery %s: %v", delivery.FileName, logErr)
+	}
+	if err != nil {
+		return err
+	}
+	log.Printf("[payroll-file] delivered %s records=%d sha256=%s", delivery.RemotePath, delivery.Records, delivery.Checksum)
+	return nil
+}
+
+// List returns deliveries, latest first.
+func (s *PayrollFileService) List(ctx context.Context, pageNum, pageSize int) (*PayrollFileListOutput, error) {
+	page := normalizePagination(pageNum, pageSize)
+	items, total, err := s.files.List(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	return &PayrollFileListOutput{Items: items, Page: page.Page, PageSize: page.PageSize, Total: total}, nil
+}
+
+// deliver builds the file in a temporary file, so its checksum is known and
+// the database is not read while the upload is in flight, then uploads it.
+func (s *PayrollFileService) deliver(ctx context.Context, delivery *domain.PayrollFileDelivery) error {
+	tmp, err := os.CreateTemp("", "payroll-file-*")
+	if err != nil {
+		return fmt.Errorf("create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+	counter := &countingWriter{w: io.MultiWriter(tmp, hash)}
+	writer, err := payroll.NewFileWriter(s.options.Format, counter, delivery.StartedAt, s.options.Fund)
+	if err != nil {
+		return err
+	}
+	err = s.files.StreamStatuses(ctx, s.options.Fund, func(row *repository.ParticipantStatusRow) error {
+		delivery.Records++
+		return writer.Write(payrollStatusRecord(row, delivery.StartedAt))
+	})
+	if err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("write payroll file: %w", err)
+	}
+	delivery.SizeBytes = counter.n
+	delivery.Checksum = hex.EncodeToString(hash.Sum(nil))
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewind payroll file: %w", err)
+	}
+	remotePath, err := s.uploader.Upload(ctx, delivery.FileName, tmp)
+	if err != nil {
+		return err
+	}
+	delivery.RemotePath = remotePath
+	return nil
+}
+
+func payrollStatusRecord(row *repository.ParticipantStatusRow, now time.Time) payroll.StatusRecord {
+	record := payroll.StatusRecord{
+		NIK:               row.NIK,
+		ParticipantID:     row.ParticipantID,
+		ParticipantStatus: string(row.ParticipantStatus),
+		CertificateStatus: currentCertificateStatus(row, now),
+		LastValidAt:       row.LastValidAt,
+		ValidUntil:        row.ValidUntil,
+	}
+	if row.MemberID != nil {
+		record.MemberID = *row.MemberID
+	}
+	if row.Fund != nil {
+		record.Fund = *row.Fund
+	}
+	return record
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
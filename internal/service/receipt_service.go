@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/tenant"
+)
+
+// Receipt check outcomes.
+const (
+	// ReceiptStatusValid marks a VALID certificate within its validity window.
+	ReceiptStatusValid = "VALID"
+	// ReceiptStatusExpired marks a certificate whose validity window has passed.
+	ReceiptStatusExpired = "EXPIRED"
+	// ReceiptStatusRevoked marks a certificate that is no longer VALID, e.g.
+	// after a status override, or whose participant was blocked.
+	ReceiptStatusRevoked = "REVOKED"
+)
+
+var (
+	// ErrReceiptNotIssuable indicates a receipt was requested for a certificate that is not VALID.
+	ErrReceiptNotIssuable = errors.New("receipts are only issued for VALID certificates")
+	// ErrReceiptNotFound indicates a receipt token that is forged, malformed or whose certificate is gone.
+	ErrReceiptNotFound = errors.New("receipt not found")
+)
+
+// ReceiptOptions configures receipt tokens.
+type ReceiptOptions struct {
+	// Secret signs receipt tokens.
+	Secret string
+	// PublicURL is the base URL of the public check the QR code points to.
+	PublicURL      string
+	ValidityMonths int
+}
+
+// ReceiptService issues signed receipts for VALID certificates and checks
+// them. A token only names the certificate, so checks always report its
+// current standing and a receipt stops passing once the certificate is
+// overridden.
+type ReceiptService struct {
+	certificates repository.LifeCertificateRepository
+	participants repository.ParticipantRepository
+	options      ReceiptOptions
+}
+
+// NewReceiptService wires dependencies for certificate receipts.
+func NewReceiptService(certificates repository.LifeCertificateRepository, participants repository.ParticipantRepository, options ReceiptOptions) *ReceiptService {
+	options.PublicURL = strings.TrimRight(options.PublicURL, "/")
+	return &ReceiptService{certificates: certificates, participants: participants, options: options}
+}
+
+// Receipt is a signed receipt for a VALID certificate.
+type Receipt struct {
+	CertificateID string    `json:"certificate_id"`
+	Token         string    `json:"token"`
+	URL           string    `json:"url"`
+	VerifiedAt    time.Time `json:"verified_at"`
+	ValidUntil    time.Time `json:"valid_until"`
+}
+
+// ReceiptCheck is what the public check reveals: whether the receipt is
+// authentic and valid, never who it was issued to.
+type ReceiptCheck struct {
+	CertificateID string    `json:"certificate_id"`
+	Status        string    `json:"status"`
+	Valid         bool      `json:"valid"`
+	VerifiedAt    time.Time `json:"verified_at"`
+	ValidUntil    time.Time `json:"valid_until"`
+	CheckedAt     time.Time `json:"checked_at"`
+}
+
+// Issue returns the receipt of a VALID certificate.
+func (s *ReceiptService) Issue(ctx context.Context, certificateID string) (*Receipt, error) {
+	record, err := s.certificates.GetByID(ctx, strings.TrimSpace(certificateID))
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, ErrCertificateNotFound
+	}
+	if record.Status != domain.LifeCertificateStatusValid {
+		return nil, ErrReceiptNotIssuable
+	}
+
+	token := s.sign(tenant.ID(ctx), record.ID)
+	return &Receipt{
+		CertificateID: record.ID,
+		Token:         token,
+		URL:           s.options.PublicURL + "/verify-receipt/" + token,
+		VerifiedAt:    record.VerifiedAt,
+		ValidUntil:    s.validUntil(record),
+	}, nil
+}
+
+// Check confirms a receipt token was issued by this service and reports the
+// certificate's standing.
+func (s *ReceiptService) Check(ctx context.Context, token string) (*ReceiptCheck, error) {
+	tenantID, certificateID, ok := s.verify(strings.TrimSpace(token))
+	if !ok {
+		return nil, ErrReceiptNotFound
+	}
+	if tenantID != "" {
+		ctx = tenant.WithID(ctx, tenantID)
+	}
+	record, err := s.certificates.GetByID(ctx, certificateID)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, ErrReceiptNotFound
+	}
+	participant, err := s.participants.GetByID(ctx, record.ParticipantID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	check := &ReceiptCheck{
+		CertificateID: record.ID,
+		VerifiedAt:    record.VerifiedAt,
+		ValidUntil:    s.validUntil(record),
+		CheckedAt:     now,
+	}
+	switch {
+	case record.Status != domain.LifeCertificateStatusValid, participant == nil, participant.Status == domain.ParticipantStatusBlocked:
+		check.Status = ReceiptStatusRevoked
+	case !now.Before(check.ValidUntil):
+		check.Status = ReceiptStatusExpired
+	default:
+		check.Status, check.Valid = ReceiptStatusValid, true
+	}
+	return check, nil
+}
+
+func (s *ReceiptService) validUntil(record *domain.LifeCertificate) time.Time {
+	return record.VerifiedAt.AddDate(0, s.options.ValidityMonths, 0)
+}
+
+// sign binds the certificate to its tenant, so a receipt is checked in the
+// tenant that issued it whatever host it is scanned on.
+func (s *ReceiptService) sign(tenantID, certificateID string) string {
+	payload := tenantID + ":" + certificateID
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + base64.RawURLEncoding.EncodeToString(s.mac(payload))
+}
+
+// verify returns the tenant and certificate of a token; tokens issued before
+// they named a tenant return an empty one.
+func (s *ReceiptService) verify(token string) (tenantID, certificateID string, ok bool) {
+	encodedPayload, encodedMAC, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", "", false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return "", "", false
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(encodedMAC)
+	if err != nil || !hmac.Equal(mac, s.mac(string(payload))) {
+		return "", "", false
+	}
+	if tenantID, certificateID, ok := strings.Cut(string(payload), ":"); ok {
+		return tenantID, certificateID, true
+	}
+	return "", string(payload), true
+}
+
+func (s *ReceiptService) mac(payload string) []byte {
+	mac := hmac.New(sha256.New, []byte(s.options.Secret))
+	mac.Write([]byte("receipt:" + payload))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/frcore"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/tenant"
+)
+
+// orphanGracePeriod skips FR Core enrollments younger than this, since a
+// registration may have uploaded the face but not yet committed its identity row.
+const orphanGracePeriod = time.Hour
+
+// JobTypeFRReconcile runs a reconciliation triggered through the API.
+const JobTypeFRReconcile = "frcore.reconcile"
+
+var (
+	// ErrReconciliationNotFound indicates the requested reconciliation run does not exist.
+	ErrReconciliationNotFound = errors.New("reconciliation run not found")
+)
+
+// ReconciliationService finds FR Core enrollments that no longer map to an
+// fr_identities row and optionally deletes them.
+type ReconciliationService struct {
+	runs         repository.FRReconciliationRepository
+	frIdentities repository.FRIdentityRepository
+	frClient     frcore.Client
+	jobs         *JobService
+	tx           repository.Transactor
+}
+
+// reconcileJob is the payload of a JobTypeFRReconcile job.
+type reconcileJob struct {
+	RunID string `json:"run_id"`
+}
+
+// OrphanFace reports a single orphaned FR Core enrollment.
+type OrphanFace struct {
+	Label       string `json:"label"`
+	ExternalRef string `json:"external_ref"`
+	Deleted     bool   `json:"deleted"`
+	Error       string `json:"error,omitempty"`
+}
+
+// NewReconciliationService wires dependencies for FR Core reconciliation and
+// registers its job handler.
+func NewReconciliationService(runs repository.FRReconciliationRepository, frIdentities repository.FRIdentityRepository, frClient frcore.Client, jobs *JobService, tx repository.Transactor) *ReconciliationService {
+	s := &ReconciliationService{
+		runs:         runs,
+		frIdentities: frIdentities,
+		frClient:     frClient,
+		jobs:         jobs,
+		tx:           tx,
+	}
+	jobs.Register(JobTypeFRReconcile, s.runJob)
+	return s
+}
+
+// Trigger records a new run and queues a job that executes it.
+func (s *ReconciliationService) Trigger(ctx context.Context, actor string, deleteOrphans bool) (*domain.FRReconciliationRun, error) {
+	run := &domain.FRReconciliationRun{
+		ID:            uuid.NewString(),
+		Status:        domain.FRReconciliationRunning,
+		DeleteOrphans: deleteOrphans,
+		StartedAt:     time.Now().UTC(),
+	}
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.runs.Create(ctx, run); err != nil {
+			return err
+		}
+		_, err := s.jobs.Enqueue(ctx, actor, JobTypeFRReconcile, reconcileJob{RunID: run.ID})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+// Run records and executes a reconciliation synchronously.
+func (s *ReconciliationService) Run(ctx context.Context, deleteOrphans bool) (*domain.FRReconciliationRun, error) {
+	run := &domain.FRReconciliationRun{
+		ID:            uuid.NewString(),
+		Status:        domain.FRReconciliationRunning,
+		DeleteOrphans: deleteOrphans,
+		StartedAt:     time.Now().UTC(),
+	}
+	if err := s.runs.Create(ctx, run); err != nil {
+		return nil, err
+	}
+
+	s.execute(ctx, run)
+	return run, nil
+}
+
+// Get returns a reconciliation run by ID.
+func (s *ReconciliationService) Get(ctx context.Context, id string) (*domain.FRReconciliationRun, error) {
+	run, err := s.runs.GetByID(ctx, strings.TrimSpace(id))
+	if err != nil {
+		return nil, err
+	}
+	if run == nil {
+		return nil, ErrReconciliationNotFound
+	}
+	return run, nil
+}
+
+// List returns the most recent reconciliation runs.
+func (s *ReconciliationService) List(ctx context.Context) ([]domain.FRReconciliationRun, error) {
+	return s.runs.List(ctx, 50)
+}
+
+// runJob executes a triggered run; a failed run fails the attempt so the job is retried.
+func (s *ReconciliationService) runJob(ctx context.Context, payload json.RawMessage) (interface{}, error) {
+	var input reconcileJob
+	if err := json.Unmarshal(payload, &input); err != nil {
+		return nil, fmt.Errorf("decode reconcile job: %w", err)
+	}
+	run, err := s.Get(ctx, input.RunID)
+	if err != nil {
+		return nil, err
+	}
+
+	run.Status = domain.FRReconciliationRunning
+	run.Error = nil
+	s.execute(ctx, run)
+	if run.Error != nil {
+		return nil, errors.New(*run.Error)
+	}
+	return map[string]interface{}{
+		"run_id":        run.ID,
+		"orphan_count":  run.OrphanCount,
+		"deleted_count": run.DeletedCount,
+	}, nil
+}
+
+func (s *ReconciliationService) execute(ctx context.Context, run *domain.FRReconciliationRun) {
+	orphans, remoteCount, err := s.findOrphans(ctx)
+	if err == nil && run.DeleteOrphans {
+		for i := range orphans {
+			if delErr := s.frClient.DeleteFace(ctx, orphans[i].Label); delErr != nil {
+				orphans[i].Error = delErr.Error()
+				continue
+			}
+			orphans[i].Deleted = true
+			run.DeletedCount++
+		}
+	}
+
+	finished := time.Now().UTC()
+	run.FinishedAt = &finished
+	run.RemoteCount = remoteCount
+	run.OrphanCount = len(orphans)
+	run.Status = domain.FRReconciliationCompleted
+	if err != nil {
+		message := err.Error()
+		run.Error = &message
+		run.Status = domain.FRReconciliationFailed
+	}
+	if payload, marshalErr := json.Marshal(orphans); marshalErr == nil {
+		run.Orphans = string(payload)
+	}
+
+	if err := s.runs.Update(ctx, run); err != nil {
+		log.Printf("[reconcile] persist run %s: %v", run.ID, err)
+		return
+	}
+	log.Printf("[reconcile] run %s finished status=%s remote=%d orphans=%d deleted=%d", run.ID, run.Status, run.RemoteCount, run.OrphanCount, run.DeletedCount)
+}
+
+func (s *ReconciliationService) findOrphans(ctx context.Context) ([]OrphanFace, int, error) {
+	faces, err := s.frClient.ListFaces(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Tenants may share an FR Core instance, so a face is only an orphan when
+	// no tenant knows its label.
+	labels, err := s.frIdentities.ListLabels(tenant.System(ctx))
+	if err != nil {
+		return nil, len(faces), err
+	}
+	known := make(map[string]struct{}, len(labels))
+	for _, label := range labels {
+		known[label] = struct{}{}
+	}
+
+	cutoff := time.Now().Add(-orphanGracePeriod)
+	orphans := []OrphanFace{}
+	for _, face := range faces {
+		label := strings.TrimSpace(face.Label)
+		if label == "" {
+			continue
+		}
+		if _, ok := known[label]; ok {
+			continue
+		}
+		if face.CreatedAt != nil && face.CreatedAt.After(cutoff) {
+			continue
+		}
+		orphans = append(orphans, OrphanFace{Label: label, ExternalRef: face.ExternalRef})
+	}
+	return orphans, len(faces), nil
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/events"
+	"life-certificates/internal/repository"
+)
+
+const reminderBatchSize = 200
+
+// ReminderOptions sets the reminder cadence.
+type ReminderOptions struct {
+	// LeadDays is how many working days before a certificate lapses or a
+	// campaign is due reminders start.
+	LeadDays int
+	// RepeatDays is the minimum gap between two reminders to the same participant.
+	RepeatDays int
+	// MaxPerTarget caps reminders about the same certificate or campaign.
+	MaxPerTarget int
+}
+
+// ReminderService finds participants whose verification is due or overdue and
+// publishes a reminder event for each, which webhook and broker subscribers
+// turn into SMS, email or push notifications.
+type ReminderService struct {
+	reminders      repository.ReminderRepository
+	tx             repository.Transactor
+	events         events.Publisher
+	calendar       *CalendarService
+	options        ReminderOptions
+	validityMonths int
+}
+
+// NewReminderService wires dependencies for the reminder scheduler.
+func NewReminderService(reminders repository.ReminderRepository, tx repository.Transactor, publisher events.Publisher, calendar *CalendarService, options ReminderOptions, validityMonths int) *ReminderService {
+	return &ReminderService{
+		reminders:      reminders,
+		tx:             tx,
+		events:         publisher,
+		calendar:       calendar,
+		options:        options,
+		validityMonths: validityMonths,
+	}
+}
+
+// Dispatch sends every reminder currently due; it runs as a scheduled task.
+func (s *ReminderService) Dispatch(ctx context.Context) error {
+	if n := s.run(ctx, time.Now().UTC()); n > 0 {
+		log.Printf("dispatched %d verification reminders", n)
+	}
+	return nil
+}
+
+// run dispatches reminders for lapsing certificates first, then unfinished
+// campaigns, sending each participant at most one reminder per run.
+func (s *ReminderService) run(ctx context.Context, now time.Time) int {
+	limits := repository.ReminderLimits{
+		QuietSince:   now.AddDate(0, 0, -s.options.RepeatDays),
+		MaxPerTarget: s.options.MaxPerTarget,
+		Limit:        reminderBatchSize,
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	reminded := make(map[string]bool)
+
+	// The lead time is counted in national working days.
+	dueBy, err := s.calendar.AddWorkingDays(ctx, today, s.options.LeadDays, "")
+	if err != nil {
+		log.Printf("compute reminder lead time: %v", err)
+		return 0
+	}
+	cutoff := now.Add(dueBy.Sub(today)).AddDate(0, -s.validityMonths, 0)
+	dispatched := s.dispatchAll(ctx, now, reminded, domain.ReminderKindExpiring, func() ([]repository.ReminderCandidate, error) {
+		return s.reminders.DueExpiring(ctx, cutoff, limits)
+	})
+
+	dispatched += s.dispatchAll(ctx, now, reminded, domain.ReminderKindCampaign, func() ([]repository.ReminderCandidate, error) {
+		return s.reminders.DueCampaign(ctx, today, dueBy, limits)
+	})
+	return dispatched
+}
+
+// dispatchAll drains the candidate query batch by batch. Every dispatched
+// reminder drops out of the next batch, so the loop ends once a batch sends nothing.
+func (s *ReminderService) dispatchAll(ctx context.Context, now time.Time, reminded map[string]bool, kind domain.ReminderKind, next func() ([]repository.ReminderCandidate, error)) int {
+	total := 0
+	for {
+		candidates, err := next()
+		if err != nil {
+			log.Printf("find %s reminder candidates: %v", kind, err)
+			return total
+		}
+		sent := 0
+		for _, candidate := range candidates {
+			if reminded[candidate.ParticipantID] {
+				continue
+			}
+			if err := s.dispatch(ctx, now, kind, candidate); err != nil {
+				log.Printf("dispatch reminder to participant %s: %v", candidate.ParticipantID, err)
+				continue
+			}
+			reminded[candidate.ParticipantID] = true
+			sent++
+		}
+		total += sent
+		if sent == 0 || len(candidates) < reminderBatchSize {
+			return total
+		}
+	}
+}
+
+func (s *ReminderService) dispatch(ctx context.Context, now time.Time, kind domain.ReminderKind, candidate repository.ReminderCandidate) error {
+	dueAt := candidate.At
+	if kind == domain.ReminderKindExpiring {
+		// A certificate lapsing on a day off may be renewed on the next working day.
+		lapses := candidate.At.AddDate(0, s.validityMonths, 0)
+		renewBy, err := s.calendar.NextWorkingDay(ctx, lapses, candidate.Region)
+		if err != nil {
+			return err
+		}
+		dueAt = renewBy
+	}
+	reminder := &domain.Reminder{
+		ID:            uuid.NewString(),
+		ParticipantID: candidate.ParticipantID,
+		Kind:          kind,
+		Reference:     candidate.Reference,
+		DueAt:         dueAt,
+		CreatedAt:     now,
+	}
+
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.reminders.Create(ctx, reminder); err != nil {
+			return err
+		}
+		return publishEvent(ctx, s.events, events.TypeReminderDue, reminderData(reminder, now))
+	})
+}
+
+// reminderData is the data of a reminder's participant.reminder_due event.
+func reminderData(reminder *domain.Reminder, now time.Time) map[string]interface{} {
+	data := map[string]interface{}{
+		"reminder_id":    reminder.ID,
+		"participant_id": reminder.ParticipantID,
+		"kind":           reminder.Kind,
+		"due_at":         reminder.DueAt,
+		"overdue":        reminder.DueAt.Before(now),
+	}
+	if reminder.Kind == domain.ReminderKindExpiring {
+		data["certificate_id"] = reminder.Reference
+	} else {
+		data["campaign_id"] = reminder.Reference
+	}
+	return data
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/storage"
+)
+
+const (
+	auditActionSelfiePurge = "certificate.selfie_purge"
+	retentionActor         = "retention"
+	retentionBatchSize     = 200
+)
+
+// RetentionPolicy caps how long stored selfies are kept.
+type RetentionPolicy struct {
+	// SelfieMonths purges selfies taken longer ago than this; 0 keeps them regardless of age.
+	SelfieMonths int
+	// LatestValidOnly keeps only each participant's latest VALID selfie and those awaiting review.
+	LatestValidOnly bool
+}
+
+// RetentionService purges personal data the retention policy no longer allows.
+type RetentionService struct {
+	certificates repository.LifeCertificateRepository
+	audit        repository.AuditLogRepository
+	blobs        storage.BlobStore
+	tx           repository.Transactor
+	policy       RetentionPolicy
+}
+
+// NewRetentionService wires dependencies for retention purges.
+func NewRetentionService(certificates repository.LifeCertificateRepository, audit repository.AuditLogRepository, blobs storage.BlobStore, tx repository.Transactor, policy RetentionPolicy) *RetentionService {
+	return &RetentionService{certificates: certificates, audit: audit, blobs: blobs, tx: tx, policy: policy}
+}
+
+// PurgeSelfies deletes the stored selfies the policy no longer allows and
+// clears their SelfiePath, writing an audit entry for each.
+func (s *RetentionService) PurgeSelfies(ctx context.Context) error {
+	n, err := s.purgeSelfies(ctx, time.Now().UTC())
+	if n > 0 {
+		log.Printf("purged %d selfies past retention", n)
+	}
+	return err
+}
+
+func (s *RetentionService) purgeSelfies(ctx context.Context, now time.Time) (int, error) {
+	var takenBefore *time.Time
+	if s.policy.SelfieMonths > 0 {
+		cutoff := now.AddDate(0, -s.policy.SelfieMonths, 0)
+		takenBefore = &cutoff
+	}
+
+	purged := 0
+	for {
+		records, err := s.certificates.ListSelfiesToPurge(ctx, takenBefore, s.policy.LatestValidOnly, retentionBatchSize)
+		if err != nil || len(records) == 0 {
+			return purged, err
+		}
+		for _, record := range records {
+			if err := s.purgeSelfie(ctx, record, takenBefore); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+	}
+}
+
+// purgeSelfie deletes the blob first, so a failure afterwards leaves a path
+// to a missing file rather than an untracked copy of the photo. The PDF
+// certificate embeds the photo, so it goes too and is rendered again without
+// it on the next download.
+func (s *RetentionService) purgeSelfie(ctx context.Context, record domain.LifeCertificate, takenBefore *time.Time) error {
+	if err := s.blobs.Delete(ctx, record.SelfiePath); err != nil {
+		return err
+	}
+	if record.DocumentPath != "" {
+		if err := s.blobs.Delete(ctx, record.DocumentPath); err != nil {
+			return err
+		}
+	}
+	reason := "superseded"
+	if takenBefore != nil && record.VerifiedAt.Before(*takenBefore) {
+		reason = "expired"
+	}
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.certificates.ClearSelfiePath(ctx, record.ID); err != nil {
+			return err
+		}
+		if record.DocumentPath != "" {
+			if err := s.certificates.SetDocument(ctx, record.ID, "", ""); err != nil {
+				return err
+			}
+		}
+		return recordAudit(ctx, s.audit, retentionActor, auditActionSelfiePurge, auditEntityLifeCertificate, record.ID, map[string]interface{}{
+			"participant_id": record.ParticipantID,
+			"selfie_path":    record.SelfiePath,
+			"verified_at":    record.VerifiedAt,
+			"status":         record.Status,
+			"reason":         reason,
+		})
+	})
+}
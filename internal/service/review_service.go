@@ -0,0 +1,400 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/events"
+	"life-certificates/internal/metrics"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/tracing"
+)
+
+// Review decisions accepted by Resolve.
+const (
+	ReviewDecisionApprove = "approve"
+	ReviewDecisionReject  = "reject"
+)
+
+// Audit vocabulary for review actions.
+const (
+	auditEntityLifeCertificate = "life_certificate"
+	auditActionReviewClaim     = "review.claim"
+	auditActionReviewAssign    = "review.assign"
+	auditActionReviewResolve   = "review.resolve"
+)
+
+var (
+	// ErrCertificateNotFound indicates the requested life certificate does not exist.
+	ErrCertificateNotFound = errors.New("life certificate not found")
+	// ErrReviewNotPending signals the attempt is not awaiting review.
+	ErrReviewNotPending = errors.New("life certificate is not pending review")
+	// ErrReviewClaimedByOther signals another reviewer holds the review.
+	ErrReviewClaimedByOther = errors.New("review is assigned to another reviewer")
+	// ErrSeniorReviewRequired signals a senior review was claimed, assigned or
+	// decided by someone who is not a senior reviewer.
+	ErrSeniorReviewRequired = errors.New("review needs a senior reviewer")
+)
+
+// ReviewService manages the manual review queue for REVIEW attempts.
+type ReviewService struct {
+	certificates repository.LifeCertificateRepository
+	audit        repository.AuditLogRepository
+	sla          time.Duration
+	tx           repository.Transactor
+	events       events.Publisher
+	seniors      map[string]bool
+}
+
+// NewReviewService wires dependencies for manual review. Senior reviews can
+// only be held and decided by the named senior reviewers.
+func NewReviewService(certificates repository.LifeCertificateRepository, audit repository.AuditLogRepository, sla time.Duration, seniors []string, tx repository.Transactor, publisher events.Publisher) *ReviewService {
+	s := &ReviewService{certificates: certificates, audit: audit, sla: sla, tx: tx, events: publisher, seniors: map[string]bool{}}
+	for _, reviewer := range seniors {
+		s.seniors[reviewer] = true
+	}
+	return s
+}
+
+// overdueBuckets groups overdue reviews by how long past their due date they are.
+var overdueBuckets = []struct {
+	label string
+	upTo  time.Duration
+}{
+	{label: "0-24h", upTo: 24 * time.Hour},
+	{label: "1-3d", upTo: 72 * time.Hour},
+	{label: "3-7d", upTo: 7 * 24 * time.Hour},
+	{label: "7d+"},
+}
+
+// ReviewQueueInput carries queue filters and paging.
+type ReviewQueueInput struct {
+	ParticipantID string
+	AssignedTo    string
+	Unassigned    bool
+	// Senior keeps senior reviews when true and the others when false.
+	Senior   *bool
+	From     string
+	To       string
+	Page     int
+	PageSize int
+}
+
+// ReviewQueueOutput is a page of attempts awaiting review.
+type ReviewQueueOutput struct {
+	Items    []domain.LifeCertificate `json:"items"`
+	Page     int                      `json:"page"`
+	PageSize int                      `json:"page_size"`
+	Total    int64                    `json:"total"`
+}
+
+// AssignReviewInput names the reviewer receiving the review.
+type AssignReviewInput struct {
+	Reviewer string `json:"reviewer"`
+}
+
+// ResolveReviewInput carries the reviewer's decision.
+type ResolveReviewInput struct {
+	Decision string `json:"decision"`
+	Notes    string `json:"notes"`
+}
+
+// OverdueBucket is a group of overdue reviews of similar age.
+type OverdueBucket struct {
+	Bucket string                   `json:"bucket"`
+	Count  int                      `json:"count"`
+	Items  []domain.LifeCertificate `json:"items"`
+}
+
+// OverdueReviewsOutput lists overdue reviews grouped by age bucket.
+type OverdueReviewsOutput struct {
+	SLAHours float64         `json:"sla_hours"`
+	Total    int             `json:"total"`
+	Buckets  []OverdueBucket `json:"buckets"`
+}
+
+// ReviewMetricsOutput summarises backlog health and decision speed.
+type ReviewMetricsOutput struct {
+	SLAHours         float64 `json:"sla_hours"`
+	Pending          int64   `json:"pending"`
+	Overdue          int64   `json:"overdue"`
+	Decided          int64   `json:"decided"`
+	DecidedWithinSLA int64   `json:"decided_within_sla"`
+	DecidedLate      int64   `json:"decided_late"`
+	AvgDecisionHours float64 `json:"average_time_to_decision_hours"`
+}
+
+// BackfillDueDates assigns SLA due dates to pending reviews that predate SLA tracking.
+func (s *ReviewService) BackfillDueDates(ctx context.Context) (int64, error) {
+	return s.certificates.BackfillReviewDueAt(ctx, s.sla)
+}
+
+// QueueDepth counts pending reviews and how many of them are overdue.
+func (s *ReviewService) QueueDepth(ctx context.Context) (int64, int64, error) {
+	stats, err := s.certificates.ReviewStats(ctx, nil, nil, time.Now().UTC())
+	if err != nil {
+		return 0, 0, err
+	}
+	return stats.Pending, stats.Overdue, nil
+}
+
+// Overdue lists pending reviews past their due date, grouped by how late they are.
+func (s *ReviewService) Overdue(ctx context.Context) (*OverdueReviewsOutput, error) {
+	now := time.Now().UTC()
+	records, err := s.certificates.ListOverdueReviews(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make([]OverdueBucket, len(overdueBuckets))
+	for i, b := range overdueBuckets {
+		buckets[i] = OverdueBucket{Bucket: b.label, Items: []domain.LifeCertificate{}}
+	}
+	for _, record := range records {
+		late := now.Sub(*record.ReviewDueAt)
+		idx := len(overdueBuckets) - 1
+		for i, b := range overdueBuckets {
+			if b.upTo > 0 && late < b.upTo {
+				idx = i
+				break
+			}
+		}
+		buckets[idx].Items = append(buckets[idx].Items, record)
+		buckets[idx].Count++
+	}
+
+	return &OverdueReviewsOutput{SLAHours: s.sla.Hours(), Total: len(records), Buckets: buckets}, nil
+}
+
+// Metrics reports the backlog and the average time-to-decision for reviews decided in the window.
+func (s *ReviewService) Metrics(ctx context.Context, fromRaw, toRaw string) (*ReviewMetricsOutput, error) {
+	verr := &ValidationError{}
+	from, until := parseDateRange(verr, fromRaw, toRaw)
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.certificates.ReviewStats(ctx, from, until, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReviewMetricsOutput{
+		SLAHours:         s.sla.Hours(),
+		Pending:          stats.Pending,
+		Overdue:          stats.Overdue,
+		Decided:          stats.Decided,
+		DecidedWithinSLA: stats.Decided - stats.DecidedLate,
+		DecidedLate:      stats.DecidedLate,
+		AvgDecisionHours: stats.AvgDecisionSeconds / 3600,
+	}, nil
+}
+
+// Queue lists unresolved REVIEW attempts, oldest first.
+func (s *ReviewService) Queue(ctx context.Context, input ReviewQueueInput) (*ReviewQueueOutput, error) {
+	filter := repository.ReviewQueueFilter{
+		ParticipantID: strings.TrimSpace(input.ParticipantID),
+		AssignedTo:    strings.TrimSpace(input.AssignedTo),
+		Unassigned:    input.Unassigned,
+		Senior:        input.Senior,
+	}
+	verr := &ValidationError{}
+	filter.From, filter.To = parseDateRange(verr, input.From, input.To)
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	page := normalizePagination(input.Page, input.PageSize)
+	items, total, err := s.certificates.ListReviewQueue(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReviewQueueOutput{Items: items, Page: page.Page, PageSize: page.PageSize, Total: total}, nil
+}
+
+// Claim assigns the review to the acting reviewer.
+func (s *ReviewService) Claim(ctx context.Context, actor, certificateID string) (*domain.LifeCertificate, error) {
+	record, err := s.pending(ctx, certificateID)
+	if err != nil {
+		return nil, err
+	}
+	if record.SeniorReview && !s.seniors[actor] {
+		return nil, ErrSeniorReviewRequired
+	}
+
+	ok, err := s.certificates.Claim(ctx, record.ID, actor, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrReviewClaimedByOther
+	}
+
+	if err := recordAudit(ctx, s.audit, actor, auditActionReviewClaim, auditEntityLifeCertificate, record.ID, map[string]interface{}{
+		"previous_assignee": record.AssignedTo,
+		"assignee":          actor,
+	}); err != nil {
+		return nil, err
+	}
+	return s.certificates.GetByID(ctx, record.ID)
+}
+
+// Assign hands the review to the named reviewer.
+func (s *ReviewService) Assign(ctx context.Context, actor, certificateID string, input AssignReviewInput) (*domain.LifeCertificate, error) {
+	reviewer := strings.TrimSpace(input.Reviewer)
+	if reviewer == "" {
+		return nil, fmt.Errorf("reviewer is required")
+	}
+
+	record, err := s.pending(ctx, certificateID)
+	if err != nil {
+		return nil, err
+	}
+	if record.SeniorReview && !s.seniors[reviewer] {
+		return nil, ErrSeniorReviewRequired
+	}
+
+	ok, err := s.certificates.Assign(ctx, record.ID, reviewer, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrReviewNotPending
+	}
+
+	if err := recordAudit(ctx, s.audit, actor, auditActionReviewAssign, auditEntityLifeCertificate, record.ID, map[string]interface{}{
+		"previous_assignee": record.AssignedTo,
+		"assignee":          reviewer,
+	}); err != nil {
+		return nil, err
+	}
+	return s.certificates.GetByID(ctx, record.ID)
+}
+
+// Resolve records the decision: approve marks the attempt VALID, reject marks it INVALID.
+func (s *ReviewService) Resolve(ctx context.Context, actor, certificateID string, input ResolveReviewInput) (*domain.LifeCertificate, error) {
+	ctx, span := tracing.Start(ctx, "ReviewService.Resolve", attribute.String("certificate_id", certificateID))
+	defer span.End()
+
+	var status domain.LifeCertificateStatus
+	switch strings.ToLower(strings.TrimSpace(input.Decision)) {
+	case ReviewDecisionApprove:
+		status = domain.LifeCertificateStatusValid
+	case ReviewDecisionReject:
+		status = domain.LifeCertificateStatusInvalid
+	default:
+		return nil, fmt.Errorf("decision must be approve or reject")
+	}
+	notes := strings.TrimSpace(input.Notes)
+	if notes == "" {
+		return nil, fmt.Errorf("notes are required")
+	}
+
+	record, err := s.pending(ctx, certificateID)
+	if err != nil {
+		return nil, err
+	}
+	if record.AssignedTo != nil && *record.AssignedTo != actor {
+		return nil, ErrReviewClaimedByOther
+	}
+	if record.SeniorReview && !s.seniors[actor] {
+		return nil, ErrSeniorReviewRequired
+	}
+
+	now := time.Now().UTC()
+	previous := record.Status
+	record.Status = status
+	record.ReviewedBy = &actor
+	record.ReviewedAt = &now
+	record.ReviewNotes = &notes
+
+	decision := strings.ToLower(strings.TrimSpace(input.Decision))
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		ok, err := s.certificates.Resolve(ctx, record)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrReviewNotPending
+		}
+		if err := recordAudit(ctx, s.audit, actor, auditActionReviewResolve, auditEntityLifeCertificate, record.ID, map[string]interface{}{
+			"decision":    decision,
+			"from_status": previous,
+			"to_status":   status,
+			"notes":       notes,
+		}); err != nil {
+			return err
+		}
+		return publishEvent(ctx, s.events, events.TypeVerificationCompleted, verificationCompletedData(record))
+	})
+	if err != nil {
+		return nil, err
+	}
+	metrics.VerificationRecorded(string(record.Status), string(record.Method))
+	return record, nil
+}
+
+// History returns the audit trail for a certificate's review.
+func (s *ReviewService) History(ctx context.Context, certificateID string) ([]domain.AuditLog, error) {
+	record, err := s.certificates.GetByID(ctx, strings.TrimSpace(certificateID))
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, ErrCertificateNotFound
+	}
+	return s.audit.ListByEntity(ctx, auditEntityLifeCertificate, record.ID)
+}
+
+func (s *ReviewService) pending(ctx context.Context, certificateID string) (*domain.LifeCertificate, error) {
+	record, err := s.certificates.GetByID(ctx, strings.TrimSpace(certificateID))
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, ErrCertificateNotFound
+	}
+	if record.Status != domain.LifeCertificateStatusReview || record.ReviewedAt != nil {
+		return nil, ErrReviewNotPending
+	}
+	return record, nil
+}
+
+// parseDateRange parses the optional from and to dates of a filter and returns
+// the bounds to query with. until is exclusive, the start of the day after to,
+// so that to covers the whole day. Invalid dates are reported on verr.
+func parseDateRange(verr *ValidationError, rawFrom, rawTo string) (from, until *time.Time) {
+	var err error
+	if from, err = parseDateParam("from", rawFrom); err != nil {
+		verr.add("from", err.Error())
+	}
+	to, err := parseDateParam("to", rawTo)
+	if err != nil {
+		verr.add("to", err.Error())
+	}
+	if to != nil {
+		end := to.AddDate(0, 0, 1)
+		until = &end
+	}
+	return from, until
+}
+
+// parseDateParam parses an optional YYYY-MM-DD query value.
+func parseDateParam(name, raw string) (*time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s date, use YYYY-MM-DD", name)
+	}
+	return &parsed, nil
+}
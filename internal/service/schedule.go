@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+)
+
+const scheduleDateLayout = "01-02"
+
+// Where a participant's due date came from.
+const (
+	ScheduleSourceCampaign = "campaign"
+	ScheduleSourceProfile  = "profile"
+	ScheduleSourceDefault  = "default"
+)
+
+// ScheduleSettings is the due date policy applied when no campaign or profile overrides it.
+type ScheduleSettings struct {
+	Policy domain.SchedulePolicy
+	// Date is the yearly MM-DD due date for FIXED_DATE.
+	Date string
+	// ValidityMonths is the ROLLING interval after the last VALID verification.
+	ValidityMonths int
+}
+
+// VerificationSchedule is when a participant's next verification is due.
+type VerificationSchedule struct {
+	ParticipantID string `json:"participant_id"`
+	// Policy is the rule that produced DueAt; empty when a campaign set it.
+	Policy domain.SchedulePolicy `json:"policy,omitempty"`
+	// Source is campaign, profile or default.
+	Source      string     `json:"source"`
+	ProfileID   *string    `json:"profile_id,omitempty"`
+	CampaignID  *string    `json:"campaign_id,omitempty"`
+	LastValidAt *time.Time `json:"last_valid_at"`
+	DueAt       time.Time  `json:"due_at"`
+	// DaysRemaining counts days from today until DueAt and is negative once overdue.
+	DaysRemaining int  `json:"days_remaining"`
+	Overdue       bool `json:"overdue"`
+	// Escalation is set when the campaign has a grace-period policy.
+	Escalation *ScheduleEscalation `json:"escalation,omitempty"`
+}
+
+// ScheduleEscalation is where a participant stands in their campaign's
+// grace-period policy.
+type ScheduleEscalation struct {
+	// Stage is the last stage reached, empty before the first.
+	Stage       domain.CampaignEscalationStage `json:"stage"`
+	EscalatedAt *time.Time                     `json:"escalated_at"`
+	// NextStage and NextAt are the stage the participant reaches next if still
+	// pending, and the day they reach it; empty after the last stage.
+	NextStage       domain.CampaignEscalationStage `json:"next_stage,omitempty"`
+	NextAt          *time.Time                     `json:"next_at,omitempty"`
+	HoldRecommended bool                           `json:"hold_recommended"`
+}
+
+// Schedule returns the participant's next verification due date.
+func (s *ParticipantService) Schedule(ctx context.Context, id string) (*VerificationSchedule, error) {
+	participant, err := s.participants.GetByID(ctx, strings.TrimSpace(id))
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return nil, ErrParticipantNotFound
+	}
+
+	state, err := s.states.GetByParticipant(ctx, participant.ID)
+	if err != nil {
+		return nil, err
+	}
+	var lastValidAt *time.Time
+	if state != nil {
+		lastValidAt = state.LastValidAt
+	}
+	return s.verificationSchedule(ctx, participant, lastValidAt, time.Now().UTC())
+}
+
+// verificationSchedule applies, in order, an unfinished campaign, the
+// participant's or fund's profile, and the configured default policy. A due
+// date the policy puts on a weekend or holiday moves to the next working day;
+// campaign due dates were moved when the campaign was created.
+func (s *ParticipantService) verificationSchedule(ctx context.Context, participant *domain.Participant, lastValidAt *time.Time, now time.Time) (*VerificationSchedule, error) {
+	today := dateOf(now)
+	schedule := &VerificationSchedule{ParticipantID: participant.ID, LastValidAt: lastValidAt}
+
+	campaign, err := s.campaigns.NextDueForParticipant(ctx, participant.ID, today)
+	if err != nil {
+		return nil, err
+	}
+	if campaign != nil {
+		schedule.Source = ScheduleSourceCampaign
+		schedule.CampaignID = &campaign.CampaignID
+		schedule.DueAt = dateOf(campaign.DueAt)
+		if campaign.Escalation.Enabled() {
+			schedule.Escalation = scheduleEscalation(campaign)
+		}
+		return schedule.countdown(today), nil
+	}
+
+	settings := s.schedule
+	schedule.Source = ScheduleSourceDefault
+	profile, err := resolveProfile(ctx, s.profiles, participant)
+	if err != nil {
+		return nil, err
+	}
+	if profile != nil && profile.SchedulePolicy != nil {
+		schedule.Source = ScheduleSourceProfile
+		schedule.ProfileID = &profile.ID
+		settings.Policy = *profile.SchedulePolicy
+		if profile.ScheduleDate != nil {
+			settings.Date = *profile.ScheduleDate
+		}
+		if profile.ScheduleMonths > 0 {
+			settings.ValidityMonths = profile.ScheduleMonths
+		}
+	}
+
+	// The member's province picks the regional holidays a due date skips.
+	var member *domain.Member
+	if participant.MemberID != nil {
+		if member, err = s.members.GetByID(ctx, *participant.MemberID); err != nil {
+			return nil, err
+		}
+	}
+
+	var anchor func(year int) time.Time
+	switch settings.Policy {
+	case domain.SchedulePolicyFixedDate:
+		date, err := time.Parse(scheduleDateLayout, settings.Date)
+		if err == nil {
+			anchor = func(year int) time.Time {
+				return time.Date(year, date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+			}
+		}
+	case domain.SchedulePolicyBirthdayMonth:
+		if member != nil {
+			month := member.BirthDate.Month()
+			anchor = func(year int) time.Time {
+				return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+			}
+		}
+	}
+
+	if anchor == nil {
+		// Participants without a usable anchor, e.g. no linked member for
+		// BIRTHDAY_MONTH, fall back to the rolling interval.
+		schedule.Policy = domain.SchedulePolicyRolling
+		schedule.DueAt = rollingDue(participant, schedule.LastValidAt, settings.ValidityMonths)
+	} else {
+		schedule.Policy = settings.Policy
+		schedule.DueAt = annualDue(anchor, participant.CreatedAt, schedule.LastValidAt, today)
+	}
+	region := ""
+	if member != nil {
+		region = member.Province
+	}
+	if schedule.DueAt, err = s.calendar.NextWorkingDay(ctx, schedule.DueAt, region); err != nil {
+		return nil, err
+	}
+	return schedule.countdown(today), nil
+}
+
+// scheduleEscalation finds the first stage of the policy after the one the
+// participant reached.
+func scheduleEscalation(campaign *repository.CampaignDue) *ScheduleEscalation {
+	escalation := &ScheduleEscalation{
+		Stage:           campaign.Stage,
+		EscalatedAt:     campaign.EscalatedAt,
+		HoldRecommended: campaign.Stage == domain.CampaignStageHoldRecommended,
+	}
+	reached := campaign.Stage == ""
+	for _, stage := range domain.CampaignEscalationStages {
+		if !reached {
+			reached = stage == campaign.Stage
+			continue
+		}
+		if days, ok := campaign.Escalation.Days(stage); ok {
+			next := dateOf(campaign.DueAt).AddDate(0, 0, days)
+			escalation.NextStage = stage
+			escalation.NextAt = &next
+			break
+		}
+	}
+	return escalation
+}
+
+func (v *VerificationSchedule) countdown(today time.Time) *VerificationSchedule {
+	v.DaysRemaining = int(v.DueAt.Sub(today).Hours() / 24)
+	v.Overdue = v.DueAt.Before(today)
+	return v
+}
+
+// rollingDue is validityMonths after the last VALID verification. Participants
+// who never passed are due immediately, i.e. from registration.
+func rollingDue(participant *domain.Participant, lastValidAt *time.Time, validityMonths int) time.Time {
+	if lastValidAt == nil {
+		return dateOf(participant.CreatedAt)
+	}
+	return dateOf(lastValidAt.AddDate(0, validityMonths, 0))
+}
+
+// annualDue finds the deadline of the yearly cycle the participant still owes.
+// A cycle runs from the day after one anchor date up to and including the
+// next; a VALID verification anywhere in it satisfies that cycle.
+func annualDue(anchor func(year int) time.Time, registeredAt time.Time, lastValidAt *time.Time, today time.Time) time.Time {
+	next := anchor(today.Year())
+	if next.Before(today) {
+		next = anchor(today.Year() + 1)
+	}
+	previous := anchor(next.Year() - 1)
+
+	var verified time.Time
+	if lastValidAt != nil {
+		verified = dateOf(*lastValidAt)
+	}
+	if verified.After(previous) {
+		return anchor(next.Year() + 1)
+	}
+	// A participant already registered for the previous cycle who did not
+	// verify in it is still due for that one.
+	if !dateOf(registeredAt).After(previous) && !verified.After(anchor(previous.Year()-1)) {
+		return previous
+	}
+	return next
+}
+
+func validScheduleDate(value string) bool {
+	_, err := time.Parse(scheduleDateLayout, value)
+	return err == nil
+}
+
+func dateOf(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
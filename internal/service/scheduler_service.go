@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/tenant"
+)
+
+// scheduledTaskLease bounds one run; a replica that dies mid-run blocks the
+// task for at most this long.
+const scheduledTaskLease = time.Hour
+
+// ScheduledTaskFunc is one run of a scheduled task.
+type ScheduledTaskFunc func(ctx context.Context) error
+
+// SchedulerService runs tasks on cron schedules so that each scheduled time
+// runs on exactly one replica: replicas compute the same slots and race to
+// claim them in scheduled_tasks, and a run in progress keeps the others out.
+// A run calls the task once for each enabled tenant.
+type SchedulerService struct {
+	tasks      repository.ScheduledTaskRepository
+	tenants    repository.TenantRepository
+	owner      string
+	registered []scheduledTask
+}
+
+type scheduledTask struct {
+	name     string
+	spec     string
+	schedule cron.Schedule
+	run      ScheduledTaskFunc
+}
+
+// NewSchedulerService wires dependencies for the task scheduler.
+func NewSchedulerService(tasks repository.ScheduledTaskRepository, tenants repository.TenantRepository) *SchedulerService {
+	host, _ := os.Hostname()
+	return &SchedulerService{
+		tasks:   tasks,
+		tenants: tenants,
+		owner:   fmt.Sprintf("%s:%d:%s", host, os.Getpid(), uuid.NewString()[:8]),
+	}
+}
+
+// Register adds a task under a unique name; an empty spec leaves it disabled.
+// Call it before Start.
+func (s *SchedulerService) Register(name, spec string, run ScheduledTaskFunc) error {
+	if spec == "" {
+		log.Printf("[scheduler] task %s disabled", name)
+		return nil
+	}
+	// Replicas must agree on slots whatever their local time zone.
+	zoned := spec
+	if !strings.HasPrefix(spec, "CRON_TZ=") && !strings.HasPrefix(spec, "TZ=") {
+		zoned = "CRON_TZ=UTC " + spec
+	}
+	schedule, err := cron.ParseStandard(zoned)
+	if err != nil {
+		return fmt.Errorf("task %s schedule %q: %w", name, spec, err)
+	}
+	s.registered = append(s.registered, scheduledTask{name: name, spec: spec, schedule: schedule, run: run})
+	return nil
+}
+
+// Tasks returns the shared state of every task, including ones other replicas registered.
+func (s *SchedulerService) Tasks(ctx context.Context) ([]domain.ScheduledTask, error) {
+	return s.tasks.List(ctx)
+}
+
+// Start records the registered tasks and runs each on its schedule until ctx is cancelled.
+func (s *SchedulerService) Start(ctx context.Context) error {
+	now := time.Now().UTC()
+	for _, task := range s.registered {
+		if err := s.tasks.Ensure(ctx, task.name, task.spec, now); err != nil {
+			return err
+		}
+	}
+	for _, task := range s.registered {
+		go s.loop(ctx, task)
+	}
+	return nil
+}
+
+func (s *SchedulerService) loop(ctx context.Context, task scheduledTask) {
+	for {
+		slot := task.schedule.Next(time.Now().UTC())
+		timer := time.NewTimer(time.Until(slot))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.fire(ctx, task, slot)
+		}
+	}
+}
+
+func (s *SchedulerService) fire(ctx context.Context, task scheduledTask, slot time.Time) {
+	now := time.Now().UTC()
+	ok, err := s.tasks.Claim(ctx, task.name, slot, s.owner, now, now.Add(scheduledTaskLease))
+	if err != nil {
+		log.Printf("[scheduler] claim %s: %v", task.name, err)
+		return
+	}
+	if !ok {
+		return
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, scheduledTaskLease)
+	err = s.runForTenants(runCtx, task)
+	cancel()
+
+	var lastError *string
+	if err != nil {
+		message := err.Error()
+		lastError = &message
+		log.Printf("[scheduler] task %s: %v", task.name, err)
+	}
+	if err := s.tasks.Finish(context.WithoutCancel(ctx), task.name, s.owner, time.Now().UTC(), lastError); err != nil {
+		log.Printf("[scheduler] finish %s: %v", task.name, err)
+	}
+}
+
+// runForTenants runs the task for every enabled tenant in turn, so one
+// tenant's failure does not skip the others.
+func (s *SchedulerService) runForTenants(ctx context.Context, task scheduledTask) error {
+	tenants, err := s.tenants.List(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, t := range tenants {
+		if t.DisabledAt != nil {
+			continue
+		}
+		if err := task.run(tenant.WithID(ctx, t.ID)); err != nil {
+			errs = append(errs, fmt.Errorf("tenant %s: %w", t.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
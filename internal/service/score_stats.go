@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"life-certificates/internal/decision"
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+)
+
+// Scores a distribution can be drawn for.
+const (
+	StatsScoreSimilarity = "similarity"
+	StatsScoreDistance   = "distance"
+)
+
+// Default bucket widths of score distributions.
+const (
+	statsSimilarityWidth = 5
+	statsDistanceWidth   = 0.05
+)
+
+// ScoreDistributionInput narrows a score distribution like StatsInput.
+type ScoreDistributionInput struct {
+	StatsInput
+	// Score is similarity (the default) or distance.
+	Score string
+	// Width is the width of a bucket, default 5 for similarity and 0.05 for distance.
+	Width string
+}
+
+// ScoreBucket counts by status the attempts scoring from Min (inclusive) to Max (exclusive).
+type ScoreBucket struct {
+	Min      float64          `json:"min"`
+	Max      float64          `json:"max"`
+	Total    int64            `json:"total"`
+	ByStatus map[string]int64 `json:"by_status"`
+}
+
+// ScoreDistribution is a histogram of a score over a range's automatic attempts.
+type ScoreDistribution struct {
+	From     string  `json:"from"`
+	To       string  `json:"to"`
+	Province string  `json:"province,omitempty"`
+	Fund     string  `json:"fund,omitempty"`
+	Score    string  `json:"score"`
+	Width    float64 `json:"width"`
+	// Threshold is the score's threshold in the verification settings.
+	Threshold float64       `json:"threshold"`
+	Buckets   []ScoreBucket `json:"buckets"`
+}
+
+// ThresholdSimulationInput narrows the attempts replayed like StatsInput;
+// omitted thresholds are those in the verification settings.
+type ThresholdSimulationInput struct {
+	From                string   `json:"from"`
+	To                  string   `json:"to"`
+	Province            string   `json:"province"`
+	Fund                string   `json:"fund"`
+	DistanceThreshold   *float64 `json:"distance_threshold"`
+	SimilarityThreshold *float64 `json:"similarity_threshold"`
+}
+
+// SimulationThresholds are a distance and a similarity threshold.
+type SimulationThresholds struct {
+	DistanceThreshold   float64 `json:"distance_threshold"`
+	SimilarityThreshold float64 `json:"similarity_threshold"`
+}
+
+// SimulatedOutcomes counts replayed attempts by status. PassRate is the
+// percentage of decided attempts that were VALID, nil without any.
+type SimulatedOutcomes struct {
+	ByStatus map[string]int64 `json:"by_status"`
+	PassRate *float64         `json:"pass_rate"`
+}
+
+// ThresholdChanges counts the attempts whose status the thresholds change.
+type ThresholdChanges struct {
+	ValidToInvalid int64 `json:"valid_to_invalid"`
+	InvalidToValid int64 `json:"invalid_to_valid"`
+}
+
+// ThresholdSimulation compares the recorded outcomes of a range's automatic
+// attempts with those the simulated thresholds would have given.
+type ThresholdSimulation struct {
+	From      string               `json:"from"`
+	To        string               `json:"to"`
+	Province  string               `json:"province,omitempty"`
+	Fund      string               `json:"fund,omitempty"`
+	Current   SimulationThresholds `json:"current_thresholds"`
+	Simulated SimulationThresholds `json:"simulated_thresholds"`
+	Attempts  int64                `json:"attempts"`
+	Recorded  SimulatedOutcomes    `json:"recorded"`
+	Outcomes  SimulatedOutcomes    `json:"simulated"`
+	Changes   ThresholdChanges     `json:"changes"`
+}
+
+// ScoreDistribution counts the range's automatic attempts per bucket of
+// similarity or distance and status, empty buckets between the lowest and
+// highest included.
+func (s *StatsService) ScoreDistribution(ctx context.Context, input ScoreDistributionInput) (*ScoreDistribution, error) {
+	score := strings.ToLower(strings.TrimSpace(input.Score))
+	if score == "" {
+		score = StatsScoreSimilarity
+	}
+	settings, err := s.settings.For(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var width, threshold float64
+	switch score {
+	case StatsScoreSimilarity:
+		width, threshold = statsSimilarityWidth, settings.SimilarityThreshold
+	case StatsScoreDistance:
+		width, threshold = statsDistanceWidth, settings.DistanceThreshold
+	default:
+		return nil, &ValidationError{Fields: map[string]string{"score": "must be one of similarity, distance"}}
+	}
+	if raw := strings.TrimSpace(input.Width); raw != "" {
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil || parsed <= 0 || math.IsInf(parsed, 0) {
+			return nil, &ValidationError{Fields: map[string]string{"width": "must be a positive number"}}
+		}
+		width = parsed
+	}
+	filter, from, to, err := statsFilter(input.StatsInput)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.stats.ScoreHistogram(ctx, filter, score, width)
+	if err != nil {
+		return nil, err
+	}
+	out := &ScoreDistribution{
+		From:      from.Format("2006-01-02"),
+		To:        to.Format("2006-01-02"),
+		Province:  filter.Province,
+		Fund:      filter.Fund,
+		Score:     score,
+		Width:     width,
+		Threshold: threshold,
+		Buckets:   []ScoreBucket{},
+	}
+	if len(rows) == 0 {
+		return out, nil
+	}
+	// Rows come ordered by bucket.
+	first, last := rows[0].Bucket, rows[len(rows)-1].Bucket
+	if last-first >= statsMaxBuckets {
+		return nil, fmt.Errorf("scores span more than %d buckets, use a wider width", statsMaxBuckets)
+	}
+	for bucket := first; bucket <= last; bucket++ {
+		out.Buckets = append(out.Buckets, ScoreBucket{
+			Min: roundEdge(float64(bucket) * width),
+			Max: roundEdge(float64(bucket+1) * width),
+			ByStatus: map[string]int64{
+				string(domain.LifeCertificateStatusValid):   0,
+				string(domain.LifeCertificateStatusInvalid): 0,
+				string(domain.LifeCertificateStatusReview):  0,
+			},
+		})
+	}
+	for _, row := range rows {
+		bucket := &out.Buckets[row.Bucket-first]
+		bucket.Total += row.Count
+		bucket.ByStatus[string(row.Status)] += row.Count
+	}
+	return out, nil
+}
+
+// SimulateThresholds replays the range's automatic attempts decided by the
+// decision rules against other thresholds. Each attempt's distance and
+// similarity rules are held to the simulated thresholds, including rules and
+// profiles with thresholds of their own, while its other rules keep their
+// outcome; attempts matching another participant's face stay INVALID.
+func (s *StatsService) SimulateThresholds(ctx context.Context, input ThresholdSimulationInput) (*ThresholdSimulation, error) {
+	settings, err := s.settings.For(ctx)
+	if err != nil {
+		return nil, err
+	}
+	simulated := SimulationThresholds{DistanceThreshold: settings.DistanceThreshold, SimilarityThreshold: settings.SimilarityThreshold}
+	if input.DistanceThreshold != nil {
+		simulated.DistanceThreshold = *input.DistanceThreshold
+	}
+	if input.SimilarityThreshold != nil {
+		simulated.SimilarityThreshold = *input.SimilarityThreshold
+	}
+	verr := &ValidationError{}
+	if simulated.DistanceThreshold <= 0 {
+		verr.add("distance_threshold", "must be positive")
+	}
+	if simulated.SimilarityThreshold < 0 || simulated.SimilarityThreshold > 100 {
+		verr.add("similarity_threshold", "must be between 0 and 100")
+	}
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+	filter, from, to, err := statsFilter(StatsInput{From: input.From, To: input.To, Province: input.Province, Fund: input.Fund})
+	if err != nil {
+		return nil, err
+	}
+
+	thresholds := decision.Thresholds{Distance: simulated.DistanceThreshold, Similarity: simulated.SimilarityThreshold}
+	recorded := make(map[domain.LifeCertificateStatus]int64)
+	outcomes := make(map[domain.LifeCertificateStatus]int64)
+	var changes ThresholdChanges
+	err = s.stats.StreamDecided(ctx, filter, func(attempt *repository.DecidedAttempt) error {
+		var trace decision.Result
+		if err := json.Unmarshal([]byte(attempt.DecisionTrace), &trace); err != nil {
+			return fmt.Errorf("decode decision trace: %w", err)
+		}
+		status := attempt.Status
+		// Attempts sent for review were not decided by their scores.
+		if status != domain.LifeCertificateStatusReview {
+			status = domain.LifeCertificateStatusInvalid
+			if !attempt.Conflict && trace.Replay(thresholds) {
+				status = domain.LifeCertificateStatusValid
+			}
+		}
+		recorded[attempt.Status]++
+		outcomes[status]++
+		switch {
+		case attempt.Status == domain.LifeCertificateStatusValid && status == domain.LifeCertificateStatusInvalid:
+			changes.ValidToInvalid++
+		case attempt.Status == domain.LifeCertificateStatusInvalid && status == domain.LifeCertificateStatusValid:
+			changes.InvalidToValid++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := &ThresholdSimulation{
+		From:      from.Format("2006-01-02"),
+		To:        to.Format("2006-01-02"),
+		Province:  filter.Province,
+		Fund:      filter.Fund,
+		Current:   SimulationThresholds{DistanceThreshold: settings.DistanceThreshold, SimilarityThreshold: settings.SimilarityThreshold},
+		Simulated: simulated,
+		Recorded:  simulatedOutcomes(recorded),
+		Outcomes:  simulatedOutcomes(outcomes),
+		Changes:   changes,
+	}
+	for _, count := range recorded {
+		out.Attempts += count
+	}
+	return out, nil
+}
+
+func simulatedOutcomes(counts map[domain.LifeCertificateStatus]int64) SimulatedOutcomes {
+	outcomes := SimulatedOutcomes{ByStatus: map[string]int64{
+		string(domain.LifeCertificateStatusValid):   counts[domain.LifeCertificateStatusValid],
+		string(domain.LifeCertificateStatusInvalid): counts[domain.LifeCertificateStatusInvalid],
+		string(domain.LifeCertificateStatusReview):  counts[domain.LifeCertificateStatusReview],
+	}}
+	if decided := counts[domain.LifeCertificateStatusValid] + counts[domain.LifeCertificateStatusInvalid]; decided > 0 {
+		rate := percentage(counts[domain.LifeCertificateStatusValid], decided)
+		outcomes.PassRate = &rate
+	}
+	return outcomes
+}
+
+// roundEdge drops the floating point noise of a bucket edge.
+func roundEdge(edge float64) float64 {
+	return math.Round(edge*1e6) / 1e6
+}
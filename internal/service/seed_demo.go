@@ -0,0 +1,152 @@
+package service
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"life-certificates/internal/domain"
+)
+
+const (
+	// maxDemoRecords caps the generated members and participants per request.
+	maxDemoRecords          = 10000
+	defaultDemoMembers      = 50
+	defaultDemoParticipants = 30
+)
+
+var (
+	demoGivenNames  = []string{"Siti", "Budi", "Agus", "Dewi", "Sri", "Joko", "Wahyu", "Rina", "Bambang", "Endang", "Hadi", "Yuliana"}
+	demoFamilyNames = []string{"Rahayu", "Santoso", "Wijaya", "Lestari", "Saputra", "Hidayat", "Kusuma", "Pratama", "Susanto", "Nugroho"}
+	demoPlaces      = []struct{ city, province string }{
+		{"Jakarta Selatan", "DKI Jakarta"},
+		{"Bandung", "Jawa Barat"},
+		{"Semarang", "Jawa Tengah"},
+		{"Surabaya", "Jawa Timur"},
+		{"Medan", "Sumatera Utara"},
+		{"Makassar", "Sulawesi Selatan"},
+		{"Denpasar", "Bali"},
+	}
+	demoLocations = []string{"kiosk-jakarta-01", "kiosk-bandung-01", "kiosk-surabaya-02", "mobile-app", "branch-medan"}
+	demoFunds     = []string{"TASPEN", "ASABRI", "DAPEN-BUMN"}
+	demoOfficers  = []string{"Andi Firmansyah", "Maria Ulfa", "Yusuf Hakim"}
+)
+
+// DemoFixtures generates members and participants with made-up identifiers:
+// NIKs start with 99, which no region code uses, and nomor peserta with
+// DEMO-. The first participants belong to the members, any beyond them stand
+// alone. Certificate histories reach back up to four years from now and mix
+// participants who verify every year, ones whose verification has lapsed,
+// failures followed by a retry, attempts awaiting review, manual
+// verifications and participants never verified. The same seed generates the
+// same people whatever the counts, so a larger run extends a smaller one.
+func DemoFixtures(seed int64, members, participants int, now time.Time) Fixtures {
+	people := rand.New(rand.NewSource(seed))
+	rng := rand.New(rand.NewSource(seed + 1))
+	fixtures := Fixtures{}
+	for i := 0; i < max(members, participants); i++ {
+		person := demoPerson(people)
+		if i < members {
+			fixtures.Members = append(fixtures.Members, person)
+		}
+		if i >= participants {
+			continue
+		}
+		participant := FixtureParticipant{
+			NIK:          person.NIK,
+			Name:         person.FullName,
+			Fund:         demoFunds[rng.Intn(len(demoFunds))],
+			Certificates: demoHistory(rng, now),
+		}
+		if i < members {
+			participant.MemberNIK = person.NIK
+		}
+		fixtures.Participants = append(fixtures.Participants, participant)
+	}
+	return fixtures
+}
+
+func demoPerson(rng *rand.Rand) CreateMemberInput {
+	given := demoGivenNames[rng.Intn(len(demoGivenNames))]
+	family := demoFamilyNames[rng.Intn(len(demoFamilyNames))]
+	place := demoPlaces[rng.Intn(len(demoPlaces))]
+	birthDate := time.Date(1940, time.January, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, rng.Intn(25*365))
+	serial := rng.Int63n(1e14)
+	return CreateMemberInput{
+		NIK:          fmt.Sprintf("99%014d", serial),
+		NomorPeserta: fmt.Sprintf("DEMO-%014d", serial),
+		BirthDate:    birthDate.Format("2006-01-02"),
+		FullName:     given + " " + family,
+		Address:      fmt.Sprintf("Jl. Merdeka No. %d", rng.Intn(200)+1),
+		City:         place.city,
+		Province:     place.province,
+		PhoneNumber:  fmt.Sprintf("+62812%08d", rng.Intn(1e8)),
+		Email:        fmt.Sprintf("%s.%s%d@example.com", strings.ToLower(given), strings.ToLower(family), rng.Intn(1000)),
+	}
+}
+
+// demoHistory draws a participant's attempts, oldest first.
+func demoHistory(rng *rand.Rand, now time.Time) []FixtureCertificate {
+	years := 1 + rng.Intn(4)
+	// Days since the most recent yearly verification.
+	var lastAgo int
+	switch roll := rng.Intn(100); {
+	case roll < 5:
+		return nil
+	case roll < 80:
+		lastAgo = rng.Intn(330)
+	default:
+		// Lapsed: the last verification is over a year old.
+		lastAgo = 380 + rng.Intn(200)
+	}
+
+	var history []FixtureCertificate
+	for year := years - 1; year >= 0; year-- {
+		at := now.AddDate(-year, 0, -lastAgo).Add(time.Duration(8+rng.Intn(8)) * time.Hour).Truncate(time.Minute)
+		switch roll := rng.Intn(100); {
+		case roll < 10:
+			history = append(history, demoAttempt(rng, domain.LifeCertificateStatusValid, domain.VerificationMethodManual, at))
+		case roll < 25:
+			// A failed selfie, retried a little later.
+			history = append(history, demoAttempt(rng, domain.LifeCertificateStatusInvalid, domain.VerificationMethodAutomatic, at))
+			history = append(history, demoAttempt(rng, domain.LifeCertificateStatusValid, domain.VerificationMethodAutomatic, at.Add(time.Duration(10+rng.Intn(120))*time.Minute)))
+		case roll < 35:
+			review := demoAttempt(rng, domain.LifeCertificateStatusReview, domain.VerificationMethodAutomatic, at)
+			// Only the latest review can still be waiting.
+			if year > 0 || lastAgo > 14 {
+				review.ReviewedBy = demoOfficers[rng.Intn(len(demoOfficers))]
+			}
+			history = append(history, review)
+		default:
+			history = append(history, demoAttempt(rng, domain.LifeCertificateStatusValid, domain.VerificationMethodAutomatic, at))
+		}
+	}
+	return history
+}
+
+func demoAttempt(rng *rand.Rand, status domain.LifeCertificateStatus, method domain.VerificationMethod, at time.Time) FixtureCertificate {
+	attempt := FixtureCertificate{
+		Status:     string(status),
+		Method:     string(method),
+		VerifiedAt: at,
+		Location:   demoLocations[rng.Intn(len(demoLocations))],
+	}
+	if method == domain.VerificationMethodManual {
+		attempt.OfficerName = demoOfficers[rng.Intn(len(demoOfficers))]
+		return attempt
+	}
+	var similarity, distance float64
+	switch status {
+	case domain.LifeCertificateStatusValid:
+		similarity, distance = 85+rng.Float64()*14, 0.2+rng.Float64()*0.25
+	case domain.LifeCertificateStatusReview:
+		similarity, distance = 72+rng.Float64()*12, 0.5+rng.Float64()*0.1
+	default:
+		similarity, distance = 30+rng.Float64()*40, 0.7+rng.Float64()*0.5
+	}
+	similarity = float64(int(similarity*100)) / 100
+	distance = float64(int(distance*1000)) / 1000
+	attempt.Similarity, attempt.Distance = &similarity, &distance
+	return attempt
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+)
+
+const (
+	auditEntitySeed     = "seed"
+	auditActionSeedLoad = "seed.load"
+
+	// seedRecorder is recorded as the operator of seeded manual verifications.
+	seedRecorder = "seed"
+)
+
+// SeedService loads fake members, participants and certificate histories into
+// demo and staging databases. Nothing is enrolled in FR Core and no events are
+// published, so webhooks, notifications and payment pushes stay quiet.
+type SeedService struct {
+	members      repository.MemberRepository
+	memberInputs *MemberService
+	participants repository.ParticipantRepository
+	frIdentities repository.FRIdentityRepository
+	certificates repository.LifeCertificateRepository
+	audit        repository.AuditLogRepository
+	tx           repository.Transactor
+	reviewSLA    time.Duration
+}
+
+// NewSeedService wires dependencies for seeding.
+func NewSeedService(members repository.MemberRepository, participants repository.ParticipantRepository, frIdentities repository.FRIdentityRepository, certificates repository.LifeCertificateRepository, audit repository.AuditLogRepository, tx repository.Transactor, reviewSLA time.Duration) *SeedService {
+	return &SeedService{
+		members:      members,
+		memberInputs: NewMemberService(members),
+		participants: participants,
+		frIdentities: frIdentities,
+		certificates: certificates,
+		audit:        audit,
+		tx:           tx,
+		reviewSLA:    reviewSLA,
+	}
+}
+
+// Fixtures are the records to load. Members and participants that already
+// exist, by NIK, are skipped with their certificates, so loading the same
+// fixtures twice changes nothing.
+type Fixtures struct {
+	Members      []CreateMemberInput  `json:"members"`
+	Participants []FixtureParticipant `json:"participants"`
+}
+
+// FixtureParticipant is a participant with their verification history.
+type FixtureParticipant struct {
+	NIK  string `json:"nik"`
+	Name string `json:"name"`
+	// MemberNIK links the participant to the member with this NIK.
+	MemberNIK string `json:"member_nik"`
+	// FRLabel is recorded as the participant's face label; defaults to
+	// demo-<participant id>. Nothing is enrolled in FR Core.
+	FRLabel      string               `json:"fr_label"`
+	Fund         string               `json:"fund"`
+	Certificates []FixtureCertificate `json:"certificates"`
+}
+
+// FixtureCertificate is one verification attempt.
+type FixtureCertificate struct {
+	Status      string    `json:"status"`
+	Method      string    `json:"method"`
+	VerifiedAt  time.Time `json:"verified_at"`
+	Similarity  *float64  `json:"similarity"`
+	Distance    *float64  `json:"distance"`
+	Location    string    `json:"location"`
+	OfficerName string    `json:"officer_name"`
+	// ReviewedBy marks a REVIEW attempt as decided; without it the attempt
+	// waits in the review queue.
+	ReviewedBy string `json:"reviewed_by"`
+}
+
+// SeedInput asks for generated demo data, or for the given fixtures.
+type SeedInput struct {
+	// Members and Participants size the generated data, 50 and 30 when both
+	// are zero; participants are linked to the first members.
+	Members      int   `json:"members"`
+	Participants int   `json:"participants"`
+	Seed         int64 `json:"seed"`
+	// Fixtures replace the generated data when set.
+	Fixtures *Fixtures `json:"fixtures"`
+}
+
+// SeedResult counts what a load created and skipped.
+type SeedResult struct {
+	MembersCreated      int `json:"members_created"`
+	MembersSkipped      int `json:"members_skipped"`
+	ParticipantsCreated int `json:"participants_created"`
+	ParticipantsSkipped int `json:"participants_skipped"`
+	Certificates        int `json:"certificates"`
+}
+
+// Seed loads input's fixtures, or demo data generated from its seed.
+func (s *SeedService) Seed(ctx context.Context, actor string, input SeedInput) (*SeedResult, error) {
+	if input.Fixtures != nil {
+		return s.Load(ctx, actor, *input.Fixtures)
+	}
+	if input.Members == 0 && input.Participants == 0 {
+		input.Members, input.Participants = defaultDemoMembers, defaultDemoParticipants
+	}
+	verr := &ValidationError{}
+	if input.Members < 0 || input.Members > maxDemoRecords {
+		verr.add("members", fmt.Sprintf("must be between 0 and %d", maxDemoRecords))
+	}
+	if input.Participants < 0 || input.Participants > maxDemoRecords {
+		verr.add("participants", fmt.Sprintf("must be between 0 and %d", maxDemoRecords))
+	}
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+	return s.Load(ctx, actor, DemoFixtures(input.Seed, input.Members, input.Participants, time.Now().UTC()))
+}
+
+// Load validates the fixtures, then creates the members and participants
+// that do not exist yet. Each participant is created with their FR label and
+// certificates in one transaction.
+func (s *SeedService) Load(ctx context.Context, actor string, fixtures Fixtures) (*SeedResult, error) {
+	if err := validateFixtures(fixtures); err != nil {
+		return nil, err
+	}
+
+	result := &SeedResult{}
+	for _, input := range fixtures.Members {
+		// Members go through MemberService for its validation.
+		_, err := s.memberInputs.Create(ctx, input)
+		switch {
+		case err == nil:
+			result.MembersCreated++
+		case errors.Is(err, ErrMemberNIKExists), errors.Is(err, ErrMemberNomorPesertaExists):
+			result.MembersSkipped++
+		default:
+			return nil, fmt.Errorf("member %s: %w", input.NIK, err)
+		}
+	}
+
+	for _, fixture := range fixtures.Participants {
+		created, err := s.loadParticipant(ctx, fixture)
+		if err != nil {
+			return nil, fmt.Errorf("participant %s: %w", fixture.NIK, err)
+		}
+		if !created {
+			result.ParticipantsSkipped++
+			continue
+		}
+		result.ParticipantsCreated++
+		result.Certificates += len(fixture.Certificates)
+	}
+
+	err := recordAudit(ctx, s.audit, actor, auditActionSeedLoad, auditEntitySeed, "", map[string]interface{}{
+		"members_created":      result.MembersCreated,
+		"participants_created": result.ParticipantsCreated,
+		"certificates":         result.Certificates,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *SeedService) loadParticipant(ctx context.Context, fixture FixtureParticipant) (bool, error) {
+	nik := strings.TrimSpace(fixture.NIK)
+	existing, err := s.participants.GetByNIK(ctx, nik)
+	if err != nil {
+		return false, err
+	}
+	if existing != nil {
+		return false, nil
+	}
+
+	now := time.Now().UTC()
+	participant := &domain.Participant{
+		ID:        uuid.NewString(),
+		NIK:       nik,
+		Name:      strings.TrimSpace(fixture.Name),
+		Status:    domain.ParticipantStatusActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if memberNIK := strings.TrimSpace(fixture.MemberNIK); memberNIK != "" {
+		member, err := s.members.GetByNIK(ctx, memberNIK)
+		if err != nil {
+			return false, err
+		}
+		if member == nil {
+			return false, fmt.Errorf("member %s: %w", memberNIK, ErrMemberNotFound)
+		}
+		participant.MemberID = &member.ID
+	}
+	if fund := strings.TrimSpace(fixture.Fund); fund != "" {
+		participant.Fund = &fund
+	}
+	label := strings.TrimSpace(fixture.FRLabel)
+	if label == "" {
+		label = "demo-" + participant.ID
+	}
+	participant.FRExternalRef = label
+	// Registration predates the first attempt, as it would for a real participant.
+	for _, certificate := range fixture.Certificates {
+		if registered := certificate.VerifiedAt.UTC().Add(-time.Hour); registered.Before(participant.CreatedAt) {
+			participant.CreatedAt = registered
+		}
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.participants.Create(ctx, participant); err != nil {
+			return err
+		}
+		if err := s.frIdentities.Create(ctx, &domain.FRIdentity{
+			Label:         label,
+			ParticipantID: participant.ID,
+			ExternalRef:   label,
+			Source:        domain.FRIdentitySourceRegistration,
+			CreatedAt:     participant.CreatedAt,
+		}); err != nil {
+			return err
+		}
+		for _, certificate := range fixture.Certificates {
+			if err := s.certificates.Create(ctx, s.seedCertificate(participant.ID, certificate)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *SeedService) seedCertificate(participantID string, fixture FixtureCertificate) *domain.LifeCertificate {
+	record := &domain.LifeCertificate{
+		ID:            uuid.NewString(),
+		ParticipantID: participantID,
+		Status:        domain.LifeCertificateStatus(strings.ToUpper(strings.TrimSpace(fixture.Status))),
+		Method:        domain.VerificationMethodAutomatic,
+		Channel:       domain.VerificationChannelMobile,
+		VerifiedAt:    fixture.VerifiedAt.UTC(),
+		Similarity:    fixture.Similarity,
+		Distance:      fixture.Distance,
+	}
+	if method := strings.ToUpper(strings.TrimSpace(fixture.Method)); method != "" {
+		record.Method = domain.VerificationMethod(method)
+	}
+	switch record.Method {
+	case domain.VerificationMethodManual:
+		record.Channel = domain.VerificationChannelManual
+	case domain.VerificationMethodProxy:
+		record.Channel = domain.VerificationChannelProxy
+	}
+	if location := strings.TrimSpace(fixture.Location); location != "" {
+		record.Location = &location
+	}
+	if record.Method == domain.VerificationMethodManual {
+		officer := strings.TrimSpace(fixture.OfficerName)
+		recorder := seedRecorder
+		record.OfficerName = &officer
+		record.RecordedBy = &recorder
+	}
+	if record.Status == domain.LifeCertificateStatusReview {
+		dueAt := record.VerifiedAt.Add(s.reviewSLA)
+		record.ReviewDueAt = &dueAt
+		if reviewer := strings.TrimSpace(fixture.ReviewedBy); reviewer != "" {
+			reviewedAt := record.VerifiedAt.Add(s.reviewSLA / 2)
+			record.ReviewedBy = &reviewer
+			record.ReviewedAt = &reviewedAt
+		}
+	}
+	return record
+}
+
+func validateFixtures(fixtures Fixtures) error {
+	verr := &ValidationError{}
+	for i, participant := range fixtures.Participants {
+		field := fmt.Sprintf("participants[%d]", i)
+		if strings.TrimSpace(participant.NIK) == "" {
+			verr.add(field+".nik", "is required")
+		}
+		if strings.TrimSpace(participant.Name) == "" {
+			verr.add(field+".name", "is required")
+		}
+		for j, certificate := range participant.Certificates {
+			field := fmt.Sprintf("%s.certificates[%d]", field, j)
+			switch domain.LifeCertificateStatus(strings.ToUpper(strings.TrimSpace(certificate.Status))) {
+			case domain.LifeCertificateStatusValid, domain.LifeCertificateStatusInvalid, domain.LifeCertificateStatusReview:
+			default:
+				verr.add(field+".status", "must be one of VALID, INVALID, REVIEW")
+			}
+			method := domain.VerificationMethod(strings.ToUpper(strings.TrimSpace(certificate.Method)))
+			switch method {
+			case "", domain.VerificationMethodAutomatic:
+			case domain.VerificationMethodManual:
+				if strings.TrimSpace(certificate.OfficerName) == "" {
+					verr.add(field+".officer_name", "is required for MANUAL")
+				}
+			default:
+				verr.add(field+".method", "must be one of AUTOMATIC, MANUAL")
+			}
+			if certificate.VerifiedAt.IsZero() {
+				verr.add(field+".verified_at", "is required")
+			}
+		}
+	}
+	return verr.errOrNil()
+}
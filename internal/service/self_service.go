@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/pii"
+	"life-certificates/internal/repository"
+)
+
+// Audit vocabulary for self-service sign-in.
+const (
+	auditActionSelfServiceCodeSent = "self_service.code_sent"
+	auditActionSelfServiceSignIn   = "self_service.sign_in"
+	selfServiceActor               = "self-service"
+	// selfServiceLocation marks self-service attempts when the app declares no location.
+	selfServiceLocation = "self-service"
+)
+
+var (
+	// ErrInvalidSelfServiceCode indicates a wrong, expired or already used code.
+	ErrInvalidSelfServiceCode = errors.New("invalid or expired code")
+	// ErrSelfServiceCodeLocked indicates too many wrong codes were tried; a new code must be requested.
+	ErrSelfServiceCodeLocked = errors.New("too many attempts, request a new code")
+)
+
+// SelfServiceOptions configures one-time codes and the tokens they are exchanged for.
+type SelfServiceOptions struct {
+	// Secret signs tokens and keys the stored code hashes.
+	Secret         string
+	CodeLength     int
+	CodeTTL        time.Duration
+	MaxAttempts    int
+	ResendInterval time.Duration
+	TokenTTL       time.Duration
+}
+
+// SelfServiceService lets pensioners verify from their own phones: a one-time
+// code sent to the participant's registered contact is exchanged for a short-lived
+// token that can only submit a verification for that participant.
+type SelfServiceService struct {
+	codes         repository.SelfServiceRepository
+	participants  repository.ParticipantRepository
+	members       repository.MemberRepository
+	audit         repository.AuditLogRepository
+	notifications *NotificationService
+	verifications *VerificationService
+	options       SelfServiceOptions
+}
+
+// NewSelfServiceService wires dependencies for self-service verification.
+func NewSelfServiceService(codes repository.SelfServiceRepository, participants repository.ParticipantRepository, members repository.MemberRepository, audit repository.AuditLogRepository, notifications *NotificationService, verifications *VerificationService, options SelfServiceOptions) *SelfServiceService {
+	return &SelfServiceService{
+		codes:         codes,
+		participants:  participants,
+		members:       members,
+		audit:         audit,
+		notifications: notifications,
+		verifications: verifications,
+		options:       options,
+	}
+}
+
+// SelfServiceCodeOutput answers a code request the same way whether or not a
+// code was sent, so the endpoint does not reveal which NIKs are registered.
+type SelfServiceCodeOutput struct {
+	Message   string `json:"message"`
+	ExpiresIn int    `json:"expires_in"`
+}
+
+// SelfServiceToken authorizes self-service verification for one participant.
+type SelfServiceToken struct {
+	Token         string    `json:"token"`
+	ParticipantID string    `json:"participant_id"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// RequestCode sends a one-time code to the participant with the NIK, on their
+// linked member's contact details or else their own. Unknown NIKs,
+// unreachable participants and requests within the resend interval get the
+// same answer without a code.
+func (s *SelfServiceService) RequestCode(ctx context.Context, nik string) (*SelfServiceCodeOutput, error) {
+	nik = strings.TrimSpace(nik)
+	if nik == "" {
+		return nil, &ValidationError{Fields: map[string]string{"nik": "is required"}}
+	}
+	out := &SelfServiceCodeOutput{
+		Message:   "if the NIK is registered, a code was sent to the participant's registered contact",
+		ExpiresIn: int(s.options.CodeTTL / time.Second),
+	}
+
+	participant, err := s.participants.GetByNIK(ctx, nik)
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return out, nil
+	}
+	var member *domain.Member
+	if participant.MemberID != nil {
+		if member, err = s.members.GetByID(ctx, *participant.MemberID); err != nil {
+			return nil, err
+		}
+	}
+	latest, err := s.codes.LatestOTP(ctx, participant.ID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if latest != nil && latest.ConsumedAt == nil && now.Sub(latest.CreatedAt) < s.options.ResendInterval {
+		return out, nil
+	}
+
+	code, err := s.newCode()
+	if err != nil {
+		return nil, err
+	}
+	channel, recipient, err := s.notifications.SendCode(ctx, participant, member, code, s.options.CodeTTL)
+	if err != nil {
+		log.Printf("[self-service] send code to participant %s: %v", participant.ID, err)
+		return out, nil
+	}
+
+	otp := &domain.SelfServiceOTP{
+		ID:            uuid.NewString(),
+		ParticipantID: participant.ID,
+		Channel:       channel,
+		Recipient:     pii.Contact(recipient),
+		ExpiresAt:     now.Add(s.options.CodeTTL),
+		CreatedAt:     now,
+	}
+	otp.CodeHash = s.codeHash(otp.ID, code)
+	if err := s.codes.CreateOTP(ctx, otp); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.audit, selfServiceActor, auditActionSelfServiceCodeSent, auditEntityParticipant, participant.ID, map[string]interface{}{
+		"channel":   otp.Channel,
+		"recipient": otp.Recipient,
+	}); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ExchangeCode trades the participant's latest code for a token.
+func (s *SelfServiceService) ExchangeCode(ctx context.Context, nik, code string) (*SelfServiceToken, error) {
+	nik, code = strings.TrimSpace(nik), strings.TrimSpace(code)
+	verr := &ValidationError{}
+	if nik == "" {
+		verr.add("nik", "is required")
+	}
+	if code == "" {
+		verr.add("code", "is required")
+	}
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	participant, err := s.participants.GetByNIK(ctx, nik)
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return nil, ErrInvalidSelfServiceCode
+	}
+	otp, err := s.codes.LatestOTP(ctx, participant.ID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if otp == nil || otp.ConsumedAt != nil || !now.Before(otp.ExpiresAt) {
+		return nil, ErrInvalidSelfServiceCode
+	}
+	counted, err := s.codes.AddOTPAttempt(ctx, otp.ID, s.options.MaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	if !counted {
+		return nil, ErrSelfServiceCodeLocked
+	}
+	if !hmac.Equal([]byte(s.codeHash(otp.ID, code)), []byte(otp.CodeHash)) {
+		return nil, ErrInvalidSelfServiceCode
+	}
+	consumed, err := s.codes.ConsumeOTP(ctx, otp.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, ErrInvalidSelfServiceCode
+	}
+
+	expiresAt := now.Add(s.options.TokenTTL)
+	if err := recordAudit(ctx, s.audit, selfServiceActor, auditActionSelfServiceSignIn, auditEntityParticipant, participant.ID, map[string]interface{}{
+		"channel":    otp.Channel,
+		"expires_at": expiresAt,
+	}); err != nil {
+		return nil, err
+	}
+	return &SelfServiceToken{
+		Token:         s.signToken(participant.ID, expiresAt),
+		ParticipantID: participant.ID,
+		ExpiresAt:     expiresAt,
+	}, nil
+}
+
+// Authenticate returns the participant ID of an unexpired token signed by this service.
+func (s *SelfServiceService) Authenticate(token string) (string, bool) {
+	encodedPayload, encodedMAC, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return "", false
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(encodedMAC)
+	if err != nil || !hmac.Equal(mac, s.mac("self-service-token:"+string(payload))) {
+		return "", false
+	}
+	participantID, rawExpiry, ok := strings.Cut(string(payload), "|")
+	if !ok {
+		return "", false
+	}
+	expiry, err := strconv.ParseInt(rawExpiry, 10, 64)
+	if err != nil || !time.Now().Before(time.Unix(expiry, 0)) {
+		return "", false
+	}
+	return participantID, true
+}
+
+// Verify submits a verification for the token's participant.
+func (s *SelfServiceService) Verify(ctx context.Context, participantID string, input VerifyInput) (*VerifyOutput, error) {
+	input.ParticipantID = participantID
+	if strings.TrimSpace(input.Location) == "" {
+		input.Location = selfServiceLocation
+	}
+	return s.verifications.Verify(ctx, input)
+}
+
+func (s *SelfServiceService) newCode() (string, error) {
+	var digits strings.Builder
+	for i := 0; i < s.options.CodeLength; i++ {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", fmt.Errorf("generate code: %w", err)
+		}
+		digits.WriteByte(byte('0' + n.Int64()))
+	}
+	return digits.String(), nil
+}
+
+// codeHash keys the code with its ID, so equal codes hash differently and a
+// leaked table cannot be brute forced without the secret.
+func (s *SelfServiceService) codeHash(id, code string) string {
+	return hex.EncodeToString(s.mac("self-service-code:" + id + ":" + code))
+}
+
+func (s *SelfServiceService) signToken(participantID string, expiresAt time.Time) string {
+	payload := participantID + "|" + strconv.FormatInt(expiresAt.Unix(), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + base64.RawURLEncoding.EncodeToString(s.mac("self-service-token:"+payload))
+}
+
+func (s *SelfServiceService) mac(message string) []byte {
+	mac := hmac.New(sha256.New, []byte(s.options.Secret))
+	mac.Write([]byte(message))
+	return mac.Sum(nil)
+}
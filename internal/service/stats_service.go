@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+)
+
+const (
+	// statsDefaultDays is the range of statistics requested without a from date.
+	statsDefaultDays = 30
+	// statsMaxBuckets caps a time series, e.g. a little over a year of days.
+	statsMaxBuckets = 400
+)
+
+// Time series intervals.
+const (
+	StatsIntervalDay   = "day"
+	StatsIntervalWeek  = "week"
+	StatsIntervalMonth = "month"
+)
+
+// StatsService aggregates verification activity for the ops dashboard.
+type StatsService struct {
+	stats    repository.StatsRepository
+	settings *VerificationSettingsService
+}
+
+// NewStatsService wires dependencies for dashboard statistics.
+func NewStatsService(stats repository.StatsRepository, settings *VerificationSettingsService) *StatsService {
+	return &StatsService{stats: stats, settings: settings}
+}
+
+// StatsInput narrows statistics to verification dates (YYYY-MM-DD, both
+// inclusive) and the participants of a province and fund.
+type StatsInput struct {
+	From     string
+	To       string
+	Province string
+	Fund     string
+	// Interval buckets a time series by day (the default), week or month.
+	Interval string
+}
+
+// VerificationCounts is the attempts of a range by status. PassRate is the
+// percentage of decided attempts that were VALID, nil without decided
+// attempts, and AverageSimilarity that of automatic attempts.
+type VerificationCounts struct {
+	Total             int64            `json:"total"`
+	ByStatus          map[string]int64 `json:"by_status"`
+	PassRate          *float64         `json:"pass_rate"`
+	AverageSimilarity *float64         `json:"average_similarity"`
+}
+
+// ReviewBacklog is the pending reviews now, whatever the range.
+type ReviewBacklog struct {
+	Pending int64 `json:"pending"`
+	Overdue int64 `json:"overdue"`
+}
+
+// CampaignStats is a campaign's completion among the filtered participants.
+type CampaignStats struct {
+	CampaignID     string  `json:"campaign_id"`
+	Name           string  `json:"name"`
+	StartsAt       string  `json:"starts_at"`
+	DueAt          string  `json:"due_at"`
+	Participants   int64   `json:"participants"`
+	Completed      int64   `json:"completed"`
+	CompletionRate float64 `json:"completion_rate"`
+	// CompletedByChannel counts the participants with a VALID certificate
+	// linked to the campaign per channel it was captured on. Certificates
+	// recorded before they were linked to campaigns are left out.
+	CompletedByChannel map[string]int64 `json:"completed_by_channel"`
+}
+
+// StatsOverview summarizes a range for the dashboard.
+type StatsOverview struct {
+	From          string             `json:"from"`
+	To            string             `json:"to"`
+	Province      string             `json:"province,omitempty"`
+	Fund          string             `json:"fund,omitempty"`
+	Verifications VerificationCounts `json:"verifications"`
+	ReviewBacklog ReviewBacklog      `json:"review_backlog"`
+	Campaigns     []CampaignStats    `json:"campaigns"`
+}
+
+// StatsBucket is the attempts of one interval, starting on Start.
+type StatsBucket struct {
+	Start string `json:"start"`
+	VerificationCounts
+}
+
+// StatsTimeseries is a range's attempts per interval, including empty ones.
+type StatsTimeseries struct {
+	From     string        `json:"from"`
+	To       string        `json:"to"`
+	Province string        `json:"province,omitempty"`
+	Fund     string        `json:"fund,omitempty"`
+	Interval string        `json:"interval"`
+	Buckets  []StatsBucket `json:"buckets"`
+}
+
+// Overview counts the range's attempts by status, its pass rate and average
+// similarity, the review backlog and the completion of overlapping campaigns,
+// overall and by channel.
+func (s *StatsService) Overview(ctx context.Context, input StatsInput) (*StatsOverview, error) {
+	filter, from, to, err := statsFilter(input)
+	if err != nil {
+		return nil, err
+	}
+	verifications, err := s.stats.Verifications(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	pending, overdue, err := s.stats.ReviewBacklog(ctx, filter, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	campaigns, err := s.stats.Campaigns(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	campaignIDs := make([]string, len(campaigns))
+	for i, campaign := range campaigns {
+		campaignIDs[i] = campaign.CampaignID
+	}
+	channels, err := s.stats.CampaignChannels(ctx, filter, campaignIDs)
+	if err != nil {
+		return nil, err
+	}
+	byChannel := make(map[string]map[string]int64, len(campaigns))
+	for _, count := range channels {
+		if byChannel[count.CampaignID] == nil {
+			byChannel[count.CampaignID] = map[string]int64{}
+		}
+		byChannel[count.CampaignID][string(count.Channel)] = count.Completed
+	}
+
+	out := &StatsOverview{
+		From:          from.Format("2006-01-02"),
+		To:            to.Format("2006-01-02"),
+		Province:      filter.Province,
+		Fund:          filter.Fund,
+		Verifications: verificationCounts(*verifications),
+		ReviewBacklog: ReviewBacklog{Pending: pending, Overdue: overdue},
+		Campaigns:     make([]CampaignStats, 0, len(campaigns)),
+	}
+	for _, campaign := range campaigns {
+		stats := CampaignStats{
+			CampaignID:         campaign.CampaignID,
+			Name:               campaign.Name,
+			StartsAt:           campaign.StartsAt.Format("2006-01-02"),
+			DueAt:              campaign.DueAt.Format("2006-01-02"),
+			Participants:       campaign.Participants,
+			Completed:          campaign.Completed,
+			CompletedByChannel: byChannel[campaign.CampaignID],
+		}
+		if stats.CompletedByChannel == nil {
+			stats.CompletedByChannel = map[string]int64{}
+		}
+		if campaign.Participants > 0 {
+			stats.CompletionRate = percentage(campaign.Completed, campaign.Participants)
+		}
+		out.Campaigns = append(out.Campaigns, stats)
+	}
+	return out, nil
+}
+
+// Timeseries counts the range's attempts per day, week (starting Monday) or month.
+func (s *StatsService) Timeseries(ctx context.Context, input StatsInput) (*StatsTimeseries, error) {
+	interval := strings.ToLower(strings.TrimSpace(input.Interval))
+	if interval == "" {
+		interval = StatsIntervalDay
+	}
+	switch interval {
+	case StatsIntervalDay, StatsIntervalWeek, StatsIntervalMonth:
+	default:
+		return nil, &ValidationError{Fields: map[string]string{"interval": "must be one of day, week, month"}}
+	}
+	filter, from, to, err := statsFilter(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var starts []time.Time
+	for start := truncateInterval(from, interval); !start.After(to); start = nextInterval(start, interval) {
+		if len(starts) == statsMaxBuckets {
+			return nil, &ValidationError{Fields: map[string]string{
+				"interval": fmt.Sprintf("range has more than %d %ss, use a longer interval", statsMaxBuckets, interval),
+			}}
+		}
+		starts = append(starts, start)
+	}
+	rows, err := s.stats.VerificationSeries(ctx, filter, interval)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]repository.VerificationStats, len(rows))
+	for _, row := range rows {
+		counts[row.Start.Format("2006-01-02")] = row.VerificationStats
+	}
+
+	out := &StatsTimeseries{
+		From:     from.Format("2006-01-02"),
+		To:       to.Format("2006-01-02"),
+		Province: filter.Province,
+		Fund:     filter.Fund,
+		Interval: interval,
+		Buckets:  make([]StatsBucket, 0, len(starts)),
+	}
+	for _, start := range starts {
+		day := start.Format("2006-01-02")
+		out.Buckets = append(out.Buckets, StatsBucket{Start: day, VerificationCounts: verificationCounts(counts[day])})
+	}
+	return out, nil
+}
+
+// statsFilter resolves the input's range, defaulting to the last 30 days
+// up to today, and returns its first and last day.
+func statsFilter(input StatsInput) (repository.StatsFilter, time.Time, time.Time, error) {
+	filter := repository.StatsFilter{
+		Province: strings.TrimSpace(input.Province),
+		Fund:     strings.TrimSpace(input.Fund),
+	}
+	verr := &ValidationError{}
+	from, until := parseDateRange(verr, input.From, input.To)
+	if err := verr.errOrNil(); err != nil {
+		return filter, time.Time{}, time.Time{}, err
+	}
+	if until == nil {
+		tomorrow := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+		until = &tomorrow
+	}
+	to := until.AddDate(0, 0, -1)
+	if from == nil {
+		start := to.AddDate(0, 0, 1-statsDefaultDays)
+		from = &start
+	}
+	if from.After(to) {
+		return filter, time.Time{}, time.Time{}, &ValidationError{Fields: map[string]string{"from": "must not be after to"}}
+	}
+	filter.From, filter.To = from, until
+	return filter, *from, to, nil
+}
+
+func verificationCounts(stats repository.VerificationStats) VerificationCounts {
+	counts := VerificationCounts{
+		Total: stats.Valid + stats.Invalid + stats.Review,
+		ByStatus: map[string]int64{
+			string(domain.LifeCertificateStatusValid):   stats.Valid,
+			string(domain.LifeCertificateStatusInvalid): stats.Invalid,
+			string(domain.LifeCertificateStatusReview):  stats.Review,
+		},
+	}
+	if decided := stats.Valid + stats.Invalid; decided > 0 {
+		rate := percentage(stats.Valid, decided)
+		counts.PassRate = &rate
+	}
+	if stats.AverageSimilarity != nil {
+		average := math.Round(*stats.AverageSimilarity*100) / 100
+		counts.AverageSimilarity = &average
+	}
+	return counts
+}
+
+// percentage is part of whole in percent, rounded to two decimals.
+func percentage(part, whole int64) float64 {
+	return math.Round(float64(part)*10000/float64(whole)) / 100
+}
+
+func truncateInterval(day time.Time, interval string) time.Time {
+	switch interval {
+	case StatsIntervalWeek:
+		// ISO weeks start on Monday, as date_trunc's do.
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case StatsIntervalMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+func nextInterval(start time.Time, interval string) time.Time {
+	switch interval {
+	case StatsIntervalWeek:
+		return start.AddDate(0, 0, 7)
+	case StatsIntervalMonth:
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
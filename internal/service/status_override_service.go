@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+)
+
+// Audit vocabulary for status overrides.
+const (
+	auditActionOverridePropose = "override.propose"
+	auditActionOverrideApprove = "override.approve"
+	auditActionOverrideReject  = "override.reject"
+)
+
+var (
+	// ErrOverrideNotFound indicates the requested override does not exist.
+	ErrOverrideNotFound = errors.New("status override not found")
+	// ErrOverridePending signals the certificate already has an override awaiting approval.
+	ErrOverridePending = errors.New("certificate already has a pending status override")
+	// ErrOverrideNotPending signals the override was already decided.
+	ErrOverrideNotPending = errors.New("status override is not pending")
+	// ErrOverrideSelfApproval enforces the four-eyes principle.
+	ErrOverrideSelfApproval = errors.New("status override must be decided by a different admin than the proposer")
+	// ErrOverrideStale signals the certificate status changed after the proposal.
+	ErrOverrideStale = errors.New("certificate status changed since the override was proposed")
+)
+
+// StatusOverrideService implements dual-control corrections of certificate status.
+type StatusOverrideService struct {
+	overrides    repository.StatusOverrideRepository
+	certificates repository.LifeCertificateRepository
+	audit        repository.AuditLogRepository
+	tx           repository.Transactor
+}
+
+// NewStatusOverrideService wires dependencies for status overrides.
+func NewStatusOverrideService(overrides repository.StatusOverrideRepository, certificates repository.LifeCertificateRepository, audit repository.AuditLogRepository, tx repository.Transactor) *StatusOverrideService {
+	return &StatusOverrideService{overrides: overrides, certificates: certificates, audit: audit, tx: tx}
+}
+
+// ProposeOverrideInput describes a proposed status change.
+type ProposeOverrideInput struct {
+	Status        string `json:"status"`
+	Justification string `json:"justification"`
+}
+
+// DecideOverrideInput carries the second admin's notes.
+type DecideOverrideInput struct {
+	Notes string `json:"notes"`
+}
+
+// OverrideListOutput is a page of status overrides.
+type OverrideListOutput struct {
+	Items    []domain.StatusOverride `json:"items"`
+	Page     int                     `json:"page"`
+	PageSize int                     `json:"page_size"`
+	Total    int64                   `json:"total"`
+}
+
+// Propose records a pending status change; it has no effect until another admin approves it.
+func (s *StatusOverrideService) Propose(ctx context.Context, actor, certificateID string, input ProposeOverrideInput) (*domain.StatusOverride, error) {
+	target, err := parseLifeCertificateStatus(input.Status)
+	if err != nil {
+		return nil, err
+	}
+	justification := strings.TrimSpace(input.Justification)
+	if justification == "" {
+		return nil, fmt.Errorf("justification is required")
+	}
+
+	record, err := s.certificates.GetByID(ctx, strings.TrimSpace(certificateID))
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, ErrCertificateNotFound
+	}
+	if record.Status == target {
+		return nil, fmt.Errorf("certificate is already %s", target)
+	}
+
+	pending, err := s.overrides.GetPendingByCertificate(ctx, record.ID)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil {
+		return nil, ErrOverridePending
+	}
+
+	override := &domain.StatusOverride{
+		ID:            uuid.NewString(),
+		CertificateID: record.ID,
+		FromStatus:    record.Status,
+		ToStatus:      target,
+		Justification: justification,
+		State:         domain.StatusOverridePending,
+		ProposedBy:    actor,
+		ProposedAt:    time.Now().UTC(),
+	}
+	if err := s.overrides.Create(ctx, override); err != nil {
+		return nil, err
+	}
+
+	if err := recordAudit(ctx, s.audit, actor, auditActionOverridePropose, auditEntityLifeCertificate, record.ID, map[string]interface{}{
+		"override_id":   override.ID,
+		"from_status":   override.FromStatus,
+		"to_status":     override.ToStatus,
+		"justification": justification,
+	}); err != nil {
+		return nil, err
+	}
+	return override, nil
+}
+
+// Approve applies a pending override on behalf of a second admin.
+func (s *StatusOverrideService) Approve(ctx context.Context, actor, overrideID string, input DecideOverrideInput) (*domain.StatusOverride, error) {
+	override, err := s.decidable(ctx, actor, overrideID)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := s.certificates.GetByID(ctx, override.CertificateID)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, ErrCertificateNotFound
+	}
+	if record.Status != override.FromStatus {
+		return nil, ErrOverrideStale
+	}
+
+	stampOverrideDecision(override, actor, input)
+	record.Status = override.ToStatus
+	if err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		ok, err := s.overrides.Approve(ctx, override)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrOverrideStale
+		}
+		if ok, err = s.certificates.OverrideStatus(ctx, record, override.FromStatus); err != nil {
+			return err
+		}
+		if !ok {
+			return ErrOverrideStale
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	override.State = domain.StatusOverrideApproved
+
+	if err := recordAudit(ctx, s.audit, actor, auditActionOverrideApprove, auditEntityLifeCertificate, override.CertificateID, map[string]interface{}{
+		"override_id": override.ID,
+		"proposed_by": override.ProposedBy,
+		"from_status": override.FromStatus,
+		"to_status":   override.ToStatus,
+		"notes":       override.DecisionNotes,
+	}); err != nil {
+		return nil, err
+	}
+	return override, nil
+}
+
+// Reject closes a pending override without changing the certificate.
+func (s *StatusOverrideService) Reject(ctx context.Context, actor, overrideID string, input DecideOverrideInput) (*domain.StatusOverride, error) {
+	if strings.TrimSpace(input.Notes) == "" {
+		return nil, fmt.Errorf("notes are required when rejecting")
+	}
+	override, err := s.decidable(ctx, actor, overrideID)
+	if err != nil {
+		return nil, err
+	}
+
+	stampOverrideDecision(override, actor, input)
+	ok, err := s.overrides.Reject(ctx, override)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrOverrideNotPending
+	}
+	override.State = domain.StatusOverrideRejected
+
+	if err := recordAudit(ctx, s.audit, actor, auditActionOverrideReject, auditEntityLifeCertificate, override.CertificateID, map[string]interface{}{
+		"override_id": override.ID,
+		"proposed_by": override.ProposedBy,
+		"notes":       override.DecisionNotes,
+	}); err != nil {
+		return nil, err
+	}
+	return override, nil
+}
+
+// List returns overrides filtered by state (PENDING, APPROVED, REJECTED or empty for all).
+func (s *StatusOverrideService) List(ctx context.Context, state string, pageNum, pageSize int) (*OverrideListOutput, error) {
+	filter := domain.StatusOverrideState(strings.ToUpper(strings.TrimSpace(state)))
+	switch filter {
+	case "", domain.StatusOverridePending, domain.StatusOverrideApproved, domain.StatusOverrideRejected:
+	default:
+		return nil, fmt.Errorf("state must be one of PENDING, APPROVED, REJECTED")
+	}
+
+	page := normalizePagination(pageNum, pageSize)
+	items, total, err := s.overrides.List(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+	return &OverrideListOutput{Items: items, Page: page.Page, PageSize: page.PageSize, Total: total}, nil
+}
+
+// ListByCertificate returns every override proposed for a certificate, newest first.
+func (s *StatusOverrideService) ListByCertificate(ctx context.Context, certificateID string) ([]domain.StatusOverride, error) {
+	record, err := s.certificates.GetByID(ctx, strings.TrimSpace(certificateID))
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, ErrCertificateNotFound
+	}
+	return s.overrides.ListByCertificate(ctx, record.ID)
+}
+
+func (s *StatusOverrideService) decidable(ctx context.Context, actor, overrideID string) (*domain.StatusOverride, error) {
+	override, err := s.overrides.GetByID(ctx, strings.TrimSpace(overrideID))
+	if err != nil {
+		return nil, err
+	}
+	if override == nil {
+		return nil, ErrOverrideNotFound
+	}
+	if override.State != domain.StatusOverridePending {
+		return nil, ErrOverrideNotPending
+	}
+	if override.ProposedBy == actor {
+		return nil, ErrOverrideSelfApproval
+	}
+	return override, nil
+}
+
+func stampOverrideDecision(override *domain.StatusOverride, actor string, input DecideOverrideInput) {
+	now := time.Now().UTC()
+	override.DecidedBy = &actor
+	override.DecidedAt = &now
+	if notes := strings.TrimSpace(input.Notes); notes != "" {
+		override.DecisionNotes = &notes
+	}
+}
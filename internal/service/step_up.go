@@ -0,0 +1,83 @@
+package service
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+)
+
+// Step-up challenges: another selfie performing a liveness action, or one
+// taken from a second angle with the head turned.
+const (
+	StepUpChallengeLiveness    = "liveness"
+	StepUpChallengeSecondAngle = "second_angle"
+)
+
+// VerificationStatusStepUpRequired is answered instead of a verification
+// status when an attempt needs a step-up challenge; nothing is recorded.
+const VerificationStatusStepUpRequired = "STEP_UP_REQUIRED"
+
+// Gray zones an attempt can fall in.
+const (
+	stepUpReasonRiskScore  = "risk_score"
+	stepUpReasonSimilarity = "similarity"
+	stepUpReasonDistance   = "distance"
+)
+
+// StepUpCheck asks for a step-up challenge instead of deciding attempts that
+// fall in a gray zone: a risk score short of review, or a similarity or
+// distance close to the profile's threshold.
+type StepUpCheck struct {
+	Enabled bool
+	// RiskScore is the risk score from which attempts below the fraud review score step up; zero ignores the score.
+	RiskScore int
+	// SimilarityMargin and DistanceMargin are how close to the profile's
+	// thresholds, on either side, a match steps up; zero ignores it.
+	SimilarityMargin float64
+	DistanceMargin   float64
+	// Challenge is the step-up challenge, liveness or second_angle.
+	Challenge string
+}
+
+// StepUp is the first step of an attempt that needs a step-up challenge.
+// The session opened for the challenge keeps it, and the certificate of the
+// second step records it.
+type StepUp struct {
+	Reasons     []string  `json:"reasons"`
+	Challenge   string    `json:"challenge"`
+	Similarity  float64   `json:"similarity"`
+	Distance    *float64  `json:"distance,omitempty"`
+	RiskScore   *int      `json:"risk_score,omitempty"`
+	SelfieHash  string    `json:"selfie_hash"`
+	SubmittedAt time.Time `json:"submitted_at"`
+	// Decision is the trace of the rules the first step would have been decided by.
+	Decision json.RawMessage `json:"decision"`
+}
+
+// reasons lists the gray zones a matched attempt fell in.
+func (c StepUpCheck) reasons(policy verificationPolicy, similarity float64, distance *float64, risk *fraudAssessment) []string {
+	if !c.Enabled {
+		return nil
+	}
+	var reasons []string
+	if risk != nil && c.RiskScore > 0 && risk.Score >= c.RiskScore {
+		reasons = append(reasons, stepUpReasonRiskScore)
+	}
+	if c.SimilarityMargin > 0 && math.Abs(similarity-policy.SimilarityThreshold) <= c.SimilarityMargin {
+		reasons = append(reasons, stepUpReasonSimilarity)
+	}
+	if distance != nil && c.DistanceMargin > 0 && math.Abs(*distance-policy.DistanceThreshold) <= c.DistanceMargin {
+		reasons = append(reasons, stepUpReasonDistance)
+	}
+	return reasons
+}
+
+// trace encodes the first step for the certificate of the second.
+func (s *StepUp) trace() *string {
+	if s == nil {
+		return nil
+	}
+	encoded, _ := json.Marshal(s)
+	trace := string(encoded)
+	return &trace
+}
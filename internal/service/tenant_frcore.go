@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/frcore"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/tenant"
+)
+
+// TenantFRClient sends each FR Core call to the instance of the tenant of
+// its context: tenants with FR Core settings of their own get a client built
+// from them, the others share the configured one.
+type TenantFRClient struct {
+	fallback frcore.Client
+	tenants  repository.TenantRepository
+	build    func(*domain.Tenant) (frcore.Client, error)
+
+	mu sync.Mutex
+	// clients is keyed by tenant ID and rebuilt when the tenant changes.
+	clients map[string]tenantFRClient
+}
+
+type tenantFRClient struct {
+	client    frcore.Client
+	updatedAt time.Time
+}
+
+// NewTenantFRClient routes calls between the shared client and the ones
+// build makes for tenants with FR Core overrides.
+func NewTenantFRClient(fallback frcore.Client, tenants repository.TenantRepository, build func(*domain.Tenant) (frcore.Client, error)) *TenantFRClient {
+	return &TenantFRClient{
+		fallback: fallback,
+		tenants:  tenants,
+		build:    build,
+		clients:  make(map[string]tenantFRClient),
+	}
+}
+
+// UploadFace implements frcore.Client.
+func (c *TenantFRClient) UploadFace(ctx context.Context, req frcore.UploadRequest) (*frcore.UploadResponse, error) {
+	client, err := c.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.UploadFace(ctx, req)
+}
+
+// Recognize implements frcore.Client.
+func (c *TenantFRClient) Recognize(ctx context.Context, req frcore.RecognizeRequest) (*frcore.RecognizeResponse, error) {
+	client, err := c.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.Recognize(ctx, req)
+}
+
+// AssessQuality implements frcore.Client.
+func (c *TenantFRClient) AssessQuality(ctx context.Context, req frcore.QualityRequest) (*frcore.QualityResponse, error) {
+	client, err := c.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.AssessQuality(ctx, req)
+}
+
+// Compare implements frcore.Client.
+func (c *TenantFRClient) Compare(ctx context.Context, req frcore.CompareRequest) (*frcore.CompareResponse, error) {
+	client, err := c.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.Compare(ctx, req)
+}
+
+// ListFaces implements frcore.Client.
+func (c *TenantFRClient) ListFaces(ctx context.Context) ([]frcore.Face, error) {
+	client, err := c.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.ListFaces(ctx)
+}
+
+// DeleteFace implements frcore.Client.
+func (c *TenantFRClient) DeleteFace(ctx context.Context, label string) error {
+	client, err := c.client(ctx)
+	if err != nil {
+		return err
+	}
+	return client.DeleteFace(ctx, label)
+}
+
+// Ping checks the shared instance, the one the health check reports on.
+func (c *TenantFRClient) Ping(ctx context.Context) error {
+	return c.fallback.Ping(ctx)
+}
+
+func (c *TenantFRClient) client(ctx context.Context) (frcore.Client, error) {
+	if tenant.IsSystem(ctx) {
+		return c.fallback, nil
+	}
+	t, err := c.tenants.Get(ctx, tenant.ID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if t == nil || !t.HasFRCoreOverrides() {
+		return c.fallback, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.clients[t.ID]; ok && cached.updatedAt.Equal(t.UpdatedAt) {
+		return cached.client, nil
+	}
+	client, err := c.build(t)
+	if err != nil {
+		return nil, fmt.Errorf("build fr client for tenant %s: %w", t.ID, err)
+	}
+	c.clients[t.ID] = tenantFRClient{client: client, updatedAt: t.UpdatedAt}
+	return client, nil
+}
@@ -0,0 +1,453 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"life-certificates/internal/document"
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/storage"
+	"life-certificates/internal/tenant"
+)
+
+// Audit vocabulary for tenant management.
+const (
+	auditEntityTenant       = "tenant"
+	auditActionTenantCreate = "tenant.create"
+	auditActionTenantUpdate = "tenant.update"
+	auditActionTenantBrand  = "tenant.branding_update"
+	auditActionTenantLogo   = "tenant.logo_update"
+	auditActionTenantUnlogo = "tenant.logo_delete"
+)
+
+// maxTenantLogoBytes bounds a logo, which is embedded in every PDF certificate.
+const maxTenantLogoBytes = 512 << 10
+
+var (
+	// ErrTenantNotFound indicates the requested tenant does not exist.
+	ErrTenantNotFound = errors.New("tenant not found")
+	// ErrTenantExists indicates a tenant with the ID already exists.
+	ErrTenantExists = errors.New("tenant already exists")
+	// ErrTenantDisabled indicates the tenant exists but is disabled.
+	ErrTenantDisabled = errors.New("tenant is disabled")
+	// ErrTenantLogoNotFound indicates the tenant has no logo.
+	ErrTenantLogoNotFound = errors.New("tenant has no logo")
+)
+
+var (
+	// tenantIDPattern keeps tenant IDs usable as a DNS label.
+	tenantIDPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,34}[a-z0-9])?$`)
+	// smsSenderPattern accepts a phone number or an alphanumeric sender ID.
+	smsSenderPattern = regexp.MustCompile(`^(\+?[0-9]{3,15}|[A-Za-z0-9 ]{1,11})$`)
+	// whatsAppPhoneNumberIDPattern matches the numeric IDs of the WhatsApp Cloud API.
+	whatsAppPhoneNumberIDPattern = regexp.MustCompile(`^[0-9]{1,50}$`)
+)
+
+// TenantService manages the pension funds served by the deployment and
+// their branding.
+type TenantService struct {
+	tenants repository.TenantRepository
+	audit   repository.AuditLogRepository
+	blobs   storage.BlobStore
+}
+
+// NewTenantService wires dependencies for tenant management.
+func NewTenantService(tenants repository.TenantRepository, audit repository.AuditLogRepository, blobs storage.BlobStore) *TenantService {
+	return &TenantService{tenants: tenants, audit: audit, blobs: blobs}
+}
+
+// UpdateTenantInput changes a tenant; nil fields are left untouched. A zero
+// threshold removes the tenant's own, falling back to the verification settings.
+type UpdateTenantInput struct {
+	Name                  *string  `json:"name"`
+	FRCoreBaseURL         *string  `json:"frcore_base_url"`
+	FRCoreUploadAPIKey    *string  `json:"frcore_upload_api_key"`
+	FRCoreRecognizeAPIKey *string  `json:"frcore_recognize_api_key"`
+	FRCoreTenantID        *string  `json:"frcore_tenant_id"`
+	DistanceThreshold     *float64 `json:"distance_threshold"`
+	SimilarityThreshold   *float64 `json:"similarity_threshold"`
+	Disabled              *bool    `json:"disabled"`
+}
+
+// TenantBrandingInput changes a tenant's branding; nil fields are left
+// untouched and empty ones cleared.
+type TenantBrandingInput struct {
+	DisplayName           *string `json:"display_name"`
+	FooterText            *string `json:"footer_text"`
+	EmailFrom             *string `json:"email_from"`
+	SMSFrom               *string `json:"sms_from"`
+	WhatsAppPhoneNumberID *string `json:"whatsapp_phone_number_id"`
+}
+
+// CreateTenantInput registers a tenant under a new ID.
+type CreateTenantInput struct {
+	ID string `json:"id"`
+	UpdateTenantInput
+}
+
+// Resolve returns the enabled tenant a request names.
+func (s *TenantService) Resolve(ctx context.Context, id string) (*domain.Tenant, error) {
+	t, err := s.tenants.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrTenantNotFound
+	}
+	if t.DisabledAt != nil {
+		return nil, ErrTenantDisabled
+	}
+	return t, nil
+}
+
+// Current returns the tenant of ctx, nil when it has been removed meanwhile.
+func (s *TenantService) Current(ctx context.Context) (*domain.Tenant, error) {
+	return s.tenants.Get(ctx, tenant.ID(ctx))
+}
+
+// List returns every tenant, disabled ones included.
+func (s *TenantService) List(ctx context.Context) ([]domain.Tenant, error) {
+	return s.tenants.List(ctx)
+}
+
+// Get returns a tenant.
+func (s *TenantService) Get(ctx context.Context, id string) (*domain.Tenant, error) {
+	t, err := s.tenants.Get(ctx, strings.TrimSpace(id))
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrTenantNotFound
+	}
+	return t, nil
+}
+
+// Create registers a tenant. Its data starts empty.
+func (s *TenantService) Create(ctx context.Context, actor string, input CreateTenantInput) (*domain.Tenant, error) {
+	verr := &ValidationError{}
+	id := strings.ToLower(strings.TrimSpace(input.ID))
+	if !tenantIDPattern.MatchString(id) {
+		verr.add("id", "must be 1 to 36 lowercase letters, digits or hyphens, not starting or ending with a hyphen")
+	}
+	if input.Name == nil || strings.TrimSpace(*input.Name) == "" {
+		verr.add("name", "is required")
+	}
+	now := time.Now().UTC()
+	t := &domain.Tenant{ID: id, CreatedAt: now, UpdatedAt: now}
+	applyTenantInput(t, input.UpdateTenantInput, now, verr)
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.tenants.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrTenantExists
+	}
+	if err := s.tenants.Create(ctx, t); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.audit, actor, auditActionTenantCreate, auditEntityTenant, t.ID, tenantAuditDetails(t, input.UpdateTenantInput)); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Update changes a tenant. The default tenant cannot be disabled.
+func (s *TenantService) Update(ctx context.Context, actor, id string, input UpdateTenantInput) (*domain.Tenant, error) {
+	t, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	verr := &ValidationError{}
+	if t.ID == tenant.DefaultID && input.Disabled != nil && *input.Disabled {
+		verr.add("disabled", "the default tenant cannot be disabled")
+	}
+	now := time.Now().UTC()
+	applyTenantInput(t, input, now, verr)
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	t.UpdatedAt = now
+	if err := s.tenants.Update(ctx, t); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.audit, actor, auditActionTenantUpdate, auditEntityTenant, t.ID, tenantAuditDetails(t, input)); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// UpdateBranding changes how a tenant presents itself in notifications and
+// PDF certificates. Certificates already generated keep their branding.
+func (s *TenantService) UpdateBranding(ctx context.Context, actor, id string, input TenantBrandingInput) (*domain.Tenant, error) {
+	t, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	verr := &ValidationError{}
+	b := &t.Branding
+	if input.DisplayName != nil {
+		b.DisplayName = strings.TrimSpace(*input.DisplayName)
+		if len(b.DisplayName) > 150 {
+			verr.add("display_name", "must be at most 150 characters")
+		}
+	}
+	if input.FooterText != nil {
+		b.FooterText = strings.TrimSpace(*input.FooterText)
+		if len(b.FooterText) > 500 {
+			verr.add("footer_text", "must be at most 500 characters")
+		}
+	}
+	if input.EmailFrom != nil {
+		b.EmailFrom = strings.TrimSpace(*input.EmailFrom)
+		if b.EmailFrom != "" {
+			if _, err := mail.ParseAddress(b.EmailFrom); err != nil || len(b.EmailFrom) > 255 {
+				verr.add("email_from", "must be an email address, optionally with a display name")
+			}
+		}
+	}
+	if input.SMSFrom != nil {
+		b.SMSFrom = strings.TrimSpace(*input.SMSFrom)
+		if b.SMSFrom != "" && !smsSenderPattern.MatchString(b.SMSFrom) {
+			verr.add("sms_from", "must be a phone number or an alphanumeric sender ID of at most 11 characters")
+		}
+	}
+	if input.WhatsAppPhoneNumberID != nil {
+		b.WhatsAppPhoneNumberID = strings.TrimSpace(*input.WhatsAppPhoneNumberID)
+		if b.WhatsAppPhoneNumberID != "" && !whatsAppPhoneNumberIDPattern.MatchString(b.WhatsAppPhoneNumberID) {
+			verr.add("whatsapp_phone_number_id", "must be the numeric phone number ID")
+		}
+	}
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	t.UpdatedAt = time.Now().UTC()
+	if err := s.tenants.Update(ctx, t); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.audit, actor, auditActionTenantBrand, auditEntityTenant, t.ID, map[string]interface{}{
+		"display_name":             b.DisplayName,
+		"footer_text":              b.FooterText,
+		"email_from":               b.EmailFrom,
+		"sms_from":                 b.SMSFrom,
+		"whatsapp_phone_number_id": b.WhatsAppPhoneNumberID,
+	}); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// SetLogo stores a PNG or JPEG as the tenant's logo, replacing any earlier one.
+func (s *TenantService) SetLogo(ctx context.Context, actor, id string, logo []byte) (*domain.Tenant, error) {
+	t, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	verr := &ValidationError{}
+	switch {
+	case len(logo) == 0:
+		verr.add("logo", "file is empty")
+	case len(logo) > maxTenantLogoBytes:
+		verr.add("logo", fmt.Sprintf("file exceeds %d bytes", maxTenantLogoBytes))
+	default:
+		if err := document.CheckLogo(logo); err != nil {
+			verr.add("logo", err.Error())
+		}
+	}
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	key := tenantLogoKey(t.ID)
+	if err := s.blobs.Put(ctx, key, logo); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	t.Branding.LogoKey, t.Branding.LogoUpdatedAt, t.UpdatedAt = key, &now, now
+	if err := s.tenants.Update(ctx, t); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.audit, actor, auditActionTenantLogo, auditEntityTenant, t.ID, map[string]interface{}{
+		"size_bytes":   len(logo),
+		"content_type": http.DetectContentType(logo),
+	}); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// DeleteLogo removes the tenant's logo.
+func (s *TenantService) DeleteLogo(ctx context.Context, actor, id string) (*domain.Tenant, error) {
+	t, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if t.Branding.LogoKey == "" {
+		return nil, ErrTenantLogoNotFound
+	}
+	if err := s.blobs.Delete(ctx, t.Branding.LogoKey); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, err
+	}
+	t.Branding.LogoKey, t.Branding.LogoUpdatedAt, t.UpdatedAt = "", nil, time.Now().UTC()
+	if err := s.tenants.Update(ctx, t); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.audit, actor, auditActionTenantUnlogo, auditEntityTenant, t.ID, nil); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Logo returns the tenant's logo and its content type.
+func (s *TenantService) Logo(ctx context.Context, id string) ([]byte, string, error) {
+	t, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	logo, err := tenantLogo(ctx, s.blobs, t.Branding)
+	if err != nil {
+		return nil, "", err
+	}
+	if logo == nil {
+		return nil, "", ErrTenantLogoNotFound
+	}
+	return logo, http.DetectContentType(logo), nil
+}
+
+// currentBranding returns the branding of the tenant of ctx, empty when the
+// tenant has been removed meanwhile.
+func currentBranding(ctx context.Context, tenants repository.TenantRepository) (domain.TenantBranding, error) {
+	t, err := tenants.Get(ctx, tenant.ID(ctx))
+	if err != nil || t == nil {
+		return domain.TenantBranding{}, err
+	}
+	return t.Branding, nil
+}
+
+// tenantLogo reads the logo of a branding, nil without one.
+func tenantLogo(ctx context.Context, blobs storage.BlobStore, branding domain.TenantBranding) ([]byte, error) {
+	if branding.LogoKey == "" {
+		return nil, nil
+	}
+	content, err := blobs.Get(ctx, branding.LogoKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer content.Close()
+	logo, err := io.ReadAll(content)
+	if err != nil {
+		return nil, fmt.Errorf("read tenant logo: %w", err)
+	}
+	return logo, nil
+}
+
+func tenantLogoKey(tenantID string) string {
+	return fmt.Sprintf("tenants/%s/logo", tenantID)
+}
+
+// applyTenantInput validates and applies the set fields of input.
+func applyTenantInput(t *domain.Tenant, input UpdateTenantInput, now time.Time, verr *ValidationError) {
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		switch {
+		case name == "":
+			verr.add("name", "is required")
+		case len(name) > 150:
+			verr.add("name", "must be at most 150 characters")
+		}
+		t.Name = name
+	}
+	if input.FRCoreBaseURL != nil {
+		raw := strings.TrimSpace(*input.FRCoreBaseURL)
+		if raw != "" {
+			parsed, err := url.Parse(raw)
+			if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+				verr.add("frcore_base_url", "must be an http or https URL")
+			}
+		}
+		t.FRCoreBaseURL = raw
+	}
+	if input.FRCoreUploadAPIKey != nil {
+		t.FRCoreUploadAPIKey = strings.TrimSpace(*input.FRCoreUploadAPIKey)
+	}
+	if input.FRCoreRecognizeAPIKey != nil {
+		t.FRCoreRecognizeAPIKey = strings.TrimSpace(*input.FRCoreRecognizeAPIKey)
+	}
+	if input.FRCoreTenantID != nil {
+		t.FRCoreTenantID = strings.TrimSpace(*input.FRCoreTenantID)
+	}
+	if input.DistanceThreshold != nil {
+		switch value := *input.DistanceThreshold; {
+		case value < 0:
+			verr.add("distance_threshold", "must be positive, or 0 to use the verification settings")
+		case value == 0:
+			t.DistanceThreshold = nil
+		default:
+			t.DistanceThreshold = &value
+		}
+	}
+	if input.SimilarityThreshold != nil {
+		switch value := *input.SimilarityThreshold; {
+		case value < 0 || value > 100:
+			verr.add("similarity_threshold", "must be between 0 and 100, 0 to use the verification settings")
+		case value == 0:
+			t.SimilarityThreshold = nil
+		default:
+			t.SimilarityThreshold = &value
+		}
+	}
+	if input.Disabled != nil {
+		switch {
+		case *input.Disabled && t.DisabledAt == nil:
+			t.DisabledAt = &now
+		case !*input.Disabled:
+			t.DisabledAt = nil
+		}
+	}
+}
+
+// tenantAuditDetails records the tenant without its FR Core keys, noting
+// which of them were changed.
+func tenantAuditDetails(t *domain.Tenant, input UpdateTenantInput) map[string]interface{} {
+	return map[string]interface{}{
+		"name":                         t.Name,
+		"frcore_base_url":              t.FRCoreBaseURL,
+		"frcore_tenant_id":             t.FRCoreTenantID,
+		"frcore_upload_key_changed":    input.FRCoreUploadAPIKey != nil,
+		"frcore_recognize_key_changed": input.FRCoreRecognizeAPIKey != nil,
+		"distance_threshold":           t.DistanceThreshold,
+		"similarity_threshold":         t.SimilarityThreshold,
+		"disabled":                     t.DisabledAt != nil,
+	}
+}
+
+// tenantThresholds overlays the thresholds of a tenant on the settings.
+func tenantThresholds(settings VerificationSettings, t *domain.Tenant) VerificationSettings {
+	if t == nil {
+		return settings
+	}
+	if t.DistanceThreshold != nil {
+		settings.DistanceThreshold = *t.DistanceThreshold
+	}
+	if t.SimilarityThreshold != nil {
+		settings.SimilarityThreshold = *t.SimilarityThreshold
+	}
+	return settings
+}
@@ -0,0 +1,448 @@
+package service
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"sort"
+	"time"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/storage"
+	"life-certificates/internal/tenant"
+)
+
+// Audit vocabulary for moving tenants between deployments.
+const (
+	auditActionTenantExport = "tenant.export"
+	auditActionTenantImport = "tenant.import"
+)
+
+const (
+	// tenantArchiveVersion is the layout of the archives Export writes.
+	tenantArchiveVersion  = 1
+	tenantArchiveManifest = "manifest.json"
+	// tenantImportBatch is how many rows are inserted per statement.
+	tenantImportBatch = 500
+)
+
+var (
+	// ErrTenantArchiveInvalid indicates an archive that is not a tenant
+	// export or whose files do not match their checksums.
+	ErrTenantArchiveInvalid = errors.New("invalid tenant archive")
+	// ErrTenantNotEmpty indicates an import into a tenant that already has data.
+	ErrTenantNotEmpty = errors.New("tenant already has data")
+)
+
+// tenantBlobColumns are the columns holding blob keys, by table.
+var tenantBlobColumns = map[string][]string{
+	"life_certificate":               {"selfie_path", "document_path"},
+	"participant_verification_state": {"selfie_path"},
+	"certificate_documents":          {"storage_key"},
+	"documents":                      {"storage_key"},
+	"monthly_reports":                {"path"},
+	"verification_requests":          {"image_key"},
+	"upload_scans":                   {"quarantine_key"},
+}
+
+// TenantArchive is the manifest of a tenant export: the tenant, one JSON
+// lines file of rows per table and the blobs the rows refer to, each file
+// with its size and SHA-256.
+type TenantArchive struct {
+	Version    int                 `json:"version"`
+	Tenant     TenantArchiveTenant `json:"tenant"`
+	ExportedAt time.Time           `json:"exported_at"`
+	Tables     []TenantArchiveFile `json:"tables"`
+	Blobs      []TenantArchiveFile `json:"blobs"`
+	// MissingBlobs are referred to by rows but were not in the blob store,
+	// e.g. selfies the retention purge removed.
+	MissingBlobs []string `json:"missing_blobs,omitempty"`
+}
+
+// TenantArchiveTenant is the exported tenant. Its FR Core API keys are left
+// out of the archive.
+type TenantArchiveTenant struct {
+	domain.Tenant
+	LogoKey string `json:"logo_key,omitempty"`
+}
+
+// TenantArchiveFile is a table or a blob in an archive.
+type TenantArchiveFile struct {
+	// Name is the table or the blob key.
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Rows   int    `json:"rows,omitempty"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// TenantTransferService moves a tenant's data between deployments, e.g. when
+// a fund leaves for an instance of its own. Faces stay in FR Core: the
+// target deployment must reach the same FR Core tenant for verifications to
+// keep matching.
+type TenantTransferService struct {
+	tenants repository.TenantRepository
+	data    repository.TenantDataRepository
+	blobs   storage.BlobStore
+	audit   repository.AuditLogRepository
+	tx      repository.Transactor
+}
+
+// NewTenantTransferService wires dependencies for tenant export and import.
+func NewTenantTransferService(tenants repository.TenantRepository, data repository.TenantDataRepository, blobs storage.BlobStore, audit repository.AuditLogRepository, tx repository.Transactor) *TenantTransferService {
+	return &TenantTransferService{tenants: tenants, data: data, blobs: blobs, audit: audit, tx: tx}
+}
+
+// Export writes the tenant of ctx to w as a ZIP archive. The rows are read
+// from one snapshot; blobs written meanwhile may be missing, so the tenant
+// is best disabled while it moves.
+func (s *TenantTransferService) Export(ctx context.Context, actor string, w io.Writer) (*TenantArchive, error) {
+	t, err := s.tenants.Get(ctx, tenant.ID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrTenantNotFound
+	}
+	archive := &TenantArchive{
+		Version:    tenantArchiveVersion,
+		Tenant:     TenantArchiveTenant{Tenant: *t, LogoKey: t.Branding.LogoKey},
+		ExportedAt: time.Now().UTC(),
+	}
+	archive.Tenant.FRCoreUploadAPIKey, archive.Tenant.FRCoreRecognizeAPIKey = "", ""
+
+	zw := zip.NewWriter(w)
+	blobKeys := make(map[string]bool)
+	if t.Branding.LogoKey != "" {
+		blobKeys[t.Branding.LogoKey] = true
+	}
+
+	// Export visits the tables one after the other, so each gets one entry.
+	var current *TenantArchiveFile
+	var entry *checksumWriter
+	var encoder *json.Encoder
+	finish := func() {
+		if current != nil {
+			current.Size, current.SHA256 = entry.size, entry.sum()
+			archive.Tables = append(archive.Tables, *current)
+		}
+	}
+	err = s.data.Export(ctx, func(table string, row repository.TenantRow) error {
+		if current == nil || current.Name != table {
+			finish()
+			current = &TenantArchiveFile{Name: table, Path: "tables/" + table + ".jsonl"}
+			file, err := zw.Create(current.Path)
+			if err != nil {
+				return fmt.Errorf("write tenant archive: %w", err)
+			}
+			entry = newChecksumWriter(file)
+			encoder = json.NewEncoder(entry)
+		}
+		current.Rows++
+		for _, column := range tenantBlobColumns[table] {
+			var key *string
+			if raw, ok := row[column]; ok && json.Unmarshal(raw, &key) == nil && key != nil && *key != "" {
+				blobKeys[*key] = true
+			}
+		}
+		if err := encoder.Encode(row); err != nil {
+			return fmt.Errorf("write tenant archive: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	finish()
+
+	keys := make([]string, 0, len(blobKeys))
+	for key := range blobKeys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		file, err := s.exportBlob(ctx, zw, key)
+		if errors.Is(err, storage.ErrNotFound) {
+			archive.MissingBlobs = append(archive.MissingBlobs, key)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		archive.Blobs = append(archive.Blobs, *file)
+	}
+
+	manifest, err := zw.Create(tenantArchiveManifest)
+	if err != nil {
+		return nil, fmt.Errorf("write tenant archive: %w", err)
+	}
+	encoder = json.NewEncoder(manifest)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(archive); err != nil {
+		return nil, fmt.Errorf("write tenant archive: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("write tenant archive: %w", err)
+	}
+
+	if err := recordAudit(ctx, s.audit, actor, auditActionTenantExport, auditEntityTenant, t.ID, tenantArchiveAuditDetails(archive)); err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
+
+func (s *TenantTransferService) exportBlob(ctx context.Context, zw *zip.Writer, key string) (*TenantArchiveFile, error) {
+	content, err := s.blobs.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+	file := &TenantArchiveFile{Name: key, Path: "blobs/" + key}
+	w, err := zw.Create(file.Path)
+	if err != nil {
+		return nil, fmt.Errorf("write tenant archive: %w", err)
+	}
+	entry := newChecksumWriter(w)
+	if _, err := io.Copy(entry, content); err != nil {
+		return nil, fmt.Errorf("archive blob %s: %w", key, err)
+	}
+	file.Size, file.SHA256 = entry.size, entry.sum()
+	return file, nil
+}
+
+// Import loads an archive written by Export under tenantID, the archived
+// tenant's ID when empty, keeping every row's ID. Every file is checked
+// against its checksum first. A new tenant is created with the archived
+// settings and no FR Core API keys; an existing one must have no data yet,
+// and takes the archived name, thresholds and branding. Blobs already in the
+// store are kept when identical, and refused otherwise.
+func (s *TenantTransferService) Import(ctx context.Context, actor string, archive *zip.Reader, tenantID string) (*TenantArchive, error) {
+	manifest, files, err := readTenantArchive(archive)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool)
+	for _, table := range s.data.Tables() {
+		known[table] = true
+	}
+	for _, table := range manifest.Tables {
+		if !known[table.Name] {
+			return nil, fmt.Errorf("%w: unknown table %s", ErrTenantArchiveInvalid, table.Name)
+		}
+	}
+	if tenantID == "" {
+		tenantID = manifest.Tenant.ID
+	}
+	if !tenantIDPattern.MatchString(tenantID) {
+		return nil, fmt.Errorf("%w: tenant ID %q is not valid", ErrTenantArchiveInvalid, tenantID)
+	}
+
+	ctx = tenant.WithID(ctx, tenantID)
+	existing, err := s.tenants.Get(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		for _, table := range s.data.Tables() {
+			count, err := s.data.Count(ctx, table)
+			if err != nil {
+				return nil, err
+			}
+			if count > 0 {
+				return nil, fmt.Errorf("%w: %s has %d rows in %s", ErrTenantNotEmpty, tenantID, count, table)
+			}
+		}
+	}
+
+	for _, blob := range manifest.Blobs {
+		if err := s.importBlob(ctx, files[blob.Path], blob); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		now := time.Now().UTC()
+		archived := manifest.Tenant
+		t := existing
+		if t == nil {
+			t = &archived.Tenant
+			t.ID, t.DisabledAt, t.UpdatedAt = tenantID, nil, now
+			t.FRCoreUploadAPIKey, t.FRCoreRecognizeAPIKey = "", ""
+			t.Branding.LogoKey = archived.LogoKey
+			if err := s.tenants.Create(ctx, t); err != nil {
+				return err
+			}
+		} else {
+			t.Name, t.Branding = archived.Name, archived.Branding
+			t.DistanceThreshold, t.SimilarityThreshold = archived.DistanceThreshold, archived.SimilarityThreshold
+			t.Branding.LogoKey, t.UpdatedAt = archived.LogoKey, now
+			if err := s.tenants.Update(ctx, t); err != nil {
+				return err
+			}
+		}
+
+		for _, table := range manifest.Tables {
+			if err := s.importTable(ctx, files[table.Path], table.Name); err != nil {
+				return err
+			}
+		}
+		details := tenantArchiveAuditDetails(manifest)
+		details["source_tenant_id"] = manifest.Tenant.ID
+		details["exported_at"] = manifest.ExportedAt
+		return recordAudit(ctx, s.audit, actor, auditActionTenantImport, auditEntityTenant, tenantID, details)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func (s *TenantTransferService) importTable(ctx context.Context, file *zip.File, table string) error {
+	content, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("read tenant archive: %w", err)
+	}
+	defer content.Close()
+
+	decoder := json.NewDecoder(bufio.NewReader(content))
+	batch := make([]repository.TenantRow, 0, tenantImportBatch)
+	for {
+		var row repository.TenantRow
+		err := decoder.Decode(&row)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrTenantArchiveInvalid, file.Name, err)
+		}
+		if batch = append(batch, row); len(batch) == tenantImportBatch {
+			if err := s.data.Import(ctx, table, batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	return s.data.Import(ctx, table, batch)
+}
+
+func (s *TenantTransferService) importBlob(ctx context.Context, file *zip.File, blob TenantArchiveFile) error {
+	content, err := readArchiveFile(file)
+	if err != nil {
+		return err
+	}
+	stored, err := s.blobs.Get(ctx, blob.Name)
+	switch {
+	case errors.Is(err, storage.ErrNotFound):
+		return s.blobs.Put(ctx, blob.Name, content)
+	case err != nil:
+		return err
+	}
+	defer stored.Close()
+	digest := sha256.New()
+	if _, err := io.Copy(digest, stored); err != nil {
+		return fmt.Errorf("read blob %s: %w", blob.Name, err)
+	}
+	if hex.EncodeToString(digest.Sum(nil)) != blob.SHA256 {
+		return fmt.Errorf("blob %s already exists with other content", blob.Name)
+	}
+	return nil
+}
+
+// readTenantArchive reads the manifest of an archive and checks every file
+// it lists against its size and checksum, returning the files by path.
+func readTenantArchive(archive *zip.Reader) (*TenantArchive, map[string]*zip.File, error) {
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+	if files[tenantArchiveManifest] == nil {
+		return nil, nil, fmt.Errorf("%w: %s is missing", ErrTenantArchiveInvalid, tenantArchiveManifest)
+	}
+	raw, err := readArchiveFile(files[tenantArchiveManifest])
+	if err != nil {
+		return nil, nil, err
+	}
+	var manifest TenantArchive
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s: %v", ErrTenantArchiveInvalid, tenantArchiveManifest, err)
+	}
+	if manifest.Version != tenantArchiveVersion {
+		return nil, nil, fmt.Errorf("%w: version %d is not supported", ErrTenantArchiveInvalid, manifest.Version)
+	}
+
+	for _, listed := range append(append([]TenantArchiveFile{}, manifest.Tables...), manifest.Blobs...) {
+		file := files[listed.Path]
+		if file == nil {
+			return nil, nil, fmt.Errorf("%w: %s is missing", ErrTenantArchiveInvalid, listed.Path)
+		}
+		content, err := file.Open()
+		if err != nil {
+			return nil, nil, fmt.Errorf("read tenant archive: %w", err)
+		}
+		entry := newChecksumWriter(io.Discard)
+		_, err = io.Copy(entry, content)
+		content.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %s: %v", ErrTenantArchiveInvalid, listed.Path, err)
+		}
+		if entry.size != listed.Size || entry.sum() != listed.SHA256 {
+			return nil, nil, fmt.Errorf("%w: %s does not match its checksum", ErrTenantArchiveInvalid, listed.Path)
+		}
+	}
+	return &manifest, files, nil
+}
+
+func readArchiveFile(file *zip.File) ([]byte, error) {
+	content, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("read tenant archive: %w", err)
+	}
+	defer content.Close()
+	raw, err := io.ReadAll(content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrTenantArchiveInvalid, file.Name, err)
+	}
+	return raw, nil
+}
+
+func tenantArchiveAuditDetails(archive *TenantArchive) map[string]interface{} {
+	rows := make(map[string]int, len(archive.Tables))
+	for _, table := range archive.Tables {
+		rows[table.Name] = table.Rows
+	}
+	return map[string]interface{}{
+		"rows":          rows,
+		"blobs":         len(archive.Blobs),
+		"missing_blobs": len(archive.MissingBlobs),
+	}
+}
+
+// checksumWriter hashes and counts what is written through it.
+type checksumWriter struct {
+	w      io.Writer
+	digest hash.Hash
+	size   int64
+}
+
+func newChecksumWriter(w io.Writer) *checksumWriter {
+	return &checksumWriter{w: w, digest: sha256.New()}
+}
+
+func (c *checksumWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.digest.Write(p[:n])
+	c.size += int64(n)
+	return n, err
+}
+
+func (c *checksumWriter) sum() string {
+	return hex.EncodeToString(c.digest.Sum(nil))
+}
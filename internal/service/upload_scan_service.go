@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/antivirus"
+	"life-certificates/internal/domain"
+	"life-certificates/internal/metrics"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/storage"
+)
+
+const (
+	auditEntityUploadScan     = "upload_scan"
+	auditActionUploadInfected = "upload.infected"
+	uploadQuarantineKeyPrefix = "quarantine/"
+)
+
+// ErrUploadScanFailed indicates an upload could not be scanned, so it was not stored.
+var ErrUploadScanFailed = errors.New("upload could not be scanned for malware, try again later")
+
+// InfectedUploadError rejects an upload in which the scanner found malware.
+type InfectedUploadError struct {
+	FileName  string
+	Signature string
+}
+
+func (e *InfectedUploadError) Error() string {
+	return fmt.Sprintf("%s was rejected: malware detected (%s)", e.FileName, e.Signature)
+}
+
+// UploadScanService scans uploads for malware before they are stored and
+// keeps a record of every scan.
+type UploadScanService struct {
+	scanner    antivirus.Scanner
+	scans      repository.UploadScanRepository
+	audit      repository.AuditLogRepository
+	blobs      storage.BlobStore
+	quarantine bool
+}
+
+// NewUploadScanService wires dependencies for upload scanning. A nil scanner
+// disables scanning; quarantine keeps infected uploads in the blob store.
+func NewUploadScanService(scanner antivirus.Scanner, scans repository.UploadScanRepository, audit repository.AuditLogRepository, blobs storage.BlobStore, quarantine bool) *UploadScanService {
+	return &UploadScanService{scanner: scanner, scans: scans, audit: audit, blobs: blobs, quarantine: quarantine}
+}
+
+// UploadScanQueryInput carries scan filters and paging.
+type UploadScanQueryInput struct {
+	Result   string
+	From     string
+	To       string
+	Page     int
+	PageSize int
+}
+
+// UploadScanListOutput is a page of scan results, newest first.
+type UploadScanListOutput struct {
+	Items    []domain.UploadScan `json:"items"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"page_size"`
+	Total    int64               `json:"total"`
+}
+
+// Check scans an upload before it is stored. A clean upload returns its scan
+// for the caller to Record alongside whatever it stores; an infected one is
+// recorded, quarantined when configured, and rejected with an
+// InfectedUploadError. It returns nil, nil when scanning is disabled.
+func (s *UploadScanService) Check(ctx context.Context, actor, fileName string, data []byte) (*domain.UploadScan, error) {
+	if s.scanner == nil {
+		return nil, nil
+	}
+	if actor == "" {
+		actor = systemActor
+	}
+
+	result, err := s.scanner.Scan(ctx, data)
+	if err != nil {
+		metrics.UploadScanned("error")
+		return nil, fmt.Errorf("%w: %v", ErrUploadScanFailed, err)
+	}
+	digest := sha256.Sum256(data)
+	scan := &domain.UploadScan{
+		ID:        uuid.NewString(),
+		FileName:  filepath.Base(fileName),
+		SizeBytes: int64(len(data)),
+		SHA256:    hex.EncodeToString(digest[:]),
+		Result:    domain.UploadScanClean,
+		ScannedBy: actor,
+		ScannedAt: time.Now().UTC(),
+	}
+	if !result.Infected {
+		metrics.UploadScanned("clean")
+		return scan, nil
+	}
+
+	metrics.UploadScanned("infected")
+	scan.Result = domain.UploadScanInfected
+	scan.Signature = &result.Signature
+	if s.quarantine {
+		key := uploadQuarantineKeyPrefix + scan.ID + strings.ToLower(filepath.Ext(scan.FileName))
+		if err := s.blobs.Put(ctx, key, data); err != nil {
+			return nil, fmt.Errorf("quarantine upload: %w", err)
+		}
+		scan.QuarantineKey = &key
+	}
+	if err := s.Record(ctx, scan); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, s.audit, actor, auditActionUploadInfected, auditEntityUploadScan, scan.ID, map[string]interface{}{
+		"file_name":      scan.FileName,
+		"sha256":         scan.SHA256,
+		"signature":      result.Signature,
+		"quarantine_key": scan.QuarantineKey,
+	}); err != nil {
+		return nil, err
+	}
+	return nil, &InfectedUploadError{FileName: scan.FileName, Signature: result.Signature}
+}
+
+// Record stores a scan result returned by Check.
+func (s *UploadScanService) Record(ctx context.Context, scan *domain.UploadScan) error {
+	if scan == nil {
+		return nil
+	}
+	return s.scans.Create(ctx, scan)
+}
+
+// List returns scan results matching the filters, newest first.
+func (s *UploadScanService) List(ctx context.Context, input UploadScanQueryInput) (*UploadScanListOutput, error) {
+	filter := repository.UploadScanFilter{Result: strings.ToUpper(strings.TrimSpace(input.Result))}
+	if filter.Result != "" && filter.Result != domain.UploadScanClean && filter.Result != domain.UploadScanInfected {
+		return nil, fmt.Errorf("result must be CLEAN or INFECTED")
+	}
+	verr := &ValidationError{}
+	filter.From, filter.To = parseDateRange(verr, input.From, input.To)
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	page := normalizePagination(input.Page, input.PageSize)
+	items, total, err := s.scans.List(ctx, filter, page)
+	if err != nil {
+		return nil, err
+	}
+	return &UploadScanListOutput{Items: items, Page: page.Page, PageSize: page.PageSize, Total: total}, nil
+}
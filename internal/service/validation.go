@@ -0,0 +1,44 @@
+package service
+
+import (
+	"sort"
+	"strings"
+)
+
+// ValidationError reports field-level problems with an input payload.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+// Error summarises the invalid fields in a stable order.
+func (e *ValidationError) Error() string {
+	names := make([]string, 0, len(e.Fields))
+	for name := range e.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+" "+e.Fields[name])
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+// add records a problem for the field, keeping the first message reported.
+func (e *ValidationError) add(field, message string) {
+	if e.Fields == nil {
+		e.Fields = make(map[string]string)
+	}
+	if _, exists := e.Fields[field]; !exists {
+		e.Fields[field] = message
+	}
+}
+
+// errOrNil returns the error only when at least one field failed.
+func (e *ValidationError) errOrNil() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+)
+
+// Audit vocabulary for verification devices.
+const (
+	auditActionDeviceTrust   = "participant.device_trust"
+	auditActionDeviceUntrust = "participant.device_untrust"
+)
+
+// ErrVerificationDeviceNotFound indicates the participant never verified from the device.
+var ErrVerificationDeviceNotFound = errors.New("verification device not found")
+
+// VerificationDeviceService keeps the registry of devices participants verify
+// from and which of them are trusted.
+type VerificationDeviceService struct {
+	devices      repository.VerificationDeviceRepository
+	participants repository.ParticipantRepository
+	audit        repository.AuditLogRepository
+	tx           repository.Transactor
+	// trustOnValid trusts a device once a VALID attempt comes from it.
+	trustOnValid bool
+}
+
+// NewVerificationDeviceService wires dependencies for the device registry.
+func NewVerificationDeviceService(devices repository.VerificationDeviceRepository, participants repository.ParticipantRepository, audit repository.AuditLogRepository, tx repository.Transactor, trustOnValid bool) *VerificationDeviceService {
+	return &VerificationDeviceService{
+		devices:      devices,
+		participants: participants,
+		audit:        audit,
+		tx:           tx,
+		trustOnValid: trustOnValid,
+	}
+}
+
+// assess returns how well the attempt's device is known. Kiosks serve many
+// pensioners from one device, so their attempts stay unknown.
+func (s *VerificationDeviceService) assess(ctx context.Context, participantID string, input VerifyInput) (domain.DeviceTrust, error) {
+	if input.DeviceID == "" || input.KioskID != "" {
+		return domain.DeviceTrustUnknown, nil
+	}
+	device, err := s.devices.Get(ctx, participantID, input.DeviceID)
+	if err != nil {
+		return "", err
+	}
+	return device.Trust(), nil
+}
+
+// record registers the attempt's device inside the transaction that stores
+// the attempt, and trusts it when the attempt is VALID.
+func (s *VerificationDeviceService) record(ctx context.Context, record *domain.LifeCertificate) error {
+	if record.DeviceTrust == nil || *record.DeviceTrust == domain.DeviceTrustUnknown {
+		return nil
+	}
+	err := s.devices.Record(ctx, &domain.VerificationDevice{
+		ParticipantID: record.ParticipantID,
+		DeviceID:      *record.DeviceID,
+		Model:         record.DeviceModel,
+		OS:            record.DeviceOS,
+		Attempts:      1,
+		FirstSeenAt:   record.VerifiedAt,
+		LastSeenAt:    record.VerifiedAt,
+	})
+	if err != nil {
+		return err
+	}
+	if !s.trustOnValid || record.Status != domain.LifeCertificateStatusValid || *record.DeviceTrust == domain.DeviceTrustTrusted {
+		return nil
+	}
+	actor := systemActor
+	if _, err := s.devices.SetTrust(ctx, record.ParticipantID, *record.DeviceID, &record.VerifiedAt, &actor); err != nil {
+		return err
+	}
+	return recordAudit(ctx, s.audit, systemActor, auditActionDeviceTrust, auditEntityParticipant, record.ParticipantID, map[string]interface{}{
+		"device_id":      *record.DeviceID,
+		"certificate_id": record.ID,
+	})
+}
+
+// List returns the participant's devices, most recently used first.
+func (s *VerificationDeviceService) List(ctx context.Context, participantID string) ([]domain.VerificationDevice, error) {
+	participant, err := s.participants.GetByID(ctx, strings.TrimSpace(participantID))
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return nil, ErrParticipantNotFound
+	}
+	return s.devices.ListByParticipant(ctx, participant.ID)
+}
+
+// Trust adds a device to the participant's trusted devices.
+func (s *VerificationDeviceService) Trust(ctx context.Context, actor, participantID, deviceID string) (*domain.VerificationDevice, error) {
+	now := time.Now().UTC()
+	return s.setTrust(ctx, actor, participantID, deviceID, &now, auditActionDeviceTrust)
+}
+
+// Untrust removes a device from the participant's trusted devices, so its
+// next attempts are held to the rules for known devices.
+func (s *VerificationDeviceService) Untrust(ctx context.Context, actor, participantID, deviceID string) (*domain.VerificationDevice, error) {
+	return s.setTrust(ctx, actor, participantID, deviceID, nil, auditActionDeviceUntrust)
+}
+
+func (s *VerificationDeviceService) setTrust(ctx context.Context, actor, participantID, deviceID string, at *time.Time, action string) (*domain.VerificationDevice, error) {
+	participantID, deviceID = strings.TrimSpace(participantID), strings.TrimSpace(deviceID)
+	participant, err := s.participants.GetByID(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return nil, ErrParticipantNotFound
+	}
+
+	var by *string
+	if at != nil {
+		by = &actor
+	}
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		ok, err := s.devices.SetTrust(ctx, participant.ID, deviceID, at, by)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrVerificationDeviceNotFound
+		}
+		return recordAudit(ctx, s.audit, actor, action, auditEntityParticipant, participant.ID, map[string]interface{}{
+			"device_id": deviceID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.devices.Get(ctx, participant.ID, deviceID)
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/metrics"
+	"life-certificates/internal/repository"
+)
+
+// Audit vocabulary for verification locks.
+const (
+	auditActionVerificationLock   = "participant.verification_lock"
+	auditActionVerificationUnlock = "participant.verification_unlock"
+)
+
+// ErrVerificationLocked indicates repeated INVALID attempts locked the participant's automatic verification.
+var ErrVerificationLocked = errors.New("verification is locked after repeated failed attempts")
+
+// VerificationLockPolicy locks automatic verification after repeated INVALID
+// attempts, so a photo cannot be varied until it passes.
+type VerificationLockPolicy struct {
+	// MaxFailures is how many consecutive INVALID attempts lock the participant; zero disables locking.
+	MaxFailures int
+	// Cooldown is how long a lock lasts; zero keeps it until an admin unlocks it.
+	Cooldown time.Duration
+}
+
+// VerificationLockStatus is a participant's lock state.
+type VerificationLockStatus struct {
+	Locked         bool       `json:"locked"`
+	FailedAttempts int        `json:"failed_attempts"`
+	LockedAt       *time.Time `json:"locked_at"`
+	LockedUntil    *time.Time `json:"locked_until"`
+}
+
+// VerificationLockService counts failed attempts, locks participants and lifts locks.
+type VerificationLockService struct {
+	locks        repository.VerificationLockRepository
+	participants repository.ParticipantRepository
+	audit        repository.AuditLogRepository
+	tx           repository.Transactor
+	policy       VerificationLockPolicy
+}
+
+// NewVerificationLockService wires dependencies for verification locks.
+func NewVerificationLockService(locks repository.VerificationLockRepository, participants repository.ParticipantRepository, audit repository.AuditLogRepository, tx repository.Transactor, policy VerificationLockPolicy) *VerificationLockService {
+	return &VerificationLockService{
+		locks:        locks,
+		participants: participants,
+		audit:        audit,
+		tx:           tx,
+		policy:       policy,
+	}
+}
+
+// Check refuses an attempt while the participant is locked.
+func (s *VerificationLockService) Check(ctx context.Context, participantID string, now time.Time) error {
+	lock, err := s.locks.GetByParticipant(ctx, participantID)
+	if err != nil {
+		return err
+	}
+	if lock.Locked(now) {
+		return ErrVerificationLocked
+	}
+	return nil
+}
+
+// Status returns the participant's lock state at now.
+func (s *VerificationLockService) Status(ctx context.Context, participantID string, now time.Time) (*VerificationLockStatus, error) {
+	lock, err := s.locks.GetByParticipant(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+	status := &VerificationLockStatus{}
+	if lock == nil {
+		return status, nil
+	}
+	status.FailedAttempts = lock.FailedAttempts
+	if lock.Locked(now) {
+		status.Locked = true
+		status.LockedAt = lock.LockedAt
+		status.LockedUntil = lock.LockedUntil
+	}
+	return status, nil
+}
+
+// RecordOutcome counts an automatic attempt's outcome inside the transaction
+// that stores it: an INVALID attempt adds a failure, locking the participant
+// at MaxFailures, and a VALID one starts the count over. REVIEW attempts do
+// not count either way.
+func (s *VerificationLockService) RecordOutcome(ctx context.Context, record *domain.LifeCertificate) error {
+	if s.policy.MaxFailures <= 0 {
+		return nil
+	}
+	switch record.Status {
+	case domain.LifeCertificateStatusValid:
+		return s.locks.ResetFailures(ctx, record.ParticipantID, record.VerifiedAt)
+	case domain.LifeCertificateStatusInvalid:
+	default:
+		return nil
+	}
+
+	lock, err := s.locks.RecordFailure(ctx, record.ParticipantID, record.VerifiedAt)
+	if err != nil {
+		return err
+	}
+	if lock.FailedAttempts < s.policy.MaxFailures {
+		return nil
+	}
+	var until *time.Time
+	if s.policy.Cooldown > 0 {
+		end := record.VerifiedAt.Add(s.policy.Cooldown)
+		until = &end
+	}
+	if err := s.locks.Lock(ctx, record.ParticipantID, record.VerifiedAt, until); err != nil {
+		return err
+	}
+	metrics.VerificationLocked()
+	return recordAudit(ctx, s.audit, systemActor, auditActionVerificationLock, auditEntityParticipant, record.ParticipantID, map[string]interface{}{
+		"failed_attempts": lock.FailedAttempts,
+		"certificate_id":  record.ID,
+		"locked_until":    until,
+	})
+}
+
+// Unlock lifts a participant's lock before its cooldown ends, or one without a cooldown.
+func (s *VerificationLockService) Unlock(ctx context.Context, actor, participantID string) (*VerificationLockStatus, error) {
+	participantID = strings.TrimSpace(participantID)
+	participant, err := s.participants.GetByID(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return nil, ErrParticipantNotFound
+	}
+
+	now := time.Now().UTC()
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		lock, err := s.locks.GetByParticipant(ctx, participant.ID)
+		if err != nil {
+			return err
+		}
+		if lock == nil {
+			return nil
+		}
+		if err := s.locks.Unlock(ctx, participant.ID, now); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionVerificationUnlock, auditEntityParticipant, participant.ID, map[string]interface{}{
+			"was_locked":      lock.Locked(now),
+			"failed_attempts": lock.FailedAttempts,
+			"locked_until":    lock.LockedUntil,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &VerificationLockStatus{}, nil
+}
@@ -0,0 +1,393 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/repository"
+)
+
+// Audit vocabulary for verification profile management.
+const (
+	auditEntityVerificationProfile = "verification_profile"
+	auditEntityParticipant         = "participant"
+	auditActionProfileCreate       = "verification_profile.create"
+	auditActionProfileUpdate       = "verification_profile.update"
+	auditActionProfileDelete       = "verification_profile.delete"
+	auditActionProfileAssign       = "verification_profile.assign"
+)
+
+const (
+	verificationProfileNameMaxLen = 100
+	fundMaxLen                    = 64
+)
+
+var (
+	// ErrVerificationProfileNotFound indicates the requested profile does not exist.
+	ErrVerificationProfileNotFound = errors.New("verification profile not found")
+	// ErrVerificationProfileConflict indicates another profile already uses the name or fund.
+	ErrVerificationProfileConflict = errors.New("verification profile name or fund already in use")
+)
+
+// VerificationProfileService manages verification profiles and their assignment to participants.
+type VerificationProfileService struct {
+	profiles     repository.VerificationProfileRepository
+	participants repository.ParticipantRepository
+	audit        repository.AuditLogRepository
+	tx           repository.Transactor
+}
+
+// VerificationProfileInput is the payload for creating a profile.
+type VerificationProfileInput struct {
+	Name                string                `json:"name"`
+	Fund                *string               `json:"fund"`
+	DistanceThreshold   float64               `json:"distance_threshold"`
+	SimilarityThreshold float64               `json:"similarity_threshold"`
+	LivenessPolicy      domain.LivenessPolicy `json:"liveness_policy"`
+	MaxAttemptsPerDay   int                   `json:"max_attempts_per_day"`
+	// SchedulePolicy is ROLLING, FIXED_DATE or BIRTHDAY_MONTH; empty keeps the global policy.
+	SchedulePolicy domain.SchedulePolicy `json:"schedule_policy"`
+	// ScheduleDate is the yearly MM-DD due date, required for FIXED_DATE.
+	ScheduleDate   *string `json:"schedule_date"`
+	ScheduleMonths int     `json:"schedule_months"`
+}
+
+// UpdateVerificationProfileInput changes a profile; nil fields are left untouched.
+// An empty fund detaches the profile from its fund.
+type UpdateVerificationProfileInput struct {
+	Name                *string                `json:"name"`
+	Fund                *string                `json:"fund"`
+	DistanceThreshold   *float64               `json:"distance_threshold"`
+	SimilarityThreshold *float64               `json:"similarity_threshold"`
+	LivenessPolicy      *domain.LivenessPolicy `json:"liveness_policy"`
+	MaxAttemptsPerDay   *int                   `json:"max_attempts_per_day"`
+	// SchedulePolicy set to an empty string goes back to the global policy.
+	SchedulePolicy *domain.SchedulePolicy `json:"schedule_policy"`
+	ScheduleDate   *string                `json:"schedule_date"`
+	ScheduleMonths *int                   `json:"schedule_months"`
+}
+
+// NewVerificationProfileService wires dependencies for profile management.
+func NewVerificationProfileService(profiles repository.VerificationProfileRepository, participants repository.ParticipantRepository, audit repository.AuditLogRepository, tx repository.Transactor) *VerificationProfileService {
+	return &VerificationProfileService{
+		profiles:     profiles,
+		participants: participants,
+		audit:        audit,
+		tx:           tx,
+	}
+}
+
+// List returns every profile ordered by name.
+func (s *VerificationProfileService) List(ctx context.Context) ([]domain.VerificationProfile, error) {
+	return s.profiles.List(ctx)
+}
+
+// Get returns a single profile.
+func (s *VerificationProfileService) Get(ctx context.Context, id string) (*domain.VerificationProfile, error) {
+	profile, err := s.profiles.GetByID(ctx, strings.TrimSpace(id))
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, ErrVerificationProfileNotFound
+	}
+	return profile, nil
+}
+
+// Create stores a new profile.
+func (s *VerificationProfileService) Create(ctx context.Context, actor string, input VerificationProfileInput) (*domain.VerificationProfile, error) {
+	now := time.Now().UTC()
+	profile := &domain.VerificationProfile{
+		ID:                  uuid.NewString(),
+		Name:                strings.TrimSpace(input.Name),
+		Fund:                optionalString(input.Fund),
+		DistanceThreshold:   input.DistanceThreshold,
+		SimilarityThreshold: input.SimilarityThreshold,
+		LivenessPolicy:      domain.LivenessPolicy(strings.ToUpper(string(input.LivenessPolicy))),
+		MaxAttemptsPerDay:   input.MaxAttemptsPerDay,
+		SchedulePolicy:      schedulePolicy(&input.SchedulePolicy),
+		ScheduleDate:        optionalString(input.ScheduleDate),
+		ScheduleMonths:      input.ScheduleMonths,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+	if profile.LivenessPolicy == "" {
+		profile.LivenessPolicy = domain.LivenessPolicyRequired
+	}
+	if err := s.validate(ctx, profile); err != nil {
+		return nil, err
+	}
+
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.profiles.Create(ctx, profile); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionProfileCreate, auditEntityVerificationProfile, profile.ID, map[string]interface{}{
+			"profile": profile,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// Update changes the provided fields of a profile.
+func (s *VerificationProfileService) Update(ctx context.Context, actor, id string, input UpdateVerificationProfileInput) (*domain.VerificationProfile, error) {
+	profile, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *profile
+
+	if input.Name != nil {
+		profile.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Fund != nil {
+		profile.Fund = optionalString(input.Fund)
+	}
+	if input.DistanceThreshold != nil {
+		profile.DistanceThreshold = *input.DistanceThreshold
+	}
+	if input.SimilarityThreshold != nil {
+		profile.SimilarityThreshold = *input.SimilarityThreshold
+	}
+	if input.LivenessPolicy != nil {
+		profile.LivenessPolicy = domain.LivenessPolicy(strings.ToUpper(string(*input.LivenessPolicy)))
+	}
+	if input.MaxAttemptsPerDay != nil {
+		profile.MaxAttemptsPerDay = *input.MaxAttemptsPerDay
+	}
+	if input.SchedulePolicy != nil {
+		profile.SchedulePolicy = schedulePolicy(input.SchedulePolicy)
+	}
+	if input.ScheduleDate != nil {
+		profile.ScheduleDate = optionalString(input.ScheduleDate)
+	}
+	if input.ScheduleMonths != nil {
+		profile.ScheduleMonths = *input.ScheduleMonths
+	}
+	if err := s.validate(ctx, profile); err != nil {
+		return nil, err
+	}
+	profile.UpdatedAt = time.Now().UTC()
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.profiles.Update(ctx, profile); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionProfileUpdate, auditEntityVerificationProfile, profile.ID, map[string]interface{}{
+			"before": before,
+			"after":  profile,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// Delete removes a profile. Participants assigned to it fall back to their fund's profile or the global settings.
+func (s *VerificationProfileService) Delete(ctx context.Context, actor, id string) error {
+	profile, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.participants.ClearVerificationProfile(ctx, profile.ID); err != nil {
+			return err
+		}
+		if err := s.profiles.Delete(ctx, profile.ID); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionProfileDelete, auditEntityVerificationProfile, profile.ID, map[string]interface{}{
+			"name": profile.Name,
+		})
+	})
+}
+
+// AssignParticipant pins a participant to a profile, or clears the assignment when profileID is nil.
+func (s *VerificationProfileService) AssignParticipant(ctx context.Context, actor, participantID string, profileID *string) (*domain.Participant, error) {
+	participant, err := s.participants.GetByID(ctx, strings.TrimSpace(participantID))
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return nil, ErrParticipantNotFound
+	}
+
+	var assigned *string
+	if profileID != nil && strings.TrimSpace(*profileID) != "" {
+		profile, err := s.Get(ctx, *profileID)
+		if err != nil {
+			return nil, err
+		}
+		assigned = &profile.ID
+	}
+
+	now := time.Now().UTC()
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.participants.SetVerificationProfile(ctx, participant.ID, assigned, now); err != nil {
+			return err
+		}
+		return recordAudit(ctx, s.audit, actor, auditActionProfileAssign, auditEntityParticipant, participant.ID, map[string]interface{}{
+			"before": participant.VerificationProfileID,
+			"after":  assigned,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	participant.VerificationProfileID = assigned
+	participant.UpdatedAt = now
+	return participant, nil
+}
+
+func (s *VerificationProfileService) validate(ctx context.Context, profile *domain.VerificationProfile) error {
+	verr := &ValidationError{}
+	switch {
+	case profile.Name == "":
+		verr.add("name", "is required")
+	case len(profile.Name) > verificationProfileNameMaxLen:
+		verr.add("name", "must be at most 100 characters")
+	}
+	if profile.Fund != nil && len(*profile.Fund) > fundMaxLen {
+		verr.add("fund", "must be at most 64 characters")
+	}
+	if profile.DistanceThreshold <= 0 {
+		verr.add("distance_threshold", "must be positive")
+	}
+	if profile.SimilarityThreshold < 0 || profile.SimilarityThreshold > 100 {
+		verr.add("similarity_threshold", "must be between 0 and 100")
+	}
+	switch profile.LivenessPolicy {
+	case domain.LivenessPolicyRequired, domain.LivenessPolicyReview, domain.LivenessPolicySkip:
+	default:
+		verr.add("liveness_policy", "must be one of REQUIRED, REVIEW, SKIP")
+	}
+	if profile.MaxAttemptsPerDay < 0 {
+		verr.add("max_attempts_per_day", "must not be negative")
+	}
+	if profile.SchedulePolicy != nil {
+		switch *profile.SchedulePolicy {
+		case domain.SchedulePolicyRolling, domain.SchedulePolicyBirthdayMonth:
+		case domain.SchedulePolicyFixedDate:
+			if profile.ScheduleDate == nil {
+				verr.add("schedule_date", "is required for FIXED_DATE")
+			}
+		default:
+			verr.add("schedule_policy", "must be one of ROLLING, FIXED_DATE, BIRTHDAY_MONTH")
+		}
+	}
+	if profile.ScheduleDate != nil && !validScheduleDate(*profile.ScheduleDate) {
+		verr.add("schedule_date", "must be a MM-DD date")
+	}
+	if profile.ScheduleMonths < 0 {
+		verr.add("schedule_months", "must not be negative")
+	}
+	if err := verr.errOrNil(); err != nil {
+		return err
+	}
+
+	existing, err := s.profiles.GetByName(ctx, profile.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != profile.ID {
+		return ErrVerificationProfileConflict
+	}
+	if profile.Fund != nil {
+		existing, err := s.profiles.GetByFund(ctx, *profile.Fund)
+		if err != nil {
+			return err
+		}
+		if existing != nil && existing.ID != profile.ID {
+			return ErrVerificationProfileConflict
+		}
+	}
+	return nil
+}
+
+// optionalString trims an optional value, mapping blank values to nil.
+func optionalString(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+// schedulePolicy upper-cases an optional schedule policy, mapping blank values to nil.
+func schedulePolicy(value *domain.SchedulePolicy) *domain.SchedulePolicy {
+	if value == nil {
+		return nil
+	}
+	policy := domain.SchedulePolicy(strings.ToUpper(strings.TrimSpace(string(*value))))
+	if policy == "" {
+		return nil
+	}
+	return &policy
+}
+
+// verificationPolicy is the set of rules applied to one verification attempt.
+type verificationPolicy struct {
+	ProfileID *string
+	// ProfileName picks the profile's decision rules; empty uses the default rules.
+	ProfileName         string
+	DistanceThreshold   float64
+	SimilarityThreshold float64
+	Liveness            domain.LivenessPolicy
+	MaxAttemptsPerDay   int
+}
+
+// resolveProfile returns the participant's own profile, then their fund's
+// profile, or nil when neither exists.
+func resolveProfile(ctx context.Context, profiles repository.VerificationProfileRepository, participant *domain.Participant) (*domain.VerificationProfile, error) {
+	if participant.VerificationProfileID != nil {
+		profile, err := profiles.GetByID(ctx, *participant.VerificationProfileID)
+		if err != nil || profile != nil {
+			return profile, err
+		}
+	}
+	if participant.Fund != nil {
+		return profiles.GetByFund(ctx, *participant.Fund)
+	}
+	return nil, nil
+}
+
+// resolveVerificationPolicy picks the participant's own profile, then their
+// fund's profile, then the global settings.
+func resolveVerificationPolicy(ctx context.Context, profiles repository.VerificationProfileRepository, participant *domain.Participant, settings VerificationSettings) (verificationPolicy, error) {
+	profile, err := resolveProfile(ctx, profiles, participant)
+	if err != nil {
+		return verificationPolicy{}, err
+	}
+	if profile != nil {
+		return verificationPolicy{
+			ProfileID:           &profile.ID,
+			ProfileName:         profile.Name,
+			DistanceThreshold:   profile.DistanceThreshold,
+			SimilarityThreshold: profile.SimilarityThreshold,
+			Liveness:            profile.LivenessPolicy,
+			MaxAttemptsPerDay:   profile.MaxAttemptsPerDay,
+		}, nil
+	}
+
+	policy := verificationPolicy{
+		DistanceThreshold:   settings.DistanceThreshold,
+		SimilarityThreshold: settings.SimilarityThreshold,
+		Liveness:            domain.LivenessPolicyRequired,
+	}
+	if !settings.LivenessEnabled {
+		policy.Liveness = domain.LivenessPolicyReview
+	}
+	return policy, nil
+}
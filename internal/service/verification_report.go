@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"life-certificates/internal/repository"
+)
+
+// Verification report groupings.
+const (
+	ReportGroupProvince = "province"
+	ReportGroupCity     = "city"
+	ReportGroupFund     = "fund"
+)
+
+// reportGroupUnknown names the group of participants without a province, city or fund.
+const reportGroupUnknown = "UNKNOWN"
+
+// VerificationReportInput selects a report's grouping and period: a year
+// (2024), a quarter (2024-Q3) or a month (2024-07).
+type VerificationReportInput struct {
+	GroupBy string
+	Period  string
+}
+
+// VerificationReportRow is a group's verification completion in the period.
+// Participants are split into verified (a VALID attempt), failed (attempts
+// but none VALID) and not attempted; the rates are percentages of participants.
+type VerificationReportRow struct {
+	Group          string  `json:"group"`
+	Participants   int64   `json:"participants"`
+	Verified       int64   `json:"verified"`
+	Failed         int64   `json:"failed"`
+	NotAttempted   int64   `json:"not_attempted"`
+	CompletionRate float64 `json:"completion_rate"`
+	FailureRate    float64 `json:"failure_rate"`
+	Attempts       int64   `json:"attempts"`
+	Valid          int64   `json:"valid"`
+	Invalid        int64   `json:"invalid"`
+	Review         int64   `json:"review"`
+}
+
+// VerificationReport is the completion of a period by province, city or fund.
+type VerificationReport struct {
+	Period  string                  `json:"period"`
+	From    string                  `json:"from"`
+	To      string                  `json:"to"`
+	GroupBy string                  `json:"group_by"`
+	Rows    []VerificationReportRow `json:"rows"`
+	Total   VerificationReportRow   `json:"total"`
+}
+
+// VerificationReport aggregates the period's verifications by the province
+// or city of the participants' members, or by their fund. Participants
+// registered after the period and those whose member is deceased are left out.
+func (s *StatsService) VerificationReport(ctx context.Context, input VerificationReportInput) (*VerificationReport, error) {
+	groupBy := strings.ToLower(strings.TrimSpace(input.GroupBy))
+	switch groupBy {
+	case ReportGroupProvince, ReportGroupCity, ReportGroupFund:
+	default:
+		return nil, &ValidationError{Fields: map[string]string{"group_by": "must be one of province, city, fund"}}
+	}
+	period := strings.ToUpper(strings.TrimSpace(input.Period))
+	from, end, err := parseReportPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.stats.VerificationReport(ctx, groupBy, from, end)
+	if err != nil {
+		return nil, err
+	}
+	out := &VerificationReport{
+		Period:  period,
+		From:    from.Format("2006-01-02"),
+		To:      end.AddDate(0, 0, -1).Format("2006-01-02"),
+		GroupBy: groupBy,
+		Rows:    make([]VerificationReportRow, 0, len(rows)),
+	}
+	var total repository.VerificationReportRow
+	for _, row := range rows {
+		group := row.Group
+		if group == "" {
+			group = reportGroupUnknown
+		}
+		out.Rows = append(out.Rows, verificationReportRow(group, row))
+		total.Participants += row.Participants
+		total.Verified += row.Verified
+		total.Failed += row.Failed
+		total.Attempts += row.Attempts
+		total.Valid += row.Valid
+		total.Invalid += row.Invalid
+		total.Review += row.Review
+	}
+	out.Total = verificationReportRow("TOTAL", total)
+	return out, nil
+}
+
+func verificationReportRow(group string, row repository.VerificationReportRow) VerificationReportRow {
+	out := VerificationReportRow{
+		Group:        group,
+		Participants: row.Participants,
+		Verified:     row.Verified,
+		Failed:       row.Failed,
+		NotAttempted: row.Participants - row.Verified - row.Failed,
+		Attempts:     row.Attempts,
+		Valid:        row.Valid,
+		Invalid:      row.Invalid,
+		Review:       row.Review,
+	}
+	if row.Participants > 0 {
+		out.CompletionRate = percentage(row.Verified, row.Participants)
+		out.FailureRate = percentage(row.Failed, row.Participants)
+	}
+	return out
+}
+
+// parseReportPeriod returns the first day of a year, quarter or month and
+// the first day after it.
+func parseReportPeriod(period string) (time.Time, time.Time, error) {
+	invalid := &ValidationError{Fields: map[string]string{"period": "must be a year (2024), quarter (2024-Q3) or month (2024-07)"}}
+	year, rest, partial := strings.Cut(period, "-")
+	y, err := strconv.Atoi(year)
+	if err != nil || len(year) != 4 {
+		return time.Time{}, time.Time{}, invalid
+	}
+	switch {
+	case !partial:
+		from := time.Date(y, time.January, 1, 0, 0, 0, 0, time.UTC)
+		return from, from.AddDate(1, 0, 0), nil
+	case strings.HasPrefix(rest, "Q"):
+		quarter, err := strconv.Atoi(rest[1:])
+		if err != nil || len(rest) != 2 || quarter < 1 || quarter > 4 {
+			return time.Time{}, time.Time{}, invalid
+		}
+		from := time.Date(y, time.Month(3*quarter-2), 1, 0, 0, 0, 0, time.UTC)
+		return from, from.AddDate(0, 3, 0), nil
+	default:
+		month, err := strconv.Atoi(rest)
+		if err != nil || len(rest) != 2 || month < 1 || month > 12 {
+			return time.Time{}, time.Time{}, invalid
+		}
+		from := time.Date(y, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+		return from, from.AddDate(0, 1, 0), nil
+	}
+}
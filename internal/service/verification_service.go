@@ -2,27 +2,61 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
 
+	"life-certificates/internal/decision"
 	"life-certificates/internal/domain"
+	"life-certificates/internal/events"
 	"life-certificates/internal/frcore"
+	"life-certificates/internal/imaging"
 	"life-certificates/internal/liveness"
+	"life-certificates/internal/metrics"
 	"life-certificates/internal/repository"
+	"life-certificates/internal/storage"
+	"life-certificates/internal/tracing"
+)
+
+var (
+	// ErrAttemptLimitReached indicates the participant used up their automatic attempts for the day.
+	ErrAttemptLimitReached = errors.New("daily verification attempt limit reached")
+	// ErrSelfieNotFound indicates the attempt has no stored selfie, because storage is off or it was purged.
+	ErrSelfieNotFound = errors.New("selfie not stored")
 )
 
 // VerificationService coordinates life certificate verification flows.
 type VerificationService struct {
-	participants        repository.ParticipantRepository
-	certificates        repository.LifeCertificateRepository
-	frIdentities        repository.FRIdentityRepository
-	frClient            frcore.Client
-	livenessChecker     liveness.Checker
-	distanceThreshold   float64
-	similarityThreshold float64
+	participants    repository.ParticipantRepository
+	certificates    repository.LifeCertificateRepository
+	states          repository.VerificationStateRepository
+	frIdentities    repository.FRIdentityRepository
+	profiles        repository.VerificationProfileRepository
+	campaigns       repository.CampaignRepository
+	frClient        frcore.Client
+	images          *imaging.Processor
+	blobs           storage.BlobStore
+	livenessChecker liveness.Checker
+	settings        *VerificationSettingsService
+	reviewSLA       time.Duration
+	calendar        *CalendarService
+	capture         CaptureCheck
+	fraud           FraudCheck
+	stepUp          StepUpCheck
+	rules           *decision.Rules
+	locks           *VerificationLockService
+	conflicts       *FacialConflictService
+	devices         *VerificationDeviceService
+	consents        *ConsentService
+	tx              repository.Transactor
+	events          events.Publisher
 }
 
 // VerifyInput captures the payload for a verification attempt.
@@ -30,15 +64,106 @@ type VerifyInput struct {
 	ParticipantID    string
 	ImageBytes       []byte
 	OriginalFilename string
+	Location         string
+	// Latitude and Longitude are where the participant says they are, set together or not at all.
+	Latitude  *float64
+	Longitude *float64
+	// Challenge is the liveness challenge of the attempt's session, nil without one.
+	Challenge *liveness.Challenge
+	// KioskID, BranchID and Operator record the branch kiosk and the operator
+	// who captured the selfie, and OfficerID and OfficerName the operator's
+	// officer record; empty outside kiosk mode.
+	KioskID     string
+	BranchID    string
+	Operator    string
+	OfficerID   string
+	OfficerName string
+	// Geofence places a kiosk submission against its branch's geofence; a
+	// submission outside it goes to review.
+	Geofence *Geofence
+	// ClientIP is the address the attempt was submitted from; DeviceID, the
+	// capturing device's app instance ID, and its model and OS are set when
+	// the client sends them.
+	ClientIP    string
+	DeviceID    string
+	DeviceModel string
+	DeviceOS    string
+	// AllowStepUp lets the attempt answer with a step-up challenge when it
+	// falls in a gray zone; callers that cannot hand the challenge to the
+	// participant leave it off.
+	AllowStepUp bool
+	// SessionID is the verification session the attempt presented and
+	// StepUpOf the first step when that session was opened for a step-up.
+	SessionID string
+	StepUpOf  *StepUp
+	// DryRun decides the attempt without recording anything: no certificate,
+	// selfie, face alias, lock or device change and no event. The session the
+	// attempt presented is not used up.
+	DryRun bool
+	// OnRecorded, when set, runs inside the transaction that stores the certificate.
+	OnRecorded func(ctx context.Context, record *domain.LifeCertificate) error
+}
+
+// attribute stores where the attempt came from on the certificate.
+func (in VerifyInput) attribute(record *domain.LifeCertificate) {
+	record.Channel = domain.VerificationChannelMobile
+	if in.KioskID != "" {
+		record.Channel = domain.VerificationChannelKiosk
+	}
+	if in.SessionID != "" {
+		record.SessionID = &in.SessionID
+	}
+	record.Latitude, record.Longitude = in.Latitude, in.Longitude
+	if in.ClientIP != "" {
+		record.ClientIP = &in.ClientIP
+	}
+	if in.DeviceID != "" {
+		record.DeviceID = &in.DeviceID
+	}
+	if in.DeviceModel != "" {
+		record.DeviceModel = &in.DeviceModel
+	}
+	if in.DeviceOS != "" {
+		record.DeviceOS = &in.DeviceOS
+	}
+	if in.KioskID != "" {
+		record.KioskID = &in.KioskID
+	}
+	if in.BranchID != "" {
+		record.BranchID = &in.BranchID
+	}
+	if in.Operator != "" {
+		record.RecordedBy = &in.Operator
+	}
+	if in.OfficerID != "" {
+		record.OfficerID, record.OfficerName = &in.OfficerID, &in.OfficerName
+	}
+	in.Geofence.apply(record)
+}
+
+// recorded runs the OnRecorded hook, if any.
+func (in VerifyInput) recorded(ctx context.Context, record *domain.LifeCertificate) error {
+	if in.OnRecorded == nil {
+		return nil
+	}
+	return in.OnRecorded(ctx, record)
 }
 
 // VerifyOutput contains persisted verification metadata.
 type VerifyOutput struct {
+	CertificateID string
 	ParticipantID string
 	Status        domain.LifeCertificateStatus
 	Distance      *float64
 	Similarity    *float64
 	VerifiedAt    time.Time
+	// StepUp is set, and nothing recorded, when the attempt needs a step-up challenge.
+	StepUp *StepUp
+	// DryRun is set when the outcome was not recorded; RiskScore and Reason,
+	// why the attempt would go to review, are only reported then.
+	DryRun    bool
+	RiskScore *int
+	Reason    string
 }
 
 // StatusOutput returns the latest verification record.
@@ -49,65 +174,243 @@ type StatusOutput struct {
 	Similarity    *float64
 	VerifiedAt    *time.Time
 	SelfiePath    string
+	// Lock is whether repeated INVALID attempts locked automatic verification.
+	Lock *VerificationLockStatus
 }
 
-// NewVerificationService wires dependencies for verification flows.
-func NewVerificationService(participants repository.ParticipantRepository, certificates repository.LifeCertificateRepository, frIdentities repository.FRIdentityRepository, frClient frcore.Client, checker liveness.Checker, distanceThreshold, similarityThreshold float64) *VerificationService {
+// NewVerificationService wires dependencies for verification flows. Selfies are
+// stored only when blobs is non-nil.
+func NewVerificationService(participants repository.ParticipantRepository, certificates repository.LifeCertificateRepository, states repository.VerificationStateRepository, frIdentities repository.FRIdentityRepository, profiles repository.VerificationProfileRepository, campaigns repository.CampaignRepository, frClient frcore.Client, images *imaging.Processor, blobs storage.BlobStore, checker liveness.Checker, settings *VerificationSettingsService, reviewSLA time.Duration, calendar *CalendarService, capture CaptureCheck, fraud FraudCheck, stepUp StepUpCheck, rules *decision.Rules, locks *VerificationLockService, conflicts *FacialConflictService, devices *VerificationDeviceService, consents *ConsentService, tx repository.Transactor, publisher events.Publisher) *VerificationService {
+	if capture.Location == nil {
+		capture.Location = time.UTC
+	}
 	return &VerificationService{
-		participants:        participants,
-		certificates:        certificates,
-		frIdentities:        frIdentities,
-		frClient:            frClient,
-		livenessChecker:     checker,
-		distanceThreshold:   distanceThreshold,
-		similarityThreshold: similarityThreshold,
+		participants:    participants,
+		certificates:    certificates,
+		states:          states,
+		frIdentities:    frIdentities,
+		profiles:        profiles,
+		campaigns:       campaigns,
+		frClient:        frClient,
+		images:          images,
+		blobs:           blobs,
+		livenessChecker: checker,
+		settings:        settings,
+		reviewSLA:       reviewSLA,
+		calendar:        calendar,
+		capture:         capture,
+		fraud:           fraud,
+		stepUp:          stepUp,
+		rules:           rules,
+		locks:           locks,
+		conflicts:       conflicts,
+		devices:         devices,
+		consents:        consents,
+		tx:              tx,
+		events:          publisher,
 	}
 }
 
 // Verify processes a life certificate submission from a participant.
 func (s *VerificationService) Verify(ctx context.Context, input VerifyInput) (*VerifyOutput, error) {
-	participantID := strings.TrimSpace(input.ParticipantID)
-	if participantID == "" {
-		return nil, fmt.Errorf("participant_id is required")
+	ctx, span := tracing.Start(ctx, "VerificationService.Verify", attribute.String("participant_id", input.ParticipantID))
+	defer span.End()
+
+	if err := validateVerifyInput(input); err != nil {
+		return nil, err
 	}
-	if len(input.ImageBytes) == 0 {
-		return nil, fmt.Errorf("image payload is required")
+	participant, err := s.eligibleParticipant(ctx, input.ParticipantID)
+	if err != nil {
+		return nil, err
 	}
 
-	participant, err := s.participants.GetByID(ctx, participantID)
+	// Processing strips EXIF, so capture metadata is read from the upload itself.
+	capture := imaging.ReadMetadata(input.ImageBytes, s.capture.Location)
+
+	// Liveness and FR Core both see the upright, downscaled photo.
+	imageBytes, err := s.images.Process(ctx, input.ImageBytes)
 	if err != nil {
 		return nil, err
 	}
-	if participant == nil {
-		return nil, ErrParticipantNotFound
-	}
-
-	filename := input.OriginalFilename
+	filename := imaging.JPEGName(input.OriginalFilename)
 	if filename == "" {
 		filename = "verification.jpg"
 	}
+	selfieHash, err := imaging.PHash(imageBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	var location *string
+	if trimmed := strings.TrimSpace(input.Location); trimmed != "" {
+		location = &trimmed
+	}
 
 	now := time.Now().UTC()
+	// One snapshot per attempt, so a concurrent settings change cannot mix thresholds.
+	settings, err := s.settings.For(ctx)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := resolveVerificationPolicy(ctx, s.profiles, participant, settings)
+	if err != nil {
+		return nil, err
+	}
+	rules := s.rules.For(policy.ProfileName)
+	trust, err := s.devices.assess(ctx, participant.ID, input)
+	if err != nil {
+		return nil, err
+	}
+	device := decision.Device(strings.ToLower(string(trust)))
+	if policy.MaxAttemptsPerDay > 0 {
+		attempts, err := s.certificates.CountAutomaticSince(ctx, participant.ID, now.Add(-24*time.Hour))
+		if err != nil {
+			return nil, err
+		}
+		if attempts >= int64(policy.MaxAttemptsPerDay) {
+			return nil, ErrAttemptLimitReached
+		}
+	}
 
-	passed, reason, err := s.livenessChecker.Evaluate(ctx, input.ImageBytes)
+	// A selfie identical to an earlier submission may be an old photo replayed
+	// instead of a fresh capture, so a person has to look at it.
+	replayOf, err := s.certificates.GetFirstBySelfieHash(ctx, selfieHash)
 	if err != nil {
-		return nil, fmt.Errorf("liveness evaluation failed: %w", err)
+		return nil, err
+	}
+
+	var captureFindings *string
+	findings := s.capture.findings(capture, now, input.Latitude, input.Longitude)
+	if len(findings) > 0 {
+		joined := strings.Join(findings, "; ")
+		captureFindings = &joined
+	}
+
+	// Kiosks serve many pensioners from one device and branch network by
+	// design, so only their selfies and positions are scored.
+	source := fraudSource{ParticipantID: participant.ID, SelfieHash: selfieHash, Latitude: input.Latitude, Longitude: input.Longitude}
+	if source.Latitude == nil {
+		source.Latitude, source.Longitude = capture.Latitude, capture.Longitude
+	}
+	if input.KioskID == "" {
+		source.DeviceID, source.ClientIP = input.DeviceID, input.ClientIP
+	}
+	risk, err := s.fraud.assess(ctx, s.certificates, source, now)
+	if err != nil {
+		return nil, err
+	}
+
+	passed, reason := true, ""
+	var livenessPassed *bool
+	switch {
+	case replayOf != nil:
+		passed, reason = false, "selfie_replay"
+		metrics.SelfieReplayDetected()
+	case input.StepUpOf != nil && input.StepUpOf.SelfieHash == selfieHash:
+		passed, reason = false, "step_up_replay"
+		metrics.SelfieReplayDetected()
+	case captureFindings != nil:
+		passed, reason = false, "capture_mismatch"
+	case input.Geofence != nil && input.Geofence.Finding != "":
+		passed, reason = false, "outside_geofence"
+	case s.fraud.review(risk):
+		passed, reason = false, "fraud_risk"
+		metrics.FraudRiskDetected()
+	// Rules may demand liveness from some devices, and step-up challenges
+	// always do, even where the profile skips it.
+	case policy.Liveness == domain.LivenessPolicyRequired,
+		policy.Liveness == domain.LivenessPolicySkip && (rules.Requires(decision.SignalLiveness, device) || input.StepUpOf != nil):
+		passed, reason, err = s.livenessChecker.Evaluate(ctx, imageBytes, input.Challenge)
+		if err != nil {
+			return nil, fmt.Errorf("liveness evaluation failed: %w", err)
+		}
+		metrics.LivenessEvaluated(passed)
+		livenessPassed = &passed
+	case policy.Liveness == domain.LivenessPolicyReview:
+		passed, reason = false, "liveness_disabled"
 	}
 
 	if !passed {
 		notes := reason
+		var replayOfID *string
+		if replayOf != nil {
+			replayOfID = &replayOf.ID
+			notes = fmt.Sprintf("%s: same photo as certificate %s", reason, replayOf.ID)
+		} else if captureFindings != nil {
+			notes = reason + ": " + *captureFindings
+		} else if reason == "outside_geofence" {
+			notes = reason + ": " + input.Geofence.Finding
+		} else if reason == "step_up_replay" {
+			notes = reason + ": same photo as the first step"
+		} else if reason == "fraud_risk" {
+			notes = reason + ": " + strings.Join(risk.Signals, "; ")
+		}
+		// Reviewers work to the national calendar.
+		dueAt, err := s.calendar.AddWorkingTime(ctx, now, s.reviewSLA, "")
+		if err != nil {
+			return nil, err
+		}
 		record := &domain.LifeCertificate{
-			ID:            uuid.NewString(),
-			ParticipantID: participant.ID,
-			SelfiePath:    "",
-			Status:        domain.LifeCertificateStatusReview,
-			VerifiedAt:    now,
-			Notes:         &notes,
+			ID:               uuid.NewString(),
+			ParticipantID:    participant.ID,
+			Status:           domain.LifeCertificateStatusReview,
+			Method:           domain.VerificationMethodAutomatic,
+			VerifiedAt:       now,
+			Notes:            &notes,
+			Location:         location,
+			SelfieHash:       &selfieHash,
+			CapturedAt:       capture.TakenAt,
+			CaptureLatitude:  capture.Latitude,
+			CaptureLongitude: capture.Longitude,
+			CaptureFindings:  captureFindings,
+			ReviewDueAt:      &dueAt,
+			DeviceTrust:      &trust,
+			StepUp:           input.StepUpOf.trace(),
 		}
-		if err := s.certificates.Create(ctx, record); err != nil {
+		input.attribute(record)
+		risk.apply(record)
+		if input.DryRun {
+			return dryRunOutput(record, reason), nil
+		}
+		if err := linkCampaign(ctx, s.campaigns, record); err != nil {
+			return nil, err
+		}
+		if err := s.storeSelfie(ctx, record, imageBytes); err != nil {
 			return nil, err
 		}
+		err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := s.certificates.Create(ctx, record); err != nil {
+				return err
+			}
+			if err := publishEvent(ctx, s.events, events.TypeVerificationReviewRequired, map[string]interface{}{
+				"certificate_id":   record.ID,
+				"participant_id":   participant.ID,
+				"status":           record.Status,
+				"location":         record.Location,
+				"kiosk_id":         record.KioskID,
+				"branch_id":        record.BranchID,
+				"reason":           reason,
+				"replay_of":        replayOfID,
+				"capture_findings": captureFindings,
+				"geofence_finding": record.GeofenceFinding,
+				"risk_score":       record.RiskScore,
+				"verified_at":      now,
+				"review_due_at":    record.ReviewDueAt,
+			}); err != nil {
+				return err
+			}
+			if err := s.devices.record(ctx, record); err != nil {
+				return err
+			}
+			return input.recorded(ctx, record)
+		})
+		if err != nil {
+			s.discardSelfie(ctx, record)
+			return nil, err
+		}
+		metrics.VerificationRecorded(string(record.Status), string(record.Method))
 		return &VerifyOutput{
+			CertificateID: record.ID,
 			ParticipantID: participant.ID,
 			Status:        domain.LifeCertificateStatusReview,
 			VerifiedAt:    now,
@@ -116,59 +419,159 @@ func (s *VerificationService) Verify(ctx context.Context, input VerifyInput) (*V
 
 	recognizeResp, err := s.frClient.Recognize(ctx, frcore.RecognizeRequest{
 		ImageName: filename,
-		Image:     input.ImageBytes,
+		Image:     imageBytes,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	status := domain.LifeCertificateStatusInvalid
-	distanceOk := false
-	if recognizeResp.Distance != nil {
-		distanceOk = *recognizeResp.Distance <= s.distanceThreshold
-	}
-	similarityOk := recognizeResp.Similarity >= s.similarityThreshold
+	distanceOk := recognizeResp.Distance == nil || *recognizeResp.Distance <= policy.DistanceThreshold
+	similarityOk := recognizeResp.Similarity >= policy.SimilarityThreshold
 
+	// A participant may have several enrolled faces; a match on any of their labels counts.
 	matchLabel := false
+	// conflictWith is another participant's identity the selfie matched with high confidence.
+	var conflictWith *domain.FRIdentity
 	label := strings.TrimSpace(recognizeResp.Label)
 	if label != "" {
-		identity, err := s.frIdentities.GetByLabel(ctx, label)
+		identities, err := s.frIdentities.ListByParticipantID(ctx, participant.ID)
 		if err != nil {
 			return nil, err
 		}
-		if identity != nil {
-			matchLabel = identity.ParticipantID == participant.ID
-		} else if similarityOk && (recognizeResp.Distance == nil || distanceOk) {
-			// New alias detected with high confidence – associate label with participant for future lookups.
-			_ = s.frIdentities.Create(ctx, &domain.FRIdentity{
-				Label:         label,
-				ParticipantID: participant.ID,
-				ExternalRef:   participant.FRExternalRef,
-			})
-			matchLabel = true
+		for _, identity := range identities {
+			if identity.Label == label {
+				matchLabel = true
+				break
+			}
+		}
+
+		if !matchLabel && similarityOk && distanceOk {
+			identity, err := s.frIdentities.GetByLabel(ctx, label)
+			if err != nil {
+				return nil, err
+			}
+			if identity == nil {
+				// New alias detected with high confidence – associate label with participant for future lookups.
+				if !input.DryRun {
+					_ = s.frIdentities.Create(ctx, &domain.FRIdentity{
+						Label:         label,
+						ParticipantID: participant.ID,
+						ExternalRef:   participant.FRExternalRef,
+						Source:        domain.FRIdentitySourceVerification,
+					})
+				}
+				matchLabel = true
+			} else if identity.ParticipantID != participant.ID {
+				conflictWith = identity
+			}
 		}
 	}
 
-	if matchLabel && (distanceOk || (!distanceOk && recognizeResp.Distance == nil && similarityOk)) {
+	observation := decision.Observation{
+		LabelMatch: matchLabel,
+		Distance:   recognizeResp.Distance,
+		Similarity: recognizeResp.Similarity,
+		Liveness:   livenessPassed,
+		Device:     device,
+	}
+	if risk != nil {
+		observation.RiskScore = &risk.Score
+	}
+	verdict := rules.Evaluate(observation, decision.Thresholds{
+		Distance:   policy.DistanceThreshold,
+		Similarity: policy.SimilarityThreshold,
+	})
+	// A face enrolled for someone else is never a pass, whatever the rules say.
+	status := domain.LifeCertificateStatusInvalid
+	if verdict.Passed && conflictWith == nil {
 		status = domain.LifeCertificateStatusValid
 	}
+	trace := verdict.Trace()
+
+	// An attempt in a gray zone is decided after a step-up challenge, and only once.
+	if input.AllowStepUp && input.StepUpOf == nil && matchLabel && conflictWith == nil {
+		if reasons := s.stepUp.reasons(policy, recognizeResp.Similarity, recognizeResp.Distance, risk); len(reasons) > 0 {
+			step := &StepUp{
+				Reasons:     reasons,
+				Challenge:   s.stepUp.Challenge,
+				Similarity:  recognizeResp.Similarity,
+				Distance:    recognizeResp.Distance,
+				SelfieHash:  selfieHash,
+				SubmittedAt: now,
+				Decision:    json.RawMessage(trace),
+			}
+			if risk != nil {
+				step.RiskScore = &risk.Score
+			}
+			if !input.DryRun {
+				metrics.StepUpRequired(reasons)
+			}
+			return &VerifyOutput{ParticipantID: participant.ID, VerifiedAt: now, StepUp: step}, nil
+		}
+	}
 
 	similarity := recognizeResp.Similarity
 	record := &domain.LifeCertificate{
-		ID:            uuid.NewString(),
-		ParticipantID: participant.ID,
-		SelfiePath:    "",
-		Status:        status,
-		Distance:      recognizeResp.Distance,
-		Similarity:    &similarity,
-		VerifiedAt:    now,
+		ID:               uuid.NewString(),
+		ParticipantID:    participant.ID,
+		Status:           status,
+		Method:           domain.VerificationMethodAutomatic,
+		Distance:         recognizeResp.Distance,
+		Similarity:       &similarity,
+		VerifiedAt:       now,
+		Location:         location,
+		SelfieHash:       &selfieHash,
+		CapturedAt:       capture.TakenAt,
+		CaptureLatitude:  capture.Latitude,
+		CaptureLongitude: capture.Longitude,
+		DecisionTrace:    &trace,
+		DeviceTrust:      &trust,
+		StepUp:           input.StepUpOf.trace(),
+	}
+	if conflictWith != nil {
+		notes := fmt.Sprintf("facial_conflict: matched label %s of participant %s", conflictWith.Label, conflictWith.ParticipantID)
+		record.Notes = &notes
+	}
+	input.attribute(record)
+	risk.apply(record)
+	if input.DryRun {
+		return dryRunOutput(record, ""), nil
+	}
+	if err := linkCampaign(ctx, s.campaigns, record); err != nil {
+		return nil, err
 	}
 
-	if err := s.certificates.Create(ctx, record); err != nil {
+	if err := s.storeSelfie(ctx, record, imageBytes); err != nil {
+		return nil, err
+	}
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.certificates.Create(ctx, record); err != nil {
+			return err
+		}
+		if err := publishEvent(ctx, s.events, events.TypeVerificationCompleted, verificationCompletedData(record)); err != nil {
+			return err
+		}
+		if conflictWith != nil {
+			if err := s.conflicts.record(ctx, record, conflictWith, similarity); err != nil {
+				return err
+			}
+		}
+		if err := s.locks.RecordOutcome(ctx, record); err != nil {
+			return err
+		}
+		if err := s.devices.record(ctx, record); err != nil {
+			return err
+		}
+		return input.recorded(ctx, record)
+	})
+	if err != nil {
+		s.discardSelfie(ctx, record)
 		return nil, err
 	}
+	metrics.VerificationRecorded(string(record.Status), string(record.Method))
 
 	return &VerifyOutput{
+		CertificateID: record.ID,
 		ParticipantID: participant.ID,
 		Status:        status,
 		Distance:      recognizeResp.Distance,
@@ -177,6 +580,77 @@ func (s *VerificationService) Verify(ctx context.Context, input VerifyInput) (*V
 	}, nil
 }
 
+// dryRunOutput answers the outcome a dry run would have had.
+func dryRunOutput(record *domain.LifeCertificate, reason string) *VerifyOutput {
+	metrics.VerificationDryRun(string(record.Status))
+	return &VerifyOutput{
+		ParticipantID: record.ParticipantID,
+		Status:        record.Status,
+		Distance:      record.Distance,
+		Similarity:    record.Similarity,
+		VerifiedAt:    record.VerifiedAt,
+		DryRun:        true,
+		RiskScore:     record.RiskScore,
+		Reason:        reason,
+	}
+}
+
+// validateVerifyInput checks a submission before anything is looked up.
+func validateVerifyInput(input VerifyInput) error {
+	if strings.TrimSpace(input.ParticipantID) == "" {
+		return fmt.Errorf("participant_id is required")
+	}
+	if len(input.ImageBytes) == 0 {
+		return fmt.Errorf("image payload is required")
+	}
+	verr := &ValidationError{}
+	if len(input.DeviceID) > 100 {
+		verr.add("device_id", "must be at most 100 characters")
+	}
+	if len(input.DeviceModel) > 100 {
+		verr.add("device_model", "must be at most 100 characters")
+	}
+	if len(input.DeviceOS) > 50 {
+		verr.add("device_os", "must be at most 50 characters")
+	}
+	switch {
+	case (input.Latitude == nil) != (input.Longitude == nil):
+		verr.add("latitude", "must be set together with longitude")
+	case input.Latitude != nil:
+		if *input.Latitude < -90 || *input.Latitude > 90 {
+			verr.add("latitude", "must be between -90 and 90")
+		}
+		if *input.Longitude < -180 || *input.Longitude > 180 {
+			verr.add("longitude", "must be between -180 and 180")
+		}
+	}
+	return verr.errOrNil()
+}
+
+// eligibleParticipant returns the participant when they may submit an automatic verification.
+func (s *VerificationService) eligibleParticipant(ctx context.Context, participantID string) (*domain.Participant, error) {
+	participant, err := s.participants.GetByID(ctx, strings.TrimSpace(participantID))
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return nil, ErrParticipantNotFound
+	}
+	switch participant.Status {
+	case domain.ParticipantStatusSuspended:
+		return nil, ErrParticipantSuspended
+	case domain.ParticipantStatusBlocked:
+		return nil, ErrParticipantBlocked
+	}
+	if err := s.locks.Check(ctx, participant.ID, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	if err := s.consents.Require(ctx, participant.NIK); err != nil {
+		return nil, err
+	}
+	return participant, nil
+}
+
 // LatestStatus returns the most recent verification record for the participant.
 func (s *VerificationService) LatestStatus(ctx context.Context, participantID string) (*StatusOutput, error) {
 	participantID = strings.TrimSpace(participantID)
@@ -192,21 +666,96 @@ func (s *VerificationService) LatestStatus(ctx context.Context, participantID st
 		return nil, ErrParticipantNotFound
 	}
 
-	record, err := s.certificates.GetLatestByParticipant(ctx, participantID)
+	state, err := s.states.GetByParticipant(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+	lock, err := s.locks.Status(ctx, participantID, time.Now().UTC())
 	if err != nil {
 		return nil, err
 	}
 
-	if record == nil {
-		return &StatusOutput{ParticipantID: participantID}, nil
+	if state == nil {
+		return &StatusOutput{ParticipantID: participantID, Lock: lock}, nil
 	}
 
 	return &StatusOutput{
 		ParticipantID: participantID,
-		Status:        record.Status,
-		Distance:      record.Distance,
-		Similarity:    record.Similarity,
-		VerifiedAt:    &record.VerifiedAt,
-		SelfiePath:    record.SelfiePath,
+		Status:        state.Status,
+		Distance:      state.Distance,
+		Similarity:    state.Similarity,
+		VerifiedAt:    &state.VerifiedAt,
+		SelfiePath:    state.SelfiePath,
+		Lock:          lock,
 	}, nil
 }
+
+// OpenSelfie returns the stored selfie of an automatic attempt.
+func (s *VerificationService) OpenSelfie(ctx context.Context, certificateID string) (io.ReadCloser, error) {
+	record, err := s.certificates.GetByID(ctx, strings.TrimSpace(certificateID))
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, ErrCertificateNotFound
+	}
+	if record.SelfiePath == "" || s.blobs == nil {
+		return nil, ErrSelfieNotFound
+	}
+	content, err := s.blobs.Get(ctx, record.SelfiePath)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrSelfieNotFound
+	}
+	return content, err
+}
+
+// storeSelfie keeps the processed selfie of an attempt when selfie storage is on.
+func (s *VerificationService) storeSelfie(ctx context.Context, record *domain.LifeCertificate, image []byte) error {
+	if s.blobs == nil {
+		return nil
+	}
+	key := fmt.Sprintf("certificates/%s/selfie.jpg", record.ID)
+	if err := s.blobs.Put(ctx, key, image); err != nil {
+		return fmt.Errorf("store selfie: %w", err)
+	}
+	record.SelfiePath = key
+	return nil
+}
+
+// discardSelfie removes the selfie of an attempt that was not saved.
+func (s *VerificationService) discardSelfie(ctx context.Context, record *domain.LifeCertificate) {
+	if s.blobs == nil || record.SelfiePath == "" {
+		return
+	}
+	if err := s.blobs.Delete(ctx, record.SelfiePath); err != nil {
+		log.Printf("discard selfie %s: %v", record.SelfiePath, err)
+	}
+}
+
+func parseLifeCertificateStatus(raw string) (domain.LifeCertificateStatus, error) {
+	switch status := domain.LifeCertificateStatus(strings.ToUpper(strings.TrimSpace(raw))); status {
+	case domain.LifeCertificateStatusValid, domain.LifeCertificateStatusInvalid, domain.LifeCertificateStatusReview:
+		return status, nil
+	default:
+		return "", fmt.Errorf("invalid status, use VALID, INVALID or REVIEW")
+	}
+}
+
+// verificationCompletedData describes a decided certificate for verification.completed events.
+func verificationCompletedData(record *domain.LifeCertificate) map[string]interface{} {
+	return map[string]interface{}{
+		"certificate_id": record.ID,
+		"participant_id": record.ParticipantID,
+		"status":         record.Status,
+		"method":         record.Method,
+		"similarity":     record.Similarity,
+		"distance":       record.Distance,
+		"verified_at":    record.VerifiedAt,
+		"location":       record.Location,
+		"kiosk_id":       record.KioskID,
+		"branch_id":      record.BranchID,
+		"risk_score":     record.RiskScore,
+		// Manual and proxy verifications away from their branch are recorded but flagged.
+		"outside_geofence": record.OutsideGeofence,
+	}
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/imaging"
+	"life-certificates/internal/liveness"
+	"life-certificates/internal/repository"
+)
+
+// verificationSessionImageField is the form field the upload policy names.
+const verificationSessionImageField = "image"
+
+var (
+	// ErrVerificationSessionRequired indicates a verification without a session token where one is required.
+	ErrVerificationSessionRequired = errors.New("verification session token is required")
+	// ErrInvalidVerificationSession indicates an unknown, expired or already used session token.
+	ErrInvalidVerificationSession = errors.New("invalid, expired or used verification session")
+	// ErrVerificationSessionMismatch indicates a session presented for another participant.
+	ErrVerificationSessionMismatch = errors.New("verification session was issued for another participant")
+)
+
+// VerificationSessionOptions configures sessions.
+type VerificationSessionOptions struct {
+	// Required rejects verifications without a session token.
+	Required      bool
+	TTL           time.Duration
+	MaxImageBytes int64
+}
+
+// VerificationSessionService issues single-use sessions to the mobile SDK and
+// checks the verification that presents one: it must come from the session's
+// participant before the session expires, and the liveness check sees the
+// session's challenge. A session records at most one certificate.
+type VerificationSessionService struct {
+	sessions     repository.VerificationSessionRepository
+	participants repository.ParticipantRepository
+	images       *imaging.Processor
+	options      VerificationSessionOptions
+}
+
+// NewVerificationSessionService wires dependencies for verification sessions.
+func NewVerificationSessionService(sessions repository.VerificationSessionRepository, participants repository.ParticipantRepository, images *imaging.Processor, options VerificationSessionOptions) *VerificationSessionService {
+	return &VerificationSessionService{sessions: sessions, participants: participants, images: images, options: options}
+}
+
+// CreateVerificationSessionInput opens a session for a participant.
+type CreateVerificationSessionInput struct {
+	ParticipantID string `json:"participant_id"`
+}
+
+// UploadPolicy tells the SDK what the verify call accepts.
+type UploadPolicy struct {
+	Field    string   `json:"field"`
+	MaxBytes int64    `json:"max_bytes"`
+	Formats  []string `json:"formats"`
+}
+
+// CreatedVerificationSession returns the session token once, at creation time.
+type CreatedVerificationSession struct {
+	SessionID     string             `json:"session_id"`
+	Token         string             `json:"token"`
+	ParticipantID string             `json:"participant_id"`
+	ExpiresAt     time.Time          `json:"expires_at"`
+	Challenge     liveness.Challenge `json:"challenge"`
+	UploadPolicy  UploadPolicy       `json:"upload_policy"`
+}
+
+// Create opens a session for the participant.
+func (s *VerificationSessionService) Create(ctx context.Context, actor string, input CreateVerificationSessionInput) (*CreatedVerificationSession, error) {
+	participantID := strings.TrimSpace(input.ParticipantID)
+	if participantID == "" {
+		return nil, &ValidationError{Fields: map[string]string{"participant_id": "is required"}}
+	}
+	participant, err := s.participants.GetByID(ctx, participantID)
+	if err != nil {
+		return nil, err
+	}
+	if participant == nil {
+		return nil, ErrParticipantNotFound
+	}
+	return s.open(ctx, actor, participant.ID, liveness.Actions, nil)
+}
+
+// StepUp uses up the session the first step presented, if any, and opens
+// the session of the step-up challenge, which keeps the first step. The
+// challenge action differs from the first step's.
+func (s *VerificationSessionService) StepUp(ctx context.Context, actor string, input VerifyInput, step *StepUp) (*CreatedVerificationSession, error) {
+	actions := liveness.Actions
+	if step.Challenge == StepUpChallengeSecondAngle {
+		actions = []string{liveness.ActionTurnLeft, liveness.ActionTurnRight}
+	}
+	if input.Challenge != nil {
+		others := make([]string, 0, len(actions))
+		for _, action := range actions {
+			if action != input.Challenge.Action {
+				others = append(others, action)
+			}
+		}
+		actions = others
+	}
+
+	var parentID *string
+	if input.SessionID != "" {
+		consumed, err := s.sessions.Close(ctx, input.SessionID, time.Now().UTC())
+		if err != nil {
+			return nil, err
+		}
+		if !consumed {
+			return nil, ErrInvalidVerificationSession
+		}
+		parentID = &input.SessionID
+	}
+	return s.open(ctx, actor, input.ParticipantID, actions, func(session *domain.VerificationSession) {
+		session.ParentID = parentID
+		session.StepUp = step.trace()
+	})
+}
+
+// open creates a session for the participant with a challenge picked from
+// actions; init, when set, completes the session before it is stored.
+func (s *VerificationSessionService) open(ctx context.Context, actor, participantID string, actions []string, init func(*domain.VerificationSession)) (*CreatedVerificationSession, error) {
+	token, err := randomToken(32)
+	if err != nil {
+		return nil, fmt.Errorf("generate session token: %w", err)
+	}
+	nonce, err := randomToken(16)
+	if err != nil {
+		return nil, fmt.Errorf("generate session nonce: %w", err)
+	}
+	pick, err := rand.Int(rand.Reader, big.NewInt(int64(len(actions))))
+	if err != nil {
+		return nil, fmt.Errorf("pick liveness challenge: %w", err)
+	}
+
+	now := time.Now().UTC()
+	session := &domain.VerificationSession{
+		ID:              uuid.NewString(),
+		ParticipantID:   participantID,
+		TokenHash:       hashSessionToken(token),
+		Nonce:           nonce,
+		ChallengeAction: actions[pick.Int64()],
+		MaxImageBytes:   s.options.MaxImageBytes,
+		ExpiresAt:       now.Add(s.options.TTL),
+		CreatedBy:       actor,
+		CreatedAt:       now,
+	}
+	if init != nil {
+		init(session)
+	}
+	if err := s.sessions.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	formats := make([]string, 0, 4)
+	for _, format := range s.images.Formats() {
+		formats = append(formats, string(format))
+	}
+	return &CreatedVerificationSession{
+		SessionID:     session.ID,
+		Token:         token,
+		ParticipantID: session.ParticipantID,
+		ExpiresAt:     session.ExpiresAt,
+		Challenge:     liveness.Challenge{Action: session.ChallengeAction, Nonce: session.Nonce},
+		UploadPolicy: UploadPolicy{
+			Field:    verificationSessionImageField,
+			MaxBytes: session.MaxImageBytes,
+			Formats:  formats,
+		},
+	}, nil
+}
+
+// Attach checks the session token presented with a verification and binds
+// the attempt to it: the participant defaults to the session's, the liveness
+// check gets the challenge, and the session is used up in the transaction
+// that records the certificate. Without a token the input is returned as it
+// is, unless sessions are required.
+func (s *VerificationSessionService) Attach(ctx context.Context, token string, input VerifyInput) (VerifyInput, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		if s.options.Required {
+			return input, ErrVerificationSessionRequired
+		}
+		return input, nil
+	}
+	session, err := s.sessions.GetByTokenHash(ctx, hashSessionToken(token))
+	if err != nil {
+		return input, err
+	}
+	if session == nil || session.ConsumedAt != nil || !time.Now().Before(session.ExpiresAt) {
+		return input, ErrInvalidVerificationSession
+	}
+	if participantID := strings.TrimSpace(input.ParticipantID); participantID != "" && participantID != session.ParticipantID {
+		return input, ErrVerificationSessionMismatch
+	}
+	if session.MaxImageBytes > 0 && int64(len(input.ImageBytes)) > session.MaxImageBytes {
+		return input, &ValidationError{Fields: map[string]string{"image": fmt.Sprintf("must be at most %d bytes", session.MaxImageBytes)}}
+	}
+	return s.bind(session, input)
+}
+
+// Resume binds a queued attempt to the session it presented when it was
+// submitted. The session may have expired while the attempt waited, but must
+// still be unused.
+func (s *VerificationSessionService) Resume(ctx context.Context, sessionID string, input VerifyInput) (VerifyInput, error) {
+	session, err := s.sessions.GetByID(ctx, sessionID)
+	if err != nil {
+		return input, err
+	}
+	if session == nil || session.ConsumedAt != nil {
+		return input, ErrInvalidVerificationSession
+	}
+	return s.bind(session, input)
+}
+
+// bind gives the attempt the session's participant and challenge, and uses
+// the session up when the attempt records its certificate.
+func (s *VerificationSessionService) bind(session *domain.VerificationSession, input VerifyInput) (VerifyInput, error) {
+	input.ParticipantID = session.ParticipantID
+	input.Challenge = &liveness.Challenge{Action: session.ChallengeAction, Nonce: session.Nonce}
+	input.SessionID = session.ID
+	if session.StepUp != nil {
+		var step StepUp
+		if err := json.Unmarshal([]byte(*session.StepUp), &step); err != nil {
+			return input, fmt.Errorf("decode step-up session: %w", err)
+		}
+		input.StepUpOf = &step
+	}
+	next := input.OnRecorded
+	input.OnRecorded = func(ctx context.Context, record *domain.LifeCertificate) error {
+		consumed, err := s.sessions.Consume(ctx, session.ID, record.ID, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		if !consumed {
+			return ErrInvalidVerificationSession
+		}
+		if next != nil {
+			return next(ctx, record)
+		}
+		return nil
+	}
+	return input, nil
+}
+
+func randomToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"life-certificates/internal/repository"
+	"life-certificates/internal/tenant"
+)
+
+const (
+	auditEntityConfig             = "config"
+	auditActionVerificationConfig = "config.verification_update"
+	verificationConfigEntityID    = "verification"
+)
+
+// VerificationSettings are the verification parameters that can change without a restart.
+type VerificationSettings struct {
+	DistanceThreshold   float64 `json:"distance_threshold"`
+	SimilarityThreshold float64 `json:"similarity_threshold"`
+	LivenessEnabled     bool    `json:"liveness_enabled"`
+}
+
+// UpdateVerificationSettingsInput changes the settings; nil fields are left untouched.
+type UpdateVerificationSettingsInput struct {
+	DistanceThreshold   *float64 `json:"distance_threshold"`
+	SimilarityThreshold *float64 `json:"similarity_threshold"`
+	LivenessEnabled     *bool    `json:"liveness_enabled"`
+}
+
+// VerificationSettingsService holds the live verification settings. Readers
+// take a consistent snapshot, so an attempt never mixes old and new values.
+type VerificationSettingsService struct {
+	current atomic.Pointer[VerificationSettings]
+	audit   repository.AuditLogRepository
+	tenants repository.TenantRepository
+	// mu serialises updates so the audited before/after values are accurate.
+	mu sync.Mutex
+}
+
+// NewVerificationSettingsService starts from the configured settings.
+func NewVerificationSettingsService(initial VerificationSettings, audit repository.AuditLogRepository, tenants repository.TenantRepository) *VerificationSettingsService {
+	s := &VerificationSettingsService{audit: audit, tenants: tenants}
+	s.current.Store(&initial)
+	return s
+}
+
+// Current returns a snapshot of the settings in force.
+func (s *VerificationSettingsService) Current() VerificationSettings {
+	return *s.current.Load()
+}
+
+// For returns the settings in force for the tenant of ctx, whose own
+// thresholds take precedence over the shared ones.
+func (s *VerificationSettingsService) For(ctx context.Context) (VerificationSettings, error) {
+	settings := s.Current()
+	if s.tenants == nil {
+		return settings, nil
+	}
+	t, err := s.tenants.Get(ctx, tenant.ID(ctx))
+	if err != nil {
+		return VerificationSettings{}, err
+	}
+	return tenantThresholds(settings, t), nil
+}
+
+// Update applies the changed fields, audit-logging the before and after values.
+func (s *VerificationSettingsService) Update(ctx context.Context, actor string, input UpdateVerificationSettingsInput) (*VerificationSettings, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := s.Current()
+	if input.DistanceThreshold != nil {
+		next.DistanceThreshold = *input.DistanceThreshold
+	}
+	if input.SimilarityThreshold != nil {
+		next.SimilarityThreshold = *input.SimilarityThreshold
+	}
+	if input.LivenessEnabled != nil {
+		next.LivenessEnabled = *input.LivenessEnabled
+	}
+	return s.apply(ctx, actor, next)
+}
+
+// Replace swaps in a complete set of settings, e.g. after the configuration is reloaded.
+func (s *VerificationSettingsService) Replace(ctx context.Context, actor string, next VerificationSettings) (*VerificationSettings, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.apply(ctx, actor, next)
+}
+
+func (s *VerificationSettingsService) apply(ctx context.Context, actor string, next VerificationSettings) (*VerificationSettings, error) {
+	verr := &ValidationError{}
+	if next.DistanceThreshold <= 0 {
+		verr.add("distance_threshold", "must be positive")
+	}
+	if next.SimilarityThreshold < 0 || next.SimilarityThreshold > 100 {
+		verr.add("similarity_threshold", "must be between 0 and 100")
+	}
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	previous := s.Current()
+	if previous == next {
+		return &next, nil
+	}
+	if err := recordAudit(ctx, s.audit, actor, auditActionVerificationConfig, auditEntityConfig, verificationConfigEntityID, map[string]interface{}{
+		"before": previous,
+		"after":  next,
+	}); err != nil {
+		return nil, err
+	}
+	s.current.Store(&next)
+	return &next, nil
+}
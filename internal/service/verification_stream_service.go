@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/events"
+	"life-certificates/internal/tenant"
+)
+
+// VerificationStreamService feeds live verification outcomes to monitoring screens.
+type VerificationStreamService struct {
+	hub *events.Hub
+}
+
+// NewVerificationStreamService wires the stream to the in-process event hub.
+func NewVerificationStreamService(hub *events.Hub) *VerificationStreamService {
+	return &VerificationStreamService{hub: hub}
+}
+
+// StreamFilter narrows the stream to a status and/or location.
+type StreamFilter struct {
+	Status   string
+	Location string
+}
+
+// Subscribe returns the verification events of the tenant of ctx matching
+// the filter until ctx is cancelled.
+func (s *VerificationStreamService) Subscribe(ctx context.Context, filter StreamFilter) (<-chan events.Event, error) {
+	var status domain.LifeCertificateStatus
+	if raw := strings.TrimSpace(filter.Status); raw != "" {
+		parsed, err := parseLifeCertificateStatus(raw)
+		if err != nil {
+			return nil, err
+		}
+		status = parsed
+	}
+	location := strings.TrimSpace(filter.Location)
+	tenantID := tenant.ID(ctx)
+
+	return s.hub.Subscribe(ctx, func(event events.Event) bool {
+		if event.Tenant != tenantID {
+			return false
+		}
+		if event.Type != events.TypeVerificationCompleted && event.Type != events.TypeVerificationReviewRequired {
+			return false
+		}
+		if status != "" && event.Data["status"] != string(status) {
+			return false
+		}
+		if location != "" && !strings.EqualFold(eventLocation(event), location) {
+			return false
+		}
+		return true
+	}), nil
+}
+
+// eventLocation reads the location from an event decoded from the outbox.
+func eventLocation(event events.Event) string {
+	location, _ := event.Data["location"].(string)
+	return location
+}
@@ -0,0 +1,444 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"life-certificates/internal/domain"
+	"life-certificates/internal/events"
+	"life-certificates/internal/repository"
+	"life-certificates/internal/tenant"
+)
+
+const (
+	webhookPollInterval       = 5 * time.Second
+	webhookBatchSize          = 50
+	webhookBaseBackoff        = 30 * time.Second
+	webhookMaxBackoff         = time.Hour
+	webhookResponseBodyLimit  = 1024
+	webhookMinimumSecretBytes = 16
+)
+
+// Headers sent with every delivery.
+const (
+	webhookSignatureHeader = "X-Webhook-Signature"
+	webhookEventHeader     = "X-Webhook-Event"
+	webhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// Audit vocabulary for webhook management.
+const (
+	auditEntityWebhook       = "webhook_subscription"
+	auditActionWebhookCreate = "webhook.create"
+	auditActionWebhookUpdate = "webhook.update"
+	auditActionWebhookDelete = "webhook.delete"
+)
+
+var (
+	// ErrWebhookNotFound indicates the requested subscription does not exist.
+	ErrWebhookNotFound = errors.New("webhook subscription not found")
+)
+
+// WebhookService manages subscriptions and delivers signed events with retries.
+type WebhookService struct {
+	webhooks    repository.WebhookRepository
+	audit       repository.AuditLogRepository
+	client      *http.Client
+	maxAttempts int
+}
+
+// NewWebhookService wires dependencies for webhook management and delivery.
+func NewWebhookService(webhooks repository.WebhookRepository, audit repository.AuditLogRepository, timeout time.Duration, maxAttempts int) *WebhookService {
+	return &WebhookService{
+		webhooks:    webhooks,
+		audit:       audit,
+		client:      &http.Client{Timeout: timeout},
+		maxAttempts: maxAttempts,
+	}
+}
+
+// CreateWebhookInput registers a subscription. An empty secret is generated.
+type CreateWebhookInput struct {
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"event_types"`
+}
+
+// UpdateWebhookInput changes a subscription; nil fields are left untouched.
+type UpdateWebhookInput struct {
+	URL        *string   `json:"url"`
+	EventTypes *[]string `json:"event_types"`
+	Active     *bool     `json:"active"`
+}
+
+// CreatedWebhook returns the signing secret once, at creation time.
+type CreatedWebhook struct {
+	domain.WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+// WebhookDeliveriesOutput is a page of the delivery log.
+type WebhookDeliveriesOutput struct {
+	Items    []domain.WebhookDelivery `json:"items"`
+	Page     int                      `json:"page"`
+	PageSize int                      `json:"page_size"`
+	Total    int64                    `json:"total"`
+}
+
+// Create registers a new subscription.
+func (s *WebhookService) Create(ctx context.Context, actor string, input CreateWebhookInput) (*CreatedWebhook, error) {
+	verr := &ValidationError{}
+	target := strings.TrimSpace(input.URL)
+	if problem := checkWebhookURL(target); problem != "" {
+		verr.add("url", problem)
+	}
+	eventTypes, problem := normalizeEventTypes(input.EventTypes)
+	if problem != "" {
+		verr.add("event_types", problem)
+	}
+	secret := strings.TrimSpace(input.Secret)
+	if secret != "" && len(secret) < webhookMinimumSecretBytes {
+		verr.add("secret", fmt.Sprintf("must be at least %d characters", webhookMinimumSecretBytes))
+	}
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	if secret == "" {
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("generate webhook secret: %w", err)
+		}
+		secret = hex.EncodeToString(buf)
+	}
+
+	now := time.Now().UTC()
+	sub := &domain.WebhookSubscription{
+		ID:         uuid.NewString(),
+		URL:        target,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		Active:     true,
+		CreatedBy:  actor,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.webhooks.CreateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	if err := recordAudit(ctx, s.audit, actor, auditActionWebhookCreate, auditEntityWebhook, sub.ID, map[string]interface{}{
+		"url":         sub.URL,
+		"event_types": sub.EventTypes,
+	}); err != nil {
+		return nil, err
+	}
+	return &CreatedWebhook{WebhookSubscription: *sub, Secret: secret}, nil
+}
+
+// Update changes the URL, event types or active flag of a subscription.
+func (s *WebhookService) Update(ctx context.Context, actor, id string, input UpdateWebhookInput) (*domain.WebhookSubscription, error) {
+	sub, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	verr := &ValidationError{}
+	if input.URL != nil {
+		target := strings.TrimSpace(*input.URL)
+		if problem := checkWebhookURL(target); problem != "" {
+			verr.add("url", problem)
+		}
+		sub.URL = target
+	}
+	if input.EventTypes != nil {
+		eventTypes, problem := normalizeEventTypes(*input.EventTypes)
+		if problem != "" {
+			verr.add("event_types", problem)
+		}
+		sub.EventTypes = eventTypes
+	}
+	if input.Active != nil {
+		sub.Active = *input.Active
+	}
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	sub.UpdatedAt = time.Now().UTC()
+	if err := s.webhooks.UpdateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	if err := recordAudit(ctx, s.audit, actor, auditActionWebhookUpdate, auditEntityWebhook, sub.ID, map[string]interface{}{
+		"url":         sub.URL,
+		"event_types": sub.EventTypes,
+		"active":      sub.Active,
+	}); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// Get returns a subscription by ID.
+func (s *WebhookService) Get(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	sub, err := s.webhooks.GetSubscription(ctx, strings.TrimSpace(id))
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, ErrWebhookNotFound
+	}
+	return sub, nil
+}
+
+// List returns every subscription.
+func (s *WebhookService) List(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return s.webhooks.ListSubscriptions(ctx)
+}
+
+// Delete removes a subscription together with its delivery log.
+func (s *WebhookService) Delete(ctx context.Context, actor, id string) error {
+	sub, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.webhooks.DeleteSubscription(ctx, sub.ID); err != nil {
+		return err
+	}
+	return recordAudit(ctx, s.audit, actor, auditActionWebhookDelete, auditEntityWebhook, sub.ID, map[string]interface{}{
+		"url": sub.URL,
+	})
+}
+
+// Deliveries returns the delivery log of a subscription filtered by status.
+func (s *WebhookService) Deliveries(ctx context.Context, id, status string, pageNum, pageSize int) (*WebhookDeliveriesOutput, error) {
+	sub, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := domain.WebhookDeliveryStatus(strings.ToUpper(strings.TrimSpace(status)))
+	switch filter {
+	case "", domain.WebhookDeliveryPending, domain.WebhookDeliverySucceeded, domain.WebhookDeliveryFailed:
+	default:
+		return nil, fmt.Errorf("status must be one of PENDING, SUCCEEDED, FAILED")
+	}
+
+	page := normalizePagination(pageNum, pageSize)
+	items, total, err := s.webhooks.ListDeliveries(ctx, sub.ID, filter, page)
+	if err != nil {
+		return nil, err
+	}
+	return &WebhookDeliveriesOutput{Items: items, Page: page.Page, PageSize: page.PageSize, Total: total}, nil
+}
+
+// Publish queues a delivery of the event for every active subscription interested in it.
+// An event that was already fanned out is skipped, so redelivery from the outbox is safe.
+func (s *WebhookService) Publish(ctx context.Context, event events.Event) error {
+	queued, err := s.webhooks.HasDeliveriesForEvent(ctx, event.ID)
+	if err != nil {
+		return err
+	}
+	if queued {
+		return nil
+	}
+
+	subs, err := s.webhooks.ListActiveSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode event: %w", err)
+	}
+
+	now := time.Now().UTC()
+	var deliveries []domain.WebhookDelivery
+	for _, sub := range subs {
+		if !subscribedTo(sub, event.Type) {
+			continue
+		}
+		deliveries = append(deliveries, domain.WebhookDelivery{
+			ID:             uuid.NewString(),
+			SubscriptionID: sub.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        string(payload),
+			Status:         domain.WebhookDeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		})
+	}
+	return s.webhooks.CreateDeliveries(ctx, deliveries)
+}
+
+// Start runs the delivery dispatcher until ctx is cancelled.
+func (s *WebhookService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(webhookPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.dispatchDue(ctx)
+			}
+		}
+	}()
+}
+
+func (s *WebhookService) dispatchDue(ctx context.Context) {
+	now := time.Now().UTC()
+	due, err := s.webhooks.ListDueDeliveries(tenant.System(ctx), now, webhookBatchSize)
+	if err != nil {
+		log.Printf("[webhook] list due deliveries: %v", err)
+		return
+	}
+
+	for i := range due {
+		delivery := &due[i]
+		ctx := tenant.WithID(ctx, delivery.TenantID)
+		// Lease the delivery for longer than one attempt can take so another instance does not pick it up.
+		ok, err := s.webhooks.ClaimDelivery(ctx, delivery.ID, delivery.NextAttemptAt, now.Add(s.client.Timeout+time.Minute))
+		if err != nil {
+			log.Printf("[webhook] claim delivery %s: %v", delivery.ID, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		s.attempt(ctx, delivery)
+	}
+}
+
+func (s *WebhookService) attempt(ctx context.Context, delivery *domain.WebhookDelivery) {
+	sub, err := s.webhooks.GetSubscription(ctx, delivery.SubscriptionID)
+	if err != nil {
+		log.Printf("[webhook] load subscription %s: %v", delivery.SubscriptionID, err)
+		return
+	}
+
+	delivery.Attempts++
+	var code int
+	if sub == nil || !sub.Active {
+		err = fmt.Errorf("subscription is inactive")
+	} else {
+		code, err = s.send(ctx, sub, delivery)
+	}
+
+	now := time.Now().UTC()
+	if code != 0 {
+		delivery.ResponseCode = &code
+	}
+	switch {
+	case err == nil:
+		delivery.Status = domain.WebhookDeliverySucceeded
+		delivery.DeliveredAt = &now
+		delivery.LastError = nil
+	case delivery.Attempts >= s.maxAttempts || sub == nil || !sub.Active:
+		msg := err.Error()
+		delivery.Status = domain.WebhookDeliveryFailed
+		delivery.LastError = &msg
+	default:
+		msg := err.Error()
+		delivery.LastError = &msg
+		delivery.NextAttemptAt = now.Add(exponentialBackoff(webhookBaseBackoff, webhookMaxBackoff, delivery.Attempts))
+	}
+
+	if err := s.webhooks.UpdateDelivery(ctx, delivery); err != nil {
+		log.Printf("[webhook] update delivery %s: %v", delivery.ID, err)
+	}
+}
+
+func (s *WebhookService) send(ctx context.Context, sub *domain.WebhookSubscription, delivery *domain.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventHeader, delivery.EventType)
+	req.Header.Set(webhookDeliveryHeader, delivery.ID)
+	req.Header.Set(webhookSignatureHeader, signWebhookPayload(sub.Secret, time.Now().UTC(), body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseBodyLimit))
+		return resp.StatusCode, fmt.Errorf("endpoint returned %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
+
+// signWebhookPayload produces "t=<unix>,v1=<hex HMAC-SHA256 of "<unix>.<body>">".
+func signWebhookPayload(secret string, at time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func subscribedTo(sub domain.WebhookSubscription, eventType string) bool {
+	for _, t := range strings.Split(sub.EventTypes, ",") {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func checkWebhookURL(raw string) string {
+	if raw == "" {
+		return "is required"
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return "must be an absolute http or https URL"
+	}
+	return ""
+}
+
+func normalizeEventTypes(raw []string) (string, string) {
+	if len(raw) == 0 {
+		return "", "at least one event type is required"
+	}
+	seen := make(map[string]bool, len(raw))
+	var types []string
+	for _, t := range raw {
+		t = strings.TrimSpace(t)
+		if !events.IsKnownType(t) {
+			return "", fmt.Sprintf("unknown event type %q, use one of %s", t, strings.Join(events.Types, ", "))
+		}
+		if !seen[t] {
+			seen[t] = true
+			types = append(types, t)
+		}
+	}
+	return strings.Join(types, ","), ""
+}
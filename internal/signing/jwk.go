@@ -0,0 +1,134 @@
+package signing
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+)
+
+// JWKSet is a JSON Web Key Set (RFC 7517).
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// Key returns the key with the kid, or nil.
+func (s JWKSet) Key(id string) *JWK {
+	for i := range s.Keys {
+		if s.Keys[i].KeyID == id {
+			return &s.Keys[i]
+		}
+	}
+	return nil
+}
+
+// JWK is a public RSA or EC signing key.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+	KeyID     string `json:"kid,omitempty"`
+	// RSA modulus and exponent.
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// EC curve and point.
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+	Y     string `json:"y,omitempty"`
+	// Chain holds the key's certificate chain, leaf first, as standard base64 DER.
+	Chain []string `json:"x5c,omitempty"`
+}
+
+func newJWK(public crypto.PublicKey, algorithm string, chain []*x509.Certificate) (*JWK, error) {
+	jwk := &JWK{Use: "sig", Algorithm: algorithm}
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = encode(key.N.Bytes())
+		jwk.E = encode(big.NewInt(int64(key.E)).Bytes())
+	case *ecdsa.PublicKey:
+		jwk.KeyType, jwk.Curve = "EC", "P-256"
+		x, y := make([]byte, 32), make([]byte, 32)
+		key.X.FillBytes(x)
+		key.Y.FillBytes(y)
+		jwk.X, jwk.Y = encode(x), encode(y)
+	default:
+		return nil, fmt.Errorf("unsupported signing key type %T", public)
+	}
+	for _, cert := range chain {
+		jwk.Chain = append(jwk.Chain, base64.StdEncoding.EncodeToString(cert.Raw))
+	}
+	jwk.KeyID = jwk.Thumbprint()
+	return jwk, nil
+}
+
+// Thumbprint returns the key's SHA-256 JWK thumbprint (RFC 7638).
+func (k *JWK) Thumbprint() string {
+	var members string
+	switch k.KeyType {
+	case "RSA":
+		members = fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, k.E, k.N)
+	default:
+		members = fmt.Sprintf(`{"crv":%q,"kty":%q,"x":%q,"y":%q}`, k.Curve, k.KeyType, k.X, k.Y)
+	}
+	sum := sha256.Sum256([]byte(members))
+	return encode(sum[:])
+}
+
+// PublicKey decodes the key.
+func (k *JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("decode JWK modulus: %w", err)
+		}
+		e, err := decode(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("decode JWK exponent: %w", err)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		if k.Curve != "P-256" {
+			return nil, fmt.Errorf("unsupported JWK curve %q", k.Curve)
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("decode JWK x: %w", err)
+		}
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("decode JWK y: %w", err)
+		}
+		key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return nil, fmt.Errorf("JWK point is not on P-256")
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported JWK key type %q", k.KeyType)
+	}
+}
+
+// Certificates decodes the key's certificate chain, leaf first, so verifiers
+// can check it against the roots they trust.
+func (k *JWK) Certificates() ([]*x509.Certificate, error) {
+	certs := make([]*x509.Certificate, 0, len(k.Chain))
+	for _, encoded := range k.Chain {
+		der, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("decode x5c: %w", err)
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("parse x5c: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
@@ -0,0 +1,116 @@
+package signing
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/kms/types"
+)
+
+// KMSOptions selects an asymmetric AWS KMS signing key.
+type KMSOptions struct {
+	// KeyID is the key ID, ARN or alias.
+	KeyID string
+	// Region overrides the region of the default AWS configuration.
+	Region string
+	// ChainFile is an optional PEM certificate chain for the key, leaf first,
+	// published with it.
+	ChainFile string
+}
+
+// kmsBackend signs with a key that never leaves KMS.
+type kmsBackend struct {
+	client    *kms.Client
+	keyID     string
+	algorithm types.SigningAlgorithmSpec
+}
+
+func (b kmsBackend) sign(ctx context.Context, digest []byte) ([]byte, error) {
+	out, err := b.client.Sign(ctx, &kms.SignInput{
+		KeyId:            aws.String(b.keyID),
+		Message:          digest,
+		MessageType:      types.MessageTypeDigest,
+		SigningAlgorithm: b.algorithm,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Signature, nil
+}
+
+// NewKMSKey loads the public half of an RSA or ECC_NIST_P256 KMS key.
+// Credentials come from the default AWS chain (environment, shared config,
+// instance or task role).
+func NewKMSKey(ctx context.Context, options KMSOptions) (*Key, error) {
+	var loaders []func(*awsconfig.LoadOptions) error
+	if options.Region != "" {
+		loaders = append(loaders, awsconfig.WithRegion(options.Region))
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, loaders...)
+	if err != nil {
+		return nil, fmt.Errorf("load AWS configuration: %w", err)
+	}
+	client := kms.NewFromConfig(cfg)
+
+	out, err := client.GetPublicKey(ctx, &kms.GetPublicKeyInput{KeyId: aws.String(options.KeyID)})
+	if err != nil {
+		return nil, fmt.Errorf("get KMS public key: %w", err)
+	}
+	if out.KeyUsage != types.KeyUsageTypeSignVerify {
+		return nil, errors.New("KMS key is not a SIGN_VERIFY key")
+	}
+	public, err := x509.ParsePKIXPublicKey(out.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("parse KMS public key: %w", err)
+	}
+	backend := kmsBackend{client: client, keyID: options.KeyID, algorithm: types.SigningAlgorithmSpecRsassaPkcs1V15Sha256}
+	if out.KeySpec == types.KeySpecEccNistP256 {
+		backend.algorithm = types.SigningAlgorithmSpecEcdsaSha256
+	}
+
+	var chain []*x509.Certificate
+	if options.ChainFile != "" {
+		if chain, err = readChain(options.ChainFile); err != nil {
+			return nil, err
+		}
+	}
+	key, err := newKey(backend, public, chain)
+	if err != nil {
+		return nil, fmt.Errorf("KMS key: %w", err)
+	}
+	return key, nil
+}
+
+func readChain(path string) ([]*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read certificate chain: %w", err)
+	}
+	var chain []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse certificate chain: %w", err)
+		}
+		chain = append(chain, cert)
+	}
+	if len(chain) == 0 {
+		return nil, errors.New("certificate chain holds no certificates")
+	}
+	return chain, nil
+}
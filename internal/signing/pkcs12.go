@@ -0,0 +1,43 @@
+package signing
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// localBackend signs with a private key held in memory.
+type localBackend struct {
+	signer crypto.Signer
+}
+
+func (b localBackend) sign(_ context.Context, digest []byte) ([]byte, error) {
+	return b.signer.Sign(rand.Reader, digest, crypto.SHA256)
+}
+
+// LoadPKCS12 reads the signing key and its certificate chain from a PKCS #12
+// (.p12/.pfx) file.
+func LoadPKCS12(path, password string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read PKCS #12 file: %w", err)
+	}
+	private, cert, caCerts, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		return nil, fmt.Errorf("decode PKCS #12 file: %w", err)
+	}
+	signer, ok := private.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported PKCS #12 key type %T", private)
+	}
+	key, err := newKey(localBackend{signer: signer}, signer.Public(), append([]*x509.Certificate{cert}, caCerts...))
+	if err != nil {
+		return nil, fmt.Errorf("PKCS #12 file: %w", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,276 @@
+// Package signing signs issued certificates with the organisation's key so
+// downstream institutions can check them offline, and verifies those
+// signatures.
+//
+// Signatures are JSON Web Signatures (RFC 7515): PDFs get a compact JWS with
+// detached content (Appendix F), JSON certificates a flattened JWS carrying
+// the payload. Keys are published as a JWK set whose x5c holds the
+// certificate chain, and are identified by their JWK thumbprint (RFC 7638).
+package signing
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Supported JWS algorithms.
+const (
+	// AlgorithmRS256 is RSASSA-PKCS1-v1_5 with SHA-256.
+	AlgorithmRS256 = "RS256"
+	// AlgorithmES256 is ECDSA on P-256 with SHA-256.
+	AlgorithmES256 = "ES256"
+)
+
+// ErrInvalidSignature indicates a signature that is malformed, made by an
+// unknown key or does not match the content.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// backend signs SHA-256 digests with a private key that may live outside the
+// process. RSA signatures are PKCS #1 v1.5 and ECDSA signatures ASN.1 DER, as
+// returned by crypto.Signer and KMS.
+type backend interface {
+	sign(ctx context.Context, digest []byte) ([]byte, error)
+}
+
+// Key signs with the organisation's key.
+type Key struct {
+	backend   backend
+	public    crypto.PublicKey
+	algorithm string
+	id        string
+	chain     []*x509.Certificate
+}
+
+func newKey(b backend, public crypto.PublicKey, chain []*x509.Certificate) (*Key, error) {
+	algorithm, err := algorithmFor(public)
+	if err != nil {
+		return nil, err
+	}
+	if len(chain) > 0 && !publicKeysEqual(chain[0].PublicKey, public) {
+		return nil, errors.New("the first certificate of the chain is not for the signing key")
+	}
+	jwk, err := newJWK(public, algorithm, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{backend: b, public: public, algorithm: algorithm, id: jwk.Thumbprint(), chain: chain}, nil
+}
+
+// ID returns the key's JWK thumbprint, the kid of its signatures.
+func (k *Key) ID() string {
+	return k.id
+}
+
+// Algorithm returns the JWS algorithm of the key's signatures.
+func (k *Key) Algorithm() string {
+	return k.algorithm
+}
+
+// header is the JWS protected header.
+type header struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Type      string `json:"typ,omitempty"`
+	// ContentType names what was signed, e.g. application/pdf.
+	ContentType string `json:"cty,omitempty"`
+}
+
+// SignDetached signs content and returns a compact JWS without the payload:
+// "<header>..<signature>".
+func (k *Key) SignDetached(ctx context.Context, contentType string, content []byte) (string, error) {
+	protected, signature, err := k.sign(ctx, header{Algorithm: k.algorithm, KeyID: k.id, ContentType: contentType}, content)
+	if err != nil {
+		return "", err
+	}
+	return protected + ".." + signature, nil
+}
+
+// JWS is a flattened JWS JSON serialization (RFC 7515 section 7.2.2).
+type JWS struct {
+	Protected string `json:"protected"`
+	Payload   string `json:"payload"`
+	Signature string `json:"signature"`
+}
+
+// SignJSON signs a JSON document.
+func (k *Key) SignJSON(ctx context.Context, payload []byte) (*JWS, error) {
+	protected, signature, err := k.sign(ctx, header{Algorithm: k.algorithm, KeyID: k.id, Type: "JOSE+JSON", ContentType: "json"}, payload)
+	if err != nil {
+		return nil, err
+	}
+	return &JWS{Protected: protected, Payload: encode(payload), Signature: signature}, nil
+}
+
+func (k *Key) sign(ctx context.Context, h header, payload []byte) (string, string, error) {
+	raw, err := json.Marshal(h)
+	if err != nil {
+		return "", "", err
+	}
+	protected := encode(raw)
+	digest := sha256.Sum256([]byte(protected + "." + encode(payload)))
+	signature, err := k.backend.sign(ctx, digest[:])
+	if err != nil {
+		return "", "", fmt.Errorf("sign certificate: %w", err)
+	}
+	if k.algorithm == AlgorithmES256 {
+		// JWS carries the raw R || S pair rather than DER.
+		if signature, err = ecdsaRaw(signature); err != nil {
+			return "", "", fmt.Errorf("sign certificate: %w", err)
+		}
+	}
+	return protected, encode(signature), nil
+}
+
+// KeySet returns the JWK set publishing the key and its chain.
+func (k *Key) KeySet() JWKSet {
+	// The key was converted when it was loaded.
+	jwk, _ := newJWK(k.public, k.algorithm, k.chain)
+	return JWKSet{Keys: []JWK{*jwk}}
+}
+
+// ChainPEM returns the certificate chain as PEM, leaf first; empty when the
+// key has no certificate.
+func (k *Key) ChainPEM() []byte {
+	var out []byte
+	for _, cert := range k.chain {
+		out = append(out, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+	}
+	return out
+}
+
+// SignatureKeyID returns the kid of a compact or flattened JWS's protected
+// header, or "" when it cannot be read.
+func SignatureKeyID(protected string) string {
+	protected, _, _ = strings.Cut(protected, ".")
+	raw, err := decode(protected)
+	if err != nil {
+		return ""
+	}
+	var h header
+	if json.Unmarshal(raw, &h) != nil {
+		return ""
+	}
+	return h.KeyID
+}
+
+// VerifyDetached checks a detached compact JWS from SignDetached against the
+// content with the keys of set.
+func VerifyDetached(set JWKSet, signature string, content []byte) (*JWK, error) {
+	parts := strings.Split(strings.TrimSpace(signature), ".")
+	if len(parts) != 3 || parts[1] != "" {
+		return nil, fmt.Errorf("%w: not a detached compact JWS", ErrInvalidSignature)
+	}
+	return verify(set, parts[0], encode(content), parts[2])
+}
+
+// VerifyJSON checks a flattened JWS from SignJSON with the keys of set and
+// returns its payload.
+func VerifyJSON(set JWKSet, jws JWS) ([]byte, *JWK, error) {
+	jwk, err := verify(set, jws.Protected, jws.Payload, jws.Signature)
+	if err != nil {
+		return nil, nil, err
+	}
+	payload, err := decode(jws.Payload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: payload is not base64url", ErrInvalidSignature)
+	}
+	return payload, jwk, nil
+}
+
+func verify(set JWKSet, protected, payload, signature string) (*JWK, error) {
+	raw, err := decode(protected)
+	if err != nil {
+		return nil, fmt.Errorf("%w: header is not base64url", ErrInvalidSignature)
+	}
+	var h header
+	if err := json.Unmarshal(raw, &h); err != nil {
+		return nil, fmt.Errorf("%w: header is not JSON", ErrInvalidSignature)
+	}
+	sig, err := decode(signature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature is not base64url", ErrInvalidSignature)
+	}
+	jwk := set.Key(h.KeyID)
+	if jwk == nil {
+		return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidSignature, h.KeyID)
+	}
+	if jwk.Algorithm != "" && jwk.Algorithm != h.Algorithm {
+		return nil, fmt.Errorf("%w: key %q does not sign with %s", ErrInvalidSignature, h.KeyID, h.Algorithm)
+	}
+	public, err := jwk.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256([]byte(protected + "." + payload))
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		if h.Algorithm != AlgorithmRS256 || rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig) != nil {
+			return nil, ErrInvalidSignature
+		}
+	case *ecdsa.PublicKey:
+		if h.Algorithm != AlgorithmES256 || len(sig) != 64 {
+			return nil, ErrInvalidSignature
+		}
+		r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+		if !ecdsa.Verify(key, digest[:], r, s) {
+			return nil, ErrInvalidSignature
+		}
+	}
+	return jwk, nil
+}
+
+func algorithmFor(public crypto.PublicKey) (string, error) {
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		if key.N.BitLen() < 2048 {
+			return "", errors.New("RSA signing keys must be at least 2048 bits")
+		}
+		return AlgorithmRS256, nil
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return "", errors.New("ECDSA signing keys must be on P-256")
+		}
+		return AlgorithmES256, nil
+	default:
+		return "", fmt.Errorf("unsupported signing key type %T, use RSA or ECDSA P-256", public)
+	}
+}
+
+func publicKeysEqual(a, b crypto.PublicKey) bool {
+	key, ok := a.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && key.Equal(b)
+}
+
+// ecdsaRaw converts an ASN.1 DER ECDSA P-256 signature to R || S.
+func ecdsaRaw(der []byte) ([]byte, error) {
+	var sig struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, fmt.Errorf("decode ECDSA signature: %w", err)
+	}
+	raw := make([]byte, 64)
+	sig.R.FillBytes(raw[:32])
+	sig.S.FillBytes(raw[32:])
+	return raw, nil
+}
+
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decode(data string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(data)
+}
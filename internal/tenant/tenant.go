@@ -0,0 +1,42 @@
+// Package tenant carries the pension fund a request, job or task acts for.
+// Repositories scope every statement to the tenant on its context; contexts
+// without one act for the default tenant, so single-fund deployments need
+// no tenant at all.
+package tenant
+
+import "context"
+
+// DefaultID is the tenant of contexts that name none; deployments created
+// before multi-tenancy keep their data under it.
+const DefaultID = "default"
+
+type idContextKey struct{}
+
+type systemContextKey struct{}
+
+// WithID returns a context acting for the tenant id, ending any system scope.
+func WithID(ctx context.Context, id string) context.Context {
+	ctx = context.WithValue(ctx, systemContextKey{}, false)
+	return context.WithValue(ctx, idContextKey{}, id)
+}
+
+// ID returns the tenant ctx acts for, DefaultID when it names none.
+func ID(ctx context.Context) string {
+	if id, _ := ctx.Value(idContextKey{}).(string); id != "" {
+		return id
+	}
+	return DefaultID
+}
+
+// System returns a context reading across tenants, for workers that poll the
+// due rows of every tenant and then handle each with WithID. Rows created
+// with it must name their tenant.
+func System(ctx context.Context) context.Context {
+	return context.WithValue(ctx, systemContextKey{}, true)
+}
+
+// IsSystem reports whether ctx reads across tenants.
+func IsSystem(ctx context.Context) bool {
+	system, _ := ctx.Value(systemContextKey{}).(bool)
+	return system
+}